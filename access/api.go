@@ -15,6 +15,7 @@ type API interface {
 	Ping(ctx context.Context) error
 	GetNetworkParameters(ctx context.Context) accessmodel.NetworkParameters
 	GetNodeVersionInfo(ctx context.Context) (*accessmodel.NodeVersionInfo, error)
+	GetEpochForecast(ctx context.Context) (*accessmodel.EpochForecast, error)
 
 	GetLatestBlockHeader(ctx context.Context, isSealed bool) (*flow.Header, flow.BlockStatus, error)
 	GetBlockHeaderByHeight(ctx context.Context, height uint64) (*flow.Header, flow.BlockStatus, error)
@@ -58,6 +59,8 @@ type API interface {
 	GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error)
 	GetProtocolStateSnapshotByBlockID(ctx context.Context, blockID flow.Identifier) ([]byte, error)
 	GetProtocolStateSnapshotByHeight(ctx context.Context, blockHeight uint64) ([]byte, error)
+	GetProtocolStateAtHeight(ctx context.Context, height uint64) (*accessmodel.ProtocolStateAtHeight, error)
+	GetProtocolStateDiff(ctx context.Context, startHeight uint64, endHeight uint64) (*accessmodel.ProtocolStateDiff, error)
 
 	GetExecutionResultForBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionResult, error)
 	GetExecutionResultByID(ctx context.Context, id flow.Identifier) (*flow.ExecutionResult, error)
@@ -554,6 +554,36 @@ func (_m *API) GetCollectionByID(ctx context.Context, id flow.Identifier) (*flow
 	return r0, r1
 }
 
+// GetEpochForecast provides a mock function with given fields: ctx
+func (_m *API) GetEpochForecast(ctx context.Context) (*modelaccess.EpochForecast, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEpochForecast")
+	}
+
+	var r0 *modelaccess.EpochForecast
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*modelaccess.EpochForecast, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *modelaccess.EpochForecast); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*modelaccess.EpochForecast)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetEventsForBlockIDs provides a mock function with given fields: ctx, eventType, blockIDs, requiredEventEncodingVersion
 func (_m *API) GetEventsForBlockIDs(ctx context.Context, eventType string, blockIDs []flow.Identifier, requiredEventEncodingVersion entities.EventEncodingVersion) ([]flow.BlockEvents, error) {
 	ret := _m.Called(ctx, eventType, blockIDs, requiredEventEncodingVersion)
@@ -856,6 +886,66 @@ func (_m *API) GetNodeVersionInfo(ctx context.Context) (*modelaccess.NodeVersion
 	return r0, r1
 }
 
+// GetProtocolStateAtHeight provides a mock function with given fields: ctx, height
+func (_m *API) GetProtocolStateAtHeight(ctx context.Context, height uint64) (*modelaccess.ProtocolStateAtHeight, error) {
+	ret := _m.Called(ctx, height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProtocolStateAtHeight")
+	}
+
+	var r0 *modelaccess.ProtocolStateAtHeight
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*modelaccess.ProtocolStateAtHeight, error)); ok {
+		return rf(ctx, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *modelaccess.ProtocolStateAtHeight); ok {
+		r0 = rf(ctx, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*modelaccess.ProtocolStateAtHeight)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProtocolStateDiff provides a mock function with given fields: ctx, startHeight, endHeight
+func (_m *API) GetProtocolStateDiff(ctx context.Context, startHeight uint64, endHeight uint64) (*modelaccess.ProtocolStateDiff, error) {
+	ret := _m.Called(ctx, startHeight, endHeight)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProtocolStateDiff")
+	}
+
+	var r0 *modelaccess.ProtocolStateDiff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (*modelaccess.ProtocolStateDiff, error)); ok {
+		return rf(ctx, startHeight, endHeight)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) *modelaccess.ProtocolStateDiff); ok {
+		r0 = rf(ctx, startHeight, endHeight)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*modelaccess.ProtocolStateDiff)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, startHeight, endHeight)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetProtocolStateSnapshotByBlockID provides a mock function with given fields: ctx, blockID
 func (_m *API) GetProtocolStateSnapshotByBlockID(ctx context.Context, blockID flow.Identifier) ([]byte, error) {
 	ret := _m.Called(ctx, blockID)
@@ -61,6 +61,14 @@ func WithMaxMsgSize(size int) CommandRunnerOption {
 	}
 }
 
+// WithHTTPHandler registers an additional handler on the HTTP server of the admin server at the given path,
+// e.g. a debug page. The handler is served alongside the admin commands and the pprof endpoints.
+func WithHTTPHandler(path string, handler http.Handler) CommandRunnerOption {
+	return func(r *CommandRunner) {
+		r.httpHandlers[path] = handler
+	}
+}
+
 type CommandRunnerBootstrapper struct {
 	handlers   map[string]CommandHandler
 	validators map[string]CommandValidator
@@ -101,6 +109,7 @@ func (r *CommandRunnerBootstrapper) Bootstrap(logger zerolog.Logger, bindAddress
 		grpcAddress:      fmt.Sprintf("%s/flow-node-admin.sock", os.TempDir()),
 		httpAddress:      bindAddress,
 		logger:           logger.With().Str("admin", "command_runner").Logger(),
+		httpHandlers:     make(map[string]http.Handler),
 		startupCompleted: make(chan struct{}),
 	}
 
@@ -136,6 +145,9 @@ type CommandRunner struct {
 	tlsConfig   *tls.Config
 	logger      zerolog.Logger
 
+	// httpHandlers are the additional handlers served by the HTTP server, keyed by path.
+	httpHandlers map[string]http.Handler
+
 	// wait for worker routines to be ready
 	workersStarted sync.WaitGroup
 
@@ -242,6 +254,10 @@ func (r *CommandRunner) runAdminServer(ctx irrecoverable.SignalerContext) error
 	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 
+	for path, handler := range r.httpHandlers {
+		mux.Handle(path, handler)
+	}
+
 	httpServer := &http.Server{
 		Addr:      r.httpAddress,
 		Handler:   mux,
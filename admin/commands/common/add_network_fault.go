@@ -0,0 +1,137 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/faults"
+)
+
+var _ commands.AdminCommand = (*AddNetworkFaultCommand)(nil)
+
+// AddNetworkFaultCommand adds a rule injecting faults into the messages exchanged by the node, restricted to
+// the given direction, channels, roles and nodes: partitions, random drops, duplicate deliveries and delays.
+// A rule with the ID of an existing rule replaces the existing rule.
+type AddNetworkFaultCommand struct {
+	injector *faults.Injector
+}
+
+func NewAddNetworkFaultCommand(injector *faults.Injector) *AddNetworkFaultCommand {
+	return &AddNetworkFaultCommand{
+		injector: injector,
+	}
+}
+
+func (a *AddNetworkFaultCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if a.injector == nil {
+		return nil, admin.NewInvalidAdminReqErrorf("network fault injection is not enabled on this node")
+	}
+	rule := req.ValidatorData.(faults.Rule)
+
+	err := a.injector.AddRule(rule)
+	if err != nil {
+		return nil, fmt.Errorf("could not add network fault rule: %w", err)
+	}
+	return "ok", nil
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (a *AddNetworkFaultCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	id, ok := input["id"].(string)
+	if !ok || strings.TrimSpace(id) == "" {
+		return admin.NewInvalidAdminReqParameterError("id", "must be a non-empty string", input["id"])
+	}
+	rule := faults.Rule{
+		ID:        strings.TrimSpace(id),
+		Direction: faults.DirectionBoth,
+		Delay:     faults.Delay{Distribution: faults.DistributionUniform},
+	}
+
+	var err error
+	if value, ok := input["direction"]; ok {
+		str, _ := value.(string)
+		rule.Direction, err = faults.ParseDirection(str)
+		if err != nil {
+			return admin.NewInvalidAdminReqParameterError("direction", err.Error(), value)
+		}
+	}
+	if values, ok := input["channels"]; ok {
+		rule.Channels, err = parseStringList("channels", values, "must be a list of channels", func(str string) (channels.Channel, error) {
+			channel := channels.Channel(str)
+			return channel, channels.IsValidFlowChannel(channel)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if values, ok := input["roles"]; ok {
+		rule.Roles, err = parseStringList("roles", values, "must be a list of roles", flow.ParseRole)
+		if err != nil {
+			return err
+		}
+	}
+	if values, ok := input["node_ids"]; ok {
+		rule.NodeIDs, err = parseStringList("node_ids", values, "must be a list of node IDs", flow.HexStringToIdentifier)
+		if err != nil {
+			return err
+		}
+	}
+	if value, ok := input["partition"]; ok {
+		rule.Partition, ok = value.(bool)
+		if !ok {
+			return admin.NewInvalidAdminReqParameterError("partition", "must be a bool", value)
+		}
+	}
+	for field, target := range map[string]*float64{
+		"drop_rate":      &rule.DropRate,
+		"duplicate_rate": &rule.DuplicateRate,
+	} {
+		if value, ok := input[field]; ok {
+			rate, ok := value.(float64)
+			if !ok || rate < 0 || rate > 1 {
+				return admin.NewInvalidAdminReqParameterError(field, "must be a number in [0, 1]", value)
+			}
+			*target = rate
+		}
+	}
+	for field, target := range map[string]*time.Duration{
+		"delay":  &rule.Delay.Base,
+		"jitter": &rule.Delay.Jitter,
+	} {
+		if value, ok := input[field]; ok {
+			str, ok := value.(string)
+			if !ok {
+				return admin.NewInvalidAdminReqParameterError(field, "must be a non-negative duration, e.g. \"100ms\"", value)
+			}
+			*target, err = time.ParseDuration(str)
+			if err != nil || *target < 0 {
+				return admin.NewInvalidAdminReqParameterError(field, "must be a non-negative duration, e.g. \"100ms\"", value)
+			}
+		}
+	}
+	if value, ok := input["distribution"]; ok {
+		str, _ := value.(string)
+		rule.Delay.Distribution, err = faults.ParseDistribution(str)
+		if err != nil {
+			return admin.NewInvalidAdminReqParameterError("distribution", err.Error(), value)
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return admin.NewInvalidAdminReqErrorf("invalid network fault rule: %v", err)
+	}
+	req.ValidatorData = rule
+	return nil
+}
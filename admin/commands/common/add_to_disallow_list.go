@@ -0,0 +1,126 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/disallowlist"
+	"github.com/onflow/flow-go/network/disallowlist/model"
+)
+
+var _ commands.AdminCommand = (*AddToDisallowListCommand)(nil)
+
+type addToDisallowListRequest struct {
+	nodeIDs flow.IdentifierList
+	peerIDs []peer.ID
+	reason  string
+	ttl     time.Duration
+}
+
+// AddToDisallowListCommand adds nodes, given by node ID, and peers, given by peer ID, to the operator-managed
+// disallow list with the given reason and an optional time-to-live. The nodes and peers are disconnected and
+// no new connections are established with them until they are removed from the list or the entries expire.
+type AddToDisallowListCommand struct {
+	disallowList *disallowlist.OperatorDisallowList
+}
+
+func NewAddToDisallowListCommand(disallowList *disallowlist.OperatorDisallowList) *AddToDisallowListCommand {
+	return &AddToDisallowListCommand{
+		disallowList: disallowList,
+	}
+}
+
+func (a *AddToDisallowListCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if a.disallowList == nil {
+		return nil, admin.NewInvalidAdminReqErrorf("operator disallow list is not supported by this node")
+	}
+	data := req.ValidatorData.(*addToDisallowListRequest)
+
+	now := time.Now()
+	var expiry time.Time
+	if data.ttl > 0 {
+		expiry = now.Add(data.ttl)
+	}
+	entries := make([]model.Entry, 0, len(data.nodeIDs)+len(data.peerIDs))
+	for _, nodeID := range data.nodeIDs {
+		entries = append(entries, model.Entry{NodeID: nodeID, Reason: data.reason, Added: now, Expiry: expiry})
+	}
+	for _, peerID := range data.peerIDs {
+		entries = append(entries, model.Entry{PeerID: peerID, Reason: data.reason, Added: now, Expiry: expiry})
+	}
+	err := a.disallowList.Add(entries...)
+	if errors.Is(err, disallowlist.ErrUnknownNode) {
+		return nil, admin.NewInvalidAdminReqErrorf("could not add entries to disallow list: %v", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not add entries to disallow list: %w", err)
+	}
+	return "ok", nil
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (a *AddToDisallowListCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+	nodeIDs, peerIDs, err := parseDisallowListTargets(input)
+	if err != nil {
+		return err
+	}
+	reason, ok := input["reason"].(string)
+	if !ok || strings.TrimSpace(reason) == "" {
+		return admin.NewInvalidAdminReqParameterError("reason", "must be a non-empty string", input["reason"])
+	}
+	data := &addToDisallowListRequest{
+		nodeIDs: nodeIDs,
+		peerIDs: peerIDs,
+		reason:  strings.TrimSpace(reason),
+	}
+	if value, ok := input["ttl"]; ok {
+		str, ok := value.(string)
+		if !ok {
+			return admin.NewInvalidAdminReqParameterError("ttl", "must be a positive duration, e.g. \"24h\"", value)
+		}
+		data.ttl, err = time.ParseDuration(str)
+		if err != nil || data.ttl <= 0 {
+			return admin.NewInvalidAdminReqParameterError("ttl", "must be a positive duration, e.g. \"24h\"", value)
+		}
+	}
+	req.ValidatorData = data
+	return nil
+}
+
+// parseDisallowListTargets parses the "node_ids" and "peer_ids" fields, at least one of which must be a
+// non-empty list.
+// Returns admin.InvalidAdminReqError if the fields are invalid.
+func parseDisallowListTargets(input map[string]interface{}) (flow.IdentifierList, []peer.ID, error) {
+	var nodeIDs flow.IdentifierList
+	var peerIDs []peer.ID
+	var err error
+	if values, ok := input["node_ids"]; ok {
+		nodeIDs, err = parseStringList("node_ids", values, "must be a list of node IDs", flow.HexStringToIdentifier)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if values, ok := input["peer_ids"]; ok {
+		peerIDs, err = parseStringList("peer_ids", values, "must be a list of peer IDs", peer.Decode)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(nodeIDs) == 0 && len(peerIDs) == 0 {
+		return nil, nil, admin.NewInvalidAdminReqErrorf("at least one of \"node_ids\" and \"peer_ids\" must be a non-empty list")
+	}
+	return nodeIDs, peerIDs, nil
+}
@@ -0,0 +1,108 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/model/flow"
+	mockalsp "github.com/onflow/flow-go/network/alsp/mock"
+	"github.com/onflow/flow-go/network/alsp/model"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestGetAlspPenalties tests that the penalties of all misbehaving nodes are returned without input,
+// and only those of the requested nodes otherwise.
+func TestGetAlspPenalties(t *testing.T) {
+	spamRecords := mockalsp.NewSpamRecordAdmin(t)
+	command := NewGetAlspPenaltiesCommand(spamRecords)
+	record := model.ProtocolSpamRecord{
+		OriginId:       unittest.IdentifierFixture(),
+		Decay:          100,
+		CutoffCounter:  1,
+		DisallowListed: true,
+		Penalty:        -50,
+	}
+	unknown := unittest.IdentifierFixture()
+
+	spamRecords.On("SpamRecords").Return([]model.ProtocolSpamRecord{record}).Once()
+	req := &admin.CommandRequest{}
+	require.NoError(t, command.Validator(req))
+	result, err := command.Handler(context.Background(), req)
+	require.NoError(t, err)
+	penalties := result.(map[string]interface{})["penalties"].([]interface{})
+	require.Len(t, penalties, 1)
+	assert.Equal(t, map[string]interface{}{
+		"node_id":         record.OriginId.String(),
+		"penalty":         float64(-50),
+		"decay":           float64(100),
+		"cutoff_counter":  float64(1),
+		"disallow_listed": true,
+	}, penalties[0])
+
+	spamRecords.On("SpamRecord", record.OriginId).Return(&record, true).Once()
+	spamRecords.On("SpamRecord", unknown).Return(nil, false).Once()
+	req = &admin.CommandRequest{Data: map[string]interface{}{
+		"node_ids": []interface{}{record.OriginId.String(), unknown.String()},
+	}}
+	require.NoError(t, command.Validator(req))
+	result, err = command.Handler(context.Background(), req)
+	require.NoError(t, err)
+	response := result.(map[string]interface{})
+	assert.Len(t, response["penalties"], 1)
+	assert.Equal(t, []interface{}{unknown.String()}, response["not_found"])
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, data := range []interface{}{
+			"all",
+			map[string]interface{}{"node_ids": "abc"},
+			map[string]interface{}{"node_ids": []interface{}{"abc"}},
+		} {
+			err := command.Validator(&admin.CommandRequest{Data: data})
+			assert.True(t, admin.IsInvalidAdminParameterError(err), "data: %v", data)
+		}
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		command := NewGetAlspPenaltiesCommand(nil)
+		_, err := command.Handler(context.Background(), &admin.CommandRequest{})
+		assert.Error(t, err)
+	})
+}
+
+// TestResetAlspPenalties tests that the penalties of the requested nodes are reset.
+func TestResetAlspPenalties(t *testing.T) {
+	spamRecords := mockalsp.NewSpamRecordAdmin(t)
+	command := NewResetAlspPenaltiesCommand(spamRecords)
+	penalized := unittest.IdentifierFixture()
+	unknown := unittest.IdentifierFixture()
+
+	spamRecords.On("ResetSpamRecord", penalized).Return(true).Once()
+	spamRecords.On("ResetSpamRecord", unknown).Return(false).Once()
+	req := &admin.CommandRequest{Data: map[string]interface{}{
+		"node_ids": []interface{}{penalized.String(), unknown.String()},
+	}}
+	require.NoError(t, command.Validator(req))
+	assert.Equal(t, flow.IdentifierList{penalized, unknown}, req.ValidatorData)
+	result, err := command.Handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"reset":     []interface{}{penalized.String()},
+		"not_found": []interface{}{unknown.String()},
+	}, result)
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, data := range []interface{}{
+			nil,
+			map[string]interface{}{},
+			map[string]interface{}{"node_ids": []interface{}{}},
+			map[string]interface{}{"node_ids": []interface{}{"abc"}},
+		} {
+			err := command.Validator(&admin.CommandRequest{Data: data})
+			assert.True(t, admin.IsInvalidAdminParameterError(err), "data: %v", data)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network"
+	mockalsp "github.com/onflow/flow-go/network/alsp/mock"
+	"github.com/onflow/flow-go/network/alsp/model"
+	"github.com/onflow/flow-go/network/disallowlist"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/cache"
+	mockp2p "github.com/onflow/flow-go/network/p2p/mock"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation/dbtest"
+	"github.com/onflow/flow-go/storage/store"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// disallowListConsumer is a disallowlist.DisallowListConsumer which ignores peer update requests.
+type disallowListConsumer struct {
+	*mockp2p.DisallowListNotificationConsumer
+}
+
+func (disallowListConsumer) RequestPeerUpdate() {}
+
+// TestDisallowListCommands tests adding nodes and peers to the operator disallow list, reporting the disallow
+// list merged with the node ID blocklist and the disallow-listings of the ALSP module, and removing nodes and peers
+// from the list.
+func TestDisallowListCommands(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		idTranslator := mockp2p.NewIDTranslator(t)
+		consumer := disallowListConsumer{mockp2p.NewDisallowListNotificationConsumer(t)}
+		disallowList, err := disallowlist.NewOperatorDisallowList(unittest.Logger(), store.NewDisallowListEntries(db), idTranslator, consumer)
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		disallowList.Start(irrecoverable.NewMockSignalerContext(t, ctx))
+		unittest.RequireCloseBefore(t, disallowList.Ready(), 100*time.Millisecond, "operator disallow list did not start")
+		defer func() {
+			cancel()
+			unittest.RequireCloseBefore(t, disallowList.Done(), 100*time.Millisecond, "operator disallow list did not stop")
+		}()
+		spamRecords := mockalsp.NewSpamRecordAdmin(t)
+
+		nodeID := unittest.IdentifierFixture()
+		nodePeerID := unittest.PeerIdFixture(t)
+		peerID := unittest.PeerIdFixture(t)
+		alspNodeID := unittest.IdentifierFixture()
+		idTranslator.On("GetPeerID", nodeID).Return(nodePeerID, nil)
+		consumer.On("OnDisallowListNotification", mock.Anything, network.DisallowListedCauseOperator).Return()
+		consumer.On("OnAllowListNotification", mock.Anything, network.DisallowListedCauseOperator).Return()
+
+		add := NewAddToDisallowListCommand(disallowList)
+		req := &admin.CommandRequest{Data: map[string]interface{}{
+			"node_ids": []interface{}{nodeID.String()},
+			"peer_ids": []interface{}{peerID.String()},
+			"reason":   "spamming",
+			"ttl":      "24h",
+		}}
+		require.NoError(t, add.Validator(req))
+		_, err = add.Handler(context.Background(), req)
+		require.NoError(t, err)
+		consumer.AssertCalled(t, "OnDisallowListNotification", nodePeerID, network.DisallowListedCauseOperator)
+		consumer.AssertCalled(t, "OnDisallowListNotification", peerID, network.DisallowListedCauseOperator)
+
+		// nodes which cannot be translated to peer IDs are rejected
+		unknownNodeID := unittest.IdentifierFixture()
+		idTranslator.On("GetPeerID", unknownNodeID).Return(peer.ID(""), p2p.ErrUnknownId)
+		req = &admin.CommandRequest{Data: map[string]interface{}{
+			"node_ids": []interface{}{unknownNodeID.String()},
+			"reason":   "spamming",
+		}}
+		require.NoError(t, add.Validator(req))
+		_, err = add.Handler(context.Background(), req)
+		require.True(t, admin.IsInvalidAdminParameterError(err), err)
+
+		blockedNodeID := unittest.IdentifierFixture()
+		var blocklist *cache.NodeDisallowListingWrapper
+		unittest.RunWithBadgerDB(t, func(bdb *badger.DB) {
+			blocklistConsumer := mocknetwork.NewDisallowListNotificationConsumer(t)
+			blocklistConsumer.On("OnDisallowListNotification", mock.Anything).Return()
+			blocklist, err = cache.NewNodeDisallowListWrapper(mockmodule.NewIdentityProvider(t), bdb, func() network.DisallowListNotificationConsumer {
+				return blocklistConsumer
+			})
+			require.NoError(t, err)
+			require.NoError(t, blocklist.Update(flow.IdentifierList{nodeID, blockedNodeID}))
+		})
+
+		spamRecords.On("SpamRecords").Return([]model.ProtocolSpamRecord{
+			{OriginId: nodeID, DisallowListed: true},
+			{OriginId: alspNodeID, DisallowListed: true},
+			{OriginId: unittest.IdentifierFixture(), Penalty: -1},
+		})
+		get := NewGetDisallowListCommand(disallowList, blocklist, spamRecords)
+		req = &admin.CommandRequest{}
+		require.NoError(t, get.Validator(req))
+		result, err := get.Handler(context.Background(), req)
+		require.NoError(t, err)
+		sources := make(map[string]interface{})
+		for _, e := range result.(map[string]interface{})["entries"].([]interface{}) {
+			entry := e.(map[string]interface{})
+			key, ok := entry["node_id"]
+			if !ok {
+				key = entry["peer_id"]
+			}
+			sources[key.(string)] = entry["sources"]
+			if key != alspNodeID.String() && key != blockedNodeID.String() {
+				assert.Equal(t, "spamming", entry["reason"])
+				assert.NotEmpty(t, entry["expiry"])
+			}
+		}
+		assert.Equal(t, map[string]interface{}{
+			nodeID.String():        []interface{}{disallowListSourceOperator, disallowListSourceBlocklist, disallowListSourceAlsp},
+			peerID.String():        []interface{}{disallowListSourceOperator},
+			blockedNodeID.String(): []interface{}{disallowListSourceBlocklist},
+			alspNodeID.String():    []interface{}{disallowListSourceAlsp},
+		}, sources)
+
+		remove := NewRemoveFromDisallowListCommand(disallowList)
+		unknown := unittest.IdentifierFixture()
+		req = &admin.CommandRequest{Data: map[string]interface{}{
+			"node_ids": []interface{}{nodeID.String(), unknown.String()},
+		}}
+		require.NoError(t, remove.Validator(req))
+		result, err = remove.Handler(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"removed":   []interface{}{nodeID.String()},
+			"not_found": []interface{}{unknown.String()},
+		}, result)
+		consumer.AssertCalled(t, "OnAllowListNotification", nodePeerID, network.DisallowListedCauseOperator)
+		require.Len(t, disallowList.Entries(), 1)
+
+		t.Run("invalid requests", func(t *testing.T) {
+			for _, data := range []interface{}{
+				nil,
+				map[string]interface{}{"reason": "spamming"},
+				map[string]interface{}{"node_ids": []interface{}{}, "reason": "spamming"},
+				map[string]interface{}{"node_ids": []interface{}{"abc"}, "reason": "spamming"},
+				map[string]interface{}{"peer_ids": []interface{}{"not-a-peer"}, "reason": "spamming"},
+				map[string]interface{}{"node_ids": []interface{}{nodeID.String()}},
+				map[string]interface{}{"node_ids": []interface{}{nodeID.String()}, "reason": " "},
+				map[string]interface{}{"node_ids": []interface{}{nodeID.String()}, "reason": "spamming", "ttl": "-1h"},
+				map[string]interface{}{"node_ids": []interface{}{nodeID.String()}, "reason": "spamming", "ttl": 10},
+			} {
+				err := add.Validator(&admin.CommandRequest{Data: data})
+				assert.True(t, admin.IsInvalidAdminParameterError(err), "data: %v", data)
+			}
+			for _, data := range []interface{}{
+				nil,
+				map[string]interface{}{},
+				map[string]interface{}{"peer_ids": []interface{}{"not-a-peer"}},
+			} {
+				err := remove.Validator(&admin.CommandRequest{Data: data})
+				assert.True(t, admin.IsInvalidAdminParameterError(err), "data: %v", data)
+			}
+		})
+
+		t.Run("not supported", func(t *testing.T) {
+			_, err := NewGetDisallowListCommand(nil, nil, nil).Handler(context.Background(), &admin.CommandRequest{})
+			assert.Error(t, err)
+		})
+	})
+}
@@ -0,0 +1,96 @@
+package common
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/alsp"
+	"github.com/onflow/flow-go/network/alsp/model"
+)
+
+var _ commands.AdminCommand = (*GetAlspPenaltiesCommand)(nil)
+
+// alspPenalty is the admin representation of the spam record of a misbehaving node.
+type alspPenalty struct {
+	NodeID         flow.Identifier `json:"node_id"`
+	Penalty        float64         `json:"penalty"`
+	Decay          float64         `json:"decay"`
+	CutoffCounter  uint64          `json:"cutoff_counter"`
+	DisallowListed bool            `json:"disallow_listed"`
+}
+
+func toAlspPenalty(record model.ProtocolSpamRecord) alspPenalty {
+	return alspPenalty{
+		NodeID:         record.OriginId,
+		Penalty:        record.Penalty,
+		Decay:          record.Decay,
+		CutoffCounter:  record.CutoffCounter,
+		DisallowListed: record.DisallowListed,
+	}
+}
+
+type getAlspPenaltiesResponse struct {
+	Penalties []alspPenalty       `json:"penalties"`
+	NotFound  flow.IdentifierList `json:"not_found,omitempty"`
+}
+
+// GetAlspPenaltiesCommand returns the penalties applied by the application layer spam prevention (ALSP) module
+// to misbehaving nodes. Without input, the penalties of all misbehaving nodes are returned, otherwise only those
+// of the nodes in "node_ids".
+type GetAlspPenaltiesCommand struct {
+	spamRecords alsp.SpamRecordAdmin
+}
+
+func NewGetAlspPenaltiesCommand(spamRecords alsp.SpamRecordAdmin) *GetAlspPenaltiesCommand {
+	return &GetAlspPenaltiesCommand{
+		spamRecords: spamRecords,
+	}
+}
+
+func (g *GetAlspPenaltiesCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if g.spamRecords == nil {
+		return nil, admin.NewInvalidAdminReqErrorf("alsp penalties are not supported by this node")
+	}
+
+	response := getAlspPenaltiesResponse{Penalties: []alspPenalty{}}
+	nodeIDs, ok := req.ValidatorData.(flow.IdentifierList)
+	if !ok {
+		for _, record := range g.spamRecords.SpamRecords() {
+			response.Penalties = append(response.Penalties, toAlspPenalty(record))
+		}
+		return commands.ConvertToMap(response)
+	}
+	for _, nodeID := range nodeIDs {
+		record, ok := g.spamRecords.SpamRecord(nodeID)
+		if !ok {
+			response.NotFound = append(response.NotFound, nodeID)
+			continue
+		}
+		response.Penalties = append(response.Penalties, toAlspPenalty(*record))
+	}
+	return commands.ConvertToMap(response)
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (g *GetAlspPenaltiesCommand) Validator(req *admin.CommandRequest) error {
+	if req.Data == nil {
+		return nil
+	}
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+	values, ok := input["node_ids"]
+	if !ok {
+		return nil
+	}
+	nodeIDs, err := parseStringList("node_ids", values, "must be a list of node IDs", flow.HexStringToIdentifier)
+	if err != nil {
+		return err
+	}
+	req.ValidatorData = flow.IdentifierList(nodeIDs)
+	return nil
+}
@@ -0,0 +1,119 @@
+package common
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/network/bandwidth"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+var _ commands.AdminCommand = (*GetBandwidthReportCommand)(nil)
+
+type channelBandwidthUsage struct {
+	Channel   string `json:"channel"`
+	Direction string `json:"direction"`
+	Role      string `json:"role"`
+	Bytes     uint64 `json:"bytes"`
+	Messages  uint64 `json:"messages"`
+}
+
+type channelBandwidthQuota struct {
+	Channel              string `json:"channel"`
+	Direction            string `json:"direction"`
+	BytesPerSecond       int    `json:"bytes_per_second"`
+	Burst                int    `json:"burst"`
+	OriginBytesPerSecond int    `json:"origin_bytes_per_second,omitempty"`
+	OriginBurst          int    `json:"origin_burst,omitempty"`
+	DroppedBytes         uint64 `json:"dropped_bytes"`
+	DroppedMessages      uint64 `json:"dropped_messages"`
+}
+
+type getBandwidthReportResponse struct {
+	Usage  []channelBandwidthUsage `json:"usage"`
+	Quotas []channelBandwidthQuota `json:"quotas"`
+}
+
+// GetBandwidthReportCommand returns the bytes and messages exchanged by the node since startup per channel,
+// direction and role of the remote node, together with the configured per-channel bandwidth quotas and the
+// messages dropped because they exceeded them. The report can be restricted to the given channels.
+type GetBandwidthReportCommand struct {
+	accountant *bandwidth.Accountant
+}
+
+func NewGetBandwidthReportCommand(accountant *bandwidth.Accountant) *GetBandwidthReportCommand {
+	return &GetBandwidthReportCommand{
+		accountant: accountant,
+	}
+}
+
+func (g *GetBandwidthReportCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if g.accountant == nil {
+		return nil, admin.NewInvalidAdminReqErrorf("bandwidth accounting is not enabled on this node")
+	}
+
+	filter, _ := req.ValidatorData.(channels.ChannelList)
+	included := func(channel channels.Channel) bool {
+		return len(filter) == 0 || filter.Contains(channel)
+	}
+
+	report := g.accountant.Report()
+	response := getBandwidthReportResponse{
+		Usage:  []channelBandwidthUsage{},
+		Quotas: []channelBandwidthQuota{},
+	}
+	for _, usage := range report.Usage {
+		if !included(usage.Channel) {
+			continue
+		}
+		response.Usage = append(response.Usage, channelBandwidthUsage{
+			Channel:   usage.Channel.String(),
+			Direction: string(usage.Direction),
+			Role:      usage.Role,
+			Bytes:     usage.Bytes,
+			Messages:  usage.Messages,
+		})
+	}
+	for _, quota := range report.Quotas {
+		if !included(quota.Channel) {
+			continue
+		}
+		response.Quotas = append(response.Quotas, channelBandwidthQuota{
+			Channel:              quota.Channel.String(),
+			Direction:            string(quota.Direction),
+			BytesPerSecond:       quota.BytesPerSecond,
+			Burst:                quota.Burst,
+			OriginBytesPerSecond: quota.OriginBytesPerSecond,
+			OriginBurst:          quota.OriginBurst,
+			DroppedBytes:         quota.DroppedBytes,
+			DroppedMessages:      quota.DroppedMessages,
+		})
+	}
+	return commands.ConvertToMap(response)
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (g *GetBandwidthReportCommand) Validator(req *admin.CommandRequest) error {
+	if req.Data == nil {
+		return nil
+	}
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+	values, ok := input["channels"]
+	if !ok {
+		return nil
+	}
+	filter, err := parseStringList("channels", values, "must be a list of channels", func(str string) (channels.Channel, error) {
+		channel := channels.Channel(str)
+		return channel, channels.IsValidFlowChannel(channel)
+	})
+	if err != nil {
+		return err
+	}
+	req.ValidatorData = channels.ChannelList(filter)
+	return nil
+}
@@ -0,0 +1,67 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/bandwidth"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestGetBandwidthReport tests that the report includes the usage and quotas of the requested channels.
+func TestGetBandwidthReport(t *testing.T) {
+	execution := unittest.IdentityFixture(unittest.WithRole(flow.RoleExecution))
+	idProvider := mock.NewIdentityProvider(t)
+	idProvider.On("ByNodeID", execution.NodeID).Return(execution, true)
+
+	accountant, err := bandwidth.NewAccountant(unittest.Logger(), metrics.NewNoopCollector(), idProvider, []bandwidth.Quota{{
+		Channel:        channels.RequestChunks,
+		Direction:      bandwidth.DirectionInbound,
+		BytesPerSecond: 1000,
+		Burst:          2000,
+	}})
+	require.NoError(t, err)
+	require.NoError(t, accountant.OnInbound(channels.RequestChunks, execution.NodeID, 100))
+	accountant.OnOutboundSent(channels.SyncCommittee, 50, execution.NodeID)
+
+	cmd := NewGetBandwidthReportCommand(accountant)
+
+	req := &admin.CommandRequest{Data: map[string]interface{}{"channels": []interface{}{"request-chunks"}}}
+	require.NoError(t, cmd.Validator(req))
+	result, err := cmd.Handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"usage": []interface{}{map[string]interface{}{
+			"channel":   "request-chunks",
+			"direction": "inbound",
+			"role":      "execution",
+			"bytes":     float64(100),
+			"messages":  float64(1),
+		}},
+		"quotas": []interface{}{map[string]interface{}{
+			"channel":          "request-chunks",
+			"direction":        "inbound",
+			"bytes_per_second": float64(1000),
+			"burst":            float64(2000),
+			"dropped_bytes":    float64(0),
+			"dropped_messages": float64(0),
+		}},
+	}, result)
+
+	req = &admin.CommandRequest{}
+	require.NoError(t, cmd.Validator(req))
+	result, err = cmd.Handler(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, result.(map[string]interface{})["usage"], 2)
+
+	req = &admin.CommandRequest{Data: map[string]interface{}{"channels": []interface{}{"unknown"}}}
+	require.True(t, admin.IsInvalidAdminParameterError(cmd.Validator(req)))
+}
@@ -0,0 +1,90 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/p2p/connectivity"
+)
+
+var _ commands.AdminCommand = (*GetConnectivitySnapshotCommand)(nil)
+
+const (
+	connectivityFormatJSON = "json"
+	connectivityFormatDOT  = "dot"
+)
+
+type getConnectivitySnapshotRequest struct {
+	network network.NetworkingType
+	format  string
+}
+
+// GetConnectivitySnapshotCommand returns a point-in-time snapshot of the connectivity of the node on the "private"
+// (default) or "public" network: the connected peers with their roles, multiaddrs, connection directions and ages,
+// whether their connections are protected by the connection manager, the GossipSub meshes of the node per topic,
+// and the DHT routing table for networks running a DHT. The snapshot is returned as JSON (default), or as a
+// Graphviz DOT graph if "format" is "dot". JSON snapshots of several nodes can be merged into a network-wide graph
+// with the `merge-connectivity-snapshots` util command.
+type GetConnectivitySnapshotCommand struct {
+	snapshotters map[network.NetworkingType]*connectivity.Snapshotter
+}
+
+func NewGetConnectivitySnapshotCommand(snapshotters map[network.NetworkingType]*connectivity.Snapshotter) *GetConnectivitySnapshotCommand {
+	return &GetConnectivitySnapshotCommand{
+		snapshotters: snapshotters,
+	}
+}
+
+func (g *GetConnectivitySnapshotCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	data := req.ValidatorData.(*getConnectivitySnapshotRequest)
+	snapshotter, ok := g.snapshotters[data.network]
+	if !ok {
+		return nil, admin.NewInvalidAdminReqErrorf("the node is not part of the %s network", data.network)
+	}
+
+	snapshot := snapshotter.Snapshot()
+	if data.format == connectivityFormatDOT {
+		return connectivity.Merge(snapshot).DOT(), nil
+	}
+	return commands.ConvertToMap(snapshot)
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (g *GetConnectivitySnapshotCommand) Validator(req *admin.CommandRequest) error {
+	data := &getConnectivitySnapshotRequest{
+		network: network.PrivateNetwork,
+		format:  connectivityFormatJSON,
+	}
+	req.ValidatorData = data
+	if req.Data == nil {
+		return nil
+	}
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	if value, ok := input["network"]; ok {
+		switch value {
+		case network.PrivateNetwork.String():
+			data.network = network.PrivateNetwork
+		case network.PublicNetwork.String():
+			data.network = network.PublicNetwork
+		default:
+			return admin.NewInvalidAdminReqParameterError("network", fmt.Sprintf("must be one of: %s, %s", network.PrivateNetwork, network.PublicNetwork), value)
+		}
+	}
+	if value, ok := input["format"]; ok {
+		switch value {
+		case connectivityFormatJSON, connectivityFormatDOT:
+			data.format = value.(string)
+		default:
+			return admin.NewInvalidAdminReqParameterError("format", fmt.Sprintf("must be one of: %s, %s", connectivityFormatJSON, connectivityFormatDOT), value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/p2p/connectivity"
+)
+
+// TestGetConnectivitySnapshotValidator tests that the network and format of the snapshot are validated.
+func TestGetConnectivitySnapshotValidator(t *testing.T) {
+	cmd := NewGetConnectivitySnapshotCommand(map[network.NetworkingType]*connectivity.Snapshotter{})
+
+	req := &admin.CommandRequest{}
+	require.NoError(t, cmd.Validator(req))
+	require.Equal(t, &getConnectivitySnapshotRequest{network: network.PrivateNetwork, format: connectivityFormatJSON}, req.ValidatorData)
+
+	req = &admin.CommandRequest{Data: map[string]interface{}{"network": "public", "format": "dot"}}
+	require.NoError(t, cmd.Validator(req))
+	require.Equal(t, &getConnectivitySnapshotRequest{network: network.PublicNetwork, format: connectivityFormatDOT}, req.ValidatorData)
+
+	// the node is not part of the public network
+	_, err := cmd.Handler(context.Background(), req)
+	require.True(t, admin.IsInvalidAdminParameterError(err), err)
+
+	for name, data := range map[string]interface{}{
+		"invalid network": map[string]interface{}{"network": "internal"},
+		"invalid format":  map[string]interface{}{"format": "svg"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := cmd.Validator(&admin.CommandRequest{Data: data})
+			require.True(t, admin.IsInvalidAdminParameterError(err), err)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package common
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/alsp"
+	"github.com/onflow/flow-go/network/disallowlist"
+	"github.com/onflow/flow-go/network/p2p/cache"
+)
+
+var _ commands.AdminCommand = (*GetDisallowListCommand)(nil)
+
+const (
+	disallowListSourceOperator  = "operator"
+	disallowListSourceBlocklist = "network-id-provider-blocklist"
+	disallowListSourceAlsp      = "alsp"
+)
+
+// disallowListEntry is the admin representation of a disallow-listed node or peer.
+type disallowListEntry struct {
+	NodeID  string     `json:"node_id,omitempty"`
+	PeerID  string     `json:"peer_id,omitempty"`
+	Sources []string   `json:"sources"`
+	Reason  string     `json:"reason,omitempty"`
+	Added   *time.Time `json:"added,omitempty"`
+	Expiry  *time.Time `json:"expiry,omitempty"`
+}
+
+// GetDisallowListCommand returns the nodes and peers which are currently disallow-listed, together with the
+// sources of the disallow-listing: the operator-managed disallow list, the node ID blocklist configured via the
+// network-id-provider-blocklist config, and the application layer spam prevention (ALSP) module.
+type GetDisallowListCommand struct {
+	disallowList *disallowlist.OperatorDisallowList
+	blocklist    *cache.NodeDisallowListingWrapper
+	spamRecords  alsp.SpamRecordAdmin
+}
+
+func NewGetDisallowListCommand(
+	disallowList *disallowlist.OperatorDisallowList,
+	blocklist *cache.NodeDisallowListingWrapper,
+	spamRecords alsp.SpamRecordAdmin,
+) *GetDisallowListCommand {
+	return &GetDisallowListCommand{
+		disallowList: disallowList,
+		blocklist:    blocklist,
+		spamRecords:  spamRecords,
+	}
+}
+
+func (g *GetDisallowListCommand) Handler(_ context.Context, _ *admin.CommandRequest) (interface{}, error) {
+	if g.disallowList == nil && g.blocklist == nil && g.spamRecords == nil {
+		return nil, admin.NewInvalidAdminReqErrorf("disallow list is not supported by this node")
+	}
+
+	byNodeID := make(map[flow.Identifier]*disallowListEntry)
+	entries := make([]*disallowListEntry, 0)
+	// addNodeSource adds the given source to the entry of the given node, creating the entry if necessary.
+	addNodeSource := func(nodeID flow.Identifier, source string) {
+		if entry, ok := byNodeID[nodeID]; ok {
+			entry.Sources = append(entry.Sources, source)
+			return
+		}
+		entry := &disallowListEntry{
+			NodeID:  nodeID.String(),
+			Sources: []string{source},
+		}
+		byNodeID[nodeID] = entry
+		entries = append(entries, entry)
+	}
+	if g.disallowList != nil {
+		for _, e := range g.disallowList.Entries() {
+			entry := &disallowListEntry{
+				Sources: []string{disallowListSourceOperator},
+				Reason:  e.Reason,
+				Added:   &e.Added,
+			}
+			if e.NodeID != flow.ZeroID {
+				entry.NodeID = e.NodeID.String()
+				byNodeID[e.NodeID] = entry
+			} else {
+				entry.PeerID = e.PeerID.String()
+			}
+			if !e.Expiry.IsZero() {
+				entry.Expiry = &e.Expiry
+			}
+			entries = append(entries, entry)
+		}
+	}
+	if g.blocklist != nil {
+		for _, nodeID := range g.blocklist.GetDisallowList() {
+			addNodeSource(nodeID, disallowListSourceBlocklist)
+		}
+	}
+	if g.spamRecords != nil {
+		for _, record := range g.spamRecords.SpamRecords() {
+			if record.DisallowListed {
+				addNodeSource(record.OriginId, disallowListSourceAlsp)
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].NodeID+entries[i].PeerID < entries[j].NodeID+entries[j].PeerID
+	})
+
+	return commands.ConvertToMap(struct {
+		Entries []*disallowListEntry `json:"entries"`
+	}{Entries: entries})
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (g *GetDisallowListCommand) Validator(_ *admin.CommandRequest) error {
+	return nil
+}
@@ -0,0 +1,85 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/consensus/hotstuff/cruisectl"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/epochs"
+	"github.com/onflow/flow-go/state/protocol"
+)
+
+var _ commands.AdminCommand = (*GetEpochForecastCommand)(nil)
+
+type epochTransitionResponse struct {
+	Transition string    `json:"transition"`
+	View       uint64    `json:"view"`
+	Time       time.Time `json:"time"`
+	Estimated  bool      `json:"estimated"`
+}
+
+type epochForecastResponse struct {
+	EpochCounter           uint64                    `json:"epoch_counter"`
+	EpochPhase             string                    `json:"epoch_phase"`
+	EpochFallbackTriggered bool                      `json:"epoch_fallback_triggered"`
+	CurrentView            uint64                    `json:"current_view"`
+	FinalView              uint64                    `json:"final_view"`
+	TargetEndTime          time.Time                 `json:"target_end_time"`
+	Extensions             []flow.EpochExtension     `json:"extensions"`
+	ViewDuration           string                    `json:"view_duration"`
+	Transitions            []epochTransitionResponse `json:"transitions"`
+}
+
+// GetEpochForecastCommand reports the projected wall-clock times of the upcoming transitions of the
+// current epoch, as of the latest finalized block.
+type GetEpochForecastCommand struct {
+	state  protocol.State
+	timing *cruisectl.TimingConfig
+}
+
+// NewGetEpochForecastCommand creates a command projecting the epoch transitions with the given
+// limits of authority of the cruise controller.
+func NewGetEpochForecastCommand(state protocol.State, timing *cruisectl.TimingConfig) *GetEpochForecastCommand {
+	return &GetEpochForecastCommand{
+		state:  state,
+		timing: timing,
+	}
+}
+
+func (g *GetEpochForecastCommand) Handler(_ context.Context, _ *admin.CommandRequest) (interface{}, error) {
+	forecast, err := epochs.ForecastEpoch(g.state.Final(), g.timing, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("could not forecast epoch transitions: %w", err)
+	}
+
+	response := epochForecastResponse{
+		EpochCounter:           forecast.EpochCounter,
+		EpochPhase:             forecast.EpochPhase.String(),
+		EpochFallbackTriggered: forecast.EpochFallbackTriggered,
+		CurrentView:            forecast.CurrentView,
+		FinalView:              forecast.FinalView,
+		TargetEndTime:          forecast.TargetEndTime,
+		Extensions:             forecast.Extensions,
+		ViewDuration:           forecast.ViewDuration.String(),
+		Transitions:            make([]epochTransitionResponse, 0, len(forecast.Transitions)),
+	}
+	for _, transition := range forecast.Transitions {
+		response.Transitions = append(response.Transitions, epochTransitionResponse{
+			Transition: string(transition.Transition),
+			View:       transition.View,
+			Time:       transition.Time,
+			Estimated:  transition.Estimated,
+		})
+	}
+	return commands.ConvertToMap(response)
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (g *GetEpochForecastCommand) Validator(_ *admin.CommandRequest) error {
+	return nil
+}
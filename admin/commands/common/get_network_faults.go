@@ -0,0 +1,94 @@
+package common
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/network/faults"
+)
+
+var _ commands.AdminCommand = (*GetNetworkFaultsCommand)(nil)
+
+// networkFaultRule is the admin representation of a network fault rule.
+type networkFaultRule struct {
+	ID            string   `json:"id"`
+	Direction     string   `json:"direction"`
+	Channels      []string `json:"channels,omitempty"`
+	Roles         []string `json:"roles,omitempty"`
+	NodeIDs       []string `json:"node_ids,omitempty"`
+	Partition     bool     `json:"partition,omitempty"`
+	DropRate      float64  `json:"drop_rate,omitempty"`
+	DuplicateRate float64  `json:"duplicate_rate,omitempty"`
+	Delay         string   `json:"delay,omitempty"`
+	Jitter        string   `json:"jitter,omitempty"`
+	Distribution  string   `json:"distribution,omitempty"`
+}
+
+func toNetworkFaultRule(rule faults.Rule) networkFaultRule {
+	r := networkFaultRule{
+		ID:            rule.ID,
+		Direction:     string(rule.Direction),
+		Channels:      rule.Channels.String(),
+		Partition:     rule.Partition,
+		DropRate:      rule.DropRate,
+		DuplicateRate: rule.DuplicateRate,
+	}
+	for _, role := range rule.Roles {
+		r.Roles = append(r.Roles, role.String())
+	}
+	for _, nodeID := range rule.NodeIDs {
+		r.NodeIDs = append(r.NodeIDs, nodeID.String())
+	}
+	if rule.Delay.Base > 0 {
+		r.Delay = rule.Delay.Base.String()
+	}
+	if rule.Delay.Jitter > 0 {
+		r.Jitter = rule.Delay.Jitter.String()
+		r.Distribution = string(rule.Delay.Distribution)
+	}
+	return r
+}
+
+type getNetworkFaultsResponse struct {
+	Rules      []networkFaultRule `json:"rules"`
+	Dropped    uint64             `json:"dropped"`
+	Delayed    uint64             `json:"delayed"`
+	Duplicated uint64             `json:"duplicated"`
+}
+
+// GetNetworkFaultsCommand returns the network fault rules of the node, together with the number of messages
+// dropped, delayed and duplicated by fault injection so far.
+type GetNetworkFaultsCommand struct {
+	injector *faults.Injector
+}
+
+func NewGetNetworkFaultsCommand(injector *faults.Injector) *GetNetworkFaultsCommand {
+	return &GetNetworkFaultsCommand{
+		injector: injector,
+	}
+}
+
+func (g *GetNetworkFaultsCommand) Handler(_ context.Context, _ *admin.CommandRequest) (interface{}, error) {
+	if g.injector == nil {
+		return nil, admin.NewInvalidAdminReqErrorf("network fault injection is not enabled on this node")
+	}
+
+	stats := g.injector.Stats()
+	response := getNetworkFaultsResponse{
+		Rules:      []networkFaultRule{},
+		Dropped:    stats.Dropped,
+		Delayed:    stats.Delayed,
+		Duplicated: stats.Duplicated,
+	}
+	for _, rule := range g.injector.Rules() {
+		response.Rules = append(response.Rules, toNetworkFaultRule(rule))
+	}
+	return commands.ConvertToMap(response)
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (g *GetNetworkFaultsCommand) Validator(_ *admin.CommandRequest) error {
+	return nil
+}
@@ -0,0 +1,68 @@
+package common
+
+import (
+	"context"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/network/p2p/scoring"
+)
+
+var _ commands.AdminCommand = (*GetPeerScoresCommand)(nil)
+
+type getPeerScoresResponse struct {
+	Peers []scoring.PeerScoreReport `json:"peers"`
+}
+
+// GetPeerScoresCommand explains the GossipSub scores of peers: for each peer it returns the score computed by
+// GossipSub with its topic scores and IP colocation factor, the cached application specific score, the spam
+// record of the scoring registry, and the recent RPC inspection violations which contributed to its penalty.
+// Without input, the reports of all connected peers are returned, otherwise only those of the peers in "peer_ids".
+// Reports are ordered from the lowest to the highest score.
+type GetPeerScoresCommand struct {
+	explorer *scoring.ScoreExplorer
+}
+
+func NewGetPeerScoresCommand(explorer *scoring.ScoreExplorer) *GetPeerScoresCommand {
+	return &GetPeerScoresCommand{
+		explorer: explorer,
+	}
+}
+
+func (g *GetPeerScoresCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if g.explorer == nil {
+		return nil, admin.NewInvalidAdminReqErrorf("peer scores are not supported by this node")
+	}
+
+	var response getPeerScoresResponse
+	if peerIDs, ok := req.ValidatorData.([]peer.ID); ok {
+		response.Peers = g.explorer.Peers(peerIDs)
+	} else {
+		response.Peers = g.explorer.ConnectedPeers()
+	}
+	return commands.ConvertToMap(response)
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (g *GetPeerScoresCommand) Validator(req *admin.CommandRequest) error {
+	if req.Data == nil {
+		return nil
+	}
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+	values, ok := input["peer_ids"]
+	if !ok {
+		return nil
+	}
+	peerIDs, err := parseStringList("peer_ids", values, "must be a list of peer IDs", peer.Decode)
+	if err != nil {
+		return err
+	}
+	req.ValidatorData = peerIDs
+	return nil
+}
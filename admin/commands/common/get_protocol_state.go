@@ -0,0 +1,182 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/state"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/history"
+)
+
+var _ commands.AdminCommand = (*GetProtocolStateCommand)(nil)
+var _ commands.AdminCommand = (*GetProtocolStateDiffCommand)(nil)
+
+// heightRef references a finalized height, or the latest finalized or sealed height.
+type heightRef struct {
+	height uint64
+	final  bool
+	sealed bool
+}
+
+// resolve returns the referenced height.
+// No errors are expected during normal operation.
+func (h heightRef) resolve(protocolState protocol.State) (uint64, error) {
+	var snapshot protocol.Snapshot
+	switch {
+	case h.final:
+		snapshot = protocolState.Final()
+	case h.sealed:
+		snapshot = protocolState.Sealed()
+	default:
+		return h.height, nil
+	}
+	head, err := snapshot.Head()
+	if err != nil {
+		return 0, fmt.Errorf("could not get latest block: %w", err)
+	}
+	return head.Height, nil
+}
+
+// parseHeightRef parses the given field as a height, or one of "final" and "sealed".
+// Returns admin.InvalidAdminReqError if the field is invalid.
+func parseHeightRef(field string, value interface{}) (heightRef, error) {
+	switch value := value.(type) {
+	case string:
+		switch value {
+		case "final":
+			return heightRef{final: true}, nil
+		case "sealed":
+			return heightRef{sealed: true}, nil
+		}
+	case float64:
+		if value >= 0 && math.Trunc(value) == value {
+			return heightRef{height: uint64(value)}, nil
+		}
+	}
+	return heightRef{}, admin.NewInvalidAdminReqParameterError(field, "must be a height, \"final\" or \"sealed\"", value)
+}
+
+// GetProtocolStateCommand returns the protocol state key-value store and epoch state entry as of
+// a finalized height, which defaults to the latest finalized block.
+type GetProtocolStateCommand struct {
+	state protocol.State
+}
+
+func NewGetProtocolStateCommand(state protocol.State) *GetProtocolStateCommand {
+	return &GetProtocolStateCommand{
+		state: state,
+	}
+}
+
+func (g *GetProtocolStateCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	ref := req.ValidatorData.(heightRef)
+	height, err := ref.resolve(g.state)
+	if err != nil {
+		return nil, err
+	}
+
+	protocolState, err := history.StateAtHeight(g.state, height)
+	if errors.Is(err, state.ErrUnknownSnapshotReference) {
+		return nil, admin.NewInvalidAdminReqErrorf("no finalized block at height %d", height)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get protocol state at height %d: %w", height, err)
+	}
+	return commands.ConvertToMap(protocolState)
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (g *GetProtocolStateCommand) Validator(req *admin.CommandRequest) error {
+	req.ValidatorData = heightRef{final: true}
+	if req.Data == nil {
+		return nil
+	}
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+	if height, ok := input["height"]; ok {
+		ref, err := parseHeightRef("height", height)
+		if err != nil {
+			return err
+		}
+		req.ValidatorData = ref
+	}
+	return nil
+}
+
+type protocolStateDiffRequest struct {
+	start heightRef
+	end   heightRef
+}
+
+// GetProtocolStateDiffCommand returns the identity changes, ejections and key-value store version
+// upgrades between two finalized heights, where the end height defaults to the latest finalized block.
+type GetProtocolStateDiffCommand struct {
+	state protocol.State
+}
+
+func NewGetProtocolStateDiffCommand(state protocol.State) *GetProtocolStateDiffCommand {
+	return &GetProtocolStateDiffCommand{
+		state: state,
+	}
+}
+
+func (g *GetProtocolStateDiffCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	data := req.ValidatorData.(*protocolStateDiffRequest)
+	startHeight, err := data.start.resolve(g.state)
+	if err != nil {
+		return nil, err
+	}
+	endHeight, err := data.end.resolve(g.state)
+	if err != nil {
+		return nil, err
+	}
+	if startHeight > endHeight {
+		return nil, admin.NewInvalidAdminReqErrorf("start height %d exceeds end height %d", startHeight, endHeight)
+	}
+
+	diff, err := history.Diff(g.state, startHeight, endHeight)
+	if errors.Is(err, state.ErrUnknownSnapshotReference) {
+		return nil, admin.NewInvalidAdminReqErrorf("no finalized block at height %d or %d", startHeight, endHeight)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not diff protocol state between heights %d and %d: %w", startHeight, endHeight, err)
+	}
+	return commands.ConvertToMap(diff)
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (g *GetProtocolStateDiffCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	data := &protocolStateDiffRequest{end: heightRef{final: true}}
+	start, ok := input["start_height"]
+	if !ok {
+		return admin.NewInvalidAdminReqErrorf("the \"start_height\" field is required")
+	}
+	var err error
+	data.start, err = parseHeightRef("start_height", start)
+	if err != nil {
+		return err
+	}
+	if end, ok := input["end_height"]; ok {
+		data.end, err = parseHeightRef("end_height", end)
+		if err != nil {
+			return err
+		}
+	}
+
+	req.ValidatorData = data
+	return nil
+}
@@ -0,0 +1,98 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/state"
+	protocolmock "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestGetProtocolState tests that the protocol state is returned for the latest finalized or a
+// given height, and that invalid requests are rejected.
+func TestGetProtocolState(t *testing.T) {
+	header := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(42))
+	entry := unittest.EpochStateFixture()
+
+	kvstore := protocolmock.NewKVStoreReader(t)
+	kvstore.On("ID").Return(unittest.IdentifierFixture())
+	kvstore.On("GetProtocolStateVersion").Return(uint64(2))
+	kvstore.On("VersionedEncode").Return(uint64(2), []byte{1, 2, 3}, nil)
+	kvstore.On("GetEpochStateID").Return(entry.ID())
+	kvstore.On("GetEpochExtensionViewCount").Return(uint64(100))
+	kvstore.On("GetFinalizationSafetyThreshold").Return(uint64(10))
+	kvstore.On("GetVersionUpgrade").Return(nil)
+	epochState := protocolmock.NewEpochProtocolState(t)
+	epochState.On("EpochPhase").Return(entry.EpochPhase())
+	epochState.On("Entry").Return(entry)
+	snapshot := protocolmock.NewSnapshot(t)
+	snapshot.On("Head").Return(header, nil)
+	snapshot.On("ProtocolState").Return(kvstore, nil)
+	snapshot.On("EpochProtocolState").Return(epochState, nil)
+
+	unknown := protocolmock.NewSnapshot(t)
+	unknown.On("Head").Return(nil, state.ErrUnknownSnapshotReference)
+
+	protocolState := protocolmock.NewState(t)
+	protocolState.On("Final").Return(snapshot).Maybe()
+	protocolState.On("AtHeight", uint64(42)).Return(snapshot)
+	protocolState.On("AtHeight", uint64(43)).Return(unknown).Maybe()
+	command := NewGetProtocolStateCommand(protocolState)
+
+	run := func(data interface{}) (interface{}, error) {
+		req := &admin.CommandRequest{Data: data}
+		require.NoError(t, command.Validator(req))
+		return command.Handler(context.Background(), req)
+	}
+
+	for _, data := range []interface{}{nil, map[string]interface{}{"height": float64(42)}} {
+		result, err := run(data)
+		require.NoError(t, err)
+		response := result.(map[string]interface{})
+		assert.Equal(t, float64(42), response["Height"])
+		assert.Equal(t, header.ID().String(), response["BlockID"])
+		assert.Equal(t, float64(2), response["KVStore"].(map[string]interface{})["Version"])
+		assert.NotNil(t, response["EpochState"])
+	}
+
+	_, err := run(map[string]interface{}{"height": float64(43)})
+	assert.True(t, admin.IsInvalidAdminParameterError(err))
+
+	for _, data := range []interface{}{
+		"height",
+		map[string]interface{}{"height": "latest"},
+		map[string]interface{}{"height": 1.5},
+		map[string]interface{}{"height": -1.0},
+	} {
+		assert.Error(t, command.Validator(&admin.CommandRequest{Data: data}), data)
+	}
+}
+
+// TestGetProtocolStateDiff_Validator tests that the diff command requires a valid start height and
+// accepts an optional end height.
+func TestGetProtocolStateDiff_Validator(t *testing.T) {
+	command := NewGetProtocolStateDiffCommand(protocolmock.NewState(t))
+
+	req := &admin.CommandRequest{Data: map[string]interface{}{"start_height": float64(10)}}
+	require.NoError(t, command.Validator(req))
+	assert.Equal(t, &protocolStateDiffRequest{start: heightRef{height: 10}, end: heightRef{final: true}}, req.ValidatorData)
+
+	req = &admin.CommandRequest{Data: map[string]interface{}{"start_height": "sealed", "end_height": float64(20)}}
+	require.NoError(t, command.Validator(req))
+	assert.Equal(t, &protocolStateDiffRequest{start: heightRef{sealed: true}, end: heightRef{height: 20}}, req.ValidatorData)
+
+	for _, data := range []interface{}{
+		nil,
+		map[string]interface{}{},
+		map[string]interface{}{"end_height": float64(20)},
+		map[string]interface{}{"start_height": "latest"},
+		map[string]interface{}{"start_height": float64(10), "end_height": -1.0},
+	} {
+		assert.Error(t, command.Validator(&admin.CommandRequest{Data: data}), data)
+	}
+}
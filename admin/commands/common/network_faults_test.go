@@ -0,0 +1,106 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/faults"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestNetworkFaultCommands tests adding network fault rules, reporting them, and removing them.
+func TestNetworkFaultCommands(t *testing.T) {
+	injector := faults.NewInjector(unittest.Logger(), mock.NewIdentityProvider(t))
+	add := NewAddNetworkFaultCommand(injector)
+	get := NewGetNetworkFaultsCommand(injector)
+	remove := NewRemoveNetworkFaultsCommand(injector)
+	nodeID := unittest.IdentifierFixture()
+
+	req := &admin.CommandRequest{Data: map[string]interface{}{
+		"id":           "slow-consensus",
+		"direction":    "outbound",
+		"channels":     []interface{}{"consensus-committee"},
+		"roles":        []interface{}{"consensus"},
+		"node_ids":     []interface{}{nodeID.String()},
+		"drop_rate":    0.1,
+		"delay":        "100ms",
+		"jitter":       "50ms",
+		"distribution": "exponential",
+	}}
+	require.NoError(t, add.Validator(req))
+	_, err := add.Handler(context.Background(), req)
+	require.NoError(t, err)
+
+	req = &admin.CommandRequest{Data: map[string]interface{}{
+		"id":        "partition",
+		"partition": true,
+	}}
+	require.NoError(t, add.Validator(req))
+	_, err = add.Handler(context.Background(), req)
+	require.NoError(t, err)
+
+	req = &admin.CommandRequest{}
+	require.NoError(t, get.Validator(req))
+	result, err := get.Handler(context.Background(), req)
+	require.NoError(t, err)
+	rules := result.(map[string]interface{})["rules"].([]interface{})
+	require.Len(t, rules, 2)
+	assert.Equal(t, map[string]interface{}{
+		"id":           "slow-consensus",
+		"direction":    "outbound",
+		"channels":     []interface{}{"consensus-committee"},
+		"roles":        []interface{}{"consensus"},
+		"node_ids":     []interface{}{nodeID.String()},
+		"drop_rate":    0.1,
+		"delay":        "100ms",
+		"jitter":       "50ms",
+		"distribution": "exponential",
+	}, rules[0])
+	assert.Equal(t, map[string]interface{}{
+		"id":        "partition",
+		"direction": "both",
+		"partition": true,
+	}, rules[1])
+
+	req = &admin.CommandRequest{Data: map[string]interface{}{
+		"ids": []interface{}{"partition", "unknown"},
+	}}
+	require.NoError(t, remove.Validator(req))
+	result, err = remove.Handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"not_found": []interface{}{"unknown"}}, result)
+	require.Len(t, injector.Rules(), 1)
+
+	req = &admin.CommandRequest{Data: map[string]interface{}{"all": true}}
+	require.NoError(t, remove.Validator(req))
+	_, err = remove.Handler(context.Background(), req)
+	require.NoError(t, err)
+	require.Empty(t, injector.Rules())
+}
+
+// TestAddNetworkFaultValidator tests that invalid network fault rules are rejected.
+func TestAddNetworkFaultValidator(t *testing.T) {
+	add := NewAddNetworkFaultCommand(nil)
+
+	for name, data := range map[string]interface{}{
+		"not a map":            "partition",
+		"missing id":           map[string]interface{}{"partition": true},
+		"no fault":             map[string]interface{}{"id": "rule"},
+		"invalid direction":    map[string]interface{}{"id": "rule", "partition": true, "direction": "sideways"},
+		"invalid channel":      map[string]interface{}{"id": "rule", "partition": true, "channels": []interface{}{"unknown"}},
+		"invalid role":         map[string]interface{}{"id": "rule", "partition": true, "roles": []interface{}{"unknown"}},
+		"invalid drop rate":    map[string]interface{}{"id": "rule", "drop_rate": 2.0},
+		"invalid delay":        map[string]interface{}{"id": "rule", "delay": "soon"},
+		"invalid distribution": map[string]interface{}{"id": "rule", "jitter": "1s", "distribution": "normal"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := add.Validator(&admin.CommandRequest{Data: data})
+			require.True(t, admin.IsInvalidAdminParameterError(err), err)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/network/disallowlist"
+	"github.com/onflow/flow-go/network/disallowlist/model"
+)
+
+var _ commands.AdminCommand = (*RemoveFromDisallowListCommand)(nil)
+
+type removeFromDisallowListResponse struct {
+	Removed  []string `json:"removed"`
+	NotFound []string `json:"not_found"`
+}
+
+// RemoveFromDisallowListCommand removes nodes, given by node ID, and peers, given by peer ID, from the
+// operator-managed disallow list. Nodes and peers which are disallow-listed for other causes, e.g., by the
+// application layer spam prevention (ALSP) module, remain disallow-listed.
+type RemoveFromDisallowListCommand struct {
+	disallowList *disallowlist.OperatorDisallowList
+}
+
+func NewRemoveFromDisallowListCommand(disallowList *disallowlist.OperatorDisallowList) *RemoveFromDisallowListCommand {
+	return &RemoveFromDisallowListCommand{
+		disallowList: disallowList,
+	}
+}
+
+func (r *RemoveFromDisallowListCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if r.disallowList == nil {
+		return nil, admin.NewInvalidAdminReqErrorf("operator disallow list is not supported by this node")
+	}
+	keys := req.ValidatorData.([]string)
+
+	removed, err := r.disallowList.Remove(keys...)
+	if err != nil {
+		return nil, fmt.Errorf("could not remove entries from disallow list: %w", err)
+	}
+	response := removeFromDisallowListResponse{
+		Removed:  []string{},
+		NotFound: []string{},
+	}
+	removedKeys := make(map[string]struct{}, len(removed))
+	for _, entry := range removed {
+		removedKeys[entry.Key()] = struct{}{}
+	}
+	for _, key := range keys {
+		if _, ok := removedKeys[key]; ok {
+			response.Removed = append(response.Removed, key)
+		} else {
+			response.NotFound = append(response.NotFound, key)
+		}
+	}
+	return commands.ConvertToMap(response)
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (r *RemoveFromDisallowListCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+	nodeIDs, peerIDs, err := parseDisallowListTargets(input)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(nodeIDs)+len(peerIDs))
+	for _, nodeID := range nodeIDs {
+		keys = append(keys, (&model.Entry{NodeID: nodeID}).Key())
+	}
+	for _, peerID := range peerIDs {
+		keys = append(keys, (&model.Entry{PeerID: peerID}).Key())
+	}
+	req.ValidatorData = keys
+	return nil
+}
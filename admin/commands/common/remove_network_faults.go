@@ -0,0 +1,66 @@
+package common
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/network/faults"
+)
+
+var _ commands.AdminCommand = (*RemoveNetworkFaultsCommand)(nil)
+
+type removeNetworkFaultsResponse struct {
+	NotFound []string `json:"not_found,omitempty"`
+}
+
+// RemoveNetworkFaultsCommand removes the network fault rules with the given IDs, or all rules if "all" is set.
+type RemoveNetworkFaultsCommand struct {
+	injector *faults.Injector
+}
+
+func NewRemoveNetworkFaultsCommand(injector *faults.Injector) *RemoveNetworkFaultsCommand {
+	return &RemoveNetworkFaultsCommand{
+		injector: injector,
+	}
+}
+
+func (r *RemoveNetworkFaultsCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if r.injector == nil {
+		return nil, admin.NewInvalidAdminReqErrorf("network fault injection is not enabled on this node")
+	}
+	ids, ok := req.ValidatorData.([]string)
+	if !ok {
+		r.injector.Clear()
+		return commands.ConvertToMap(removeNetworkFaultsResponse{})
+	}
+	return commands.ConvertToMap(removeNetworkFaultsResponse{
+		NotFound: r.injector.RemoveRules(ids...),
+	})
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (r *RemoveNetworkFaultsCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+	if all, ok := input["all"]; ok {
+		if all != true {
+			return admin.NewInvalidAdminReqParameterError("all", "must be true if set", all)
+		}
+		return nil
+	}
+	ids, err := parseStringList("ids", input["ids"], "must be a non-empty list of rule IDs", func(str string) (string, error) {
+		return str, nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return admin.NewInvalidAdminReqParameterError("ids", "must be a non-empty list of rule IDs", input["ids"])
+	}
+	req.ValidatorData = ids
+	return nil
+}
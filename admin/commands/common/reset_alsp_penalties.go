@@ -0,0 +1,66 @@
+package common
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/alsp"
+)
+
+var _ commands.AdminCommand = (*ResetAlspPenaltiesCommand)(nil)
+
+type resetAlspPenaltiesResponse struct {
+	Reset    flow.IdentifierList `json:"reset"`
+	NotFound flow.IdentifierList `json:"not_found"`
+}
+
+// ResetAlspPenaltiesCommand resets the penalties applied by the application layer spam prevention (ALSP) module
+// to the nodes in "node_ids", allow-listing them if they are disallow-listed by the ALSP module.
+type ResetAlspPenaltiesCommand struct {
+	spamRecords alsp.SpamRecordAdmin
+}
+
+func NewResetAlspPenaltiesCommand(spamRecords alsp.SpamRecordAdmin) *ResetAlspPenaltiesCommand {
+	return &ResetAlspPenaltiesCommand{
+		spamRecords: spamRecords,
+	}
+}
+
+func (r *ResetAlspPenaltiesCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if r.spamRecords == nil {
+		return nil, admin.NewInvalidAdminReqErrorf("alsp penalties are not supported by this node")
+	}
+
+	response := resetAlspPenaltiesResponse{
+		Reset:    flow.IdentifierList{},
+		NotFound: flow.IdentifierList{},
+	}
+	for _, nodeID := range req.ValidatorData.(flow.IdentifierList) {
+		if r.spamRecords.ResetSpamRecord(nodeID) {
+			response.Reset = append(response.Reset, nodeID)
+		} else {
+			response.NotFound = append(response.NotFound, nodeID)
+		}
+	}
+	return commands.ConvertToMap(response)
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (r *ResetAlspPenaltiesCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+	nodeIDs, err := parseStringList("node_ids", input["node_ids"], "must be a non-empty list of node IDs", flow.HexStringToIdentifier)
+	if err != nil {
+		return err
+	}
+	if len(nodeIDs) == 0 {
+		return admin.NewInvalidAdminReqParameterError("node_ids", "must be a non-empty list of node IDs", input["node_ids"])
+	}
+	req.ValidatorData = flow.IdentifierList(nodeIDs)
+	return nil
+}
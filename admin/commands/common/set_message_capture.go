@@ -0,0 +1,174 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/capture"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+var _ commands.AdminCommand = (*SetMessageCaptureCommand)(nil)
+
+type setMessageCaptureRequest struct {
+	enabled bool
+	config  capture.Config
+}
+
+// SetMessageCaptureCommand enables or disables the capture of the messages exchanged by the
+// network, optionally restricted to a set of channels and peers. Without input, it returns the
+// status of the capture.
+type SetMessageCaptureCommand struct {
+	capturer   *capture.Capturer
+	defaultDir string
+}
+
+func NewSetMessageCaptureCommand(capturer *capture.Capturer, defaultDir string) *SetMessageCaptureCommand {
+	return &SetMessageCaptureCommand{
+		capturer:   capturer,
+		defaultDir: defaultDir,
+	}
+}
+
+func (s *SetMessageCaptureCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if s.capturer == nil {
+		return nil, admin.NewInvalidAdminReqErrorf("message capture is not supported by this node")
+	}
+	if data, ok := req.ValidatorData.(*setMessageCaptureRequest); ok {
+		if data.enabled {
+			err := s.capturer.Enable(data.config)
+			if err != nil {
+				return nil, fmt.Errorf("could not enable message capture: %w", err)
+			}
+		} else {
+			s.capturer.Disable()
+		}
+	}
+	return commands.ConvertToMap(s.capturer.Status())
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (s *SetMessageCaptureCommand) Validator(req *admin.CommandRequest) error {
+	if req.Data == nil {
+		return nil
+	}
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	enabled, ok := input["enabled"].(bool)
+	if !ok {
+		return admin.NewInvalidAdminReqParameterError("enabled", "must be a bool", input["enabled"])
+	}
+	data := &setMessageCaptureRequest{
+		enabled: enabled,
+		config:  capture.DefaultConfig(s.defaultDir),
+	}
+	req.ValidatorData = data
+	if !enabled {
+		return nil
+	}
+
+	if dir, ok := input["dir"]; ok {
+		str, ok := dir.(string)
+		if !ok || strings.TrimSpace(str) == "" {
+			return admin.NewInvalidAdminReqParameterError("dir", "must be a directory", dir)
+		}
+		data.config.Dir = strings.TrimSpace(str)
+	}
+	if data.config.Dir == "" {
+		return admin.NewInvalidAdminReqErrorf("the \"dir\" field is required, as no default capture directory is configured")
+	}
+	if includePayload, ok := input["include_payload"]; ok {
+		include, ok := includePayload.(bool)
+		if !ok {
+			return admin.NewInvalidAdminReqParameterError("include_payload", "must be a bool", includePayload)
+		}
+		data.config.IncludePayload = include
+	}
+
+	for field, target := range map[string]*int{
+		"max_files":   &data.config.MaxFiles,
+		"buffer_size": &data.config.BufferSize,
+	} {
+		if value, ok := input[field]; ok {
+			n, err := parsePositiveInt(field, value)
+			if err != nil {
+				return err
+			}
+			*target = n
+		}
+	}
+	if value, ok := input["max_file_bytes"]; ok {
+		n, err := parsePositiveInt("max_file_bytes", value)
+		if err != nil {
+			return err
+		}
+		data.config.MaxFileBytes = int64(n)
+	}
+
+	var err error
+	if values, ok := input["channels"]; ok {
+		data.config.Channels, err = parseStringList("channels", values, "must be a list of channels", func(str string) (channels.Channel, error) {
+			channel := channels.Channel(str)
+			return channel, channels.IsValidFlowChannel(channel)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if values, ok := input["peers"]; ok {
+		data.config.Peers, err = parseStringList("peers", values, "must be a list of peer IDs", peer.Decode)
+		if err != nil {
+			return err
+		}
+	}
+	if values, ok := input["node_ids"]; ok {
+		data.config.NodeIDs, err = parseStringList("node_ids", values, "must be a list of node IDs", flow.HexStringToIdentifier)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parsePositiveInt parses the given field as a positive integer.
+// Returns admin.InvalidAdminReqError if the field is invalid.
+func parsePositiveInt(field string, value interface{}) (int, error) {
+	n, ok := value.(float64)
+	if !ok || n <= 0 || n > math.MaxInt32 || math.Trunc(n) != n {
+		return 0, admin.NewInvalidAdminReqParameterError(field, "must be a positive integer", value)
+	}
+	return int(n), nil
+}
+
+// parseStringList parses the given field as a list of strings, each of which is converted with parse.
+// Returns admin.InvalidAdminReqError if the field is invalid.
+func parseStringList[T any](field string, value interface{}, msg string, parse func(string) (T, error)) ([]T, error) {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, admin.NewInvalidAdminReqParameterError(field, msg, value)
+	}
+	result := make([]T, 0, len(list))
+	for _, item := range list {
+		str, ok := item.(string)
+		if !ok {
+			return nil, admin.NewInvalidAdminReqParameterError(field, msg, value)
+		}
+		parsed, err := parse(strings.TrimSpace(str))
+		if err != nil {
+			return nil, admin.NewInvalidAdminReqParameterError(field, msg, value)
+		}
+		result = append(result, parsed)
+	}
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/network/capture"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestSetMessageCapture tests that the message capture is enabled with the requested filters,
+// disabled, and that the status is returned without input.
+func TestSetMessageCapture(t *testing.T) {
+	dir := t.TempDir()
+	capturer := capture.NewCapturer(zerolog.Nop(), unittest.IdentifierFixture())
+	command := NewSetMessageCaptureCommand(capturer, dir)
+	nodeID := unittest.IdentifierFixture()
+	peerID := unittest.PeerIdFixture(t)
+
+	run := func(data interface{}) map[string]interface{} {
+		req := &admin.CommandRequest{Data: data}
+		require.NoError(t, command.Validator(req))
+		result, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+		return result.(map[string]interface{})
+	}
+
+	status := run(nil)
+	assert.Equal(t, false, status["Enabled"])
+
+	status = run(map[string]interface{}{
+		"enabled":         true,
+		"include_payload": true,
+		"channels":        []interface{}{channels.ConsensusCommittee.String()},
+		"peers":           []interface{}{peerID.String()},
+		"node_ids":        []interface{}{nodeID.String()},
+		"max_files":       float64(2),
+	})
+	assert.Equal(t, true, status["Enabled"])
+	config := capturer.Status().Config
+	require.NotNil(t, config)
+	assert.Equal(t, dir, config.Dir)
+	assert.True(t, config.IncludePayload)
+	assert.Equal(t, []channels.Channel{channels.ConsensusCommittee}, config.Channels)
+	assert.Equal(t, peerID, config.Peers[0])
+	assert.Equal(t, nodeID, config.NodeIDs[0])
+	assert.Equal(t, 2, config.MaxFiles)
+
+	status = run(map[string]interface{}{"enabled": false})
+	assert.Equal(t, false, status["Enabled"])
+	assert.False(t, capturer.Status().Enabled)
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, data := range []interface{}{
+			"enable",
+			map[string]interface{}{},
+			map[string]interface{}{"enabled": "yes"},
+			map[string]interface{}{"enabled": true, "dir": ""},
+			map[string]interface{}{"enabled": true, "channels": []interface{}{"unknown-channel"}},
+			map[string]interface{}{"enabled": true, "peers": []interface{}{"not-a-peer"}},
+			map[string]interface{}{"enabled": true, "node_ids": "abc"},
+			map[string]interface{}{"enabled": true, "max_files": float64(0)},
+			map[string]interface{}{"enabled": true, "include_payload": "true"},
+		} {
+			err := command.Validator(&admin.CommandRequest{Data: data})
+			assert.True(t, admin.IsInvalidAdminParameterError(err), "data: %v", data)
+		}
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		command := NewSetMessageCaptureCommand(nil, dir)
+		req := &admin.CommandRequest{}
+		require.NoError(t, command.Validator(req))
+		_, err := command.Handler(context.Background(), req)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,94 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/model/dkg"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/storage"
+)
+
+var _ commands.AdminCommand = (*DKGStatusCommand)(nil)
+
+type dkgStatusRequest struct {
+	// epoch is nil for the most recent DKG
+	epoch *uint64
+}
+
+type dkgStatusResponse struct {
+	*dkg.Status
+	// DKGState is the state of the beacon key state machine for the epoch, empty if unknown.
+	DKGState string
+}
+
+// DKGStatusCommand reports the progress of the DKG run by this node for an epoch: the phase
+// transitions, the messages received per dealer, the complaints raised and the final result,
+// along with the state of the random beacon key for the epoch.
+type DKGStatusCommand struct {
+	provider module.DKGStatusProvider
+	dkgState storage.DKGStateReader
+}
+
+func NewDKGStatusCommand(provider module.DKGStatusProvider, dkgState storage.DKGStateReader) *DKGStatusCommand {
+	return &DKGStatusCommand{
+		provider: provider,
+		dkgState: dkgState,
+	}
+}
+
+func (d *DKGStatusCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	data := req.ValidatorData.(*dkgStatusRequest)
+
+	var status *dkg.Status
+	var ok bool
+	if data.epoch != nil {
+		status, ok = d.provider.DKGStatus(*data.epoch)
+		if !ok {
+			return nil, admin.NewInvalidAdminReqErrorf("no dkg status for epoch %d", *data.epoch)
+		}
+	} else {
+		status, ok = d.provider.LatestDKGStatus()
+		if !ok {
+			return nil, admin.NewInvalidAdminReqErrorf("no dkg has been run by this node")
+		}
+	}
+
+	response := dkgStatusResponse{Status: status}
+	state, err := d.dkgState.GetDKGState(status.EpochCounter)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("could not get dkg state for epoch %d: %w", status.EpochCounter, err)
+	}
+	if err == nil {
+		response.DKGState = state.String()
+	}
+	return commands.ConvertToMap(response)
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (d *DKGStatusCommand) Validator(req *admin.CommandRequest) error {
+	data := &dkgStatusRequest{}
+	req.ValidatorData = data
+	if req.Data == nil {
+		return nil
+	}
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	if epoch, ok := input["epoch"]; ok {
+		counter, ok := epoch.(float64)
+		if !ok || counter < 0 || math.Trunc(counter) != counter {
+			return admin.NewInvalidAdminReqParameterError("epoch", "must be an epoch counter", epoch)
+		}
+		data.epoch = new(uint64)
+		*data.epoch = uint64(counter)
+	}
+	return nil
+}
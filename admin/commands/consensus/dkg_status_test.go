@@ -0,0 +1,75 @@
+package consensus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/model/dkg"
+	"github.com/onflow/flow-go/model/flow"
+	modulemock "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestDKGStatus tests that the status of the latest or a given epoch's DKG is returned along with
+// the state of the beacon key, and that invalid requests are rejected.
+func TestDKGStatus(t *testing.T) {
+	committee := unittest.IdentityListFixture(3).Sort(flow.Canonical[flow.Identity]).ToSkeleton()
+	latest := dkg.NewStatus(3, "dkg-flow-testnet-3", committee, 0)
+	latest.Phase = "Phase2"
+	previous := dkg.NewStatus(2, "dkg-flow-testnet-2", committee, 0)
+	previous.Result = dkg.ResultSuccess
+
+	provider := modulemock.NewDKGStatusProvider(t)
+	provider.On("LatestDKGStatus").Return(latest, true).Maybe()
+	provider.On("DKGStatus", uint64(2)).Return(previous, true).Maybe()
+	provider.On("DKGStatus", uint64(1)).Return(nil, false).Maybe()
+	dkgState := storagemock.NewDKGStateReader(t)
+	dkgState.On("GetDKGState", uint64(3)).Return(flow.DKGStateUninitialized, storage.ErrNotFound).Maybe()
+	dkgState.On("GetDKGState", uint64(2)).Return(flow.RandomBeaconKeyCommitted, nil).Maybe()
+	command := NewDKGStatusCommand(provider, dkgState)
+
+	run := func(data interface{}) (interface{}, error) {
+		req := &admin.CommandRequest{Data: data}
+		require.NoError(t, command.Validator(req))
+		return command.Handler(context.Background(), req)
+	}
+
+	t.Run("latest", func(t *testing.T) {
+		expected, err := commands.ConvertToMap(dkgStatusResponse{Status: latest})
+		require.NoError(t, err)
+		result, err := run(nil)
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("by epoch", func(t *testing.T) {
+		expected, err := commands.ConvertToMap(dkgStatusResponse{Status: previous, DKGState: flow.RandomBeaconKeyCommitted.String()})
+		require.NoError(t, err)
+		result, err := run(map[string]interface{}{"epoch": float64(2)})
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("unknown epoch", func(t *testing.T) {
+		_, err := run(map[string]interface{}{"epoch": float64(1)})
+		assert.True(t, admin.IsInvalidAdminParameterError(err))
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, data := range []interface{}{
+			"epoch",
+			map[string]interface{}{"epoch": "latest"},
+			map[string]interface{}{"epoch": 1.5},
+			map[string]interface{}{"epoch": -1.0},
+		} {
+			assert.Error(t, command.Validator(&admin.CommandRequest{Data: data}), data)
+		}
+	})
+}
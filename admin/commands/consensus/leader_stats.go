@@ -0,0 +1,102 @@
+package consensus
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+)
+
+var _ commands.AdminCommand = (*LeaderStatsCommand)(nil)
+
+type leaderStatsRequest struct {
+	// epoch is nil for the current epoch
+	epoch  *uint64
+	nodeID *flow.Identifier
+}
+
+type leaderStatsResponse struct {
+	Epoch   uint64
+	Leaders []*model.LeaderStats
+}
+
+// LeaderStatsCommand reports, per leader, how many of the views it led in an epoch had its
+// proposal arrive on time, late or not at all, and how many were concluded by a timeout
+// certificate, as observed by this node.
+type LeaderStatsCommand struct {
+	state    protocol.State
+	provider hotstuff.LeaderStatsProvider
+}
+
+func NewLeaderStatsCommand(state protocol.State, provider hotstuff.LeaderStatsProvider) *LeaderStatsCommand {
+	return &LeaderStatsCommand{
+		state:    state,
+		provider: provider,
+	}
+}
+
+func (l *LeaderStatsCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	data := req.ValidatorData.(*leaderStatsRequest)
+
+	var epoch uint64
+	if data.epoch != nil {
+		epoch = *data.epoch
+	} else {
+		current, err := l.state.Final().Epochs().Current()
+		if err != nil {
+			return nil, fmt.Errorf("could not get current epoch: %w", err)
+		}
+		epoch = current.Counter()
+	}
+
+	response := leaderStatsResponse{Epoch: epoch, Leaders: []*model.LeaderStats{}}
+	for _, stats := range l.provider.LeaderStats(epoch) {
+		if data.nodeID != nil && stats.NodeID != *data.nodeID {
+			continue
+		}
+		response.Leaders = append(response.Leaders, stats)
+	}
+	return commands.ConvertToMap(response)
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (l *LeaderStatsCommand) Validator(req *admin.CommandRequest) error {
+	data := &leaderStatsRequest{}
+	req.ValidatorData = data
+	if req.Data == nil {
+		return nil
+	}
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	if epoch, ok := input["epoch"]; ok {
+		counter, ok := epoch.(float64)
+		if !ok || counter < 0 || math.Trunc(counter) != counter {
+			return admin.NewInvalidAdminReqParameterError("epoch", "must be an epoch counter", epoch)
+		}
+		data.epoch = new(uint64)
+		*data.epoch = uint64(counter)
+	}
+	if nodeID, ok := input["node_id"]; ok {
+		str, ok := nodeID.(string)
+		if !ok {
+			return admin.NewInvalidAdminReqParameterError("node_id", "must be a node ID", nodeID)
+		}
+		id, err := flow.HexStringToIdentifier(strings.TrimSpace(str))
+		if err != nil {
+			return admin.NewInvalidAdminReqParameterError("node_id", "must be a node ID", nodeID)
+		}
+		data.nodeID = &id
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package consensus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/consensus/hotstuff/mocks"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	protocolmock "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestLeaderStats tests that the leader stats of the current or a given epoch are returned,
+// optionally filtered by node, and that invalid requests are rejected.
+func TestLeaderStats(t *testing.T) {
+	stats := []*model.LeaderStats{
+		{Epoch: 3, NodeID: unittest.IdentifierFixture(), Views: 2, ProposalsOnTime: 2, TotalProposalDelay: time.Second, LastView: 30},
+		{Epoch: 3, NodeID: unittest.IdentifierFixture(), Views: 1, ProposalsMissing: 1, TimedOut: 1, LastView: 31},
+	}
+	provider := mocks.NewLeaderStatsProvider(t)
+	provider.On("LeaderStats", uint64(3)).Return(stats)
+	state := protocolmock.NewState(t)
+	command := NewLeaderStatsCommand(state, provider)
+
+	run := func(data interface{}) map[string]interface{} {
+		req := &admin.CommandRequest{Data: data}
+		require.NoError(t, command.Validator(req))
+		result, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+		return result.(map[string]interface{})
+	}
+
+	t.Run("current epoch", func(t *testing.T) {
+		epoch := protocolmock.NewCommittedEpoch(t)
+		epoch.On("Counter").Return(uint64(3))
+		epochs := protocolmock.NewEpochQuery(t)
+		epochs.On("Current").Return(epoch, nil)
+		snapshot := protocolmock.NewSnapshot(t)
+		snapshot.On("Epochs").Return(epochs)
+		state.On("Final").Return(snapshot)
+
+		expected, err := commands.ConvertToMap(leaderStatsResponse{Epoch: 3, Leaders: stats})
+		require.NoError(t, err)
+		assert.Equal(t, expected, run(nil))
+	})
+
+	t.Run("by epoch and node", func(t *testing.T) {
+		expected, err := commands.ConvertToMap(leaderStatsResponse{Epoch: 3, Leaders: stats[1:]})
+		require.NoError(t, err)
+		assert.Equal(t, expected, run(map[string]interface{}{"epoch": float64(3), "node_id": stats[1].NodeID.String()}))
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, data := range []interface{}{
+			"epoch",
+			map[string]interface{}{"epoch": "current"},
+			map[string]interface{}{"epoch": 1.5},
+			map[string]interface{}{"epoch": -1.0},
+			map[string]interface{}{"node_id": "node"},
+			map[string]interface{}{"node_id": 1.0},
+		} {
+			assert.Error(t, command.Validator(&admin.CommandRequest{Data: data}), data)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/consensus"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+)
+
+var _ commands.AdminCommand = (*SealingStatusCommand)(nil)
+
+type sealingStatusRequest struct {
+	blockID *flow.Identifier
+	height  uint64
+}
+
+// SealingStatusCommand explains why a block is not yet sealed: it reports every incorporated
+// result for the block known to the sealing engine, the verifier assignment of each chunk, the
+// received and missing approvals per verifier, emergency sealing eligibility and the approval
+// requests sent for the chunks.
+type SealingStatusCommand struct {
+	state    protocol.State
+	provider consensus.SealingStatusProvider
+}
+
+func NewSealingStatusCommand(state protocol.State, provider consensus.SealingStatusProvider) *SealingStatusCommand {
+	return &SealingStatusCommand{
+		state:    state,
+		provider: provider,
+	}
+}
+
+func (s *SealingStatusCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	data := req.ValidatorData.(*sealingStatusRequest)
+
+	var blockID flow.Identifier
+	if data.blockID != nil {
+		blockID = *data.blockID
+	} else {
+		header, err := s.state.AtHeight(data.height).Head()
+		if err != nil {
+			return nil, admin.NewInvalidAdminReqErrorf("no finalized block at height %d: %v", data.height, err)
+		}
+		blockID = header.ID()
+	}
+
+	status, err := s.provider.SealingStatus(blockID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, admin.NewInvalidAdminReqErrorf("unknown block %v", blockID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get sealing status of block %v: %w", blockID, err)
+	}
+	return commands.ConvertToMap(status)
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (s *SealingStatusCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+	block, ok := input["block"]
+	if !ok {
+		return admin.NewInvalidAdminReqErrorf("the \"block\" field is required")
+	}
+
+	data := &sealingStatusRequest{}
+	switch block := block.(type) {
+	case string:
+		id, err := flow.HexStringToIdentifier(strings.TrimSpace(block))
+		if err != nil {
+			return admin.NewInvalidAdminReqParameterError("block", "must be a block ID or height", block)
+		}
+		data.blockID = &id
+	case float64:
+		if block < 0 || math.Trunc(block) != block {
+			return admin.NewInvalidAdminReqParameterError("block", "must be a block ID or height", block)
+		}
+		data.height = uint64(block)
+	default:
+		return admin.NewInvalidAdminReqParameterError("block", "must be a block ID or height", block)
+	}
+
+	req.ValidatorData = data
+	return nil
+}
@@ -0,0 +1,85 @@
+package consensus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/consensus"
+	consensusmock "github.com/onflow/flow-go/engine/consensus/mock"
+	"github.com/onflow/flow-go/model/flow"
+	protocolmock "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestSealingStatus tests that the sealing status is returned for blocks given by ID or by
+// finalized height, and that invalid requests are rejected.
+func TestSealingStatus(t *testing.T) {
+	block := unittest.BlockHeaderFixture()
+	status := &consensus.SealingStatus{
+		BlockID: block.ID(),
+		Height:  block.Height,
+		Results: []consensus.ResultSealingStatus{{
+			ResultID:         unittest.IdentifierFixture(),
+			ProcessingStatus: "VerifyingApprovals",
+			Incorporations: []consensus.IncorporatedResultSealingStatus{{
+				IncorporatedBlockID: unittest.IdentifierFixture(),
+				Chunks: []consensus.ChunkSealingStatus{{
+					Index:             0,
+					AssignedVerifiers: unittest.IdentifierListFixture(2),
+				}},
+			}},
+		}},
+	}
+	expected, err := commands.ConvertToMap(status)
+	require.NoError(t, err)
+
+	state := protocolmock.NewState(t)
+	provider := consensusmock.NewSealingStatusProvider(t)
+	provider.On("SealingStatus", block.ID()).Return(status, nil)
+	command := NewSealingStatusCommand(state, provider)
+
+	t.Run("by block ID", func(t *testing.T) {
+		req := &admin.CommandRequest{Data: map[string]interface{}{"block": block.ID().String()}}
+		require.NoError(t, command.Validator(req))
+		result, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("by height", func(t *testing.T) {
+		state.On("AtHeight", block.Height).Return(unittest.StateSnapshotForKnownBlock(block, nil))
+		req := &admin.CommandRequest{Data: map[string]interface{}{"block": float64(block.Height)}}
+		require.NoError(t, command.Validator(req))
+		result, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("unknown block", func(t *testing.T) {
+		unknown := unittest.IdentifierFixture()
+		provider.On("SealingStatus", unknown).Return(nil, storage.ErrNotFound)
+		req := &admin.CommandRequest{Data: map[string]interface{}{"block": unknown.String()}}
+		require.NoError(t, command.Validator(req))
+		_, err := command.Handler(context.Background(), req)
+		assert.True(t, admin.IsInvalidAdminParameterError(err))
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, data := range []interface{}{
+			nil,
+			map[string]interface{}{},
+			map[string]interface{}{"block": "final"},
+			map[string]interface{}{"block": 1.5},
+			map[string]interface{}{"block": -1.0},
+			map[string]interface{}{"block": []flow.Identifier{}},
+		} {
+			assert.Error(t, command.Validator(&admin.CommandRequest{Data: data}), data)
+		}
+	})
+}
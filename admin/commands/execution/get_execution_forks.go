@@ -0,0 +1,42 @@
+package execution
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/execution/checker"
+)
+
+var _ commands.AdminCommand = (*GetExecutionForksCommand)(nil)
+
+// GetExecutionForksCommand returns the records of all detected execution
+// forks, i.e. blocks for which the locally computed execution result differs
+// from the sealed result.
+type GetExecutionForksCommand struct {
+	recorder *checker.ForkRecorder
+}
+
+// NewGetExecutionForksCommand creates a new GetExecutionForksCommand object
+func NewGetExecutionForksCommand(recorder *checker.ForkRecorder) *GetExecutionForksCommand {
+	return &GetExecutionForksCommand{
+		recorder: recorder,
+	}
+}
+
+// Handler returns the fork records, ordered by height.
+func (s *GetExecutionForksCommand) Handler(_ context.Context, _ *admin.CommandRequest) (interface{}, error) {
+	forks, err := commands.ConvertToInterfaceList(s.recorder.Records())
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"forks": forks,
+	}, nil
+}
+
+// Validator is a no-op, the command takes no input.
+func (s *GetExecutionForksCommand) Validator(_ *admin.CommandRequest) error {
+	return nil
+}
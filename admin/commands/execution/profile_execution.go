@@ -0,0 +1,94 @@
+package execution
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/execution/computation/profiler"
+)
+
+var _ commands.AdminCommand = (*ProfileExecutionCommand)(nil)
+
+// ProfileExecutionCommand enables or disables per-transaction execution
+// profiling for a range of block heights.
+type ProfileExecutionCommand struct {
+	profiler *profiler.Profiler
+}
+
+// NewProfileExecutionCommand creates a new ProfileExecutionCommand object
+func NewProfileExecutionCommand(p *profiler.Profiler) *ProfileExecutionCommand {
+	return &ProfileExecutionCommand{
+		profiler: p,
+	}
+}
+
+// Handler sets the profiled height range, or disables profiling.
+// Returns the previous and the new height range.
+func (s *ProfileExecutionCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	newRange := req.ValidatorData.(*profiler.HeightRange)
+
+	oldRange := s.profiler.HeightRange()
+	s.profiler.SetHeightRange(newRange)
+
+	log.Info().
+		Interface("newRange", newRange).
+		Interface("oldRange", oldRange).
+		Msgf("admintool: execution profiler height range set")
+
+	return commands.ConvertToMap(map[string]interface{}{
+		"old": oldRange,
+		"new": newRange,
+	})
+}
+
+// Validator checks the inputs for ProfileExecution command.
+// It expects either of the following in the Data field of the req object:
+//   - start-height and end-height in a numeric format, to enable profiling
+//     for all blocks within the inclusive range
+//   - disable, set to true, to disable profiling
+//
+// The following sentinel errors are expected during normal operations:
+// * `admin.InvalidAdminReqError` if any required field is missing or in a wrong format
+func (s *ProfileExecutionCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	if result, ok := input["disable"]; ok {
+		disable, ok := result.(bool)
+		if !ok || !disable {
+			return admin.NewInvalidAdminReqParameterError("disable", "must be true if set", result)
+		}
+		req.ValidatorData = (*profiler.HeightRange)(nil)
+		return nil
+	}
+
+	result, ok := input["start-height"]
+	if !ok {
+		return admin.NewInvalidAdminReqErrorf("missing required field: 'start-height'")
+	}
+	start, ok := result.(float64)
+	if !ok || start <= 0 {
+		return admin.NewInvalidAdminReqParameterError("start-height", "must be number >0", result)
+	}
+
+	result, ok = input["end-height"]
+	if !ok {
+		return admin.NewInvalidAdminReqErrorf("missing required field: 'end-height'")
+	}
+	end, ok := result.(float64)
+	if !ok || end < start {
+		return admin.NewInvalidAdminReqParameterError("end-height", "must be number >= start-height", result)
+	}
+
+	req.ValidatorData = &profiler.HeightRange{
+		Start: uint64(start),
+		End:   uint64(end),
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/engine/execution/computation/profiler"
+)
+
+func TestProfileExecutionCommandParsing(t *testing.T) {
+	cmd := ProfileExecutionCommand{}
+
+	t.Run("happy path", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"start-height": float64(21), // raw json parses to float64
+				"end-height":   float64(25),
+			},
+		}
+
+		err := cmd.Validator(req)
+		require.NoError(t, err)
+		require.Equal(t, &profiler.HeightRange{Start: 21, End: 25}, req.ValidatorData)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"disable": true,
+			},
+		}
+
+		err := cmd.Validator(req)
+		require.NoError(t, err)
+		require.Nil(t, req.ValidatorData)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{},
+		}
+
+		err := cmd.Validator(req)
+		require.True(t, admin.IsInvalidAdminParameterError(err))
+	})
+
+	t.Run("end before start", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"start-height": float64(21),
+				"end-height":   float64(20),
+			},
+		}
+
+		err := cmd.Validator(req)
+		require.True(t, admin.IsInvalidAdminParameterError(err))
+	})
+
+	t.Run("disable false", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"disable": false,
+			},
+		}
+
+		err := cmd.Validator(req)
+		require.True(t, admin.IsInvalidAdminParameterError(err))
+	})
+}
+
+func TestProfileExecutionCommandHandler(t *testing.T) {
+	p, err := profiler.New(zerolog.Nop(), t.TempDir())
+	require.NoError(t, err)
+
+	cmd := NewProfileExecutionCommand(p)
+
+	req := &admin.CommandRequest{
+		Data: map[string]interface{}{
+			"start-height": float64(10),
+			"end-height":   float64(10),
+		},
+	}
+	require.NoError(t, cmd.Validator(req))
+
+	_, err = cmd.Handler(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, &profiler.HeightRange{Start: 10, End: 10}, p.HeightRange())
+
+	req = &admin.CommandRequest{
+		Data: map[string]interface{}{
+			"disable": true,
+		},
+	}
+	require.NoError(t, cmd.Validator(req))
+
+	result, err := cmd.Handler(context.Background(), req)
+	require.NoError(t, err)
+	require.Nil(t, p.HeightRange())
+
+	// the result must be convertible to a protobuf value by the admin server
+	_, err = structpb.NewValue(result)
+	require.NoError(t, err)
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+var _ commands.AdminCommand = (*ReadSlashingEvidenceCommand)(nil)
+
+type readSlashingEvidenceRequest struct {
+	evidenceID *flow.Identifier
+	offender   *flow.Identifier
+	kind       model.SlashingEvidenceKind
+}
+
+// slashingEvidenceEntry is a stored evidence together with its ID and the result of
+// verifying it.
+type slashingEvidenceEntry struct {
+	ID           flow.Identifier
+	Verification string
+	Evidence     *model.SlashingEvidence
+}
+
+// ReadSlashingEvidenceCommand returns the persisted evidence of slashable HotStuff
+// violations, optionally filtered by evidence ID, offender or kind.
+type ReadSlashingEvidenceCommand struct {
+	evidence storage.SlashingEvidence
+}
+
+func NewReadSlashingEvidenceCommand(evidence storage.SlashingEvidence) *ReadSlashingEvidenceCommand {
+	return &ReadSlashingEvidenceCommand{
+		evidence: evidence,
+	}
+}
+
+func (r *ReadSlashingEvidenceCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	data := req.ValidatorData.(*readSlashingEvidenceRequest)
+
+	var all []*model.SlashingEvidence
+	if data.evidenceID != nil {
+		evidence, err := r.evidence.ByID(*data.evidenceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get slashing evidence by ID: %w", err)
+		}
+		all = append(all, evidence)
+	} else {
+		var err error
+		all, err = r.evidence.All()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read slashing evidence: %w", err)
+		}
+	}
+
+	entries := make([]slashingEvidenceEntry, 0, len(all))
+	for _, evidence := range all {
+		if data.offender != nil && evidence.Offender != *data.offender {
+			continue
+		}
+		if data.kind != "" && evidence.Kind != data.kind {
+			continue
+		}
+		entries = append(entries, slashingEvidenceEntry{
+			ID:           evidence.ID(),
+			Verification: verification.SlashingEvidenceStatus(evidence),
+			Evidence:     evidence,
+		})
+	}
+
+	result, err := commands.ConvertToInterfaceList(entries)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"evidence": result,
+	}, nil
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (r *ReadSlashingEvidenceCommand) Validator(req *admin.CommandRequest) error {
+	data := &readSlashingEvidenceRequest{}
+	req.ValidatorData = data
+
+	if req.Data == nil {
+		return nil
+	}
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	parseID := func(field string) (*flow.Identifier, error) {
+		value, ok := input[field]
+		if !ok {
+			return nil, nil
+		}
+		str, ok := value.(string)
+		if !ok {
+			return nil, admin.NewInvalidAdminReqParameterError(field, "must be a hex string", value)
+		}
+		id, err := flow.HexStringToIdentifier(str)
+		if err != nil {
+			return nil, admin.NewInvalidAdminReqParameterError(field, "must be a valid identifier", value)
+		}
+		return &id, nil
+	}
+
+	var err error
+	data.evidenceID, err = parseID("id")
+	if err != nil {
+		return err
+	}
+	data.offender, err = parseID("offender")
+	if err != nil {
+		return err
+	}
+
+	if value, ok := input["kind"]; ok {
+		kind, ok := value.(string)
+		if !ok {
+			return admin.NewInvalidAdminReqParameterError("kind", "must be a string", value)
+		}
+		data.kind = model.SlashingEvidenceKind(kind)
+	}
+
+	return nil
+}
@@ -27,6 +27,7 @@ import (
 
 	txvalidator "github.com/onflow/flow-go/access/validator"
 	"github.com/onflow/flow-go/admin/commands"
+	commonCommands "github.com/onflow/flow-go/admin/commands/common"
 	stateSyncCommands "github.com/onflow/flow-go/admin/commands/state_synchronization"
 	storageCommands "github.com/onflow/flow-go/admin/commands/storage"
 	"github.com/onflow/flow-go/cmd"
@@ -34,6 +35,7 @@ import (
 	"github.com/onflow/flow-go/consensus"
 	"github.com/onflow/flow-go/consensus/hotstuff"
 	"github.com/onflow/flow-go/consensus/hotstuff/committees"
+	"github.com/onflow/flow-go/consensus/hotstuff/cruisectl"
 	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
 	consensuspubsub "github.com/onflow/flow-go/consensus/hotstuff/notifications/pubsub"
 	"github.com/onflow/flow-go/consensus/hotstuff/signature"
@@ -102,6 +104,7 @@ import (
 	"github.com/onflow/flow-go/network/p2p/cache"
 	"github.com/onflow/flow-go/network/p2p/conduit"
 	"github.com/onflow/flow-go/network/p2p/connection"
+	"github.com/onflow/flow-go/network/p2p/connectivity"
 	"github.com/onflow/flow-go/network/p2p/dht"
 	networkingsubscription "github.com/onflow/flow-go/network/p2p/subscription"
 	"github.com/onflow/flow-go/network/p2p/translator"
@@ -183,6 +186,8 @@ type AccessNodeConfig struct {
 	storeTxResultErrorMessages           bool
 	stopControlEnabled                   bool
 	registerDBPruneThreshold             uint64
+	cruiseCtlMinViewDuration             time.Duration // limits of authority of the consensus nodes' block time controller,
+	cruiseCtlMaxViewDuration             time.Duration // used to forecast epoch transitions
 }
 
 type PublicNetworkConfig struct {
@@ -292,9 +297,20 @@ func DefaultAccessNodeConfig() *AccessNodeConfig {
 		storeTxResultErrorMessages:           false,
 		stopControlEnabled:                   false,
 		registerDBPruneThreshold:             0,
+		cruiseCtlMinViewDuration:             cruisectl.DefaultConfig().MinViewDuration.Load(),
+		cruiseCtlMaxViewDuration:             cruisectl.DefaultConfig().MaxViewDuration.Load(),
 	}
 }
 
+// cruiseCtlTimingConfig returns the limits of authority of the consensus nodes' block time controller,
+// which the epoch forecast is based on.
+func (builder *FlowAccessNodeBuilder) cruiseCtlTimingConfig() *cruisectl.TimingConfig {
+	timing := cruisectl.DefaultConfig().TimingConfig
+	timing.MinViewDuration.Store(builder.cruiseCtlMinViewDuration)
+	timing.MaxViewDuration.Store(builder.cruiseCtlMaxViewDuration)
+	return &timing
+}
+
 // FlowAccessNodeBuilder provides the common functionality needed to bootstrap a Flow access node
 // It is composed of the FlowNodeBuilder, the AccessNodeConfig and contains all the components and modules needed for the
 // access nodes
@@ -1405,6 +1421,16 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 			"store-tx-result-error-messages",
 			defaultConfig.storeTxResultErrorMessages,
 			"whether to enable storing transaction error messages into the db")
+
+		// Epoch forecast
+		flags.DurationVar(&builder.cruiseCtlMinViewDuration,
+			"cruise-ctl-min-view-duration",
+			defaultConfig.cruiseCtlMinViewDuration,
+			"the lower bound of authority of the block time controller, as configured on the consensus nodes. used to forecast epoch transitions")
+		flags.DurationVar(&builder.cruiseCtlMaxViewDuration,
+			"cruise-ctl-max-view-duration",
+			defaultConfig.cruiseCtlMaxViewDuration,
+			"the upper bound of authority of the block time controller, as configured on the consensus nodes. used to forecast epoch transitions")
 		// Script Execution
 		flags.StringVar(&builder.rpcConf.BackendConfig.ScriptExecutionMode,
 			"script-execution-mode",
@@ -1485,6 +1511,9 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 			"[experimental] enables WebSockets Stream API that operates under /ws endpoint. this flag may change in a future release.",
 		)
 	}).ValidateFlags(func() error {
+		if builder.cruiseCtlMinViewDuration > builder.cruiseCtlMaxViewDuration {
+			return errors.New("cruise-ctl-min-view-duration must not exceed cruise-ctl-max-view-duration")
+		}
 		if builder.supportsObserver && (builder.PublicNetworkConfig.BindAddress == cmd.NotSet || builder.PublicNetworkConfig.BindAddress == "") {
 			return errors.New("public-network-address must be set if supports-observer is true")
 		}
@@ -1588,6 +1617,7 @@ func (builder *FlowAccessNodeBuilder) InitIDProviders() {
 			return fmt.Errorf("could not initialize NodeBlockListWrapper: %w", err)
 		}
 		builder.IdentityProvider = disallowListWrapper
+		builder.NodeDisallowListWrapper = disallowListWrapper
 
 		// register the wrapper for dynamic configuration via admin command
 		err = node.ConfigManager.RegisterIdentifierListConfig("network-id-provider-blocklist",
@@ -1621,6 +1651,9 @@ func (builder *FlowAccessNodeBuilder) Initialize() error {
 	builder.AdminCommand("get-transactions", func(conf *cmd.NodeConfig) commands.AdminCommand {
 		return storageCommands.NewGetTransactionsCommand(conf.State, conf.Storage.Payloads, conf.Storage.Collections)
 	})
+	builder.AdminCommand("get-epoch-forecast", func(conf *cmd.NodeConfig) commands.AdminCommand {
+		return commonCommands.NewGetEpochForecastCommand(conf.State, builder.cruiseCtlTimingConfig())
+	})
 
 	// if this is an access node that supports public followers, enqueue the public network
 	if builder.supportsObserver {
@@ -2073,6 +2106,7 @@ func (builder *FlowAccessNodeBuilder) Build() (cmd.Node, error) {
 				IndexReporter:              indexReporter,
 				VersionControl:             builder.VersionControl,
 				ExecNodeIdentitiesProvider: builder.ExecNodeIdentitiesProvider,
+				CruiseCtlTiming:            builder.cruiseCtlTimingConfig(),
 			})
 			if err != nil {
 				return nil, fmt.Errorf("could not initialize backend: %w", err)
@@ -2272,6 +2306,7 @@ func (builder *FlowAccessNodeBuilder) enqueuePublicNetworkInit() {
 			if err != nil {
 				return nil, fmt.Errorf("could not create public libp2p node: %w", err)
 			}
+			node.ConnectivitySnapshotters[network.PublicNetwork] = connectivity.NewSnapshotter(node.Me, publicLibp2pNode, node.IDTranslator, node.IdentityProvider)
 
 			return publicLibp2pNode, nil
 		}).
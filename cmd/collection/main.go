@@ -57,6 +57,7 @@ import (
 	"github.com/onflow/flow-go/state/protocol/blocktimer"
 	"github.com/onflow/flow-go/state/protocol/events/gadgets"
 	"github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/store"
 	"github.com/onflow/flow-go/utils/grpcutils"
 )
 
@@ -109,6 +110,8 @@ func main() {
 		txRatelimits       float64
 		txBurstlimits      int
 		txRatelimitPayers  string
+
+		slashingEvidence *store.SlashingEvidence
 	)
 	var deprecatedFlagBlockRateDelay time.Duration
 
@@ -225,6 +228,13 @@ func main() {
 			}
 			return storageCommands.NewReadRangeClusterBlocksCommand(conf.DB, headers, clusterPayloads)
 		}).
+		Module("slashing evidence storage", func(node *cmd.NodeConfig) error {
+			slashingEvidence = store.NewSlashingEvidence(node.ProtocolDB)
+			return nil
+		}).
+		AdminCommand("read-slashing-evidence", func(node *cmd.NodeConfig) commands.AdminCommand {
+			return storageCommands.NewReadSlashingEvidenceCommand(slashingEvidence)
+		}).
 		Module("follower distributor", func(node *cmd.NodeConfig) error {
 			followerDistributor = pubsub.NewFollowerDistributor()
 			followerDistributor.AddProposalViolationConsumer(notifications.NewSlashingViolationsConsumer(node.Logger))
@@ -570,6 +580,7 @@ func main() {
 				node.Me,
 				node.DB,
 				node.State,
+				slashingEvidence,
 				node.Metrics.Engine,
 				node.Metrics.Mempool,
 				createMetrics,
@@ -14,6 +14,10 @@ import (
 	client "github.com/onflow/flow-go-sdk/access/grpc"
 	"github.com/onflow/flow-go-sdk/crypto"
 
+	"github.com/onflow/flow-go/admin/commands"
+	commonCommands "github.com/onflow/flow-go/admin/commands/common"
+	consensusCommands "github.com/onflow/flow-go/admin/commands/consensus"
+	storageCommands "github.com/onflow/flow-go/admin/commands/storage"
 	"github.com/onflow/flow-go/cmd"
 	"github.com/onflow/flow-go/cmd/util/cmd/common"
 	"github.com/onflow/flow-go/consensus"
@@ -21,6 +25,8 @@ import (
 	"github.com/onflow/flow-go/consensus/hotstuff/blockproducer"
 	"github.com/onflow/flow-go/consensus/hotstuff/committees"
 	"github.com/onflow/flow-go/consensus/hotstuff/cruisectl"
+	"github.com/onflow/flow-go/consensus/hotstuff/flightrecorder"
+	"github.com/onflow/flow-go/consensus/hotstuff/leaderstats"
 	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
 	"github.com/onflow/flow-go/consensus/hotstuff/notifications/pubsub"
 	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
@@ -69,6 +75,7 @@ import (
 	protocol_state "github.com/onflow/flow-go/state/protocol/protocol_state/state"
 	bstorage "github.com/onflow/flow-go/storage/badger"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/store"
 	"github.com/onflow/flow-go/utils/io"
 )
 
@@ -99,6 +106,7 @@ func main() {
 		cruiseCtlEnabledFlag                  bool
 		startupTimeString                     string
 		startupTime                           time.Time
+		flightRecorderConfig                  = flightrecorder.DefaultConfig("")
 
 		// DKG contract client
 		machineAccountInfo *bootstrap.NodeMachineAccountInfo
@@ -130,7 +138,12 @@ func main() {
 		epochLookup             *epochs.EpochLookup
 		hotstuffModules         *consensus.HotstuffModules
 		myBeaconKeyStateMachine *bstorage.RecoverablePrivateBeaconKeyStateMachine
+		dkgStatusTracker        *dkgmodule.StatusTracker
 		getSealingConfigs       module.SealingConfigsGetter
+		slashingEvidence        *store.SlashingEvidence
+		flightRecorder          *flightrecorder.Recorder
+		leaderStatsTracker      *leaderstats.Tracker
+		sealingEngine           *sealing.Engine
 	)
 	var deprecatedFlagBlockRateDelay time.Duration
 
@@ -163,6 +176,9 @@ func main() {
 		flags.DurationVar(&dkgMessagingEngineConfig.RetryBaseWait, "dkg-messaging-engine-retry-base-wait", dkgMessagingEngineConfig.RetryBaseWait, "the inter-attempt wait time for the first attempt (base of exponential retry)")
 		flags.Uint64Var(&dkgMessagingEngineConfig.RetryMax, "dkg-messaging-engine-retry-max", dkgMessagingEngineConfig.RetryMax, "the maximum number of retry attempts for an outbound DKG message")
 		flags.Uint64Var(&dkgMessagingEngineConfig.RetryJitterPercent, "dkg-messaging-engine-retry-jitter-percent", dkgMessagingEngineConfig.RetryJitterPercent, "the percentage of jitter to apply to each inter-attempt wait time")
+		flags.StringVar(&flightRecorderConfig.Dir, "hotstuff-flight-recorder-dir", "", "directory to record view-level hotstuff events to, for offline analysis with the util read-flight-recordings command; recording is disabled if empty")
+		flags.Int64Var(&flightRecorderConfig.MaxFileBytes, "hotstuff-flight-recorder-max-file-size", flightRecorderConfig.MaxFileBytes, "size in bytes after which the hotstuff flight recorder starts a new file")
+		flags.IntVar(&flightRecorderConfig.MaxFiles, "hotstuff-flight-recorder-max-files", flightRecorderConfig.MaxFiles, "number of files retained by the hotstuff flight recorder")
 		flags.StringVar(&startupTimeString, "hotstuff-startup-time", cmd.NotSet, "specifies date and time (in ISO 8601 format) after which the consensus participant may enter the first view (e.g 1996-04-24T15:04:05-07:00)")
 		flags.DurationVar(&deprecatedFlagBlockRateDelay, "block-rate-delay", 0, "[deprecated in v0.30; Jun 2023] Use `cruise-ctl-*` flags instead, this flag has no effect and will eventually be removed")
 	}).ValidateFlags(func() error {
@@ -231,6 +247,14 @@ func main() {
 			)
 			return err
 		}).
+		Module("dkg status tracker", func(node *cmd.NodeConfig) error {
+			dkgStatuses, err := bstorage.NewDKGStatuses(node.SecretsDB)
+			if err != nil {
+				return fmt.Errorf("could not initialize dkg status storage: %w", err)
+			}
+			dkgStatusTracker, err = dkgmodule.NewStatusTracker(node.Logger, metrics.NewDKGMetrics(), dkgStatuses)
+			return err
+		}).
 		Module("updatable sealing config", func(node *cmd.NodeConfig) error {
 			setter, err := updatable_configs.NewSealingConfigs(
 				requiredApprovalsForSealConstruction,
@@ -428,6 +452,13 @@ func main() {
 			followerDistributor = pubsub.NewFollowerDistributor()
 			return nil
 		}).
+		Module("slashing evidence storage", func(node *cmd.NodeConfig) error {
+			slashingEvidence = store.NewSlashingEvidence(node.ProtocolDB)
+			return nil
+		}).
+		AdminCommand("read-slashing-evidence", func(node *cmd.NodeConfig) commands.AdminCommand {
+			return storageCommands.NewReadSlashingEvidenceCommand(slashingEvidence)
+		}).
 		Module("sdk client connection options", func(node *cmd.NodeConfig) error {
 			anIDS, err := common.ValidateAccessNodeIDSFlag(accessNodeIDS, node.RootChainID, node.State.Sealed())
 			if err != nil {
@@ -494,8 +525,21 @@ func main() {
 			followerDistributor.AddOnBlockFinalizedConsumer(e.OnFinalizedBlock)
 			followerDistributor.AddOnBlockIncorporatedConsumer(e.OnBlockIncorporated)
 
+			sealingEngine = e
 			return e, err
 		}).
+		AdminCommand("get-sealing-status", func(node *cmd.NodeConfig) commands.AdminCommand {
+			return consensusCommands.NewSealingStatusCommand(node.State, sealingEngine)
+		}).
+		AdminCommand("get-leader-stats", func(node *cmd.NodeConfig) commands.AdminCommand {
+			return consensusCommands.NewLeaderStatsCommand(node.State, leaderStatsTracker)
+		}).
+		AdminCommand("get-epoch-forecast", func(node *cmd.NodeConfig) commands.AdminCommand {
+			return commonCommands.NewGetEpochForecastCommand(node.State, &cruiseCtlConfig.TimingConfig)
+		}).
+		AdminCommand("get-dkg-status", func(node *cmd.NodeConfig) commands.AdminCommand {
+			return consensusCommands.NewDKGStatusCommand(dkgStatusTracker, myBeaconKeyStateMachine)
+		}).
 		Component("matching engine", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			receiptRequester, err = requester.New(
 				node.Logger,
@@ -581,6 +625,30 @@ func main() {
 			node.ProtocolEvents.AddConsumer(epochLookup)
 			return epochLookup, err
 		}).
+		Component("hotstuff flight recorder", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			if flightRecorderConfig.Dir == "" {
+				return &module.NoopReadyDoneAware{}, nil
+			}
+			flightRecorder, err = flightrecorder.NewRecorder(node.Logger, node.Me.NodeID(), flightRecorderConfig)
+			if err != nil {
+				return nil, fmt.Errorf("could not create hotstuff flight recorder: %w", err)
+			}
+			return flightRecorder, nil
+		}).
+		Component("hotstuff leader stats", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			leaderStatsTracker, err = leaderstats.NewTracker(
+				node.Logger,
+				metrics.NewLeaderMetrics(),
+				committee,
+				epochLookup,
+				store.NewLeaderStats(node.ProtocolDB),
+				leaderstats.DefaultConfig(),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("could not create hotstuff leader stats tracker: %w", err)
+			}
+			return leaderStatsTracker, nil
+		}).
 		Component("hotstuff modules", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			// initialize the block finalizer
 			finalize := finalizer.NewFinalizer(
@@ -616,6 +684,15 @@ func main() {
 			telemetryConsumer := notifications.NewTelemetryConsumer(logger)
 			slashingViolationConsumer := notifications.NewSlashingViolationsConsumer(nodeBuilder.Logger)
 			followerDistributor.AddProposalViolationConsumer(slashingViolationConsumer)
+			slashingEvidenceConsumer := notifications.NewSlashingEvidenceConsumer(
+				nodeBuilder.Logger,
+				node.RootChainID,
+				slashingEvidence,
+				wrappedCommittee,
+				epochLookup.EpochForView,
+				node.Storage.Headers,
+			)
+			followerDistributor.AddProposalViolationConsumer(slashingEvidenceConsumer)
 
 			// initialize a logging notifier for hotstuff
 			notifier := createNotifier(
@@ -627,6 +704,10 @@ func main() {
 			notifier.AddCommunicatorConsumer(telemetryConsumer)
 			notifier.AddFinalizationConsumer(telemetryConsumer)
 			notifier.AddFollowerConsumer(followerDistributor)
+			if flightRecorder != nil {
+				notifier.AddConsumer(flightRecorder)
+			}
+			notifier.AddConsumer(leaderStatsTracker)
 
 			// initialize the persister
 			persist, err := persister.New(node.DB, node.RootChainID)
@@ -655,6 +736,10 @@ func main() {
 			voteAggregationDistributor := pubsub.NewVoteAggregationDistributor()
 			voteAggregationDistributor.AddVoteCollectorConsumer(telemetryConsumer)
 			voteAggregationDistributor.AddVoteAggregationViolationConsumer(slashingViolationConsumer)
+			voteAggregationDistributor.AddVoteAggregationViolationConsumer(slashingEvidenceConsumer)
+			if flightRecorder != nil {
+				voteAggregationDistributor.AddVoteCollectorConsumer(flightRecorder)
+			}
 
 			validator := consensus.NewValidator(mainMetrics, wrappedCommittee)
 			voteProcessorFactory := votecollector.NewCombinedVoteProcessorFactory(wrappedCommittee, voteAggregationDistributor.OnQcConstructedFromVotes)
@@ -676,6 +761,10 @@ func main() {
 			timeoutAggregationDistributor := pubsub.NewTimeoutAggregationDistributor()
 			timeoutAggregationDistributor.AddTimeoutCollectorConsumer(telemetryConsumer)
 			timeoutAggregationDistributor.AddTimeoutAggregationViolationConsumer(slashingViolationConsumer)
+			timeoutAggregationDistributor.AddTimeoutAggregationViolationConsumer(slashingEvidenceConsumer)
+			if flightRecorder != nil {
+				timeoutAggregationDistributor.AddTimeoutCollectorConsumer(flightRecorder)
+			}
 
 			timeoutProcessorFactory := timeoutcollector.NewTimeoutProcessorFactory(
 				logger,
@@ -949,6 +1038,7 @@ func main() {
 					node.Me,
 					dkgContractClients,
 					dkgBrokerTunnel,
+					dkgmodule.WithStatusTracker(dkgStatusTracker),
 				),
 				viewsObserver,
 			)
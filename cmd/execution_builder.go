@@ -50,6 +50,7 @@ import (
 	"github.com/onflow/flow-go/engine/execution/computation"
 	"github.com/onflow/flow-go/engine/execution/computation/committer"
 	txmetrics "github.com/onflow/flow-go/engine/execution/computation/metrics"
+	exeprofiler "github.com/onflow/flow-go/engine/execution/computation/profiler"
 	"github.com/onflow/flow-go/engine/execution/ingestion"
 	"github.com/onflow/flow-go/engine/execution/ingestion/fetcher"
 	"github.com/onflow/flow-go/engine/execution/ingestion/stop"
@@ -157,6 +158,7 @@ type ExecutionNode struct {
 	collections            storageerr.Collections
 	providerEngine         exeprovider.ProviderEngine
 	checkerEng             *checker.Engine
+	forkRecorder           *checker.ForkRecorder
 	syncCore               *chainsync.Core
 	syncEngine             *synchronization.Engine
 	followerCore           *hotstuff.FollowerLoop        // follower hotstuff logic
@@ -178,6 +180,7 @@ type ExecutionNode struct {
 	blobService            network.BlobService
 	blobserviceDependable  *module.ProxiedReadyDoneAware
 	metricsProvider        txmetrics.TransactionExecutionMetricsProvider
+	executionProfiler      *exeprofiler.Profiler // records per-transaction execution profiles for heights set by admin tool
 }
 
 func (builder *ExecutionNodeBuilder) LoadComponentsAndModules() {
@@ -199,6 +202,12 @@ func (builder *ExecutionNodeBuilder) LoadComponentsAndModules() {
 		AdminCommand("stop-at-height", func(config *NodeConfig) commands.AdminCommand {
 			return executionCommands.NewStopAtHeightCommand(exeNode.stopControl)
 		}).
+		AdminCommand("profile-execution", func(config *NodeConfig) commands.AdminCommand {
+			return executionCommands.NewProfileExecutionCommand(exeNode.executionProfiler)
+		}).
+		AdminCommand("get-execution-forks", func(config *NodeConfig) commands.AdminCommand {
+			return executionCommands.NewGetExecutionForksCommand(exeNode.forkRecorder)
+		}).
 		AdminCommand("set-uploader-enabled", func(config *NodeConfig) commands.AdminCommand {
 			return uploaderCommands.NewToggleUploaderCommand(exeNode.blockDataUploader)
 		}).
@@ -254,7 +263,9 @@ func (builder *ExecutionNodeBuilder) LoadComponentsAndModules() {
 		Component("block data upload manager", exeNode.LoadBlockUploaderManager).
 		Component("GCP block data uploader", exeNode.LoadGCPBlockDataUploader).
 		Component("S3 block data uploader", exeNode.LoadS3BlockDataUploader).
+		Component("local block data sinks", exeNode.LoadBlockDataSinks).
 		Component("transaction execution metrics", exeNode.LoadTransactionExecutionMetrics).
+		Component("execution profiler", exeNode.LoadExecutionProfiler).
 		Component("provider engine", exeNode.LoadProviderEngine).
 		Component("checker engine", exeNode.LoadCheckerEngine).
 		Component("ingestion engine", exeNode.LoadIngestionEngine).
@@ -558,6 +569,59 @@ func (exeNode *ExecutionNode) LoadS3BlockDataUploader(
 	return asyncUploader, nil
 }
 
+func (exeNode *ExecutionNode) LoadBlockDataSinks(
+	node *NodeConfig,
+) (
+	module.ReadyDoneAware,
+	error,
+) {
+	if !exeNode.exeConf.enableBlockDataUpload || len(exeNode.exeConf.blockDataSinks) == 0 {
+		return &module.NoopReadyDoneAware{}, nil
+	}
+	logger := node.Logger.With().Str("component_name", "block_data_sinks").Logger()
+
+	outputTypes, specs, err := uploader.ParseSinkSpecs(exeNode.exeConf.blockDataSinks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block data sinks: %w", err)
+	}
+
+	// output types configured with the same sink share a single instance, so an
+	// append-only log is only ever written by one writer
+	sinks := make(map[uploader.SinkSpec]uploader.Sink)
+	sinkUploader := uploader.NewSinkUploader()
+	exeNode.builder.ShutdownFunc(sinkUploader.Close)
+
+	for _, outputType := range outputTypes {
+		spec := specs[outputType]
+		sink, ok := sinks[spec]
+		if !ok {
+			sink, err = uploader.NewSink(spec)
+			if err != nil {
+				return nil, fmt.Errorf("cannot create %s sink in %s: %w", spec.Kind, spec.Dir, err)
+			}
+			sinks[spec] = sink
+		}
+		sinkUploader.AddSink(outputType, sink)
+
+		logger.Info().
+			Str("output_type", string(outputType)).
+			Str("sink", string(spec.Kind)).
+			Str("dir", spec.Dir).
+			Msg("block data sink configured")
+	}
+
+	asyncUploader := uploader.NewAsyncUploader(
+		sinkUploader,
+		blockdataUploaderRetryTimeout,
+		blockDataUploaderMaxRetry,
+		logger,
+		exeNode.collector,
+	)
+	exeNode.blockDataUploader.AddUploader(asyncUploader)
+
+	return asyncUploader, nil
+}
+
 func (exeNode *ExecutionNode) LoadProviderEngine(
 	node *NodeConfig,
 ) (
@@ -612,6 +676,9 @@ func (exeNode *ExecutionNode) LoadProviderEngine(
 			})
 	}
 
+	computationConfig := exeNode.exeConf.computationConfig
+	computationConfig.Profiler = exeNode.executionProfiler
+
 	ledgerViewCommitter := committer.NewLedgerViewCommitter(exeNode.ledgerStorage, node.Tracer)
 	manager, err := computation.New(
 		node.Logger,
@@ -622,7 +689,7 @@ func (exeNode *ExecutionNode) LoadProviderEngine(
 		vmCtx,
 		ledgerViewCommitter,
 		executionDataProvider,
-		exeNode.exeConf.computationConfig,
+		computationConfig,
 	)
 	if err != nil {
 		return nil, err
@@ -787,8 +854,13 @@ func (exeNode *ExecutionNode) LoadExecutionState(
 		}
 		return nil
 	})
+	chunkDataPackCompression, err := storageerr.ParseChunkDataPackCompression(exeNode.exeConf.chunkDataPackCompression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chunk data pack compression: %w", err)
+	}
 	chunkDataPacks := store.NewChunkDataPacks(node.Metrics.Cache,
-		pebbleimpl.ToDB(chunkDataPackDB), exeNode.collections, exeNode.exeConf.chunkDataPackCacheSize)
+		pebbleimpl.ToDB(chunkDataPackDB), exeNode.collections, exeNode.exeConf.chunkDataPackCacheSize,
+		store.WithChunkDataPackCompression(chunkDataPackCompression))
 
 	getLatestFinalized := func() (uint64, error) {
 		final, err := node.State.Final().Head()
@@ -1071,6 +1143,13 @@ func (exeNode *ExecutionNode) LoadCheckerEngine(
 	module.ReadyDoneAware,
 	error,
 ) {
+	// forks recorded previously can be inspected even if the checker is disabled
+	forkRecorder, err := checker.NewForkRecorder(exeNode.exeConf.checkerForkDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not create fork recorder: %w", err)
+	}
+	exeNode.forkRecorder = forkRecorder
+
 	if !exeNode.exeConf.enableChecker {
 		node.Logger.Warn().Msgf("checker engine is disabled")
 		return &module.NoopReadyDoneAware{}, nil
@@ -1078,10 +1157,20 @@ func (exeNode *ExecutionNode) LoadCheckerEngine(
 
 	node.Logger.Info().Msgf("checker engine is enabled")
 
+	var halter checker.ExecutionHalter
+	if exeNode.exeConf.checkerHaltOnFork {
+		halter = exeNode.stopControl
+	}
+
 	core := checker.NewCore(
 		node.Logger,
 		node.State,
 		exeNode.executionState,
+		node.Storage.Seals,
+		exeNode.results,
+		forkRecorder,
+		exeNode.collector,
+		halter,
 	)
 	exeNode.checkerEng = checker.NewEngine(core)
 	return exeNode.checkerEng, nil
@@ -1194,6 +1283,18 @@ func (exeNode *ExecutionNode) LoadTransactionExecutionMetrics(
 	return metricsProvider, nil
 }
 
+func (exeNode *ExecutionNode) LoadExecutionProfiler(
+	node *NodeConfig,
+) (module.ReadyDoneAware, error) {
+	executionProfiler, err := exeprofiler.New(node.Logger, exeNode.exeConf.executionProfileDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not create execution profiler: %w", err)
+	}
+
+	exeNode.executionProfiler = executionProfiler
+	return executionProfiler, nil
+}
+
 func (exeNode *ExecutionNode) LoadConsensusCommittee(
 	node *NodeConfig,
 ) (
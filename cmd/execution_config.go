@@ -19,6 +19,7 @@ import (
 
 	"github.com/onflow/flow-go/engine/execution/computation"
 	"github.com/onflow/flow-go/engine/execution/ingestion/stop"
+	"github.com/onflow/flow-go/engine/execution/ingestion/uploader"
 	"github.com/onflow/flow-go/engine/execution/rpc"
 	"github.com/onflow/flow-go/fvm/storage/derived"
 	storage "github.com/onflow/flow-go/storage/badger"
@@ -37,6 +38,7 @@ type ExecutionConfig struct {
 	chunkDataPackDir                      string
 	chunkDataPackCheckpointsDir           string
 	chunkDataPackCacheSize                uint
+	chunkDataPackCompression              string
 	chunkDataPackRequestsCacheSize        uint32
 	requestInterval                       time.Duration
 	extensiveLog                          bool
@@ -46,6 +48,7 @@ type ExecutionConfig struct {
 	enableBlockDataUpload                 bool
 	gcpBucketName                         string
 	s3BucketName                          string
+	blockDataSinks                        map[string]string
 	apiRatelimits                         map[string]int
 	apiBurstlimits                        map[string]int
 	executionDataAllowedPeers             string
@@ -58,6 +61,7 @@ type ExecutionConfig struct {
 	importCheckpointWorkerCount           int
 	transactionExecutionMetricsEnabled    bool
 	transactionExecutionMetricsBufferSize uint
+	executionProfileDir                   string
 
 	computationConfig        computation.ComputationConfig
 	receiptRequestWorkers    uint   // common provider engine workers
@@ -67,10 +71,12 @@ type ExecutionConfig struct {
 	// It works around an issue where some collection nodes are not configured with enough
 	// this works around an issue where some collection nodes are not configured with enough
 	// file descriptors causing connection failures.
-	onflowOnlyLNs    bool
-	enableStorehouse bool
-	enableChecker    bool
-	publicAccessID   string
+	onflowOnlyLNs     bool
+	enableStorehouse  bool
+	enableChecker     bool
+	checkerForkDir    string
+	checkerHaltOnFork bool
+	publicAccessID    string
 
 	pruningConfigThreshold           uint64
 	pruningConfigBatchSize           uint
@@ -95,9 +101,11 @@ func (exeConf *ExecutionConfig) SetupFlags(flags *pflag.FlagSet) {
 	flags.BoolVar(&exeConf.computationConfig.ExtensiveTracing, "extensive-tracing", false, "adds high-overhead tracing to execution")
 	flags.BoolVar(&exeConf.computationConfig.CadenceTracing, "cadence-tracing", false, "enables cadence runtime level tracing")
 	flags.IntVar(&exeConf.computationConfig.MaxConcurrency, "computer-max-concurrency", 1, "set to greater than 1 to enable concurrent transaction execution")
+	flags.BoolVar(&exeConf.computationConfig.ConflictAwareScheduling, "computer-conflict-aware-scheduling", false, "serialize transactions predicted to conflict based on recent blocks (only effective with computer-max-concurrency greater than 1)")
 	flags.StringVar(&exeConf.chunkDataPackDir, "chunk-data-pack-dir", filepath.Join(datadir, "chunk_data_packs"), "directory to use for storing chunk data packs")
 	flags.StringVar(&exeConf.chunkDataPackCheckpointsDir, "chunk-data-pack-checkpoints-dir", filepath.Join(datadir, "chunk_data_packs_checkpoints_dir"), "directory to use storing chunk data packs pebble database checkpoints for querying while the node is running")
 	flags.UintVar(&exeConf.chunkDataPackCacheSize, "chdp-cache", storage.DefaultCacheSize, "cache size for chunk data packs")
+	flags.StringVar(&exeConf.chunkDataPackCompression, "chunk-data-pack-compression", "none", "compression applied to the proofs of newly stored chunk data packs, one of none, lz4 or gzip. the chunk data pack database must be decompressed with the compress-chunk-data-packs util before downgrading to a version without compression support")
 	flags.Uint32Var(&exeConf.chunkDataPackRequestsCacheSize, "chdp-request-queue", mempool.DefaultChunkDataPackRequestQueueSize, "queue size for chunk data pack requests")
 	flags.DurationVar(&exeConf.requestInterval, "request-interval", 60*time.Second, "the interval between requests for the requester engine")
 	flags.Uint32Var(&exeConf.receiptRequestsCacheSize, "receipt-request-cache", provider.DefaultEntityRequestCacheSize, "queue size for entity requests at common provider engine")
@@ -118,6 +126,8 @@ func (exeConf *ExecutionConfig) SetupFlags(flags *pflag.FlagSet) {
 	flags.BoolVar(&exeConf.enableBlockDataUpload, "enable-blockdata-upload", false, "enable uploading block data to Cloud Bucket")
 	flags.StringVar(&exeConf.gcpBucketName, "gcp-bucket-name", "", "GCP Bucket name for block data uploader")
 	flags.StringVar(&exeConf.s3BucketName, "s3-bucket-name", "", "S3 Bucket name for block data uploader")
+	flags.StringToStringVar(&exeConf.blockDataSinks, "blockdata-sinks", map[string]string{}, "local sinks for uploaded block data per output type, where a sink is either object:<dir> for a content-addressed object store or log:<dir> for an append-only log, "+
+		"e.g. events=log:/data/events,trie_updates=object:/data/objects. output types are block_data, events, trie_updates and transaction_results")
 	flags.StringVar(&exeConf.executionDataAllowedPeers, "execution-data-allowed-requesters", "", "comma separated list of Access node IDs that are allowed to request Execution Data. an empty list allows all peers")
 	flags.Uint64Var(&exeConf.executionDataPrunerHeightRangeTarget, "execution-data-height-range-target", 0, "target height range size used to limit the amount of Execution Data kept on disk")
 	flags.Uint64Var(&exeConf.executionDataPrunerThreshold, "execution-data-height-range-threshold", 100_000, "height threshold used to trigger Execution Data pruning")
@@ -128,11 +138,14 @@ func (exeConf *ExecutionConfig) SetupFlags(flags *pflag.FlagSet) {
 	flags.DurationVar(&exeConf.maxGracefulStopDuration, "max-graceful-stop-duration", stop.DefaultMaxGracefulStopDuration, "the maximum amount of time stop control will wait for ingestion engine to gracefully shutdown before crashing")
 	flags.IntVar(&exeConf.importCheckpointWorkerCount, "import-checkpoint-worker-count", 10, "number of workers to import checkpoint file during bootstrap")
 	flags.BoolVar(&exeConf.transactionExecutionMetricsEnabled, "tx-execution-metrics", true, "enable collection of transaction execution metrics")
+	flags.StringVar(&exeConf.executionProfileDir, "execution-profile-dir", filepath.Join(datadir, "execution_profiles"), "directory to write per-transaction execution profiles to, when enabled for a height range via the profile-execution admin command")
 	flags.UintVar(&exeConf.transactionExecutionMetricsBufferSize, "tx-execution-metrics-buffer-size", 200, "buffer size for transaction execution metrics. The buffer size is the number of blocks that are kept in memory by the metrics provider engine")
 
 	flags.BoolVar(&exeConf.onflowOnlyLNs, "temp-onflow-only-lns", false, "do not use unless required. forces node to only request collections from onflow collection nodes")
 	flags.BoolVar(&exeConf.enableStorehouse, "enable-storehouse", false, "enable storehouse to store registers on disk, default is false")
 	flags.BoolVar(&exeConf.enableChecker, "enable-checker", true, "enable checker to check the correctness of the execution result, default is true")
+	flags.StringVar(&exeConf.checkerForkDir, "checker-fork-dir", filepath.Join(datadir, "execution_forks"), "directory to persist records of execution results which differ from the sealed results")
+	flags.BoolVar(&exeConf.checkerHaltOnFork, "checker-halt-on-fork", false, "halt block execution instead of crashing when the checker detects an execution result which differs from the sealed result")
 	// deprecated. Retain it to prevent nodes that previously had this configuration from crashing.
 	var deprecatedEnableNewIngestionEngine bool
 	flags.BoolVar(&deprecatedEnableNewIngestionEngine, "enable-new-ingestion-engine", true, "enable new ingestion engine, default is true")
@@ -146,10 +159,13 @@ func (exeConf *ExecutionConfig) SetupFlags(flags *pflag.FlagSet) {
 
 func (exeConf *ExecutionConfig) ValidateFlags() error {
 	if exeConf.enableBlockDataUpload {
-		if exeConf.gcpBucketName == "" && exeConf.s3BucketName == "" {
-			return fmt.Errorf("invalid flag. gcp-bucket-name or s3-bucket-name required when blockdata-uploader is enabled")
+		if exeConf.gcpBucketName == "" && exeConf.s3BucketName == "" && len(exeConf.blockDataSinks) == 0 {
+			return fmt.Errorf("invalid flag. gcp-bucket-name, s3-bucket-name or blockdata-sinks required when blockdata-uploader is enabled")
 		}
 	}
+	if _, _, err := uploader.ParseSinkSpecs(exeConf.blockDataSinks); err != nil {
+		return fmt.Errorf("invalid flag. blockdata-sinks: %w", err)
+	}
 	if exeConf.executionDataAllowedPeers != "" {
 		ids := strings.Split(exeConf.executionDataAllowedPeers, ",")
 		for _, id := range ids {
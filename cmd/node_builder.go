@@ -23,8 +23,17 @@ import (
 	"github.com/onflow/flow-go/module/profiler"
 	"github.com/onflow/flow-go/module/updatable_configs"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
+	"github.com/onflow/flow-go/network/bandwidth"
+	"github.com/onflow/flow-go/network/capture"
 	"github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/disallowlist"
+	"github.com/onflow/flow-go/network/faults"
 	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/cache"
+	"github.com/onflow/flow-go/network/p2p/connectivity"
+	"github.com/onflow/flow-go/network/p2p/scoring"
+	"github.com/onflow/flow-go/network/queue"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/state/protocol/events"
 	"github.com/onflow/flow-go/storage"
@@ -156,6 +165,9 @@ type BaseConfig struct {
 	datadir                     string
 	pebbleDir                   string
 	pebbleCheckpointsDir        string
+	netCaptureDir               string
+	peerScoreExplorerPage       bool
+	networkFaultInjection       bool
 	dbops                       string
 	badgerDB                    *badger.DB
 	pebbleDB                    *pebble.DB
@@ -241,6 +253,37 @@ type NodeConfig struct {
 
 	// UnicastRateLimiterDistributor notifies consumers when a peer's unicast message is rate limited.
 	UnicastRateLimiterDistributor p2p.UnicastRateLimiterDistributor
+
+	// MessageCapturer records the messages exchanged by the network while enabled via admin command.
+	MessageCapturer *capture.Capturer
+
+	// AlspSpamRecords allows inspecting and resetting the penalties of the application layer spam prevention (ALSP) module.
+	AlspSpamRecords alsp.SpamRecordAdmin
+
+	// OperatorDisallowList is the disallow list of nodes and peers managed by the operator via admin commands.
+	OperatorDisallowList *disallowlist.OperatorDisallowList
+
+	// NodeDisallowListWrapper is the identity provider wrapper ejecting the nodes of the disallow list configured
+	// via the network-id-provider-blocklist config; nil if the node does not use it.
+	NodeDisallowListWrapper *cache.NodeDisallowListingWrapper
+
+	// PeerScoreExplorer explains the GossipSub scores of the peers of the node; nil if the node has no private
+	// libp2p node.
+	PeerScoreExplorer *scoring.ScoreExplorer
+
+	// NetworkFaultInjector injects faults into the messages exchanged by the engines of the node; nil unless
+	// network fault injection is enabled.
+	NetworkFaultInjector *faults.Injector
+
+	// BandwidthAccountant accounts the bandwidth of the messages exchanged by the network per channel, direction
+	// and role of the remote node, and enforces the configured per-channel bandwidth quotas.
+	BandwidthAccountant *bandwidth.Accountant
+
+	// InboundQueuePolicy is the fair queuing policy of the inbound message queue of the network.
+	InboundQueuePolicy *queue.Policy
+
+	// ConnectivitySnapshotters take connectivity snapshots of the libp2p nodes of the node, by network.
+	ConnectivitySnapshotters map[network.NetworkingType]*connectivity.Snapshotter
 }
 
 // StateExcerptAtBoot stores information about the root snapshot and latest finalized block for use in bootstrapping.
@@ -280,6 +323,7 @@ func DefaultBaseConfig() *BaseConfig {
 		BootstrapDir:     "bootstrap",
 		datadir:          datadir,
 		pebbleDir:        pebbleDir,
+		netCaptureDir:    "/data/network_capture",
 		dbops:            string(dbops.BadgerTransaction), // "badger-transaction" (default) or "batch-update"
 		badgerDB:         nil,
 		pebbleDB:         nil,
@@ -94,6 +94,7 @@ import (
 	"github.com/onflow/flow-go/network/p2p/blob"
 	"github.com/onflow/flow-go/network/p2p/cache"
 	"github.com/onflow/flow-go/network/p2p/conduit"
+	"github.com/onflow/flow-go/network/p2p/connectivity"
 	"github.com/onflow/flow-go/network/p2p/keyutils"
 	p2plogging "github.com/onflow/flow-go/network/p2p/logging"
 	"github.com/onflow/flow-go/network/p2p/translator"
@@ -1638,6 +1639,7 @@ func (builder *ObserverServiceBuilder) enqueuePublicNetworkInit() {
 			}
 
 			builder.LibP2PNode = publicLibp2pNode
+			node.ConnectivitySnapshotters[network.PublicNetwork] = connectivity.NewSnapshotter(node.Me, publicLibp2pNode, node.IDTranslator, node.IdentityProvider)
 
 			return publicLibp2pNode, nil
 		}).
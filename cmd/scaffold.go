@@ -54,25 +54,32 @@ import (
 	"github.com/onflow/flow-go/module/util"
 	"github.com/onflow/flow-go/network"
 	alspmgr "github.com/onflow/flow-go/network/alsp/manager"
+	"github.com/onflow/flow-go/network/bandwidth"
 	netcache "github.com/onflow/flow-go/network/cache"
+	"github.com/onflow/flow-go/network/capture"
 	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/network/converter"
+	"github.com/onflow/flow-go/network/disallowlist"
+	"github.com/onflow/flow-go/network/faults"
 	"github.com/onflow/flow-go/network/p2p"
 	p2pbuilder "github.com/onflow/flow-go/network/p2p/builder"
 	p2pbuilderconfig "github.com/onflow/flow-go/network/p2p/builder/config"
 	"github.com/onflow/flow-go/network/p2p/cache"
 	"github.com/onflow/flow-go/network/p2p/conduit"
 	"github.com/onflow/flow-go/network/p2p/connection"
+	"github.com/onflow/flow-go/network/p2p/connectivity"
 	p2pdht "github.com/onflow/flow-go/network/p2p/dht"
 	"github.com/onflow/flow-go/network/p2p/dns"
 	"github.com/onflow/flow-go/network/p2p/keyutils"
 	"github.com/onflow/flow-go/network/p2p/ping"
+	"github.com/onflow/flow-go/network/p2p/scoring"
 	"github.com/onflow/flow-go/network/p2p/subscription"
 	"github.com/onflow/flow-go/network/p2p/translator"
 	"github.com/onflow/flow-go/network/p2p/unicast/protocols"
 	"github.com/onflow/flow-go/network/p2p/unicast/ratelimit"
 	"github.com/onflow/flow-go/network/p2p/utils"
 	"github.com/onflow/flow-go/network/p2p/utils/ratelimiter"
+	"github.com/onflow/flow-go/network/queue"
 	"github.com/onflow/flow-go/network/slashing"
 	"github.com/onflow/flow-go/network/topology"
 	"github.com/onflow/flow-go/network/underlay"
@@ -95,16 +102,20 @@ const (
 	NetworkComponent        = "network"
 	ConduitFactoryComponent = "conduit-factory"
 	LibP2PNodeComponent     = "libp2p-node"
+
+	// peerScoreExplorerPath is the path of the GossipSub peer score explorer page on the admin server.
+	peerScoreExplorerPath = "/debug/gossipsub/scores"
 )
 
 type Metrics struct {
-	Network        module.NetworkMetrics
-	Engine         module.EngineMetrics
-	Compliance     module.ComplianceMetrics
-	Cache          module.CacheMetrics
-	Mempool        module.MempoolMetrics
-	CleanCollector module.CleanerMetrics
-	Bitswap        module.BitswapMetrics
+	Network          module.NetworkMetrics
+	Engine           module.EngineMetrics
+	Compliance       module.ComplianceMetrics
+	Cache            module.CacheMetrics
+	Mempool          module.MempoolMetrics
+	CleanCollector   module.CleanerMetrics
+	Bitswap          module.BitswapMetrics
+	ChannelBandwidth module.ChannelBandwidthMetrics
 }
 
 type Storage = storage.All
@@ -172,6 +183,9 @@ func (fnb *FlowNodeBuilder) BaseFlags() {
 	fnb.flags.StringVarP(&fnb.BaseConfig.BootstrapDir, "bootstrapdir", "b", defaultConfig.BootstrapDir, "path to the bootstrap directory")
 	fnb.flags.StringVarP(&fnb.BaseConfig.datadir, "datadir", "d", defaultConfig.datadir, "directory to store the public database (protocol state)")
 	fnb.flags.StringVar(&fnb.BaseConfig.pebbleCheckpointsDir, "pebble-checkpoints-dir", defaultConfig.pebbleCheckpointsDir, "directory to store the checkpoints for the public pebble database (protocol state)")
+	fnb.flags.BoolVar(&fnb.BaseConfig.peerScoreExplorerPage, "peer-score-explorer-page", defaultConfig.peerScoreExplorerPage, fmt.Sprintf("whether to serve the GossipSub peer score explorer page on the admin server at %s", peerScoreExplorerPath))
+	fnb.flags.BoolVar(&fnb.BaseConfig.networkFaultInjection, "network-fault-injection", defaultConfig.networkFaultInjection, "whether to enable the injection of network faults (delays, drops, duplicates, partitions) via admin commands, for testing only")
+	fnb.flags.StringVar(&fnb.BaseConfig.netCaptureDir, "network-capture-dir", defaultConfig.netCaptureDir, "default directory to write network message captures to, when enabled with the set-message-capture admin command")
 	fnb.flags.StringVar(&fnb.BaseConfig.pebbleDir, "pebble-dir", defaultConfig.pebbleDir, "directory to store the public pebble database (protocol state)")
 	fnb.flags.StringVar(&fnb.BaseConfig.secretsdir, "secretsdir", defaultConfig.secretsdir, "directory to store private database (secrets)")
 	fnb.flags.StringVar(&fnb.BaseConfig.dbops, "dbops", defaultConfig.dbops, "database operations to use (badger-transaction, batch-update, pebble-update)")
@@ -485,6 +499,18 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit() {
 		}
 
 		fnb.LibP2PNode = libp2pNode
+
+		node.PeerScoreExplorer, err = scoring.NewScoreExplorer(&scoring.ScoreExplorerConfig{
+			ConnectedPeers: func() peer.IDSlice {
+				return libp2pNode.Host().Network().Peers()
+			},
+			PeerScore:  libp2pNode,
+			IdProvider: fnb.IdentityProvider,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not create peer score explorer: %w", err)
+		}
+		node.ConnectivitySnapshotters[network.PrivateNetwork] = connectivity.NewSnapshotter(node.Me, libp2pNode, node.IDTranslator, node.IdentityProvider)
 		return libp2pNode, nil
 	})
 	fnb.Component(NetworkComponent, func(node *NodeConfig) (module.ReadyDoneAware, error) {
@@ -495,6 +521,18 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit() {
 			unicastRateLimiters,
 			peerManagerFilters)
 	})
+	fnb.Component("operator disallow list", func(node *NodeConfig) (module.ReadyDoneAware, error) {
+		operatorDisallowList, err := disallowlist.NewOperatorDisallowList(
+			node.Logger,
+			store.NewDisallowListEntries(node.ProtocolDB),
+			node.IDTranslator,
+			node.LibP2PNode)
+		if err != nil {
+			return nil, fmt.Errorf("could not create operator disallow list: %w", err)
+		}
+		node.OperatorDisallowList = operatorDisallowList
+		return operatorDisallowList, nil
+	})
 
 	fnb.Module("epoch transition logger", func(node *NodeConfig) error {
 		node.ProtocolEvents.AddConsumer(events.NewEventLogger(node.Logger))
@@ -606,6 +644,58 @@ func (fnb *FlowNodeBuilder) BuildPublicLibp2pNode(address string, bootstrapIdent
 	return node, nil
 }
 
+// registerInboundQueuePolicyConfigs registers the fair queuing policy of the inbound message queue for dynamic
+// configuring, so that the cap on the messages per sender and the weights of the senders can be tuned at runtime.
+func (fnb *FlowNodeBuilder) registerInboundQueuePolicyConfigs(policy *queue.Policy) error {
+	err := fnb.ConfigManager.RegisterUintConfig(
+		"network-inbound-queue-max-messages-per-sender",
+		policy.MaxMessagesPerSender,
+		policy.SetMaxMessagesPerSender,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = fnb.ConfigManager.RegisterBoolConfig(
+		"network-inbound-queue-stake-weighted",
+		policy.StakeWeighted,
+		policy.SetStakeWeighted,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range flow.Roles() {
+		role := role
+		err = fnb.ConfigManager.RegisterUintConfig(
+			fmt.Sprintf("network-inbound-queue-weight-%s", role),
+			func() uint { return policy.RoleWeight(role) },
+			func(weight uint) error {
+				err := policy.SetRoleWeight(role, weight)
+				if err != nil {
+					return updatable_configs.NewValidationErrorf("invalid weight: %w", err)
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return fnb.ConfigManager.RegisterUintConfig(
+		"network-inbound-queue-weight-unknown",
+		policy.UnknownSenderWeight,
+		func(weight uint) error {
+			err := policy.SetUnknownSenderWeight(weight)
+			if err != nil {
+				return updatable_configs.NewValidationErrorf("invalid weight: %w", err)
+			}
+			return nil
+		},
+	)
+}
+
 func (fnb *FlowNodeBuilder) InitFlowNetworkWithConduitFactory(
 	node *NodeConfig,
 	cf network.ConduitFactory,
@@ -630,11 +720,34 @@ func (fnb *FlowNodeBuilder) InitFlowNetworkWithConduitFactory(
 		networkOptions = append(networkOptions, underlay.WithPeerManagerFilters(peerManagerFilters...))
 	}
 
+	// message capture is disabled until enabled via the set-message-capture admin command
+	node.MessageCapturer = capture.NewCapturer(fnb.Logger, fnb.Me.NodeID())
+	networkOptions = append(networkOptions, underlay.WithMessageCapturer(node.MessageCapturer))
+
+	quotas, err := bandwidth.ParseQuotas(fnb.FlowConfig.NetworkConfig.ChannelBandwidthQuotas)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse channel bandwidth quotas: %w", err)
+	}
+	node.BandwidthAccountant, err = bandwidth.NewAccountant(fnb.Logger, fnb.Metrics.ChannelBandwidth, fnb.IdentityProvider, quotas)
+	if err != nil {
+		return nil, fmt.Errorf("could not create bandwidth accountant: %w", err)
+	}
+	networkOptions = append(networkOptions, underlay.WithBandwidthAccountant(node.BandwidthAccountant))
+
+	node.InboundQueuePolicy = queue.NewPolicy(
+		fnb.FlowConfig.NetworkConfig.InboundQueueMaxMessagesPerSender,
+		fnb.FlowConfig.NetworkConfig.InboundQueueStakeWeighted)
+	err = fnb.registerInboundQueuePolicyConfigs(node.InboundQueuePolicy)
+	if err != nil {
+		return nil, fmt.Errorf("could not register inbound queue policy configs: %w", err)
+	}
+	networkOptions = append(networkOptions, underlay.WithInboundQueuePolicy(node.InboundQueuePolicy))
+
 	receiveCache := netcache.NewHeroReceiveCache(fnb.FlowConfig.NetworkConfig.NetworkReceivedMessageCacheSize,
 		fnb.Logger,
 		metrics.NetworkReceiveCacheMetricsFactory(fnb.HeroCacheMetricsFactory(), network.PrivateNetwork))
 
-	err := node.Metrics.Mempool.Register(metrics.ResourceNetworkingReceiveCache, receiveCache.Size)
+	err = node.Metrics.Mempool.Register(metrics.ResourceNetworkingReceiveCache, receiveCache.Size)
 	if err != nil {
 		return nil, fmt.Errorf("could not register networking receive cache metric: %w", err)
 	}
@@ -669,6 +782,8 @@ func (fnb *FlowNodeBuilder) InitFlowNetworkWithConduitFactory(
 			AlspMetrics:             fnb.Metrics.Network,
 			HeroCacheMetricsFactory: fnb.HeroCacheMetricsFactory(),
 			NetworkType:             networkType,
+			SpamRecordSnapshots:     store.NewSpamRecordSnapshots(fnb.ProtocolDB),
+			SnapshotInterval:        fnb.FlowConfig.NetworkConfig.AlspConfig.SnapshotInterval,
 		},
 		SlashingViolationConsumerFactory: func(adapter network.ConduitAdapter) network.ViolationsConsumer {
 			return slashing.NewSlashingViolationsConsumer(fnb.Logger, fnb.Metrics.Network, adapter)
@@ -683,7 +798,14 @@ func (fnb *FlowNodeBuilder) InitFlowNetworkWithConduitFactory(
 	} else {
 		fnb.EngineRegistry = net // setting network as the fnb.Network for the engine-level components
 	}
+	if fnb.BaseConfig.networkFaultInjection {
+		// no faults are injected until fault rules are added via the add-network-fault admin command
+		node.NetworkFaultInjector = faults.NewInjector(fnb.Logger, fnb.IdentityProvider)
+		fnb.EngineRegistry = faults.NewNetwork(fnb.EngineRegistry, node.NetworkFaultInjector)
+		fnb.Logger.Warn().Msg("network fault injection is enabled, this must not be used in production")
+	}
 	fnb.NetworkUnderlay = net // setting network as the fnb.Underlay for the lower-level components
+	node.AlspSpamRecords = net.SpamRecords()
 
 	// register network ReadyDoneAware interface so other components can depend on it for startup
 	if fnb.networkUnderlayDependable != nil {
@@ -727,6 +849,10 @@ func (fnb *FlowNodeBuilder) EnqueueAdminServerInit() error {
 			admin.WithMaxMsgSize(int(fnb.AdminMaxMsgSize)),
 		}
 
+		if fnb.BaseConfig.peerScoreExplorerPage && node.PeerScoreExplorer != nil {
+			opts = append(opts, admin.WithHTTPHandler(peerScoreExplorerPath, node.PeerScoreExplorer))
+		}
+
 		if node.AdminCert != NotSet {
 			serverCert, err := tls.LoadX509KeyPair(node.AdminCert, node.AdminKey)
 			if err != nil {
@@ -927,13 +1053,14 @@ func (fnb *FlowNodeBuilder) initMetrics() error {
 	}
 
 	fnb.Metrics = Metrics{
-		Network:        metrics.NewNoopCollector(),
-		Engine:         metrics.NewNoopCollector(),
-		Compliance:     metrics.NewNoopCollector(),
-		Cache:          metrics.NewNoopCollector(),
-		Mempool:        metrics.NewNoopCollector(),
-		CleanCollector: metrics.NewNoopCollector(),
-		Bitswap:        metrics.NewNoopCollector(),
+		Network:          metrics.NewNoopCollector(),
+		Engine:           metrics.NewNoopCollector(),
+		Compliance:       metrics.NewNoopCollector(),
+		Cache:            metrics.NewNoopCollector(),
+		Mempool:          metrics.NewNoopCollector(),
+		CleanCollector:   metrics.NewNoopCollector(),
+		Bitswap:          metrics.NewNoopCollector(),
+		ChannelBandwidth: metrics.NewNoopCollector(),
 	}
 	if fnb.BaseConfig.MetricsEnabled {
 		fnb.MetricsRegisterer = prometheus.DefaultRegisterer
@@ -946,10 +1073,11 @@ func (fnb *FlowNodeBuilder) initMetrics() error {
 			Compliance: metrics.NewComplianceCollector(),
 			// CacheControl metrics has been causing memory abuse, disable for now
 			// Cache:          metrics.NewCacheCollector(fnb.RootChainID),
-			Cache:          metrics.NewNoopCollector(),
-			CleanCollector: metrics.NewCleanerCollector(),
-			Mempool:        mempools,
-			Bitswap:        metrics.NewBitswapCollector(),
+			Cache:            metrics.NewNoopCollector(),
+			CleanCollector:   metrics.NewCleanerCollector(),
+			Mempool:          mempools,
+			Bitswap:          metrics.NewBitswapCollector(),
+			ChannelBandwidth: metrics.NewChannelBandwidthMetrics(),
 		}
 
 		// registers mempools as a Component so that its Ready method is invoked upon startup
@@ -1294,6 +1422,7 @@ func (fnb *FlowNodeBuilder) InitIDProviders() {
 			return fmt.Errorf("could not initialize NodeBlockListWrapper: %w", err)
 		}
 		node.IdentityProvider = disallowListWrapper
+		node.NodeDisallowListWrapper = disallowListWrapper
 
 		if node.ObserverMode {
 			// identifier providers decides which node to connect to when syncing blocks,
@@ -2036,10 +2165,11 @@ func FlowNode(role string, opts ...Option) *FlowNodeBuilder {
 
 	builder := &FlowNodeBuilder{
 		NodeConfig: &NodeConfig{
-			BaseConfig:              *config,
-			Logger:                  zerolog.New(os.Stderr),
-			PeerManagerDependencies: NewDependencyList(),
-			ConfigManager:           updatable_configs.NewManager(),
+			BaseConfig:               *config,
+			Logger:                   zerolog.New(os.Stderr),
+			PeerManagerDependencies:  NewDependencyList(),
+			ConfigManager:            updatable_configs.NewManager(),
+			ConnectivitySnapshotters: make(map[network.NetworkingType]*connectivity.Snapshotter),
 		},
 		flags:                    pflag.CommandLine,
 		adminCommandBootstrapper: admin.NewCommandRunnerBootstrapper(),
@@ -2100,6 +2230,34 @@ func (fnb *FlowNodeBuilder) RegisterDefaultAdminCommands() {
 		return storageCommands.NewReadSealsCommand(config.State, config.Storage.Seals, config.Storage.Index)
 	}).AdminCommand("get-latest-identity", func(config *NodeConfig) commands.AdminCommand {
 		return common.NewGetIdentityCommand(config.IdentityProvider)
+	}).AdminCommand("get-protocol-state", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewGetProtocolStateCommand(config.State)
+	}).AdminCommand("get-protocol-state-diff", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewGetProtocolStateDiffCommand(config.State)
+	}).AdminCommand("set-message-capture", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewSetMessageCaptureCommand(config.MessageCapturer, config.netCaptureDir)
+	}).AdminCommand("get-alsp-penalties", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewGetAlspPenaltiesCommand(config.AlspSpamRecords)
+	}).AdminCommand("reset-alsp-penalties", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewResetAlspPenaltiesCommand(config.AlspSpamRecords)
+	}).AdminCommand("get-disallow-list", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewGetDisallowListCommand(config.OperatorDisallowList, config.NodeDisallowListWrapper, config.AlspSpamRecords)
+	}).AdminCommand("add-to-disallow-list", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewAddToDisallowListCommand(config.OperatorDisallowList)
+	}).AdminCommand("remove-from-disallow-list", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewRemoveFromDisallowListCommand(config.OperatorDisallowList)
+	}).AdminCommand("get-peer-scores", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewGetPeerScoresCommand(config.PeerScoreExplorer)
+	}).AdminCommand("add-network-fault", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewAddNetworkFaultCommand(config.NetworkFaultInjector)
+	}).AdminCommand("remove-network-faults", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewRemoveNetworkFaultsCommand(config.NetworkFaultInjector)
+	}).AdminCommand("get-network-faults", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewGetNetworkFaultsCommand(config.NetworkFaultInjector)
+	}).AdminCommand("get-bandwidth-report", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewGetBandwidthReportCommand(config.BandwidthAccountant)
+	}).AdminCommand("get-connectivity-snapshot", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewGetConnectivitySnapshotCommand(config.ConnectivitySnapshotters)
 	}).AdminCommand("create-pebble-checkpoint", func(config *NodeConfig) commands.AdminCommand {
 		// by default checkpoints will be created under "/data/protocol_pebble_checkpoints"
 		return storageCommands.NewPebbleDBCheckpointCommand(config.pebbleCheckpointsDir, "protocol", config.PebbleDB)
@@ -0,0 +1,92 @@
+package compress_chunk_data_packs
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/operation/pebbleimpl"
+	storagepebble "github.com/onflow/flow-go/storage/pebble"
+)
+
+var (
+	flagChunkDataPackDir string
+	flagDatadir          string
+	flagCompression      string
+	flagBatchSize        int
+)
+
+var Cmd = &cobra.Command{
+	Use:   "compress-chunk-data-packs",
+	Short: "Re-encodes all stored chunk data packs with the given proof compression",
+	Long: `Re-encodes all chunk data packs in the chunk data pack database with the given proof compression.
+Running with --compression=none decompresses all chunk data packs, which is required before
+downgrading to a version without chunk data pack compression support.
+The execution node must be stopped while this command is running.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagChunkDataPackDir, "chunk_data_pack_dir", "/var/flow/data/chunk_data_pack",
+		"directory that stores the chunk data packs")
+	_ = Cmd.MarkFlagRequired("chunk_data_pack_dir")
+
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state. if set, the collections referenced by "+
+			"chunk data packs are checked to be present in the collections storage")
+
+	Cmd.Flags().StringVar(&flagCompression, "compression", storage.ChunkDataPackCompressedLz4.String(),
+		"the compression to apply to chunk data pack proofs, one of none, lz4 or gzip")
+
+	Cmd.Flags().IntVar(&flagBatchSize, "batch-size", 1000,
+		"number of chunk data packs re-encoded in a single batch")
+}
+
+func run(*cobra.Command, []string) {
+	compression, err := storage.ParseChunkDataPackCompression(flagCompression)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid compression")
+	}
+
+	if flagBatchSize <= 0 {
+		log.Fatal().Msg("batch size must be above 0")
+	}
+
+	log.Info().
+		Str("chunk_data_pack_dir", flagChunkDataPackDir).
+		Str("datadir", flagDatadir).
+		Stringer("compression", compression).
+		Int("batch_size", flagBatchSize).
+		Msg("flags")
+
+	var collections storage.Collections
+	if flagDatadir != "" {
+		bdb := common.InitStorage(flagDatadir)
+		defer bdb.Close()
+		collections = badger.NewCollections(bdb, badger.NewTransactions(metrics.NewNoopCollector(), bdb))
+	}
+
+	pdb, err := storagepebble.MustOpenDefaultPebbleDB(
+		log.Logger.With().Str("pebbledb", "cdp").Logger(), flagChunkDataPackDir)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("could not open chunk data pack DB at %v", flagChunkDataPackDir)
+	}
+	defer pdb.Close()
+
+	stats, err := CompressChunkDataPacks(log.Logger, pebbleimpl.ToDB(pdb), collections, compression, flagBatchSize)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not compress chunk data packs")
+	}
+
+	log.Info().
+		Int("total", stats.Total).
+		Int("reencoded", stats.Reencoded).
+		Int("distinct_collections", stats.DistinctCollections).
+		Int("missing_collections", stats.MissingCollections).
+		Uint64("proof_bytes_before", stats.ProofBytesBefore).
+		Uint64("proof_bytes_after", stats.ProofBytesAfter).
+		Msg("chunk data packs re-encoded")
+}
@@ -0,0 +1,134 @@
+package compress_chunk_data_packs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation"
+	"github.com/onflow/flow-go/storage/store"
+)
+
+// Stats summarizes the re-encoding of the chunk data pack database.
+type Stats struct {
+	// Total is the number of stored chunk data packs.
+	Total int
+	// Reencoded is the number of chunk data packs which were not already stored
+	// with the requested compression.
+	Reencoded int
+	// DistinctCollections is the number of distinct collections referenced by
+	// chunk data packs. Collections are stored once in the collections storage,
+	// no matter how many chunk data packs reference them.
+	DistinctCollections int
+	// MissingCollections is the number of referenced collections which are not
+	// present in the collections storage. Only counted if collections are given.
+	MissingCollections int
+	// ProofBytesBefore and ProofBytesAfter are the total sizes of the stored
+	// proofs before and after re-encoding.
+	ProofBytesBefore uint64
+	ProofBytesAfter  uint64
+}
+
+// CompressChunkDataPacks re-encodes all chunk data packs stored in the database with the
+// given proof compression, committing every batchSize re-encoded chunk data packs.
+// If collections is not nil, the collections referenced by chunk data packs are checked
+// to be present in the collections storage.
+// No errors are expected during normal operation.
+func CompressChunkDataPacks(
+	log zerolog.Logger,
+	db storage.DB,
+	collections storage.Collections,
+	compression storage.ChunkDataPackCompression,
+	batchSize int,
+) (Stats, error) {
+	var stats Stats
+	referenced := make(map[flow.Identifier]struct{})
+
+	batch := db.NewBatch()
+	pending := 0
+
+	commit := func() error {
+		err := batch.Commit()
+		if err != nil {
+			return fmt.Errorf("could not commit batch: %w", err)
+		}
+		batch = db.NewBatch()
+		pending = 0
+		return nil
+	}
+
+	err := operation.TraverseChunkDataPacks(db.Reader(), func(sc *storage.StoredChunkDataPack) error {
+		stats.Total++
+		stats.ProofBytesBefore += uint64(len(sc.Proof))
+
+		if !sc.SystemChunk {
+			if _, ok := referenced[sc.CollectionID]; !ok {
+				referenced[sc.CollectionID] = struct{}{}
+
+				if collections != nil {
+					_, err := collections.LightByID(sc.CollectionID)
+					if errors.Is(err, storage.ErrNotFound) {
+						stats.MissingCollections++
+						log.Warn().
+							Hex("chunk_id", sc.ChunkID[:]).
+							Hex("collection_id", sc.CollectionID[:]).
+							Msg("collection referenced by chunk data pack not found")
+					} else if err != nil {
+						return fmt.Errorf("could not check collection %v: %w", sc.CollectionID, err)
+					}
+				}
+			}
+		}
+
+		if sc.Compression == compression {
+			stats.ProofBytesAfter += uint64(len(sc.Proof))
+			return nil
+		}
+
+		decompressed, err := store.DecompressChunkDataPack(sc)
+		if err != nil {
+			return err
+		}
+
+		reencoded, err := store.CompressChunkDataPack(decompressed, compression)
+		if err != nil {
+			return err
+		}
+
+		err = operation.InsertChunkDataPack(batch.Writer(), reencoded)
+		if err != nil {
+			return fmt.Errorf("could not insert chunk data pack %v: %w", sc.ChunkID, err)
+		}
+
+		stats.Reencoded++
+		stats.ProofBytesAfter += uint64(len(reencoded.Proof))
+
+		pending++
+		if pending >= batchSize {
+			err = commit()
+			if err != nil {
+				return err
+			}
+			log.Info().
+				Int("total", stats.Total).
+				Int("reencoded", stats.Reencoded).
+				Msg("re-encoding chunk data packs")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return stats, fmt.Errorf("could not traverse chunk data packs: %w", err)
+	}
+
+	err = commit()
+	if err != nil {
+		return stats, err
+	}
+
+	stats.DistinctCollections = len(referenced)
+	return stats, nil
+}
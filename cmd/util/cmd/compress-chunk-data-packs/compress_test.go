@@ -0,0 +1,74 @@
+package compress_chunk_data_packs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/operation"
+	"github.com/onflow/flow-go/storage/operation/pebbleimpl"
+	"github.com/onflow/flow-go/storage/store"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestCompressChunkDataPacks(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(bdb *badger.DB) {
+		unittest.RunWithPebbleDB(t, func(pdb *pebble.DB) {
+			db := pebbleimpl.ToDB(pdb)
+			collections := badgerstorage.NewCollections(bdb, badgerstorage.NewTransactions(metrics.NewNoopCollector(), bdb))
+
+			// chunk data packs stored before compression was introduced
+			chunkDataPacks := unittest.ChunkDataPacksFixture(5)
+			for i, c := range chunkDataPacks {
+				c.Proof = bytes.Repeat([]byte("proof"), 100)
+				// the last collection is not stored
+				if i < len(chunkDataPacks)-1 {
+					require.NoError(t, collections.Store(c.Collection))
+				}
+			}
+			uncompressed := store.NewChunkDataPacks(metrics.NewNoopCollector(), db, collections, 1)
+			require.NoError(t, uncompressed.Store(chunkDataPacks))
+
+			stats, err := CompressChunkDataPacks(unittest.Logger(), db, collections, storage.ChunkDataPackCompressedLz4, 2)
+			require.NoError(t, err)
+			require.Equal(t, 5, stats.Total)
+			require.Equal(t, 5, stats.Reencoded)
+			require.Equal(t, 5, stats.DistinctCollections)
+			require.Equal(t, 1, stats.MissingCollections)
+			require.Less(t, stats.ProofBytesAfter, stats.ProofBytesBefore)
+
+			for _, c := range chunkDataPacks[:len(chunkDataPacks)-1] {
+				var stored storage.StoredChunkDataPack
+				require.NoError(t, operation.RetrieveChunkDataPack(db.Reader(), c.ChunkID, &stored))
+				require.Equal(t, storage.ChunkDataPackCompressedLz4, stored.Compression)
+
+				// chunk data packs are served unchanged
+				actual, err := uncompressed.ByChunkID(c.ChunkID)
+				require.NoError(t, err)
+				require.Equal(t, c, actual)
+			}
+
+			// running again is a no-op
+			stats, err = CompressChunkDataPacks(unittest.Logger(), db, nil, storage.ChunkDataPackCompressedLz4, 2)
+			require.NoError(t, err)
+			require.Equal(t, 0, stats.Reencoded)
+
+			// decompressing restores the original encoding
+			stats, err = CompressChunkDataPacks(unittest.Logger(), db, nil, storage.ChunkDataPackUncompressed, 2)
+			require.NoError(t, err)
+			require.Equal(t, 5, stats.Reencoded)
+
+			for _, c := range chunkDataPacks {
+				var stored storage.StoredChunkDataPack
+				require.NoError(t, operation.RetrieveChunkDataPack(db.Reader(), c.ChunkID, &stored))
+				require.Equal(t, storage.ToStoredChunkDataPack(c), &stored)
+			}
+		})
+	})
+}
@@ -0,0 +1,129 @@
+package decode_network_capture
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/capture"
+	"github.com/onflow/flow-go/network/codec"
+	"github.com/onflow/flow-go/network/codec/cbor"
+)
+
+var (
+	flagPath     string
+	flagChannels []string
+	flagPeers    []string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "decode-network-capture",
+	Short: "decode network message captures into JSON",
+	Long: `Reads the network message captures written while message capture is enabled with the
+set-message-capture admin command, and prints one JSON object per captured message. Payloads,
+if captured, are decoded with the CBOR network codec into the message type denoted by the
+message code.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagPath, "path", "", "capture directory, or a single capture file")
+	_ = Cmd.MarkFlagRequired("path")
+	Cmd.Flags().StringSliceVar(&flagChannels, "channel", nil, "only print messages on the given channels")
+	Cmd.Flags().StringSliceVar(&flagPeers, "peer", nil, "only print messages exchanged with the given peer IDs or node IDs")
+}
+
+// decodedRecord is the JSON representation of a captured message.
+type decodedRecord struct {
+	Time      time.Time           `json:"time"`
+	Direction string              `json:"direction"`
+	Protocol  string              `json:"protocol"`
+	Channel   string              `json:"channel"`
+	PeerID    string              `json:"peer_id,omitempty"`
+	OriginID  flow.Identifier     `json:"origin_id"`
+	TargetIDs flow.IdentifierList `json:"target_ids,omitempty"`
+	Code      codec.MessageCode   `json:"code"`
+	Type      string              `json:"type"`
+	Size      int                 `json:"size"`
+	Message   interface{}         `json:"message,omitempty"`
+	Error     string              `json:"error,omitempty"`
+}
+
+func run(*cobra.Command, []string) {
+	records, err := capture.ReadCapture(flagPath)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("could not read capture from %s", flagPath)
+	}
+	log.Info().Msgf("read %d captured messages from %s", len(records), flagPath)
+
+	channels := make(map[string]struct{}, len(flagChannels))
+	for _, channel := range flagChannels {
+		channels[channel] = struct{}{}
+	}
+	peers := make(map[string]struct{}, len(flagPeers))
+	for _, peer := range flagPeers {
+		peers[peer] = struct{}{}
+	}
+
+	networkCodec := cbor.NewCodec()
+	encoder := json.NewEncoder(os.Stdout)
+	for _, record := range records {
+		if len(channels) > 0 {
+			if _, ok := channels[record.Channel.String()]; !ok {
+				continue
+			}
+		}
+		if len(peers) > 0 && !matchesPeer(record, peers) {
+			continue
+		}
+
+		decoded := decodedRecord{
+			Time:      record.Time(),
+			Direction: record.Direction.String(),
+			Protocol:  record.Protocol.String(),
+			Channel:   record.Channel.String(),
+			PeerID:    record.PeerID,
+			OriginID:  record.OriginID,
+			TargetIDs: record.TargetIDs,
+			Code:      record.Code,
+			Size:      record.Size,
+		}
+		_, decoded.Type, err = codec.InterfaceFromMessageCode(record.Code)
+		if err != nil {
+			decoded.Type = "unknown"
+		}
+		if record.Payload != nil {
+			decoded.Message, err = networkCodec.Decode(record.Payload)
+			if err != nil {
+				decoded.Error = err.Error()
+			}
+		}
+
+		err = encoder.Encode(decoded)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not encode captured message")
+		}
+	}
+}
+
+// matchesPeer returns true if the record was exchanged with one of the given peers, which are
+// libp2p peer IDs or hex-encoded Flow node IDs.
+func matchesPeer(record *capture.Record, peers map[string]struct{}) bool {
+	if _, ok := peers[record.PeerID]; ok && record.PeerID != "" {
+		return true
+	}
+	if record.Direction == capture.Inbound {
+		_, ok := peers[record.OriginID.String()]
+		return ok
+	}
+	for _, targetID := range record.TargetIDs {
+		if _, ok := peers[targetID.String()]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package light_follower
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/follower/light"
+	"github.com/onflow/flow-go/storage/operation/pebbleimpl"
+	storagepebble "github.com/onflow/flow-go/storage/pebble"
+	"github.com/onflow/flow-go/utils/io"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+var (
+	flagDatadir       string
+	flagRootSnapshot  string
+	flagAccessAddress string
+	flagStopHeight    uint64
+	flagPollInterval  time.Duration
+)
+
+var Cmd = &cobra.Command{
+	Use:   "light-follower",
+	Short: "Follows the finalized block headers of an Access node with the light follower",
+	Long: `Follows the finalized block headers of an Access node with the light follower, verifying the
+proposer signature and QC of each header against the consensus committee, and the epoch service
+events against the seals of finalized blocks. The light follower database is bootstrapped from the
+given protocol snapshot on first use, and following continues from the latest finalized header
+afterwards.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory of the light follower database")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().StringVar(&flagRootSnapshot, "root-snapshot", "",
+		"path to the protocol snapshot (e.g. root-protocol-state-snapshot.json) to bootstrap an empty database with")
+
+	Cmd.Flags().StringVar(&flagAccessAddress, "access-address", "",
+		"gRPC address of the access node")
+	_ = Cmd.MarkFlagRequired("access-address")
+
+	Cmd.Flags().Uint64Var(&flagStopHeight, "stop-height", 0,
+		"height at which to stop following, 0 to follow until interrupted")
+
+	Cmd.Flags().DurationVar(&flagPollInterval, "poll-interval", time.Second,
+		"interval at which to poll the access node for new finalized blocks")
+}
+
+func run(*cobra.Command, []string) {
+	pdb, err := storagepebble.MustOpenDefaultPebbleDB(
+		log.Logger.With().Str("pebbledb", "light-follower").Logger(), flagDatadir)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("could not open light follower DB at %v", flagDatadir)
+	}
+	defer pdb.Close()
+	db := pebbleimpl.ToDB(pdb)
+
+	bootstrapped, err := light.IsBootstrapped(db)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not check whether the light follower is bootstrapped")
+	}
+	if !bootstrapped {
+		if flagRootSnapshot == "" {
+			log.Fatal().Msg("--root-snapshot is required to bootstrap an empty light follower database")
+		}
+		bz, err := io.ReadFile(flagRootSnapshot)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("could not read snapshot %v", flagRootSnapshot)
+		}
+		snapshot, err := convert.BytesToInmemSnapshot(bz)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not decode snapshot")
+		}
+		err = BootstrapFromSnapshot(db, snapshot)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not bootstrap light follower")
+		}
+	}
+
+	follower, err := light.New(log.Logger, db)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create light follower")
+	}
+
+	conn, err := grpc.NewClient(
+		flagAccessAddress,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create access connection")
+	}
+	defer conn.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	err = Follow(ctx, log.Logger, follower, access.NewAccessAPIClient(conn), flagStopHeight, flagPollInterval)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not follow access node")
+	}
+
+	finalized := follower.FinalizedHeader()
+	log.Info().
+		Uint64("finalized_height", finalized.Height).
+		Hex("finalized_id", logging.ID(finalized.ID())).
+		Msg("stopped following")
+}
@@ -0,0 +1,148 @@
+package light_follower
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/follower/light"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// BootstrapFromSnapshot bootstraps an empty light follower database with the head of the given
+// snapshot, the QC certifying it and the service events of the epoch containing it.
+// No errors are expected during normal operation.
+func BootstrapFromSnapshot(db storage.DB, snapshot protocol.Snapshot) error {
+	head, err := snapshot.Head()
+	if err != nil {
+		return fmt.Errorf("could not get snapshot head: %w", err)
+	}
+	qc, err := snapshot.QuorumCertificate()
+	if err != nil {
+		return fmt.Errorf("could not get snapshot QC: %w", err)
+	}
+	epochState, err := snapshot.EpochProtocolState()
+	if err != nil {
+		return fmt.Errorf("could not get snapshot epoch state: %w", err)
+	}
+	return light.Bootstrap(db, head, qc, epochState.EpochSetup(), epochState.EpochCommit())
+}
+
+// Follow adds the finalized blocks of the Access node to the light follower in order of height,
+// starting above the latest header finalized by the follower. Once the follower finalizes a block,
+// the execution results sealed in the block's payload are retrieved, and those containing service
+// events are added to the follower, so that it follows the committee across epochs.
+// Following stops once the follower has finalized the given stop height (0 to never stop), or
+// the context is cancelled.
+// No errors are expected during normal operation.
+func Follow(
+	ctx context.Context,
+	log zerolog.Logger,
+	follower *light.Follower,
+	client access.AccessAPIClient,
+	stopHeight uint64,
+	pollInterval time.Duration,
+) error {
+	// the consumer is called synchronously by AddHeader, i.e. by this goroutine
+	var finalized []*flow.Header
+	follower.AddOnHeaderFinalizedConsumer(func(header *flow.Header) {
+		finalized = append(finalized, header)
+	})
+	// payloads of the added blocks, until the follower finalizes them
+	payloads := make(map[flow.Identifier]*flow.Payload)
+
+	height := follower.FinalizedHeader().Height + 1
+	for stopHeight == 0 || follower.FinalizedHeader().Height < stopHeight {
+		resp, err := client.GetBlockByHeight(ctx, &access.GetBlockByHeightRequest{Height: height, FullBlockResponse: true})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if status.Code(err) == codes.NotFound {
+			// the block at this height is not finalized yet
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not get block at height %d: %w", height, err)
+		}
+		block, err := convert.MessageToBlock(resp.Block)
+		if err != nil {
+			return fmt.Errorf("could not convert block at height %d: %w", height, err)
+		}
+
+		err = follower.AddHeader(block.Header)
+		if err != nil {
+			return fmt.Errorf("could not add header at height %d: %w", height, err)
+		}
+		payloads[block.ID()] = block.Payload
+
+		for _, header := range finalized {
+			payload, ok := payloads[header.ID()]
+			if !ok {
+				return fmt.Errorf("missing payload of finalized block %v", header.ID())
+			}
+			delete(payloads, header.ID())
+			err = addSealedResults(ctx, log, follower, client, header, payload)
+			if err != nil {
+				return fmt.Errorf("could not add results sealed in block %v at height %d: %w", header.ID(), header.Height, err)
+			}
+			log.Debug().Uint64("height", header.Height).Hex("block_id", logging.ID(header.ID())).Msg("header finalized")
+		}
+		finalized = finalized[:0]
+		height++
+	}
+	return nil
+}
+
+// addSealedResults adds the execution results sealed in the payload of the finalized header
+// which contain service events to the follower.
+// No errors are expected during normal operation.
+func addSealedResults(
+	ctx context.Context,
+	log zerolog.Logger,
+	follower *light.Follower,
+	client access.AccessAPIClient,
+	header *flow.Header,
+	payload *flow.Payload,
+) error {
+	for _, seal := range payload.Seals {
+		resp, err := client.GetExecutionResultByID(ctx, &access.GetExecutionResultByIDRequest{Id: seal.ResultID[:]})
+		if err != nil {
+			return fmt.Errorf("could not get execution result %v: %w", seal.ResultID, err)
+		}
+		result, err := convert.MessageToExecutionResult(resp.ExecutionResult)
+		if err != nil {
+			return fmt.Errorf("could not convert execution result %v: %w", seal.ResultID, err)
+		}
+		if len(result.ServiceEvents) == 0 {
+			continue
+		}
+
+		err = follower.AddSealedResult(&light.SealedResultProof{
+			SealingBlockID: header.ID(),
+			Payload:        payload,
+			Result:         result,
+		})
+		if err != nil {
+			return fmt.Errorf("could not add sealed result %v: %w", seal.ResultID, err)
+		}
+		log.Info().
+			Hex("result_id", logging.ID(seal.ResultID)).
+			Int("service_events", len(result.ServiceEvents)).
+			Msg("added sealed result with service events")
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package merge_connectivity_snapshots
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/network/p2p/connectivity"
+)
+
+var (
+	flagFormat string
+	flagOutput string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "merge-connectivity-snapshots <snapshot-file>...",
+	Short: "merge connectivity snapshots of several nodes into a network-wide graph",
+	Long: `Reads the JSON connectivity snapshots returned by the get-connectivity-snapshot admin command
+on several nodes, either the raw snapshots or the full admin command responses, and merges them into
+a network-wide connectivity graph. The graph is printed in Graphviz DOT (default), which can be
+rendered with e.g. "dot -Tsvg", or as JSON.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagFormat, "format", "dot", "output format, one of: dot, json")
+	Cmd.Flags().StringVar(&flagOutput, "output", "", "output file, defaults to stdout")
+}
+
+func run(_ *cobra.Command, args []string) {
+	if flagFormat != "dot" && flagFormat != "json" {
+		log.Fatal().Msgf("invalid format %q, expected one of: dot, json", flagFormat)
+	}
+
+	snapshots := make([]connectivity.Snapshot, 0, len(args))
+	for _, path := range args {
+		snapshot, err := readSnapshot(path)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("could not read snapshot from %s", path)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	graph := connectivity.Merge(snapshots...)
+	log.Info().Msgf("merged %d snapshots into a graph of %d nodes and %d connections", len(snapshots), len(graph.Nodes), len(graph.Edges))
+
+	var output []byte
+	if flagFormat == "json" {
+		var err error
+		output, err = json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not encode graph")
+		}
+	} else {
+		output = []byte(graph.DOT())
+	}
+
+	if flagOutput == "" {
+		_, err := os.Stdout.Write(output)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not write graph")
+		}
+		return
+	}
+	err := os.WriteFile(flagOutput, output, 0644)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("could not write graph to %s", flagOutput)
+	}
+}
+
+// readSnapshot reads a snapshot from the given file, which contains either the snapshot or the response of the
+// admin command, with the snapshot in its "output" field.
+func readSnapshot(path string) (connectivity.Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return connectivity.Snapshot{}, err
+	}
+
+	var response struct {
+		Output *connectivity.Snapshot `json:"output"`
+	}
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return connectivity.Snapshot{}, fmt.Errorf("could not decode snapshot: %w", err)
+	}
+	if response.Output != nil {
+		return *response.Output, nil
+	}
+
+	var snapshot connectivity.Snapshot
+	err = json.Unmarshal(data, &snapshot)
+	if err != nil {
+		return connectivity.Snapshot{}, fmt.Errorf("could not decode snapshot: %w", err)
+	}
+	if snapshot.PeerID == "" {
+		return connectivity.Snapshot{}, fmt.Errorf("missing peer ID, expected a connectivity snapshot")
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,139 @@
+package read_flight_recordings
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/flightrecorder"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+var (
+	flagDirs             []string
+	flagFromView         uint64
+	flagToView           uint64
+	flagCriticalPathOnly bool
+)
+
+var Cmd = &cobra.Command{
+	Use:   "read-flight-recordings",
+	Short: "merge hotstuff flight recordings of several nodes and print per-view timelines and critical paths",
+	Long: `Reads the recordings written by the hotstuff flight recorder (--hotstuff-flight-recorder-dir)
+of one or more nodes, merges them and prints, for each view, the events of all nodes relative to
+the time the view was first entered, followed by the critical path which determined when the view
+ended. Events are timestamped with the clock of the recording node, so timings across nodes are
+subject to the clock offset between the nodes.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringSliceVar(&flagDirs, "dir", nil, "recording directory of a node, can be repeated to merge the recordings of several nodes")
+	_ = Cmd.MarkFlagRequired("dir")
+	Cmd.Flags().Uint64Var(&flagFromView, "from-view", 0, "first view to print")
+	Cmd.Flags().Uint64Var(&flagToView, "to-view", math.MaxUint64, "last view to print")
+	Cmd.Flags().BoolVar(&flagCriticalPathOnly, "critical-path-only", false, "only print the critical path of each view")
+}
+
+func run(*cobra.Command, []string) {
+	if flagFromView > flagToView {
+		log.Fatal().Msgf("--from-view %d is larger than --to-view %d", flagFromView, flagToView)
+	}
+
+	recordings := make([]*flightrecorder.Recording, 0, len(flagDirs))
+	for _, dir := range flagDirs {
+		recording, err := flightrecorder.ReadRecording(dir)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("could not read recording from %s", dir)
+		}
+		log.Info().Msgf("read %d records of node %v from %s", len(recording.Records), recording.NodeID, dir)
+		recordings = append(recordings, recording)
+	}
+
+	events := flightrecorder.Merge(recordings...)
+	PrintTimelines(os.Stdout, events, flagFromView, flagToView, flagCriticalPathOnly)
+}
+
+// PrintTimelines prints the timeline and critical path of every view within [fromView, toView],
+// followed by a summary of the outcomes and critical path durations of all views.
+func PrintTimelines(w io.Writer, events []flightrecorder.Event, fromView, toView uint64, criticalPathOnly bool) {
+	// include the view following the last view, which concludes the critical path of the last view
+	lastView := toView
+	if lastView < math.MaxUint64 {
+		lastView++
+	}
+	timelines := flightrecorder.Timelines(events, fromView, lastView)
+
+	outcomes := make(map[flightrecorder.ViewOutcome]int)
+	var total, slowest time.Duration
+	var slowestView uint64
+	printed := 0
+	for i, timeline := range timelines {
+		if timeline.View > toView {
+			break
+		}
+		var next *flightrecorder.ViewTimeline
+		if i+1 < len(timelines) {
+			next = timelines[i+1]
+		}
+		path := timeline.CriticalPath(next)
+		printed++
+		outcomes[path.Outcome]++
+		total += path.Duration()
+		if path.Duration() > slowest {
+			slowest, slowestView = path.Duration(), timeline.View
+		}
+
+		_, _ = fmt.Fprintf(w, "view %d: %s, critical path %v\n", timeline.View, path.Outcome, path.Duration())
+		if !criticalPathOnly {
+			start := timeline.Start()
+			for _, event := range timeline.Events {
+				_, _ = fmt.Fprintf(w, "  %10v  %s  %-18s %s\n", event.Time.Sub(start), shortID(event.Recorder), event.Type, details(event.Record))
+			}
+			_, _ = fmt.Fprintln(w, "  critical path:")
+		}
+		for _, step := range path.Steps {
+			_, _ = fmt.Fprintf(w, "  %10v  %s  %-18s %s\n", step.Elapsed, shortID(step.Recorder), step.Type, details(step.Record))
+		}
+	}
+
+	if printed == 0 {
+		_, _ = fmt.Fprintln(w, "no events in the requested views")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "\n%d views: %d concluded by QC, %d by TC, %d unknown\n",
+		printed, outcomes[flightrecorder.OutcomeQC], outcomes[flightrecorder.OutcomeTC], outcomes[flightrecorder.OutcomeUnknown])
+	_, _ = fmt.Fprintf(w, "average critical path %v, slowest view %d (%v)\n",
+		total/time.Duration(printed), slowestView, slowest)
+}
+
+// details formats the optional fields of a record.
+func details(r *flightrecorder.Record) string {
+	var parts []string
+	if r.CurView != 0 {
+		parts = append(parts, fmt.Sprintf("cur_view=%d", r.CurView))
+	}
+	if r.BlockID != flow.ZeroID {
+		parts = append(parts, "block="+shortID(r.BlockID))
+	}
+	if r.NodeID != flow.ZeroID {
+		parts = append(parts, "node="+shortID(r.NodeID))
+	}
+	if r.AuxView != 0 {
+		parts = append(parts, fmt.Sprintf("aux_view=%d", r.AuxView))
+	}
+	if !r.AuxTime.IsZero() {
+		parts = append(parts, fmt.Sprintf("target=%v", r.AuxTime.Sub(r.Time)))
+	}
+	return strings.Join(parts, " ")
+}
+
+func shortID(id flow.Identifier) string {
+	return id.String()[:8]
+}
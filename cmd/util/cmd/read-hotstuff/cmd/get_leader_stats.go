@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/operation/badgerimpl"
+	"github.com/onflow/flow-go/storage/store"
+)
+
+var (
+	flagEpoch  uint64
+	flagNodeID string
+)
+
+var GetLeaderStatsCmd = &cobra.Command{
+	Use:   "get-leader-stats",
+	Short: "get the persisted per-leader view outcome statistics (proposals on time, late, missing and timed out views)",
+	Run:   runGetLeaderStats,
+}
+
+func init() {
+	rootCmd.AddCommand(GetLeaderStatsCmd)
+
+	GetLeaderStatsCmd.Flags().Uint64Var(&flagEpoch, "epoch", 0, "only get the statistics of the given epoch")
+	GetLeaderStatsCmd.Flags().StringVar(&flagNodeID, "node-id", "", "only get the statistics of the leader with the given node ID")
+}
+
+func runGetLeaderStats(cmd *cobra.Command, _ []string) {
+	var nodeID *flow.Identifier
+	if flagNodeID != "" {
+		id, err := flow.HexStringToIdentifier(flagNodeID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid node ID")
+		}
+		nodeID = &id
+	}
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	log.Info().Msg("getting leader stats")
+
+	leaderStats := store.NewLeaderStats(badgerimpl.ToDB(db))
+	var all []*model.LeaderStats
+	var err error
+	if cmd.Flags().Changed("epoch") {
+		all, err = leaderStats.ByEpoch(flagEpoch)
+	} else {
+		all, err = leaderStats.All()
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not get leader stats")
+	}
+
+	filtered := make([]*model.LeaderStats, 0, len(all))
+	for _, stats := range all {
+		if nodeID != nil && stats.NodeID != *nodeID {
+			continue
+		}
+		filtered = append(filtered, stats)
+	}
+
+	log.Info().Msgf("successfully got stats of %d leaders", len(filtered))
+	common.PrettyPrint(filtered)
+}
@@ -17,8 +17,10 @@ import (
 	checkpoint_collect_stats "github.com/onflow/flow-go/cmd/util/cmd/checkpoint-collect-stats"
 	checkpoint_list_tries "github.com/onflow/flow-go/cmd/util/cmd/checkpoint-list-tries"
 	checkpoint_trie_stats "github.com/onflow/flow-go/cmd/util/cmd/checkpoint-trie-stats"
+	compress_chunk_data_packs "github.com/onflow/flow-go/cmd/util/cmd/compress-chunk-data-packs"
 	debug_script "github.com/onflow/flow-go/cmd/util/cmd/debug-script"
 	debug_tx "github.com/onflow/flow-go/cmd/util/cmd/debug-tx"
+	decode_network_capture "github.com/onflow/flow-go/cmd/util/cmd/decode-network-capture"
 	diff_states "github.com/onflow/flow-go/cmd/util/cmd/diff-states"
 	epochs "github.com/onflow/flow-go/cmd/util/cmd/epochs/cmd"
 	export "github.com/onflow/flow-go/cmd/util/cmd/exec-data-json-export"
@@ -31,16 +33,22 @@ import (
 	find_inconsistent_result "github.com/onflow/flow-go/cmd/util/cmd/find-inconsistent-result"
 	find_trie_root "github.com/onflow/flow-go/cmd/util/cmd/find-trie-root"
 	generate_authorization_fixes "github.com/onflow/flow-go/cmd/util/cmd/generate-authorization-fixes"
+	light_follower "github.com/onflow/flow-go/cmd/util/cmd/light-follower"
+	merge_connectivity_snapshots "github.com/onflow/flow-go/cmd/util/cmd/merge-connectivity-snapshots"
 	read_badger "github.com/onflow/flow-go/cmd/util/cmd/read-badger/cmd"
 	read_execution_state "github.com/onflow/flow-go/cmd/util/cmd/read-execution-state"
+	read_flight_recordings "github.com/onflow/flow-go/cmd/util/cmd/read-flight-recordings"
 	read_hotstuff "github.com/onflow/flow-go/cmd/util/cmd/read-hotstuff/cmd"
 	read_protocol_state "github.com/onflow/flow-go/cmd/util/cmd/read-protocol-state/cmd"
 	index_er "github.com/onflow/flow-go/cmd/util/cmd/reindex/cmd"
 	rollback_executed_height "github.com/onflow/flow-go/cmd/util/cmd/rollback-executed-height/cmd"
 	run_script "github.com/onflow/flow-go/cmd/util/cmd/run-script"
+	simulate_consensus "github.com/onflow/flow-go/cmd/util/cmd/simulate-consensus"
+	slashing_evidence "github.com/onflow/flow-go/cmd/util/cmd/slashing-evidence"
 	"github.com/onflow/flow-go/cmd/util/cmd/snapshot"
 	system_addresses "github.com/onflow/flow-go/cmd/util/cmd/system-addresses"
 	truncate_database "github.com/onflow/flow-go/cmd/util/cmd/truncate-database"
+	tune_cruisectl "github.com/onflow/flow-go/cmd/util/cmd/tune-cruisectl"
 	verify_evm_offchain_replay "github.com/onflow/flow-go/cmd/util/cmd/verify-evm-offchain-replay"
 	verify_execution_result "github.com/onflow/flow-go/cmd/util/cmd/verify_execution_result"
 	"github.com/onflow/flow-go/cmd/util/cmd/version"
@@ -110,10 +118,18 @@ func addCommands() {
 	rootCmd.AddCommand(edbs.RootCmd)
 	rootCmd.AddCommand(index_er.RootCmd)
 	rootCmd.AddCommand(rollback_executed_height.Cmd)
+	rootCmd.AddCommand(compress_chunk_data_packs.Cmd)
 	rootCmd.AddCommand(read_execution_state.Cmd)
 	rootCmd.AddCommand(snapshot.Cmd)
 	rootCmd.AddCommand(export_json_transactions.Cmd)
 	rootCmd.AddCommand(read_hotstuff.RootCmd)
+	rootCmd.AddCommand(slashing_evidence.RootCmd)
+	rootCmd.AddCommand(read_flight_recordings.Cmd)
+	rootCmd.AddCommand(simulate_consensus.Cmd)
+	rootCmd.AddCommand(light_follower.Cmd)
+	rootCmd.AddCommand(tune_cruisectl.Cmd)
+	rootCmd.AddCommand(decode_network_capture.Cmd)
+	rootCmd.AddCommand(merge_connectivity_snapshots.Cmd)
 	rootCmd.AddCommand(addresses.Cmd)
 	rootCmd.AddCommand(bootstrap_execution_state_payloads.Cmd)
 	rootCmd.AddCommand(extractpayloads.Cmd)
@@ -268,6 +268,10 @@ func (*api) GetNodeVersionInfo(_ context.Context) (*accessmodel.NodeVersionInfo,
 	return nil, errors.New("unimplemented")
 }
 
+func (*api) GetEpochForecast(_ context.Context) (*accessmodel.EpochForecast, error) {
+	return nil, errors.New("unimplemented")
+}
+
 func (*api) GetLatestBlockHeader(_ context.Context, _ bool) (*flow.Header, flow.BlockStatus, error) {
 	return nil, flow.BlockStatusUnknown, errors.New("unimplemented")
 }
@@ -472,6 +476,14 @@ func (*api) GetProtocolStateSnapshotByHeight(_ context.Context, _ uint64) ([]byt
 	return nil, errors.New("unimplemented")
 }
 
+func (*api) GetProtocolStateAtHeight(_ context.Context, _ uint64) (*accessmodel.ProtocolStateAtHeight, error) {
+	return nil, errors.New("unimplemented")
+}
+
+func (*api) GetProtocolStateDiff(_ context.Context, _ uint64, _ uint64) (*accessmodel.ProtocolStateDiff, error) {
+	return nil, errors.New("unimplemented")
+}
+
 func (*api) GetExecutionResultForBlockID(_ context.Context, _ flow.Identifier) (*flow.ExecutionResult, error) {
 	return nil, errors.New("unimplemented")
 }
@@ -0,0 +1,94 @@
+package simulate_consensus
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/consensus/simulator"
+)
+
+var (
+	flagScenario      string
+	flagJSON          bool
+	flagPrintScenario bool
+	flagNodeLogLevel  string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "simulate-consensus",
+	Short: "run a network of consensus replicas according to a scenario and report liveness and safety",
+	Long: `Runs the given number of HotStuff consensus replicas within this process, over an in-memory
+network with the latencies, partitions, crashed and equivocating replicas and epoch switches
+described by the scenario file (JSON). Leaders delay their proposals by a static proposal duration,
+or by the block time controller if the scenario configures cruise control. Afterwards, the finalization rate, view durations,
+detected protocol violations and any safety violations are reported. The simulation runs in
+real time. Use --print-scenario to print the default scenario as a template. Exits with a
+non-zero status if conflicting blocks were finalized.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagScenario, "scenario", "", "path to the scenario file, the default scenario is used if empty")
+	Cmd.Flags().BoolVar(&flagJSON, "json", false, "print the report as JSON")
+	Cmd.Flags().BoolVar(&flagPrintScenario, "print-scenario", false, "print the scenario and exit")
+	Cmd.Flags().StringVar(&flagNodeLogLevel, "node-log-level", "error", "log level of the simulated replicas")
+}
+
+func run(*cobra.Command, []string) {
+	scenario := simulator.DefaultScenario()
+	if flagScenario != "" {
+		var err error
+		scenario, err = simulator.LoadScenario(flagScenario)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not load scenario")
+		}
+	}
+	if flagPrintScenario {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err := encoder.Encode(scenario)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not encode scenario")
+		}
+		return
+	}
+
+	level, err := zerolog.ParseLevel(flagNodeLogLevel)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid log level")
+	}
+	sim, err := simulator.New(log.Logger.Level(level), scenario)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create simulation")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	log.Info().Msgf("simulating %d replicas for %v", scenario.Nodes, time.Duration(scenario.Duration))
+	report, err := sim.Run(ctx)
+	if err != nil {
+		log.Fatal().Err(err).Msg("simulation failed")
+	}
+
+	if flagJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not encode report")
+		}
+	} else {
+		report.WriteText(os.Stdout)
+	}
+	if !report.Safe() {
+		log.Fatal().Msgf("%d safety violations", len(report.SafetyViolations))
+	}
+}
@@ -0,0 +1,50 @@
+package slashing_evidence
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation/badgerimpl"
+	"github.com/onflow/flow-go/storage/operation/pebbleimpl"
+	storagepebble "github.com/onflow/flow-go/storage/pebble"
+)
+
+var (
+	flagDatadir   string
+	flagPebbleDir string
+)
+
+var RootCmd = &cobra.Command{
+	Use:   "slashing-evidence",
+	Short: "export and verify evidence of slashable HotStuff violations",
+	Long: `Consensus and collection nodes persist the signed artifacts of slashable HotStuff violations
+(double votes, double proposals and double timeouts) in their protocol database.
+The export command writes the evidence to a JSON file, which can be verified independently of the
+node with the verify command.`,
+}
+
+func init() {
+	RootCmd.AddCommand(exportCmd)
+	RootCmd.AddCommand(verifyCmd)
+}
+
+// openProtocolDB opens the protocol database, which is either the badger database in the
+// data directory or the pebble database in the pebble directory.
+func openProtocolDB() (storage.DB, func() error) {
+	if flagPebbleDir != "" {
+		db, err := storagepebble.MustOpenDefaultPebbleDB(
+			log.Logger.With().Str("pebbledb", "protocol").Logger(), flagPebbleDir)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("could not open pebble database at %v", flagPebbleDir)
+		}
+		return pebbleimpl.ToDB(db), db.Close
+	}
+
+	if flagDatadir == "" {
+		log.Fatal().Msg("either --datadir or --pebble-dir is required")
+	}
+	db := common.InitStorage(flagDatadir)
+	return badgerimpl.ToDB(db), db.Close
+}
@@ -0,0 +1,119 @@
+package slashing_evidence
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/store"
+)
+
+// ExportVersion is the version of the export format.
+const ExportVersion = 1
+
+// Export is the content of an exported evidence file.
+type Export struct {
+	Version    int
+	ExportedAt time.Time
+	Evidence   []ExportedEvidence
+}
+
+// ExportedEvidence is a single evidence of an export, together with its ID and the
+// result of verifying it at the time of the export.
+type ExportedEvidence struct {
+	ID           flow.Identifier
+	Verification string
+	Evidence     *model.SlashingEvidence
+}
+
+var (
+	flagOutput   string
+	flagOffender string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export the persisted slashing evidence as JSON",
+	Run:   runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&flagDatadir, "datadir", "d", "", "directory of the badger protocol database")
+	exportCmd.Flags().StringVar(&flagPebbleDir, "pebble-dir", "", "directory of the pebble protocol database, if the node uses pebble")
+	exportCmd.Flags().StringVarP(&flagOutput, "output", "o", "", "file to write the evidence to, defaults to stdout")
+	exportCmd.Flags().StringVar(&flagOffender, "offender", "", "only export evidence against the node with the given ID")
+}
+
+func runExport(*cobra.Command, []string) {
+	var offender *flow.Identifier
+	if flagOffender != "" {
+		id, err := flow.HexStringToIdentifier(flagOffender)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid offender")
+		}
+		offender = &id
+	}
+
+	db, closeDB := openProtocolDB()
+	defer closeDB()
+
+	export, err := ExportSlashingEvidence(store.NewSlashingEvidence(db), offender)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not export slashing evidence")
+	}
+
+	var out io.Writer = os.Stdout
+	if flagOutput != "" {
+		file, err := os.Create(flagOutput)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not create output file")
+		}
+		defer file.Close()
+		out = file
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(export)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not write slashing evidence")
+	}
+
+	log.Info().Int("evidence", len(export.Evidence)).Msg("exported slashing evidence")
+}
+
+// ExportSlashingEvidence returns all stored evidence, optionally restricted to the given
+// offender, verified and ordered by view.
+// No errors are expected during normal operation.
+func ExportSlashingEvidence(evidence storage.SlashingEvidence, offender *flow.Identifier) (*Export, error) {
+	all, err := evidence.All()
+	if err != nil {
+		return nil, fmt.Errorf("could not read slashing evidence: %w", err)
+	}
+
+	export := &Export{
+		Version:    ExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Evidence:   make([]ExportedEvidence, 0, len(all)),
+	}
+	for _, e := range all {
+		if offender != nil && e.Offender != *offender {
+			continue
+		}
+		export.Evidence = append(export.Evidence, ExportedEvidence{
+			ID:           e.ID(),
+			Verification: verification.SlashingEvidenceStatus(e),
+			Evidence:     e,
+		})
+	}
+	return export, nil
+}
@@ -0,0 +1,93 @@
+package slashing_evidence
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/helper"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/local"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation/dbtest"
+	"github.com/onflow/flow-go/storage/store"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestExportVerifiesAfterRoundTrip tests that exported evidence can be verified after
+// decoding the JSON export.
+func TestExportVerifiesAfterRoundTrip(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		evidenceStore := store.NewSlashingEvidence(db)
+
+		stakingPriv := unittest.StakingPrivKeyFixture()
+		offender := unittest.IdentityFixture(unittest.WithRole(flow.RoleCollection), unittest.WithStakingPubKey(stakingPriv.PublicKey()))
+		me, err := local.New(offender.IdentitySkeleton, stakingPriv)
+		require.NoError(t, err)
+		signer := verification.NewStakingSigner(me)
+
+		view := uint64(50)
+		vote1, err := signer.CreateVote(helper.MakeBlock(helper.WithBlockView(view)))
+		require.NoError(t, err)
+		vote2, err := signer.CreateVote(helper.MakeBlock(helper.WithBlockView(view)))
+		require.NoError(t, err)
+		_, err = evidenceStore.Store(&model.SlashingEvidence{
+			Kind:             model.EvidenceDoubleVote,
+			View:             view,
+			Offender:         offender.NodeID,
+			OffenderIdentity: &offender.IdentitySkeleton,
+			Votes:            []*model.Vote{vote1, vote2},
+		})
+		require.NoError(t, err)
+
+		headers := make([]*flow.Header, 2)
+		blocks := make([]*model.Block, 2)
+		for i := range headers {
+			headers[i] = unittest.BlockHeaderFixture(unittest.HeaderWithView(view+1), func(header *flow.Header) {
+				header.ProposerID = offender.NodeID
+			})
+			vote, err := signer.CreateVote(model.BlockFromFlow(headers[i]))
+			require.NoError(t, err)
+			headers[i].ProposerSigData = vote.SigData
+			blocks[i] = model.BlockFromFlow(headers[i])
+		}
+		_, err = evidenceStore.Store(&model.SlashingEvidence{
+			Kind:             model.EvidenceDoubleProposal,
+			View:             view + 1,
+			Offender:         offender.NodeID,
+			OffenderIdentity: &offender.IdentitySkeleton,
+			Blocks:           blocks,
+			Headers:          headers,
+		})
+		require.NoError(t, err)
+
+		export, err := ExportSlashingEvidence(evidenceStore, nil)
+		require.NoError(t, err)
+		require.Len(t, export.Evidence, 2)
+
+		data, err := json.Marshal(export)
+		require.NoError(t, err)
+		var decoded Export
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		require.Len(t, decoded.Evidence, 2)
+
+		for _, e := range decoded.Evidence {
+			require.Equal(t, "verified", e.Verification)
+			require.NoError(t, VerifyExportedEvidence(e))
+		}
+
+		// evidence which does not match its exported ID is rejected
+		tampered := decoded.Evidence[0]
+		tampered.ID = unittest.IdentifierFixture()
+		require.Error(t, VerifyExportedEvidence(tampered))
+
+		// the export can be restricted to an offender
+		other := unittest.IdentifierFixture()
+		export, err = ExportSlashingEvidence(evidenceStore, &other)
+		require.NoError(t, err)
+		require.Empty(t, export.Evidence)
+	})
+}
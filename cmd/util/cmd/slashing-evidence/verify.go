@@ -0,0 +1,86 @@
+package slashing_evidence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+)
+
+var flagInput string
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "verify an exported slashing evidence file",
+	Long: `Verifies every evidence of an exported file using only the artifacts and keys included in the file.
+Evidence of equivocation (double votes, double timeouts and double proposals with signed headers) is
+verified cryptographically. Evidence of invalid artifacts is reported as not self-contained, as its
+validity depends on the protocol state. The keys included in the evidence must be checked against the
+identity table of the evidence's epoch separately.`,
+	Run: runVerify,
+}
+
+func init() {
+	verifyCmd.Flags().StringVarP(&flagInput, "input", "i", "", "exported slashing evidence file")
+	_ = verifyCmd.MarkFlagRequired("input")
+}
+
+func runVerify(*cobra.Command, []string) {
+	data, err := os.ReadFile(flagInput)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not read input file")
+	}
+
+	var export Export
+	err = json.Unmarshal(data, &export)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not decode input file")
+	}
+
+	invalid := 0
+	for _, e := range export.Evidence {
+		err := VerifyExportedEvidence(e)
+		entry := log.Info()
+		switch {
+		case err == nil:
+		case errors.Is(err, verification.ErrEvidenceNotSelfContained):
+			entry = log.Warn().Err(err)
+		default:
+			invalid++
+			entry = log.Error().Err(err)
+		}
+		entry.
+			Hex("evidence_id", e.ID[:]).
+			Str("kind", string(e.Evidence.Kind)).
+			Hex("offender", e.Evidence.Offender[:]).
+			Uint64("epoch", e.Evidence.Epoch).
+			Uint64("view", e.Evidence.View).
+			Msg("verified evidence")
+	}
+
+	if invalid > 0 {
+		log.Fatal().Int("invalid", invalid).Int("total", len(export.Evidence)).Msg("export contains invalid evidence")
+	}
+	log.Info().Int("total", len(export.Evidence)).Msg("verified export")
+}
+
+// VerifyExportedEvidence checks that the exported ID matches the evidence and verifies
+// the evidence.
+// Returns:
+//   - nil if the evidence proves the violation
+//   - verification.ErrEvidenceNotSelfContained if the evidence cannot be verified on its own
+//   - generic error if the evidence is invalid
+func VerifyExportedEvidence(e ExportedEvidence) error {
+	if e.Evidence == nil {
+		return fmt.Errorf("missing evidence")
+	}
+	if id := e.Evidence.ID(); id != e.ID {
+		return fmt.Errorf("evidence ID %v does not match the exported ID %v", id, e.ID)
+	}
+	return verification.VerifySlashingEvidence(e.Evidence)
+}
@@ -0,0 +1,356 @@
+package tune_cruisectl
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/consensus/hotstuff/cruisectl"
+)
+
+var (
+	flagSeries          string
+	flagEpochs          string
+	flagDatadir         string
+	flagFromHeight      uint64
+	flagToHeight        uint64
+	flagBaseline        string
+	flagCandidates      []string
+	flagNaturalDuration time.Duration
+	flagJSON            bool
+)
+
+var Cmd = &cobra.Command{
+	Use:   "tune-cruisectl",
+	Short: "replay recorded block timing through the block time controller with alternative configurations",
+	Long: `Replays a recorded series of block observations through the block time controller (cruisectl)
+and simulates the epoch switchover time error under alternative controller parameters and timing
+bounds, to validate a configuration before applying it with the set-config admin command.
+
+The series is read either from a CSV file (--series) with lines 'view,observed_unix_ms[,publication_delay_ms]',
+e.g. exported from the cruisectl metrics, or from the finalized blocks of a protocol database (--datadir),
+approximating the time a block was observed by its timestamp. The epoch timing is read from a JSON file
+(--epochs) with a list of objects with the fields first_view, final_view, target_duration and target_end_time,
+or from the protocol database.
+
+Configurations are given as comma-separated key=value pairs with the keys kp, ki, kd, n_ewma, n_itg,
+min (MinViewDuration) and max (MaxViewDuration), e.g. 'kp=2.0,ki=0.6,kd=3.0,max=1.2s'. Keys which
+are not given take the value of the default configuration. The baseline is the configuration
+active during the recording, and is used to estimate the natural view durations of the network.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagSeries, "series", "", "CSV file with the recorded block observations")
+	Cmd.Flags().StringVar(&flagEpochs, "epochs", "", "JSON file with the epoch timing, overrides the epochs read from the database")
+	Cmd.Flags().StringVarP(&flagDatadir, "datadir", "d", "", "directory of the protocol database to read the finalized blocks from")
+	Cmd.Flags().Uint64Var(&flagFromHeight, "from-height", 0, "first finalized height to read from the database")
+	Cmd.Flags().Uint64Var(&flagToHeight, "to-height", 0, "last finalized height to read from the database, defaults to the latest finalized height")
+	Cmd.Flags().StringVar(&flagBaseline, "baseline", "", "configuration active during the recording, defaults to the default configuration")
+	Cmd.Flags().StringArrayVar(&flagCandidates, "candidate", nil, "configuration to evaluate, can be repeated")
+	Cmd.Flags().DurationVar(&flagNaturalDuration, "natural-view-duration", 0, "view duration of the network when not delayed by the controller, defaults to the shortest recorded view duration")
+	Cmd.Flags().BoolVar(&flagJSON, "json", false, "print the results as JSON")
+}
+
+// result is the replay of a single configuration.
+type result struct {
+	Name   string                  `json:"name"`
+	Config string                  `json:"config"`
+	Result *cruisectl.ReplayResult `json:"result"`
+}
+
+func run(*cobra.Command, []string) {
+	var series []cruisectl.TimingSample
+	var epochs []cruisectl.EpochTiming
+	switch {
+	case flagSeries != "" && flagDatadir != "":
+		log.Fatal().Msg("only one of --series and --datadir can be given")
+	case flagSeries != "":
+		var err error
+		series, err = readSeries(flagSeries)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not read series")
+		}
+	case flagDatadir != "":
+		var err error
+		series, epochs, err = readDatabase(flagDatadir, flagFromHeight, flagToHeight)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not read series from database")
+		}
+	default:
+		log.Fatal().Msg("either --series or --datadir is required")
+	}
+	if flagEpochs != "" {
+		var err error
+		epochs, err = readEpochs(flagEpochs)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not read epochs")
+		}
+	}
+	if len(epochs) == 0 {
+		log.Fatal().Msg("epoch timing is required, use --epochs or --datadir")
+	}
+	series = cruisectl.SortSeries(series)
+	if len(series) < 2 {
+		log.Fatal().Msg("at least two observations are required")
+	}
+
+	baseline, err := parseConfig(flagBaseline)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid baseline")
+	}
+	throttled := flagNaturalDuration
+	if throttled == 0 {
+		throttled = shortestDuration(series)
+	}
+	natural, err := cruisectl.EstimateNaturalDurations(series, epochs, baseline, throttled)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not estimate natural view durations")
+	}
+
+	configs := append([]string{flagBaseline}, flagCandidates...)
+	results := make([]result, 0, len(configs))
+	for i, spec := range configs {
+		config, err := parseConfig(spec)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("invalid candidate %q", spec)
+		}
+		replay, err := cruisectl.Replay(series, natural, epochs, config)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("could not replay configuration %q", spec)
+		}
+		name := "baseline"
+		if i > 0 {
+			name = fmt.Sprintf("candidate %d", i)
+		}
+		results = append(results, result{Name: name, Config: formatConfig(config), Result: replay})
+	}
+
+	if flagJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err := encoder.Encode(results)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not encode results")
+		}
+		return
+	}
+	fmt.Printf("replayed %d observations (views %d to %d), natural view duration when throttled: %v\n\n",
+		len(series), series[0].View, series[len(series)-1].View, throttled)
+	writeTable(os.Stdout, results)
+}
+
+// writeTable writes the results as a table with one configuration per row.
+func writeTable(w io.Writer, results []result) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAME\tCONFIG\tDURATION\tFINAL ERR\tMEAN |ERR|\tRMS ERR\tMAX |ERR|\tSWITCHOVER ERR\tMEAN BLOCK TIME\tAT MIN\tAT MAX")
+	for _, r := range results {
+		switchovers := make([]string, 0, len(r.Result.SwitchoverErrors))
+		for _, s := range r.Result.SwitchoverErrors {
+			switchovers = append(switchovers, round(s.Error).String())
+		}
+		switchover := "-"
+		if len(switchovers) > 0 {
+			switchover = strings.Join(switchovers, " ")
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%v\t%v\t%v\t%v\t%v\t%s\t%v\t%d\t%d\n",
+			r.Name, r.Config, round(r.Result.Duration), round(r.Result.FinalProjectedError), round(r.Result.MeanAbsError),
+			round(r.Result.RMSError), round(r.Result.MaxAbsError), switchover, round(r.Result.MeanBlockTime),
+			r.Result.AtMinViewDuration, r.Result.AtMaxViewDuration)
+	}
+	_ = tw.Flush()
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Millisecond)
+}
+
+// shortestDuration returns the shortest time between consecutive observations of the series.
+func shortestDuration(series []cruisectl.TimingSample) time.Duration {
+	shortest := time.Duration(0)
+	for i := 1; i < len(series); i++ {
+		d := series[i].Observed.Sub(series[i-1].Observed)
+		if i == 1 || d < shortest {
+			shortest = d
+		}
+	}
+	return max(shortest, 0)
+}
+
+// parseConfig parses a configuration of comma-separated key=value pairs on top of the default
+// configuration.
+func parseConfig(spec string) (*cruisectl.Config, error) {
+	config := cruisectl.DefaultConfig()
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected key=value, got %q", pair)
+		}
+		var err error
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "kp":
+			config.KP, err = strconv.ParseFloat(value, 64)
+		case "ki":
+			config.KI, err = strconv.ParseFloat(value, 64)
+		case "kd":
+			config.KD, err = strconv.ParseFloat(value, 64)
+		case "n_ewma":
+			config.N_ewma, err = parseUint(value)
+		case "n_itg":
+			config.N_itg, err = parseUint(value)
+		case "min":
+			var d time.Duration
+			d, err = time.ParseDuration(value)
+			config.MinViewDuration.Store(d)
+		case "max":
+			var d time.Duration
+			d, err = time.ParseDuration(value)
+			config.MaxViewDuration.Store(d)
+		default:
+			return nil, fmt.Errorf("unknown key %q", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+	}
+	if config.N_ewma == 0 || config.N_itg == 0 {
+		return nil, fmt.Errorf("n_ewma and n_itg must be positive")
+	}
+	if config.MinViewDuration.Load() > config.MaxViewDuration.Load() {
+		return nil, fmt.Errorf("min view duration %v exceeds max view duration %v", config.MinViewDuration.Load(), config.MaxViewDuration.Load())
+	}
+	return config, nil
+}
+
+func parseUint(value string) (uint, error) {
+	v, err := strconv.ParseUint(value, 10, 32)
+	return uint(v), err
+}
+
+func formatConfig(config *cruisectl.Config) string {
+	return fmt.Sprintf("kp=%g,ki=%g,kd=%g,n_ewma=%d,n_itg=%d,min=%v,max=%v",
+		config.KP, config.KI, config.KD, config.N_ewma, config.N_itg, config.MinViewDuration.Load(), config.MaxViewDuration.Load())
+}
+
+// readSeries reads observations from a CSV file with lines 'view,observed_unix_ms[,publication_delay_ms]'.
+// Lines which do not start with a number, such as headers, are skipped.
+func readSeries(path string) ([]cruisectl.TimingSample, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("could not parse CSV: %w", err)
+	}
+	series := make([]cruisectl.TimingSample, 0, len(records))
+	for i, record := range records {
+		view, err := strconv.ParseUint(record[0], 10, 64)
+		if err != nil {
+			if i == 0 {
+				continue // header
+			}
+			return nil, fmt.Errorf("invalid view in line %d: %w", i+1, err)
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("missing observation time in line %d", i+1)
+		}
+		observed, err := strconv.ParseInt(record[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid observation time in line %d: %w", i+1, err)
+		}
+		sample := cruisectl.TimingSample{View: view, Observed: time.UnixMilli(observed), PublicationDelay: -1}
+		if len(record) > 2 && record[2] != "" {
+			delay, err := strconv.ParseInt(record[2], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid publication delay in line %d: %w", i+1, err)
+			}
+			sample.PublicationDelay = time.Duration(delay) * time.Millisecond
+		}
+		series = append(series, sample)
+	}
+	return series, nil
+}
+
+// readEpochs reads the epoch timing from a JSON file.
+func readEpochs(path string) ([]cruisectl.EpochTiming, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var epochs []cruisectl.EpochTiming
+	err = json.Unmarshal(data, &epochs)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode epochs: %w", err)
+	}
+	return epochs, nil
+}
+
+// readDatabase reads the view and timestamp of the finalized blocks in the given height range,
+// together with the timing of the epochs of the first and last block.
+func readDatabase(datadir string, fromHeight, toHeight uint64) ([]cruisectl.TimingSample, []cruisectl.EpochTiming, error) {
+	db := common.InitStorage(datadir)
+	defer db.Close()
+	storages := common.InitStorages(db)
+	state, err := common.InitProtocolState(db, storages)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if toHeight == 0 {
+		final, err := state.Final().Head()
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not get finalized block: %w", err)
+		}
+		toHeight = final.Height
+	}
+	if fromHeight >= toHeight {
+		return nil, nil, fmt.Errorf("from height %d must be below to height %d", fromHeight, toHeight)
+	}
+
+	series := make([]cruisectl.TimingSample, 0, toHeight-fromHeight+1)
+	for height := fromHeight; height <= toHeight; height++ {
+		header, err := storages.Headers.ByHeight(height)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not get block at height %d: %w", height, err)
+		}
+		series = append(series, cruisectl.TimingSample{View: header.View, Observed: header.Timestamp, PublicationDelay: -1})
+	}
+
+	first, err := state.AtHeight(fromHeight).Epochs().Current()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get epoch at height %d: %w", fromHeight, err)
+	}
+	last, err := state.AtHeight(toHeight).Epochs().Current()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get epoch at height %d: %w", toHeight, err)
+	}
+	epochs := []cruisectl.EpochTiming{cruisectl.EpochTimingOf(first)}
+	if last.Counter() != first.Counter() {
+		if last.Counter() != first.Counter()+1 {
+			return nil, nil, fmt.Errorf("height range spans more than two epochs, use --epochs to specify the epoch timing")
+		}
+		epochs = append(epochs, cruisectl.EpochTimingOf(last))
+	}
+	return series, epochs, nil
+}
@@ -57,6 +57,19 @@ func (epoch *epochTiming) targetViewTime() float64 {
 	return float64(epoch.targetDuration) / float64(epoch.finalView-epoch.firstView+1)
 }
 
+// switchoverError computes the instantaneous error e[v] = k[v]·τ - Γ[v] upon observing the block for view v
+// at time t[v], i.e. the projected difference between the epoch switchover time, assuming that we progress
+// through the remaining views with the idealized target view time τ, and the target switchover time. Returns:
+//   - k[v]: the number of views remaining in the epoch
+//   - Γ[v] = T[v] - t[v]: the time remaining until the target switchover time T[v]
+//   - e[v]: the instantaneous error in units of seconds
+func (epoch *epochTiming) switchoverError(view uint64, observed time.Time) (uint64, time.Duration, float64) {
+	viewDurationsRemaining := epoch.finalView + 1 - view                                            // k[v]: views remaining in current epoch
+	durationRemaining := unix2time(epoch.targetEndTime).Sub(observed)                               // Γ[v] = T[v] - t[v]
+	instErr := float64(viewDurationsRemaining)*epoch.targetViewTime() - durationRemaining.Seconds() // e[v] = k[v]·τ - Γ[v]
+	return viewDurationsRemaining, durationRemaining, instErr
+}
+
 // isFollowedBy determines whether nextEpoch is indeed the direct successor of the receiver,
 // based on the view ranges of both epochs.
 func (et *epochTiming) isFollowedBy(nextEpoch *epochTiming) bool {
@@ -369,19 +382,16 @@ func (ctl *BlockTimeController) measureViewDuration(tb TimedBlock) error {
 	// In accordance with this convention, observing the proposal for the last view of an epoch, marks the start of the last view.
 	// By observing the proposal, nodes enter the last view, verify the block, vote for it, the primary aggregates the votes,
 	// constructs the child (for first view of new epoch). The last view of the epoch ends, when the child proposal is published.
-	tau := ctl.currentEpochTiming.targetViewTime()                                            // τ: idealized target view time in units of seconds
-	viewDurationsRemaining := ctl.currentEpochTiming.finalView + 1 - view                     // k[v]: views remaining in current epoch
-	durationRemaining := unix2time(ctl.currentEpochTiming.targetEndTime).Sub(tb.TimeObserved) // Γ[v] = T[v] - t[v], with t[v] ≡ tb.TimeObserved the time when observing the block that triggered the view change
+	tau := ctl.currentEpochTiming.targetViewTime() // τ: idealized target view time in units of seconds
+	viewDurationsRemaining, durationRemaining, instErr := ctl.currentEpochTiming.switchoverError(view, tb.TimeObserved)
 
-	// Compute instantaneous error term: e[v] = k[v]·τ - T[v] i.e. the projected difference from target switchover
-	// and update PID controller's error terms. All UNITS in SECOND.
-	instErr := float64(viewDurationsRemaining)*tau - durationRemaining.Seconds()
+	// update PID controller's error terms with the instantaneous error. All UNITS in SECOND.
 	propErr := ctl.proportionalErr.AddObservation(instErr)
 	itgErr := ctl.integralErr.AddObservation(instErr)
 	drivErr := propErr - previousPropErr
 
 	// controller output u[v] in units of second
-	u := propErr*ctl.config.KP + itgErr*ctl.config.KI + drivErr*ctl.config.KD
+	u := ctl.config.output(propErr, itgErr, drivErr)
 
 	// compute the controller output for this observation
 	unconstrainedBlockTime := sec2dur(tau - u) // desired time between parent and child block, in units of seconds
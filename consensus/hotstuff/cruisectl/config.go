@@ -90,6 +90,12 @@ func (c *ControllerParams) beta() float64 {
 	return 1.0 / float64(c.N_itg)
 }
 
+// output returns the controller output u[v] in units of seconds for the given proportional,
+// integral and derivative error terms.
+func (c *ControllerParams) output(propErr, itgErr, drivErr float64) float64 {
+	return propErr*c.KP + itgErr*c.KI + drivErr*c.KD
+}
+
 // GetFallbackProposalDuration returns the proposal duration used when Cruise Control is not active.
 func (ctl TimingConfig) GetFallbackProposalDuration() time.Duration {
 	return ctl.FallbackProposalDelay.Load()
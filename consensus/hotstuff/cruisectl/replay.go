@@ -0,0 +1,305 @@
+package cruisectl
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/state/protocol"
+)
+
+// This file implements the offline replay of the BlockTimeController, which simulates the
+// controller with alternative parameters on a recorded series of block observations. The
+// replay uses the same error computation and limits of authority as the BlockTimeController.
+//
+// Since the recorded view durations were themselves shaped by the controller which was active
+// during the recording, the replay models the network as follows: the time between observing
+// a block and its child is the larger of
+//   - the block time targeted by the controller, and
+//   - the natural duration of the view, i.e. the time the committee needed to produce the child
+//     block without any delay imposed by the controller.
+//
+// The natural durations are derived from the recording by EstimateNaturalDurations.
+
+// TimingSample is the time at which a block was observed, e.g. as recorded by the block time
+// controller metrics or approximated by the block's timestamp.
+type TimingSample struct {
+	View     uint64
+	Observed time.Time
+	// PublicationDelay is the time the proposer of the block delayed its publication, as
+	// determined by the controller. Negative if unknown.
+	PublicationDelay time.Duration
+}
+
+// EpochTiming is the timing information of an epoch, as specified by its EpochSetup
+// event and any epoch extensions.
+type EpochTiming struct {
+	FirstView uint64 `json:"first_view"`
+	FinalView uint64 `json:"final_view"`
+	// TargetDuration is the desired total duration of the epoch in seconds.
+	TargetDuration uint64 `json:"target_duration"`
+	// TargetEndTime is the target end time of the epoch in Unix time [seconds].
+	TargetEndTime uint64 `json:"target_end_time"`
+}
+
+// EpochTimingOf returns the timing information of the given epoch.
+func EpochTimingOf(epoch protocol.CommittedEpoch) EpochTiming {
+	timing := newEpochTiming(epoch)
+	return EpochTiming{
+		FirstView:      timing.firstView,
+		FinalView:      timing.finalView,
+		TargetDuration: timing.targetDuration,
+		TargetEndTime:  timing.targetEndTime,
+	}
+}
+
+func (e EpochTiming) internal() *epochTiming {
+	return &epochTiming{
+		firstView:      e.FirstView,
+		finalView:      e.FinalView,
+		targetDuration: e.TargetDuration,
+		targetEndTime:  e.TargetEndTime,
+	}
+}
+
+// ReplayStep is the state of the controller after processing one observation.
+type ReplayStep struct {
+	View     uint64    `json:"view"`
+	Observed time.Time `json:"observed"`
+	// InstantaneousError is the projected epoch switchover time error e[v] in seconds.
+	InstantaneousError float64 `json:"instantaneous_error"`
+	ProportionalError  float64 `json:"proportional_error"`
+	IntegralError      float64 `json:"integral_error"`
+	DerivativeError    float64 `json:"derivative_error"`
+	// BlockTime is the time between this block and its child targeted by the controller, after
+	// applying the limits of authority.
+	BlockTime time.Duration `json:"block_time"`
+}
+
+// SwitchoverError is the difference between the time the first block of an epoch was observed
+// and the target end time of the preceding epoch. Positive values mean the switchover was late.
+type SwitchoverError struct {
+	FinalView uint64        `json:"final_view"`
+	Error     time.Duration `json:"error"`
+}
+
+// ReplayResult summarizes the replay of the controller on a series of observations.
+type ReplayResult struct {
+	Steps []ReplayStep `json:"-"`
+	// Duration is the time between the first and the last observation.
+	Duration time.Duration `json:"duration"`
+	// FinalProjectedError is the projected epoch switchover time error at the last observation.
+	FinalProjectedError time.Duration `json:"final_projected_error"`
+	// MeanAbsError, RMSError and MaxAbsError summarize the projected switchover time error over all observations.
+	MeanAbsError time.Duration `json:"mean_abs_error"`
+	RMSError     time.Duration `json:"rms_error"`
+	MaxAbsError  time.Duration `json:"max_abs_error"`
+	// SwitchoverErrors are the errors of the epoch switchovers within the series.
+	SwitchoverErrors []SwitchoverError `json:"switchover_errors"`
+	// MeanBlockTime is the mean of the block times targeted by the controller.
+	MeanBlockTime time.Duration `json:"mean_block_time"`
+	// AtMinViewDuration and AtMaxViewDuration count the observations for which the targeted
+	// block time was limited by MinViewDuration or MaxViewDuration respectively.
+	AtMinViewDuration int `json:"at_min_view_duration"`
+	AtMaxViewDuration int `json:"at_max_view_duration"`
+}
+
+// replayer is an offline instance of the PID controller of the BlockTimeController.
+type replayer struct {
+	config          *Config
+	epochs          []EpochTiming
+	proportionalErr Ewma
+	integralErr     LeakyIntegrator
+}
+
+func newReplayer(config *Config, epochs []EpochTiming) (*replayer, error) {
+	if len(epochs) == 0 {
+		return nil, fmt.Errorf("timing of at least one epoch is required")
+	}
+	for i := 1; i < len(epochs); i++ {
+		if !epochs[i-1].internal().isFollowedBy(epochs[i].internal()) {
+			return nil, fmt.Errorf("epoch with first view %d does not directly follow epoch with final view %d", epochs[i].FirstView, epochs[i-1].FinalView)
+		}
+	}
+	proportionalErr, err := NewEwma(config.alpha(), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize EWMA for computing the proportional error: %w", err)
+	}
+	integralErr, err := NewLeakyIntegrator(config.beta(), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize LeakyIntegrator for computing the integral error: %w", err)
+	}
+	return &replayer{
+		config:          config,
+		epochs:          epochs,
+		proportionalErr: proportionalErr,
+		integralErr:     integralErr,
+	}, nil
+}
+
+// observe processes the observation of the block for the given view, like
+// BlockTimeController.measureViewDuration.
+// Returns an error if the view is not within any of the epochs.
+func (r *replayer) observe(view uint64, observed time.Time) (ReplayStep, error) {
+	var epoch *epochTiming
+	for _, e := range r.epochs {
+		if view >= e.FirstView && view <= e.FinalView {
+			epoch = e.internal()
+			break
+		}
+	}
+	if epoch == nil {
+		return ReplayStep{}, fmt.Errorf("view %d is not within any of the given epochs", view)
+	}
+
+	previousPropErr := r.proportionalErr.Value()
+	tau := epoch.targetViewTime()
+	_, _, instErr := epoch.switchoverError(view, observed)
+	propErr := r.proportionalErr.AddObservation(instErr)
+	itgErr := r.integralErr.AddObservation(instErr)
+	drivErr := propErr - previousPropErr
+	u := r.config.output(propErr, itgErr, drivErr)
+
+	timing := newHappyPathBlockTime(TimedBlock{Block: &model.Block{View: view}, TimeObserved: observed}, sec2dur(tau-u), r.config.TimingConfig)
+	return ReplayStep{
+		View:               view,
+		Observed:           observed,
+		InstantaneousError: instErr,
+		ProportionalError:  propErr,
+		IntegralError:      itgErr,
+		DerivativeError:    drivErr,
+		BlockTime:          timing.ConstrainedBlockTime(),
+	}, nil
+}
+
+// EstimateNaturalDurations estimates the natural duration between each block of the series and
+// the next block, i.e. the time the committee needed to produce the next block without any delay
+// imposed by the controller:
+//   - if the publication delay of the next block is known, it is subtracted from the recorded duration
+//   - otherwise, the controller active during the recording (baseline) is replayed on the recorded
+//     observations. If the recorded duration exceeds the block time targeted by the baseline, the
+//     view was not delayed by the controller and the recorded duration is its natural duration.
+//     Otherwise, the view was delayed and its natural duration is assumed to be throttledDuration
+//     (at most the recorded duration).
+//
+// The returned slice has one element less than the series.
+// Returns an error if the series is not ordered by view or if a view is not within the epochs.
+func EstimateNaturalDurations(series []TimingSample, epochs []EpochTiming, baseline *Config, throttledDuration time.Duration) ([]time.Duration, error) {
+	r, err := newReplayer(baseline, epochs)
+	if err != nil {
+		return nil, err
+	}
+	natural := make([]time.Duration, 0, len(series))
+	for i := 0; i+1 < len(series); i++ {
+		sample, next := series[i], series[i+1]
+		if next.View <= sample.View {
+			return nil, fmt.Errorf("series is not ordered by increasing view at view %d", next.View)
+		}
+		step, err := r.observe(sample.View, sample.Observed)
+		if err != nil {
+			return nil, err
+		}
+		recorded := next.Observed.Sub(sample.Observed)
+		switch {
+		case next.PublicationDelay >= 0:
+			natural = append(natural, max(recorded-next.PublicationDelay, 0))
+		case recorded > step.BlockTime:
+			natural = append(natural, recorded)
+		default:
+			natural = append(natural, min(recorded, throttledDuration))
+		}
+	}
+	return natural, nil
+}
+
+// Replay simulates the controller with the given configuration, starting at the first
+// observation of the series. The time between observing a block of the series and the next
+// block is the larger of the block time targeted by the controller and the natural duration
+// (see EstimateNaturalDurations).
+// Returns an error if the series is not ordered by view, if a view is not within the epochs
+// or if the configuration is invalid.
+func Replay(series []TimingSample, natural []time.Duration, epochs []EpochTiming, config *Config) (*ReplayResult, error) {
+	if len(series) == 0 {
+		return nil, fmt.Errorf("empty series")
+	}
+	if len(natural) != len(series)-1 {
+		return nil, fmt.Errorf("expected %d natural durations, got %d", len(series)-1, len(natural))
+	}
+	r, err := newReplayer(config, epochs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReplayResult{Steps: make([]ReplayStep, 0, len(series))}
+	observed := series[0].Observed
+	var sumAbs, sumSquares, maxAbs float64
+	var totalBlockTime time.Duration
+	for i, sample := range series {
+		if i > 0 && sample.View <= series[i-1].View {
+			return nil, fmt.Errorf("series is not ordered by increasing view at view %d", sample.View)
+		}
+		// record the switchover when observing the first block beyond the final view of an epoch
+		for _, epoch := range epochs {
+			if i > 0 && series[i-1].View <= epoch.FinalView && sample.View > epoch.FinalView {
+				result.SwitchoverErrors = append(result.SwitchoverErrors, SwitchoverError{
+					FinalView: epoch.FinalView,
+					Error:     observed.Sub(unix2time(epoch.TargetEndTime)),
+				})
+			}
+		}
+
+		step, err := r.observe(sample.View, observed)
+		if err != nil {
+			return nil, err
+		}
+		result.Steps = append(result.Steps, step)
+
+		absErr := math.Abs(step.InstantaneousError)
+		sumAbs += absErr
+		sumSquares += step.InstantaneousError * step.InstantaneousError
+		maxAbs = math.Max(maxAbs, absErr)
+		totalBlockTime += step.BlockTime
+		if step.BlockTime <= config.MinViewDuration.Load() {
+			result.AtMinViewDuration++
+		}
+		if step.BlockTime >= config.MaxViewDuration.Load() {
+			result.AtMaxViewDuration++
+		}
+
+		if i < len(natural) {
+			observed = observed.Add(max(step.BlockTime, natural[i]))
+		}
+	}
+
+	n := float64(len(result.Steps))
+	last := result.Steps[len(result.Steps)-1]
+	result.Duration = last.Observed.Sub(series[0].Observed)
+	result.FinalProjectedError = sec2dur(last.InstantaneousError)
+	result.MeanAbsError = sec2dur(sumAbs / n)
+	result.RMSError = sec2dur(math.Sqrt(sumSquares / n))
+	result.MaxAbsError = sec2dur(maxAbs)
+	result.MeanBlockTime = totalBlockTime / time.Duration(len(result.Steps))
+	return result, nil
+}
+
+// SortSeries orders the samples by view and removes samples with duplicate views, retaining
+// the earliest observation of each view.
+func SortSeries(series []TimingSample) []TimingSample {
+	sorted := append([]TimingSample{}, series...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].View != sorted[j].View {
+			return sorted[i].View < sorted[j].View
+		}
+		return sorted[i].Observed.Before(sorted[j].Observed)
+	})
+	deduplicated := sorted[:0]
+	for _, sample := range sorted {
+		if len(deduplicated) > 0 && deduplicated[len(deduplicated)-1].View == sample.View {
+			continue
+		}
+		deduplicated = append(deduplicated, sample)
+	}
+	return deduplicated
+}
@@ -0,0 +1,111 @@
+package cruisectl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replayEpochs returns two consecutive epochs of 600 views each, targeting one view per second.
+// The first epoch starts at the given time.
+func replayEpochs(start time.Time) []EpochTiming {
+	return []EpochTiming{
+		{FirstView: 0, FinalView: 599, TargetDuration: 600, TargetEndTime: uint64(start.Unix()) + 600},
+		{FirstView: 600, FinalView: 1199, TargetDuration: 600, TargetEndTime: uint64(start.Unix()) + 1200},
+	}
+}
+
+// replaySeries returns the given number of consecutive views, observed at the given time.
+func replaySeries(start time.Time, views int) []TimingSample {
+	series := make([]TimingSample, 0, views)
+	for view := 0; view < views; view++ {
+		series = append(series, TimingSample{View: uint64(view), Observed: start, PublicationDelay: -1})
+	}
+	return series
+}
+
+// TestReplay_ReproducesRecording verifies that replaying the controller which produced a
+// recording, with the natural durations estimated from the recording, reproduces the recording.
+func TestReplay_ReproducesRecording(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	epochs := replayEpochs(start)
+	naturalDuration := 700 * time.Millisecond
+	natural := make([]time.Duration, 899)
+	for i := range natural {
+		natural[i] = naturalDuration
+	}
+
+	// produce a recording by simulating the default controller, with authority to slow down to the target view time
+	baseline := DefaultConfig()
+	baseline.MaxViewDuration.Store(2 * time.Second)
+	recording, err := Replay(replaySeries(start, 900), natural, epochs, baseline)
+	require.NoError(t, err)
+	series := make([]TimingSample, 0, len(recording.Steps))
+	for _, step := range recording.Steps {
+		series = append(series, TimingSample{View: step.View, Observed: step.Observed, PublicationDelay: -1})
+	}
+
+	estimated, err := EstimateNaturalDurations(series, epochs, baseline, naturalDuration)
+	require.NoError(t, err)
+	assert.Equal(t, natural, estimated)
+
+	replayed, err := Replay(series, estimated, epochs, baseline)
+	require.NoError(t, err)
+	assert.Equal(t, recording.Steps, replayed.Steps)
+	require.Len(t, replayed.SwitchoverErrors, 1)
+	assert.Equal(t, uint64(599), replayed.SwitchoverErrors[0].FinalView)
+	assert.Less(t, replayed.SwitchoverErrors[0].Error.Abs(), 5*time.Second)
+
+	// a controller without authority to slow down the views runs ahead of the schedule
+	candidate := DefaultConfig()
+	candidate.MaxViewDuration.Store(candidate.MinViewDuration.Load())
+	result, err := Replay(series, estimated, epochs, candidate)
+	require.NoError(t, err)
+	assert.Equal(t, len(series), result.AtMinViewDuration)
+	assert.Equal(t, naturalDuration, result.Steps[1].Observed.Sub(result.Steps[0].Observed))
+	assert.Less(t, result.SwitchoverErrors[0].Error, replayed.SwitchoverErrors[0].Error)
+}
+
+// TestEstimateNaturalDurations_PublicationDelay verifies that known publication delays are
+// subtracted from the recorded view durations.
+func TestEstimateNaturalDurations_PublicationDelay(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	series := []TimingSample{
+		{View: 10, Observed: start, PublicationDelay: -1},
+		{View: 11, Observed: start.Add(time.Second), PublicationDelay: 300 * time.Millisecond},
+		{View: 13, Observed: start.Add(3 * time.Second), PublicationDelay: 0},
+	}
+	natural, err := EstimateNaturalDurations(series, replayEpochs(start), DefaultConfig(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{700 * time.Millisecond, 2 * time.Second}, natural)
+}
+
+// TestReplay_InvalidInputs verifies that inconsistent inputs are rejected.
+func TestReplay_InvalidInputs(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	epochs := replayEpochs(start)
+	series := replaySeries(start, 3)
+	natural := []time.Duration{time.Second, time.Second}
+
+	_, err := Replay(nil, nil, epochs, DefaultConfig())
+	assert.Error(t, err)
+	_, err = Replay(series, natural[:1], epochs, DefaultConfig())
+	assert.Error(t, err)
+	_, err = Replay(series, natural, nil, DefaultConfig())
+	assert.Error(t, err)
+	_, err = Replay(series, natural, []EpochTiming{epochs[1]}, DefaultConfig())
+	assert.Error(t, err) // views not within the epochs
+	_, err = Replay(series, natural, []EpochTiming{epochs[1], epochs[0]}, DefaultConfig())
+	assert.Error(t, err) // epochs not consecutive
+	invalid := DefaultConfig()
+	invalid.N_ewma = 0
+	_, err = Replay(series, natural, epochs, invalid)
+	assert.Error(t, err)
+
+	unordered := []TimingSample{series[1], series[0], series[2]}
+	_, err = Replay(unordered, natural, epochs, DefaultConfig())
+	assert.Error(t, err)
+	assert.Equal(t, series, SortSeries(append(unordered, series[2])))
+}
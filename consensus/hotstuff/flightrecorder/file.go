@@ -0,0 +1,273 @@
+package flightrecorder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	// FileExtension is the extension of flight recording files.
+	FileExtension = ".flight"
+
+	fileMagic   = "FLTR"
+	fileVersion = 1
+
+	// maxRecordLen bounds the length of a single encoded record, larger length prefixes
+	// indicate a corrupt file.
+	maxRecordLen = 1024
+)
+
+// fileHeaderLen is the length of the header at the start of every recording file:
+// magic | version u8 | node ID
+var fileHeaderLen = len(fileMagic) + 1 + flow.IdentifierLen
+
+// rotatingWriter writes records to a sequence of files in a directory. A new file is started
+// once the current file exceeds maxFileBytes, and the oldest files are removed once there
+// are more than maxFiles. Files are named by their zero-padded sequence number, so that
+// their lexicographic order is the order in which they were written.
+//
+// Not concurrency safe.
+type rotatingWriter struct {
+	dir          string
+	nodeID       flow.Identifier
+	maxFileBytes int64
+	maxFiles     int
+
+	seq     uint64
+	file    *os.File
+	buf     *bufio.Writer
+	written int64
+	scratch []byte
+}
+
+// newRotatingWriter creates the directory if necessary and starts a new recording file, which
+// follows any files already present in the directory.
+// No errors are expected during normal operation.
+func newRotatingWriter(dir string, nodeID flow.Identifier, maxFileBytes int64, maxFiles int) (*rotatingWriter, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("could not create recording directory %s: %w", dir, err)
+	}
+	seqs, err := listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &rotatingWriter{
+		dir:          dir,
+		nodeID:       nodeID,
+		maxFileBytes: maxFileBytes,
+		maxFiles:     maxFiles,
+	}
+	if len(seqs) > 0 {
+		w.seq = seqs[len(seqs)-1]
+	}
+	err = w.rotate()
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write appends the record to the current file, starting a new file if the current file is full.
+// No errors are expected during normal operation.
+func (w *rotatingWriter) Write(r *Record) error {
+	if w.written >= w.maxFileBytes {
+		err := w.rotate()
+		if err != nil {
+			return err
+		}
+	}
+	w.scratch = appendRecord(w.scratch[:0], r)
+	n, err := w.buf.Write(w.scratch)
+	w.written += int64(n)
+	if err != nil {
+		return fmt.Errorf("could not write record: %w", err)
+	}
+	return nil
+}
+
+// Flush writes buffered records to the current file.
+// No errors are expected during normal operation.
+func (w *rotatingWriter) Flush() error {
+	err := w.buf.Flush()
+	if err != nil {
+		return fmt.Errorf("could not flush records: %w", err)
+	}
+	return nil
+}
+
+// Close flushes buffered records and closes the current file.
+// No errors are expected during normal operation.
+func (w *rotatingWriter) Close() error {
+	err := w.Flush()
+	if err != nil {
+		_ = w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// rotate closes the current file, if any, starts the next file and removes the oldest files
+// exceeding the retention limit.
+func (w *rotatingWriter) rotate() error {
+	if w.file != nil {
+		err := w.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	w.seq++
+	file, err := os.OpenFile(filepath.Join(w.dir, fileName(w.seq)), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("could not create recording file: %w", err)
+	}
+	w.file = file
+	w.buf = bufio.NewWriter(file)
+
+	header := make([]byte, 0, fileHeaderLen)
+	header = append(header, fileMagic...)
+	header = append(header, fileVersion)
+	header = append(header, w.nodeID[:]...)
+	_, err = w.buf.Write(header)
+	if err != nil {
+		return fmt.Errorf("could not write file header: %w", err)
+	}
+	w.written = int64(len(header))
+
+	seqs, err := listFiles(w.dir)
+	if err != nil {
+		return err
+	}
+	for len(seqs) > w.maxFiles {
+		err = os.Remove(filepath.Join(w.dir, fileName(seqs[0])))
+		if err != nil {
+			return fmt.Errorf("could not remove old recording file: %w", err)
+		}
+		seqs = seqs[1:]
+	}
+	return nil
+}
+
+func fileName(seq uint64) string {
+	return fmt.Sprintf("%020d%s", seq, FileExtension)
+}
+
+// listFiles returns the sequence numbers of the recording files in the directory, in ascending order.
+func listFiles(dir string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read recording directory %s: %w", dir, err)
+	}
+	var seqs []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, FileExtension) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, FileExtension), 10, 64)
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	return seqs, nil
+}
+
+// Recording is the sequence of records captured by a single node.
+type Recording struct {
+	NodeID  flow.Identifier
+	Records []*Record
+}
+
+// ReadRecording reads all recording files of a node from the given directory. A truncated record
+// at the end of a file, which is left behind if the node crashed, is ignored.
+// No errors are expected for a directory written by the flight recorder.
+func ReadRecording(dir string) (*Recording, error) {
+	seqs, err := listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(seqs) == 0 {
+		return nil, fmt.Errorf("no recording files in %s", dir)
+	}
+
+	recording := &Recording{}
+	for i, seq := range seqs {
+		path := filepath.Join(dir, fileName(seq))
+		nodeID, records, err := readFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read recording file %s: %w", path, err)
+		}
+		if i == 0 {
+			recording.NodeID = nodeID
+		} else if nodeID != recording.NodeID {
+			return nil, fmt.Errorf("recording file %s was written by node %v, expected %v", path, nodeID, recording.NodeID)
+		}
+		recording.Records = append(recording.Records, records...)
+	}
+	return recording, nil
+}
+
+// readFile reads the node ID and all complete records from a single recording file.
+func readFile(path string) (flow.Identifier, []*Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return flow.ZeroID, nil, err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+
+	header := make([]byte, fileHeaderLen)
+	_, err = io.ReadFull(reader, header)
+	if err != nil {
+		return flow.ZeroID, nil, fmt.Errorf("could not read file header: %w", err)
+	}
+	if !bytes.Equal(header[:len(fileMagic)], []byte(fileMagic)) {
+		return flow.ZeroID, nil, fmt.Errorf("not a flight recording")
+	}
+	if version := header[len(fileMagic)]; version != fileVersion {
+		return flow.ZeroID, nil, fmt.Errorf("unsupported recording version %d", version)
+	}
+	nodeID := flow.HashToID(header[len(fileMagic)+1:])
+
+	var records []*Record
+	for {
+		length, err := binary.ReadUvarint(reader)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nodeID, records, nil
+			}
+			return flow.ZeroID, nil, fmt.Errorf("could not read record length: %w", err)
+		}
+		if length > maxRecordLen {
+			return flow.ZeroID, nil, fmt.Errorf("record of length %d at index %d: %w", length, len(records), errCorruptRecord)
+		}
+		body := make([]byte, length)
+		_, err = io.ReadFull(reader, body)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nodeID, records, nil
+			}
+			return flow.ZeroID, nil, fmt.Errorf("could not read record: %w", err)
+		}
+		record, err := decodeRecord(body)
+		if err != nil {
+			return flow.ZeroID, nil, fmt.Errorf("could not decode record at index %d: %w", len(records), err)
+		}
+		records = append(records, record)
+	}
+}
@@ -0,0 +1,243 @@
+package flightrecorder
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// EventType is the type of a view-level HotStuff event captured by the flight recorder.
+type EventType uint8
+
+const (
+	// EventStarted is recorded when the event handler starts. View is the current view.
+	EventStarted EventType = iota + 1
+	// EventViewEnteredQC is recorded when a QC advances the local view. View is the new view,
+	// AuxView and BlockID identify the QC.
+	EventViewEnteredQC
+	// EventViewEnteredTC is recorded when a TC advances the local view. View is the new view,
+	// AuxView is the view of the TC.
+	EventViewEnteredTC
+	// EventTimerStarted is recorded when the pacemaker starts the timeout timer for a view.
+	// AuxTime is the time at which the view times out.
+	EventTimerStarted
+	// EventViewDetails is recorded when the event handler enters a view. AuxView is the
+	// finalized view and NodeID the leader of the view.
+	EventViewDetails
+	// EventProposalReceived is recorded when the event handler processes a proposal.
+	// NodeID is the proposer.
+	EventProposalReceived
+	// EventProposalSent is recorded when the node publishes its own proposal. AuxTime is the
+	// publication time targeted by the block time controller.
+	EventProposalSent
+	// EventBlockIncorporated is recorded when a block is added to the local forks.
+	EventBlockIncorporated
+	// EventBlockFinalized is recorded when a block is finalized.
+	EventBlockFinalized
+	// EventVoteSent is recorded when the node sends its vote. NodeID is the recipient.
+	EventVoteSent
+	// EventVoteReceived is recorded when the vote aggregator processes a valid vote.
+	// NodeID is the signer.
+	EventVoteReceived
+	// EventQcFormed is recorded when the vote aggregator constructs a QC from votes.
+	EventQcFormed
+	// EventQcReceived is recorded when the event handler processes a QC, which
+	// was either constructed locally or discovered in a timeout object.
+	EventQcReceived
+	// EventTcReceived is recorded when the event handler processes a TC.
+	// AuxView is the view of the newest QC included in the TC.
+	EventTcReceived
+	// EventLocalTimeout is recorded when the local timeout for the current view fires.
+	EventLocalTimeout
+	// EventTimeoutSent is recorded when the node broadcasts its timeout object. AuxView is the
+	// view of the newest QC included in the timeout.
+	EventTimeoutSent
+	// EventTimeoutReceived is recorded when the timeout aggregator processes a valid timeout
+	// object. NodeID is the signer, AuxView the view of the newest QC.
+	EventTimeoutReceived
+	// EventPartialTcFormed is recorded when the timeout aggregator has collected timeouts from
+	// more than a third of the committee's weight.
+	EventPartialTcFormed
+	// EventTcFormed is recorded when the timeout aggregator constructs a TC. AuxView is the view
+	// of the newest QC included in the TC.
+	EventTcFormed
+)
+
+var eventTypeNames = map[EventType]string{
+	EventStarted:           "started",
+	EventViewEnteredQC:     "view_entered_qc",
+	EventViewEnteredTC:     "view_entered_tc",
+	EventTimerStarted:      "timer_started",
+	EventViewDetails:       "view_details",
+	EventProposalReceived:  "proposal_received",
+	EventProposalSent:      "proposal_sent",
+	EventBlockIncorporated: "block_incorporated",
+	EventBlockFinalized:    "block_finalized",
+	EventVoteSent:          "vote_sent",
+	EventVoteReceived:      "vote_received",
+	EventQcFormed:          "qc_formed",
+	EventQcReceived:        "qc_received",
+	EventTcReceived:        "tc_received",
+	EventLocalTimeout:      "local_timeout",
+	EventTimeoutSent:       "timeout_sent",
+	EventTimeoutReceived:   "timeout_received",
+	EventPartialTcFormed:   "partial_tc_formed",
+	EventTcFormed:          "tc_formed",
+}
+
+func (t EventType) String() string {
+	name, ok := eventTypeNames[t]
+	if !ok {
+		return fmt.Sprintf("unknown_%d", uint8(t))
+	}
+	return name
+}
+
+// IsViewEntered returns true if the event marks the node entering a new view.
+func (t EventType) IsViewEntered() bool {
+	return t == EventStarted || t == EventViewEnteredQC || t == EventViewEnteredTC
+}
+
+// Record is a single event captured by the flight recorder. Which of the optional fields are
+// set depends on the event type, unset fields are zero.
+type Record struct {
+	Type EventType
+	Time time.Time
+	// View is the view the event refers to, e.g. the view of a vote or the view entered.
+	View uint64
+	// CurView is the local view at the time the event was processed, if relevant.
+	CurView uint64
+	BlockID flow.Identifier
+	// NodeID is the other node involved in the event, e.g. the proposer, signer or recipient.
+	NodeID  flow.Identifier
+	AuxView uint64
+	AuxTime time.Time
+}
+
+// flags of the optional fields of an encoded record
+const (
+	fieldCurView uint8 = 1 << iota
+	fieldBlockID
+	fieldNodeID
+	fieldAuxView
+	fieldAuxTime
+)
+
+var errCorruptRecord = errors.New("corrupt flight record")
+
+// appendRecord appends the compact binary encoding of the record to the buffer. Records are
+// length-prefixed and omit zero-valued optional fields:
+//
+//	length uvarint | type u8 | fields u8 | time varint | view uvarint | [cur view] [block ID] [node ID] [aux view] [aux time]
+func appendRecord(buf []byte, r *Record) []byte {
+	var fields uint8
+	if r.CurView != 0 {
+		fields |= fieldCurView
+	}
+	if r.BlockID != flow.ZeroID {
+		fields |= fieldBlockID
+	}
+	if r.NodeID != flow.ZeroID {
+		fields |= fieldNodeID
+	}
+	if r.AuxView != 0 {
+		fields |= fieldAuxView
+	}
+	if !r.AuxTime.IsZero() {
+		fields |= fieldAuxTime
+	}
+
+	body := make([]byte, 0, 2+2*binary.MaxVarintLen64+2*flow.IdentifierLen+3*binary.MaxVarintLen64)
+	body = append(body, byte(r.Type), fields)
+	body = binary.AppendVarint(body, r.Time.UnixNano())
+	body = binary.AppendUvarint(body, r.View)
+	if fields&fieldCurView != 0 {
+		body = binary.AppendUvarint(body, r.CurView)
+	}
+	if fields&fieldBlockID != 0 {
+		body = append(body, r.BlockID[:]...)
+	}
+	if fields&fieldNodeID != 0 {
+		body = append(body, r.NodeID[:]...)
+	}
+	if fields&fieldAuxView != 0 {
+		body = binary.AppendUvarint(body, r.AuxView)
+	}
+	if fields&fieldAuxTime != 0 {
+		body = binary.AppendVarint(body, r.AuxTime.UnixNano())
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(body)))
+	return append(buf, body...)
+}
+
+// decodeRecord decodes the body of a record, without the length prefix.
+// Expected errors:
+//   - errCorruptRecord if the body is malformed
+func decodeRecord(body []byte) (*Record, error) {
+	if len(body) < 2 {
+		return nil, errCorruptRecord
+	}
+	r := &Record{Type: EventType(body[0])}
+	fields := body[1]
+	rest := body[2:]
+
+	readUvarint := func() uint64 {
+		v, n := binary.Uvarint(rest)
+		if n <= 0 {
+			rest = nil
+			return 0
+		}
+		rest = rest[n:]
+		return v
+	}
+	readTime := func() (time.Time, bool) {
+		v, n := binary.Varint(rest)
+		if n <= 0 {
+			return time.Time{}, false
+		}
+		rest = rest[n:]
+		return time.Unix(0, v).UTC(), true
+	}
+	readID := func(id *flow.Identifier) bool {
+		if len(rest) < flow.IdentifierLen {
+			return false
+		}
+		copy(id[:], rest[:flow.IdentifierLen])
+		rest = rest[flow.IdentifierLen:]
+		return true
+	}
+
+	var ok bool
+	if r.Time, ok = readTime(); !ok {
+		return nil, errCorruptRecord
+	}
+	if r.View = readUvarint(); rest == nil {
+		return nil, errCorruptRecord
+	}
+	if fields&fieldCurView != 0 {
+		if r.CurView = readUvarint(); rest == nil {
+			return nil, errCorruptRecord
+		}
+	}
+	if fields&fieldBlockID != 0 && !readID(&r.BlockID) {
+		return nil, errCorruptRecord
+	}
+	if fields&fieldNodeID != 0 && !readID(&r.NodeID) {
+		return nil, errCorruptRecord
+	}
+	if fields&fieldAuxView != 0 {
+		if r.AuxView = readUvarint(); rest == nil {
+			return nil, errCorruptRecord
+		}
+	}
+	if fields&fieldAuxTime != 0 {
+		if r.AuxTime, ok = readTime(); !ok {
+			return nil, errCorruptRecord
+		}
+	}
+	return r, nil
+}
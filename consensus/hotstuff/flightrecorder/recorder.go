@@ -0,0 +1,261 @@
+package flightrecorder
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+)
+
+// Config configures the flight recorder.
+type Config struct {
+	// Dir is the directory the recording files are written to.
+	Dir string
+	// MaxFileBytes is the size after which a new recording file is started.
+	MaxFileBytes int64
+	// MaxFiles is the number of recording files retained, older files are removed.
+	MaxFiles int
+	// BufferSize is the number of records buffered for writing. Records are dropped
+	// while the buffer is full.
+	BufferSize int
+}
+
+// DefaultConfig returns the default configuration, which retains up to 512 MiB of
+// recordings. At roughly 50 bytes per record, this covers several days of views.
+func DefaultConfig(dir string) Config {
+	return Config{
+		Dir:          dir,
+		MaxFileBytes: 64 * 1024 * 1024,
+		MaxFiles:     8,
+		BufferSize:   10_000,
+	}
+}
+
+// Recorder is an implementation of the notifications consumer that captures every view-level
+// event of the local HotStuff instance in a compact binary format: entering views, proposals,
+// votes and timeouts sent and received, QCs and TCs formed, as well as the timeout and the
+// proposal publication time targeted by the pacemaker and the block time controller.
+// Recordings of several nodes can be merged with Merge to reconstruct per-view timelines
+// and the critical path of each view.
+//
+// Notifications are buffered and written to a rotating set of files in the background, so
+// the recorder never blocks HotStuff. Records are dropped while the buffer is full.
+type Recorder struct {
+	component.Component
+	notifications.NoopProposalViolationConsumer
+
+	log     zerolog.Logger
+	writer  *rotatingWriter
+	records chan *Record
+	dropped *atomic.Uint64
+	// failed is set after a write error, after which records are discarded
+	failed bool
+}
+
+var _ hotstuff.Consumer = (*Recorder)(nil)
+var _ hotstuff.VoteCollectorConsumer = (*Recorder)(nil)
+var _ hotstuff.TimeoutCollectorConsumer = (*Recorder)(nil)
+var _ component.Component = (*Recorder)(nil)
+
+// NewRecorder creates a flight recorder for the node with the given ID, which starts a new
+// recording file in the configured directory.
+// No errors are expected during normal operation.
+func NewRecorder(log zerolog.Logger, nodeID flow.Identifier, config Config) (*Recorder, error) {
+	writer, err := newRotatingWriter(config.Dir, nodeID, config.MaxFileBytes, config.MaxFiles)
+	if err != nil {
+		return nil, err
+	}
+	r := &Recorder{
+		log:     log.With().Str("hotstuff", "flight_recorder").Logger(),
+		writer:  writer,
+		records: make(chan *Record, config.BufferSize),
+		dropped: atomic.NewUint64(0),
+	}
+	r.Component = component.NewComponentManagerBuilder().
+		AddWorker(r.writeRecordsWorkerLogic).
+		Build()
+	return r, nil
+}
+
+// Dropped returns the number of records dropped because the buffer was full.
+func (r *Recorder) Dropped() uint64 {
+	return r.dropped.Load()
+}
+
+// record queues the record for writing, or drops it if the buffer is full.
+func (r *Recorder) record(rec *Record) {
+	rec.Time = time.Now().UTC()
+	select {
+	case r.records <- rec:
+	default:
+		r.dropped.Inc()
+	}
+}
+
+// writeRecordsWorkerLogic writes queued records to the recording files and flushes them
+// periodically. On shutdown, the remaining queued records are written before the file is closed.
+// This method should be executed by a single worker routine.
+func (r *Recorder) writeRecordsWorkerLogic(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	ready()
+
+	flushTicker := time.NewTicker(time.Second)
+	defer flushTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.drain()
+			err := r.writer.Close()
+			if err != nil {
+				r.log.Error().Err(err).Msg("could not close recording file")
+			}
+			return
+		case rec := <-r.records:
+			r.write(rec)
+		case <-flushTicker.C:
+			if r.failed {
+				continue
+			}
+			err := r.writer.Flush()
+			if err != nil {
+				r.fail(err)
+			}
+		}
+	}
+}
+
+// drain writes all records currently queued.
+func (r *Recorder) drain() {
+	for {
+		select {
+		case rec := <-r.records:
+			r.write(rec)
+		default:
+			return
+		}
+	}
+}
+
+func (r *Recorder) write(rec *Record) {
+	if r.failed {
+		return
+	}
+	err := r.writer.Write(rec)
+	if err != nil {
+		r.fail(err)
+	}
+}
+
+// fail disables the recorder after a write error. The recorder is a diagnostic tool, so
+// failing to write recordings must not interrupt consensus.
+func (r *Recorder) fail(err error) {
+	r.failed = true
+	r.log.Error().Err(err).Msg("could not write flight recording, disabling flight recorder")
+}
+
+func (r *Recorder) OnEventProcessed() {}
+
+func (r *Recorder) OnStart(currentView uint64) {
+	r.record(&Record{Type: EventStarted, View: currentView})
+}
+
+func (r *Recorder) OnReceiveProposal(currentView uint64, proposal *model.SignedProposal) {
+	r.record(&Record{
+		Type:    EventProposalReceived,
+		View:    proposal.Block.View,
+		CurView: currentView,
+		BlockID: proposal.Block.BlockID,
+		NodeID:  proposal.Block.ProposerID,
+	})
+}
+
+func (r *Recorder) OnReceiveQc(currentView uint64, qc *flow.QuorumCertificate) {
+	r.record(&Record{Type: EventQcReceived, View: qc.View, CurView: currentView, BlockID: qc.BlockID})
+}
+
+func (r *Recorder) OnReceiveTc(currentView uint64, tc *flow.TimeoutCertificate) {
+	r.record(&Record{Type: EventTcReceived, View: tc.View, CurView: currentView, AuxView: tc.NewestQC.View})
+}
+
+// OnPartialTc is not recorded, the partial TC is recorded when it is created by the timeout
+// aggregator (OnPartialTcCreated).
+func (r *Recorder) OnPartialTc(uint64, *hotstuff.PartialTcCreated) {}
+
+func (r *Recorder) OnLocalTimeout(currentView uint64) {
+	r.record(&Record{Type: EventLocalTimeout, View: currentView})
+}
+
+// OnViewChange is not recorded, as every view change is also reported together with the
+// certificate that triggered it (OnQcTriggeredViewChange or OnTcTriggeredViewChange).
+func (r *Recorder) OnViewChange(uint64, uint64) {}
+
+func (r *Recorder) OnQcTriggeredViewChange(_ uint64, newView uint64, qc *flow.QuorumCertificate) {
+	r.record(&Record{Type: EventViewEnteredQC, View: newView, AuxView: qc.View, BlockID: qc.BlockID})
+}
+
+func (r *Recorder) OnTcTriggeredViewChange(_ uint64, newView uint64, tc *flow.TimeoutCertificate) {
+	r.record(&Record{Type: EventViewEnteredTC, View: newView, AuxView: tc.View})
+}
+
+func (r *Recorder) OnStartingTimeout(info model.TimerInfo) {
+	r.record(&Record{Type: EventTimerStarted, View: info.View, AuxTime: info.StartTime.Add(info.Duration)})
+}
+
+func (r *Recorder) OnCurrentViewDetails(currentView, finalizedView uint64, currentLeader flow.Identifier) {
+	r.record(&Record{Type: EventViewDetails, View: currentView, AuxView: finalizedView, NodeID: currentLeader})
+}
+
+func (r *Recorder) OnBlockIncorporated(block *model.Block) {
+	r.record(&Record{Type: EventBlockIncorporated, View: block.View, BlockID: block.BlockID})
+}
+
+func (r *Recorder) OnFinalizedBlock(block *model.Block) {
+	r.record(&Record{Type: EventBlockFinalized, View: block.View, BlockID: block.BlockID})
+}
+
+func (r *Recorder) OnOwnVote(blockID flow.Identifier, view uint64, _ []byte, recipientID flow.Identifier) {
+	r.record(&Record{Type: EventVoteSent, View: view, BlockID: blockID, NodeID: recipientID})
+}
+
+func (r *Recorder) OnOwnTimeout(timeout *model.TimeoutObject) {
+	r.record(&Record{Type: EventTimeoutSent, View: timeout.View, AuxView: timeout.NewestQC.View})
+}
+
+func (r *Recorder) OnOwnProposal(header *flow.Header, targetPublicationTime time.Time) {
+	r.record(&Record{Type: EventProposalSent, View: header.View, BlockID: header.ID(), AuxTime: targetPublicationTime})
+}
+
+func (r *Recorder) OnQcConstructedFromVotes(qc *flow.QuorumCertificate) {
+	r.record(&Record{Type: EventQcFormed, View: qc.View, BlockID: qc.BlockID})
+}
+
+func (r *Recorder) OnVoteProcessed(vote *model.Vote) {
+	r.record(&Record{Type: EventVoteReceived, View: vote.View, BlockID: vote.BlockID, NodeID: vote.SignerID})
+}
+
+func (r *Recorder) OnTcConstructedFromTimeouts(tc *flow.TimeoutCertificate) {
+	r.record(&Record{Type: EventTcFormed, View: tc.View, AuxView: tc.NewestQC.View})
+}
+
+func (r *Recorder) OnPartialTcCreated(view uint64, newestQC *flow.QuorumCertificate, _ *flow.TimeoutCertificate) {
+	r.record(&Record{Type: EventPartialTcFormed, View: view, AuxView: newestQC.View})
+}
+
+// OnNewQcDiscovered is not recorded, QCs are recorded when they are processed by the event
+// handler (OnReceiveQc).
+func (r *Recorder) OnNewQcDiscovered(*flow.QuorumCertificate) {}
+
+// OnNewTcDiscovered is not recorded, TCs are recorded when they are processed by the event
+// handler (OnReceiveTc).
+func (r *Recorder) OnNewTcDiscovered(*flow.TimeoutCertificate) {}
+
+func (r *Recorder) OnTimeoutProcessed(timeout *model.TimeoutObject) {
+	r.record(&Record{Type: EventTimeoutReceived, View: timeout.View, NodeID: timeout.SignerID, AuxView: timeout.NewestQC.View})
+}
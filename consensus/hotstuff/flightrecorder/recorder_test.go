@@ -0,0 +1,236 @@
+package flightrecorder
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/helper"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestRecorder verifies that notifications are written to the recording and can be read back.
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	nodeID := unittest.IdentifierFixture()
+	recorder, err := NewRecorder(zerolog.Nop(), nodeID, DefaultConfig(dir))
+	require.NoError(t, err)
+
+	ctx, cancel := irrecoverable.NewMockSignalerContextWithCancel(t, context.Background())
+	recorder.Start(ctx)
+	unittest.RequireComponentsReadyBefore(t, time.Second, recorder)
+
+	block := helper.MakeBlock(helper.WithBlockView(10))
+	qc := helper.MakeQC(helper.WithQCBlock(block))
+	leader := unittest.IdentifierFixture()
+	recorder.OnQcTriggeredViewChange(9, 10, helper.MakeQC(helper.WithQCView(9)))
+	recorder.OnCurrentViewDetails(10, 8, leader)
+	recorder.OnReceiveProposal(10, helper.MakeSignedProposal(helper.WithProposal(helper.MakeProposal(helper.WithBlock(block)))))
+	recorder.OnOwnVote(block.BlockID, block.View, nil, leader)
+	recorder.OnQcConstructedFromVotes(qc)
+	recorder.OnViewChange(10, 11) // not recorded
+
+	cancel()
+	unittest.RequireComponentsDoneBefore(t, time.Second, recorder)
+
+	recording, err := ReadRecording(dir)
+	require.NoError(t, err)
+	assert.Equal(t, nodeID, recording.NodeID)
+	require.Len(t, recording.Records, 5)
+
+	types := make([]EventType, 0, len(recording.Records))
+	for _, record := range recording.Records {
+		types = append(types, record.Type)
+	}
+	assert.Equal(t, []EventType{EventViewEnteredQC, EventViewDetails, EventProposalReceived, EventVoteSent, EventQcFormed}, types)
+
+	proposal := recording.Records[2]
+	assert.Equal(t, uint64(10), proposal.View)
+	assert.Equal(t, uint64(10), proposal.CurView)
+	assert.Equal(t, block.BlockID, proposal.BlockID)
+	assert.Equal(t, block.ProposerID, proposal.NodeID)
+	assert.Equal(t, leader, recording.Records[1].NodeID)
+	assert.Equal(t, uint64(8), recording.Records[1].AuxView)
+}
+
+// TestRotatingWriter verifies that records are split across files, old files are removed and
+// truncated records left behind by a crash are ignored.
+func TestRotatingWriter(t *testing.T) {
+	dir := t.TempDir()
+	nodeID := unittest.IdentifierFixture()
+	start := time.Unix(1_700_000_000, 0).UTC()
+
+	writer, err := newRotatingWriter(dir, nodeID, 512, 3)
+	require.NoError(t, err)
+	var written []*Record
+	for i := 0; i < 100; i++ {
+		record := &Record{
+			Type:    EventTimeoutReceived,
+			Time:    start.Add(time.Duration(i) * time.Millisecond),
+			View:    uint64(i),
+			NodeID:  unittest.IdentifierFixture(),
+			AuxView: uint64(i / 2),
+			AuxTime: start.Add(time.Second),
+		}
+		require.NoError(t, writer.Write(record))
+		written = append(written, record)
+	}
+	require.NoError(t, writer.Close())
+
+	seqs, err := listFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, seqs, 3)
+
+	recording, err := ReadRecording(dir)
+	require.NoError(t, err)
+	assert.Equal(t, nodeID, recording.NodeID)
+	require.NotEmpty(t, recording.Records)
+	require.Less(t, len(recording.Records), len(written))
+	// the retained records are the most recent ones
+	assert.Equal(t, written[len(written)-len(recording.Records):], recording.Records)
+
+	// a restarted recorder starts a new file after the existing ones
+	writer, err = newRotatingWriter(dir, nodeID, 512, 3)
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(written[0]))
+	require.NoError(t, writer.Close())
+	restarted, err := ReadRecording(dir)
+	require.NoError(t, err)
+	assert.Equal(t, written[0], restarted.Records[len(restarted.Records)-1])
+
+	// truncate the last record of the newest file
+	seqs, err = listFiles(dir)
+	require.NoError(t, err)
+	path := dir + "/" + fileName(seqs[len(seqs)-1])
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(path, info.Size()-3))
+	truncated, err := ReadRecording(dir)
+	require.NoError(t, err)
+	assert.Len(t, truncated.Records, len(restarted.Records)-1)
+}
+
+// TestCriticalPath verifies the reconstruction of the critical path of views concluded by a QC
+// and by a TC from the merged recordings of three nodes.
+func TestCriticalPath(t *testing.T) {
+	leader, voter, nextLeader := unittest.IdentifierFixture(), unittest.IdentifierFixture(), unittest.IdentifierFixture()
+	blockID := unittest.IdentifierFixture()
+	start := time.Unix(1_700_000_000, 0).UTC()
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+
+	recordings := []*Recording{
+		{NodeID: leader, Records: []*Record{
+			{Type: EventViewEnteredQC, Time: at(0), View: 10},
+			{Type: EventProposalSent, Time: at(20), View: 10, BlockID: blockID, AuxTime: at(250)},
+			{Type: EventVoteSent, Time: at(260), View: 10, BlockID: blockID, NodeID: nextLeader},
+			{Type: EventViewEnteredQC, Time: at(500), View: 11},
+			{Type: EventLocalTimeout, Time: at(2500), View: 11},
+			{Type: EventTimeoutSent, Time: at(2505), View: 11, AuxView: 10},
+		}},
+		{NodeID: voter, Records: []*Record{
+			{Type: EventViewEnteredQC, Time: at(5), View: 10},
+			{Type: EventProposalReceived, Time: at(300), View: 10, BlockID: blockID, NodeID: leader},
+			{Type: EventVoteSent, Time: at(310), View: 10, BlockID: blockID, NodeID: nextLeader},
+			{Type: EventViewEnteredQC, Time: at(510), View: 11},
+			{Type: EventLocalTimeout, Time: at(2600), View: 11},
+			{Type: EventTimeoutSent, Time: at(2610), View: 11, AuxView: 10},
+		}},
+		{NodeID: nextLeader, Records: []*Record{
+			{Type: EventViewEnteredQC, Time: at(3), View: 10},
+			{Type: EventVoteReceived, Time: at(280), View: 10, BlockID: blockID, NodeID: leader},
+			{Type: EventVoteReceived, Time: at(330), View: 10, BlockID: blockID, NodeID: voter},
+			{Type: EventQcFormed, Time: at(335), View: 10, BlockID: blockID},
+			{Type: EventViewEnteredQC, Time: at(340), View: 11},
+			{Type: EventTimeoutReceived, Time: at(2520), View: 11, NodeID: leader},
+			{Type: EventTimeoutReceived, Time: at(2630), View: 11, NodeID: voter},
+			{Type: EventTcFormed, Time: at(2640), View: 11, AuxView: 10},
+			{Type: EventViewEnteredTC, Time: at(2650), View: 12},
+		}},
+	}
+	events := Merge(recordings...)
+
+	timelines := Timelines(events, 10, 10)
+	require.Len(t, timelines, 1)
+	assert.Len(t, timelines[0].Events, 10)
+	assert.Equal(t, at(0), timelines[0].Start())
+
+	type step struct {
+		recorder  string
+		eventType EventType
+		elapsed   time.Duration
+	}
+	names := map[flow.Identifier]string{leader: "leader", voter: "voter", nextLeader: "next"}
+	steps := func(path *CriticalPath) []step {
+		var steps []step
+		for _, s := range path.Steps {
+			steps = append(steps, step{names[s.Recorder], s.Type, s.Elapsed})
+		}
+		return steps
+	}
+
+	qcPath := CriticalPathOf(events, 10)
+	assert.Equal(t, OutcomeQC, qcPath.Outcome)
+	assert.Equal(t, []step{
+		{"leader", EventViewEnteredQC, 0},
+		{"leader", EventProposalSent, 20 * time.Millisecond},
+		{"voter", EventProposalReceived, 280 * time.Millisecond},
+		{"voter", EventVoteSent, 10 * time.Millisecond},
+		{"next", EventVoteReceived, 20 * time.Millisecond},
+		{"next", EventQcFormed, 5 * time.Millisecond},
+		{"next", EventViewEnteredQC, 5 * time.Millisecond},
+	}, steps(qcPath))
+	assert.Equal(t, 340*time.Millisecond, qcPath.Duration())
+
+	// the critical path can be reconstructed from the timelines of the view and the next view
+	all := Timelines(events, 10, 12)
+	require.Len(t, all, 3)
+	assert.Equal(t, qcPath, all[0].CriticalPath(all[1]))
+	assert.Len(t, all[0].CriticalPath(nil).Steps, len(qcPath.Steps)-1)
+
+	tcPath := CriticalPathOf(events, 11)
+	assert.Equal(t, OutcomeTC, tcPath.Outcome)
+	assert.Equal(t, []step{
+		{"voter", EventViewEnteredQC, 0},
+		{"voter", EventLocalTimeout, 2090 * time.Millisecond},
+		{"voter", EventTimeoutSent, 10 * time.Millisecond},
+		{"next", EventTimeoutReceived, 20 * time.Millisecond},
+		{"next", EventTcFormed, 10 * time.Millisecond},
+		{"next", EventViewEnteredTC, 10 * time.Millisecond},
+	}, steps(tcPath))
+
+	unknown := CriticalPathOf(events, 12)
+	assert.Equal(t, OutcomeUnknown, unknown.Outcome)
+	assert.Empty(t, unknown.Steps)
+}
+
+// TestRecordEncoding verifies that all optional fields survive encoding.
+func TestRecordEncoding(t *testing.T) {
+	record := &Record{
+		Type:    EventProposalReceived,
+		Time:    time.Unix(1_700_000_000, 123).UTC(),
+		View:    1 << 40,
+		CurView: 1<<40 - 1,
+		BlockID: unittest.IdentifierFixture(),
+		NodeID:  unittest.IdentifierFixture(),
+		AuxView: 7,
+		AuxTime: time.Unix(1_700_000_001, 0).UTC(),
+	}
+	encoded := appendRecord(nil, record)
+	decoded, err := decodeRecord(encoded[1:]) // single byte length prefix
+	require.NoError(t, err)
+	assert.Equal(t, record, decoded)
+
+	_, err = decodeRecord(encoded[1 : len(encoded)-2])
+	assert.ErrorIs(t, err, errCorruptRecord)
+
+	// unset fields are omitted
+	minimal := appendRecord(nil, &Record{Type: EventLocalTimeout, Time: record.Time, View: 5})
+	assert.Less(t, len(minimal), 16)
+}
@@ -0,0 +1,273 @@
+package flightrecorder
+
+import (
+	"sort"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Event is a record together with the node that recorded it.
+type Event struct {
+	Recorder flow.Identifier
+	*Record
+}
+
+// Merge merges the recordings of several nodes into a single sequence of events, ordered by
+// time. Events are timestamped with the local clock of the recording node, so the order of
+// events of different nodes is only as accurate as the synchronization of their clocks.
+func Merge(recordings ...*Recording) []Event {
+	var events []Event
+	for _, recording := range recordings {
+		for _, record := range recording.Records {
+			events = append(events, Event{Recorder: recording.NodeID, Record: record})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events
+}
+
+// ViewTimeline is the sequence of events of all nodes referring to a single view.
+type ViewTimeline struct {
+	View   uint64
+	Events []Event
+}
+
+// Start returns the time the first node entered the view, or the time of the first event of
+// the view if no node recorded entering it.
+func (t *ViewTimeline) Start() time.Time {
+	for _, event := range t.Events {
+		if event.Type.IsViewEntered() {
+			return event.Time
+		}
+	}
+	if len(t.Events) == 0 {
+		return time.Time{}
+	}
+	return t.Events[0].Time
+}
+
+// CriticalPath reconstructs the critical path of the view from the events of the view and of
+// the following view, which contains the events of the nodes entering it. The following view's
+// timeline may be nil, in which case the path ends with the certificate concluding the view.
+func (t *ViewTimeline) CriticalPath(next *ViewTimeline) *CriticalPath {
+	events := t.Events
+	if next != nil {
+		events = make([]Event, 0, len(t.Events)+len(next.Events))
+		events = append(events, t.Events...)
+		events = append(events, next.Events...)
+		sort.SliceStable(events, func(i, j int) bool {
+			return events[i].Time.Before(events[j].Time)
+		})
+	}
+	return CriticalPathOf(events, t.View)
+}
+
+// Timelines groups the time-ordered events by view and returns the timelines of the views
+// within [fromView, toView], ordered by view.
+func Timelines(events []Event, fromView, toView uint64) []*ViewTimeline {
+	byView := make(map[uint64]*ViewTimeline)
+	for _, event := range events {
+		if event.View < fromView || event.View > toView {
+			continue
+		}
+		timeline, ok := byView[event.View]
+		if !ok {
+			timeline = &ViewTimeline{View: event.View}
+			byView[event.View] = timeline
+		}
+		timeline.Events = append(timeline.Events, event)
+	}
+
+	timelines := make([]*ViewTimeline, 0, len(byView))
+	for _, timeline := range byView {
+		timelines = append(timelines, timeline)
+	}
+	sort.Slice(timelines, func(i, j int) bool { return timelines[i].View < timelines[j].View })
+	return timelines
+}
+
+// ViewOutcome is the way a view was concluded.
+type ViewOutcome string
+
+const (
+	OutcomeQC      ViewOutcome = "qc"
+	OutcomeTC      ViewOutcome = "tc"
+	OutcomeUnknown ViewOutcome = "unknown"
+)
+
+// Step is a single event on the critical path of a view.
+type Step struct {
+	Event
+	// Elapsed is the time since the previous step, zero for the first step. Steps on different
+	// nodes are subject to the clock offset between the nodes.
+	Elapsed time.Duration
+}
+
+// CriticalPath is the chain of causally dependent events which determined when a view ended:
+//   - for a view concluded by a QC, the leader entering the view and publishing its proposal,
+//     the replica whose vote completed the QC receiving the proposal and sending its vote, the
+//     next leader receiving the vote and forming the QC, and entering the next view
+//   - for a view concluded by a TC, the replica whose timeout completed the TC entering the
+//     view, timing out and sending its timeout, the node forming the TC receiving it, and
+//     entering the next view
+//
+// The vote (or timeout) completing the certificate is taken to be the last one processed
+// before the certificate was formed. Events on other nodes are matched by view and block
+// rather than by time, so that the path is robust to clock offsets between the nodes.
+// Steps on nodes without recordings are omitted.
+type CriticalPath struct {
+	View    uint64
+	Outcome ViewOutcome
+	Steps   []Step
+}
+
+// Duration returns the time between the first and the last step of the path.
+func (p *CriticalPath) Duration() time.Duration {
+	if len(p.Steps) == 0 {
+		return 0
+	}
+	return p.Steps[len(p.Steps)-1].Time.Sub(p.Steps[0].Time)
+}
+
+// CriticalPathOf reconstructs the critical path of the given view from the time-ordered events
+// of all nodes.
+func CriticalPathOf(events []Event, view uint64) *CriticalPath {
+	path := &CriticalPath{View: view, Outcome: OutcomeUnknown}
+
+	// the earliest certificate concluding the view
+	var certificate *Event
+	for i := range events {
+		event := &events[i]
+		if event.View == view && (event.Type == EventQcFormed || event.Type == EventTcFormed) {
+			certificate = event
+			break
+		}
+	}
+	if certificate == nil {
+		return path
+	}
+
+	// build the path backwards, starting from the certificate
+	var steps []Event
+	collector := certificate.Recorder
+	if certificate.Type == EventQcFormed {
+		path.Outcome = OutcomeQC
+		steps = append(steps, *certificate)
+
+		vote, ok := lastBefore(events, certificate.Time, func(e Event) bool {
+			return e.Recorder == collector && e.Type == EventVoteReceived && e.View == view && e.BlockID == certificate.BlockID
+		})
+		if ok {
+			steps = append(steps, vote)
+			voter := vote.NodeID
+			if sent, ok := first(events, func(e Event) bool {
+				return e.Recorder == voter && e.Type == EventVoteSent && e.View == view
+			}); ok {
+				steps = append(steps, sent)
+			}
+
+			var proposer flow.Identifier
+			if received, ok := first(events, func(e Event) bool {
+				return e.Recorder == voter && e.Type == EventProposalReceived && e.View == view && e.BlockID == certificate.BlockID
+			}); ok {
+				steps = append(steps, received)
+				proposer = received.NodeID
+			}
+			if proposer == flow.ZeroID {
+				proposer = voter
+			}
+			if proposal, ok := first(events, func(e Event) bool {
+				return e.Recorder == proposer && e.Type == EventProposalSent && e.View == view
+			}); ok {
+				steps = append(steps, proposal)
+			}
+			if entered, ok := first(events, func(e Event) bool {
+				return e.Recorder == proposer && e.Type.IsViewEntered() && e.View == view
+			}); ok {
+				steps = append(steps, entered)
+			}
+		}
+	} else {
+		path.Outcome = OutcomeTC
+		steps = append(steps, *certificate)
+
+		timeout, ok := lastBefore(events, certificate.Time, func(e Event) bool {
+			return e.Recorder == collector && e.Type == EventTimeoutReceived && e.View == view
+		})
+		if ok {
+			steps = append(steps, timeout)
+			replica := timeout.NodeID
+			for _, eventType := range []EventType{EventTimeoutSent, EventLocalTimeout} {
+				if step, ok := first(events, func(e Event) bool {
+					return e.Recorder == replica && e.Type == eventType && e.View == view
+				}); ok {
+					steps = append(steps, step)
+				}
+			}
+			if entered, ok := first(events, func(e Event) bool {
+				return e.Recorder == replica && e.Type.IsViewEntered() && e.View == view
+			}); ok {
+				steps = append(steps, entered)
+			}
+		}
+	}
+
+	// reverse into causal order, and conclude with the collector entering the next view
+	for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+		steps[i], steps[j] = steps[j], steps[i]
+	}
+	if next, ok := firstAfter(events, certificate.Time, func(e Event) bool {
+		return e.Recorder == collector && e.Type.IsViewEntered() && e.View > view
+	}); ok {
+		steps = append(steps, next)
+	}
+
+	path.Steps = make([]Step, 0, len(steps))
+	for i, event := range steps {
+		step := Step{Event: event}
+		if i > 0 {
+			step.Elapsed = event.Time.Sub(steps[i-1].Time)
+		}
+		path.Steps = append(path.Steps, step)
+	}
+	return path
+}
+
+// first returns the first event matching the predicate.
+func first(events []Event, match func(Event) bool) (Event, bool) {
+	for _, event := range events {
+		if match(event) {
+			return event, true
+		}
+	}
+	return Event{}, false
+}
+
+// lastBefore returns the last event at or before the given time matching the predicate.
+func lastBefore(events []Event, t time.Time, match func(Event) bool) (Event, bool) {
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Time.After(t) {
+			continue
+		}
+		if match(events[i]) {
+			return events[i], true
+		}
+	}
+	return Event{}, false
+}
+
+// firstAfter returns the first event at or after the given time matching the predicate.
+func firstAfter(events []Event, t time.Time, match func(Event) bool) (Event, bool) {
+	for _, event := range events {
+		if event.Time.Before(t) {
+			continue
+		}
+		if match(event) {
+			return event, true
+		}
+	}
+	return Event{}, false
+}
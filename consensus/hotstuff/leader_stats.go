@@ -0,0 +1,13 @@
+package hotstuff
+
+import (
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+)
+
+// LeaderStatsProvider reports the outcomes of the views led by each consensus participant,
+// as observed by the local node. Implementations are concurrency safe.
+type LeaderStatsProvider interface {
+	// LeaderStats returns the statistics of all leaders observed in the given epoch, ordered
+	// by node ID. Returns an empty list if no views of the epoch were observed.
+	LeaderStats(epoch uint64) []*model.LeaderStats
+}
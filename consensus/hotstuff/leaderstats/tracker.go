@@ -0,0 +1,401 @@
+package leaderstats
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/storage"
+)
+
+// Config configures the leader stats tracker.
+type Config struct {
+	// FlushInterval is the interval at which updated statistics are persisted.
+	FlushInterval time.Duration
+	// ResolutionDelay is the number of views after which the outcome of a view is resolved.
+	// A proposal received within this window after the local node left the view is counted
+	// as late, afterwards the proposal is counted as missing.
+	ResolutionDelay uint64
+	// RetainedEpochs is the number of most recent epochs whose statistics are kept in memory.
+	// The statistics of older epochs are read from the store. Must be at least 2, so that the
+	// views of the previous epoch can still be resolved after the epoch switchover.
+	RetainedEpochs uint64
+}
+
+// DefaultConfig returns the default configuration of the leader stats tracker.
+func DefaultConfig() Config {
+	return Config{
+		FlushInterval:   30 * time.Second,
+		ResolutionDelay: 10,
+		RetainedEpochs:  3,
+	}
+}
+
+// viewRecord tracks the events of a single view until the view's outcome is resolved.
+type viewRecord struct {
+	epoch  uint64
+	leader flow.Identifier
+	// entered is the time the local node entered the view, zero if it has not (yet)
+	entered time.Time
+	// left is true once the local node left the view
+	left bool
+	// localTimeout is true if the local node timed out in the view
+	localTimeout bool
+	// timedOut is true if the view was concluded by a TC
+	timedOut         bool
+	proposalReceived bool
+	proposalLate     bool
+	proposalDelay    time.Duration
+}
+
+// outcome returns the outcome of the view from the leader's perspective.
+func (r *viewRecord) outcome() model.ViewOutcome {
+	switch {
+	case !r.proposalReceived:
+		return model.ViewProposalMissing
+	case r.proposalLate:
+		return model.ViewProposalLate
+	default:
+		return model.ViewProposalOnTime
+	}
+}
+
+// Tracker is an implementation of the notifications consumer that attributes the outcome of
+// each view to the view's leader: whether the leader's proposal was received on time, late or
+// not at all, and whether the view was concluded by a timeout certificate. A proposal is late
+// if it is received after the local node timed out in or left the view. The outcome of a view
+// is resolved Config.ResolutionDelay views after it was entered, views still unresolved on
+// shutdown are not accounted.
+//
+// The statistics are aggregated per epoch and leader, reported as metrics and persisted
+// periodically, so they are retained across restarts. Only the statistics of the most recent
+// Config.RetainedEpochs epochs are kept in memory.
+type Tracker struct {
+	component.Component
+	notifications.NoopConsumer
+
+	log       zerolog.Logger
+	metrics   module.LeaderMetrics
+	committee hotstuff.Replicas
+	epochs    module.EpochLookup
+	store     storage.LeaderStats
+	config    Config
+
+	mu          sync.Mutex
+	currentView uint64
+	views       map[uint64]*viewRecord
+	// stats holds the statistics of the retained epochs, see Config.RetainedEpochs
+	stats       map[uint64]map[flow.Identifier]*model.LeaderStats
+	latestEpoch uint64
+	// dirty holds the statistics updated since they were last persisted
+	dirty map[*model.LeaderStats]struct{}
+}
+
+var _ hotstuff.Consumer = (*Tracker)(nil)
+var _ hotstuff.LeaderStatsProvider = (*Tracker)(nil)
+var _ component.Component = (*Tracker)(nil)
+
+// NewTracker creates a leader stats tracker, which continues the statistics already persisted
+// in the given store.
+// No errors are expected during normal operation.
+func NewTracker(
+	log zerolog.Logger,
+	metrics module.LeaderMetrics,
+	committee hotstuff.Replicas,
+	epochs module.EpochLookup,
+	store storage.LeaderStats,
+	config Config,
+) (*Tracker, error) {
+	if config.RetainedEpochs < 2 {
+		return nil, fmt.Errorf("at least 2 epochs must be retained, got %d", config.RetainedEpochs)
+	}
+	persisted, err := store.All()
+	if err != nil {
+		return nil, fmt.Errorf("could not load persisted leader stats: %w", err)
+	}
+
+	t := &Tracker{
+		log:       log.With().Str("hotstuff", "leader_stats").Logger(),
+		metrics:   metrics,
+		committee: committee,
+		epochs:    epochs,
+		store:     store,
+		config:    config,
+		views:     make(map[uint64]*viewRecord),
+		stats:     make(map[uint64]map[flow.Identifier]*model.LeaderStats),
+		dirty:     make(map[*model.LeaderStats]struct{}),
+	}
+	// persisted statistics are ordered by epoch, so that older epochs are pruned as newer ones are loaded
+	for _, stats := range persisted {
+		if epochStats := t.epochStats(stats.Epoch); epochStats != nil {
+			epochStats[stats.NodeID] = stats
+		}
+	}
+
+	t.Component = component.NewComponentManagerBuilder().
+		AddWorker(t.flushWorkerLogic).
+		Build()
+	return t, nil
+}
+
+// LeaderStats returns the statistics of all leaders observed in the given epoch, ordered by
+// node ID. Returns an empty list if no views of the epoch were observed. The statistics of
+// epochs which are not retained in memory are read from the store.
+func (t *Tracker) LeaderStats(epoch uint64) []*model.LeaderStats {
+	t.mu.Lock()
+	epochStats := make(map[flow.Identifier]model.LeaderStats)
+	retained := t.retained(epoch)
+	if retained {
+		for nodeID, stats := range t.stats[epoch] {
+			epochStats[nodeID] = *stats
+		}
+	} else {
+		// statistics of pruned epochs which were updated after the last flush
+		for stats := range t.dirty {
+			if stats.Epoch == epoch {
+				epochStats[stats.NodeID] = *stats
+			}
+		}
+	}
+	t.mu.Unlock()
+
+	if !retained {
+		stored, err := t.store.ByEpoch(epoch)
+		if err != nil {
+			t.log.Error().Err(err).Uint64("epoch", epoch).Msg("could not read leader stats")
+		}
+		for _, stats := range stored {
+			if _, ok := epochStats[stats.NodeID]; !ok {
+				epochStats[stats.NodeID] = *stats
+			}
+		}
+	}
+
+	nodeIDs := make(flow.IdentifierList, 0, len(epochStats))
+	for nodeID := range epochStats {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	nodeIDs = nodeIDs.Sort(flow.IdentifierCanonical)
+
+	all := make([]*model.LeaderStats, 0, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		stats := epochStats[nodeID]
+		all = append(all, &stats)
+	}
+	return all
+}
+
+func (t *Tracker) OnStart(currentView uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.currentView = currentView
+}
+
+func (t *Tracker) OnViewChange(oldView, newView uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if record, ok := t.views[oldView]; ok {
+		record.left = true
+	}
+	t.currentView = newView
+	if record := t.view(newView); record != nil && record.entered.IsZero() {
+		record.entered = time.Now()
+	}
+	t.resolve()
+}
+
+func (t *Tracker) OnTcTriggeredViewChange(_ uint64, _ uint64, tc *flow.TimeoutCertificate) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if record := t.view(tc.View); record != nil {
+		record.timedOut = true
+	}
+}
+
+func (t *Tracker) OnLocalTimeout(currentView uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if record, ok := t.views[currentView]; ok {
+		record.localTimeout = true
+	}
+}
+
+// OnReceiveProposal records the arrival of the proposal. Own proposals are reported here as
+// well, once the node's own proposal is processed. Proposals are typically received before
+// the local node enters their view, as the proposal's QC triggers the view change. Hence, the
+// proposal delay is measured from the local node entering the previous view, in which the
+// leader collects the QC for its proposal. If the local node entered the proposal's view
+// before receiving the proposal (e.g. on a TC), the delay is measured from entering the view.
+func (t *Tracker) OnReceiveProposal(_ uint64, proposal *model.SignedProposal) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	view := proposal.Block.View
+	record := t.view(view)
+	if record == nil || record.proposalReceived {
+		return
+	}
+	record.proposalReceived = true
+	record.proposalLate = record.left || record.localTimeout
+
+	entered := record.entered
+	if previous, ok := t.views[view-1]; ok && entered.IsZero() {
+		entered = previous.entered
+	}
+	if !entered.IsZero() {
+		record.proposalDelay = time.Since(entered)
+	}
+}
+
+// view returns the record of the given view, creating it if necessary. Returns nil if the
+// view has already been resolved or if its leader cannot be determined.
+// Caller must hold the lock.
+func (t *Tracker) view(view uint64) *viewRecord {
+	if record, ok := t.views[view]; ok {
+		return record
+	}
+	if view+t.config.ResolutionDelay <= t.currentView {
+		return nil
+	}
+
+	leader, err := t.committee.LeaderForView(view)
+	if err != nil {
+		t.log.Warn().Err(err).Uint64("view", view).Msg("could not determine leader, view is not accounted")
+		return nil
+	}
+	epoch, err := t.epochs.EpochForView(view)
+	if err != nil {
+		t.log.Warn().Err(err).Uint64("view", view).Msg("could not determine epoch, view is not accounted")
+		return nil
+	}
+	record := &viewRecord{epoch: epoch, leader: leader}
+	t.views[view] = record
+	return record
+}
+
+// resolve accounts the outcomes of all views which are at least Config.ResolutionDelay
+// views older than the current view.
+// Caller must hold the lock.
+func (t *Tracker) resolve() {
+	for view, record := range t.views {
+		if view+t.config.ResolutionDelay > t.currentView {
+			continue
+		}
+		delete(t.views, view)
+
+		epochStats := t.epochStats(record.epoch)
+		if epochStats == nil {
+			t.log.Warn().Uint64("view", view).Uint64("epoch", record.epoch).Msg("epoch is not retained, view is not accounted")
+			continue
+		}
+		stats, ok := epochStats[record.leader]
+		if !ok {
+			stats = &model.LeaderStats{Epoch: record.epoch, NodeID: record.leader}
+			epochStats[record.leader] = stats
+		}
+		outcome := record.outcome()
+		stats.Record(view, outcome, record.proposalDelay, record.timedOut)
+		t.dirty[stats] = struct{}{}
+		t.metrics.LeaderViewOutcome(record.leader, string(outcome), record.timedOut)
+	}
+}
+
+// epochStats returns the statistics of the given epoch, creating them if necessary. Creating the
+// statistics of a new epoch prunes the statistics of the epochs which are no longer retained.
+// Their updated statistics are still persisted with the next flush.
+// Returns nil if the epoch is no longer retained.
+// Caller must hold the lock.
+func (t *Tracker) epochStats(epoch uint64) map[flow.Identifier]*model.LeaderStats {
+	if stats, ok := t.stats[epoch]; ok {
+		return stats
+	}
+	if epoch > t.latestEpoch {
+		t.latestEpoch = epoch
+		for retainedEpoch := range t.stats {
+			if !t.retained(retainedEpoch) {
+				delete(t.stats, retainedEpoch)
+			}
+		}
+	}
+	if !t.retained(epoch) {
+		return nil
+	}
+	stats := make(map[flow.Identifier]*model.LeaderStats)
+	t.stats[epoch] = stats
+	return stats
+}
+
+// retained returns whether the statistics of the given epoch are kept in memory.
+// Caller must hold the lock.
+func (t *Tracker) retained(epoch uint64) bool {
+	return epoch+t.config.RetainedEpochs > t.latestEpoch
+}
+
+// flushWorkerLogic persists the updated statistics periodically and on shutdown.
+// Failures to persist the statistics are logged but otherwise ignored, as they must not
+// interrupt consensus. The statistics are retried with the next flush.
+// This method should be executed by a single worker routine.
+func (t *Tracker) flushWorkerLogic(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	ready()
+
+	ticker := time.NewTicker(t.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			err := t.flush()
+			if err != nil {
+				t.log.Error().Err(err).Msg("could not persist leader stats on shutdown")
+			}
+			return
+		case <-ticker.C:
+			err := t.flush()
+			if err != nil {
+				t.log.Error().Err(err).Msg("could not persist leader stats, will retry with the next flush")
+			}
+		}
+	}
+}
+
+// flush persists the statistics updated since the last flush. If persisting fails, the
+// statistics remain marked as updated, so that they are persisted with the next flush.
+// No errors are expected during normal operation.
+func (t *Tracker) flush() error {
+	t.mu.Lock()
+	flushed := make([]*model.LeaderStats, 0, len(t.dirty))
+	updated := make([]*model.LeaderStats, 0, len(t.dirty))
+	for stats := range t.dirty {
+		snapshot := *stats
+		flushed = append(flushed, stats)
+		updated = append(updated, &snapshot)
+	}
+	t.dirty = make(map[*model.LeaderStats]struct{})
+	t.mu.Unlock()
+
+	if len(updated) == 0 {
+		return nil
+	}
+	err := t.store.Store(updated)
+	if err != nil {
+		t.mu.Lock()
+		for _, stats := range flushed {
+			t.dirty[stats] = struct{}{}
+		}
+		t.mu.Unlock()
+		return fmt.Errorf("could not persist leader stats: %w", err)
+	}
+	return nil
+}
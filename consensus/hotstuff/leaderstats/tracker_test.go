@@ -0,0 +1,239 @@
+package leaderstats
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/helper"
+	"github.com/onflow/flow-go/consensus/hotstuff/mocks"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	modulemock "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation/dbtest"
+	"github.com/onflow/flow-go/storage/store"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestTracker verifies that view outcomes are attributed to the views' leaders, and that the
+// statistics are persisted on shutdown and continued after a restart.
+func TestTracker(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		leaders := unittest.IdentifierListFixture(2).Sort(flow.IdentifierCanonical)
+		a, b := leaders[0], leaders[1]
+		committee := mocks.NewReplicas(t)
+		committee.On("LeaderForView", mock.Anything).Return(
+			func(view uint64) flow.Identifier { return leaders[view%2] },
+			func(uint64) error { return nil },
+		)
+		epochs := modulemock.NewEpochLookup(t)
+		epochs.On("EpochForView", mock.Anything).Return(uint64(1), nil)
+		metrics := modulemock.NewLeaderMetrics(t)
+		metrics.On("LeaderViewOutcome", a, string(model.ViewProposalOnTime), false).Once()
+		metrics.On("LeaderViewOutcome", b, string(model.ViewProposalLate), true).Once()
+		metrics.On("LeaderViewOutcome", a, string(model.ViewProposalMissing), true).Once()
+		metrics.On("LeaderViewOutcome", b, string(model.ViewProposalMissing), false).Once()
+
+		statsStore := store.NewLeaderStats(db)
+		config := DefaultConfig()
+		config.ResolutionDelay = 2
+		tracker, err := NewTracker(zerolog.Nop(), metrics, committee, epochs, statsStore, config)
+		require.NoError(t, err)
+		ctx, cancel := irrecoverable.NewMockSignalerContextWithCancel(t, context.Background())
+		tracker.Start(ctx)
+		unittest.RequireComponentsReadyBefore(t, time.Second, tracker)
+
+		proposal := func(view uint64) *model.SignedProposal {
+			return helper.MakeSignedProposal(helper.WithProposal(helper.MakeProposal(helper.WithBlock(
+				helper.MakeBlock(helper.WithBlockView(view), helper.WithBlockProposer(leaders[view%2]))))))
+		}
+
+		tracker.OnStart(9)
+		// view 10: proposal received with the QC entering the view
+		tracker.OnReceiveProposal(9, proposal(10))
+		tracker.OnViewChange(9, 10)
+		// view 11: proposal received after the local timeout, the view is concluded by a TC
+		tracker.OnViewChange(10, 11)
+		tracker.OnLocalTimeout(11)
+		time.Sleep(10 * time.Millisecond)
+		tracker.OnReceiveProposal(11, proposal(11))
+		tracker.OnTcTriggeredViewChange(11, 12, helper.MakeTC(helper.WithTCView(11)))
+		tracker.OnViewChange(11, 12)
+		// view 12: no proposal, the view is concluded by a TC
+		tracker.OnLocalTimeout(12)
+		tracker.OnTcTriggeredViewChange(12, 13, helper.MakeTC(helper.WithTCView(12)))
+		tracker.OnViewChange(12, 13)
+		// view 13: no proposal, the node catches up with a QC for a later view
+		tracker.OnViewChange(13, 15)
+		// proposals for resolved views are ignored
+		tracker.OnReceiveProposal(15, proposal(12))
+
+		stats := tracker.LeaderStats(1)
+		require.Len(t, stats, 2)
+		assert.Equal(t, model.LeaderStats{Epoch: 1, NodeID: a, Views: 2, ProposalsOnTime: 1, ProposalsMissing: 1, TimedOut: 1, LastView: 12}, *stats[0])
+		assert.Equal(t, uint64(2), stats[1].Views)
+		assert.Equal(t, uint64(1), stats[1].ProposalsLate)
+		assert.Equal(t, uint64(1), stats[1].ProposalsMissing)
+		assert.Equal(t, uint64(1), stats[1].TimedOut)
+		assert.Equal(t, uint64(13), stats[1].LastView)
+		assert.GreaterOrEqual(t, stats[1].MeanProposalDelay(), 10*time.Millisecond)
+		assert.Empty(t, tracker.LeaderStats(2))
+
+		// statistics are persisted on shutdown
+		cancel()
+		unittest.RequireComponentsDoneBefore(t, time.Second, tracker)
+		persisted, err := statsStore.ByEpoch(1)
+		require.NoError(t, err)
+		assert.Equal(t, stats, persisted)
+
+		// and continued after a restart
+		restarted, err := NewTracker(zerolog.Nop(), metrics, committee, epochs, statsStore, config)
+		require.NoError(t, err)
+		assert.Equal(t, stats, restarted.LeaderStats(1))
+	})
+}
+
+// TestTracker_ProposalDelay verifies that the proposal delay is measured from the local node
+// entering the previous view, or from entering the proposal's view if the proposal arrives later.
+func TestTracker_ProposalDelay(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		leader := unittest.IdentifierFixture()
+		committee := mocks.NewReplicas(t)
+		committee.On("LeaderForView", mock.Anything).Return(leader, nil)
+		epochs := modulemock.NewEpochLookup(t)
+		epochs.On("EpochForView", mock.Anything).Return(uint64(1), nil)
+		tracker, err := NewTracker(zerolog.Nop(), modulemock.NewLeaderMetrics(t), committee, epochs, store.NewLeaderStats(db), DefaultConfig())
+		require.NoError(t, err)
+
+		proposal := func(view uint64) *model.SignedProposal {
+			return helper.MakeSignedProposal(helper.WithProposal(helper.MakeProposal(helper.WithBlock(
+				helper.MakeBlock(helper.WithBlockView(view), helper.WithBlockProposer(leader))))))
+		}
+
+		tracker.OnStart(9)
+		// view 11: proposal received with the QC for view 10, measured from entering view 10
+		tracker.OnViewChange(9, 10)
+		time.Sleep(10 * time.Millisecond)
+		tracker.OnReceiveProposal(10, proposal(11))
+		tracker.OnViewChange(10, 11)
+		// view 12: proposal received after entering the view on a TC, measured from entering view 12
+		time.Sleep(200 * time.Millisecond)
+		tracker.OnLocalTimeout(11)
+		tracker.OnTcTriggeredViewChange(11, 12, helper.MakeTC(helper.WithTCView(11)))
+		tracker.OnViewChange(11, 12)
+		time.Sleep(10 * time.Millisecond)
+		tracker.OnReceiveProposal(12, proposal(12))
+
+		tracker.mu.Lock()
+		defer tracker.mu.Unlock()
+		assert.GreaterOrEqual(t, tracker.views[11].proposalDelay, 10*time.Millisecond)
+		assert.GreaterOrEqual(t, tracker.views[12].proposalDelay, 10*time.Millisecond)
+		assert.Less(t, tracker.views[12].proposalDelay, 200*time.Millisecond)
+	})
+}
+
+// TestTracker_RetainedEpochs verifies that only the statistics of the most recent epochs are kept
+// in memory, and that the statistics of older epochs are served from the store.
+func TestTracker_RetainedEpochs(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		leader := unittest.IdentifierFixture()
+		committee := mocks.NewReplicas(t)
+		committee.On("LeaderForView", mock.Anything).Return(leader, nil)
+		// epoch N spans views [10N, 10N+9]
+		epochs := modulemock.NewEpochLookup(t)
+		epochs.On("EpochForView", mock.Anything).Return(
+			func(view uint64) uint64 { return view / 10 },
+			func(uint64) error { return nil },
+		)
+		metrics := modulemock.NewLeaderMetrics(t)
+		metrics.On("LeaderViewOutcome", leader, string(model.ViewProposalOnTime), false)
+
+		statsStore := store.NewLeaderStats(db)
+		config := DefaultConfig()
+		config.ResolutionDelay = 2
+		config.RetainedEpochs = 1
+		_, err := NewTracker(zerolog.Nop(), metrics, committee, epochs, statsStore, config)
+		require.Error(t, err)
+
+		config.RetainedEpochs = 2
+		tracker, err := NewTracker(zerolog.Nop(), metrics, committee, epochs, statsStore, config)
+		require.NoError(t, err)
+
+		tracker.OnStart(9)
+		for view := uint64(10); view <= 40; view++ {
+			tracker.OnReceiveProposal(view-1, helper.MakeSignedProposal(helper.WithProposal(helper.MakeProposal(helper.WithBlock(
+				helper.MakeBlock(helper.WithBlockView(view), helper.WithBlockProposer(leader)))))))
+			tracker.OnViewChange(view-1, view)
+		}
+
+		// views up to 38 are resolved, so epochs 2 and 3 are retained
+		tracker.mu.Lock()
+		assert.Len(t, tracker.stats, 2)
+		assert.Contains(t, tracker.stats, uint64(2))
+		assert.Contains(t, tracker.stats, uint64(3))
+		tracker.mu.Unlock()
+
+		// statistics of the pruned epoch are served before and after being persisted
+		expected := tracker.LeaderStats(1)
+		require.Len(t, expected, 1)
+		assert.Equal(t, model.LeaderStats{Epoch: 1, NodeID: leader, Views: 10, ProposalsOnTime: 10, TotalProposalDelay: expected[0].TotalProposalDelay, LastView: 19}, *expected[0])
+		require.NoError(t, tracker.flush())
+		assert.Equal(t, expected, tracker.LeaderStats(1))
+		assert.Equal(t, uint64(9), tracker.LeaderStats(3)[0].Views)
+
+		// only the retained epochs are loaded after a restart
+		restarted, err := NewTracker(zerolog.Nop(), metrics, committee, epochs, statsStore, config)
+		require.NoError(t, err)
+		assert.Len(t, restarted.stats, 2)
+		assert.Equal(t, expected, restarted.LeaderStats(1))
+		assert.Equal(t, tracker.LeaderStats(3), restarted.LeaderStats(3))
+	})
+}
+
+// failingLeaderStats is a leader stats storage which fails to persist statistics while fail is set.
+type failingLeaderStats struct {
+	storage.LeaderStats
+	fail bool
+}
+
+func (s *failingLeaderStats) Store(stats []*model.LeaderStats) error {
+	if s.fail {
+		return fmt.Errorf("storage failure")
+	}
+	return s.LeaderStats.Store(stats)
+}
+
+// TestTracker_FlushFailure verifies that statistics which could not be persisted are retained and
+// persisted with the next flush.
+func TestTracker_FlushFailure(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		statsStore := &failingLeaderStats{LeaderStats: store.NewLeaderStats(db), fail: true}
+		tracker, err := NewTracker(zerolog.Nop(), modulemock.NewLeaderMetrics(t), mocks.NewReplicas(t), modulemock.NewEpochLookup(t), statsStore, DefaultConfig())
+		require.NoError(t, err)
+
+		stats := &model.LeaderStats{Epoch: 1, NodeID: unittest.IdentifierFixture(), Views: 1, ProposalsOnTime: 1, LastView: 10}
+		tracker.mu.Lock()
+		tracker.epochStats(1)[stats.NodeID] = stats
+		tracker.dirty[stats] = struct{}{}
+		tracker.mu.Unlock()
+
+		require.Error(t, tracker.flush())
+		persisted, err := statsStore.ByEpoch(1)
+		require.NoError(t, err)
+		assert.Empty(t, persisted)
+
+		statsStore.fail = false
+		require.NoError(t, tracker.flush())
+		persisted, err = statsStore.ByEpoch(1)
+		require.NoError(t, err)
+		assert.Equal(t, []*model.LeaderStats{stats}, persisted)
+	})
+}
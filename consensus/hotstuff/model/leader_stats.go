@@ -0,0 +1,80 @@
+package model
+
+import (
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ViewOutcome is the outcome of a view from the perspective of the view's leader, as
+// observed by the local node.
+type ViewOutcome string
+
+const (
+	// ViewProposalOnTime means the leader's proposal was received before the local node
+	// timed out in or left the view.
+	ViewProposalOnTime ViewOutcome = "on_time"
+	// ViewProposalLate means the leader's proposal was received only after the local node
+	// timed out in or left the view.
+	ViewProposalLate ViewOutcome = "late"
+	// ViewProposalMissing means no proposal of the leader was received for the view.
+	ViewProposalMissing ViewOutcome = "missing"
+)
+
+// LeaderStats aggregates the outcomes of the views led by a single consensus participant
+// within an epoch, as observed by the local node. Views which the local node skipped
+// entirely (e.g. while catching up) are not attributed to their leaders.
+type LeaderStats struct {
+	Epoch  uint64
+	NodeID flow.Identifier
+	// Views is the number of views led by the node which were observed.
+	Views uint64
+	// ProposalsOnTime, ProposalsLate and ProposalsMissing partition the observed views
+	// by their ViewOutcome.
+	ProposalsOnTime  uint64
+	ProposalsLate    uint64
+	ProposalsMissing uint64
+	// TimedOut is the number of observed views which were concluded by a timeout
+	// certificate, independently of whether a proposal was received.
+	TimedOut uint64
+	// TotalProposalDelay is the sum, over all received proposals, of the time between the
+	// local node entering the previous view and receiving the proposal, which covers the
+	// leader collecting the QC and publishing its proposal. If the local node entered the
+	// proposal's view first (e.g. on a TC), the delay is measured from entering that view.
+	// Proposals for which the local node observed neither view contribute no delay.
+	TotalProposalDelay time.Duration
+	// LastView is the highest observed view led by the node.
+	LastView uint64
+}
+
+// Record accounts the outcome of a view led by the node.
+func (s *LeaderStats) Record(view uint64, outcome ViewOutcome, proposalDelay time.Duration, timedOut bool) {
+	s.Views++
+	switch outcome {
+	case ViewProposalOnTime:
+		s.ProposalsOnTime++
+	case ViewProposalLate:
+		s.ProposalsLate++
+	case ViewProposalMissing:
+		s.ProposalsMissing++
+	}
+	if outcome != ViewProposalMissing {
+		s.TotalProposalDelay += proposalDelay
+	}
+	if timedOut {
+		s.TimedOut++
+	}
+	if view > s.LastView {
+		s.LastView = view
+	}
+}
+
+// MeanProposalDelay returns the average delay of the received proposals, zero if no
+// proposal was received.
+func (s *LeaderStats) MeanProposalDelay() time.Duration {
+	received := s.ProposalsOnTime + s.ProposalsLate
+	if received == 0 {
+		return 0
+	}
+	return s.TotalProposalDelay / time.Duration(received)
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// SlashingEvidenceKind is the kind of protocol violation recorded in SlashingEvidence.
+type SlashingEvidenceKind string
+
+const (
+	// EvidenceDoubleVote is evidence of a replica voting for two different blocks in the same view.
+	EvidenceDoubleVote SlashingEvidenceKind = "double_vote"
+	// EvidenceDoubleProposal is evidence of a leader proposing two different blocks in the same view.
+	EvidenceDoubleProposal SlashingEvidenceKind = "double_proposal"
+	// EvidenceDoubleTimeout is evidence of a replica creating two different timeouts for the same view.
+	EvidenceDoubleTimeout SlashingEvidenceKind = "double_timeout"
+)
+
+// SlashingEvidence records the signed artifacts proving a slashable protocol violation
+// of a HotStuff participant, together with the context required to verify the artifacts
+// independently of the node which detected the violation.
+//
+// The evidence holds the two conflicting artifacts of an equivocation (double votes,
+// proposals and timeouts). Evidence of single invalid artifacts is not recorded: their
+// validity depends on the protocol state they were checked against, and their signer
+// is not authenticated if the signature is what made them invalid.
+type SlashingEvidence struct {
+	Kind SlashingEvidenceKind
+	// ChainID is the chain of the consensus instance, i.e. the main chain or a cluster chain.
+	ChainID flow.ChainID
+	// Epoch is the counter of the epoch containing View.
+	Epoch uint64
+	View  uint64
+	// Offender is the node ID of the participant which committed the violation.
+	Offender flow.Identifier
+	// OffenderIdentity is the identity of the offender in Epoch, including the staking
+	// key which signed the artifacts. Nil if the identity could not be determined.
+	OffenderIdentity *flow.IdentitySkeleton
+	// OffenderBeaconKey is the encoded random beacon key share of the offender in Epoch,
+	// which is required to verify votes signed by consensus participants with their
+	// random beacon key. Empty for participants without a key share.
+	OffenderBeaconKey []byte
+	DetectedAt        time.Time
+
+	// Votes are the offending votes.
+	Votes []*Vote
+	// Timeouts are the offending timeouts.
+	Timeouts []*TimeoutObject
+	// Blocks are the conflicting blocks of a double proposal.
+	Blocks []*Block
+	// Headers are the full headers of Blocks, which include the proposer signatures.
+	// They are only available for blocks which are stored locally.
+	Headers []*flow.Header
+}
+
+// ID returns the identifier of the evidence. The identifier only depends on the
+// violation and the artifacts proving it, but not on the order in which the conflicting
+// artifacts were observed or on when they were observed. Therefore,
+// repeated observations of the same violation have the same ID.
+func (e *SlashingEvidence) ID() flow.Identifier {
+	artifacts := make([]flow.Identifier, 0, len(e.Votes)+len(e.Timeouts)+len(e.Blocks))
+	for _, vote := range e.Votes {
+		artifacts = append(artifacts, vote.ID())
+	}
+	for _, timeout := range e.Timeouts {
+		artifacts = append(artifacts, timeout.ID())
+	}
+	for _, block := range e.Blocks {
+		artifacts = append(artifacts, block.BlockID)
+	}
+	sort.Slice(artifacts, func(i, j int) bool {
+		return bytes.Compare(artifacts[i][:], artifacts[j][:]) < 0
+	})
+
+	body := struct {
+		Kind      SlashingEvidenceKind
+		ChainID   flow.ChainID
+		View      uint64
+		Offender  flow.Identifier
+		Artifacts []flow.Identifier
+	}{
+		Kind:      e.Kind,
+		ChainID:   e.ChainID,
+		View:      e.View,
+		Offender:  e.Offender,
+		Artifacts: artifacts,
+	}
+	return flow.MakeID(body)
+}
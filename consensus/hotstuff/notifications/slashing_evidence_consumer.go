@@ -0,0 +1,197 @@
+package notifications
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// SlashingEvidenceConsumer is an implementation of the notifications consumer that
+// persists the signed artifacts of slashable offenses, so that the evidence survives
+// restarts and log rotation and can be exported for governance to act on.
+//
+// Together with the artifacts, the consumer records the epoch of the offense and the
+// offender's keys in that epoch, which makes evidence of equivocation verifiable by
+// anyone (see verification.VerifySlashingEvidence). Only evidence of equivocation which
+// passes this verification is persisted, so that the evidence cannot be forged in the
+// name of an honest node, and only actual offenders can cause evidence to be stored.
+// Repeated observations of the same offense are stored once.
+//
+// Invalid proposals, votes and timeouts are not persisted: their signer is not
+// authenticated if the signature is what made them invalid, and their validity depends
+// on the protocol state they were checked against. They are logged by the
+// SlashingViolationsConsumer.
+//
+// Failures to persist evidence are logged but otherwise ignored, as they must not
+// interrupt consensus.
+type SlashingEvidenceConsumer struct {
+	log          zerolog.Logger
+	chainID      flow.ChainID
+	evidence     storage.SlashingEvidence
+	committee    hotstuff.Replicas
+	epochForView func(view uint64) (uint64, error)
+	headers      storage.Headers
+}
+
+var _ hotstuff.ProposalViolationConsumer = (*SlashingEvidenceConsumer)(nil)
+var _ hotstuff.VoteAggregationViolationConsumer = (*SlashingEvidenceConsumer)(nil)
+var _ hotstuff.TimeoutAggregationViolationConsumer = (*SlashingEvidenceConsumer)(nil)
+
+// NewSlashingEvidenceConsumer creates a consumer persisting evidence of offenses within the
+// consensus instance of the given chain.
+//   - committee is used to look up the offender's keys
+//   - epochForView returns the counter of the epoch containing the given view
+//   - headers is used to look up the signed headers of double proposals
+func NewSlashingEvidenceConsumer(
+	log zerolog.Logger,
+	chainID flow.ChainID,
+	evidence storage.SlashingEvidence,
+	committee hotstuff.Replicas,
+	epochForView func(view uint64) (uint64, error),
+	headers storage.Headers,
+) *SlashingEvidenceConsumer {
+	return &SlashingEvidenceConsumer{
+		log:          log.With().Str("component", "slashing_evidence").Str("chain_id", chainID.String()).Logger(),
+		chainID:      chainID,
+		evidence:     evidence,
+		committee:    committee,
+		epochForView: epochForView,
+		headers:      headers,
+	}
+}
+
+// OnInvalidBlockDetected is a no-op, as invalid proposals are not self-contained evidence.
+func (c *SlashingEvidenceConsumer) OnInvalidBlockDetected(flow.Slashable[model.InvalidProposalError]) {
+}
+
+func (c *SlashingEvidenceConsumer) OnDoubleProposeDetected(block1 *model.Block, block2 *model.Block) {
+	evidence := &model.SlashingEvidence{
+		Kind:     model.EvidenceDoubleProposal,
+		View:     block1.View,
+		Offender: block1.ProposerID,
+		Blocks:   []*model.Block{block1, block2},
+	}
+
+	// the proposer signatures are not part of the notification, include the signed
+	// headers if both blocks are stored locally
+	headers := make([]*flow.Header, 0, 2)
+	for _, block := range evidence.Blocks {
+		header, err := c.headers.ByBlockID(block.BlockID)
+		if err != nil {
+			c.log.Debug().Err(err).Hex("block_id", block.BlockID[:]).Msg("signed header of double proposal not available")
+			break
+		}
+		headers = append(headers, header)
+	}
+	if len(headers) == len(evidence.Blocks) {
+		evidence.Headers = headers
+	}
+
+	c.record(evidence)
+}
+
+func (c *SlashingEvidenceConsumer) OnDoubleVotingDetected(vote1 *model.Vote, vote2 *model.Vote) {
+	c.record(&model.SlashingEvidence{
+		Kind:     model.EvidenceDoubleVote,
+		View:     vote1.View,
+		Offender: vote1.SignerID,
+		Votes:    []*model.Vote{vote1, vote2},
+	})
+}
+
+// OnInvalidVoteDetected is a no-op, as invalid votes are not self-contained evidence.
+func (c *SlashingEvidenceConsumer) OnInvalidVoteDetected(model.InvalidVoteError) {}
+
+// OnVoteForInvalidBlockDetected is a no-op, as votes for invalid blocks are not self-contained evidence.
+func (c *SlashingEvidenceConsumer) OnVoteForInvalidBlockDetected(*model.Vote, *model.SignedProposal) {
+}
+
+func (c *SlashingEvidenceConsumer) OnDoubleTimeoutDetected(timeout *model.TimeoutObject, altTimeout *model.TimeoutObject) {
+	c.record(&model.SlashingEvidence{
+		Kind:     model.EvidenceDoubleTimeout,
+		View:     timeout.View,
+		Offender: timeout.SignerID,
+		Timeouts: []*model.TimeoutObject{timeout, altTimeout},
+	})
+}
+
+// OnInvalidTimeoutDetected is a no-op, as invalid timeouts are not self-contained evidence.
+func (c *SlashingEvidenceConsumer) OnInvalidTimeoutDetected(model.InvalidTimeoutError) {}
+
+// record completes the evidence with the context of the offense and persists it, if the
+// evidence proves the offense on its own.
+func (c *SlashingEvidenceConsumer) record(evidence *model.SlashingEvidence) {
+	evidence.ChainID = c.chainID
+	evidence.DetectedAt = time.Now().UTC()
+
+	log := c.log.With().
+		Str("kind", string(evidence.Kind)).
+		Uint64("view", evidence.View).
+		Hex("offender", evidence.Offender[:]).
+		Logger()
+
+	epoch, err := c.epochForView(evidence.View)
+	if err != nil {
+		log.Warn().Err(err).Msg("could not determine epoch of slashable offense")
+	} else {
+		evidence.Epoch = epoch
+	}
+
+	identity, err := c.committee.IdentityByEpoch(evidence.View, evidence.Offender)
+	if err != nil {
+		log.Warn().Err(err).Msg("could not determine identity of offender")
+	} else {
+		evidence.OffenderIdentity = identity
+	}
+
+	if identity != nil && identity.Role == flow.RoleConsensus {
+		evidence.OffenderBeaconKey = c.beaconKey(evidence.View, evidence.Offender)
+	}
+
+	evidenceID := evidence.ID()
+	err = verification.VerifySlashingEvidence(evidence)
+	if err != nil {
+		// evidence which does not verify is not persisted, otherwise any node could frame
+		// others with forged artifacts or exhaust our disk space
+		log.Warn().Err(err).Hex("evidence_id", evidenceID[:]).Msg("slashing evidence does not prove offense, not persisting it")
+		return
+	}
+
+	stored, err := c.evidence.Store(evidence)
+	if err != nil {
+		log.Error().Err(err).Hex("evidence_id", evidenceID[:]).Msg("could not persist slashing evidence")
+		return
+	}
+	if !stored {
+		log.Debug().Hex("evidence_id", evidenceID[:]).Msg("slashing evidence already persisted")
+		return
+	}
+
+	log.Warn().
+		Bool(logging.KeySuspicious, true).
+		Hex("evidence_id", evidenceID[:]).
+		Uint64("epoch", evidence.Epoch).
+		Msg("persisted slashing evidence")
+}
+
+// beaconKey returns the encoded random beacon key share of the participant in the epoch
+// of the given view, or nil if the participant has no key share.
+func (c *SlashingEvidenceConsumer) beaconKey(view uint64, participantID flow.Identifier) []byte {
+	dkg, err := c.committee.DKG(view)
+	if err != nil {
+		c.log.Debug().Err(err).Uint64("view", view).Msg("could not retrieve DKG")
+		return nil
+	}
+	key, err := dkg.KeyShare(participantID)
+	if err != nil {
+		return nil
+	}
+	return key.Encode()
+}
@@ -0,0 +1,69 @@
+package notifications_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/helper"
+	"github.com/onflow/flow-go/consensus/hotstuff/mocks"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/local"
+	"github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/storage/operation/dbtest"
+	"github.com/onflow/flow-go/storage/store"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestSlashingEvidenceConsumer tests that only evidence of equivocation which is validly signed by
+// the offender is persisted, so that evidence cannot be forged in the name of honest nodes.
+func TestSlashingEvidenceConsumer(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		stakingPriv := unittest.StakingPrivKeyFixture()
+		offender := unittest.IdentityFixture(unittest.WithRole(flow.RoleCollection), unittest.WithStakingPubKey(stakingPriv.PublicKey()))
+		me, err := local.New(offender.IdentitySkeleton, stakingPriv)
+		require.NoError(t, err)
+		signer := verification.NewStakingSigner(me)
+
+		committee := mocks.NewReplicas(t)
+		committee.On("IdentityByEpoch", uint64(100), offender.NodeID).Return(&offender.IdentitySkeleton, nil).Maybe()
+		evidenceStore := store.NewSlashingEvidence(db)
+		consumer := notifications.NewSlashingEvidenceConsumer(
+			unittest.Logger(),
+			flow.Emulator,
+			evidenceStore,
+			committee,
+			func(uint64) (uint64, error) { return 1, nil },
+			storagemock.NewHeaders(t),
+		)
+
+		vote1, err := signer.CreateVote(helper.MakeBlock(helper.WithBlockView(100)))
+		require.NoError(t, err)
+		vote2, err := signer.CreateVote(helper.MakeBlock(helper.WithBlockView(100)))
+		require.NoError(t, err)
+
+		// a forged vote in the name of the offender does not prove a double vote
+		forged := unittest.VoteFixture(unittest.WithVoteSignerID(offender.NodeID), unittest.WithVoteView(100))
+		consumer.OnDoubleVotingDetected(vote1, forged)
+
+		// invalid artifacts are not persisted
+		consumer.OnInvalidVoteDetected(model.InvalidVoteError{Vote: forged})
+		timeout := helper.TimeoutObjectFixture(helper.WithTimeoutObjectSignerID(offender.NodeID), helper.WithTimeoutObjectView(100))
+		consumer.OnInvalidTimeoutDetected(model.InvalidTimeoutError{Timeout: timeout})
+
+		all, err := evidenceStore.All()
+		require.NoError(t, err)
+		require.Empty(t, all)
+
+		consumer.OnDoubleVotingDetected(vote1, vote2)
+		all, err = evidenceStore.All()
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, model.EvidenceDoubleVote, all[0].Kind)
+		require.NoError(t, verification.VerifySlashingEvidence(all[0]))
+	})
+}
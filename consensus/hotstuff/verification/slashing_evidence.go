@@ -0,0 +1,212 @@
+package verification
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/onflow/crypto"
+	"github.com/onflow/crypto/hash"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/encoding"
+	"github.com/onflow/flow-go/model/flow"
+	msig "github.com/onflow/flow-go/module/signature"
+)
+
+// ErrEvidenceNotSelfContained is returned when verifying evidence whose validity cannot be
+// established from the evidence alone. This is the case for double proposals of blocks whose
+// full headers, including the proposer signatures, were not available locally.
+var ErrEvidenceNotSelfContained = errors.New("slashing evidence is not self-contained")
+
+// VerifySlashingEvidence verifies that the evidence proves an equivocation of the offender,
+// i.e. that it contains two different artifacts for the same view, both validly signed with
+// the offender's keys included in the evidence. It is up to the caller to check that the
+// included keys are the keys of the offender in the evidence's epoch.
+// Returns:
+//   - nil if the evidence proves the equivocation
+//   - ErrEvidenceNotSelfContained if the evidence cannot be verified on its own
+//   - generic error if the evidence is malformed or does not prove the violation
+func VerifySlashingEvidence(evidence *model.SlashingEvidence) error {
+	switch evidence.Kind {
+	case model.EvidenceDoubleVote, model.EvidenceDoubleTimeout, model.EvidenceDoubleProposal:
+	default:
+		return fmt.Errorf("evidence of kind %s: %w", evidence.Kind, ErrEvidenceNotSelfContained)
+	}
+
+	offender := evidence.OffenderIdentity
+	if offender == nil {
+		return fmt.Errorf("evidence does not include the identity of the offender")
+	}
+	if offender.NodeID != evidence.Offender {
+		return fmt.Errorf("included identity %v is not the offender %v", offender.NodeID, evidence.Offender)
+	}
+
+	verifier, err := newEvidenceVerifier(offender, evidence.OffenderBeaconKey)
+	if err != nil {
+		return err
+	}
+
+	switch evidence.Kind {
+	case model.EvidenceDoubleVote:
+		return verifier.verifyDoubleVote(evidence)
+	case model.EvidenceDoubleTimeout:
+		return verifier.verifyDoubleTimeout(evidence)
+	default:
+		return verifier.verifyDoubleProposal(evidence)
+	}
+}
+
+// evidenceVerifier verifies the signatures of a single HotStuff participant, using the
+// signing tags of the participant's consensus committee.
+type evidenceVerifier struct {
+	offender      *flow.IdentitySkeleton
+	beaconKey     crypto.PublicKey
+	stakingHasher hash.Hasher
+	timeoutHasher hash.Hasher
+	beaconHasher  hash.Hasher
+}
+
+func newEvidenceVerifier(offender *flow.IdentitySkeleton, encodedBeaconKey []byte) (*evidenceVerifier, error) {
+	v := &evidenceVerifier{
+		offender: offender,
+	}
+
+	switch offender.Role {
+	case flow.RoleConsensus:
+		v.stakingHasher = msig.NewBLSHasher(msig.ConsensusVoteTag)
+		v.timeoutHasher = msig.NewBLSHasher(msig.ConsensusTimeoutTag)
+		v.beaconHasher = msig.NewBLSHasher(msig.RandomBeaconTag)
+		if len(encodedBeaconKey) > 0 {
+			beaconKey, err := crypto.DecodePublicKey(crypto.BLSBLS12381, encodedBeaconKey)
+			if err != nil {
+				return nil, fmt.Errorf("could not decode random beacon key of the offender: %w", err)
+			}
+			v.beaconKey = beaconKey
+		}
+	case flow.RoleCollection:
+		v.stakingHasher = msig.NewBLSHasher(msig.CollectorVoteTag)
+		v.timeoutHasher = msig.NewBLSHasher(msig.CollectorTimeoutTag)
+	default:
+		return nil, fmt.Errorf("offender has role %s, which does not participate in HotStuff", offender.Role)
+	}
+
+	return v, nil
+}
+
+func (v *evidenceVerifier) verifyDoubleVote(evidence *model.SlashingEvidence) error {
+	if len(evidence.Votes) != 2 {
+		return fmt.Errorf("double vote evidence must include 2 votes, got %d", len(evidence.Votes))
+	}
+	for _, vote := range evidence.Votes {
+		if vote.View != evidence.View || vote.SignerID != evidence.Offender {
+			return fmt.Errorf("vote for block %v is not signed by the offender for view %d", vote.BlockID, evidence.View)
+		}
+		err := v.verifyVoteSig(vote.SigData, vote.View, vote.BlockID)
+		if err != nil {
+			return fmt.Errorf("invalid vote for block %v: %w", vote.BlockID, err)
+		}
+	}
+	if evidence.Votes[0].BlockID == evidence.Votes[1].BlockID {
+		return fmt.Errorf("votes are for the same block %v", evidence.Votes[0].BlockID)
+	}
+	return nil
+}
+
+func (v *evidenceVerifier) verifyDoubleTimeout(evidence *model.SlashingEvidence) error {
+	if len(evidence.Timeouts) != 2 {
+		return fmt.Errorf("double timeout evidence must include 2 timeouts, got %d", len(evidence.Timeouts))
+	}
+	for _, timeout := range evidence.Timeouts {
+		if timeout.View != evidence.View || timeout.SignerID != evidence.Offender {
+			return fmt.Errorf("timeout %v is not signed by the offender for view %d", timeout.ID(), evidence.View)
+		}
+		if timeout.NewestQC == nil {
+			return fmt.Errorf("timeout %v has no newest QC", timeout.ID())
+		}
+		msg := MakeTimeoutMessage(timeout.View, timeout.NewestQC.View)
+		valid, err := v.offender.StakingPubKey.Verify(timeout.SigData, msg, v.timeoutHasher)
+		if err != nil {
+			return fmt.Errorf("could not verify signature of timeout %v: %w", timeout.ID(), err)
+		}
+		if !valid {
+			return fmt.Errorf("invalid signature of timeout %v: %w", timeout.ID(), model.ErrInvalidSignature)
+		}
+	}
+	if evidence.Timeouts[0].ID() == evidence.Timeouts[1].ID() {
+		return fmt.Errorf("timeouts are identical")
+	}
+	return nil
+}
+
+func (v *evidenceVerifier) verifyDoubleProposal(evidence *model.SlashingEvidence) error {
+	if len(evidence.Blocks) != 2 {
+		return fmt.Errorf("double proposal evidence must include 2 blocks, got %d", len(evidence.Blocks))
+	}
+	for _, block := range evidence.Blocks {
+		if block.View != evidence.View || block.ProposerID != evidence.Offender {
+			return fmt.Errorf("block %v is not proposed by the offender for view %d", block.BlockID, evidence.View)
+		}
+	}
+	if evidence.Blocks[0].BlockID == evidence.Blocks[1].BlockID {
+		return fmt.Errorf("blocks are identical")
+	}
+	if len(evidence.Headers) != 2 {
+		return fmt.Errorf("evidence includes %d of 2 signed headers: %w", len(evidence.Headers), ErrEvidenceNotSelfContained)
+	}
+
+	for i, header := range evidence.Headers {
+		blockID := header.ID()
+		if blockID != evidence.Blocks[i].BlockID {
+			return fmt.Errorf("header %v does not match block %v", blockID, evidence.Blocks[i].BlockID)
+		}
+		err := v.verifyVoteSig(header.ProposerSigData, header.View, blockID)
+		if err != nil {
+			return fmt.Errorf("invalid proposer signature for block %v: %w", blockID, err)
+		}
+	}
+	return nil
+}
+
+// verifyVoteSig verifies a vote signature, which is a plain staking signature for collector
+// votes and a staking or random beacon signature prefixed by its type for consensus votes.
+func (v *evidenceVerifier) verifyVoteSig(sigData []byte, view uint64, blockID flow.Identifier) error {
+	msg := MakeVoteMessage(view, blockID)
+
+	key, hasher, sig := v.offender.StakingPubKey, v.stakingHasher, crypto.Signature(sigData)
+	if v.offender.Role == flow.RoleConsensus {
+		sigType, decoded, err := msig.DecodeSingleSig(sigData)
+		if err != nil {
+			return fmt.Errorf("could not decode signature: %w", err)
+		}
+		sig = decoded
+		if sigType == encoding.SigTypeRandomBeacon {
+			if v.beaconKey == nil {
+				return fmt.Errorf("evidence does not include the random beacon key of the offender")
+			}
+			key, hasher = v.beaconKey, v.beaconHasher
+		}
+	}
+
+	valid, err := key.Verify(sig, msg, hasher)
+	if err != nil {
+		return fmt.Errorf("could not verify signature: %w", err)
+	}
+	if !valid {
+		return model.ErrInvalidSignature
+	}
+	return nil
+}
+
+// SlashingEvidenceStatus returns a human-readable summary of the result of
+// VerifySlashingEvidence: "verified", "not self-contained" or "invalid: <reason>".
+func SlashingEvidenceStatus(evidence *model.SlashingEvidence) string {
+	err := VerifySlashingEvidence(evidence)
+	switch {
+	case err == nil:
+		return "verified"
+	case errors.Is(err, ErrEvidenceNotSelfContained):
+		return "not self-contained"
+	default:
+		return fmt.Sprintf("invalid: %v", err)
+	}
+}
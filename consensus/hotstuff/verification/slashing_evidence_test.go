@@ -0,0 +1,164 @@
+package verification
+
+import (
+	"testing"
+
+	"github.com/onflow/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/helper"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/encoding"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/local"
+	msig "github.com/onflow/flow-go/module/signature"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestVerifySlashingEvidence_Collector verifies evidence of equivocation by collection nodes,
+// which sign votes and timeouts with their staking key only.
+func TestVerifySlashingEvidence_Collector(t *testing.T) {
+	stakingPriv := unittest.StakingPrivKeyFixture()
+	identity := unittest.IdentityFixture(unittest.WithRole(flow.RoleCollection), unittest.WithStakingPubKey(stakingPriv.PublicKey()))
+	me, err := local.New(identity.IdentitySkeleton, stakingPriv)
+	require.NoError(t, err)
+	signer := NewStakingSigner(me)
+
+	view := uint64(100)
+
+	t.Run("double vote", func(t *testing.T) {
+		vote1, err := signer.CreateVote(helper.MakeBlock(helper.WithBlockView(view)))
+		require.NoError(t, err)
+		vote2, err := signer.CreateVote(helper.MakeBlock(helper.WithBlockView(view)))
+		require.NoError(t, err)
+
+		evidence := &model.SlashingEvidence{
+			Kind:             model.EvidenceDoubleVote,
+			View:             view,
+			Offender:         identity.NodeID,
+			OffenderIdentity: &identity.IdentitySkeleton,
+			Votes:            []*model.Vote{vote1, vote2},
+		}
+		require.NoError(t, VerifySlashingEvidence(evidence))
+		require.Equal(t, "verified", SlashingEvidenceStatus(evidence))
+
+		// votes for the same block are no equivocation
+		evidence.Votes = []*model.Vote{vote1, vote1}
+		require.Error(t, VerifySlashingEvidence(evidence))
+
+		// tampered signatures are rejected
+		tampered := *vote2
+		tampered.SigData = vote1.SigData
+		evidence.Votes = []*model.Vote{vote1, &tampered}
+		err = VerifySlashingEvidence(evidence)
+		require.ErrorIs(t, err, model.ErrInvalidSignature)
+
+		// signatures must be verified with the offender's key
+		other := unittest.IdentityFixture(unittest.WithNodeID(identity.NodeID), unittest.WithRole(flow.RoleCollection))
+		evidence.Votes = []*model.Vote{vote1, vote2}
+		evidence.OffenderIdentity = &other.IdentitySkeleton
+		require.ErrorIs(t, VerifySlashingEvidence(evidence), model.ErrInvalidSignature)
+	})
+
+	t.Run("double timeout", func(t *testing.T) {
+		timeout1, err := signer.CreateTimeout(view, helper.MakeQC(helper.WithQCView(view-1)), nil)
+		require.NoError(t, err)
+		timeout2, err := signer.CreateTimeout(view, helper.MakeQC(helper.WithQCView(view-2)), helper.MakeTC(helper.WithTCView(view-1)))
+		require.NoError(t, err)
+
+		evidence := &model.SlashingEvidence{
+			Kind:             model.EvidenceDoubleTimeout,
+			View:             view,
+			Offender:         identity.NodeID,
+			OffenderIdentity: &identity.IdentitySkeleton,
+			Timeouts:         []*model.TimeoutObject{timeout1, timeout2},
+		}
+		require.NoError(t, VerifySlashingEvidence(evidence))
+
+		evidence.Timeouts = []*model.TimeoutObject{timeout1, timeout1}
+		require.Error(t, VerifySlashingEvidence(evidence))
+	})
+
+	t.Run("double proposal", func(t *testing.T) {
+		sign := func(header *flow.Header) {
+			vote, err := signer.CreateVote(model.BlockFromFlow(header))
+			require.NoError(t, err)
+			header.ProposerSigData = vote.SigData
+		}
+		withProposer := func(header *flow.Header) { header.ProposerID = identity.NodeID }
+		header1 := unittest.BlockHeaderFixture(unittest.HeaderWithView(view), withProposer)
+		header2 := unittest.BlockHeaderFixture(unittest.HeaderWithView(view), withProposer)
+		sign(header1)
+		sign(header2)
+
+		evidence := &model.SlashingEvidence{
+			Kind:             model.EvidenceDoubleProposal,
+			View:             view,
+			Offender:         identity.NodeID,
+			OffenderIdentity: &identity.IdentitySkeleton,
+			Blocks:           []*model.Block{model.BlockFromFlow(header1), model.BlockFromFlow(header2)},
+		}
+		// without the signed headers, the blocks do not prove the proposals
+		require.ErrorIs(t, VerifySlashingEvidence(evidence), ErrEvidenceNotSelfContained)
+
+		evidence.Headers = []*flow.Header{header1, header2}
+		require.NoError(t, VerifySlashingEvidence(evidence))
+	})
+
+	t.Run("unknown kinds are not self-contained", func(t *testing.T) {
+		vote, err := signer.CreateVote(helper.MakeBlock(helper.WithBlockView(view)))
+		require.NoError(t, err)
+
+		evidence := &model.SlashingEvidence{
+			Kind:             "invalid_vote",
+			View:             view,
+			Offender:         identity.NodeID,
+			OffenderIdentity: &identity.IdentitySkeleton,
+			Votes:            []*model.Vote{vote},
+		}
+		require.ErrorIs(t, VerifySlashingEvidence(evidence), ErrEvidenceNotSelfContained)
+		require.Equal(t, "not self-contained", SlashingEvidenceStatus(evidence))
+	})
+}
+
+// TestVerifySlashingEvidence_Consensus verifies evidence of double votes by consensus nodes,
+// which sign votes with either their staking key or their random beacon key.
+func TestVerifySlashingEvidence_Consensus(t *testing.T) {
+	stakingPriv := unittest.StakingPrivKeyFixture()
+	beaconPriv := unittest.RandomBeaconPriv()
+	identity := unittest.IdentityFixture(unittest.WithRole(flow.RoleConsensus), unittest.WithStakingPubKey(stakingPriv.PublicKey()))
+
+	view := uint64(100)
+	vote := func(key crypto.PrivateKey, tag string, sigType encoding.SigType) *model.Vote {
+		blockID := unittest.IdentifierFixture()
+		sig, err := key.Sign(MakeVoteMessage(view, blockID), msig.NewBLSHasher(tag))
+		require.NoError(t, err)
+		return &model.Vote{
+			View:     view,
+			BlockID:  blockID,
+			SignerID: identity.NodeID,
+			SigData:  msig.EncodeSingleSig(sigType, sig),
+		}
+	}
+
+	evidence := &model.SlashingEvidence{
+		Kind:             model.EvidenceDoubleVote,
+		View:             view,
+		Offender:         identity.NodeID,
+		OffenderIdentity: &identity.IdentitySkeleton,
+		Votes: []*model.Vote{
+			vote(stakingPriv, msig.ConsensusVoteTag, encoding.SigTypeStaking),
+			vote(beaconPriv, msig.RandomBeaconTag, encoding.SigTypeRandomBeacon),
+		},
+	}
+
+	// the beacon signature can only be verified with the beacon key
+	require.Error(t, VerifySlashingEvidence(evidence))
+
+	evidence.OffenderBeaconKey = beaconPriv.PublicKey().Encode()
+	require.NoError(t, VerifySlashingEvidence(evidence))
+
+	// signatures with the collector tag are not valid consensus votes
+	evidence.Votes[0] = vote(stakingPriv, msig.CollectorVoteTag, encoding.SigTypeStaking)
+	require.ErrorIs(t, VerifySlashingEvidence(evidence), model.ErrInvalidSignature)
+}
@@ -0,0 +1,197 @@
+package simulator
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/committees"
+	"github.com/onflow/flow-go/consensus/hotstuff/committees/leader"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state"
+)
+
+// lastEpochViews is the number of views for which leaders are selected in the last epoch,
+// which otherwise has no final view.
+const lastEpochViews = 100_000
+
+// blockStore holds the headers of all blocks produced during the simulation, shared by all
+// replicas. It stands in for the replicas' protocol state, which in a real network only
+// contains the blocks a replica received.
+type blockStore struct {
+	mu      sync.RWMutex
+	headers map[flow.Identifier]*flow.Header
+}
+
+func newBlockStore() *blockStore {
+	return &blockStore{headers: make(map[flow.Identifier]*flow.Header)}
+}
+
+func (s *blockStore) add(header *flow.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.headers[header.ID()] = header
+}
+
+func (s *blockStore) byID(blockID flow.Identifier) (*flow.Header, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	header, ok := s.headers[blockID]
+	return header, ok
+}
+
+// epoch is the consensus committee of the views [firstView, finalView].
+type epoch struct {
+	firstView  uint64
+	finalView  uint64
+	identities flow.IdentityList // in canonical order
+	leaders    *leader.LeaderSelection
+	qcWeight   uint64
+	tcWeight   uint64
+}
+
+// newEpochs creates the committees of the epoch schedule of the scenario, given the
+// identities of all replicas. Leader selection of each epoch is seeded by the given seeds.
+func newEpochs(scenario *Scenario, identities flow.IdentityList, seeds [][]byte) ([]*epoch, error) {
+	schedule := scenario.Epochs
+	if len(schedule) == 0 {
+		all := make([]int, len(identities))
+		for i := range all {
+			all[i] = i
+		}
+		schedule = []Epoch{{FirstView: 0, Nodes: all}}
+	}
+
+	epochs := make([]*epoch, 0, len(schedule))
+	for i, spec := range schedule {
+		finalView := spec.FirstView + lastEpochViews - 1
+		if i+1 < len(schedule) {
+			finalView = schedule[i+1].FirstView - 1
+		}
+		members := make(flow.IdentityList, 0, len(spec.Nodes))
+		seen := make(map[int]bool)
+		for _, node := range spec.Nodes {
+			if !seen[node] {
+				seen[node] = true
+				members = append(members, identities[node])
+			}
+		}
+		members = members.Sort(flow.Canonical[flow.Identity])
+		leaders, err := leader.SelectionForConsensus(members.ToSkeleton(), seeds[i], spec.FirstView, finalView)
+		if err != nil {
+			return nil, fmt.Errorf("could not select leaders of epoch %d: %w", i, err)
+		}
+		totalWeight := members.ToSkeleton().TotalWeight()
+		epochs = append(epochs, &epoch{
+			firstView:  spec.FirstView,
+			finalView:  finalView,
+			identities: members,
+			leaders:    leaders,
+			qcWeight:   committees.WeightThresholdToBuildQC(totalWeight),
+			tcWeight:   committees.WeightThresholdToTimeout(totalWeight),
+		})
+	}
+	return epochs, nil
+}
+
+// committee implements hotstuff.DynamicCommittee for a replica, based on the static epoch
+// schedule of the scenario. The identity table does not change within an epoch, so the
+// committee of a block is the committee of the epoch containing the block's view.
+type committee struct {
+	self   flow.Identifier
+	epochs []*epoch
+	blocks *blockStore
+}
+
+var _ hotstuff.DynamicCommittee = (*committee)(nil)
+
+// epochOf returns the epoch containing the view.
+// Expected errors:
+//   - model.ErrViewForUnknownEpoch if the view is not within any epoch
+func (c *committee) epochOf(view uint64) (*epoch, error) {
+	for i := len(c.epochs) - 1; i >= 0; i-- {
+		e := c.epochs[i]
+		if view >= e.firstView && view <= e.finalView {
+			return e, nil
+		}
+	}
+	return nil, model.ErrViewForUnknownEpoch
+}
+
+func (c *committee) LeaderForView(view uint64) (flow.Identifier, error) {
+	e, err := c.epochOf(view)
+	if err != nil {
+		return flow.ZeroID, err
+	}
+	return e.leaders.LeaderForView(view)
+}
+
+func (c *committee) QuorumThresholdForView(view uint64) (uint64, error) {
+	e, err := c.epochOf(view)
+	if err != nil {
+		return 0, err
+	}
+	return e.qcWeight, nil
+}
+
+func (c *committee) TimeoutThresholdForView(view uint64) (uint64, error) {
+	e, err := c.epochOf(view)
+	if err != nil {
+		return 0, err
+	}
+	return e.tcWeight, nil
+}
+
+func (c *committee) Self() flow.Identifier {
+	return c.self
+}
+
+// DKG is not supported, the simulated replicas only use staking signatures.
+func (c *committee) DKG(uint64) (hotstuff.DKG, error) {
+	return nil, fmt.Errorf("the simulated committee has no random beacon")
+}
+
+func (c *committee) IdentitiesByEpoch(view uint64) (flow.IdentitySkeletonList, error) {
+	e, err := c.epochOf(view)
+	if err != nil {
+		return nil, err
+	}
+	return e.identities.ToSkeleton(), nil
+}
+
+func (c *committee) IdentityByEpoch(view uint64, participantID flow.Identifier) (*flow.IdentitySkeleton, error) {
+	e, err := c.epochOf(view)
+	if err != nil {
+		return nil, err
+	}
+	identity, ok := e.identities.ByNodeID(participantID)
+	if !ok {
+		return nil, model.NewInvalidSignerErrorf("node %v is not a consensus participant at view %d", participantID, view)
+	}
+	return &identity.IdentitySkeleton, nil
+}
+
+func (c *committee) IdentitiesByBlock(blockID flow.Identifier) (flow.IdentityList, error) {
+	header, ok := c.blocks.byID(blockID)
+	if !ok {
+		return nil, state.ErrUnknownSnapshotReference
+	}
+	e, err := c.epochOf(header.View)
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch of block %v: %w", blockID, err)
+	}
+	return e.identities, nil
+}
+
+func (c *committee) IdentityByBlock(blockID flow.Identifier, participantID flow.Identifier) (*flow.Identity, error) {
+	identities, err := c.IdentitiesByBlock(blockID)
+	if err != nil {
+		return nil, err
+	}
+	identity, ok := identities.ByNodeID(participantID)
+	if !ok {
+		return nil, model.NewInvalidSignerErrorf("node %v is not a consensus participant at block %v", participantID, blockID)
+	}
+	return identity, nil
+}
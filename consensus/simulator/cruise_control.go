@@ -0,0 +1,83 @@
+package simulator
+
+import (
+	"time"
+
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/state/protocol"
+)
+
+// cruiseState implements the parts of protocol.State used by the block time controller.
+// All epochs of the simulation target the same view time, so the controller is given a
+// single epoch spanning the whole epoch schedule, which has the same timing as switching
+// between the scheduled epochs. Hence, the controller never needs the next epoch.
+type cruiseState struct {
+	protocol.State
+	epoch *cruiseEpoch
+}
+
+var _ protocol.State = (*cruiseState)(nil)
+
+// newCruiseState creates the state of an epoch from view 0 up to finalView, whose views
+// take targetViewTime from the given start.
+func newCruiseState(start time.Time, finalView uint64, targetViewTime time.Duration) *cruiseState {
+	duration := time.Duration(finalView+1) * targetViewTime
+	return &cruiseState{epoch: &cruiseEpoch{
+		finalView:      finalView,
+		targetDuration: uint64(duration / time.Second),
+		targetEndTime:  uint64(start.Add(duration).Unix()),
+	}}
+}
+
+func (s *cruiseState) Final() protocol.Snapshot {
+	return &cruiseSnapshot{epoch: s.epoch}
+}
+
+func (s *cruiseState) AtHeight(uint64) protocol.Snapshot {
+	return &cruiseSnapshot{epoch: s.epoch}
+}
+
+type cruiseSnapshot struct {
+	protocol.Snapshot
+	epoch *cruiseEpoch
+}
+
+func (s *cruiseSnapshot) Epochs() protocol.EpochQuery {
+	return &cruiseEpochQuery{epoch: s.epoch}
+}
+
+type cruiseEpochQuery struct {
+	protocol.EpochQuery
+	epoch *cruiseEpoch
+}
+
+func (q *cruiseEpochQuery) Current() (protocol.CommittedEpoch, error) {
+	return q.epoch, nil
+}
+
+func (q *cruiseEpochQuery) NextCommitted() (protocol.CommittedEpoch, error) {
+	return nil, protocol.ErrNextEpochNotCommitted
+}
+
+type cruiseEpoch struct {
+	protocol.CommittedEpoch
+	finalView      uint64
+	targetDuration uint64
+	targetEndTime  uint64
+}
+
+func (e *cruiseEpoch) FirstView() uint64      { return 0 }
+func (e *cruiseEpoch) FinalView() uint64      { return e.finalView }
+func (e *cruiseEpoch) TargetDuration() uint64 { return e.targetDuration }
+func (e *cruiseEpoch) TargetEndTime() uint64  { return e.targetEndTime }
+
+// noopCruiseCtlMetrics discards the metrics of the block time controller, as the metrics of
+// the replicas would collide in the metrics registry.
+type noopCruiseCtlMetrics struct{}
+
+var _ module.CruiseCtlMetrics = noopCruiseCtlMetrics{}
+
+func (noopCruiseCtlMetrics) PIDError(float64, float64, float64)     {}
+func (noopCruiseCtlMetrics) TargetProposalDuration(time.Duration)   {}
+func (noopCruiseCtlMetrics) ControllerOutput(time.Duration)         {}
+func (noopCruiseCtlMetrics) ProposalPublicationDelay(time.Duration) {}
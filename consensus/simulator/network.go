@@ -0,0 +1,160 @@
+package simulator
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+// network is an in-memory network connecting the replicas. Every message is delivered after
+// a latency sampled from the distribution of its link, unless the link is cut by a partition
+// or a crash at the time the message is sent or delivered.
+type network struct {
+	ctx     context.Context
+	start   time.Time
+	nodes   []*node
+	latency [][]Latency // by sender and recipient
+
+	partitions []partition
+	crashes    []Crash
+
+	mu  sync.Mutex // protects rng
+	rng *rand.Rand
+
+	sent      *atomic.Uint64
+	delivered *atomic.Uint64
+	dropped   *atomic.Uint64
+}
+
+// partition is a Partition with the group of every replica resolved.
+type partition struct {
+	start, end time.Duration
+	group      []int // by replica, replicas not listed in the scenario are in group -1
+}
+
+func newNetwork(scenario *Scenario) *network {
+	latency := make([][]Latency, scenario.Nodes)
+	for from := range latency {
+		latency[from] = make([]Latency, scenario.Nodes)
+		for to := range latency[from] {
+			latency[from][to] = scenario.Latency
+		}
+	}
+	matches := func(nodes []int, node int) bool {
+		if len(nodes) == 0 {
+			return true
+		}
+		for _, n := range nodes {
+			if n == node {
+				return true
+			}
+		}
+		return false
+	}
+	for _, link := range scenario.Links {
+		for from := range latency {
+			for to := range latency[from] {
+				if matches(link.From, from) && matches(link.To, to) {
+					latency[from][to] = link.Latency
+				}
+			}
+		}
+	}
+
+	partitions := make([]partition, 0, len(scenario.Partitions))
+	for _, p := range scenario.Partitions {
+		group := make([]int, scenario.Nodes)
+		for i := range group {
+			group[i] = -1
+		}
+		for g, nodes := range p.Groups {
+			for _, node := range nodes {
+				group[node] = g
+			}
+		}
+		partitions = append(partitions, partition{start: time.Duration(p.Start), end: time.Duration(p.End), group: group})
+	}
+
+	return &network{
+		latency:    latency,
+		partitions: partitions,
+		crashes:    scenario.Crashes,
+		rng:        rand.New(rand.NewSource(scenario.Seed)),
+		sent:       atomic.NewUint64(0),
+		delivered:  atomic.NewUint64(0),
+		dropped:    atomic.NewUint64(0),
+	}
+}
+
+// run starts the clock of the network, all messages are dropped after the context is cancelled.
+func (n *network) run(ctx context.Context) {
+	n.ctx = ctx
+	n.start = time.Now()
+}
+
+// crashed returns true if the replica is crashed at the given time since the start.
+func (n *network) crashed(node int, elapsed time.Duration) bool {
+	for _, crash := range n.crashes {
+		if crash.Node != node || elapsed < time.Duration(crash.At) {
+			continue
+		}
+		if crash.Recover == 0 || elapsed < time.Duration(crash.Recover) {
+			return true
+		}
+	}
+	return false
+}
+
+// connected returns true if the link between the replicas is neither cut by a partition nor
+// by a crash of either replica at the current time.
+func (n *network) connected(from, to int) bool {
+	elapsed := time.Since(n.start)
+	if n.crashed(from, elapsed) || n.crashed(to, elapsed) {
+		return false
+	}
+	for _, p := range n.partitions {
+		if elapsed >= p.start && elapsed < p.end && p.group[from] != p.group[to] {
+			return false
+		}
+	}
+	return true
+}
+
+// send delivers the message to the recipient asynchronously, after the latency of the link.
+// Messages from a replica to itself are not sent over the network.
+func (n *network) send(from, to int, message interface{}) {
+	n.sent.Inc()
+	if n.ctx.Err() != nil || !n.connected(from, to) {
+		n.dropped.Inc()
+		return
+	}
+
+	n.mu.Lock()
+	latency, ok := n.latency[from][to].sample(n.rng)
+	n.mu.Unlock()
+	if !ok {
+		n.dropped.Inc()
+		return
+	}
+
+	time.AfterFunc(latency, func() {
+		if n.ctx.Err() != nil || !n.connected(from, to) {
+			n.dropped.Inc()
+			return
+		}
+		n.delivered.Inc()
+		n.nodes[to].deliver(from, message)
+	})
+}
+
+// broadcast sends the message to all replicas except the sender.
+func (n *network) broadcast(from int, message interface{}) {
+	for to := range n.nodes {
+		if to != from {
+			n.send(from, to, message)
+		}
+	}
+}
@@ -0,0 +1,549 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/consensus"
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/cruisectl"
+	"github.com/onflow/flow-go/consensus/hotstuff/eventloop"
+	"github.com/onflow/flow-go/consensus/hotstuff/forks"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications/pubsub"
+	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker"
+	"github.com/onflow/flow-go/consensus/hotstuff/timeoutcollector"
+	validatorImpl "github.com/onflow/flow-go/consensus/hotstuff/validator"
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	"github.com/onflow/flow-go/consensus/hotstuff/votecollector"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+	msig "github.com/onflow/flow-go/module/signature"
+)
+
+// maxSyncBatch is the maximum number of blocks sent in response to a sync request.
+const maxSyncBatch = 64
+
+// syncRetryInterval is the interval after which a missing block is requested again.
+const syncRetryInterval = time.Second
+
+// syncRequest requests a block and its ancestors unknown to the requester.
+type syncRequest struct {
+	BlockID flow.Identifier
+}
+
+// syncResponse contains a block and its ancestors, ordered by increasing height.
+type syncResponse struct {
+	Headers []*flow.Header
+}
+
+// node is a simulated consensus replica. It runs the production HotStuff components (event
+// loop, event handler, pacemaker, safety rules, forks and the vote and timeout aggregators)
+// and stands in for the compliance engine, message hub and block sync of a consensus node:
+// it validates incoming proposals, requests missing ancestors from the sender of a proposal
+// and forwards proposals, votes and timeouts to HotStuff.
+type node struct {
+	notifications.NoopConsumer
+
+	index        int
+	identity     *flow.Identity
+	sim          *Simulator
+	log          zerolog.Logger
+	signer       hotstuff.Signer
+	equivocating bool
+
+	validator         hotstuff.Validator
+	voteAggregator    hotstuff.VoteAggregator
+	timeoutAggregator hotstuff.TimeoutAggregator
+	loop              *eventloop.EventLoop
+	cruiseCtl         *cruisectl.BlockTimeController // nil without cruise control
+
+	currentView     *atomic.Uint64
+	finalizedView   *atomic.Uint64
+	finalizedHeight *atomic.Uint64
+
+	mu        sync.Mutex
+	known     map[flow.Identifier]struct{}
+	pending   map[flow.Identifier][]*flow.Header // proposals by the ID of their missing parent
+	requested map[flow.Identifier]time.Time      // missing blocks by the time they were requested
+}
+
+var _ hotstuff.Consumer = (*node)(nil)
+var _ hotstuff.VoteAggregationViolationConsumer = (*node)(nil)
+var _ hotstuff.TimeoutAggregationViolationConsumer = (*node)(nil)
+var _ module.Finalizer = (*node)(nil)
+
+func newNode(sim *Simulator, index int, identity *flow.Identity, signer hotstuff.Signer, committee hotstuff.DynamicCommittee) (*node, error) {
+	log := sim.log.With().Int("node", index).Logger()
+	root, rootQC := sim.root, sim.rootQC
+	n := &node{
+		index:           index,
+		identity:        identity,
+		sim:             sim,
+		log:             log,
+		signer:          signer,
+		currentView:     atomic.NewUint64(rootQC.View + 1),
+		finalizedView:   atomic.NewUint64(root.View),
+		finalizedHeight: atomic.NewUint64(root.Height),
+		known:           map[flow.Identifier]struct{}{root.ID(): {}},
+		pending:         make(map[flow.Identifier][]*flow.Header),
+		requested:       make(map[flow.Identifier]time.Time),
+	}
+	for _, equivocator := range sim.scenario.Equivocators {
+		if equivocator == index {
+			n.equivocating = true
+		}
+	}
+	collector := metrics.NewNoopCollector()
+
+	notifier := pubsub.NewDistributor()
+	notifier.AddConsumer(n)
+
+	// by convention of Forks, the trusted root block omits its QC
+	rootBlock := model.GenesisBlockFromFlow(root)
+	certifiedRoot, err := model.NewCertifiedBlock(rootBlock, rootQC)
+	if err != nil {
+		return nil, fmt.Errorf("could not create certified root block: %w", err)
+	}
+	finalizer, err := forks.New(&certifiedRoot, n, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("could not create forks: %w", err)
+	}
+
+	n.validator = validatorImpl.New(committee, verification.NewStakingVerifier())
+
+	voteAggregationDistributor := pubsub.NewVoteAggregationDistributor()
+	voteAggregationDistributor.AddVoteAggregationViolationConsumer(n)
+	voteProcessorFactory := votecollector.NewStakingVoteProcessorFactory(committee, voteAggregationDistributor.OnQcConstructedFromVotes)
+	n.voteAggregator, err = consensus.NewVoteAggregator(
+		log,
+		collector,
+		collector,
+		collector,
+		root.View+1,
+		voteAggregationDistributor,
+		voteProcessorFactory,
+		notifier.FollowerDistributor,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create vote aggregator: %w", err)
+	}
+
+	timeoutAggregationDistributor := pubsub.NewTimeoutAggregationDistributor()
+	timeoutAggregationDistributor.AddTimeoutAggregationViolationConsumer(n)
+	timeoutProcessorFactory := timeoutcollector.NewTimeoutProcessorFactory(log, timeoutAggregationDistributor, committee, n.validator, msig.CollectorTimeoutTag)
+	n.timeoutAggregator, err = consensus.NewTimeoutAggregator(
+		log,
+		collector,
+		collector,
+		collector,
+		notifier,
+		timeoutProcessorFactory,
+		timeoutAggregationDistributor,
+		root.View+1,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create timeout aggregator: %w", err)
+	}
+
+	scenario := sim.scenario
+	var proposalDurationProvider hotstuff.ProposalDurationProvider = pacemaker.NewStaticProposalDurationProvider(time.Duration(scenario.ProposalDuration))
+	if scenario.CruiseControl != nil {
+		n.cruiseCtl, err = cruisectl.NewBlockTimeController(log, noopCruiseCtlMetrics{}, scenario.CruiseControl.Config(), sim.cruise, rootQC.View+1)
+		if err != nil {
+			return nil, fmt.Errorf("could not create block time controller: %w", err)
+		}
+		notifier.AddOnBlockIncorporatedConsumer(n.cruiseCtl.OnBlockIncorporated)
+		proposalDurationProvider = n.cruiseCtl
+	}
+
+	n.loop, err = consensus.NewParticipant(
+		log,
+		collector,
+		collector,
+		&builder{blocks: sim.blocks},
+		root,
+		nil,
+		&consensus.HotstuffModules{
+			Committee:                   committee,
+			Signer:                      signer,
+			Persist:                     newPersister(root, rootQC),
+			Notifier:                    notifier,
+			VoteCollectorDistributor:    voteAggregationDistributor.VoteCollectorDistributor,
+			TimeoutCollectorDistributor: timeoutAggregationDistributor.TimeoutCollectorDistributor,
+			Forks:                       finalizer,
+			Validator:                   n.validator,
+			VoteAggregator:              n.voteAggregator,
+			TimeoutAggregator:           n.timeoutAggregator,
+		},
+		consensus.WithMinTimeout(time.Duration(scenario.MinTimeout)),
+		func(cfg *consensus.ParticipantConfig) {
+			cfg.TimeoutMaximum = time.Duration(scenario.MaxTimeout)
+		},
+		consensus.WithTimeoutAdjustmentFactor(scenario.TimeoutAdjustmentFactor),
+		consensus.WithHappyPathMaxRoundFailures(scenario.HappyPathMaxRoundFailures),
+		consensus.WithProposalDurationProvider(proposalDurationProvider),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create participant: %w", err)
+	}
+	return n, nil
+}
+
+// components returns the components of the replica which need to be started.
+func (n *node) components() []module.ReadyDoneAware {
+	components := []module.ReadyDoneAware{n.loop, n.voteAggregator, n.timeoutAggregator}
+	if n.cruiseCtl != nil {
+		components = append(components, n.cruiseCtl)
+	}
+	return components
+}
+
+// deliver processes a message received from the network.
+func (n *node) deliver(from int, message interface{}) {
+	switch msg := message.(type) {
+	case *flow.Header:
+		n.onProposal(from, msg)
+	case *model.Vote:
+		n.voteAggregator.AddVote(msg)
+	case *model.TimeoutObject:
+		n.timeoutAggregator.AddTimeout(msg)
+	case *syncRequest:
+		n.onSyncRequest(from, msg)
+	case *syncResponse:
+		for _, header := range msg.Headers {
+			n.onProposal(from, header)
+		}
+	default:
+		n.sim.throw(fmt.Errorf("unexpected message type %T", message))
+	}
+}
+
+// onProposal processes a proposal received from the network. Proposals whose parent is
+// unknown are cached until the parent is received, and the parent is requested from the sender.
+func (n *node) onProposal(from int, header *flow.Header) {
+	n.mu.Lock()
+	if _, ok := n.known[header.ID()]; ok || header.View <= n.finalizedView.Load() {
+		n.mu.Unlock()
+		return
+	}
+	if _, ok := n.known[header.ParentID]; !ok {
+		n.pending[header.ParentID] = append(n.pending[header.ParentID], header)
+		requestedAt, requested := n.requested[header.ParentID]
+		request := !requested || time.Since(requestedAt) > syncRetryInterval
+		if request {
+			n.requested[header.ParentID] = time.Now()
+		}
+		n.mu.Unlock()
+		if request {
+			n.sim.network.send(n.index, from, &syncRequest{BlockID: header.ParentID})
+		}
+		return
+	}
+	n.mu.Unlock()
+
+	n.processProposal(header)
+}
+
+// processProposal validates a proposal whose parent is known and submits it to HotStuff,
+// followed by all cached descendants.
+func (n *node) processProposal(header *flow.Header) {
+	proposal := model.SignedProposalFromFlow(header)
+	err := n.validator.ValidateProposal(proposal)
+	if err != nil {
+		if model.IsInvalidProposalError(err) {
+			n.sim.stats.onInvalidProposal()
+			n.log.Warn().Err(err).Msg("dropping invalid proposal")
+			return
+		}
+		if errors.Is(err, model.ErrViewForUnknownEpoch) {
+			n.log.Warn().Err(err).Msg("dropping proposal for unknown epoch")
+			return
+		}
+		n.sim.throw(fmt.Errorf("could not validate proposal %v: %w", header.ID(), err))
+		return
+	}
+
+	// proposals are submitted while holding the lock, so that HotStuff always receives a
+	// block after its parent
+	n.mu.Lock()
+	blockID := header.ID()
+	if _, ok := n.known[blockID]; ok {
+		n.mu.Unlock()
+		return
+	}
+	n.known[blockID] = struct{}{}
+	n.voteAggregator.AddBlock(proposal)
+	n.loop.SubmitProposal(proposal)
+	children := n.pending[blockID]
+	delete(n.pending, blockID)
+	delete(n.requested, blockID)
+	n.mu.Unlock()
+
+	for _, child := range children {
+		n.processProposal(child)
+	}
+}
+
+// onSyncRequest responds with the requested block and up to maxSyncBatch of its ancestors
+// known to the replica.
+func (n *node) onSyncRequest(from int, request *syncRequest) {
+	var headers []*flow.Header
+	blockID := request.BlockID
+	n.mu.Lock()
+	for len(headers) < maxSyncBatch {
+		if _, ok := n.known[blockID]; !ok {
+			break
+		}
+		header, ok := n.sim.blocks.byID(blockID)
+		if !ok || header.Height == n.sim.root.Height {
+			break
+		}
+		headers = append(headers, header)
+		blockID = header.ParentID
+	}
+	n.mu.Unlock()
+	if len(headers) == 0 {
+		return
+	}
+
+	for i, j := 0, len(headers)-1; i < j; i, j = i+1, j-1 {
+		headers[i], headers[j] = headers[j], headers[i]
+	}
+	n.sim.network.send(n.index, from, &syncResponse{Headers: headers})
+}
+
+// OnOwnVote sends the vote to the leader of the next view. Equivocating replicas additionally
+// send a vote for a fabricated conflicting block.
+func (n *node) OnOwnVote(blockID flow.Identifier, view uint64, sigData []byte, recipientID flow.Identifier) {
+	recipient, ok := n.sim.indexOf(recipientID)
+	if !ok {
+		n.sim.throw(fmt.Errorf("vote recipient %v is not a simulated replica", recipientID))
+		return
+	}
+	n.sendVote(recipient, model.VoteFromFlow(n.identity.NodeID, blockID, view, sigData))
+
+	if n.equivocating {
+		conflicting := &model.Block{View: view, BlockID: flow.MakeID(struct {
+			View     uint64
+			Conflict flow.Identifier
+		}{view, blockID})}
+		vote, err := n.signer.CreateVote(conflicting)
+		if err != nil {
+			n.sim.throw(fmt.Errorf("could not create conflicting vote: %w", err))
+			return
+		}
+		n.sendVote(recipient, vote)
+	}
+}
+
+func (n *node) sendVote(recipient int, vote *model.Vote) {
+	if recipient == n.index {
+		n.voteAggregator.AddVote(vote)
+		return
+	}
+	n.sim.network.send(n.index, recipient, vote)
+}
+
+// OnOwnTimeout broadcasts the timeout and adds it to the local timeout aggregator.
+func (n *node) OnOwnTimeout(timeout *model.TimeoutObject) {
+	n.timeoutAggregator.AddTimeout(timeout)
+	n.sim.network.broadcast(n.index, timeout)
+}
+
+// OnOwnProposal publishes the proposal at the target publication time: the proposal is
+// submitted to the local HotStuff instance and broadcast to all other replicas. Equivocating
+// replicas send a conflicting proposal to the replicas with odd indices.
+func (n *node) OnOwnProposal(header *flow.Header, targetPublicationTime time.Time) {
+	go func() {
+		select {
+		case <-time.After(time.Until(targetPublicationTime)):
+		case <-n.sim.ctx.Done():
+			return
+		}
+
+		proposal := model.SignedProposalFromFlow(header)
+		n.mu.Lock()
+		n.known[header.ID()] = struct{}{}
+		n.voteAggregator.AddBlock(proposal)
+		n.loop.SubmitProposal(proposal)
+		n.mu.Unlock()
+
+		if !n.equivocating {
+			n.sim.network.broadcast(n.index, header)
+			return
+		}
+		conflicting, err := n.conflictingProposal(header)
+		if err != nil {
+			n.sim.throw(err)
+			return
+		}
+		for to := range n.sim.nodes {
+			switch {
+			case to == n.index:
+			case to%2 == 0:
+				n.sim.network.send(n.index, to, header)
+			default:
+				n.sim.network.send(n.index, to, conflicting)
+			}
+		}
+	}()
+}
+
+// conflictingProposal creates a validly signed proposal for the same view and parent as the
+// given proposal, but with a different payload.
+func (n *node) conflictingProposal(header *flow.Header) (*flow.Header, error) {
+	conflicting := *header
+	conflicting.PayloadHash = flow.MakeID(header.ID())
+	vote, err := n.signer.CreateVote(model.BlockFromFlow(&conflicting))
+	if err != nil {
+		return nil, fmt.Errorf("could not sign conflicting proposal: %w", err)
+	}
+	conflicting.ProposerSigData = vote.SigData
+	n.sim.blocks.add(&conflicting)
+	n.mu.Lock()
+	n.known[conflicting.ID()] = struct{}{}
+	n.mu.Unlock()
+	return &conflicting, nil
+}
+
+// MakeFinal records the finalization of the block.
+func (n *node) MakeFinal(blockID flow.Identifier) error {
+	header, ok := n.sim.blocks.byID(blockID)
+	if !ok {
+		return fmt.Errorf("finalized block %v is unknown", blockID)
+	}
+	n.finalizedView.Store(header.View)
+	n.finalizedHeight.Store(header.Height)
+	n.sim.stats.onFinalized(n.index, header)
+	return nil
+}
+
+func (n *node) OnStart(currentView uint64) {
+	n.sim.stats.onViewEntered(currentView, false)
+}
+
+func (n *node) OnQcTriggeredViewChange(_ uint64, newView uint64, _ *flow.QuorumCertificate) {
+	n.currentView.Store(newView)
+	n.sim.stats.onViewEntered(newView, false)
+}
+
+func (n *node) OnTcTriggeredViewChange(_ uint64, newView uint64, _ *flow.TimeoutCertificate) {
+	n.currentView.Store(newView)
+	n.sim.stats.onViewEntered(newView, true)
+}
+
+func (n *node) OnInvalidBlockDetected(flow.Slashable[model.InvalidProposalError]) {
+	n.sim.stats.onDetected(ViolationInvalidBlock)
+}
+
+func (n *node) OnDoubleProposeDetected(*model.Block, *model.Block) {
+	n.sim.stats.onDetected(ViolationDoubleProposal)
+}
+
+func (n *node) OnDoubleVotingDetected(*model.Vote, *model.Vote) {
+	n.sim.stats.onDetected(ViolationDoubleVote)
+}
+
+func (n *node) OnInvalidVoteDetected(model.InvalidVoteError) {
+	n.sim.stats.onDetected(ViolationInvalidVote)
+}
+
+func (n *node) OnVoteForInvalidBlockDetected(*model.Vote, *model.SignedProposal) {
+	n.sim.stats.onDetected(ViolationVoteForInvalidBlock)
+}
+
+func (n *node) OnDoubleTimeoutDetected(*model.TimeoutObject, *model.TimeoutObject) {
+	n.sim.stats.onDetected(ViolationDoubleTimeout)
+}
+
+func (n *node) OnInvalidTimeoutDetected(model.InvalidTimeoutError) {
+	n.sim.stats.onDetected(ViolationInvalidTimeout)
+}
+
+// builder builds blocks with an empty payload on top of any block produced in the simulation.
+type builder struct {
+	blocks *blockStore
+}
+
+var _ module.Builder = (*builder)(nil)
+
+func (b *builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) error, sign func(*flow.Header) error) (*flow.Header, error) {
+	parent, ok := b.blocks.byID(parentID)
+	if !ok {
+		return nil, fmt.Errorf("parent block %v is unknown", parentID)
+	}
+	header := &flow.Header{
+		ChainID:   parent.ChainID,
+		ParentID:  parentID,
+		Height:    parent.Height + 1,
+		Timestamp: time.Now().UTC(),
+	}
+	err := setter(header)
+	if err != nil {
+		return nil, err
+	}
+	err = sign(header)
+	if err != nil {
+		return nil, err
+	}
+	b.blocks.add(header)
+	return header, nil
+}
+
+// persister keeps the safety and liveness data of a replica in memory.
+type persister struct {
+	mu       sync.Mutex
+	safety   hotstuff.SafetyData
+	liveness hotstuff.LivenessData
+}
+
+var _ hotstuff.Persister = (*persister)(nil)
+
+func newPersister(root *flow.Header, rootQC *flow.QuorumCertificate) *persister {
+	return &persister{
+		safety: hotstuff.SafetyData{
+			LockedOneChainView:      root.View,
+			HighestAcknowledgedView: root.View,
+		},
+		liveness: hotstuff.LivenessData{
+			CurrentView: rootQC.View + 1,
+			NewestQC:    rootQC,
+		},
+	}
+}
+
+func (p *persister) PutSafetyData(safetyData *hotstuff.SafetyData) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.safety = *safetyData
+	return nil
+}
+
+func (p *persister) PutLivenessData(livenessData *hotstuff.LivenessData) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.liveness = *livenessData
+	return nil
+}
+
+func (p *persister) GetSafetyData() (*hotstuff.SafetyData, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	safety := p.safety
+	return &safety, nil
+}
+
+func (p *persister) GetLivenessData() (*hotstuff.LivenessData, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	liveness := p.liveness
+	return &liveness, nil
+}
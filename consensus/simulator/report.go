@@ -0,0 +1,154 @@
+package simulator
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// protocol violations detected by the replicas
+const (
+	ViolationInvalidBlock        = "invalid_block"
+	ViolationDoubleProposal      = "double_proposal"
+	ViolationDoubleVote          = "double_vote"
+	ViolationInvalidVote         = "invalid_vote"
+	ViolationVoteForInvalidBlock = "vote_for_invalid_block"
+	ViolationDoubleTimeout       = "double_timeout"
+	ViolationInvalidTimeout      = "invalid_timeout"
+)
+
+// Report summarizes a simulation.
+type Report struct {
+	Nodes    int           `json:"nodes"`
+	Duration time.Duration `json:"duration"`
+	// HighestView is the highest view entered by any replica.
+	HighestView uint64 `json:"highest_view"`
+	// FinalizedBlocks is the number of blocks finalized by any replica.
+	FinalizedBlocks uint64 `json:"finalized_blocks"`
+	// FinalizationRate is the number of finalized blocks per second.
+	FinalizationRate float64 `json:"finalization_rate"`
+	// ViewDuration is the distribution of the time between the first replica entering a view
+	// and the first replica entering the next view.
+	ViewDuration       DurationStats `json:"view_duration"`
+	ViewsConcludedByQC uint64        `json:"views_concluded_by_qc"`
+	ViewsConcludedByTC uint64        `json:"views_concluded_by_tc"`
+	// SafetyViolations are conflicting blocks finalized at the same height.
+	SafetyViolations []SafetyViolation `json:"safety_violations"`
+	// DetectedViolations counts the protocol violations detected by the replicas, by kind.
+	DetectedViolations map[string]uint64 `json:"detected_violations"`
+	// InvalidProposals is the number of proposals rejected by the replicas as invalid.
+	InvalidProposals uint64          `json:"invalid_proposals"`
+	Messages         MessageStats    `json:"messages"`
+	Replicas         []ReplicaReport `json:"replicas"`
+}
+
+// Safe returns true if no conflicting blocks were finalized.
+func (r *Report) Safe() bool {
+	return len(r.SafetyViolations) == 0
+}
+
+// SafetyViolation is a block finalized by a replica which conflicts with a different block
+// finalized at the same height by another replica.
+type SafetyViolation struct {
+	Node               int             `json:"node"`
+	Height             uint64          `json:"height"`
+	BlockID            flow.Identifier `json:"block_id"`
+	ConflictingBlockID flow.Identifier `json:"conflicting_block_id"`
+}
+
+// DurationStats summarizes a distribution of durations.
+type DurationStats struct {
+	Count int           `json:"count"`
+	Mean  time.Duration `json:"mean"`
+	P50   time.Duration `json:"p50"`
+	P90   time.Duration `json:"p90"`
+	P99   time.Duration `json:"p99"`
+	Max   time.Duration `json:"max"`
+}
+
+func newDurationStats(durations []time.Duration) DurationStats {
+	if len(durations) == 0 {
+		return DurationStats{}
+	}
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	percentile := func(p int) time.Duration {
+		return sorted[(len(sorted)-1)*p/100]
+	}
+	return DurationStats{
+		Count: len(sorted),
+		Mean:  total / time.Duration(len(sorted)),
+		P50:   percentile(50),
+		P90:   percentile(90),
+		P99:   percentile(99),
+		Max:   sorted[len(sorted)-1],
+	}
+}
+
+// MessageStats counts the messages sent over the simulated network.
+type MessageStats struct {
+	Sent      uint64 `json:"sent"`
+	Delivered uint64 `json:"delivered"`
+	Dropped   uint64 `json:"dropped"`
+}
+
+// ReplicaReport is the state of a replica at the end of the simulation.
+type ReplicaReport struct {
+	Index           int             `json:"index"`
+	NodeID          flow.Identifier `json:"node_id"`
+	Equivocating    bool            `json:"equivocating"`
+	CurrentView     uint64          `json:"current_view"`
+	FinalizedView   uint64          `json:"finalized_view"`
+	FinalizedHeight uint64          `json:"finalized_height"`
+}
+
+// WriteText writes the report in a human-readable format.
+func (r *Report) WriteText(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "simulated %d replicas for %v\n", r.Nodes, r.Duration.Round(time.Millisecond))
+	_, _ = fmt.Fprintf(w, "finalized %d blocks (%.2f blocks/s), highest view %d\n", r.FinalizedBlocks, r.FinalizationRate, r.HighestView)
+	_, _ = fmt.Fprintf(w, "views: %d concluded by QC, %d by TC\n", r.ViewsConcludedByQC, r.ViewsConcludedByTC)
+	d := r.ViewDuration
+	_, _ = fmt.Fprintf(w, "view duration: mean %v, p50 %v, p90 %v, p99 %v, max %v\n",
+		d.Mean.Round(time.Millisecond), d.P50.Round(time.Millisecond), d.P90.Round(time.Millisecond),
+		d.P99.Round(time.Millisecond), d.Max.Round(time.Millisecond))
+	_, _ = fmt.Fprintf(w, "messages: %d sent, %d delivered, %d dropped\n", r.Messages.Sent, r.Messages.Delivered, r.Messages.Dropped)
+
+	if len(r.DetectedViolations) > 0 || r.InvalidProposals > 0 {
+		kinds := make([]string, 0, len(r.DetectedViolations))
+		for kind := range r.DetectedViolations {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		_, _ = fmt.Fprintf(w, "detected protocol violations:")
+		for _, kind := range kinds {
+			_, _ = fmt.Fprintf(w, " %s=%d", kind, r.DetectedViolations[kind])
+		}
+		_, _ = fmt.Fprintf(w, " rejected_proposals=%d\n", r.InvalidProposals)
+	}
+
+	_, _ = fmt.Fprintln(w, "replicas:")
+	for _, replica := range r.Replicas {
+		equivocating := ""
+		if replica.Equivocating {
+			equivocating = " (equivocating)"
+		}
+		_, _ = fmt.Fprintf(w, "  %d %s view %d, finalized view %d height %d%s\n",
+			replica.Index, replica.NodeID.String()[:8], replica.CurrentView, replica.FinalizedView, replica.FinalizedHeight, equivocating)
+	}
+
+	if r.Safe() {
+		_, _ = fmt.Fprintln(w, "no safety violations")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "%d SAFETY VIOLATIONS:\n", len(r.SafetyViolations))
+	for _, v := range r.SafetyViolations {
+		_, _ = fmt.Fprintf(w, "  replica %d finalized %v at height %d, conflicting with %v\n", v.Node, v.BlockID, v.Height, v.ConflictingBlockID)
+	}
+}
@@ -0,0 +1,362 @@
+package simulator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/cruisectl"
+)
+
+// Duration is a time.Duration which is encoded in JSON as a string such as "250ms".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return fmt.Errorf("duration must be a string such as \"250ms\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Scenario describes a simulation: the consensus committee, the configuration of the
+// replicas and the conditions of the network they communicate over. Replicas are referred
+// to by their index in [0, Nodes).
+type Scenario struct {
+	// Nodes is the number of consensus replicas.
+	Nodes int `json:"nodes"`
+	// Duration is the wall-clock time the simulation runs for.
+	Duration Duration `json:"duration"`
+	// Seed seeds the generation of the replicas' keys, the leader selection and the
+	// sampling of message latencies.
+	Seed int64 `json:"seed"`
+
+	// MinTimeout, MaxTimeout, TimeoutAdjustmentFactor and HappyPathMaxRoundFailures
+	// configure the pacemaker of every replica, see timeout.Config.
+	MinTimeout                Duration `json:"min_timeout"`
+	MaxTimeout                Duration `json:"max_timeout"`
+	TimeoutAdjustmentFactor   float64  `json:"timeout_adjustment_factor"`
+	HappyPathMaxRoundFailures uint64   `json:"happy_path_max_round_failures"`
+	// ProposalDuration is the static delay between a leader entering a view and publishing
+	// its proposal. It is only used if CruiseControl is not set.
+	ProposalDuration Duration `json:"proposal_duration"`
+	// CruiseControl, if set, runs the block time controller on every replica, which delays
+	// the publication of proposals to attain the target view time.
+	CruiseControl *CruiseControl `json:"cruise_control,omitempty"`
+
+	// Latency is the latency distribution of all links not matched by any entry of Links.
+	Latency Latency `json:"latency"`
+	// Links overrides the latency distribution of individual links. The last matching
+	// entry applies.
+	Links []Link `json:"links,omitempty"`
+	// Partitions split the network into groups which cannot communicate with each other.
+	Partitions []Partition `json:"partitions,omitempty"`
+	// Crashes isolate replicas from the network for a period of time.
+	Crashes []Crash `json:"crashes,omitempty"`
+	// Equivocators are the indices of byzantine replicas which equivocate: they vote for
+	// conflicting blocks and, as leaders, send conflicting proposals to different replicas.
+	Equivocators []int `json:"equivocators,omitempty"`
+	// Epochs is the schedule of consensus committees. If empty, all replicas form the
+	// committee of a single epoch.
+	Epochs []Epoch `json:"epochs,omitempty"`
+}
+
+// Latency is a distribution of message latencies. Samples are clamped to [Min, Max],
+// where a zero Max means no upper bound.
+type Latency struct {
+	// Distribution is one of "constant" (Mean), "uniform" (between Min and Max),
+	// "normal" (Mean and StdDev) and "exponential" (Min plus an exponentially distributed
+	// delay with the given Mean).
+	Distribution string   `json:"distribution"`
+	Mean         Duration `json:"mean,omitempty"`
+	StdDev       Duration `json:"stddev,omitempty"`
+	Min          Duration `json:"min,omitempty"`
+	Max          Duration `json:"max,omitempty"`
+	// Loss is the probability in [0, 1] that a message is lost.
+	Loss float64 `json:"loss,omitempty"`
+}
+
+const (
+	DistributionConstant    = "constant"
+	DistributionUniform     = "uniform"
+	DistributionNormal      = "normal"
+	DistributionExponential = "exponential"
+)
+
+// Validate returns an error if the distribution is unknown or its parameters are invalid.
+func (l Latency) Validate() error {
+	switch l.Distribution {
+	case DistributionConstant, DistributionNormal, DistributionExponential:
+	case DistributionUniform:
+		if l.Max < l.Min {
+			return fmt.Errorf("uniform latency requires max (%v) >= min (%v)", time.Duration(l.Max), time.Duration(l.Min))
+		}
+	default:
+		return fmt.Errorf("unknown latency distribution %q", l.Distribution)
+	}
+	if l.Mean < 0 || l.StdDev < 0 || l.Min < 0 || l.Max < 0 {
+		return fmt.Errorf("latency parameters must not be negative")
+	}
+	if l.Loss < 0 || l.Loss > 1 {
+		return fmt.Errorf("loss must be within [0, 1], got %f", l.Loss)
+	}
+	return nil
+}
+
+// sample draws a latency from the distribution, and returns false if the message is lost.
+func (l Latency) sample(rng *rand.Rand) (time.Duration, bool) {
+	if l.Loss > 0 && rng.Float64() < l.Loss {
+		return 0, false
+	}
+	var d float64
+	switch l.Distribution {
+	case DistributionConstant:
+		d = float64(l.Mean)
+	case DistributionUniform:
+		d = float64(l.Min) + rng.Float64()*float64(l.Max-l.Min)
+	case DistributionNormal:
+		d = float64(l.Mean) + rng.NormFloat64()*float64(l.StdDev)
+	case DistributionExponential:
+		d = float64(l.Min) + rng.ExpFloat64()*float64(l.Mean)
+	}
+	d = math.Max(d, float64(l.Min))
+	if l.Max > 0 {
+		d = math.Min(d, float64(l.Max))
+	}
+	return time.Duration(d), true
+}
+
+// Link selects the links from any of the From replicas to any of the To replicas. An empty
+// list matches all replicas. Links are directional.
+type Link struct {
+	From    []int   `json:"from,omitempty"`
+	To      []int   `json:"to,omitempty"`
+	Latency Latency `json:"latency"`
+}
+
+// Partition splits the network into groups during [Start, End), measured from the start of
+// the simulation. Replicas in different groups cannot communicate, replicas not listed in any
+// group form an additional group. Messages in flight when the partition starts are dropped.
+type Partition struct {
+	Start  Duration `json:"start"`
+	End    Duration `json:"end"`
+	Groups [][]int  `json:"groups"`
+}
+
+// Crash isolates a replica from the network from At until Recover, measured from the start
+// of the simulation. A zero Recover means that the replica never recovers. While crashed, all
+// messages to and from the replica are dropped; the replica retains its state and catches up
+// with the other replicas after recovering.
+type Crash struct {
+	Node    int      `json:"node"`
+	At      Duration `json:"at"`
+	Recover Duration `json:"recover,omitempty"`
+}
+
+// CruiseControl configures the block time controller of the replicas, see cruisectl.Config.
+// Zero fields other than TargetViewTime take the values of cruisectl.DefaultConfig.
+type CruiseControl struct {
+	// TargetViewTime is the view time the controller targets. The target end time of every
+	// epoch is the time at which its final view ends, when all views from the start of the
+	// simulation take TargetViewTime.
+	TargetViewTime  Duration `json:"target_view_time"`
+	MinViewDuration Duration `json:"min_view_duration,omitempty"`
+	MaxViewDuration Duration `json:"max_view_duration,omitempty"`
+	N_ewma          uint     `json:"n_ewma,omitempty"`
+	N_itg           uint     `json:"n_itg,omitempty"`
+	KP              float64  `json:"kp,omitempty"`
+	KI              float64  `json:"ki,omitempty"`
+	KD              float64  `json:"kd,omitempty"`
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c *CruiseControl) Validate() error {
+	if c.TargetViewTime <= 0 {
+		return fmt.Errorf("target_view_time must be positive")
+	}
+	if c.MinViewDuration < 0 || c.MaxViewDuration < 0 || c.KP < 0 || c.KI < 0 || c.KD < 0 {
+		return fmt.Errorf("cruise control parameters must not be negative")
+	}
+	config := c.Config()
+	if config.MinViewDuration.Load() > config.MaxViewDuration.Load() {
+		return fmt.Errorf("min_view_duration (%v) exceeds max_view_duration (%v)", config.MinViewDuration.Load(), config.MaxViewDuration.Load())
+	}
+	return nil
+}
+
+// Config returns the configuration of the block time controller.
+func (c *CruiseControl) Config() *cruisectl.Config {
+	config := cruisectl.DefaultConfig()
+	if c.MinViewDuration > 0 {
+		config.MinViewDuration.Store(time.Duration(c.MinViewDuration))
+	}
+	if c.MaxViewDuration > 0 {
+		config.MaxViewDuration.Store(time.Duration(c.MaxViewDuration))
+	}
+	if c.N_ewma > 0 {
+		config.N_ewma = c.N_ewma
+	}
+	if c.N_itg > 0 {
+		config.N_itg = c.N_itg
+	}
+	if c.KP > 0 {
+		config.KP = c.KP
+	}
+	if c.KI > 0 {
+		config.KI = c.KI
+	}
+	if c.KD > 0 {
+		config.KD = c.KD
+	}
+	return config
+}
+
+// Epoch is the consensus committee from FirstView until the first view of the next epoch.
+// The first epoch must start at view 0, its committee signs the root QC.
+type Epoch struct {
+	FirstView uint64 `json:"first_view"`
+	Nodes     []int  `json:"nodes"`
+}
+
+// DefaultScenario returns a scenario of 4 honest replicas on a network with normally
+// distributed latencies of 20ms ± 5ms, running for 30 seconds.
+func DefaultScenario() Scenario {
+	return Scenario{
+		Nodes:                     4,
+		Duration:                  Duration(30 * time.Second),
+		Seed:                      1,
+		MinTimeout:                Duration(time.Second),
+		MaxTimeout:                Duration(10 * time.Second),
+		TimeoutAdjustmentFactor:   1.2,
+		HappyPathMaxRoundFailures: 6,
+		ProposalDuration:          Duration(100 * time.Millisecond),
+		Latency: Latency{
+			Distribution: DistributionNormal,
+			Mean:         Duration(20 * time.Millisecond),
+			StdDev:       Duration(5 * time.Millisecond),
+		},
+	}
+}
+
+// LoadScenario reads a scenario from a JSON file. Fields missing from the file take the
+// values of DefaultScenario.
+func LoadScenario(path string) (Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("could not read scenario: %w", err)
+	}
+	scenario := DefaultScenario()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&scenario)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("could not decode scenario: %w", err)
+	}
+	err = scenario.Validate()
+	if err != nil {
+		return Scenario{}, fmt.Errorf("invalid scenario: %w", err)
+	}
+	return scenario, nil
+}
+
+// Validate returns an error if the scenario is inconsistent.
+func (s *Scenario) Validate() error {
+	if s.Nodes < 1 {
+		return fmt.Errorf("at least one node is required")
+	}
+	if s.Duration <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+	if s.MinTimeout <= 0 || s.MaxTimeout < s.MinTimeout {
+		return fmt.Errorf("timeouts must satisfy 0 < min_timeout <= max_timeout")
+	}
+	if s.TimeoutAdjustmentFactor <= 1 {
+		return fmt.Errorf("timeout_adjustment_factor must be larger than 1")
+	}
+	if s.ProposalDuration < 0 {
+		return fmt.Errorf("proposal_duration must not be negative")
+	}
+	if s.CruiseControl != nil {
+		if err := s.CruiseControl.Validate(); err != nil {
+			return fmt.Errorf("invalid cruise control: %w", err)
+		}
+	}
+	err := s.Latency.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid default latency: %w", err)
+	}
+
+	checkNodes := func(what string, nodes []int) error {
+		for _, node := range nodes {
+			if node < 0 || node >= s.Nodes {
+				return fmt.Errorf("%s refers to unknown node %d", what, node)
+			}
+		}
+		return nil
+	}
+	for i, link := range s.Links {
+		if err := checkNodes(fmt.Sprintf("link %d", i), append(append([]int{}, link.From...), link.To...)); err != nil {
+			return err
+		}
+		if err := link.Latency.Validate(); err != nil {
+			return fmt.Errorf("invalid latency of link %d: %w", i, err)
+		}
+	}
+	for i, partition := range s.Partitions {
+		if partition.End <= partition.Start {
+			return fmt.Errorf("partition %d ends before it starts", i)
+		}
+		seen := make(map[int]bool)
+		for _, group := range partition.Groups {
+			if err := checkNodes(fmt.Sprintf("partition %d", i), group); err != nil {
+				return err
+			}
+			for _, node := range group {
+				if seen[node] {
+					return fmt.Errorf("node %d is in several groups of partition %d", node, i)
+				}
+				seen[node] = true
+			}
+		}
+	}
+	for i, crash := range s.Crashes {
+		if err := checkNodes(fmt.Sprintf("crash %d", i), []int{crash.Node}); err != nil {
+			return err
+		}
+		if crash.Recover != 0 && crash.Recover <= crash.At {
+			return fmt.Errorf("crash %d recovers before it happens", i)
+		}
+	}
+	if err := checkNodes("equivocators", s.Equivocators); err != nil {
+		return err
+	}
+	for i, epoch := range s.Epochs {
+		if i == 0 && epoch.FirstView != 0 {
+			return fmt.Errorf("the first epoch must start at view 0")
+		}
+		if i > 0 && epoch.FirstView <= s.Epochs[i-1].FirstView {
+			return fmt.Errorf("epochs must be ordered by strictly increasing first view")
+		}
+		if len(epoch.Nodes) == 0 {
+			return fmt.Errorf("epoch %d has no nodes", i)
+		}
+		if err := checkNodes(fmt.Sprintf("epoch %d", i), epoch.Nodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,359 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onflow/crypto"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	"github.com/onflow/flow-go/consensus/hotstuff/votecollector"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/local"
+	"github.com/onflow/flow-go/module/util"
+)
+
+// chainID is the chain ID of the blocks produced in the simulation.
+const chainID flow.ChainID = "flow-consensus-simulator"
+
+// shutdownTimeout is the time the replicas are given to shut down at the end of a simulation.
+const shutdownTimeout = 10 * time.Second
+
+// Simulator runs a network of consensus replicas according to a scenario. The replicas run
+// the production HotStuff implementation with staking signatures, on an in-memory network
+// which delays, drops and partitions messages as configured by the scenario. All replicas
+// share a single process and the real clock, so the simulation runs in real time.
+type Simulator struct {
+	scenario Scenario
+	log      zerolog.Logger
+	ctx      irrecoverable.SignalerContext
+
+	root    *flow.Header
+	rootQC  *flow.QuorumCertificate
+	blocks  *blockStore
+	network *network
+	nodes   []*node
+	indices map[flow.Identifier]int
+	stats   *statistics
+	// cruise is the epoch timing of the block time controllers, nil without cruise control
+	cruise *cruiseState
+}
+
+// New creates the replicas of the scenario: it generates their identities and staking keys,
+// computes the leader selection of every epoch and signs the root QC.
+// Returns an error if the scenario is invalid.
+func New(log zerolog.Logger, scenario Scenario) (*Simulator, error) {
+	err := scenario.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid scenario: %w", err)
+	}
+
+	s := &Simulator{
+		scenario: scenario,
+		log:      log.With().Str("component", "consensus_simulator").Logger(),
+		blocks:   newBlockStore(),
+		network:  newNetwork(&scenario),
+		indices:  make(map[flow.Identifier]int),
+		stats:    newStatistics(),
+	}
+
+	// generate the identities and signers of all replicas deterministically from the seed
+	rng := rand.New(rand.NewSource(scenario.Seed))
+	identities := make(flow.IdentityList, 0, scenario.Nodes)
+	signers := make([]hotstuff.Signer, 0, scenario.Nodes)
+	for i := 0; i < scenario.Nodes; i++ {
+		var nodeID flow.Identifier
+		_, _ = rng.Read(nodeID[:])
+		seed := make([]byte, crypto.KeyGenSeedMinLen)
+		_, _ = rng.Read(seed)
+		stakingKey, err := crypto.GeneratePrivateKey(crypto.BLSBLS12381, seed)
+		if err != nil {
+			return nil, fmt.Errorf("could not generate staking key: %w", err)
+		}
+		identity := &flow.Identity{
+			IdentitySkeleton: flow.IdentitySkeleton{
+				NodeID:        nodeID,
+				Address:       fmt.Sprintf("consensus-%d", i),
+				Role:          flow.RoleConsensus,
+				InitialWeight: flow.DefaultInitialWeight,
+				StakingPubKey: stakingKey.PublicKey(),
+			},
+			DynamicIdentity: flow.DynamicIdentity{
+				EpochParticipationStatus: flow.EpochParticipationStatusActive,
+			},
+		}
+		me, err := local.New(identity.IdentitySkeleton, stakingKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not create local identity: %w", err)
+		}
+		identities = append(identities, identity)
+		signers = append(signers, verification.NewStakingSigner(me))
+		s.indices[nodeID] = i
+	}
+
+	seeds := make([][]byte, max(len(scenario.Epochs), 1))
+	for i := range seeds {
+		seeds[i] = make([]byte, 32)
+		_, _ = rng.Read(seeds[i])
+	}
+	epochs, err := newEpochs(&scenario, identities, seeds)
+	if err != nil {
+		return nil, err
+	}
+
+	if scenario.CruiseControl != nil {
+		s.cruise = newCruiseState(time.Now(), epochs[len(epochs)-1].finalView, time.Duration(scenario.CruiseControl.TargetViewTime))
+	}
+
+	s.root = &flow.Header{
+		ChainID:   chainID,
+		Height:    0,
+		View:      0,
+		Timestamp: time.Unix(0, 0).UTC(),
+	}
+	s.blocks.add(s.root)
+	s.rootQC, err = s.signRootQC(epochs, signers)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, identity := range identities {
+		committee := &committee{self: identity.NodeID, epochs: epochs, blocks: s.blocks}
+		n, err := newNode(s, i, identity, signers[i], committee)
+		if err != nil {
+			return nil, fmt.Errorf("could not create node %d: %w", i, err)
+		}
+		s.nodes = append(s.nodes, n)
+	}
+	s.network.nodes = s.nodes
+	return s, nil
+}
+
+// signRootQC creates the QC for the root block, signed by the committee of the first epoch.
+func (s *Simulator) signRootQC(epochs []*epoch, signers []hotstuff.Signer) (*flow.QuorumCertificate, error) {
+	rootBlock := model.GenesisBlockFromFlow(s.root)
+	first := epochs[0].identities
+	var rootQC *flow.QuorumCertificate
+	processor, err := votecollector.NewBootstrapStakingVoteProcessor(
+		s.log,
+		&committee{self: first[0].NodeID, epochs: epochs, blocks: s.blocks},
+		rootBlock,
+		func(qc *flow.QuorumCertificate) { rootQC = qc },
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create root vote processor: %w", err)
+	}
+	for _, identity := range first {
+		vote, err := signers[s.indices[identity.NodeID]].CreateVote(rootBlock)
+		if err != nil {
+			return nil, fmt.Errorf("could not sign root block: %w", err)
+		}
+		err = processor.Process(vote)
+		if err != nil {
+			return nil, fmt.Errorf("could not process root vote: %w", err)
+		}
+	}
+	if rootQC == nil {
+		return nil, fmt.Errorf("root QC was not constructed")
+	}
+	return rootQC, nil
+}
+
+// Run runs the simulation for the duration of the scenario, or until the context is
+// cancelled, and returns the report of the simulation. A simulator can only be run once.
+// Returns an error if any replica encountered an unexpected error.
+func (s *Simulator) Run(ctx context.Context) (*Report, error) {
+	signalerCtx, cancel, errCh := irrecoverable.WithSignallerAndCancel(ctx)
+	defer cancel()
+	s.ctx = signalerCtx
+
+	var components []module.ReadyDoneAware
+	for _, n := range s.nodes {
+		components = append(components, n.components()...)
+	}
+
+	s.network.run(signalerCtx)
+	for _, c := range components {
+		c.(module.Startable).Start(signalerCtx)
+	}
+
+	timer := time.NewTimer(time.Duration(s.scenario.Duration))
+	defer timer.Stop()
+	var runErr error
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	case err := <-errCh:
+		runErr = err
+	}
+	elapsed := time.Since(s.network.start)
+	cancel()
+
+	select {
+	case <-util.AllDone(components...):
+	case <-time.After(shutdownTimeout):
+		if runErr == nil {
+			runErr = fmt.Errorf("replicas did not shut down within %v", shutdownTimeout)
+		}
+	}
+	if runErr != nil {
+		return nil, fmt.Errorf("simulation failed: %w", runErr)
+	}
+	return s.report(elapsed), nil
+}
+
+// throw aborts the simulation with an unexpected error.
+func (s *Simulator) throw(err error) {
+	s.ctx.Throw(err)
+}
+
+// indexOf returns the index of the replica with the given node ID.
+func (s *Simulator) indexOf(nodeID flow.Identifier) (int, bool) {
+	index, ok := s.indices[nodeID]
+	return index, ok
+}
+
+// report summarizes the simulation.
+func (s *Simulator) report(elapsed time.Duration) *Report {
+	report := s.stats.report(elapsed)
+	report.Nodes = len(s.nodes)
+	report.Messages = MessageStats{
+		Sent:      s.network.sent.Load(),
+		Delivered: s.network.delivered.Load(),
+		Dropped:   s.network.dropped.Load(),
+	}
+	for _, n := range s.nodes {
+		report.Replicas = append(report.Replicas, ReplicaReport{
+			Index:           n.index,
+			NodeID:          n.identity.NodeID,
+			Equivocating:    n.equivocating,
+			CurrentView:     n.currentView.Load(),
+			FinalizedView:   n.finalizedView.Load(),
+			FinalizedHeight: n.finalizedHeight.Load(),
+		})
+	}
+	return report
+}
+
+// viewEntry is the time a view was first entered by any replica.
+type viewEntry struct {
+	time time.Time
+	byTC bool
+}
+
+// statistics collects the events of all replicas during the simulation.
+type statistics struct {
+	mu          sync.Mutex
+	views       map[uint64]viewEntry
+	finalized   map[uint64]flow.Identifier // finalized block by height
+	violations  []SafetyViolation
+	detected    map[string]uint64
+	invalidSeen uint64
+}
+
+func newStatistics() *statistics {
+	return &statistics{
+		views:     make(map[uint64]viewEntry),
+		finalized: make(map[uint64]flow.Identifier),
+		detected:  make(map[string]uint64),
+	}
+}
+
+// onViewEntered records the time a replica entered a view, retaining the earliest entry.
+func (s *statistics) onViewEntered(view uint64, byTC bool) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.views[view]; ok && !now.Before(entry.time) {
+		return
+	}
+	s.views[view] = viewEntry{time: now, byTC: byTC}
+}
+
+// onFinalized records the finalization of a block by a replica, and a safety violation if a
+// different block was finalized at the same height by any replica.
+func (s *statistics) onFinalized(node int, header *flow.Header) {
+	blockID := header.ID()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	finalized, ok := s.finalized[header.Height]
+	if !ok {
+		s.finalized[header.Height] = blockID
+		return
+	}
+	if finalized != blockID {
+		s.violations = append(s.violations, SafetyViolation{
+			Node:               node,
+			Height:             header.Height,
+			BlockID:            blockID,
+			ConflictingBlockID: finalized,
+		})
+	}
+}
+
+func (s *statistics) onDetected(violation string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.detected[violation]++
+}
+
+func (s *statistics) onInvalidProposal() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.invalidSeen++
+}
+
+func (s *statistics) report(elapsed time.Duration) *Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := &Report{
+		Duration:           elapsed,
+		SafetyViolations:   append([]SafetyViolation{}, s.violations...),
+		DetectedViolations: make(map[string]uint64, len(s.detected)),
+		InvalidProposals:   s.invalidSeen,
+	}
+	for violation, count := range s.detected {
+		report.DetectedViolations[violation] = count
+	}
+
+	// finalized blocks form a chain starting at the root, so the number of finalized blocks
+	// is the number of heights finalized by any replica
+	report.FinalizedBlocks = uint64(len(s.finalized))
+	if elapsed > 0 {
+		report.FinalizationRate = float64(report.FinalizedBlocks) / elapsed.Seconds()
+	}
+
+	views := make([]uint64, 0, len(s.views))
+	for view := range s.views {
+		views = append(views, view)
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i] < views[j] })
+	if len(views) > 0 {
+		report.HighestView = views[len(views)-1]
+	}
+	var durations []time.Duration
+	for _, view := range views {
+		next, ok := s.views[view+1]
+		if !ok {
+			continue
+		}
+		durations = append(durations, next.time.Sub(s.views[view].time))
+		if next.byTC {
+			report.ViewsConcludedByTC++
+		} else {
+			report.ViewsConcludedByQC++
+		}
+	}
+	report.ViewDuration = newDurationStats(durations)
+	return report
+}
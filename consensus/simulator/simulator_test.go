@@ -0,0 +1,133 @@
+package simulator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/cruisectl"
+)
+
+// fastScenario returns a scenario of replicas on a fast network with short timeouts.
+func fastScenario(nodes int, duration time.Duration) Scenario {
+	scenario := DefaultScenario()
+	scenario.Nodes = nodes
+	scenario.Duration = Duration(duration)
+	scenario.MinTimeout = Duration(300 * time.Millisecond)
+	scenario.MaxTimeout = Duration(2 * time.Second)
+	scenario.ProposalDuration = Duration(20 * time.Millisecond)
+	scenario.Latency = Latency{Distribution: DistributionUniform, Min: Duration(time.Millisecond), Max: Duration(5 * time.Millisecond)}
+	return scenario
+}
+
+// TestHappyPath verifies that honest replicas on a fast network finalize blocks.
+func TestHappyPath(t *testing.T) {
+	sim, err := New(zerolog.Nop(), fastScenario(4, 2*time.Second))
+	require.NoError(t, err)
+	report, err := sim.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, report.Safe())
+	assert.Greater(t, report.FinalizedBlocks, uint64(5))
+	assert.Greater(t, report.ViewsConcludedByQC, uint64(5))
+	assert.Greater(t, report.ViewDuration.Count, 5)
+	assert.Empty(t, report.DetectedViolations)
+	for _, replica := range report.Replicas {
+		assert.Greater(t, replica.FinalizedHeight, uint64(0))
+	}
+}
+
+// TestFaults verifies that the replicas remain safe in the presence of a crashed replica, an
+// equivocating replica, a partition and an epoch switch, and that the equivocation is detected.
+// As the simulation runs in real time, the progress of the replicas depends on the speed of the
+// machine, and is not asserted.
+func TestFaults(t *testing.T) {
+	scenario := fastScenario(7, 4*time.Second)
+	scenario.Crashes = []Crash{{Node: 6, At: Duration(500 * time.Millisecond), Recover: Duration(2 * time.Second)}}
+	scenario.Equivocators = []int{5}
+	scenario.Partitions = []Partition{{Start: Duration(time.Second), End: Duration(1500 * time.Millisecond), Groups: [][]int{{0, 1}}}}
+	scenario.Epochs = []Epoch{
+		{FirstView: 0, Nodes: []int{0, 1, 2, 3, 4, 5, 6}},
+		{FirstView: 10, Nodes: []int{0, 1, 2, 3, 5, 6}},
+	}
+
+	sim, err := New(zerolog.Nop(), scenario)
+	require.NoError(t, err)
+	report, err := sim.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, report.Safe(), "safety violations: %v", report.SafetyViolations)
+	assert.Greater(t, report.DetectedViolations[ViolationDoubleVote], uint64(0))
+	assert.Greater(t, report.Messages.Dropped, uint64(0))
+	assert.True(t, report.Replicas[5].Equivocating)
+}
+
+// TestCruiseControl verifies that replicas running the block time controller finalize blocks.
+func TestCruiseControl(t *testing.T) {
+	scenario := fastScenario(4, 2*time.Second)
+	scenario.CruiseControl = &CruiseControl{
+		TargetViewTime:  Duration(100 * time.Millisecond),
+		MinViewDuration: Duration(20 * time.Millisecond),
+		MaxViewDuration: Duration(200 * time.Millisecond),
+	}
+	sim, err := New(zerolog.Nop(), scenario)
+	require.NoError(t, err)
+	report, err := sim.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, report.Safe())
+	assert.Greater(t, report.FinalizedBlocks, uint64(0))
+	assert.Empty(t, report.DetectedViolations)
+}
+
+// TestLoadScenario verifies that scenarios are decoded on top of the defaults and validated.
+func TestLoadScenario(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scenario.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"nodes": 5,
+		"duration": "1m",
+		"latency": {"distribution": "exponential", "min": "10ms", "mean": "15ms", "loss": 0.01},
+		"links": [{"from": [0], "latency": {"distribution": "constant", "mean": "200ms"}}],
+		"crashes": [{"node": 4, "at": "10s"}],
+		"cruise_control": {"target_view_time": "1s", "kp": 1.5}
+	}`), 0644))
+
+	scenario, err := LoadScenario(path)
+	require.NoError(t, err)
+	assert.Equal(t, 5, scenario.Nodes)
+	assert.Equal(t, Duration(time.Minute), scenario.Duration)
+	assert.Equal(t, DefaultScenario().MinTimeout, scenario.MinTimeout)
+	assert.Equal(t, DistributionExponential, scenario.Latency.Distribution)
+	cruiseCtlConfig := scenario.CruiseControl.Config()
+	assert.Equal(t, 1.5, cruiseCtlConfig.KP)
+	assert.Equal(t, cruisectl.DefaultConfig().KI, cruiseCtlConfig.KI)
+	assert.Equal(t, cruisectl.DefaultConfig().MaxViewDuration.Load(), cruiseCtlConfig.MaxViewDuration.Load())
+
+	network := newNetwork(&scenario)
+	assert.Equal(t, DistributionConstant, network.latency[0][3].Distribution)
+	assert.Equal(t, DistributionExponential, network.latency[3][0].Distribution)
+	assert.False(t, network.crashed(4, 9*time.Second))
+	assert.True(t, network.crashed(4, time.Hour))
+
+	invalid := map[string]string{
+		"unknown field":        `{"nodes": 4, "unknown": 1}`,
+		"unknown node":         `{"nodes": 4, "crashes": [{"node": 4, "at": "1s"}]}`,
+		"unknown distribution": `{"latency": {"distribution": "pareto"}}`,
+		"first epoch":          `{"epochs": [{"first_view": 5, "nodes": [0]}]}`,
+		"epoch order":          `{"epochs": [{"first_view": 0, "nodes": [0]}, {"first_view": 0, "nodes": [1]}]}`,
+		"partition":            `{"partitions": [{"start": "2s", "end": "1s", "groups": [[0]]}]}`,
+		"cruise control":       `{"cruise_control": {"min_view_duration": "1s"}}`,
+	}
+	for name, content := range invalid {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		_, err := LoadScenario(path)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"github.com/onflow/flow-go/engine/access/rest/util"
+	accessmodel "github.com/onflow/flow-go/model/access"
+)
+
+func (t *EpochForecast) Build(forecast *accessmodel.EpochForecast) {
+	t.EpochCounter = util.FromUint(forecast.EpochCounter)
+	t.EpochPhase = forecast.EpochPhase.String()
+	t.EpochFallbackTriggered = forecast.EpochFallbackTriggered
+	t.CurrentView = util.FromUint(forecast.CurrentView)
+	t.FinalView = util.FromUint(forecast.FinalView)
+	t.TargetEndTime = forecast.TargetEndTime
+	t.ViewDurationMs = util.FromUint(uint64(forecast.ViewDuration.Milliseconds()))
+
+	t.Extensions = make([]EpochExtension, len(forecast.Extensions))
+	for i, extension := range forecast.Extensions {
+		t.Extensions[i] = EpochExtension{
+			FirstView: util.FromUint(extension.FirstView),
+			FinalView: util.FromUint(extension.FinalView),
+		}
+	}
+
+	t.Transitions = make([]EpochTransitionForecast, len(forecast.Transitions))
+	for i, transition := range forecast.Transitions {
+		t.Transitions[i] = EpochTransitionForecast{
+			Transition: string(transition.Transition),
+			View:       util.FromUint(transition.View),
+			Time:       transition.Time,
+			Estimated:  transition.Estimated,
+		}
+	}
+}
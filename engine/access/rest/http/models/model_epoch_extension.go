@@ -0,0 +1,15 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+// An extension of the current epoch in epoch fallback mode.
+type EpochExtension struct {
+	FirstView string `json:"first_view"`
+	FinalView string `json:"final_view"`
+}
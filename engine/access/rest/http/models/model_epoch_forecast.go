@@ -0,0 +1,25 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+import (
+	"time"
+)
+
+type EpochForecast struct {
+	EpochCounter           string                    `json:"epoch_counter"`
+	EpochPhase             string                    `json:"epoch_phase"`
+	EpochFallbackTriggered bool                      `json:"epoch_fallback_triggered"`
+	CurrentView            string                    `json:"current_view"`
+	FinalView              string                    `json:"final_view"`
+	TargetEndTime          time.Time                 `json:"target_end_time"`
+	Extensions             []EpochExtension          `json:"extensions"`
+	ViewDurationMs         string                    `json:"view_duration_ms"`
+	Transitions            []EpochTransitionForecast `json:"transitions"`
+}
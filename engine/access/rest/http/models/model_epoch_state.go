@@ -0,0 +1,19 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+// The state of a single epoch, as defined by its setup and commit service events.
+type EpochState struct {
+	Counter    string           `json:"counter"`
+	FirstView  string           `json:"first_view"`
+	FinalView  string           `json:"final_view"`
+	SetupId    string           `json:"setup_id"`
+	CommitId   string           `json:"commit_id"`
+	Extensions []EpochExtension `json:"extensions"`
+}
@@ -0,0 +1,18 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+type EpochStateEntry struct {
+	EpochFallbackTriggered    bool        `json:"epoch_fallback_triggered"`
+	PreviousEpoch             *EpochState `json:"previous_epoch,omitempty"`
+	CurrentEpoch              *EpochState `json:"current_epoch"`
+	NextEpoch                 *EpochState `json:"next_epoch,omitempty"`
+	CurrentEpochIdentityTable []Identity  `json:"current_epoch_identity_table"`
+	NextEpochIdentityTable    []Identity  `json:"next_epoch_identity_table"`
+}
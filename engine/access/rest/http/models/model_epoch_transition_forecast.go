@@ -0,0 +1,21 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+import (
+	"time"
+)
+
+// The projected time of an upcoming epoch transition.
+type EpochTransitionForecast struct {
+	Transition string    `json:"transition"`
+	View       string    `json:"view"`
+	Time       time.Time `json:"time"`
+	Estimated  bool      `json:"estimated"`
+}
@@ -0,0 +1,19 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+type Identity struct {
+	NodeId              string `json:"node_id"`
+	Role                string `json:"role"`
+	Address             string `json:"address"`
+	InitialWeight       string `json:"initial_weight"`
+	ParticipationStatus string `json:"participation_status"`
+	StakingKey          string `json:"staking_key"`
+	NetworkingKey       string `json:"networking_key"`
+}
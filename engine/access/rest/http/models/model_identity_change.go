@@ -0,0 +1,17 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+// A change of a node's entry in the identity table of the current epoch.
+type IdentityChange struct {
+	NodeId string    `json:"node_id"`
+	Type   string    `json:"type"`
+	Before *Identity `json:"before,omitempty"`
+	After  *Identity `json:"after,omitempty"`
+}
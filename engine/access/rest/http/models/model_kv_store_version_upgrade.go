@@ -0,0 +1,18 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+// An upgrade of the protocol state key-value store, which took effect at the given block.
+type KvStoreVersionUpgrade struct {
+	Height      string `json:"height"`
+	BlockId     string `json:"block_id"`
+	View        string `json:"view"`
+	FromVersion string `json:"from_version"`
+	ToVersion   string `json:"to_version"`
+}
@@ -0,0 +1,15 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+// An upgrade of the protocol state key-value store to a new version, scheduled to take effect at the activation view.
+type KvStoreVersionUpgradeSchedule struct {
+	NewVersion     string `json:"new_version"`
+	ActivationView string `json:"activation_view"`
+}
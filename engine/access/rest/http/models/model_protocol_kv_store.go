@@ -0,0 +1,21 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+// The contents of the protocol state key-value store as of a block.
+type ProtocolKvStore struct {
+	Id                          string                         `json:"id"`
+	Version                     string                         `json:"version"`
+	VersionUpgrade              *KvStoreVersionUpgradeSchedule `json:"version_upgrade,omitempty"`
+	EpochStateId                string                         `json:"epoch_state_id"`
+	EpochExtensionViewCount     string                         `json:"epoch_extension_view_count"`
+	FinalizationSafetyThreshold string                         `json:"finalization_safety_threshold"`
+	// The complete key-value store in the encoding of its version, base64 encoded.
+	Data string `json:"data"`
+}
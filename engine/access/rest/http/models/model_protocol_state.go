@@ -0,0 +1,18 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+type ProtocolState struct {
+	Height     string           `json:"height"`
+	BlockId    string           `json:"block_id"`
+	View       string           `json:"view"`
+	KvStore    *ProtocolKvStore `json:"kv_store"`
+	EpochPhase string           `json:"epoch_phase"`
+	EpochState *EpochStateEntry `json:"epoch_state"`
+}
@@ -0,0 +1,23 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+type ProtocolStateDiff struct {
+	StartHeight                 string                  `json:"start_height"`
+	EndHeight                   string                  `json:"end_height"`
+	StartEpochCounter           string                  `json:"start_epoch_counter"`
+	EndEpochCounter             string                  `json:"end_epoch_counter"`
+	StartEpochPhase             string                  `json:"start_epoch_phase"`
+	EndEpochPhase               string                  `json:"end_epoch_phase"`
+	StartEpochFallbackTriggered bool                    `json:"start_epoch_fallback_triggered"`
+	EndEpochFallbackTriggered   bool                    `json:"end_epoch_fallback_triggered"`
+	IdentityChanges             []IdentityChange        `json:"identity_changes"`
+	Ejections                   []string                `json:"ejections"`
+	VersionUpgrades             []KvStoreVersionUpgrade `json:"version_upgrades"`
+}
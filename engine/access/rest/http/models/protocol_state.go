@@ -0,0 +1,133 @@
+package models
+
+import (
+	"github.com/onflow/flow-go/engine/access/rest/util"
+	accessmodel "github.com/onflow/flow-go/model/access"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func (t *ProtocolState) Build(protocolState *accessmodel.ProtocolStateAtHeight) {
+	t.Height = util.FromUint(protocolState.Height)
+	t.BlockId = protocolState.BlockID.String()
+	t.View = util.FromUint(protocolState.View)
+	t.EpochPhase = protocolState.EpochPhase.String()
+
+	var kvStore ProtocolKvStore
+	kvStore.Build(&protocolState.KVStore)
+	t.KvStore = &kvStore
+
+	var epochState EpochStateEntry
+	epochState.Build(protocolState.EpochState)
+	t.EpochState = &epochState
+}
+
+func (t *ProtocolKvStore) Build(kvStore *accessmodel.ProtocolKVStore) {
+	t.Id = kvStore.ID.String()
+	t.Version = util.FromUint(kvStore.Version)
+	t.EpochStateId = kvStore.EpochStateID.String()
+	t.EpochExtensionViewCount = util.FromUint(kvStore.EpochExtensionViewCount)
+	t.FinalizationSafetyThreshold = util.FromUint(kvStore.FinalizationSafetyThreshold)
+	t.Data = util.ToBase64(kvStore.Data)
+	if kvStore.VersionUpgrade != nil {
+		t.VersionUpgrade = &KvStoreVersionUpgradeSchedule{
+			NewVersion:     util.FromUint(kvStore.VersionUpgrade.NewVersion),
+			ActivationView: util.FromUint(kvStore.VersionUpgrade.ActivationView),
+		}
+	}
+}
+
+func (t *EpochStateEntry) Build(entry *flow.RichEpochStateEntry) {
+	t.EpochFallbackTriggered = entry.EpochFallbackTriggered
+	t.CurrentEpoch = buildEpochState(&entry.CurrentEpoch, entry.CurrentEpochSetup)
+	if entry.PreviousEpoch != nil && entry.PreviousEpochSetup != nil {
+		t.PreviousEpoch = buildEpochState(entry.PreviousEpoch, entry.PreviousEpochSetup)
+	}
+	if entry.NextEpoch != nil && entry.NextEpochSetup != nil {
+		t.NextEpoch = buildEpochState(entry.NextEpoch, entry.NextEpochSetup)
+	}
+	t.CurrentEpochIdentityTable = buildIdentities(entry.CurrentEpochIdentityTable)
+	t.NextEpochIdentityTable = buildIdentities(entry.NextEpochIdentityTable)
+}
+
+func buildEpochState(container *flow.EpochStateContainer, setup *flow.EpochSetup) *EpochState {
+	epochState := &EpochState{
+		Counter:    util.FromUint(setup.Counter),
+		FirstView:  util.FromUint(setup.FirstView),
+		FinalView:  util.FromUint(setup.FinalView),
+		SetupId:    container.SetupID.String(),
+		CommitId:   container.CommitID.String(),
+		Extensions: make([]EpochExtension, len(container.EpochExtensions)),
+	}
+	for i, extension := range container.EpochExtensions {
+		epochState.Extensions[i] = EpochExtension{
+			FirstView: util.FromUint(extension.FirstView),
+			FinalView: util.FromUint(extension.FinalView),
+		}
+	}
+	return epochState
+}
+
+func (t *Identity) Build(identity *flow.Identity) {
+	t.NodeId = identity.NodeID.String()
+	t.Role = identity.Role.String()
+	t.Address = identity.Address
+	t.InitialWeight = util.FromUint(identity.InitialWeight)
+	t.ParticipationStatus = identity.EpochParticipationStatus.String()
+	if identity.StakingPubKey != nil {
+		t.StakingKey = identity.StakingPubKey.String()
+	}
+	if identity.NetworkPubKey != nil {
+		t.NetworkingKey = identity.NetworkPubKey.String()
+	}
+}
+
+func buildIdentities(identities flow.IdentityList) []Identity {
+	result := make([]Identity, len(identities))
+	for i, identity := range identities {
+		result[i].Build(identity)
+	}
+	return result
+}
+
+func (t *ProtocolStateDiff) Build(diff *accessmodel.ProtocolStateDiff) {
+	t.StartHeight = util.FromUint(diff.StartHeight)
+	t.EndHeight = util.FromUint(diff.EndHeight)
+	t.StartEpochCounter = util.FromUint(diff.StartEpochCounter)
+	t.EndEpochCounter = util.FromUint(diff.EndEpochCounter)
+	t.StartEpochPhase = diff.StartEpochPhase.String()
+	t.EndEpochPhase = diff.EndEpochPhase.String()
+	t.StartEpochFallbackTriggered = diff.StartEpochFallbackTriggered
+	t.EndEpochFallbackTriggered = diff.EndEpochFallbackTriggered
+
+	t.IdentityChanges = make([]IdentityChange, len(diff.IdentityChanges))
+	for i, change := range diff.IdentityChanges {
+		t.IdentityChanges[i] = IdentityChange{
+			NodeId: change.NodeID.String(),
+			Type:   string(change.Type),
+		}
+		if change.Before != nil {
+			t.IdentityChanges[i].Before = new(Identity)
+			t.IdentityChanges[i].Before.Build(change.Before)
+		}
+		if change.After != nil {
+			t.IdentityChanges[i].After = new(Identity)
+			t.IdentityChanges[i].After.Build(change.After)
+		}
+	}
+
+	t.Ejections = make([]string, len(diff.Ejections))
+	for i, nodeID := range diff.Ejections {
+		t.Ejections[i] = nodeID.String()
+	}
+
+	t.VersionUpgrades = make([]KvStoreVersionUpgrade, len(diff.VersionUpgrades))
+	for i, upgrade := range diff.VersionUpgrades {
+		t.VersionUpgrades[i] = KvStoreVersionUpgrade{
+			Height:      util.FromUint(upgrade.Height),
+			BlockId:     upgrade.BlockID.String(),
+			View:        util.FromUint(upgrade.View),
+			FromVersion: util.FromUint(upgrade.FromVersion),
+			ToVersion:   util.FromUint(upgrade.ToVersion),
+		}
+	}
+}
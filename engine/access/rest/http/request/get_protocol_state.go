@@ -0,0 +1,91 @@
+package request
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/engine/access/rest/common"
+)
+
+type GetProtocolState struct {
+	Height uint64
+}
+
+// GetProtocolStateRequest extracts necessary query parameters from the provided request,
+// builds a GetProtocolState instance, and validates it.
+//
+// No errors are expected during normal operation.
+func GetProtocolStateRequest(r *common.Request) (GetProtocolState, error) {
+	var req GetProtocolState
+	err := req.Build(r)
+	return req, err
+}
+
+func (g *GetProtocolState) Build(r *common.Request) error {
+	return g.Parse(r.GetQueryParam(heightQuery))
+}
+
+func (g *GetProtocolState) Parse(rawHeight string) error {
+	var height Height
+	err := height.Parse(rawHeight)
+	if err != nil {
+		return err
+	}
+	g.Height = height.Flow()
+
+	// default to latest finalized block
+	if g.Height == EmptyHeight {
+		g.Height = FinalHeight
+	}
+	return nil
+}
+
+type GetProtocolStateDiff struct {
+	StartHeight uint64
+	EndHeight   uint64
+}
+
+// GetProtocolStateDiffRequest extracts necessary query parameters from the provided request,
+// builds a GetProtocolStateDiff instance, and validates it.
+//
+// No errors are expected during normal operation.
+func GetProtocolStateDiffRequest(r *common.Request) (GetProtocolStateDiff, error) {
+	var req GetProtocolStateDiff
+	err := req.Build(r)
+	return req, err
+}
+
+func (g *GetProtocolStateDiff) Build(r *common.Request) error {
+	return g.Parse(
+		r.GetQueryParam(startHeightQuery),
+		r.GetQueryParam(endHeightQuery),
+	)
+}
+
+func (g *GetProtocolStateDiff) Parse(rawStart string, rawEnd string) error {
+	var height Height
+	err := height.Parse(rawStart)
+	if err != nil {
+		return err
+	}
+	g.StartHeight = height.Flow()
+	if g.StartHeight == EmptyHeight {
+		return fmt.Errorf("must provide a start height")
+	}
+
+	err = height.Parse(rawEnd)
+	if err != nil {
+		return err
+	}
+	g.EndHeight = height.Flow()
+
+	// default to latest finalized block
+	if g.EndHeight == EmptyHeight {
+		g.EndHeight = FinalHeight
+	}
+
+	// the range can only be checked here if neither height is a special value, which is resolved later
+	if g.StartHeight < FinalHeight && g.EndHeight < FinalHeight && g.StartHeight > g.EndHeight {
+		return fmt.Errorf("start height must be less than or equal to end height")
+	}
+	return nil
+}
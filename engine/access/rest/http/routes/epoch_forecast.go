@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/common"
+	commonmodels "github.com/onflow/flow-go/engine/access/rest/common/models"
+	"github.com/onflow/flow-go/engine/access/rest/http/models"
+)
+
+// GetEpochForecast returns the projected times of the upcoming transitions of the current epoch
+func GetEpochForecast(r *common.Request, backend access.API, _ commonmodels.LinkGenerator) (interface{}, error) {
+	forecast, err := backend.GetEpochForecast(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.EpochForecast
+	response.Build(forecast)
+	return response, nil
+}
@@ -0,0 +1,62 @@
+package routes_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	mocktestify "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/access/mock"
+	"github.com/onflow/flow-go/engine/access/rest/router"
+	accessmodel "github.com/onflow/flow-go/model/access"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func TestGetEpochForecast(t *testing.T) {
+	backend := mock.NewAPI(t)
+
+	t.Run("get epoch forecast in epoch fallback mode", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/network/epoch_forecast", nil)
+		require.NoError(t, err)
+
+		targetEndTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		forecast := &accessmodel.EpochForecast{
+			EpochCounter:           7,
+			EpochPhase:             flow.EpochPhaseFallback,
+			EpochFallbackTriggered: true,
+			CurrentView:            2500,
+			FinalView:              2999,
+			TargetEndTime:          targetEndTime,
+			Extensions:             []flow.EpochExtension{{FirstView: 2000, FinalView: 2999}},
+			ViewDuration:           1250 * time.Millisecond,
+			Transitions: []accessmodel.EpochTransitionForecast{{
+				Transition: accessmodel.EpochTransitionSwitchover,
+				View:       2999,
+				Time:       targetEndTime,
+			}},
+		}
+		backend.Mock.
+			On("GetEpochForecast", mocktestify.Anything).
+			Return(forecast, nil)
+
+		expected := `{
+			"epoch_counter": "7",
+			"epoch_phase": "EpochPhaseFallback",
+			"epoch_fallback_triggered": true,
+			"current_view": "2500",
+			"final_view": "2999",
+			"target_end_time": "2024-05-01T12:00:00Z",
+			"extensions": [{"first_view": "2000", "final_view": "2999"}],
+			"view_duration_ms": "1250",
+			"transitions": [{
+				"transition": "epoch_switchover",
+				"view": "2999",
+				"time": "2024-05-01T12:00:00Z",
+				"estimated": false
+			}]
+		}`
+		router.AssertOKResponse(t, req, expected, backend)
+	})
+}
@@ -0,0 +1,77 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/common"
+	commonmodels "github.com/onflow/flow-go/engine/access/rest/common/models"
+	"github.com/onflow/flow-go/engine/access/rest/http/models"
+	"github.com/onflow/flow-go/engine/access/rest/http/request"
+)
+
+// GetProtocolState returns the protocol state key-value store and epoch state as of a finalized height
+func GetProtocolState(r *common.Request, backend access.API, _ commonmodels.LinkGenerator) (interface{}, error) {
+	req, err := request.GetProtocolStateRequest(r)
+	if err != nil {
+		return nil, common.NewBadRequestError(err)
+	}
+
+	height, err := resolveHeight(r.Context(), backend, req.Height)
+	if err != nil {
+		return nil, err
+	}
+	protocolState, err := backend.GetProtocolStateAtHeight(r.Context(), height)
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.ProtocolState
+	response.Build(protocolState)
+	return response, nil
+}
+
+// GetProtocolStateDiff returns the identity changes, ejections and key-value store version upgrades
+// between two finalized heights
+func GetProtocolStateDiff(r *common.Request, backend access.API, _ commonmodels.LinkGenerator) (interface{}, error) {
+	req, err := request.GetProtocolStateDiffRequest(r)
+	if err != nil {
+		return nil, common.NewBadRequestError(err)
+	}
+
+	startHeight, err := resolveHeight(r.Context(), backend, req.StartHeight)
+	if err != nil {
+		return nil, err
+	}
+	endHeight, err := resolveHeight(r.Context(), backend, req.EndHeight)
+	if err != nil {
+		return nil, err
+	}
+	if startHeight > endHeight {
+		return nil, common.NewBadRequestError(fmt.Errorf("start height must be less than or equal to end height"))
+	}
+	diff, err := backend.GetProtocolStateDiff(r.Context(), startHeight, endHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.ProtocolStateDiff
+	response.Build(diff)
+	return response, nil
+}
+
+// resolveHeight returns the height of the latest finalized or sealed block for the special
+// height values 'final' and 'sealed', and the given height otherwise.
+func resolveHeight(ctx context.Context, backend access.API, height uint64) (uint64, error) {
+	isSealed := height == request.SealedHeight
+	isFinal := height == request.FinalHeight
+	if !isFinal && !isSealed {
+		return height, nil
+	}
+	header, _, err := backend.GetLatestBlockHeader(ctx, isSealed)
+	if err != nil {
+		return 0, err
+	}
+	return header.Height, nil
+}
@@ -0,0 +1,120 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	mocktestify "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/access/mock"
+	"github.com/onflow/flow-go/engine/access/rest/router"
+	accessmodel "github.com/onflow/flow-go/model/access"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func protocolStateDiffURL(t *testing.T, startHeight string, endHeight string) string {
+	u, err := url.ParseRequestURI("/v1/protocol_state/diff")
+	require.NoError(t, err)
+	q := u.Query()
+	if startHeight != "" {
+		q.Add("start_height", startHeight)
+	}
+	if endHeight != "" {
+		q.Add("end_height", endHeight)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func TestGetProtocolStateDiff(t *testing.T) {
+	backend := mock.NewAPI(t)
+
+	t.Run("get diff up to the latest finalized block", func(t *testing.T) {
+		req, err := http.NewRequest("GET", protocolStateDiffURL(t, "100", ""), nil)
+		require.NoError(t, err)
+
+		header := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(200))
+		backend.Mock.
+			On("GetLatestBlockHeader", mocktestify.Anything, false).
+			Return(header, flow.BlockStatusFinalized, nil).
+			Once()
+
+		removed := unittest.IdentityFixture(unittest.WithRole(flow.RoleConsensus), unittest.WithKeys)
+		ejected := unittest.IdentifierFixture()
+		upgradeBlockID := unittest.IdentifierFixture()
+		diff := &accessmodel.ProtocolStateDiff{
+			StartHeight:       100,
+			EndHeight:         200,
+			StartEpochCounter: 3,
+			EndEpochCounter:   4,
+			StartEpochPhase:   flow.EpochPhaseCommitted,
+			EndEpochPhase:     flow.EpochPhaseStaking,
+			IdentityChanges: []accessmodel.IdentityChange{{
+				NodeID: removed.NodeID,
+				Type:   accessmodel.IdentityRemoved,
+				Before: removed,
+			}},
+			Ejections: []flow.Identifier{ejected},
+			VersionUpgrades: []accessmodel.KVStoreVersionUpgrade{{
+				Height:      150,
+				BlockID:     upgradeBlockID,
+				View:        160,
+				FromVersion: 1,
+				ToVersion:   2,
+			}},
+		}
+		backend.Mock.
+			On("GetProtocolStateDiff", mocktestify.Anything, uint64(100), uint64(200)).
+			Return(diff, nil).
+			Once()
+
+		expected := fmt.Sprintf(`{
+			"start_height": "100",
+			"end_height": "200",
+			"start_epoch_counter": "3",
+			"end_epoch_counter": "4",
+			"start_epoch_phase": "EpochPhaseCommitted",
+			"end_epoch_phase": "EpochPhaseStaking",
+			"start_epoch_fallback_triggered": false,
+			"end_epoch_fallback_triggered": false,
+			"identity_changes": [{
+				"node_id": "%s",
+				"type": "removed",
+				"before": {
+					"node_id": "%s",
+					"role": "consensus",
+					"address": "%s",
+					"initial_weight": "%d",
+					"participation_status": "%s",
+					"staking_key": "%s",
+					"networking_key": "%s"
+				}
+			}],
+			"ejections": ["%s"],
+			"version_upgrades": [{
+				"height": "150",
+				"block_id": "%s",
+				"view": "160",
+				"from_version": "1",
+				"to_version": "2"
+			}]
+		}`,
+			removed.NodeID, removed.NodeID, removed.Address, removed.InitialWeight, removed.EpochParticipationStatus,
+			removed.StakingPubKey, removed.NetworkPubKey, ejected, upgradeBlockID)
+		router.AssertOKResponse(t, req, expected, backend)
+	})
+
+	t.Run("get diff with invalid heights", func(t *testing.T) {
+		for _, heights := range [][2]string{{"", "10"}, {"20", "10"}, {"foo", "10"}} {
+			req, err := http.NewRequest("GET", protocolStateDiffURL(t, heights[0], heights[1]), nil)
+			require.NoError(t, err)
+
+			rr := router.ExecuteRequest(req, backend)
+			require.Equal(t, http.StatusBadRequest, rr.Code, heights)
+		}
+	})
+}
@@ -94,6 +94,21 @@ var Routes = []route{{
 	Pattern: "/network/parameters",
 	Name:    "getNetworkParameters",
 	Handler: routes.GetNetworkParameters,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/network/epoch_forecast",
+	Name:    "getEpochForecast",
+	Handler: routes.GetEpochForecast,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/protocol_state",
+	Name:    "getProtocolState",
+	Handler: routes.GetProtocolState,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/protocol_state/diff",
+	Name:    "getProtocolStateDiff",
+	Handler: routes.GetProtocolStateDiff,
 }, {
 	Method:  http.MethodGet,
 	Pattern: "/node_version_info",
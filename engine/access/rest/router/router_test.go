@@ -94,6 +94,21 @@ func TestParseURL(t *testing.T) {
 			url:      "/v1/network/parameters",
 			expected: "getNetworkParameters",
 		},
+		{
+			name:     "/v1/network/epoch_forecast",
+			url:      "/v1/network/epoch_forecast",
+			expected: "getEpochForecast",
+		},
+		{
+			name:     "/v1/protocol_state",
+			url:      "/v1/protocol_state",
+			expected: "getProtocolState",
+		},
+		{
+			name:     "/v1/protocol_state/diff",
+			url:      "/v1/protocol_state/diff",
+			expected: "getProtocolStateDiff",
+		},
 		{
 			name:     "/v1/node_version_info",
 			url:      "/v1/node_version_info",
@@ -201,6 +216,21 @@ func TestBenchmarkParseURL(t *testing.T) {
 			url:      "/v1/network/parameters",
 			expected: "getNetworkParameters",
 		},
+		{
+			name:     "/v1/network/epoch_forecast",
+			url:      "/v1/network/epoch_forecast",
+			expected: "getEpochForecast",
+		},
+		{
+			name:     "/v1/protocol_state",
+			url:      "/v1/protocol_state",
+			expected: "getProtocolState",
+		},
+		{
+			name:     "/v1/protocol_state/diff",
+			url:      "/v1/protocol_state/diff",
+			expected: "getProtocolStateDiff",
+		},
 		{
 			name:     "/v1/node_version_info",
 			url:      "/v1/node_version_info",
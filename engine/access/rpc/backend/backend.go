@@ -13,6 +13,7 @@ import (
 
 	"github.com/onflow/flow-go/access/validator"
 	"github.com/onflow/flow-go/cmd/build"
+	"github.com/onflow/flow-go/consensus/hotstuff/cruisectl"
 	"github.com/onflow/flow-go/engine/access/index"
 	"github.com/onflow/flow-go/engine/access/rpc/connection"
 	"github.com/onflow/flow-go/engine/access/subscription"
@@ -114,6 +115,9 @@ type Params struct {
 	IndexReporter              state_synchronization.IndexReporter
 	VersionControl             *version.VersionControl
 	ExecNodeIdentitiesProvider *commonrpc.ExecutionNodeIdentitiesProvider
+	// CruiseCtlTiming are the limits of authority of the consensus nodes' block time controller,
+	// used to forecast epoch transitions. If nil, the controller's default limits are assumed.
+	CruiseCtlTiming *cruisectl.TimingConfig
 }
 
 var _ TransactionErrorMessage = (*Backend)(nil)
@@ -138,6 +142,11 @@ func New(params Params) (*Backend, error) {
 		}
 	}
 
+	cruiseCtlTiming := params.CruiseCtlTiming
+	if cruiseCtlTiming == nil {
+		cruiseCtlTiming = &cruisectl.DefaultConfig().TimingConfig
+	}
+
 	// the system tx is hardcoded and never changes during runtime
 	systemTx, err := blueprints.SystemChunkTransaction(params.ChainID.Chain())
 	if err != nil {
@@ -199,6 +208,7 @@ func New(params Params) (*Backend, error) {
 			chainID:              params.ChainID,
 			headers:              params.Headers,
 			snapshotHistoryLimit: params.SnapshotHistoryLimit,
+			cruiseCtlTiming:      cruiseCtlTiming,
 		},
 		backendSubscribeBlocks: backendSubscribeBlocks{
 			log:                 params.Log,
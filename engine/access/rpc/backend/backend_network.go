@@ -3,6 +3,7 @@ package backend
 import (
 	"context"
 	"errors"
+	"time"
 
 	accessmodel "github.com/onflow/flow-go/model/access"
 	"github.com/onflow/flow-go/state"
@@ -10,9 +11,12 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/onflow/flow-go/consensus/hotstuff/cruisectl"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/epochs"
 	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/history"
 	"github.com/onflow/flow-go/storage"
 )
 
@@ -21,6 +25,7 @@ type backendNetwork struct {
 	chainID              flow.ChainID
 	headers              storage.Headers
 	snapshotHistoryLimit int
+	cruiseCtlTiming      *cruisectl.TimingConfig
 }
 
 /*
@@ -35,12 +40,14 @@ func NewNetworkAPI(
 	chainID flow.ChainID,
 	headers storage.Headers,
 	snapshotHistoryLimit int,
+	cruiseCtlTiming *cruisectl.TimingConfig,
 ) *backendNetwork {
 	return &backendNetwork{
 		state:                state,
 		chainID:              chainID,
 		headers:              headers,
 		snapshotHistoryLimit: snapshotHistoryLimit,
+		cruiseCtlTiming:      cruiseCtlTiming,
 	}
 }
 
@@ -50,6 +57,17 @@ func (b *backendNetwork) GetNetworkParameters(_ context.Context) accessmodel.Net
 	}
 }
 
+// GetEpochForecast returns the projected times of the upcoming transitions of the current epoch,
+// as of the latest finalized block. Access nodes do not run the cruise controller, so the projection
+// assumes the limits of authority configured for the consensus nodes' controller.
+func (b *backendNetwork) GetEpochForecast(_ context.Context) (*accessmodel.EpochForecast, error) {
+	forecast, err := epochs.ForecastEpoch(b.state.Final(), b.cruiseCtlTiming, time.Now())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to forecast epoch transitions: %v", err)
+	}
+	return forecast, nil
+}
+
 // GetLatestProtocolStateSnapshot returns the latest finalized snapshot.
 func (b *backendNetwork) GetLatestProtocolStateSnapshot(_ context.Context) ([]byte, error) {
 	snapshot := b.state.Final()
@@ -128,3 +146,37 @@ func (b *backendNetwork) GetProtocolStateSnapshotByHeight(_ context.Context, blo
 	}
 	return data, nil
 }
+
+// GetProtocolStateAtHeight returns the protocol state key-value store and epoch state as of the
+// finalized block at the given height.
+// Expected errors during normal operation:
+//   - status.Error[codes.NotFound] - No block has been finalized at the given height.
+func (b *backendNetwork) GetProtocolStateAtHeight(_ context.Context, height uint64) (*accessmodel.ProtocolStateAtHeight, error) {
+	protocolState, err := history.StateAtHeight(b.state, height)
+	if err != nil {
+		if errors.Is(err, state.ErrUnknownSnapshotReference) {
+			return nil, status.Errorf(codes.NotFound, "failed to find protocol state: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get protocol state at height %d: %v", height, err)
+	}
+	return protocolState, nil
+}
+
+// GetProtocolStateDiff returns the identity changes, ejections and key-value store version upgrades
+// between the finalized blocks at the given heights.
+// Expected errors during normal operation:
+//   - status.Error[codes.InvalidArgument] - The start height exceeds the end height.
+//   - status.Error[codes.NotFound] - No block has been finalized at either height.
+func (b *backendNetwork) GetProtocolStateDiff(_ context.Context, startHeight uint64, endHeight uint64) (*accessmodel.ProtocolStateDiff, error) {
+	if startHeight > endHeight {
+		return nil, status.Errorf(codes.InvalidArgument, "start height %d exceeds end height %d", startHeight, endHeight)
+	}
+	diff, err := history.Diff(b.state, startHeight, endHeight)
+	if err != nil {
+		if errors.Is(err, state.ErrUnknownSnapshotReference) {
+			return nil, status.Errorf(codes.NotFound, "failed to find protocol state: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to diff protocol state between heights %d and %d: %v", startHeight, endHeight, err)
+	}
+	return diff, nil
+}
@@ -34,6 +34,7 @@ type HotStuffFactory struct {
 	me             module.Local
 	db             *badger.DB
 	protoState     protocol.State
+	evidence       storage.SlashingEvidence
 	engineMetrics  module.EngineMetrics
 	mempoolMetrics module.MempoolMetrics
 	createMetrics  HotStuffMetricsFunc
@@ -45,6 +46,7 @@ func NewHotStuffFactory(
 	me module.Local,
 	db *badger.DB,
 	protoState protocol.State,
+	evidence storage.SlashingEvidence,
 	engineMetrics module.EngineMetrics,
 	mempoolMetrics module.MempoolMetrics,
 	createMetrics HotStuffMetricsFunc,
@@ -56,6 +58,7 @@ func NewHotStuffFactory(
 		me:             me,
 		db:             db,
 		protoState:     protoState,
+		evidence:       evidence,
 		engineMetrics:  engineMetrics,
 		mempoolMetrics: mempoolMetrics,
 		createMetrics:  createMetrics,
@@ -93,6 +96,18 @@ func (f *HotStuffFactory) CreateModules(
 	}
 	committee = committees.NewMetricsWrapper(committee, metrics) // wrapper for measuring time spent determining consensus committee relations
 
+	// persist evidence of slashable offenses within the cluster, which only exists for a single epoch
+	epochCounter := epoch.Counter()
+	evidenceConsumer := notifications.NewSlashingEvidenceConsumer(
+		log,
+		cluster.ChainID(),
+		f.evidence,
+		committee,
+		func(uint64) (uint64, error) { return epochCounter, nil },
+		headers,
+	)
+	notifier.AddProposalViolationConsumer(evidenceConsumer)
+
 	// create a signing provider
 	var signer hotstuff.Signer = verification.NewStakingSigner(f.me)
 	signer = verification.NewMetricsWrapper(signer, metrics) // wrapper for measuring time spent with crypto-related operations
@@ -117,6 +132,7 @@ func (f *HotStuffFactory) CreateModules(
 	voteAggregationDistributor := pubsub.NewVoteAggregationDistributor()
 	voteAggregationDistributor.AddVoteCollectorConsumer(telemetryConsumer)
 	voteAggregationDistributor.AddVoteAggregationViolationConsumer(slashingConsumer)
+	voteAggregationDistributor.AddVoteAggregationViolationConsumer(evidenceConsumer)
 
 	verifier := verification.NewStakingVerifier()
 	validator := validatorImpl.NewMetricsWrapper(validatorImpl.New(committee, verifier), metrics)
@@ -140,6 +156,7 @@ func (f *HotStuffFactory) CreateModules(
 	timeoutCollectorDistributor := pubsub.NewTimeoutAggregationDistributor()
 	timeoutCollectorDistributor.AddTimeoutCollectorConsumer(telemetryConsumer)
 	timeoutCollectorDistributor.AddTimeoutAggregationViolationConsumer(slashingConsumer)
+	timeoutCollectorDistributor.AddTimeoutAggregationViolationConsumer(evidenceConsumer)
 
 	timeoutProcessorFactory := timeoutcollector.NewTimeoutProcessorFactory(log, timeoutCollectorDistributor, committee, validator, msig.CollectorTimeoutTag)
 	timeoutAggregator, err := consensus.NewTimeoutAggregator(
@@ -6,6 +6,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/consensus"
 	"github.com/onflow/flow-go/model/chunks"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/mempool"
@@ -190,3 +191,19 @@ func (c *ApprovalCollector) CollectMissingVerifiers() map[uint64]flow.Identifier
 
 	return targetIDs
 }
+
+// ChunkStatuses returns the assigned verifiers and the received and missing approvals for every chunk.
+func (c *ApprovalCollector) ChunkStatuses() []consensus.ChunkSealingStatus {
+	statuses := make([]consensus.ChunkSealingStatus, 0, len(c.chunkCollectors))
+	for chunkIndex, collector := range c.chunkCollectors {
+		assigned, approved := collector.Status()
+		statuses = append(statuses, consensus.ChunkSealingStatus{
+			Index:               uint64(chunkIndex),
+			AssignedVerifiers:   assigned,
+			Approvals:           approved,
+			MissingApprovals:    collector.GetMissingSigners().Sort(flow.IdentifierCanonical),
+			SufficientApprovals: c.aggregatedSignatures.HasSignature(uint64(chunkIndex)),
+		})
+	}
+	return statuses
+}
@@ -93,4 +93,9 @@ type AssignmentCollectorState interface {
 
 	// ProcessingStatus returns the AssignmentCollector's ProcessingStatus (state descriptor).
 	ProcessingStatus() ProcessingStatus
+
+	// SealingStatus returns the approval status for each incorporated result of this collector,
+	// for inspecting why a result is not sealed. Emergency sealing eligibility is evaluated
+	// with respect to the given finalized height.
+	SealingStatus(finalizedBlockHeight uint64) []consensus.IncorporatedResultSealingStatus
 }
@@ -77,6 +77,27 @@ func (t *AssignmentCollectorTree) GetCollector(resultID flow.Identifier) Assignm
 	return v.collector
 }
 
+// GetCollectorsForBlock returns the collectors for all results of the given executed block,
+// regardless of their ProcessingStatus.
+func (t *AssignmentCollectorTree) GetCollectorsForBlock(executedBlock *flow.Header) []AssignmentCollector {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	if executedBlock.Height < t.forest.LowestLevel {
+		return nil
+	}
+
+	blockID := executedBlock.ID()
+	var collectors []AssignmentCollector
+	iter := t.forest.GetVerticesAtLevel(executedBlock.Height)
+	for iter.HasNext() {
+		vertex := iter.NextVertex().(*assignmentCollectorVertex)
+		if vertex.collector.BlockID() == blockID {
+			collectors = append(collectors, vertex.collector)
+		}
+	}
+	return collectors
+}
+
 // FinalizeForkAtLevel orphans forks in the AssignmentCollectorTree and prunes levels below the
 // sealed finalized height. When a block is finalized we can mark results for conflicting forks as
 // orphaned and stop processing approvals for them. Eventually all forks will be cleaned up by height.
@@ -88,6 +88,22 @@ func (ac *CachingAssignmentCollector) ProcessApproval(approval *flow.ResultAppro
 	return nil
 }
 
+// SealingStatus returns the cached incorporated results. As the approvals are not yet verified,
+// the chunk assignments are not computed and the chunk statuses are omitted.
+func (ac *CachingAssignmentCollector) SealingStatus(uint64) []consensus.IncorporatedResultSealingStatus {
+	incorporatedResults := ac.GetIncorporatedResults()
+	statuses := make([]consensus.IncorporatedResultSealingStatus, 0, len(incorporatedResults))
+	for _, incorporatedResult := range incorporatedResults {
+		status := consensus.IncorporatedResultSealingStatus{IncorporatedBlockID: incorporatedResult.IncorporatedBlockID}
+		// the height is informational only, the incorporating block is known as the result was accepted
+		if incorporatedBlock, err := ac.headers.ByBlockID(incorporatedResult.IncorporatedBlockID); err == nil {
+			status.IncorporatedBlockHeight = incorporatedBlock.Height
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
 func (ac *CachingAssignmentCollector) GetIncorporatedResults() []*flow.IncorporatedResult {
 	return ac.incResCache.All()
 }
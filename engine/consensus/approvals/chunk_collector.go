@@ -39,6 +39,21 @@ func (c *ChunkApprovalCollector) ProcessApproval(approval *flow.ResultApproval)
 	return flow.AggregatedSignature{}, false
 }
 
+// Status returns the verifiers assigned to the chunk and the subset of them that provided an approval.
+func (c *ChunkApprovalCollector) Status() (assigned flow.IdentifierList, approved flow.IdentifierList) {
+	assigned = make(flow.IdentifierList, 0, len(c.assignment))
+	c.lock.Lock()
+	for id := range c.assignment {
+		assigned = append(assigned, id)
+		if c.chunkApprovals.HasSigned(id) {
+			approved = append(approved, id)
+		}
+	}
+	c.lock.Unlock()
+
+	return assigned.Sort(flow.IdentifierCanonical), approved.Sort(flow.IdentifierCanonical)
+}
+
 // GetMissingSigners returns ids of approvers that are present in assignment but didn't provide approvals
 func (c *ChunkApprovalCollector) GetMissingSigners() flow.IdentifierList {
 	// provide capacity for worst-case
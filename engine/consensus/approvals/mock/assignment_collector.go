@@ -214,6 +214,26 @@ func (_m *AssignmentCollector) ResultID() flow.Identifier {
 	return r0
 }
 
+// SealingStatus provides a mock function with given fields: finalizedBlockHeight
+func (_m *AssignmentCollector) SealingStatus(finalizedBlockHeight uint64) []consensus.IncorporatedResultSealingStatus {
+	ret := _m.Called(finalizedBlockHeight)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SealingStatus")
+	}
+
+	var r0 []consensus.IncorporatedResultSealingStatus
+	if rf, ok := ret.Get(0).(func(uint64) []consensus.IncorporatedResultSealingStatus); ok {
+		r0 = rf(finalizedBlockHeight)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]consensus.IncorporatedResultSealingStatus)
+		}
+	}
+
+	return r0
+}
+
 // NewAssignmentCollector creates a new instance of AssignmentCollector. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAssignmentCollector(t interface {
@@ -23,6 +23,9 @@ func (oc *OrphanAssignmentCollector) CheckEmergencySealing(consensus.SealingObse
 func (oc *OrphanAssignmentCollector) RequestMissingApprovals(consensus.SealingObservation, uint64) (uint, error) {
 	return 0, nil
 }
+func (oc *OrphanAssignmentCollector) SealingStatus(uint64) []consensus.IncorporatedResultSealingStatus {
+	return nil
+}
 func (oc *OrphanAssignmentCollector) ProcessIncorporatedResult(*flow.IncorporatedResult) error {
 	return nil
 }
@@ -2,6 +2,7 @@ package approvals
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/rs/zerolog"
@@ -387,6 +388,35 @@ func (ac *VerifyingAssignmentCollector) RequestMissingApprovals(observation cons
 	return overallRequestCount, nil
 }
 
+// SealingStatus returns the approval status for each incorporated result of this collector,
+// including the approval requests made for the chunks with missing approvals.
+func (ac *VerifyingAssignmentCollector) SealingStatus(finalizedBlockHeight uint64) []consensus.IncorporatedResultSealingStatus {
+	collectors := ac.allCollectors()
+	statuses := make([]consensus.IncorporatedResultSealingStatus, 0, len(collectors))
+	for _, collector := range collectors {
+		chunks := collector.ChunkStatuses()
+		sufficientApprovals := true
+		for i := range chunks {
+			sufficientApprovals = sufficientApprovals && chunks[i].SufficientApprovals
+			if item, ok := ac.requestTracker.Get(ac.ResultID(), collector.IncorporatedBlockID(), chunks[i].Index); ok {
+				chunks[i].ApprovalRequests = item.Requests
+				chunks[i].NextApprovalRequest = item.NextTimeout
+			}
+		}
+		statuses = append(statuses, consensus.IncorporatedResultSealingStatus{
+			IncorporatedBlockID:     collector.IncorporatedBlockID(),
+			IncorporatedBlockHeight: collector.IncorporatedBlock().Height,
+			SufficientApprovals:     sufficientApprovals,
+			EmergencySealable:       ac.emergencySealable(collector, finalizedBlockHeight),
+			Chunks:                  chunks,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].IncorporatedBlockHeight < statuses[j].IncorporatedBlockHeight
+	})
+	return statuses
+}
+
 // authorizedVerifiersAtBlock pre-select all authorized Verifiers at the block that incorporates the result.
 // The method returns the set of all node IDs that:
 //   - are authorized members of the network at the given block and
@@ -3,7 +3,9 @@
 package mock
 
 import (
+	consensus "github.com/onflow/flow-go/engine/consensus"
 	flow "github.com/onflow/flow-go/model/flow"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -66,6 +68,36 @@ func (_m *SealingCore) ProcessIncorporatedResult(result *flow.IncorporatedResult
 	return r0
 }
 
+// SealingStatus provides a mock function with given fields: blockID
+func (_m *SealingCore) SealingStatus(blockID flow.Identifier) (*consensus.SealingStatus, error) {
+	ret := _m.Called(blockID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SealingStatus")
+	}
+
+	var r0 *consensus.SealingStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(flow.Identifier) (*consensus.SealingStatus, error)); ok {
+		return rf(blockID)
+	}
+	if rf, ok := ret.Get(0).(func(flow.Identifier) *consensus.SealingStatus); ok {
+		r0 = rf(blockID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*consensus.SealingStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(flow.Identifier) error); ok {
+		r1 = rf(blockID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewSealingCore creates a new instance of SealingCore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewSealingCore(t interface {
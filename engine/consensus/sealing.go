@@ -22,4 +22,6 @@ type SealingCore interface {
 	// * exception in case of unexpected error
 	// * nil - successfully processed finalized block
 	ProcessFinalizedBlock(finalizedBlockID flow.Identifier) error
+	// SealingStatusProvider explains the sealing progress of individual blocks. Concurrency safe.
+	SealingStatusProvider
 }
@@ -637,6 +637,47 @@ func (c *Core) requestPendingApprovals(observation consensus.SealingObservation,
 	return nil
 }
 
+// SealingStatus reports the state of the sealing logic for the given executed block.
+// Results of blocks which are sealed, or which are orphaned below the sealed height, are no
+// longer tracked, so only the block's sealing and finalization status is reported for them.
+// Expected errors during normal operations:
+//   - storage.ErrNotFound if the block is unknown
+func (c *Core) SealingStatus(blockID flow.Identifier) (*consensus.SealingStatus, error) {
+	executedBlock, err := c.headers.ByBlockID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve block %v: %w", blockID, err)
+	}
+	status := &consensus.SealingStatus{
+		BlockID:                blockID,
+		Height:                 executedBlock.Height,
+		LastSealedHeight:       c.counterLastSealedHeight.Value(),
+		LastFinalizedHeight:    c.counterLastFinalizedHeight.Value(),
+		EmergencySealingActive: c.sealingConfigsGetter.EmergencySealingActiveConst(),
+	}
+
+	if executedBlock.Height <= status.LastFinalizedHeight {
+		finalizedID, err := c.headers.BlockIDByHeight(executedBlock.Height)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve finalized block at height %d: %w", executedBlock.Height, err)
+		}
+		status.Finalized = finalizedID == blockID
+		status.Sealed = status.Finalized && executedBlock.Height <= status.LastSealedHeight
+	}
+
+	for _, collector := range c.collectorTree.GetCollectorsForBlock(executedBlock) {
+		incorporations := collector.SealingStatus(status.LastFinalizedHeight)
+		for i := range incorporations {
+			incorporations[i].EmergencySealable = incorporations[i].EmergencySealable && status.EmergencySealingActive
+		}
+		status.Results = append(status.Results, consensus.ResultSealingStatus{
+			ResultID:         collector.ResultID(),
+			ProcessingStatus: collector.ProcessingStatus().String(),
+			Incorporations:   incorporations,
+		})
+	}
+	return status, nil
+}
+
 // getOutdatedBlockIDsFromRootSealingSegment finds all references to unknown blocks
 // by execution results within the sealing segment. In general we disallow references
 // to unknown blocks, but execution results incorporated within the sealing segment
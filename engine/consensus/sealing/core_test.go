@@ -20,6 +20,7 @@ import (
 	"github.com/onflow/flow-go/module/trace"
 	"github.com/onflow/flow-go/module/updatable_configs"
 	mockstate "github.com/onflow/flow-go/state/protocol/mock"
+	realstorage "github.com/onflow/flow-go/storage"
 	storage "github.com/onflow/flow-go/storage/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 )
@@ -270,6 +271,53 @@ func (s *ApprovalProcessingCoreTestSuite) TestProcessIncorporated_ApprovalsAfter
 	s.SealsPL.AssertCalled(s.T(), "Add", mock.Anything)
 }
 
+// TestSealingStatus tests that the sealing status reports the received and missing approvals
+// for every chunk of the tracked results, and that unknown blocks are reported as not found.
+func (s *ApprovalProcessingCoreTestSuite) TestSealingStatus() {
+	s.PublicKey.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+
+	err := s.core.processIncorporatedResult(s.IncorporatedResult)
+	require.NoError(s.T(), err)
+
+	// all verifiers approve the first chunk only
+	for verID := range s.AuthorizedVerifiers {
+		approval := unittest.ResultApprovalFixture(unittest.WithChunk(s.Chunks[0].Index),
+			unittest.WithApproverID(verID),
+			unittest.WithBlockID(s.Block.ID()),
+			unittest.WithExecutionResultID(s.IncorporatedResult.Result.ID()))
+		err := s.core.processApproval(approval)
+		require.NoError(s.T(), err)
+	}
+
+	status, err := s.core.SealingStatus(s.Block.ID())
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), s.Block.Height, status.Height)
+	require.False(s.T(), status.Sealed)
+	require.Len(s.T(), status.Results, 1)
+	result := status.Results[0]
+	require.Equal(s.T(), s.IncorporatedResult.Result.ID(), result.ResultID)
+	require.Equal(s.T(), approvals.VerifyingApprovals.String(), result.ProcessingStatus)
+	require.Len(s.T(), result.Incorporations, 1)
+	incorporation := result.Incorporations[0]
+	require.Equal(s.T(), s.IncorporatedBlock.ID(), incorporation.IncorporatedBlockID)
+	require.False(s.T(), incorporation.SufficientApprovals)
+	require.False(s.T(), incorporation.EmergencySealable)
+	require.Len(s.T(), incorporation.Chunks, len(s.Chunks))
+
+	first, second := incorporation.Chunks[0], incorporation.Chunks[1]
+	require.True(s.T(), first.SufficientApprovals)
+	require.Len(s.T(), first.AssignedVerifiers, len(s.AuthorizedVerifiers))
+	require.NotEmpty(s.T(), first.Approvals) // approvals are no longer collected once the chunk has sufficient approvals
+	require.ElementsMatch(s.T(), first.AssignedVerifiers, append(first.Approvals, first.MissingApprovals...))
+	require.False(s.T(), second.SufficientApprovals)
+	require.Empty(s.T(), second.Approvals)
+	require.ElementsMatch(s.T(), second.AssignedVerifiers, second.MissingApprovals)
+	require.Zero(s.T(), second.ApprovalRequests)
+
+	_, err = s.core.SealingStatus(unittest.IdentifierFixture())
+	require.ErrorIs(s.T(), err, realstorage.ErrNotFound)
+}
+
 // TestProcessIncorporated_ProcessingInvalidApproval tests that processing invalid approval when result is discovered
 // is correctly handled in case of sentinel error
 func (s *ApprovalProcessingCoreTestSuite) TestProcessIncorporated_ProcessingInvalidApproval() {
@@ -428,6 +428,13 @@ func (e *Engine) onApproval(originID flow.Identifier, approval *flow.ResultAppro
 	return nil
 }
 
+// SealingStatus reports the state of the sealing logic for the given executed block.
+// Expected errors during normal operations:
+//   - storage.ErrNotFound if the block is unknown
+func (e *Engine) SealingStatus(blockID flow.Identifier) (*consensus.SealingStatus, error) {
+	return e.core.SealingStatus(blockID)
+}
+
 // OnFinalizedBlock implements the `OnFinalizedBlock` callback from the `hotstuff.FinalizationConsumer`
 // It informs sealing.Core about finalization of respective block.
 //
@@ -0,0 +1,78 @@
+package consensus
+
+import (
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// SealingStatusProvider explains the sealing progress of individual blocks, for debugging
+// blocks which remain unsealed. Implementations are concurrency safe.
+type SealingStatusProvider interface {
+	// SealingStatus reports the state of the sealing logic for the given executed block:
+	// all results for the block that are tracked by the sealing logic, the blocks incorporating
+	// each result with the respective verifier assignment, the received and missing approvals
+	// per chunk and the approval requests sent for the chunk.
+	// Expected errors during normal operations:
+	//   - storage.ErrNotFound if the block is unknown
+	SealingStatus(blockID flow.Identifier) (*SealingStatus, error)
+}
+
+// SealingStatus is a snapshot of the sealing logic's state for a single executed block.
+type SealingStatus struct {
+	BlockID flow.Identifier
+	Height  uint64
+	// Sealed is true if the block is finalized and sealed. Once a block is sealed, the sealing
+	// logic no longer tracks its results.
+	Sealed bool
+	// Finalized is true if the block is finalized.
+	Finalized           bool
+	LastSealedHeight    uint64
+	LastFinalizedHeight uint64
+	// EmergencySealingActive is true if the node is configured to emergency-seal results which
+	// lack approvals for too long.
+	EmergencySealingActive bool
+	Results                []ResultSealingStatus
+}
+
+// ResultSealingStatus is the sealing status of one execution result for the block.
+type ResultSealingStatus struct {
+	ResultID flow.Identifier
+	// ProcessingStatus is the state of the assignment collector for the result. Approvals are
+	// only verified once the result's parent result is being verified or sealed
+	// (VerifyingApprovals), approvals for results on orphaned forks are discarded (Orphaned).
+	ProcessingStatus string
+	Incorporations   []IncorporatedResultSealingStatus
+}
+
+// IncorporatedResultSealingStatus is the sealing status of an execution result incorporated
+// in a specific block. Each incorporating block determines a distinct verifier assignment.
+type IncorporatedResultSealingStatus struct {
+	IncorporatedBlockID     flow.Identifier
+	IncorporatedBlockHeight uint64
+	// SufficientApprovals is true if every chunk has the approvals required for constructing
+	// a seal, i.e. a candidate seal has been produced for the incorporated result.
+	SufficientApprovals bool
+	// EmergencySealable is true if the incorporated result qualifies for emergency sealing,
+	// based on the number of finalized blocks after the executed and the incorporating block.
+	EmergencySealable bool
+	// Chunks is empty while the approvals for the result are only cached, as the assignment
+	// is only computed when the approvals are verified.
+	Chunks []ChunkSealingStatus
+}
+
+// ChunkSealingStatus is the approval status of a single chunk of an incorporated result.
+type ChunkSealingStatus struct {
+	Index             uint64
+	AssignedVerifiers flow.IdentifierList
+	Approvals         flow.IdentifierList
+	MissingApprovals  flow.IdentifierList
+	// SufficientApprovals is true if the chunk has the approvals required for constructing a seal.
+	SufficientApprovals bool
+	// ApprovalRequests is the number of times missing approvals for the chunk were requested
+	// from the assigned verifiers.
+	ApprovalRequests uint
+	// NextApprovalRequest is the earliest time at which approvals are requested again, zero
+	// if approvals were never requested for the chunk.
+	NextApprovalRequest time.Time
+}
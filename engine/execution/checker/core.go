@@ -7,108 +7,282 @@ import (
 
 	"github.com/rs/zerolog"
 
+	"github.com/onflow/flow-go/engine/execution/ingestion/stop"
 	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
 
+// ExecutionHalter stops block execution, it is implemented by stop.StopControl.
+type ExecutionHalter interface {
+	SetStopParameters(stop stop.StopParameters) error
+}
+
+// ExecutionForkError is returned when the locally computed execution result of
+// a block differs from the sealed result.
+type ExecutionForkError struct {
+	Record *ForkRecord
+}
+
+func NewExecutionForkError(record *ForkRecord) ExecutionForkError {
+	return ExecutionForkError{Record: record}
+}
+
+func (e ExecutionForkError) Error() string {
+	return fmt.Sprintf("execution result is different from the sealed result, height: %v, block_id: %v, sealed_commit: %v, my_commit: %v, first_diverging_chunk: %v",
+		e.Record.Height,
+		e.Record.BlockID,
+		e.Record.SealedCommit,
+		e.Record.MyCommit,
+		e.Record.FirstDivergingChunk,
+	)
+}
+
+// IsExecutionForkError returns true if the error is an ExecutionForkError.
+func IsExecutionForkError(err error) bool {
+	var forkErr ExecutionForkError
+	return errors.As(err, &forkErr)
+}
+
 // Core is the core logic of the checker engine that checks if the execution result matches the sealed result.
 type Core struct {
 	log       zerolog.Logger
 	state     protocol.State
 	execState state.ExecutionState
+	seals     storage.Seals
+	results   storage.ExecutionResults
+	recorder  *ForkRecorder
+	metrics   module.ExecutionMetrics
+	halter    ExecutionHalter // nil if execution should not be halted on a fork
+
+	// lastCheckedHeight is the highest sealed height which has been checked,
+	// or zero if no height has ever been checked. It is persisted by the
+	// recorder, so that no sealed height is skipped or checked again after a
+	// restart.
+	lastCheckedHeight uint64
 }
 
+// NewCore creates a new checker core.
+// If halter is not nil, block execution is halted once a fork is detected.
 func NewCore(
 	logger zerolog.Logger,
 	state protocol.State,
 	execState state.ExecutionState,
+	seals storage.Seals,
+	results storage.ExecutionResults,
+	recorder *ForkRecorder,
+	metrics module.ExecutionMetrics,
+	halter ExecutionHalter,
 ) *Core {
 	e := &Core{
 		log:       logger.With().Str("engine", "checker").Logger(),
 		state:     state,
 		execState: execState,
+		seals:     seals,
+		results:   results,
+		recorder:  recorder,
+		metrics:   metrics,
+		halter:    halter,
+
+		lastCheckedHeight: recorder.LastCheckedHeight(),
 	}
 
 	return e
 }
 
-// checkMyCommitWithSealedCommit is the main check of the checker engine
-func checkMyCommitWithSealedCommit(
-	logger zerolog.Logger,
-	executedBlock *flow.Header,
-	myCommit flow.StateCommitment,
-	sealedCommit flow.StateCommitment,
-) error {
-	if myCommit != sealedCommit {
-		// mismatch
-		return fmt.Errorf("execution result is different from the sealed result, height: %v, block_id: %v, sealed_commit: %v, my_commit: %v",
-			executedBlock.Height,
-			executedBlock.ID(),
-			sealedCommit,
-			myCommit,
-		)
-	}
-
-	logger.Info().
-		Uint64("height", executedBlock.Height).
-		Str("block_id", executedBlock.ID().String()).
-		Msg("execution result matches the sealed result")
-
-	// match
-	return nil
+// HaltsOnFork returns true if block execution is halted once a fork is detected.
+func (c *Core) HaltsOnFork() bool {
+	return c.halter != nil
 }
 
-// RunCheck skips when the last sealed has not been executed, and last executed has not been finalized.
+// RunCheck checks the execution results of all blocks sealed since the last check
+// against the sealed results. Blocks which have not been executed yet are checked
+// by later runs. Checking resumes after the last checked height persisted by the
+// recorder. If no height has ever been checked, it begins at the highest height
+// which is both sealed and executed.
+//
+// When a result differs from the sealed result, the first diverging chunk is
+// localized, a fork record is persisted, and block execution is halted if enabled.
+// If execution cannot be halted, the fork is returned as an exception.
+//
+// Expected errors during normal operations:
+//   - ExecutionForkError if an execution result differs from the sealed result
 func (c *Core) RunCheck() error {
 	// find last sealed block
-	lastSealedBlock, lastFinal, seal, err := c.findLastSealedBlock()
+	lastSealedBlock, lastFinal, _, err := c.findLastSealedBlock()
 	if err != nil {
 		return err
 	}
 
-	mycommitAtLastSealed, err := c.execState.StateCommitmentByBlockID(lastSealedBlock.ID())
-	if err == nil {
-		// if last sealed block has been executed, then check if they match
-		return checkMyCommitWithSealedCommit(c.log, lastSealedBlock, mycommitAtLastSealed, seal.FinalState)
-	}
-
-	// if last sealed block has not been executed, then check if recent executed block has
-	// been sealed already, if yes, check if they match.
 	lastExecutedHeight, err := c.findLastExecutedBlockHeight()
 	if err != nil {
 		return err
 	}
 
-	if lastExecutedHeight > lastFinal.Height {
-		// last executed block has not been finalized yet,
-		// can't check since unfinalized block is also unsealed, skip
-		return nil
+	// unsealed blocks can't be checked, and blocks above the last executed
+	// height have not been executed yet.
+	toHeight := lastSealedBlock.Height
+	if lastExecutedHeight < toHeight {
+		toHeight = lastExecutedHeight
 	}
 
-	// TODO: better to query seals from protocol state,
-	// switch to state.Final().LastSealed() when available
-	sealedExecuted, seal, err := c.findLatestSealedAtHeight(lastExecutedHeight)
-	if err != nil {
-		return fmt.Errorf("could not get the last sealed block at height: %v, err: %w", lastExecutedHeight, err)
+	fromHeight := c.lastCheckedHeight + 1
+	if c.lastCheckedHeight == 0 {
+		fromHeight = toHeight
+	}
+
+	for height := fromHeight; height <= toHeight; height++ {
+		executed, err := c.checkHeight(height)
+		if err != nil {
+			if IsExecutionForkError(err) {
+				haltErr := c.haltExecution(lastFinal)
+				if haltErr != nil {
+					return fmt.Errorf("could not halt execution after detecting a fork (%s): %w", err.Error(), haltErr)
+				}
+			}
+			return err
+		}
+
+		if !executed {
+			// the finalized block at this height has not been executed yet,
+			// it will be checked by the next run
+			break
+		}
+
+		c.lastCheckedHeight = height
+		c.metrics.ExecutionLastCheckedSealedHeight(height)
 	}
 
-	sealedCommit := seal.FinalState
+	if fromHeight <= c.lastCheckedHeight {
+		err = c.recorder.SetLastCheckedHeight(c.lastCheckedHeight)
+		if err != nil {
+			return fmt.Errorf("could not persist last checked height: %w", err)
+		}
 
-	mycommit, err := c.execState.StateCommitmentByBlockID(seal.BlockID)
+		c.log.Info().
+			Uint64("from_height", fromHeight).
+			Uint64("to_height", c.lastCheckedHeight).
+			Msg("execution results match the sealed results")
+	}
+
+	return nil
+}
+
+// checkHeight compares the execution result of the sealed block at the given
+// height with the sealed result, and returns false if the block has not been
+// executed yet.
+//
+// Expected errors during normal operations:
+//   - ExecutionForkError if the execution result differs from the sealed result
+func (c *Core) checkHeight(height uint64) (bool, error) {
+	header, err := c.state.AtHeight(height).Head()
+	if err != nil {
+		return false, fmt.Errorf("could not get the sealed block at height %v: %w", height, err)
+	}
+	blockID := header.ID()
+
+	myResultID, err := c.execState.GetExecutionResultID(context.Background(), blockID)
 	if errors.Is(err, storage.ErrNotFound) {
-		// have not executed the sealed block yet
-		// in other words, this can't detect execution fork, if the execution is behind
-		// the sealing
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not get my execution result for block %v: %w", blockID, err)
+	}
+
+	seal, err := c.seals.FinalizedSealForBlock(blockID)
+	if err != nil {
+		return false, fmt.Errorf("could not get the seal for block %v: %w", blockID, err)
+	}
+
+	if myResultID == seal.ResultID {
+		return true, nil
+	}
+
+	myResult, err := c.results.ByID(myResultID)
+	if err != nil {
+		return false, fmt.Errorf("could not get my execution result %v: %w", myResultID, err)
+	}
+
+	sealedResult, err := c.results.ByID(seal.ResultID)
+	if err != nil {
+		return false, fmt.Errorf("could not get the sealed execution result %v: %w", seal.ResultID, err)
+	}
+
+	record, err := localizeFork(header, myResult, sealedResult)
+	if err != nil {
+		return false, fmt.Errorf("could not localize execution fork at block %v: %w", blockID, err)
+	}
+
+	if record.FirstDivergingChunk == -1 {
+		// all chunks match, so the execution state does not diverge (e.g. only
+		// the execution data ID differs), which does not warrant halting.
+		c.log.Warn().
+			Uint64("height", record.Height).
+			Hex("block_id", record.BlockID[:]).
+			Hex("my_result_id", record.MyResultID[:]).
+			Hex("sealed_result_id", record.SealedResultID[:]).
+			Msg("execution result differs from the sealed result, but all chunks match")
+		return true, nil
+	}
+
+	if record.Chunk != nil && record.FirstDivergingChunk < len(myResult.Chunks) {
+		chunkID := myResult.Chunks[record.FirstDivergingChunk].ID()
+		chunkDataPack, err := c.execState.ChunkDataPackByChunkID(chunkID)
+		if errors.Is(err, storage.ErrNotFound) {
+			c.log.Warn().
+				Hex("chunk_id", chunkID[:]).
+				Msg("chunk data pack of diverging chunk not found, fork record will not include registers")
+		} else if err != nil {
+			return false, fmt.Errorf("could not get chunk data pack %v: %w", chunkID, err)
+		} else {
+			record.TouchedRegisters, err = registersFromChunkDataPack(chunkDataPack)
+			if err != nil {
+				return false, fmt.Errorf("could not get registers of chunk %v: %w", chunkID, err)
+			}
+		}
+	}
+
+	c.log.Error().
+		Uint64("height", record.Height).
+		Hex("block_id", record.BlockID[:]).
+		Hex("my_result_id", record.MyResultID[:]).
+		Hex("sealed_result_id", record.SealedResultID[:]).
+		Int("first_diverging_chunk", record.FirstDivergingChunk).
+		Int("touched_registers", len(record.TouchedRegisters)).
+		Msg("execution fork detected")
+
+	err = c.recorder.Record(record)
+	if err != nil {
+		return false, fmt.Errorf("could not record execution fork: %w", err)
+	}
+
+	c.metrics.ExecutionForkDetected(record.Height, record.FirstDivergingChunk)
+
+	return false, NewExecutionForkError(record)
+}
+
+// haltExecution stops block execution at the next finalized height, if halting
+// on forks is enabled.
+// No errors are expected during normal operation.
+func (c *Core) haltExecution(lastFinal *flow.Header) error {
+	if c.halter == nil {
 		return nil
 	}
 
+	err := c.halter.SetStopParameters(stop.StopParameters{
+		StopBeforeHeight: lastFinal.Height + 1,
+		ShouldCrash:      false,
+	})
 	if err != nil {
-		return fmt.Errorf("could not get my state commitment OnFinalizedBlock, blockID: %v", seal.BlockID)
+		return fmt.Errorf("could not set stop parameters: %w", err)
 	}
 
-	return checkMyCommitWithSealedCommit(c.log, sealedExecuted, mycommit, sealedCommit)
+	c.log.Warn().
+		Uint64("stop_before_height", lastFinal.Height+1).
+		Msg("halting execution after detecting a fork")
+	return nil
 }
 
 // findLastSealedBlock finds the last sealed block
@@ -140,17 +314,3 @@ func (c *Core) findLastExecutedBlockHeight() (uint64, error) {
 	}
 	return height, nil
 }
-
-// findLatestSealedAtHeight finds the latest sealed block at the given height
-func (c *Core) findLatestSealedAtHeight(finalizedHeight uint64) (*flow.Header, *flow.Seal, error) {
-	_, seal, err := c.state.AtHeight(finalizedHeight).SealedResult()
-	if err != nil {
-		return nil, nil, fmt.Errorf("could not get the last sealed for the finalized block: %w", err)
-	}
-
-	sealed, err := c.state.AtBlockID(seal.BlockID).Head()
-	if err != nil {
-		return nil, nil, fmt.Errorf("could not get the last sealed block: %w", err)
-	}
-	return sealed, seal, nil
-}
@@ -1,74 +1,141 @@
 package checker_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/engine/execution/checker"
+	"github.com/onflow/flow-go/engine/execution/ingestion/stop"
 	stateMock "github.com/onflow/flow-go/engine/execution/state/mock"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/convert"
+	"github.com/onflow/flow-go/ledger/common/testutils"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+	modulemock "github.com/onflow/flow-go/module/mock"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
 	"github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
-func makeCore(t *testing.T) (*checker.Core, *protocol.State, *stateMock.ExecutionState) {
+type testCore struct {
+	core      *checker.Core
+	state     *protocol.State
+	execState *stateMock.ExecutionState
+	seals     *storagemock.Seals
+	results   *storagemock.ExecutionResults
+	recorder  *checker.ForkRecorder
+	dir       string
+}
+
+// recordingHalter records the stop parameters it was asked to set, or fails with err if set.
+type recordingHalter struct {
+	stops []stop.StopParameters
+	err   error
+}
+
+func (h *recordingHalter) SetStopParameters(params stop.StopParameters) error {
+	if h.err != nil {
+		return h.err
+	}
+	h.stops = append(h.stops, params)
+	return nil
+}
+
+func makeCore(t *testing.T) *testCore {
+	return makeCoreWith(t, metrics.NewNoopCollector(), nil)
+}
+
+func makeCoreWith(t *testing.T, collector module.ExecutionMetrics, halter checker.ExecutionHalter) *testCore {
 	logger := unittest.Logger()
 	state := protocol.NewState(t)
 	execState := stateMock.NewExecutionState(t)
-	core := checker.NewCore(logger, state, execState)
-	return core, state, execState
+	seals := storagemock.NewSeals(t)
+	results := storagemock.NewExecutionResults(t)
+	dir := t.TempDir()
+	recorder, err := checker.NewForkRecorder(dir)
+	require.NoError(t, err)
+
+	core := checker.NewCore(logger, state, execState, seals, results, recorder, collector, halter)
+	return &testCore{
+		core:      core,
+		state:     state,
+		execState: execState,
+		seals:     seals,
+		results:   results,
+		recorder:  recorder,
+		dir:       dir,
+	}
 }
 
-func mockFinalizedBlock(t *testing.T, state *protocol.State, finalized *flow.Header) *protocol.Snapshot {
+func mockFinalizedSealedBlock(t *testing.T, state *protocol.State, finalized *flow.Header, sealed *flow.Header) {
 	finalizedSnapshot := protocol.NewSnapshot(t)
 	finalizedSnapshot.On("Head").Return(finalized, nil)
 	state.On("Final").Return(finalizedSnapshot)
-	return finalizedSnapshot
+
+	lastSealResult := unittest.ExecutionResultFixture(unittest.WithExecutionResultBlockID(sealed.ID()))
+	lastSeal := unittest.Seal.Fixture(unittest.Seal.WithResult(lastSealResult))
+	finalizedSnapshot.On("SealedResult").Return(lastSealResult, lastSeal, nil)
+
+	sealedSnapshot := protocol.NewSnapshot(t)
+	sealedSnapshot.On("Head").Return(sealed, nil)
+	state.On("AtBlockID", sealed.ID()).Return(sealedSnapshot)
 }
 
-func mockAtBlockID(t *testing.T, state *protocol.State, header *flow.Header) *protocol.Snapshot {
+func mockLastExecuted(es *stateMock.ExecutionState, executed *flow.Header) {
+	es.On("GetLastExecutedBlockID", mock.Anything).Return(executed.Height, executed.ID(), nil)
+}
+
+func mockAtHeight(t *testing.T, state *protocol.State, header *flow.Header) {
 	snapshot := protocol.NewSnapshot(t)
 	snapshot.On("Head").Return(header, nil)
-	state.On("AtBlockID", header.ID()).Return(snapshot)
-	return snapshot
+	state.On("AtHeight", header.Height).Return(snapshot)
 }
 
-func mockSealedBlock(t *testing.T, state *protocol.State, finalized *protocol.Snapshot, sealed *flow.Header) (*flow.ExecutionResult, *flow.Seal) {
-	lastSealResult := unittest.ExecutionResultFixture(func(r *flow.ExecutionResult) {
-		r.BlockID = sealed.ID()
-	})
-	lastSeal := unittest.Seal.Fixture(unittest.Seal.WithResult(lastSealResult))
-	finalized.On("SealedResult").Return(lastSealResult, lastSeal, nil)
-	return lastSealResult, lastSeal
+// mockSealedResult mocks the sealed result of the block, and returns it.
+func mockSealedResult(c *testCore, header *flow.Header) *flow.ExecutionResult {
+	result := unittest.ExecutionResultFixture(unittest.WithExecutionResultBlockID(header.ID()))
+	seal := unittest.Seal.Fixture(unittest.Seal.WithResult(result))
+	c.seals.On("FinalizedSealForBlock", header.ID()).Return(seal, nil)
+	return result
 }
 
-func mockFinalizedSealedBlock(t *testing.T, state *protocol.State, finalized *flow.Header, sealed *flow.Header) (*flow.ExecutionResult, *flow.Seal) {
-	finalizedSnapshot := mockFinalizedBlock(t, state, finalized)
-	return mockSealedBlock(t, state, finalizedSnapshot, sealed)
+// mockExecutedBlock mocks the block as executed with the given result.
+func mockExecutedBlock(c *testCore, header *flow.Header, result *flow.ExecutionResult) {
+	c.execState.On("GetExecutionResultID", mock.Anything, header.ID()).Return(result.ID(), nil)
 }
 
-func mockSealedBlockAtHeight(t *testing.T, state *protocol.State, height uint64, lastSealed *flow.Header) (*flow.ExecutionResult, *flow.Seal) {
-	snapshotAtHeight := protocol.NewSnapshot(t)
-	lastSealedResultAtHeight := unittest.ExecutionResultFixture(func(r *flow.ExecutionResult) {
-		r.BlockID = lastSealed.ID()
-	})
-	lastSealAtHeight := unittest.Seal.Fixture(unittest.Seal.WithResult(lastSealedResultAtHeight))
-	snapshotAtHeight.On("SealedResult").Return(lastSealedResultAtHeight, lastSealAtHeight, nil)
-	state.On("AtHeight", height).Return(snapshotAtHeight, nil)
-	return lastSealedResultAtHeight, lastSealAtHeight
+func mockUnexecutedBlock(c *testCore, header *flow.Header) {
+	c.execState.On("GetExecutionResultID", mock.Anything, header.ID()).Return(flow.ZeroID, storage.ErrNotFound)
 }
 
-func mockExecutedBlock(t *testing.T, es *stateMock.ExecutionState, executed *flow.Header, result *flow.ExecutionResult) {
-	commit, err := result.FinalStateCommitment()
-	require.NoError(t, err)
-	es.On("StateCommitmentByBlockID", executed.ID()).Return(commit, nil)
-}
+// mockMismatchingResult mocks the block as executed with a result which differs
+// from the sealed result in the given chunk, and returns the mismatching result.
+func mockMismatchingResult(c *testCore, header *flow.Header, sealedResult *flow.ExecutionResult, chunkIndex int) *flow.ExecutionResult {
+	myResult := unittest.ExecutionResultFixture(unittest.WithExecutionResultBlockID(header.ID()))
+	myResult.PreviousResultID = sealedResult.PreviousResultID
+	myResult.ExecutionDataID = sealedResult.ExecutionDataID
+	myResult.Chunks = make(flow.ChunkList, len(sealedResult.Chunks))
+	for i, chunk := range sealedResult.Chunks {
+		diverging := *chunk
+		if i >= chunkIndex {
+			diverging.EndState = unittest.StateCommitmentFixture()
+		}
+		if i > chunkIndex {
+			diverging.StartState = myResult.Chunks[i-1].EndState
+		}
+		myResult.Chunks[i] = &diverging
+	}
 
-func mockUnexecutedBlock(t *testing.T, es *stateMock.ExecutionState, unexecuted *flow.Header) {
-	es.On("StateCommitmentByBlockID", unexecuted.ID()).Return(nil, storage.ErrNotFound)
+	mockExecutedBlock(c, header, myResult)
+	c.results.On("ByID", myResult.ID()).Return(myResult, nil)
+	c.results.On("ByID", sealedResult.ID()).Return(sealedResult, nil)
+	return myResult
 }
 
 func TestCheckPassIfLastSealedIsExecutedAndMatch(t *testing.T) {
@@ -76,13 +143,16 @@ func TestCheckPassIfLastSealedIsExecutedAndMatch(t *testing.T) {
 	chain, _, _ := unittest.ChainFixture(10)
 	lastFinal := chain[7].Header
 	lastSealed := chain[5].Header
+	lastExecuted := chain[9].Header
 
-	core, state, es := makeCore(t)
-	lastSealedResult, _ := mockFinalizedSealedBlock(t, state, lastFinal, lastSealed)
-	mockAtBlockID(t, state, lastSealed)
-	mockExecutedBlock(t, es, lastSealed, lastSealedResult)
+	c := makeCore(t)
+	mockFinalizedSealedBlock(t, c.state, lastFinal, lastSealed)
+	mockLastExecuted(c.execState, lastExecuted)
+	mockAtHeight(t, c.state, lastSealed)
+	result := mockSealedResult(c, lastSealed)
+	mockExecutedBlock(c, lastSealed, result)
 
-	require.NoError(t, core.RunCheck())
+	require.NoError(t, c.core.RunCheck())
 }
 
 func TestCheckFailIfLastSealedIsExecutedButMismatch(t *testing.T) {
@@ -90,67 +160,232 @@ func TestCheckFailIfLastSealedIsExecutedButMismatch(t *testing.T) {
 	chain, _, _ := unittest.ChainFixture(10)
 	lastFinal := chain[7].Header
 	lastSealed := chain[5].Header
+	lastExecuted := chain[9].Header
 
-	core, state, es := makeCore(t)
-	_, _ = mockFinalizedSealedBlock(t, state, lastFinal, lastSealed)
-	mockAtBlockID(t, state, lastSealed)
-
-	mismatchingResult := unittest.ExecutionResultFixture()
-
-	mockExecutedBlock(t, es, lastSealed, mismatchingResult)
+	c := makeCore(t)
+	mockFinalizedSealedBlock(t, c.state, lastFinal, lastSealed)
+	mockLastExecuted(c.execState, lastExecuted)
+	mockAtHeight(t, c.state, lastSealed)
+	sealedResult := mockSealedResult(c, lastSealed)
+	myResult := mockMismatchingResult(c, lastSealed, sealedResult, 1)
+	c.execState.On("ChunkDataPackByChunkID", myResult.Chunks[1].ID()).Return(nil, storage.ErrNotFound)
 
-	require.Error(t, core.RunCheck())
-	require.Contains(t, core.RunCheck().Error(), "execution result is different from the sealed result")
+	err := c.core.RunCheck()
+	require.Error(t, err)
+	require.True(t, checker.IsExecutionForkError(err))
+	require.Contains(t, err.Error(), "execution result is different from the sealed result")
 }
 
 func TestCheckPassIfLastSealedIsNotExecutedAndLastExecutedMatch(t *testing.T) {
-	// LastSealedExecuted (sealed) <..<- LastExecuted(finalized) <..<- LastSealed(not executed) <..<- LastFinalized
+	// LastExecuted(sealed) <..<- LastSealed(not executed) <..<- LastFinalized
 	chain, _, _ := unittest.ChainFixture(10)
 	lastFinal := chain[7].Header
 	lastSealed := chain[5].Header
 	lastExecuted := chain[3].Header
-	lastSealedExecuted := chain[1].Header
 
-	core, state, es := makeCore(t)
-	// mock that last sealed is not executed
-	mockFinalizedSealedBlock(t, state, lastFinal, lastSealed)
-	mockAtBlockID(t, state, lastSealed)
-	mockUnexecutedBlock(t, es, lastSealed)
+	c := makeCore(t)
+	mockFinalizedSealedBlock(t, c.state, lastFinal, lastSealed)
+	mockLastExecuted(c.execState, lastExecuted)
 
-	// mock the last sealed and is also executed
-	es.On("GetLastExecutedBlockID", mock.Anything).Return(lastExecuted.Height, lastExecuted.ID(), nil)
-	lastSealedResultAtExecutedHeight, _ := mockSealedBlockAtHeight(t, state, lastExecuted.Height, lastSealedExecuted)
-	mockAtBlockID(t, state, lastSealedExecuted)
+	// the last executed block is checked, since it is sealed
+	mockAtHeight(t, c.state, lastExecuted)
+	result := mockSealedResult(c, lastExecuted)
+	mockExecutedBlock(c, lastExecuted, result)
 
-	// mock with matching result
-	mockExecutedBlock(t, es, lastSealedExecuted, lastSealedResultAtExecutedHeight)
-
-	require.NoError(t, core.RunCheck())
+	require.NoError(t, c.core.RunCheck())
 }
 
 func TestCheckFailIfLastSealedIsNotExecutedAndLastExecutedMismatch(t *testing.T) {
-	// LastSealedExecuted (sealed) <..<- LastExecuted(finalized) <..<- LastSealed(not executed) <..<- LastFinalized
+	// LastExecuted(sealed) <..<- LastSealed(not executed) <..<- LastFinalized
 	chain, _, _ := unittest.ChainFixture(10)
 	lastFinal := chain[7].Header
 	lastSealed := chain[5].Header
 	lastExecuted := chain[3].Header
-	lastSealedExecuted := chain[1].Header
 
-	core, state, es := makeCore(t)
-	// mock that last sealed is not executed
-	mockFinalizedSealedBlock(t, state, lastFinal, lastSealed)
-	mockAtBlockID(t, state, lastSealed)
-	mockUnexecutedBlock(t, es, lastSealed)
+	c := makeCore(t)
+	mockFinalizedSealedBlock(t, c.state, lastFinal, lastSealed)
+	mockLastExecuted(c.execState, lastExecuted)
+
+	mockAtHeight(t, c.state, lastExecuted)
+	sealedResult := mockSealedResult(c, lastExecuted)
+	myResult := mockMismatchingResult(c, lastExecuted, sealedResult, 0)
+	c.execState.On("ChunkDataPackByChunkID", myResult.Chunks[0].ID()).Return(nil, storage.ErrNotFound)
 
-	// mock the last sealed and is also executed
-	es.On("GetLastExecutedBlockID", mock.Anything).Return(lastExecuted.Height, lastExecuted.ID(), nil)
-	mockSealedBlockAtHeight(t, state, lastExecuted.Height, lastSealedExecuted)
-	mockAtBlockID(t, state, lastSealedExecuted)
+	err := c.core.RunCheck()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "execution result is different from the sealed result")
+}
 
-	// mock with mismatching result
-	mismatchingResult := unittest.ExecutionResultFixture()
-	mockExecutedBlock(t, es, lastSealedExecuted, mismatchingResult)
+func TestCheckEverySealedHeightSinceLastCheck(t *testing.T) {
+	chain, _, _ := unittest.ChainFixture(10)
+
+	c := makeCore(t)
+
+	// first run only checks the last sealed and executed block
+	mockFinalizedSealedBlock(t, c.state, chain[7].Header, chain[3].Header)
+	mockLastExecuted(c.execState, chain[9].Header)
+	mockAtHeight(t, c.state, chain[3].Header)
+	mockExecutedBlock(c, chain[3].Header, mockSealedResult(c, chain[3].Header))
+
+	require.NoError(t, c.core.RunCheck())
+
+	// after more blocks are sealed, every sealed height is checked up to the
+	// first unexecuted block
+	c.state.ExpectedCalls = nil
+	c.execState.ExpectedCalls = nil
+	mockFinalizedSealedBlock(t, c.state, chain[9].Header, chain[8].Header)
+	mockLastExecuted(c.execState, chain[9].Header)
+	for _, block := range chain[4:6] {
+		mockAtHeight(t, c.state, block.Header)
+		mockExecutedBlock(c, block.Header, mockSealedResult(c, block.Header))
+	}
+	mockAtHeight(t, c.state, chain[6].Header)
+	mockUnexecutedBlock(c, chain[6].Header)
+
+	require.NoError(t, c.core.RunCheck())
+
+	// the next run resumes at the unexecuted block
+	c.state.ExpectedCalls = nil
+	c.execState.ExpectedCalls = nil
+	mockFinalizedSealedBlock(t, c.state, chain[9].Header, chain[6].Header)
+	mockLastExecuted(c.execState, chain[9].Header)
+	mockAtHeight(t, c.state, chain[6].Header)
+	mockExecutedBlock(c, chain[6].Header, mockSealedResult(c, chain[6].Header))
+
+	require.NoError(t, c.core.RunCheck())
+}
 
-	require.Error(t, core.RunCheck())
-	require.Contains(t, core.RunCheck().Error(), "execution result is different from the sealed result")
+func TestForkLocalizesFirstDivergingChunk(t *testing.T) {
+	chain, _, _ := unittest.ChainFixture(10)
+	lastFinal := chain[7].Header
+	lastSealed := chain[5].Header
+
+	halter := &recordingHalter{}
+	collector := modulemock.NewExecutionMetrics(t)
+	c := makeCoreWith(t, collector, halter)
+
+	mockFinalizedSealedBlock(t, c.state, lastFinal, lastSealed)
+	mockLastExecuted(c.execState, lastSealed)
+	mockAtHeight(t, c.state, lastSealed)
+	sealedResult := mockSealedResult(c, lastSealed)
+	myResult := mockMismatchingResult(c, lastSealed, sealedResult, 1)
+
+	// the chunk data pack proves the registers touched by the chunk
+	touched := []flow.RegisterID{
+		flow.NewRegisterID(unittest.RandomAddressFixture(), "b"),
+		flow.NewRegisterID(unittest.RandomAddressFixture(), "a"),
+	}
+	proof := ledger.NewTrieBatchProof()
+	for _, id := range touched {
+		p, _ := testutils.TrieProofFixture()
+		p.Payload = ledger.NewPayload(convert.RegisterIDToLedgerKey(id), []byte("value"))
+		proof.Proofs = append(proof.Proofs, p)
+	}
+	chunkDataPack := unittest.ChunkDataPackFixture(myResult.Chunks[1].ID(), func(cdp *flow.ChunkDataPack) {
+		cdp.Proof = ledger.EncodeTrieBatchProof(proof)
+	})
+	c.execState.On("ChunkDataPackByChunkID", myResult.Chunks[1].ID()).Return(chunkDataPack, nil)
+
+	collector.On("ExecutionForkDetected", lastSealed.Height, 1).Once()
+
+	err := c.core.RunCheck()
+	require.True(t, checker.IsExecutionForkError(err))
+
+	// execution is halted at the next finalized height
+	require.Equal(t, []stop.StopParameters{{StopBeforeHeight: lastFinal.Height + 1}}, halter.stops)
+
+	records := c.recorder.Records()
+	require.Len(t, records, 1)
+	record := records[0]
+	require.Equal(t, lastSealed.ID(), record.BlockID)
+	require.Equal(t, lastSealed.Height, record.Height)
+	require.Equal(t, myResult.ID(), record.MyResultID)
+	require.Equal(t, sealedResult.ID(), record.SealedResultID)
+	require.Equal(t, 1, record.FirstDivergingChunk)
+	require.False(t, record.Chunk.StartStateDiverges)
+	require.True(t, record.Chunk.EndStateDiverges)
+	require.False(t, record.Chunk.EventCollectionDiverges)
+	require.False(t, record.Chunk.ServiceEventsDiverge)
+
+	expectedRegisters := []string{touched[0].String(), touched[1].String()}
+	if expectedRegisters[0] > expectedRegisters[1] {
+		expectedRegisters[0], expectedRegisters[1] = expectedRegisters[1], expectedRegisters[0]
+	}
+	require.Equal(t, expectedRegisters, record.TouchedRegisters)
+}
+
+func TestCheckFailIfForkCannotBeHalted(t *testing.T) {
+	chain, _, _ := unittest.ChainFixture(10)
+	lastFinal := chain[7].Header
+	lastSealed := chain[5].Header
+
+	halter := &recordingHalter{err: fmt.Errorf("stop control is not accepting parameters")}
+	c := makeCoreWith(t, metrics.NewNoopCollector(), halter)
+	mockFinalizedSealedBlock(t, c.state, lastFinal, lastSealed)
+	mockLastExecuted(c.execState, lastSealed)
+	mockAtHeight(t, c.state, lastSealed)
+	sealedResult := mockSealedResult(c, lastSealed)
+	myResult := mockMismatchingResult(c, lastSealed, sealedResult, 0)
+	c.execState.On("ChunkDataPackByChunkID", myResult.Chunks[0].ID()).Return(nil, storage.ErrNotFound)
+
+	// the fork is not an expected error anymore, so that the checker does not
+	// stop while the node keeps executing on the fork
+	err := c.core.RunCheck()
+	require.Error(t, err)
+	require.False(t, checker.IsExecutionForkError(err))
+	require.ErrorIs(t, err, halter.err)
+}
+
+func TestCheckPassIfNoChunkDiverges(t *testing.T) {
+	chain, _, _ := unittest.ChainFixture(10)
+	lastFinal := chain[7].Header
+	lastSealed := chain[5].Header
+
+	halter := &recordingHalter{}
+	c := makeCoreWith(t, metrics.NewNoopCollector(), halter)
+	mockFinalizedSealedBlock(t, c.state, lastFinal, lastSealed)
+	mockLastExecuted(c.execState, lastSealed)
+	mockAtHeight(t, c.state, lastSealed)
+	sealedResult := mockSealedResult(c, lastSealed)
+
+	// only the execution data ID differs
+	myResult := unittest.ExecutionResultFixture(unittest.WithExecutionResultBlockID(lastSealed.ID()))
+	myResult.PreviousResultID = sealedResult.PreviousResultID
+	myResult.Chunks = sealedResult.Chunks
+	myResult.ServiceEvents = sealedResult.ServiceEvents
+	mockExecutedBlock(c, lastSealed, myResult)
+	c.results.On("ByID", myResult.ID()).Return(myResult, nil)
+	c.results.On("ByID", sealedResult.ID()).Return(sealedResult, nil)
+
+	require.NoError(t, c.core.RunCheck())
+	require.Empty(t, halter.stops)
+	require.Empty(t, c.recorder.Records())
+}
+
+func TestCheckResumesAfterRestart(t *testing.T) {
+	chain, _, _ := unittest.ChainFixture(10)
+
+	c := makeCore(t)
+	mockFinalizedSealedBlock(t, c.state, chain[7].Header, chain[3].Header)
+	mockLastExecuted(c.execState, chain[9].Header)
+	mockAtHeight(t, c.state, chain[3].Header)
+	mockExecutedBlock(c, chain[3].Header, mockSealedResult(c, chain[3].Header))
+	require.NoError(t, c.core.RunCheck())
+
+	// after a restart, every height sealed since the last check is checked
+	recorder, err := checker.NewForkRecorder(c.dir)
+	require.NoError(t, err)
+	require.Equal(t, chain[3].Header.Height, recorder.LastCheckedHeight())
+	c.recorder = recorder
+	c.state.ExpectedCalls = nil
+	c.execState.ExpectedCalls = nil
+	c.core = checker.NewCore(unittest.Logger(), c.state, c.execState, c.seals, c.results, recorder, metrics.NewNoopCollector(), nil)
+	mockFinalizedSealedBlock(t, c.state, chain[9].Header, chain[5].Header)
+	mockLastExecuted(c.execState, chain[9].Header)
+	for _, block := range chain[4:6] {
+		mockAtHeight(t, c.state, block.Header)
+		mockExecutedBlock(c, block.Header, mockSealedResult(c, block.Header))
+	}
+	require.NoError(t, c.core.RunCheck())
+	require.Equal(t, chain[5].Header.Height, recorder.LastCheckedHeight())
 }
@@ -37,9 +37,11 @@ func NewEngine(core *Core) *Engine {
 // runLoop runs the check every minute.
 // Why using a timer instead of listening to finalized and executed events?
 // because it's simpler as it doesn't need to subscribe to those events.
-// It also runs less checks, note: the checker doesn't need to find the
-// first mismatched block, as long as it can find a mismatch, it's good enough.
-// A timer could reduce the number of checks, as it only checks once every minute.
+// A timer could reduce the number of checks, as each run checks all blocks sealed
+// since the previous run.
+//
+// If a fork is detected and execution is being halted, the loop stops without
+// throwing, so that the node keeps running and the fork can be investigated.
 func (e *Engine) runLoop(ctx context.Context, tickInterval time.Duration) error {
 	ticker := time.NewTicker(tickInterval)
 	defer ticker.Stop() // critical for ticker to be garbage collected
@@ -47,6 +49,9 @@ func (e *Engine) runLoop(ctx context.Context, tickInterval time.Duration) error
 		select {
 		case <-ticker.C:
 			err := e.core.RunCheck()
+			if IsExecutionForkError(err) && e.core.HaltsOnFork() {
+				return nil
+			}
 			if err != nil {
 				return err
 			}
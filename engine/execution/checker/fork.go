@@ -0,0 +1,357 @@
+package checker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/convert"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ChunkDivergence describes how the locally computed version of a chunk differs
+// from the sealed version.
+type ChunkDivergence struct {
+	Index           uint64 `json:"index"`
+	CollectionIndex uint   `json:"collection_index"`
+
+	StartStateDiverges      bool `json:"start_state_diverges"`
+	EndStateDiverges        bool `json:"end_state_diverges"`
+	EventCollectionDiverges bool `json:"event_collection_diverges"`
+	ServiceEventsDiverge    bool `json:"service_events_diverge"`
+
+	MyStartState          flow.StateCommitment `json:"my_start_state"`
+	SealedStartState      flow.StateCommitment `json:"sealed_start_state"`
+	MyEndState            flow.StateCommitment `json:"my_end_state"`
+	SealedEndState        flow.StateCommitment `json:"sealed_end_state"`
+	MyEventCollection     flow.Identifier      `json:"my_event_collection"`
+	SealedEventCollection flow.Identifier      `json:"sealed_event_collection"`
+	MyServiceEvents       int                  `json:"my_service_events"`
+	SealedServiceEvents   int                  `json:"sealed_service_events"`
+}
+
+// ForkRecord describes a block for which the locally computed execution result
+// differs from the sealed result.
+type ForkRecord struct {
+	BlockID        flow.Identifier      `json:"block_id"`
+	Height         uint64               `json:"height"`
+	DetectedAt     time.Time            `json:"detected_at"`
+	MyResultID     flow.Identifier      `json:"my_result_id"`
+	SealedResultID flow.Identifier      `json:"sealed_result_id"`
+	MyCommit       flow.StateCommitment `json:"my_commit"`
+	SealedCommit   flow.StateCommitment `json:"sealed_commit"`
+
+	// FirstDivergingChunk is the index of the first chunk which differs from
+	// the sealed result, or -1 if all chunks match (e.g. if only the execution
+	// data ID differs).
+	FirstDivergingChunk int              `json:"first_diverging_chunk"`
+	Chunk               *ChunkDivergence `json:"chunk,omitempty"`
+
+	// TouchedRegisters are all registers read or written by the local
+	// version of the first diverging chunk, taken from its chunk data pack.
+	// The chunk data pack of the sealed version is not available locally, so
+	// these are the candidates for the divergence, not the registers which
+	// actually diverge. Registers are formatted as `<hex owner>/<key>`. Empty
+	// if the chunk data pack is not available (e.g. it was pruned).
+	TouchedRegisters []string `json:"touched_registers,omitempty"`
+}
+
+// localizeFork compares the locally computed execution result with the sealed
+// result of the same block, and returns a fork record pointing to the first
+// diverging chunk.
+// No errors are expected during normal operation.
+func localizeFork(
+	header *flow.Header,
+	myResult *flow.ExecutionResult,
+	sealedResult *flow.ExecutionResult,
+) (*ForkRecord, error) {
+	myCommit, err := myResult.FinalStateCommitment()
+	if err != nil {
+		return nil, fmt.Errorf("could not get final state of my result %v: %w", myResult.ID(), err)
+	}
+
+	sealedCommit, err := sealedResult.FinalStateCommitment()
+	if err != nil {
+		return nil, fmt.Errorf("could not get final state of sealed result %v: %w", sealedResult.ID(), err)
+	}
+
+	record := &ForkRecord{
+		BlockID:             header.ID(),
+		Height:              header.Height,
+		DetectedAt:          time.Now(),
+		MyResultID:          myResult.ID(),
+		SealedResultID:      sealedResult.ID(),
+		MyCommit:            myCommit,
+		SealedCommit:        sealedCommit,
+		FirstDivergingChunk: -1,
+	}
+
+	numChunks := len(myResult.Chunks)
+	if len(sealedResult.Chunks) > numChunks {
+		numChunks = len(sealedResult.Chunks)
+	}
+
+	for i := 0; i < numChunks; i++ {
+		if i >= len(myResult.Chunks) || i >= len(sealedResult.Chunks) {
+			// the results have a different number of chunks, which should
+			// never happen for the same block. Report the first missing chunk
+			// without any further details.
+			record.FirstDivergingChunk = i
+			record.Chunk = &ChunkDivergence{Index: uint64(i)}
+			return record, nil
+		}
+
+		divergence, err := compareChunks(myResult, sealedResult, uint64(i))
+		if err != nil {
+			return nil, err
+		}
+
+		if divergence != nil {
+			record.FirstDivergingChunk = i
+			record.Chunk = divergence
+			return record, nil
+		}
+	}
+
+	return record, nil
+}
+
+// compareChunks compares the chunk at the given index of both results, and
+// returns nil if the chunks match.
+// No errors are expected during normal operation.
+func compareChunks(
+	myResult *flow.ExecutionResult,
+	sealedResult *flow.ExecutionResult,
+	index uint64,
+) (*ChunkDivergence, error) {
+	mine := myResult.Chunks[index]
+	sealed := sealedResult.Chunks[index]
+
+	myServiceEvents := myResult.ServiceEventsByChunk(index)
+	sealedServiceEvents := sealedResult.ServiceEventsByChunk(index)
+
+	serviceEventsEqual, err := serviceEventsEqual(myServiceEvents, sealedServiceEvents)
+	if err != nil {
+		return nil, fmt.Errorf("could not compare service events of chunk %d: %w", index, err)
+	}
+
+	divergence := &ChunkDivergence{
+		Index:                   index,
+		CollectionIndex:         mine.CollectionIndex,
+		StartStateDiverges:      mine.StartState != sealed.StartState,
+		EndStateDiverges:        mine.EndState != sealed.EndState,
+		EventCollectionDiverges: mine.EventCollection != sealed.EventCollection,
+		ServiceEventsDiverge:    !serviceEventsEqual,
+		MyStartState:            mine.StartState,
+		SealedStartState:        sealed.StartState,
+		MyEndState:              mine.EndState,
+		SealedEndState:          sealed.EndState,
+		MyEventCollection:       mine.EventCollection,
+		SealedEventCollection:   sealed.EventCollection,
+		MyServiceEvents:         len(myServiceEvents),
+		SealedServiceEvents:     len(sealedServiceEvents),
+	}
+
+	if !divergence.StartStateDiverges &&
+		!divergence.EndStateDiverges &&
+		!divergence.EventCollectionDiverges &&
+		!divergence.ServiceEventsDiverge {
+		return nil, nil
+	}
+
+	return divergence, nil
+}
+
+// No errors are expected during normal operation.
+func serviceEventsEqual(a flow.ServiceEventList, b flow.ServiceEventList) (bool, error) {
+	if len(a) != len(b) {
+		return false, nil
+	}
+
+	for i := range a {
+		equal, err := a[i].EqualTo(&b[i])
+		if err != nil {
+			return false, err
+		}
+		if !equal {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// registersFromChunkDataPack returns the registers read or written while
+// executing the chunk, in sorted order.
+// No errors are expected during normal operation.
+func registersFromChunkDataPack(chunkDataPack *flow.ChunkDataPack) ([]string, error) {
+	proof, err := ledger.DecodeTrieBatchProof(chunkDataPack.Proof)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode chunk data pack proof: %w", err)
+	}
+
+	registers := make([]string, 0, len(proof.Proofs))
+	for _, p := range proof.Proofs {
+		id, _, err := convert.PayloadToRegister(p.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert payload to register: %w", err)
+		}
+		registers = append(registers, id.String())
+	}
+
+	sort.Strings(registers)
+
+	return registers, nil
+}
+
+// lastCheckedHeightFile is the name of the file in the fork record directory
+// which holds the highest sealed height checked by the checker.
+const lastCheckedHeightFile = "last-checked-height"
+
+// ForkRecorder persists fork records as one JSON file per block in a
+// directory, so they are still available for inspection after a restart.
+// It also persists the highest sealed height which has been checked, so that
+// the checker resumes after it after a restart.
+//
+// ForkRecorder is safe for concurrent use.
+type ForkRecorder struct {
+	dir string
+
+	mu                sync.RWMutex
+	records           map[flow.Identifier]*ForkRecord
+	lastCheckedHeight uint64
+}
+
+// NewForkRecorder creates a fork recorder writing to the given directory, and
+// loads any records written previously.
+// No errors are expected during normal operation.
+func NewForkRecorder(dir string) (*ForkRecorder, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("could not create fork record dir %v: %w", dir, err)
+	}
+
+	r := &ForkRecorder{
+		dir:     dir,
+		records: make(map[flow.Identifier]*ForkRecord),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read fork record dir %v: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("could not read fork record %v: %w", entry.Name(), err)
+		}
+
+		var record ForkRecord
+		err = json.Unmarshal(data, &record)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode fork record %v: %w", entry.Name(), err)
+		}
+
+		r.records[record.BlockID] = &record
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, lastCheckedHeightFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("could not read last checked height: %w", err)
+	}
+	if err == nil {
+		r.lastCheckedHeight, err = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode last checked height: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+// LastCheckedHeight returns the highest sealed height which has been checked,
+// or zero if no height has been checked.
+func (r *ForkRecorder) LastCheckedHeight() uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.lastCheckedHeight
+}
+
+// SetLastCheckedHeight persists the highest sealed height which has been
+// checked. The file is replaced atomically, so a crash does not leave a
+// partially written height behind.
+// No errors are expected during normal operation.
+func (r *ForkRecorder) SetLastCheckedHeight(height uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path := filepath.Join(r.dir, lastCheckedHeightFile)
+	tmp := path + ".tmp"
+	err := os.WriteFile(tmp, []byte(strconv.FormatUint(height, 10)), 0644)
+	if err != nil {
+		return fmt.Errorf("could not write last checked height: %w", err)
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return fmt.Errorf("could not replace last checked height: %w", err)
+	}
+
+	r.lastCheckedHeight = height
+	return nil
+}
+
+// Record persists the fork record.  Recording a fork for a block which was
+// already recorded keeps the original record.
+// No errors are expected during normal operation.
+func (r *ForkRecorder) Record(record *ForkRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.records[record.BlockID]; ok {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode fork record: %w", err)
+	}
+
+	path := filepath.Join(r.dir, fmt.Sprintf("fork-%d-%s.json", record.Height, record.BlockID))
+	err = os.WriteFile(path, data, 0644)
+	if err != nil {
+		return fmt.Errorf("could not write fork record: %w", err)
+	}
+
+	r.records[record.BlockID] = record
+	return nil
+}
+
+// Records returns all fork records, ordered by height.
+func (r *ForkRecorder) Records() []*ForkRecord {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := make([]*ForkRecord, 0, len(r.records))
+	for _, record := range r.records {
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Height < records[j].Height
+	})
+
+	return records
+}
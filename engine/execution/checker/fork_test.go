@@ -0,0 +1,49 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/checker"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestForkRecorderReloadsRecords(t *testing.T) {
+	dir := t.TempDir()
+
+	recorder, err := checker.NewForkRecorder(dir)
+	require.NoError(t, err)
+	require.Empty(t, recorder.Records())
+
+	higher := &checker.ForkRecord{
+		BlockID:             unittest.IdentifierFixture(),
+		Height:              20,
+		FirstDivergingChunk: 2,
+		TouchedRegisters:    []string{"01/#02"},
+	}
+	lower := &checker.ForkRecord{
+		BlockID:             unittest.IdentifierFixture(),
+		Height:              10,
+		FirstDivergingChunk: -1,
+	}
+	require.NoError(t, recorder.Record(higher))
+	require.NoError(t, recorder.Record(lower))
+
+	// recording the same block again keeps the original record
+	require.NoError(t, recorder.Record(&checker.ForkRecord{
+		BlockID: higher.BlockID,
+		Height:  20,
+	}))
+
+	reloaded, err := checker.NewForkRecorder(dir)
+	require.NoError(t, err)
+
+	records := reloaded.Records()
+	require.Len(t, records, 2)
+	require.Equal(t, lower.BlockID, records[0].BlockID)
+	require.Equal(t, -1, records[0].FirstDivergingChunk)
+	require.Equal(t, higher.BlockID, records[1].BlockID)
+	require.Equal(t, 2, records[1].FirstDivergingChunk)
+	require.Equal(t, higher.TouchedRegisters, records[1].TouchedRegisters)
+}
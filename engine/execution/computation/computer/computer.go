@@ -11,6 +11,7 @@ import (
 	otelTrace "go.opentelemetry.io/otel/trace"
 
 	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/engine/execution/computation/profiler"
 	"github.com/onflow/flow-go/engine/execution/computation/result"
 	"github.com/onflow/flow-go/engine/execution/utils"
 	"github.com/onflow/flow-go/fvm"
@@ -117,6 +118,27 @@ type blockComputer struct {
 	colResCons            []result.ExecutedCollectionConsumer
 	protocolState         protocol.SnapshotExecutionSubsetProvider
 	maxConcurrency        int
+	conflictScheduler     *ConflictScheduler
+	profiler              *profiler.Profiler
+}
+
+// BlockComputerOption configures optional block computer behavior.
+type BlockComputerOption func(*blockComputer)
+
+// WithConflictScheduler enables conflict-aware transaction scheduling.  This
+// is only useful when maxConcurrency is greater than 1.
+func WithConflictScheduler(scheduler *ConflictScheduler) BlockComputerOption {
+	return func(computer *blockComputer) {
+		computer.conflictScheduler = scheduler
+	}
+}
+
+// WithProfiler enables recording per-transaction execution profiles for the
+// blocks selected by the profiler.
+func WithProfiler(p *profiler.Profiler) BlockComputerOption {
+	return func(computer *blockComputer) {
+		computer.profiler = p
+	}
 }
 
 func SystemChunkContext(vmCtx fvm.Context, metrics module.ExecutionMetrics) fvm.Context {
@@ -149,6 +171,7 @@ func NewBlockComputer(
 	colResCons []result.ExecutedCollectionConsumer,
 	state protocol.SnapshotExecutionSubsetProvider,
 	maxConcurrency int,
+	opts ...BlockComputerOption,
 ) (BlockComputer, error) {
 	if maxConcurrency < 1 {
 		return nil, fmt.Errorf("invalid maxConcurrency: %d", maxConcurrency)
@@ -165,7 +188,7 @@ func NewBlockComputer(
 		vmCtx,
 		fvm.WithMetricsReporter(metrics),
 		fvm.WithTracer(tracer))
-	return &blockComputer{
+	computer := &blockComputer{
 		vm:                    vm,
 		vmCtx:                 vmCtx,
 		metrics:               metrics,
@@ -180,7 +203,13 @@ func NewBlockComputer(
 		colResCons:            colResCons,
 		protocolState:         state,
 		maxConcurrency:        maxConcurrency,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(computer)
+	}
+
+	return computer, nil
 }
 
 // ExecuteBlock executes a block and returns the resulting chunks.
@@ -215,6 +244,7 @@ func (e *blockComputer) queueTransactionRequests(
 	systemTxnBody *flow.TransactionBody,
 	requestQueue chan TransactionRequest,
 	numTxns int,
+	profiling bool,
 ) {
 	txnIndex := uint32(0)
 
@@ -223,6 +253,9 @@ func (e *blockComputer) queueTransactionRequests(
 		fvm.WithBlockHeader(blockHeader),
 		fvm.WithProtocolStateSnapshot(e.protocolState.AtBlockID(blockId)),
 	)
+	if profiling {
+		collectionCtx = fvm.NewContextFromParent(collectionCtx, fvm.WithContractFunctionProfiling())
+	}
 
 	for idx, collection := range rawCollections {
 		collectionLogger := collectionCtx.Logger.With().
@@ -258,6 +291,9 @@ func (e *blockComputer) queueTransactionRequests(
 		fvm.WithBlockHeader(blockHeader),
 		fvm.WithProtocolStateSnapshot(e.protocolState.AtBlockID(blockId)),
 	)
+	if profiling {
+		systemCtx = fvm.NewContextFromParent(systemCtx, fvm.WithContractFunctionProfiling())
+	}
 	systemCollectionLogger := systemCtx.Logger.With().
 		Str("block_id", blockIdStr).
 		Uint64("height", blockHeader.Height).
@@ -359,6 +395,11 @@ func (e *blockComputer) executeBlock(
 		return nil, fmt.Errorf("could not select chunk constructor for current protocol version: %w", err)
 	}
 
+	var blockProfile *profiler.BlockProfile
+	if e.profiler != nil {
+		blockProfile = e.profiler.StartBlock(blockId, block.Height())
+	}
+
 	collector := newResultCollector(
 		e.tracer,
 		blockSpan,
@@ -374,16 +415,28 @@ func (e *blockComputer) executeBlock(
 		e.colResCons,
 		baseSnapshot,
 		versionedChunkConstructor,
+		blockProfile,
 	)
 	defer collector.Stop()
 
 	requestQueue := make(chan TransactionRequest, numTxns)
 
+	var writeBehindLog TransactionWriteBehindLogger = collector
+	var conflictTracker *blockConflictTracker
+	if e.conflictScheduler != nil && e.maxConcurrency > 1 {
+		conflictTracker = newBlockConflictTracker(
+			e.conflictScheduler,
+			e.metrics,
+			collector,
+			numTxns)
+		writeBehindLog = conflictTracker
+	}
+
 	database := newTransactionCoordinator(
 		e.vm,
 		baseSnapshot,
 		derivedBlockData,
-		collector)
+		writeBehindLog)
 
 	e.queueTransactionRequests(
 		blockId,
@@ -393,6 +446,7 @@ func (e *blockComputer) executeBlock(
 		systemTxn,
 		requestQueue,
 		numTxns,
+		blockProfile != nil,
 	)
 	close(requestQueue)
 
@@ -403,6 +457,7 @@ func (e *blockComputer) executeBlock(
 		go e.executeTransactions(
 			blockSpan,
 			database,
+			conflictTracker,
 			requestQueue,
 			wg)
 	}
@@ -414,11 +469,19 @@ func (e *blockComputer) executeBlock(
 		return nil, err
 	}
 
+	if conflictTracker != nil {
+		conflictTracker.Finish()
+	}
+
 	res, err := collector.Finalize(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("cannot finalize computation result: %w", err)
 	}
 
+	if blockProfile != nil {
+		e.profiler.FinishBlock(blockProfile)
+	}
+
 	e.log.Debug().
 		Hex("block_id", logging.Entity(block)).
 		Msg("all views committed")
@@ -431,12 +494,25 @@ func (e *blockComputer) executeBlock(
 func (e *blockComputer) executeTransactions(
 	blockSpan otelTrace.Span,
 	database *transactionCoordinator,
+	conflictTracker *blockConflictTracker,
 	requestQueue chan TransactionRequest,
 	wg *sync.WaitGroup,
 ) {
 	defer wg.Done()
 
 	for request := range requestQueue {
+		if conflictTracker != nil &&
+			conflictTracker.start(request, database.SnapshotTime()) {
+
+			request.ctx.Logger.Info().
+				Msg("transaction likely to conflict. waiting for preceding transactions")
+
+			err := database.waitForSnapshotTime(request.ExecutionTime())
+			if err != nil {
+				return
+			}
+		}
+
 		attempt := 0
 		for {
 			request.ctx.Logger.Info().
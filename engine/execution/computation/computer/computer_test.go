@@ -2,10 +2,14 @@ package computer_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/common"
@@ -27,6 +31,7 @@ import (
 	"github.com/onflow/flow-go/engine/execution/computation/committer"
 	"github.com/onflow/flow-go/engine/execution/computation/computer"
 	computermock "github.com/onflow/flow-go/engine/execution/computation/computer/mock"
+	"github.com/onflow/flow-go/engine/execution/computation/profiler"
 	"github.com/onflow/flow-go/engine/execution/storehouse"
 	"github.com/onflow/flow-go/engine/execution/testutil"
 	"github.com/onflow/flow-go/fvm"
@@ -49,6 +54,7 @@ import (
 	"github.com/onflow/flow-go/module/epochs"
 	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
 	"github.com/onflow/flow-go/module/executiondatasync/provider"
+	"github.com/onflow/flow-go/module/irrecoverable"
 	mocktracker "github.com/onflow/flow-go/module/executiondatasync/tracker/mock"
 	"github.com/onflow/flow-go/module/mempool/entity"
 	"github.com/onflow/flow-go/module/metrics"
@@ -541,6 +547,90 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 		assert.LessOrEqual(t, vm.CallCount(), (1+3)/2*3)
 	})
 
+	t.Run("profiled block records every transaction", func(t *testing.T) {
+
+		execCtx := fvm.NewContext()
+
+		vm := &testVM{
+			t:                    t,
+			eventsPerTransaction: 1,
+		}
+
+		committer := &fakeCommitter{
+			callCount: 0,
+		}
+
+		bservice := requesterunit.MockBlobService(blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore())))
+		trackerStorage := mocktracker.NewMockStorage()
+
+		prov := provider.NewProvider(
+			zerolog.Nop(),
+			metrics.NewNoopCollector(),
+			execution_data.DefaultSerializer,
+			bservice,
+			trackerStorage,
+		)
+
+		profileDir := t.TempDir()
+		p, err := profiler.New(zerolog.Nop(), profileDir)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		p.Start(irrecoverable.NewMockSignalerContext(t, ctx))
+
+		exe, err := computer.NewBlockComputer(
+			vm,
+			execCtx,
+			metrics.NewNoopCollector(),
+			trace.NewNoopTracer(),
+			zerolog.Nop(),
+			committer,
+			me,
+			prov,
+			nil,
+			testutil.ProtocolStateWithSourceFixture(nil),
+			testMaxConcurrency,
+			computer.WithProfiler(p))
+		require.NoError(t, err)
+
+		// create a block with 2 collections with 2 transactions each
+		block := generateBlock(2, 2, rag)
+		p.SetHeightRange(&profiler.HeightRange{
+			Start: block.Height(),
+			End:   block.Height(),
+		})
+
+		_, err = exe.ExecuteBlock(
+			context.Background(),
+			unittest.IdentifierFixture(),
+			block,
+			nil,
+			derived.NewEmptyDerivedBlockData(0))
+		require.NoError(t, err)
+
+		profileFile := filepath.Join(
+			profileDir,
+			fmt.Sprintf("%d-%s.json", block.Height(), block.ID()))
+		require.Eventually(t, func() bool {
+			_, err := os.Stat(profileFile)
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+
+		data, err := os.ReadFile(profileFile)
+		require.NoError(t, err)
+
+		var blockProfile profiler.BlockProfile
+		require.NoError(t, json.Unmarshal(data, &blockProfile))
+		require.Equal(t, block.ID(), blockProfile.BlockID)
+		require.Len(t, blockProfile.Transactions, 2*2+1) // +1 system transaction
+
+		for i, txn := range blockProfile.Transactions {
+			require.Equal(t, uint32(i), txn.TransactionIndex)
+		}
+		require.True(t, blockProfile.Transactions[2*2].SystemTx)
+	})
+
 	t.Run("empty block still computes system chunk", func(t *testing.T) {
 
 		execCtx := fvm.NewContext()
@@ -0,0 +1,352 @@
+package computer
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onflow/crypto/hash"
+
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/storage/logical"
+	"github.com/onflow/flow-go/fvm/storage/snapshot"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+)
+
+const (
+	// DefaultConflictScoreDecay is the factor applied to every conflict score
+	// after each executed block.  With the default, a single conflict stops
+	// affecting scheduling decisions after a handful of blocks.
+	DefaultConflictScoreDecay = 0.7
+
+	// DefaultConflictScoreThreshold is the score at which a transaction is
+	// predicted to conflict.
+	DefaultConflictScoreThreshold = 1.0
+
+	// DefaultConflictStatsCapacity bounds the number of registers, owners and
+	// scripts tracked by the scheduler.
+	DefaultConflictStatsCapacity = 10_000
+
+	// minConflictScore is the score below which entries are dropped.
+	minConflictScore = 0.05
+)
+
+// ConflictScheduler learns which registers, register owners (i.e. accounts
+// holding contracts and their state) and transaction scripts caused
+// transaction conflicts in recently executed blocks, and uses these statistics
+// to decide which transactions should not be executed speculatively.
+//
+// A transaction predicted to conflict is serialized: its execution is delayed
+// until all transactions preceding it in the block have been committed, so it
+// executes against an up-to-date snapshot and never needs to be retried.
+// Transactions are still committed in their original order; the scheduler only
+// changes when a transaction starts executing.  Note that we do not reorder
+// the request queue itself, since a worker holding a later transaction blocks
+// until all earlier transactions commit, and with a bounded number of workers
+// reordering could lead to a deadlock.
+//
+// ConflictScheduler is safe for concurrent use and is shared across blocks.
+type ConflictScheduler struct {
+	mu sync.RWMutex
+
+	decay     float64
+	threshold float64
+	capacity  int
+
+	registers map[flow.RegisterID]float64
+	owners    map[string]float64
+	scripts   map[flow.Identifier]float64
+}
+
+// NewConflictScheduler creates a new conflict scheduler.
+func NewConflictScheduler(
+	decay float64,
+	threshold float64,
+	capacity int,
+) *ConflictScheduler {
+	return &ConflictScheduler{
+		decay:     decay,
+		threshold: threshold,
+		capacity:  capacity,
+		registers: make(map[flow.RegisterID]float64),
+		owners:    make(map[string]float64),
+		scripts:   make(map[flow.Identifier]float64),
+	}
+}
+
+// NewDefaultConflictScheduler creates a new conflict scheduler with default
+// parameters.
+func NewDefaultConflictScheduler() *ConflictScheduler {
+	return NewConflictScheduler(
+		DefaultConflictScoreDecay,
+		DefaultConflictScoreThreshold,
+		DefaultConflictStatsCapacity)
+}
+
+// scriptKey returns the key under which conflict statistics for the
+// transaction's script are tracked.
+func scriptKey(txnBody *flow.TransactionBody) flow.Identifier {
+	return flow.HashToID(hash.NewSHA3_256().ComputeHash(txnBody.Script))
+}
+
+// PredictConflict returns true if the transaction is likely to conflict with
+// transactions executed concurrently, based on the conflict history of its
+// script and of the accounts it is signed by.
+func (scheduler *ConflictScheduler) PredictConflict(
+	txnBody *flow.TransactionBody,
+) bool {
+	scheduler.mu.RLock()
+	defer scheduler.mu.RUnlock()
+
+	if scheduler.scripts[scriptKey(txnBody)] >= scheduler.threshold {
+		return true
+	}
+
+	if scheduler.owners[string(txnBody.Payer.Bytes())] >= scheduler.threshold {
+		return true
+	}
+
+	for _, authorizer := range txnBody.Authorizers {
+		if scheduler.owners[string(authorizer.Bytes())] >= scheduler.threshold {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RegisterConflictScore returns the current conflict score of the register.
+func (scheduler *ConflictScheduler) RegisterConflictScore(
+	id flow.RegisterID,
+) float64 {
+	scheduler.mu.RLock()
+	defer scheduler.mu.RUnlock()
+
+	return scheduler.registers[id]
+}
+
+// OwnerConflictScore returns the current conflict score of the register
+// owner (account).
+func (scheduler *ConflictScheduler) OwnerConflictScore(
+	owner flow.Address,
+) float64 {
+	scheduler.mu.RLock()
+	defer scheduler.mu.RUnlock()
+
+	return scheduler.owners[string(owner.Bytes())]
+}
+
+// observedConflict is a transaction which conflicted (or would have conflicted
+// had it not been serialized) on the given registers.
+type observedConflict struct {
+	script    flow.Identifier
+	registers []flow.RegisterID
+}
+
+// observeBlock decays all scores, then updates the conflict statistics with
+// the conflicts observed while executing a single block.
+func (scheduler *ConflictScheduler) observeBlock(conflicts []observedConflict) {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	decayScores(scheduler.registers, scheduler.decay)
+	decayScores(scheduler.owners, scheduler.decay)
+	decayScores(scheduler.scripts, scheduler.decay)
+
+	for _, conflict := range conflicts {
+		scheduler.scripts[conflict.script] += 1
+
+		owners := make(map[string]struct{}, len(conflict.registers))
+		for _, id := range conflict.registers {
+			scheduler.registers[id] += 1
+			owners[id.Owner] = struct{}{}
+		}
+
+		for owner := range owners {
+			scheduler.owners[owner] += 1
+		}
+	}
+
+	trimScores(scheduler.registers, scheduler.capacity)
+	trimScores(scheduler.owners, scheduler.capacity)
+	trimScores(scheduler.scripts, scheduler.capacity)
+}
+
+// decayScores multiplies every score by the decay factor, dropping entries
+// which became insignificant.
+func decayScores[K comparable](scores map[K]float64, decay float64) {
+	for key, score := range scores {
+		score *= decay
+		if score < minConflictScore {
+			delete(scores, key)
+			continue
+		}
+		scores[key] = score
+	}
+}
+
+// trimScores drops the lowest scoring entries until at most capacity entries
+// remain.
+func trimScores[K comparable](scores map[K]float64, capacity int) {
+	if len(scores) <= capacity {
+		return
+	}
+
+	keys := make([]K, 0, len(scores))
+	for key := range scores {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i int, j int) bool {
+		return scores[keys[i]] < scores[keys[j]]
+	})
+
+	for _, key := range keys[:len(keys)-capacity] {
+		delete(scores, key)
+	}
+}
+
+// blockConflictTracker tracks register writes and conflicts while executing
+// a single block.  It wraps the block's result collector so that it observes
+// every transaction in commit order.
+type blockConflictTracker struct {
+	scheduler *ConflictScheduler
+	metrics   module.ExecutionMetrics
+	next      TransactionWriteBehindLogger
+
+	mu sync.Mutex
+
+	// startedAt records the snapshot time at which each transaction was first
+	// picked up for execution, keyed by execution time.
+	startedAt map[logical.Time]logical.Time
+
+	// serialized records which transactions were serialized, keyed by
+	// execution time.
+	serialized map[logical.Time]struct{}
+
+	// writes holds the registers updated by each committed transaction,
+	// indexed by execution time.
+	writes [][]flow.RegisterID
+
+	conflicts      []observedConflict
+	retriesAvoided int
+}
+
+var _ TransactionWriteBehindLogger = (*blockConflictTracker)(nil)
+
+func newBlockConflictTracker(
+	scheduler *ConflictScheduler,
+	metrics module.ExecutionMetrics,
+	next TransactionWriteBehindLogger,
+	numTxns int,
+) *blockConflictTracker {
+	return &blockConflictTracker{
+		scheduler:  scheduler,
+		metrics:    metrics,
+		next:       next,
+		startedAt:  make(map[logical.Time]logical.Time, numTxns),
+		serialized: make(map[logical.Time]struct{}),
+		writes:     make([][]flow.RegisterID, 0, numTxns),
+	}
+}
+
+// start records that the transaction was picked up for execution at the given
+// snapshot time, and returns true if the transaction should be serialized.
+// The system transaction is never serialized since it executes last anyway.
+func (tracker *blockConflictTracker) start(
+	request TransactionRequest,
+	snapshotTime logical.Time,
+) bool {
+	serialize := !request.isSystemTransaction &&
+		snapshotTime < request.ExecutionTime() &&
+		tracker.scheduler.PredictConflict(request.Transaction)
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.startedAt[request.ExecutionTime()] = snapshotTime
+	if serialize {
+		tracker.serialized[request.ExecutionTime()] = struct{}{}
+	}
+
+	return serialize
+}
+
+// AddTransactionResult records the transaction's writes and any conflicts it
+// encountered, then forwards the result.  This is called by the transaction
+// coordinator in commit order.
+func (tracker *blockConflictTracker) AddTransactionResult(
+	request TransactionRequest,
+	executionSnapshot *snapshot.ExecutionSnapshot,
+	output fvm.ProcedureOutput,
+	timeSpent time.Duration,
+	numTxnConflictRetries int,
+) {
+	tracker.observe(request, executionSnapshot, numTxnConflictRetries)
+
+	tracker.next.AddTransactionResult(
+		request,
+		executionSnapshot,
+		output,
+		timeSpent,
+		numTxnConflictRetries)
+}
+
+func (tracker *blockConflictTracker) observe(
+	request TransactionRequest,
+	executionSnapshot *snapshot.ExecutionSnapshot,
+	numTxnConflictRetries int,
+) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	executionTime := request.ExecutionTime()
+	startedAt := tracker.startedAt[executionTime]
+	_, serialized := tracker.serialized[executionTime]
+
+	// The first attempt is counted as a "retry" by the coordinator.
+	retried := numTxnConflictRetries > 1
+
+	if retried || serialized {
+		// Registers read by this transaction which were written by
+		// transactions committed after this transaction started executing.
+		var conflicting []flow.RegisterID
+		for _, writes := range tracker.writes[startedAt:executionTime] {
+			for _, id := range writes {
+				if _, ok := executionSnapshot.ReadSet[id]; ok {
+					conflicting = append(conflicting, id)
+				}
+			}
+		}
+
+		if len(conflicting) > 0 {
+			tracker.conflicts = append(
+				tracker.conflicts,
+				observedConflict{
+					script:    scriptKey(request.Transaction),
+					registers: conflicting,
+				})
+
+			if serialized {
+				tracker.retriesAvoided += 1
+			}
+		}
+	}
+
+	tracker.writes = append(
+		tracker.writes,
+		executionSnapshot.UpdatedRegisterIDs())
+}
+
+// Finish reports the block's conflict statistics to the scheduler and to
+// metrics.
+func (tracker *blockConflictTracker) Finish() {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.scheduler.observeBlock(tracker.conflicts)
+	tracker.metrics.ExecutionBlockConflictScheduling(
+		len(tracker.serialized),
+		tracker.retriesAvoided)
+}
@@ -0,0 +1,200 @@
+package computer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/storage/snapshot"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+type noopWriteBehindLogger struct{}
+
+func (noopWriteBehindLogger) AddTransactionResult(
+	TransactionRequest,
+	*snapshot.ExecutionSnapshot,
+	fvm.ProcedureOutput,
+	time.Duration,
+	int,
+) {
+}
+
+func conflictTestRequest(
+	txnIndex uint32,
+	txnBody *flow.TransactionBody,
+) TransactionRequest {
+	return TransactionRequest{
+		txnIndex: txnIndex,
+		TransactionProcedure: fvm.NewTransaction(
+			txnBody.ID(),
+			txnIndex,
+			txnBody),
+	}
+}
+
+func TestConflictSchedulerLearnsFromRetries(t *testing.T) {
+	scheduler := NewDefaultConflictScheduler()
+
+	payer := unittest.RandomAddressFixture()
+	hot := flow.NewRegisterID(payer, "balance")
+
+	txnBody := &flow.TransactionBody{
+		Script: []byte("transaction {}"),
+		Payer:  payer,
+	}
+	require.False(t, scheduler.PredictConflict(txnBody))
+
+	tracker := newBlockConflictTracker(
+		scheduler,
+		metrics.NewNoopCollector(),
+		noopWriteBehindLogger{},
+		2)
+
+	first := conflictTestRequest(0, txnBody)
+	second := conflictTestRequest(1, txnBody)
+
+	// both transactions started executing against the same snapshot.
+	require.False(t, tracker.start(first, 0))
+	require.False(t, tracker.start(second, 0))
+
+	tracker.AddTransactionResult(
+		first,
+		&snapshot.ExecutionSnapshot{
+			ReadSet:  map[flow.RegisterID]struct{}{hot: {}},
+			WriteSet: map[flow.RegisterID]flow.RegisterValue{hot: []byte("1")},
+		},
+		fvm.ProcedureOutput{},
+		0,
+		1)
+
+	// the second transaction was retried after reading the register written
+	// by the first transaction.
+	tracker.AddTransactionResult(
+		second,
+		&snapshot.ExecutionSnapshot{
+			ReadSet:  map[flow.RegisterID]struct{}{hot: {}},
+			WriteSet: map[flow.RegisterID]flow.RegisterValue{hot: []byte("2")},
+		},
+		fvm.ProcedureOutput{},
+		0,
+		2)
+
+	tracker.Finish()
+
+	require.Greater(t, scheduler.RegisterConflictScore(hot), 0.0)
+	require.Greater(t, scheduler.OwnerConflictScore(payer), 0.0)
+
+	// the same script from an unrelated payer is predicted to conflict since
+	// the script itself conflicted.
+	require.True(t, scheduler.PredictConflict(txnBody))
+
+	// a different script from an unrelated payer is not.
+	require.False(t, scheduler.PredictConflict(&flow.TransactionBody{
+		Script: []byte("transaction { execute {} }"),
+		Payer:  unittest.RandomAddressFixture(),
+	}))
+}
+
+func TestConflictSchedulerSerializesPredictedConflicts(t *testing.T) {
+	scheduler := NewDefaultConflictScheduler()
+
+	payer := unittest.RandomAddressFixture()
+	hot := flow.NewRegisterID(payer, "balance")
+
+	// seed the scheduler with a conflict on the payer's account.
+	scheduler.observeBlock([]observedConflict{
+		{registers: []flow.RegisterID{hot}},
+	})
+
+	txnBody := &flow.TransactionBody{
+		Script: []byte("transaction {}"),
+		Payer:  payer,
+	}
+	require.True(t, scheduler.PredictConflict(txnBody))
+
+	tracker := newBlockConflictTracker(
+		scheduler,
+		metrics.NewNoopCollector(),
+		noopWriteBehindLogger{},
+		2)
+
+	first := conflictTestRequest(0, txnBody)
+	second := conflictTestRequest(1, txnBody)
+
+	// the first transaction has no preceding transactions to wait for.
+	require.False(t, tracker.start(first, 0))
+	require.True(t, tracker.start(second, 0))
+
+	tracker.AddTransactionResult(
+		first,
+		&snapshot.ExecutionSnapshot{
+			WriteSet: map[flow.RegisterID]flow.RegisterValue{hot: []byte("1")},
+		},
+		fvm.ProcedureOutput{},
+		0,
+		1)
+	tracker.AddTransactionResult(
+		second,
+		&snapshot.ExecutionSnapshot{
+			ReadSet: map[flow.RegisterID]struct{}{hot: {}},
+		},
+		fvm.ProcedureOutput{},
+		0,
+		1)
+
+	require.Equal(t, 1, tracker.retriesAvoided)
+	require.Len(t, tracker.serialized, 1)
+
+	// the avoided conflict keeps the register hot.
+	tracker.Finish()
+	require.True(t, scheduler.PredictConflict(txnBody))
+}
+
+func TestConflictSchedulerDecay(t *testing.T) {
+	scheduler := NewConflictScheduler(0.5, 1.0, 2)
+
+	owner := unittest.RandomAddressFixture()
+	id := flow.NewRegisterID(owner, "key")
+
+	scheduler.observeBlock([]observedConflict{
+		{registers: []flow.RegisterID{id}},
+		{registers: []flow.RegisterID{id}},
+	})
+	require.Equal(t, 2.0, scheduler.RegisterConflictScore(id))
+
+	scheduler.observeBlock(nil)
+	require.Equal(t, 1.0, scheduler.RegisterConflictScore(id))
+
+	for i := 0; i < 10; i++ {
+		scheduler.observeBlock(nil)
+	}
+	require.Equal(t, 0.0, scheduler.RegisterConflictScore(id))
+	require.Empty(t, scheduler.registers)
+
+	// capacity is enforced by dropping the lowest scores.
+	conflicts := make([]observedConflict, 0, 10)
+	for i := 0; i < 10; i++ {
+		for j := 0; j <= i; j++ {
+			conflicts = append(conflicts, observedConflict{
+				registers: []flow.RegisterID{
+					flow.NewRegisterID(owner, string(rune('a'+i))),
+				},
+			})
+		}
+	}
+	scheduler.observeBlock(conflicts)
+	require.Len(t, scheduler.registers, 2)
+	require.Equal(
+		t,
+		10.0,
+		scheduler.RegisterConflictScore(flow.NewRegisterID(owner, "j")))
+	require.Equal(
+		t,
+		9.0,
+		scheduler.RegisterConflictScore(flow.NewRegisterID(owner, "i")))
+}
@@ -11,6 +11,7 @@ import (
 	otelTrace "go.opentelemetry.io/otel/trace"
 
 	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/engine/execution/computation/profiler"
 	"github.com/onflow/flow-go/engine/execution/computation/result"
 	"github.com/onflow/flow-go/engine/execution/storehouse"
 	"github.com/onflow/flow-go/fvm"
@@ -85,6 +86,9 @@ type resultCollector struct {
 	currentCollectionState           *state.ExecutionState
 	currentCollectionStats           module.CollectionExecutionResultStats
 	currentCollectionStorageSnapshot execution.ExtendableStorageSnapshot
+
+	// profile is nil unless execution profiling is enabled for this block.
+	profile *profiler.BlockProfile
 }
 
 func newResultCollector(
@@ -102,6 +106,7 @@ func newResultCollector(
 	consumers []result.ExecutedCollectionConsumer,
 	previousBlockSnapshot snapshot.StorageSnapshot,
 	versionAwareChunkConstructor flow.ChunkConstructor,
+	profile *profiler.BlockProfile,
 ) *resultCollector {
 	numCollections := len(block.Collections()) + 1
 	now := time.Now()
@@ -129,6 +134,7 @@ func newResultCollector(
 			previousBlockSnapshot,
 			*block.StartState,
 		),
+		profile: profile,
 	}
 
 	go collector.runResultProcessor()
@@ -265,6 +271,15 @@ func (collector *resultCollector) processTransactionResult(
 		numConflictRetries,
 	)
 
+	if collector.profile != nil {
+		collector.profile.AddTransaction(newTransactionProfile(
+			txn,
+			txnExecutionSnapshot,
+			output,
+			timeSpent,
+			numConflictRetries))
+	}
+
 	txnResult := flow.TransactionResult{
 		TransactionID:   txn.ID,
 		ComputationUsed: output.ComputationUsed,
@@ -335,6 +350,47 @@ func (collector *resultCollector) handleTransactionExecutionMetrics(
 	collector.currentCollectionStats.Add(transactionExecutionStats)
 }
 
+func newTransactionProfile(
+	txn TransactionRequest,
+	txnExecutionSnapshot *snapshot.ExecutionSnapshot,
+	output fvm.ProcedureOutput,
+	timeSpent time.Duration,
+	numConflictRetries int,
+) profiler.TransactionProfile {
+	txnProfile := profiler.TransactionProfile{
+		TransactionID:          txn.ID,
+		TransactionIndex:       txn.txnIndex,
+		CollectionIndex:        txn.collectionIndex,
+		SystemTx:               txn.isSystemTransaction,
+		Failed:                 output.Err != nil,
+		ConflictRetries:        numConflictRetries,
+		WallTime:               timeSpent,
+		ComputationUsed:        output.ComputationUsed,
+		ComputationIntensities: make(map[string]uint, len(output.ComputationIntensities)),
+		MemoryEstimate:         output.MemoryEstimate,
+		RegisterReads:          len(txnExecutionSnapshot.ReadSet),
+		RegisterWrites:         len(txnExecutionSnapshot.WriteSet),
+		ContractFunctions:      output.ContractFunctionInvocations,
+	}
+
+	for kind, intensity := range output.ComputationIntensities {
+		txnProfile.ComputationIntensities[kind.String()] = intensity
+	}
+
+	if txnExecutionSnapshot.Meter != nil {
+		txnProfile.RegisterReadBytes = txnExecutionSnapshot.TotalBytesReadFromStorage()
+		txnProfile.RegisterWriteBytes = txnExecutionSnapshot.TotalBytesWrittenToStorage()
+
+		memoryIntensities := txnExecutionSnapshot.MemoryIntensities()
+		txnProfile.MemoryIntensities = make(map[string]uint, len(memoryIntensities))
+		for kind, intensity := range memoryIntensities {
+			txnProfile.MemoryIntensities[kind.String()] = intensity
+		}
+	}
+
+	return txnProfile
+}
+
 func (collector *resultCollector) AddTransactionResult(
 	request TransactionRequest,
 	snapshot *snapshot.ExecutionSnapshot,
@@ -185,6 +185,22 @@ func (coordinator *transactionCoordinator) waitForUpdatesNewerThan(
 	return startTime, startErr, coordinator.snapshotTime, coordinator.abortErr
 }
 
+// waitForSnapshotTime blocks until the database's snapshot time reaches the
+// given time, i.e. until all transactions preceding a transaction with the
+// given execution time have been committed.
+func (coordinator *transactionCoordinator) waitForSnapshotTime(
+	snapshotTime logical.Time,
+) error {
+	coordinator.mutex.Lock()
+	defer coordinator.mutex.Unlock()
+
+	for coordinator.snapshotTime < snapshotTime && coordinator.abortErr == nil {
+		coordinator.cond.Wait()
+	}
+
+	return coordinator.abortErr
+}
+
 func (txn *transaction) WaitForUpdates() error {
 	// Note: the frist three returned values are only used by tests to ensure
 	// the function correctly waited.
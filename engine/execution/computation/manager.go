@@ -9,6 +9,7 @@ import (
 
 	"github.com/onflow/flow-go/engine/execution"
 	"github.com/onflow/flow-go/engine/execution/computation/computer"
+	"github.com/onflow/flow-go/engine/execution/computation/profiler"
 	"github.com/onflow/flow-go/engine/execution/computation/query"
 	"github.com/onflow/flow-go/fvm"
 	reusableRuntime "github.com/onflow/flow-go/fvm/runtime"
@@ -67,6 +68,15 @@ type ComputationConfig struct {
 	DerivedDataCacheSize uint
 	MaxConcurrency       int
 
+	// ConflictAwareScheduling enables serializing transactions which are
+	// predicted to conflict, based on conflicts observed in recent blocks.
+	// Only effective when MaxConcurrency is greater than 1.
+	ConflictAwareScheduling bool
+
+	// When Profiler is not nil, per-transaction execution profiles are
+	// recorded for the blocks selected by the profiler.
+	Profiler *profiler.Profiler
+
 	// When NewCustomVirtualMachine is nil, the manager will create a standard
 	// fvm virtual machine via fvm.NewVirtualMachine.  Otherwise, the manager
 	// will create a virtual machine using this function.
@@ -110,6 +120,16 @@ func New(
 	options := DefaultFVMOptions(chainID, params.CadenceTracing, params.ExtensiveTracing)
 	vmCtx = fvm.NewContextFromParent(vmCtx, options...)
 
+	var computerOpts []computer.BlockComputerOption
+	if params.ConflictAwareScheduling {
+		computerOpts = append(
+			computerOpts,
+			computer.WithConflictScheduler(computer.NewDefaultConflictScheduler()))
+	}
+	if params.Profiler != nil {
+		computerOpts = append(computerOpts, computer.WithProfiler(params.Profiler))
+	}
+
 	blockComputer, err := computer.NewBlockComputer(
 		vm,
 		vmCtx,
@@ -122,6 +142,7 @@ func New(
 		nil, // TODO(ramtin): update me with proper consumers
 		protoState,
 		params.MaxConcurrency,
+		computerOpts...,
 	)
 
 	if err != nil {
@@ -109,6 +109,7 @@ func BenchmarkComputeBlock(b *testing.B) {
 		numCollections               int
 		numTransactionsPerCollection int
 		maxConcurrency               int
+		conflictAwareScheduling      bool
 	}
 
 	for _, benchCase := range []benchmarkCase{
@@ -122,19 +123,29 @@ func BenchmarkComputeBlock(b *testing.B) {
 			numTransactionsPerCollection: 128,
 			maxConcurrency:               2,
 		},
+		{
+			// all transactions share the same payer, hence they all conflict
+			// on the payer's FlowToken vault.
+			numCollections:               16,
+			numTransactionsPerCollection: 128,
+			maxConcurrency:               2,
+			conflictAwareScheduling:      true,
+		},
 	} {
 		b.Run(
 			fmt.Sprintf(
-				"%d/cols/%d/txes/%d/max-concurrency",
+				"%d/cols/%d/txes/%d/max-concurrency/%t/conflict-aware",
 				benchCase.numCollections,
 				benchCase.numTransactionsPerCollection,
-				benchCase.maxConcurrency),
+				benchCase.maxConcurrency,
+				benchCase.conflictAwareScheduling),
 			func(b *testing.B) {
 				benchmarkComputeBlock(
 					b,
 					benchCase.numCollections,
 					benchCase.numTransactionsPerCollection,
-					benchCase.maxConcurrency)
+					benchCase.maxConcurrency,
+					benchCase.conflictAwareScheduling)
 			})
 	}
 }
@@ -144,6 +155,7 @@ func benchmarkComputeBlock(
 	numCollections int,
 	numTransactionsPerCollection int,
 	maxConcurrency int,
+	conflictAwareScheduling bool,
 ) {
 	tracer, err := trace.NewTracer(zerolog.Nop(), "", "", 4)
 	require.NoError(b, err)
@@ -190,6 +202,13 @@ func benchmarkComputeBlock(
 		trackerStorage,
 	)
 
+	var computerOpts []computer.BlockComputerOption
+	if conflictAwareScheduling {
+		computerOpts = append(
+			computerOpts,
+			computer.WithConflictScheduler(computer.NewDefaultConflictScheduler()))
+	}
+
 	// TODO(rbtz): add real ledger
 	blockComputer, err := computer.NewBlockComputer(
 		vm,
@@ -202,7 +221,8 @@ func benchmarkComputeBlock(
 		prov,
 		nil,
 		testutil.ProtocolStateWithSourceFixture(nil),
-		maxConcurrency)
+		maxConcurrency,
+		computerOpts...)
 	require.NoError(b, err)
 
 	derivedChainData, err := derived.NewDerivedChainData(
@@ -0,0 +1,189 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/google/pprof/profile"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// TransactionProfile holds the resources consumed while executing a single
+// transaction.
+type TransactionProfile struct {
+	TransactionID    flow.Identifier `json:"transaction_id"`
+	TransactionIndex uint32          `json:"transaction_index"`
+	CollectionIndex  int             `json:"collection_index"`
+	SystemTx         bool            `json:"system_transaction"`
+	Failed           bool            `json:"failed"`
+	ConflictRetries  int             `json:"conflict_retries"`
+	WallTime         time.Duration   `json:"wall_time_ns"`
+
+	ComputationUsed        uint64          `json:"computation_used"`
+	ComputationIntensities map[string]uint `json:"computation_intensities"`
+	MemoryEstimate         uint64          `json:"memory_estimate"`
+	MemoryIntensities      map[string]uint `json:"memory_intensities"`
+
+	// Register byte counts include both key and value sizes, as metered by
+	// the FVM.
+	RegisterReads      int    `json:"register_reads"`
+	RegisterReadBytes  uint64 `json:"register_read_bytes"`
+	RegisterWrites     int    `json:"register_writes"`
+	RegisterWriteBytes uint64 `json:"register_write_bytes"`
+
+	// ContractFunctions counts the Cadence functions invoked by the
+	// transaction.
+	ContractFunctions map[string]uint `json:"contract_functions,omitempty"`
+}
+
+// BlockProfile holds the transaction profiles of a single executed block.
+//
+// BlockProfile is not safe for concurrent use; transactions are expected to be
+// added by the block's result collector, in execution order.
+type BlockProfile struct {
+	BlockID      flow.Identifier      `json:"block_id"`
+	Height       uint64               `json:"height"`
+	StartedAt    time.Time            `json:"started_at"`
+	WallTime     time.Duration        `json:"wall_time_ns"`
+	Transactions []TransactionProfile `json:"transactions"`
+}
+
+// NewBlockProfile creates an empty profile for the given block.
+func NewBlockProfile(blockID flow.Identifier, height uint64) *BlockProfile {
+	return &BlockProfile{
+		BlockID:   blockID,
+		Height:    height,
+		StartedAt: time.Now(),
+	}
+}
+
+// AddTransaction appends a transaction profile.
+func (p *BlockProfile) AddTransaction(txn TransactionProfile) {
+	p.Transactions = append(p.Transactions, txn)
+}
+
+// Finish records the block's total wall time.
+func (p *BlockProfile) Finish() {
+	p.WallTime = time.Since(p.StartedAt)
+}
+
+// sampleTypes are the pprof sample types written by WritePprof, in order.
+var sampleTypes = []*profile.ValueType{
+	{Type: "wall", Unit: "nanoseconds"},
+	{Type: "computation", Unit: "count"},
+	{Type: "memory", Unit: "bytes"},
+}
+
+// WritePprof writes the profile in the (gzip compressed) pprof protobuf
+// format, so it can be inspected with `go tool pprof` and rendered as a flame
+// graph.
+//
+// Each transaction is represented as a stack of
+// block -> collection -> transaction, with one child frame per computation
+// kind.  Wall time and memory are attributed to the transaction frame, and
+// computation intensities to the computation kind frames.
+func (p *BlockProfile) WritePprof(w io.Writer) error {
+	builder := newPprofBuilder()
+
+	blockFrame := builder.location(fmt.Sprintf("block %d %s", p.Height, p.BlockID))
+
+	for _, txn := range p.Transactions {
+		collectionName := fmt.Sprintf("collection %d", txn.CollectionIndex)
+		if txn.SystemTx {
+			collectionName = "system collection"
+		}
+		collectionFrame := builder.location(collectionName)
+		txnFrame := builder.location(
+			fmt.Sprintf("tx %d %s", txn.TransactionIndex, txn.TransactionID))
+
+		builder.sample(
+			[]*profile.Location{txnFrame, collectionFrame, blockFrame},
+			int64(txn.WallTime),
+			0,
+			int64(txn.MemoryEstimate))
+
+		for _, kind := range sortedKeys(txn.ComputationIntensities) {
+			builder.sample(
+				[]*profile.Location{
+					builder.location(kind),
+					txnFrame,
+					collectionFrame,
+					blockFrame,
+				},
+				0,
+				int64(txn.ComputationIntensities[kind]),
+				0)
+		}
+	}
+
+	prof := builder.build(p.StartedAt, p.WallTime)
+	err := prof.CheckValid()
+	if err != nil {
+		return fmt.Errorf("invalid pprof profile: %w", err)
+	}
+
+	return prof.Write(w)
+}
+
+// pprofBuilder deduplicates functions and locations by name.
+type pprofBuilder struct {
+	locations map[string]*profile.Location
+	prof      *profile.Profile
+}
+
+func newPprofBuilder() *pprofBuilder {
+	return &pprofBuilder{
+		locations: make(map[string]*profile.Location),
+		prof: &profile.Profile{
+			SampleType: sampleTypes,
+		},
+	}
+}
+
+func (b *pprofBuilder) location(name string) *profile.Location {
+	loc, ok := b.locations[name]
+	if ok {
+		return loc
+	}
+
+	fn := &profile.Function{
+		ID:         uint64(len(b.prof.Function) + 1),
+		Name:       name,
+		SystemName: name,
+	}
+	b.prof.Function = append(b.prof.Function, fn)
+
+	loc = &profile.Location{
+		ID:   uint64(len(b.prof.Location) + 1),
+		Line: []profile.Line{{Function: fn}},
+	}
+	b.prof.Location = append(b.prof.Location, loc)
+	b.locations[name] = loc
+
+	return loc
+}
+
+func (b *pprofBuilder) sample(stack []*profile.Location, values ...int64) {
+	b.prof.Sample = append(b.prof.Sample, &profile.Sample{
+		Location: stack,
+		Value:    values,
+	})
+}
+
+func (b *pprofBuilder) build(startedAt time.Time, duration time.Duration) *profile.Profile {
+	b.prof.TimeNanos = startedAt.UnixNano()
+	b.prof.DurationNanos = int64(duration)
+	return b.prof
+}
+
+func sortedKeys(m map[string]uint) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
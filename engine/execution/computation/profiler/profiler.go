@@ -0,0 +1,183 @@
+package profiler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+)
+
+// DefaultQueueSize is the number of block profiles which can be waiting to be
+// written to disk.  Profiles are dropped when the queue is full, so that
+// profiling never blocks block execution.
+const DefaultQueueSize = 100
+
+// HeightRange is an inclusive range of block heights.
+type HeightRange struct {
+	Start uint64 `json:"start_height"`
+	End   uint64 `json:"end_height"`
+}
+
+// Contains returns true if the height is within the range.
+func (r HeightRange) Contains(height uint64) bool {
+	return height >= r.Start && height <= r.End
+}
+
+// Profiler records per-transaction execution profiles for blocks within a
+// configurable height range, and writes one JSON and one pprof file per
+// executed block to the output directory.
+//
+// Profiling is disabled until a height range is set, and profiling a block
+// which is executed more than once (e.g. on different forks) produces one set
+// of files per block ID.
+type Profiler struct {
+	component.Component
+
+	log zerolog.Logger
+	dir string
+
+	mu          sync.RWMutex
+	heightRange *HeightRange
+
+	profiles chan *BlockProfile
+}
+
+// New creates a new profiler writing to the given directory.
+// No errors are expected during normal operation.
+func New(log zerolog.Logger, dir string) (*Profiler, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("could not create profile dir %v: %w", dir, err)
+	}
+
+	p := &Profiler{
+		log:      log.With().Str("component", "execution_profiler").Logger(),
+		dir:      dir,
+		profiles: make(chan *BlockProfile, DefaultQueueSize),
+	}
+
+	p.Component = component.NewComponentManagerBuilder().
+		AddWorker(p.writeWorker).
+		Build()
+
+	return p, nil
+}
+
+// SetHeightRange enables profiling for all blocks within the given range.
+// Passing nil disables profiling.
+func (p *Profiler) SetHeightRange(heightRange *HeightRange) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.heightRange = heightRange
+}
+
+// HeightRange returns the current height range, or nil if profiling is
+// disabled.
+func (p *Profiler) HeightRange() *HeightRange {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.heightRange == nil {
+		return nil
+	}
+	heightRange := *p.heightRange
+	return &heightRange
+}
+
+// StartBlock returns a new profile for the given block if profiling is
+// enabled at its height, or nil otherwise.
+func (p *Profiler) StartBlock(blockID flow.Identifier, height uint64) *BlockProfile {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.heightRange == nil || !p.heightRange.Contains(height) {
+		return nil
+	}
+
+	return NewBlockProfile(blockID, height)
+}
+
+// FinishBlock completes the profile and queues it to be written to disk.
+// This never blocks; the profile is dropped if the write queue is full.
+func (p *Profiler) FinishBlock(profile *BlockProfile) {
+	profile.Finish()
+
+	select {
+	case p.profiles <- profile:
+	default:
+		p.log.Warn().
+			Uint64("height", profile.Height).
+			Hex("block_id", profile.BlockID[:]).
+			Msg("dropping execution profile because the write queue is full")
+	}
+}
+
+func (p *Profiler) writeWorker(
+	ctx irrecoverable.SignalerContext,
+	ready component.ReadyFunc,
+) {
+	ready()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case profile := <-p.profiles:
+			err := p.write(profile)
+			if err != nil {
+				p.log.Error().
+					Err(err).
+					Uint64("height", profile.Height).
+					Hex("block_id", profile.BlockID[:]).
+					Msg("failed to write execution profile")
+				continue
+			}
+
+			p.log.Info().
+				Uint64("height", profile.Height).
+				Hex("block_id", profile.BlockID[:]).
+				Int("transactions", len(profile.Transactions)).
+				Msg("execution profile written")
+		}
+	}
+}
+
+// write writes the JSON and pprof encodings of the profile.
+// No errors are expected during normal operation.
+func (p *Profiler) write(profile *BlockProfile) error {
+	base := filepath.Join(p.dir, fmt.Sprintf("%d-%s", profile.Height, profile.BlockID))
+
+	jsonFile, err := os.Create(base + ".json")
+	if err != nil {
+		return fmt.Errorf("could not create json profile file: %w", err)
+	}
+	defer jsonFile.Close()
+
+	encoder := json.NewEncoder(jsonFile)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(profile)
+	if err != nil {
+		return fmt.Errorf("could not write json profile: %w", err)
+	}
+
+	pprofFile, err := os.Create(base + ".pb.gz")
+	if err != nil {
+		return fmt.Errorf("could not create pprof profile file: %w", err)
+	}
+	defer pprofFile.Close()
+
+	err = profile.WritePprof(pprofFile)
+	if err != nil {
+		return fmt.Errorf("could not write pprof profile: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package profiler
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/pprof/profile"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestProfilerHeightRange(t *testing.T) {
+	p, err := New(zerolog.Nop(), t.TempDir())
+	require.NoError(t, err)
+
+	blockID := unittest.IdentifierFixture()
+
+	// disabled by default
+	require.Nil(t, p.HeightRange())
+	require.Nil(t, p.StartBlock(blockID, 10))
+
+	p.SetHeightRange(&HeightRange{Start: 10, End: 12})
+	require.Equal(t, &HeightRange{Start: 10, End: 12}, p.HeightRange())
+
+	require.Nil(t, p.StartBlock(blockID, 9))
+	require.NotNil(t, p.StartBlock(blockID, 10))
+	require.NotNil(t, p.StartBlock(blockID, 12))
+	require.Nil(t, p.StartBlock(blockID, 13))
+
+	p.SetHeightRange(nil)
+	require.Nil(t, p.StartBlock(blockID, 10))
+}
+
+func TestProfilerWritesProfiles(t *testing.T) {
+	dir := t.TempDir()
+	p, err := New(zerolog.Nop(), dir)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.Start(irrecoverable.NewMockSignalerContext(t, ctx))
+	unittest.RequireCloseBefore(t, p.Ready(), time.Second, "profiler not ready")
+
+	p.SetHeightRange(&HeightRange{Start: 1, End: 1})
+
+	blockID := unittest.IdentifierFixture()
+	blockProfile := p.StartBlock(blockID, 1)
+	require.NotNil(t, blockProfile)
+
+	txn := TransactionProfile{
+		TransactionID:    unittest.IdentifierFixture(),
+		TransactionIndex: 0,
+		CollectionIndex:  0,
+		WallTime:         time.Millisecond,
+		ComputationUsed:  10,
+		ComputationIntensities: map[string]uint{
+			"Statement": 7,
+			"Loop":      3,
+		},
+		MemoryEstimate:     1024,
+		RegisterReads:      2,
+		RegisterReadBytes:  100,
+		RegisterWrites:     1,
+		RegisterWriteBytes: 50,
+	}
+	blockProfile.AddTransaction(txn)
+	blockProfile.AddTransaction(TransactionProfile{
+		TransactionID:    unittest.IdentifierFixture(),
+		TransactionIndex: 1,
+		CollectionIndex:  1,
+		SystemTx:         true,
+		WallTime:         time.Millisecond,
+	})
+	p.FinishBlock(blockProfile)
+
+	base := filepath.Join(dir, "1-"+blockID.String())
+
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(base + ".pb.gz")
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	data, err := os.ReadFile(base + ".json")
+	require.NoError(t, err)
+
+	var decoded BlockProfile
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, blockID, decoded.BlockID)
+	require.Len(t, decoded.Transactions, 2)
+	require.Equal(t, txn, decoded.Transactions[0])
+
+	pprofFile, err := os.Open(base + ".pb.gz")
+	require.NoError(t, err)
+	defer pprofFile.Close()
+
+	prof, err := profile.Parse(pprofFile)
+	require.NoError(t, err)
+	require.Len(t, prof.SampleType, 3)
+
+	// one sample per transaction, plus one per computation kind.
+	require.Len(t, prof.Sample, 4)
+
+	var computation int64
+	for _, sample := range prof.Sample {
+		computation += sample.Value[1]
+	}
+	require.Equal(t, int64(10), computation)
+
+	cancel()
+	unittest.RequireCloseBefore(t, p.Done(), time.Second, "profiler not done")
+}
@@ -0,0 +1,432 @@
+package uploader
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Log file format
+//
+// A log is a directory of segment files named `<base offset>.log`, where the base
+// offset is the offset of the first record of the segment, zero padded to 20 digits.
+// Records are appended to the last segment and a new segment is started once the
+// last one exceeds the maximum segment size. Each record is laid out as:
+//
+//	offset       uint64, big endian, increases by one per record
+//	length       uint32, big endian, length of the body
+//	checksum     uint32, big endian, CRC-32C of the body
+//	body:
+//	  block ID     32 bytes
+//	  height       uint64, big endian
+//	  type length  uint8
+//	  output type  type length bytes
+//	  data         CBOR encoded output
+//
+// Records are never modified once written, so consumers can tail the log while it
+// is written, see LogReader. A partially written record at the end of the last
+// segment is truncated when the log is reopened for writing.
+
+const (
+	logSegmentSuffix = ".log"
+	// DefaultLogMaxSegmentBytes is the default size after which a new segment is started.
+	DefaultLogMaxSegmentBytes = 256 * 1024 * 1024
+
+	logRecordHeaderSize = 8 + 4 + 4
+	logBodyHeaderSize   = flow.IdentifierLen + 8 + 1
+	// logMaxBodySize bounds the body length accepted by readers, to detect corrupted headers.
+	logMaxBodySize = 1 << 30
+)
+
+var logChecksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+// errLogIncomplete is returned when the end of a segment contains a partial record.
+var errLogIncomplete = errors.New("incomplete log record")
+
+// LogRecord is a record of an append-only log.
+type LogRecord struct {
+	Offset     uint64
+	BlockID    flow.Identifier
+	Height     uint64
+	OutputType OutputType
+	Data       []byte
+}
+
+var _ Sink = (*LogSink)(nil)
+
+// LogSink is a Sink which appends outputs to a Kafka-style append-only log, which
+// downstream consumers can tail using a LogReader.
+type LogSink struct {
+	mu              sync.Mutex
+	dir             string
+	maxSegmentBytes int64
+	segment         *os.File
+	segmentSize     int64
+	nextOffset      uint64
+}
+
+// NewLogSink opens the log in the given directory for appending, creating it if it
+// does not exist. A partially written record at the end of the log is truncated.
+// No errors are expected during normal operation.
+func NewLogSink(dir string, maxSegmentBytes int64) (*LogSink, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("could not create log directory: %w", err)
+	}
+
+	sink := &LogSink{
+		dir:             dir,
+		maxSegmentBytes: maxSegmentBytes,
+	}
+
+	bases, err := logSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(bases) == 0 {
+		err = sink.openSegment(0)
+		if err != nil {
+			return nil, err
+		}
+		return sink, nil
+	}
+
+	// recover the next offset and the end of the last complete record
+	base := bases[len(bases)-1]
+	file, err := os.OpenFile(logSegmentPath(dir, base), os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open log segment: %w", err)
+	}
+
+	nextOffset, end := base, int64(0)
+	for {
+		record, size, err := readLogRecord(file, end)
+		if errors.Is(err, io.EOF) || errors.Is(err, errLogIncomplete) {
+			break
+		}
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("could not recover log segment %d: %w", base, err)
+		}
+		nextOffset = record.Offset + 1
+		end += size
+	}
+
+	err = file.Truncate(end)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("could not truncate partial log record: %w", err)
+	}
+	_, err = file.Seek(end, io.SeekStart)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("could not seek to end of log segment: %w", err)
+	}
+
+	sink.segment = file
+	sink.segmentSize = end
+	sink.nextOffset = nextOffset
+	return sink, nil
+}
+
+// Write appends a record with the output to the log.
+// All errors returned from this function can be considered benign.
+func (s *LogSink) Write(blockID flow.Identifier, height uint64, outputType OutputType, data []byte) error {
+	if len(outputType) > 255 {
+		return fmt.Errorf("output type too long: %s", outputType)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.segmentSize > 0 && s.segmentSize >= s.maxSegmentBytes {
+		err := s.segment.Close()
+		if err != nil {
+			return fmt.Errorf("could not close log segment: %w", err)
+		}
+		err = s.openSegment(s.nextOffset)
+		if err != nil {
+			return err
+		}
+	}
+
+	record := encodeLogRecord(&LogRecord{
+		Offset:     s.nextOffset,
+		BlockID:    blockID,
+		Height:     height,
+		OutputType: outputType,
+		Data:       data,
+	})
+
+	n, err := s.segment.Write(record)
+	if err != nil {
+		// drop the partially written record, so the next write starts on a record boundary
+		_ = s.segment.Truncate(s.segmentSize)
+		_, _ = s.segment.Seek(s.segmentSize, io.SeekStart)
+		return fmt.Errorf("could not append log record: %w", err)
+	}
+
+	err = s.segment.Sync()
+	if err != nil {
+		return fmt.Errorf("could not sync log segment: %w", err)
+	}
+
+	s.segmentSize += int64(n)
+	s.nextOffset++
+	return nil
+}
+
+// NextOffset returns the offset of the next record written to the log.
+func (s *LogSink) NextOffset() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.nextOffset
+}
+
+// Close closes the current segment.
+func (s *LogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.segment.Close()
+}
+
+func (s *LogSink) openSegment(base uint64) error {
+	file, err := os.OpenFile(logSegmentPath(s.dir, base), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("could not create log segment: %w", err)
+	}
+
+	s.segment = file
+	s.segmentSize = 0
+	s.nextOffset = base
+	return nil
+}
+
+// LogReader reads the records of an append-only log written by a LogSink. Reading
+// an incomplete or missing record returns io.EOF without advancing the reader, so
+// consumers can tail the log by retrying Next after io.EOF.
+//
+// LogReader is not safe for concurrent use.
+type LogReader struct {
+	dir        string
+	segment    *os.File
+	base       uint64
+	position   int64
+	nextOffset uint64
+}
+
+// NewLogReader creates a reader for the log in the given directory, which returns
+// records starting at the given offset.
+// No errors are expected during normal operation.
+func NewLogReader(dir string, offset uint64) (*LogReader, error) {
+	bases, err := logSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// find the last segment starting at or before the offset
+	base := uint64(0)
+	for _, b := range bases {
+		if b > offset {
+			break
+		}
+		base = b
+	}
+
+	reader := &LogReader{
+		dir:        dir,
+		base:       base,
+		nextOffset: base,
+	}
+
+	// skip the records before the offset
+	for reader.nextOffset < offset {
+		_, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			_ = reader.Close()
+			return nil, err
+		}
+	}
+
+	return reader, nil
+}
+
+// Next returns the next record of the log. It returns io.EOF if the next record has
+// not been completely written yet.
+// No other errors are expected during normal operation.
+func (r *LogReader) Next() (*LogRecord, error) {
+	for {
+		if r.segment == nil {
+			file, err := os.Open(logSegmentPath(r.dir, r.base))
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, io.EOF
+			}
+			if err != nil {
+				return nil, fmt.Errorf("could not open log segment: %w", err)
+			}
+			r.segment = file
+		}
+
+		record, size, err := readLogRecord(r.segment, r.position)
+		if err == nil {
+			if record.Offset != r.nextOffset {
+				return nil, fmt.Errorf("unexpected log record offset %d, expected %d", record.Offset, r.nextOffset)
+			}
+			r.position += size
+			r.nextOffset++
+			return record, nil
+		}
+		if !errors.Is(err, io.EOF) && !errors.Is(err, errLogIncomplete) {
+			return nil, fmt.Errorf("could not read log record %d: %w", r.nextOffset, err)
+		}
+
+		// the segment has no complete record left, continue with the next segment
+		// once the writer has started it
+		_, statErr := os.Stat(logSegmentPath(r.dir, r.nextOffset))
+		if r.nextOffset == r.base || statErr != nil {
+			return nil, io.EOF
+		}
+
+		err = r.segment.Close()
+		if err != nil {
+			return nil, fmt.Errorf("could not close log segment: %w", err)
+		}
+		r.segment = nil
+		r.base = r.nextOffset
+		r.position = 0
+	}
+}
+
+// NextOffset returns the offset of the next record returned by Next.
+func (r *LogReader) NextOffset() uint64 {
+	return r.nextOffset
+}
+
+// Close closes the reader.
+func (r *LogReader) Close() error {
+	if r.segment == nil {
+		return nil
+	}
+	err := r.segment.Close()
+	r.segment = nil
+	return err
+}
+
+func encodeLogRecord(record *LogRecord) []byte {
+	bodySize := logBodyHeaderSize + len(record.OutputType) + len(record.Data)
+	buf := make([]byte, logRecordHeaderSize+bodySize)
+
+	body := buf[logRecordHeaderSize:]
+	copy(body, record.BlockID[:])
+	binary.BigEndian.PutUint64(body[flow.IdentifierLen:], record.Height)
+	body[flow.IdentifierLen+8] = byte(len(record.OutputType))
+	copy(body[logBodyHeaderSize:], record.OutputType)
+	copy(body[logBodyHeaderSize+len(record.OutputType):], record.Data)
+
+	binary.BigEndian.PutUint64(buf, record.Offset)
+	binary.BigEndian.PutUint32(buf[8:], uint32(bodySize))
+	binary.BigEndian.PutUint32(buf[12:], crc32.Checksum(body, logChecksumTable))
+
+	return buf
+}
+
+// readLogRecord reads the record at the given position of the segment and returns
+// it together with its encoded size.
+// Expected errors:
+//   - io.EOF if there is no record at the position
+//   - errLogIncomplete if the record at the position is only partially written
+func readLogRecord(segment io.ReaderAt, position int64) (*LogRecord, int64, error) {
+	header := make([]byte, logRecordHeaderSize)
+	n, err := segment.ReadAt(header, position)
+	if n == 0 && errors.Is(err, io.EOF) {
+		return nil, 0, io.EOF
+	}
+	if n < len(header) {
+		if err == nil || errors.Is(err, io.EOF) {
+			return nil, 0, errLogIncomplete
+		}
+		return nil, 0, err
+	}
+
+	offset := binary.BigEndian.Uint64(header)
+	bodySize := binary.BigEndian.Uint32(header[8:])
+	checksum := binary.BigEndian.Uint32(header[12:])
+	if bodySize < logBodyHeaderSize || bodySize > logMaxBodySize {
+		return nil, 0, fmt.Errorf("invalid body length %d of log record %d", bodySize, offset)
+	}
+
+	body := make([]byte, bodySize)
+	n, err = segment.ReadAt(body, position+logRecordHeaderSize)
+	if n < len(body) {
+		if err == nil || errors.Is(err, io.EOF) {
+			return nil, 0, errLogIncomplete
+		}
+		return nil, 0, err
+	}
+
+	if crc32.Checksum(body, logChecksumTable) != checksum {
+		return nil, 0, fmt.Errorf("checksum mismatch of log record %d", offset)
+	}
+
+	typeLen := int(body[flow.IdentifierLen+8])
+	if logBodyHeaderSize+typeLen > len(body) {
+		return nil, 0, fmt.Errorf("invalid output type length of log record %d", offset)
+	}
+
+	record := &LogRecord{
+		Offset:     offset,
+		Height:     binary.BigEndian.Uint64(body[flow.IdentifierLen:]),
+		OutputType: OutputType(body[logBodyHeaderSize : logBodyHeaderSize+typeLen]),
+		Data:       body[logBodyHeaderSize+typeLen:],
+	}
+	copy(record.BlockID[:], body[:flow.IdentifierLen])
+
+	return record, logRecordHeaderSize + int64(bodySize), nil
+}
+
+// logSegments returns the base offsets of the segments of the log in the given
+// directory, in increasing order.
+func logSegments(dir string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read log directory: %w", err)
+	}
+
+	var bases []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, logSegmentSuffix) {
+			continue
+		}
+		base, err := strconv.ParseUint(strings.TrimSuffix(name, logSegmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		bases = append(bases, base)
+	}
+
+	sort.Slice(bases, func(i, j int) bool {
+		return bases[i] < bases[j]
+	})
+
+	return bases, nil
+}
+
+func logSegmentPath(dir string, base uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", base, logSegmentSuffix))
+}
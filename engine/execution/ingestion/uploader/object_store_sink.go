@@ -0,0 +1,253 @@
+package uploader
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/compressor"
+)
+
+const (
+	objectStoreObjectsDir   = "objects"
+	objectStoreManifestFile = "manifest.jsonl"
+	objectStoreObjectSuffix = ".cbor.lz4"
+)
+
+// ManifestEntry is a line of the manifest index of an ObjectStoreSink.
+type ManifestEntry struct {
+	BlockID    flow.Identifier `json:"block_id"`
+	Height     uint64          `json:"height"`
+	OutputType OutputType      `json:"output_type"`
+	// Object is the hex encoded SHA-256 hash of the uncompressed CBOR data.
+	Object string `json:"object"`
+	// Size is the size of the uncompressed CBOR data.
+	Size int `json:"size"`
+	// CompressedSize is the size of the stored object.
+	CompressedSize int       `json:"compressed_size"`
+	WrittenAt      time.Time `json:"written_at"`
+}
+
+var _ Sink = (*ObjectStoreSink)(nil)
+
+// ObjectStoreSink is a Sink which stores outputs in a local directory as
+// content-addressed, lz4 compressed objects, and records every written output in
+// a manifest index.
+//
+// The directory layout is:
+//
+//	<dir>/manifest.jsonl              one ManifestEntry per line, in write order
+//	<dir>/objects/<aa>/<hash>.cbor.lz4  compressed object named by the hash of its CBOR data
+//
+// Identical outputs are stored once. An output is only added to the manifest after
+// its object has been written, so every manifest entry refers to a complete object.
+// Retried uploads add duplicate manifest entries, which readers should ignore.
+type ObjectStoreSink struct {
+	mu         sync.Mutex
+	dir        string
+	compressor network.Compressor
+	manifest   *os.File
+}
+
+// NewObjectStoreSink creates an ObjectStoreSink writing to the given directory,
+// which is created if it does not exist.
+// No errors are expected during normal operation.
+func NewObjectStoreSink(dir string) (*ObjectStoreSink, error) {
+	err := os.MkdirAll(filepath.Join(dir, objectStoreObjectsDir), 0755)
+	if err != nil {
+		return nil, fmt.Errorf("could not create object store directory: %w", err)
+	}
+
+	manifest, err := os.OpenFile(filepath.Join(dir, objectStoreManifestFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open manifest: %w", err)
+	}
+
+	return &ObjectStoreSink{
+		dir:        dir,
+		compressor: compressor.NewLz4Compressor(),
+		manifest:   manifest,
+	}, nil
+}
+
+// Write stores the data as a content-addressed object and appends an entry to the
+// manifest.
+// All errors returned from this function can be considered benign.
+func (s *ObjectStoreSink) Write(blockID flow.Identifier, height uint64, outputType OutputType, data []byte) error {
+	hash := sha256.Sum256(data)
+	object := hex.EncodeToString(hash[:])
+
+	compressedSize, err := s.writeObject(object, data)
+	if err != nil {
+		return fmt.Errorf("could not write object %s: %w", object, err)
+	}
+
+	line, err := json.Marshal(ManifestEntry{
+		BlockID:        blockID,
+		Height:         height,
+		OutputType:     outputType,
+		Object:         object,
+		Size:           len(data),
+		CompressedSize: compressedSize,
+		WrittenAt:      time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("could not encode manifest entry: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.manifest.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("could not append manifest entry: %w", err)
+	}
+
+	return s.manifest.Sync()
+}
+
+// writeObject writes the compressed object unless it already exists, and returns
+// the size of the stored object.
+func (s *ObjectStoreSink) writeObject(object string, data []byte) (int, error) {
+	path := s.objectPath(object)
+
+	info, err := os.Stat(path)
+	if err == nil {
+		return int(info.Size()), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+
+	var buf bytes.Buffer
+	w, err := s.compressor.NewWriter(&buf)
+	if err != nil {
+		return 0, fmt.Errorf("could not create compressor: %w", err)
+	}
+	_, err = w.Write(data)
+	if err != nil {
+		return 0, fmt.Errorf("could not compress object: %w", err)
+	}
+	err = w.Close()
+	if err != nil {
+		return 0, fmt.Errorf("could not compress object: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return 0, fmt.Errorf("could not create object directory: %w", err)
+	}
+
+	// write to a temporary file first, so concurrent writers and crashes never
+	// leave a partial object behind
+	tmp, err := os.CreateTemp(filepath.Dir(path), object+".tmp-*")
+	if err != nil {
+		return 0, fmt.Errorf("could not create temporary object file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(buf.Bytes())
+	if err == nil {
+		err = tmp.Sync()
+	}
+	closeErr := tmp.Close()
+	if err != nil {
+		return 0, fmt.Errorf("could not write temporary object file: %w", err)
+	}
+	if closeErr != nil {
+		return 0, fmt.Errorf("could not close temporary object file: %w", closeErr)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return 0, fmt.Errorf("could not rename temporary object file: %w", err)
+	}
+
+	return buf.Len(), nil
+}
+
+func (s *ObjectStoreSink) objectPath(object string) string {
+	return filepath.Join(s.dir, objectStoreObjectsDir, object[:2], object+objectStoreObjectSuffix)
+}
+
+// Close closes the manifest.
+func (s *ObjectStoreSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.manifest.Close()
+}
+
+// ReadManifest reads all entries of the manifest index of the object store in the
+// given directory, in write order.
+// No errors are expected during normal operation.
+func ReadManifest(dir string) ([]ManifestEntry, error) {
+	file, err := os.Open(filepath.Join(dir, objectStoreManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("could not open manifest: %w", err)
+	}
+	defer file.Close()
+
+	var entries []ManifestEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry ManifestEntry
+		err = json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode manifest entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read manifest: %w", err)
+	}
+
+	return entries, nil
+}
+
+// ReadObject reads and decompresses the object with the given hash from the object
+// store in the given directory.
+// No errors are expected during normal operation.
+func ReadObject(dir string, object string) ([]byte, error) {
+	if len(object) != 2*sha256.Size {
+		return nil, fmt.Errorf("invalid object hash: %q", object)
+	}
+
+	file, err := os.Open(filepath.Join(dir, objectStoreObjectsDir, object[:2], object+objectStoreObjectSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("could not open object: %w", err)
+	}
+	defer file.Close()
+
+	r, err := compressor.NewLz4Compressor().NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not create decompressor: %w", err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress object: %w", err)
+	}
+
+	hash := sha256.Sum256(data)
+	if hex.EncodeToString(hash[:]) != object {
+		return nil, fmt.Errorf("object %s is corrupted", object)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,244 @@
+package uploader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// OutputType identifies a kind of block data output written to a sink.
+type OutputType string
+
+const (
+	// OutputBlockData is the complete BlockData, as uploaded to GCP and S3.
+	OutputBlockData OutputType = "block_data"
+	// OutputEvents is the list of events emitted by the block.
+	OutputEvents OutputType = "events"
+	// OutputTrieUpdates is the list of trie updates, one per chunk.
+	OutputTrieUpdates OutputType = "trie_updates"
+	// OutputTransactionResults is the list of transaction results.
+	OutputTransactionResults OutputType = "transaction_results"
+)
+
+// OutputTypes are all supported output types.
+var OutputTypes = []OutputType{
+	OutputBlockData,
+	OutputEvents,
+	OutputTrieUpdates,
+	OutputTransactionResults,
+}
+
+// ParseOutputType parses the name of an output type.
+func ParseOutputType(name string) (OutputType, error) {
+	for _, outputType := range OutputTypes {
+		if string(outputType) == name {
+			return outputType, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output type: %s", name)
+}
+
+// Sink persists encoded block data outputs.
+//
+// Sinks must be safe for concurrent use, since computation results of different
+// blocks are uploaded concurrently.  Uploads are retried as a whole, so a sink may
+// receive the same output more than once.
+type Sink interface {
+	// Write stores the CBOR encoded output of the given type for the block.
+	// All errors returned from this function can be considered benign.
+	Write(blockID flow.Identifier, height uint64, outputType OutputType, data []byte) error
+}
+
+var _ Uploader = (*SinkUploader)(nil)
+
+// SinkUploader is an Uploader which encodes the configured outputs of each
+// computation result and writes every output to the sinks configured for its
+// output type.
+type SinkUploader struct {
+	mu    sync.RWMutex
+	sinks map[OutputType][]Sink
+}
+
+// NewSinkUploader creates a new SinkUploader without any sinks.
+func NewSinkUploader() *SinkUploader {
+	return &SinkUploader{
+		sinks: make(map[OutputType][]Sink),
+	}
+}
+
+// AddSink adds a sink for the given output type.
+func (u *SinkUploader) AddSink(outputType OutputType, sink Sink) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.sinks[outputType] = append(u.sinks[outputType], sink)
+}
+
+// Upload encodes the configured outputs of the computation result and writes
+// them to their sinks.
+// All errors returned from this function can be considered benign.
+func (u *SinkUploader) Upload(computationResult *execution.ComputationResult) error {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	blockData := ComputationResultToBlockData(computationResult)
+	blockID := computationResult.ExecutableBlock.ID()
+	height := computationResult.ExecutableBlock.Height()
+
+	for _, outputType := range OutputTypes {
+		sinks := u.sinks[outputType]
+		if len(sinks) == 0 {
+			continue
+		}
+
+		data, err := EncodeOutput(blockData, outputType)
+		if err != nil {
+			return fmt.Errorf("could not encode %s of block %v: %w", outputType, blockID, err)
+		}
+
+		for _, sink := range sinks {
+			err = sink.Write(blockID, height, outputType, data)
+			if err != nil {
+				return fmt.Errorf("could not write %s of block %v: %w", outputType, blockID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close closes all sinks which implement io.Closer. Sinks shared by several output
+// types are closed once.
+func (u *SinkUploader) Close() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var errs []error
+	closed := make(map[Sink]struct{})
+	for _, outputType := range OutputTypes {
+		for _, sink := range u.sinks[outputType] {
+			if _, ok := closed[sink]; ok {
+				continue
+			}
+			closed[sink] = struct{}{}
+			if closer, ok := sink.(io.Closer); ok {
+				errs = append(errs, closer.Close())
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// EncodeOutput returns the deterministic CBOR encoding of the given output of the
+// block data.
+// No errors are expected during normal operation.
+func EncodeOutput(blockData *BlockData, outputType OutputType) ([]byte, error) {
+	var output interface{}
+	switch outputType {
+	case OutputBlockData:
+		output = blockData
+	case OutputEvents:
+		output = blockData.Events
+	case OutputTrieUpdates:
+		output = blockData.TrieUpdates
+	case OutputTransactionResults:
+		output = blockData.TxResults
+	default:
+		return nil, fmt.Errorf("unknown output type: %s", outputType)
+	}
+
+	mode, err := cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create deterministic cbor encoding mode: %w", err)
+	}
+
+	var buf bytes.Buffer
+	err = mode.NewEncoder(&buf).Encode(output)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// SinkKind is the kind of a sink created from a sink spec.
+type SinkKind string
+
+const (
+	// SinkKindObjectStore is a local content-addressed object store, see ObjectStoreSink.
+	SinkKindObjectStore SinkKind = "object"
+	// SinkKindLog is an append-only log, see LogSink.
+	SinkKindLog SinkKind = "log"
+)
+
+// SinkSpec describes a sink in the format `<kind>:<directory>`, for example
+// `log:/data/uploads/events`.
+type SinkSpec struct {
+	Kind SinkKind
+	Dir  string
+}
+
+// ParseSinkSpec parses a sink spec in the format `<kind>:<directory>`.
+func ParseSinkSpec(spec string) (SinkSpec, error) {
+	kind, dir, ok := strings.Cut(spec, ":")
+	if !ok || dir == "" {
+		return SinkSpec{}, fmt.Errorf("invalid sink spec %q, expected <kind>:<directory>", spec)
+	}
+
+	switch SinkKind(kind) {
+	case SinkKindObjectStore, SinkKindLog:
+		return SinkSpec{Kind: SinkKind(kind), Dir: dir}, nil
+	default:
+		return SinkSpec{}, fmt.Errorf("invalid sink kind %q, expected %s or %s", kind, SinkKindObjectStore, SinkKindLog)
+	}
+}
+
+// NewSink creates the sink described by the spec.
+// No errors are expected during normal operation.
+func NewSink(spec SinkSpec) (Sink, error) {
+	switch spec.Kind {
+	case SinkKindObjectStore:
+		return NewObjectStoreSink(spec.Dir)
+	case SinkKindLog:
+		return NewLogSink(spec.Dir, DefaultLogMaxSegmentBytes)
+	default:
+		return nil, fmt.Errorf("invalid sink kind: %s", spec.Kind)
+	}
+}
+
+// ParseSinkSpecs parses a mapping from output type names to sink specs, as
+// provided on the command line.  The output types are returned in sorted order.
+func ParseSinkSpecs(specs map[string]string) ([]OutputType, map[OutputType]SinkSpec, error) {
+	outputTypes := make([]OutputType, 0, len(specs))
+	parsed := make(map[OutputType]SinkSpec, len(specs))
+
+	for name, spec := range specs {
+		outputType, err := ParseOutputType(name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		sinkSpec, err := ParseSinkSpec(spec)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid sink for %s: %w", outputType, err)
+		}
+
+		outputTypes = append(outputTypes, outputType)
+		parsed[outputType] = sinkSpec
+	}
+
+	sort.Slice(outputTypes, func(i, j int) bool {
+		return outputTypes[i] < outputTypes[j]
+	})
+
+	return outputTypes, parsed, nil
+}
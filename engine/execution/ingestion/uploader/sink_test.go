@@ -0,0 +1,195 @@
+package uploader
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+
+	exeunittest "github.com/onflow/flow-go/engine/execution/state/unittest"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func Test_SinkUploader(t *testing.T) {
+	computationResult := exeunittest.ComputationResultFixture(
+		t,
+		unittest.IdentifierFixture(),
+		nil)
+	blockID := computationResult.ExecutableBlock.ID()
+	height := computationResult.ExecutableBlock.Height()
+
+	objectDir := t.TempDir()
+	objectSink, err := NewObjectStoreSink(objectDir)
+	require.NoError(t, err)
+
+	logDir := t.TempDir()
+	logSink, err := NewLogSink(logDir, DefaultLogMaxSegmentBytes)
+	require.NoError(t, err)
+
+	uploader := NewSinkUploader()
+	uploader.AddSink(OutputEvents, logSink)
+	uploader.AddSink(OutputTransactionResults, logSink)
+	uploader.AddSink(OutputTrieUpdates, objectSink)
+
+	err = uploader.Upload(computationResult)
+	require.NoError(t, err)
+	// uploading the same result again stores the trie updates object once
+	err = uploader.Upload(computationResult)
+	require.NoError(t, err)
+	require.NoError(t, uploader.Close())
+
+	blockData := ComputationResultToBlockData(computationResult)
+
+	t.Run("object store", func(t *testing.T) {
+		entries, err := ReadManifest(objectDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, entries[0].Object, entries[1].Object)
+
+		entry := entries[0]
+		require.Equal(t, blockID, entry.BlockID)
+		require.Equal(t, height, entry.Height)
+		require.Equal(t, OutputTrieUpdates, entry.OutputType)
+
+		data, err := ReadObject(objectDir, entry.Object)
+		require.NoError(t, err)
+		require.Len(t, data, entry.Size)
+
+		expected, err := EncodeOutput(blockData, OutputTrieUpdates)
+		require.NoError(t, err)
+		require.Equal(t, expected, data)
+	})
+
+	t.Run("log", func(t *testing.T) {
+		reader, err := NewLogReader(logDir, 0)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		expectedTypes := []OutputType{OutputEvents, OutputTransactionResults, OutputEvents, OutputTransactionResults}
+		for i, outputType := range expectedTypes {
+			record, err := reader.Next()
+			require.NoError(t, err)
+			require.Equal(t, uint64(i), record.Offset)
+			require.Equal(t, blockID, record.BlockID)
+			require.Equal(t, height, record.Height)
+			require.Equal(t, outputType, record.OutputType)
+		}
+
+		_, err = reader.Next()
+		require.ErrorIs(t, err, io.EOF)
+
+		// the records decode to the outputs of the block
+		reader, err = NewLogReader(logDir, 0)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		record, err := reader.Next()
+		require.NoError(t, err)
+		var events []*flow.Event
+		require.NoError(t, cbor.Unmarshal(record.Data, &events))
+		require.Equal(t, len(blockData.Events), len(events))
+	})
+}
+
+func Test_LogSink(t *testing.T) {
+	write := func(t *testing.T, sink *LogSink, n int) {
+		for i := 0; i < n; i++ {
+			err := sink.Write(unittest.IdentifierFixture(), uint64(i), OutputEvents, unittest.RandomBytes(100))
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("readers tail the log across segments", func(t *testing.T) {
+		dir := t.TempDir()
+		// every segment holds two records
+		sink, err := NewLogSink(dir, 200)
+		require.NoError(t, err)
+		defer sink.Close()
+
+		reader, err := NewLogReader(dir, 0)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		_, err = reader.Next()
+		require.ErrorIs(t, err, io.EOF)
+
+		for round := 0; round < 3; round++ {
+			write(t, sink, 3)
+			for i := 0; i < 3; i++ {
+				record, err := reader.Next()
+				require.NoError(t, err)
+				require.Equal(t, uint64(round*3+i), record.Offset)
+			}
+			_, err = reader.Next()
+			require.ErrorIs(t, err, io.EOF)
+		}
+
+		segments, err := logSegments(dir)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{0, 2, 4, 6, 8}, segments)
+
+		// readers can start at any offset
+		reader, err = NewLogReader(dir, 5)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		record, err := reader.Next()
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), record.Offset)
+	})
+
+	t.Run("reopening truncates a partial record", func(t *testing.T) {
+		dir := t.TempDir()
+		sink, err := NewLogSink(dir, DefaultLogMaxSegmentBytes)
+		require.NoError(t, err)
+		write(t, sink, 2)
+		require.NoError(t, sink.Close())
+
+		// simulate a crash in the middle of writing a record
+		file, err := os.OpenFile(filepath.Join(dir, "00000000000000000000.log"), os.O_WRONLY|os.O_APPEND, 0644)
+		require.NoError(t, err)
+		_, err = file.Write(encodeLogRecord(&LogRecord{Offset: 2, OutputType: OutputEvents, Data: []byte{1, 2, 3}})[:20])
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		reader, err := NewLogReader(dir, 2)
+		require.NoError(t, err)
+		defer reader.Close()
+		_, err = reader.Next()
+		require.ErrorIs(t, err, io.EOF)
+
+		sink, err = NewLogSink(dir, DefaultLogMaxSegmentBytes)
+		require.NoError(t, err)
+		defer sink.Close()
+		require.Equal(t, uint64(2), sink.NextOffset())
+
+		write(t, sink, 1)
+		record, err := reader.Next()
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), record.Offset)
+	})
+}
+
+func Test_ParseSinkSpecs(t *testing.T) {
+	outputTypes, specs, err := ParseSinkSpecs(map[string]string{
+		"transaction_results": "log:/data/log",
+		"events":              "log:/data/log",
+		"trie_updates":        "object:/data/objects",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []OutputType{OutputEvents, OutputTransactionResults, OutputTrieUpdates}, outputTypes)
+	require.Equal(t, SinkSpec{Kind: SinkKindObjectStore, Dir: "/data/objects"}, specs[OutputTrieUpdates])
+
+	_, _, err = ParseSinkSpecs(map[string]string{"blocks": "log:/data/log"})
+	require.Error(t, err)
+
+	_, _, err = ParseSinkSpecs(map[string]string{"events": "kafka:/data/log"})
+	require.Error(t, err)
+
+	_, _, err = ParseSinkSpecs(map[string]string{"events": "/data/log"})
+	require.Error(t, err)
+}
@@ -365,6 +365,7 @@ func CollectionNode(t *testing.T, hub *stub.Hub, identity bootstrap.NodeInfo, ro
 		node.Me,
 		node.PublicDB,
 		node.State,
+		store.NewSlashingEvidence(badgerimpl.ToDB(node.PublicDB)),
 		node.Metrics,
 		node.Metrics,
 		createMetrics,
@@ -2,6 +2,7 @@ package requester
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/onflow/flow-go/module/trace"
 	"github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/reqresp"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/utils/logging"
 )
@@ -38,6 +40,14 @@ const (
 
 	// DefaultRequestTargets is the  maximum number of execution nodes a chunk data pack request is dispatched to.
 	DefaultRequestTargets = 2
+
+	// requestTimeout is the time a dispatched chunk data pack request waits for a response before being retried
+	// with the other agreeing execution nodes.
+	requestTimeout = 10 * time.Second
+
+	// requestAttempts is the number of times a dispatched chunk data pack request is sent before giving up. Once the
+	// request gives up, it is dispatched again once its backoff in the pending requests mempool has elapsed.
+	requestAttempts = 3
 )
 
 // Engine implements a ChunkDataPackRequester that is responsible of receiving chunk data pack requests,
@@ -50,6 +60,10 @@ type Engine struct {
 	state protocol.State  // used to check the last sealed height.
 	con   network.Conduit // used to send the chunk data request, and receive the response.
 
+	// requests matches the received chunk data packs with the dispatched requests, and retries the requests
+	// with other execution nodes if they time out.
+	requests *reqresp.Client[*messages.ChunkDataRequest, *messages.ChunkDataResponse]
+
 	// monitoring
 	tracer  module.Tracer
 	metrics module.VerificationMetrics
@@ -95,6 +109,17 @@ func New(log zerolog.Logger,
 	}
 	e.con = con
 
+	e.requests, err = reqresp.NewClient[*messages.ChunkDataRequest, *messages.ChunkDataResponse](e.log, con, reqresp.Config[*messages.ChunkDataResponse]{
+		Timeout:     requestTimeout,
+		MaxAttempts: requestAttempts,
+		Fanout:      uint(requestTargets),
+		// chunk data requests are only authorized over pubsub, which execution nodes of all versions accept
+		Publish: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create chunk data pack request client: %w", err)
+	}
+
 	return e, nil
 }
 
@@ -156,6 +181,16 @@ func (e *Engine) Done() <-chan struct{} {
 func (e *Engine) process(originID flow.Identifier, event interface{}) error {
 	switch resource := event.(type) {
 	case *messages.ChunkDataResponse:
+		err := e.requests.Deliver(originID, resource.ChunkDataPack.ChunkID, resource)
+		if err == nil {
+			// the chunk data pack is handled by the dispatched request awaiting it
+			return nil
+		}
+		if !errors.Is(err, reqresp.ErrUnsolicitedResponse) {
+			return fmt.Errorf("could not deliver chunk data response: %w", err)
+		}
+		// the chunk data pack may still be pending even though its request is no longer awaiting it, e.g.,
+		// if it arrives after the request timed out.
 		e.handleChunkDataPackWithTracing(originID, &resource.ChunkDataPack)
 	default:
 		return fmt.Errorf("invalid event type (%T)", event)
@@ -330,19 +365,44 @@ func (e *Engine) requestChunkDataPack(request *verification.ChunkDataPackRequest
 		Nonce:   rand.Uint64(), // prevent the request from being deduplicated by the receiver
 	}
 
-	// publishes the chunk data request to the network
+	// the sampled execution nodes are asked first, and the other agreeing execution nodes are asked
+	// if they do not respond in time.
 	targetIDs, err := request.SampleTargets(int(e.requestTargets))
 	if err != nil {
 		return fmt.Errorf("target sampling failed: %w", err)
 	}
-	err = e.con.Publish(req, targetIDs...)
+	targetIDs = targetIDs.Union(request.Agrees)
+
+	// if the chunk data pack is already being requested, the request is sent again on the pending call.
+	call, joined, err := e.requests.Request(e.unit.Ctx(), request.ChunkID, req, targetIDs)
 	if err != nil {
-		return fmt.Errorf("could not publish chunk data pack request for chunk (id=%s): %w", request.ChunkID, err)
+		return fmt.Errorf("could not send chunk data pack request for chunk (id=%s): %w", request.ChunkID, err)
+	}
+	if !joined {
+		e.unit.Launch(func() {
+			e.awaitChunkDataPack(request.ChunkID, call)
+		})
 	}
 
 	return nil
 }
 
+// awaitChunkDataPack waits for the response to the given chunk data pack request, and handles the received
+// chunk data pack.
+func (e *Engine) awaitChunkDataPack(chunkID flow.Identifier, call *reqresp.Call[*messages.ChunkDataResponse]) {
+	<-call.Done()
+	response, originID, err := call.Result()
+	if err != nil {
+		// the chunk data pack is requested again once the backoff of its request has elapsed.
+		e.log.Debug().
+			Err(err).
+			Hex("chunk_id", logging.ID(chunkID)).
+			Msg("no chunk data pack received for the request")
+		return
+	}
+	e.handleChunkDataPackWithTracing(originID, &response.ChunkDataPack)
+}
+
 // canDispatchRequest returns whether chunk data request for this chunk ID can be dispatched.
 func (e *Engine) canDispatchRequest(chunkID flow.Identifier) bool {
 	attempts, lastAttempt, retryAfter, exists := e.pendingRequests.RequestHistory(chunkID)
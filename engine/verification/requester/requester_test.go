@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/rs/zerolog"
 	testifymock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -22,7 +23,10 @@ import (
 	"github.com/onflow/flow-go/module/mock"
 	"github.com/onflow/flow-go/module/trace"
 	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/message"
 	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/validator"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 )
@@ -800,3 +804,27 @@ func mockPendingRequestInfoAndUpdate(t *testing.T,
 
 	return historyWG, updateWG
 }
+
+// TestChunkDataRequestAuthorization evaluates that the chunk data requests, which the requester publishes, pass the
+// authorized sender validation of the execution nodes, while unicast chunk data requests would be rejected and
+// reported as a violation.
+func TestChunkDataRequestAuthorization(t *testing.T) {
+	verIdentity, _ := unittest.IdentityWithNetworkingKeyFixture(unittest.WithRole(flow.RoleVerification))
+	pid, err := unittest.PeerIDFromFlowID(verIdentity)
+	require.NoError(t, err)
+	payload, err := unittest.NetworkCodec().Encode(&messages.ChunkDataRequest{ChunkID: unittest.IdentifierFixture()})
+	require.NoError(t, err)
+
+	violations := mocknetwork.NewViolationsConsumer(t)
+	authorizedSenderValidator := validator.NewAuthorizedSenderValidator(unittest.Logger(), violations, func(peer.ID) (*flow.Identity, bool) {
+		return verIdentity, true
+	})
+
+	validatePubsub := authorizedSenderValidator.PubSubMessageValidator(channels.RequestChunks)
+	result := validatePubsub(pid, &message.Message{ChannelID: channels.RequestChunks.String(), Payload: payload})
+	require.Equal(t, p2p.ValidationAccept, result)
+
+	violations.On("OnUnauthorizedUnicastOnChannel", testifymock.Anything).Once()
+	_, err = authorizedSenderValidator.Validate(pid, payload, channels.RequestChunks, message.ProtocolTypeUnicast)
+	require.ErrorIs(t, err, message.ErrUnauthorizedUnicastOnChannel)
+}
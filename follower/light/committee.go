@@ -0,0 +1,267 @@
+package light
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/committees"
+	"github.com/onflow/flow-go/consensus/hotstuff/committees/leader"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+)
+
+// epochInfo holds the data about one epoch that is pertinent to verifying QCs, derived from
+// the epoch's EpochSetup and EpochCommit service events.
+type epochInfo struct {
+	*leader.LeaderSelection
+	counter              uint64
+	setupID              flow.Identifier
+	commitID             flow.Identifier
+	initialCommittee     flow.IdentitySkeletonList
+	initialCommitteeMap  map[flow.Identifier]*flow.IdentitySkeleton
+	weightThresholdForQC uint64
+	weightThresholdForTO uint64
+	dkg                  hotstuff.DKG
+}
+
+// newEpochInfo computes the committee information and leader selection of the epoch defined
+// by the given service events.
+// No errors are expected during normal operation.
+func newEpochInfo(setup *flow.EpochSetup, commit *flow.EpochCommit) (*epochInfo, error) {
+	epoch := inmem.NewCommittedEpoch(setup, commit, nil)
+	leaders, err := leader.SelectionForConsensusFromEpoch(epoch)
+	if err != nil {
+		return nil, fmt.Errorf("could not compute leader selection: %w", err)
+	}
+	dkg, err := epoch.DKG()
+	if err != nil {
+		return nil, fmt.Errorf("could not get dkg: %w", err)
+	}
+
+	initialCommittee := epoch.InitialIdentities().Filter(filter.IsConsensusCommitteeMember)
+	totalWeight := initialCommittee.TotalWeight()
+	return &epochInfo{
+		LeaderSelection:      leaders,
+		counter:              setup.Counter,
+		setupID:              setup.ID(),
+		commitID:             commit.ID(),
+		initialCommittee:     initialCommittee,
+		initialCommitteeMap:  initialCommittee.Lookup(),
+		weightThresholdForQC: committees.WeightThresholdToBuildQC(totalWeight),
+		weightThresholdForTO: committees.WeightThresholdToTimeout(totalWeight),
+		dkg:                  dkg,
+	}, nil
+}
+
+// committee is the consensus committee of the epochs followed by the light follower. In
+// contrast to committees.Consensus, it is not backed by the protocol state: epochs are added
+// as their service events are verified by the light follower.
+type committee struct {
+	mu     sync.RWMutex
+	epochs []*epochInfo // ordered by epoch counter
+}
+
+var _ hotstuff.Replicas = (*committee)(nil)
+
+// addEpoch adds the epoch defined by the given service events. The epoch must directly
+// follow the latest epoch of the committee. The service events must have been validated
+// by the caller (see isValidNextEpoch).
+// No errors are expected during normal operation.
+func (c *committee) addEpoch(setup *flow.EpochSetup, commit *flow.EpochCommit) error {
+	epoch, err := newEpochInfo(setup, commit)
+	if err != nil {
+		return fmt.Errorf("could not prepare epoch %d: %w", setup.Counter, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.epochs) > 0 {
+		err = follows(epoch, c.epochs[len(c.epochs)-1])
+		if err != nil {
+			return err
+		}
+	}
+	c.epochs = append(c.epochs, epoch)
+	return nil
+}
+
+// replaceLatestEpoch replaces the latest epoch with the epoch defined by the given service
+// events, which must have the same counter. The root epoch cannot be replaced. The service
+// events must have been validated by the caller (see isValidNextEpoch).
+// No errors are expected during normal operation.
+func (c *committee) replaceLatestEpoch(setup *flow.EpochSetup, commit *flow.EpochCommit) error {
+	epoch, err := newEpochInfo(setup, commit)
+	if err != nil {
+		return fmt.Errorf("could not prepare epoch %d: %w", setup.Counter, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.epochs) < 2 {
+		return fmt.Errorf("cannot replace root epoch %d", c.epochs[0].counter)
+	}
+	err = follows(epoch, c.epochs[len(c.epochs)-2])
+	if err != nil {
+		return err
+	}
+	c.epochs[len(c.epochs)-1] = epoch
+	return nil
+}
+
+// follows returns an error unless the epoch directly follows the previous epoch.
+func follows(epoch *epochInfo, previous *epochInfo) error {
+	if epoch.counter != previous.counter+1 || epoch.FirstView() <= previous.FinalView() {
+		return fmt.Errorf("epoch %d (first view %d) does not follow epoch %d (final view %d)",
+			epoch.counter, epoch.FirstView(), previous.counter, previous.FinalView())
+	}
+	return nil
+}
+
+// latestEpochs returns the latest epoch of the committee, and the epoch before it. The
+// previous epoch is nil if the latest epoch is the root epoch.
+func (c *committee) latestEpochs() (latest *epochInfo, previous *epochInfo) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	latest = c.epochs[len(c.epochs)-1]
+	if len(c.epochs) > 1 {
+		previous = c.epochs[len(c.epochs)-2]
+	}
+	return latest, previous
+}
+
+// isValidNextEpoch checks the service events of the epoch following the previous epoch
+// against the rules the protocol state enforces: the EpochSetup event must be well-formed,
+// continue the epoch counter and start with the view after the previous epoch's final
+// view, and the EpochCommit event must be consistent with the EpochSetup event, including
+// the DKG and the cluster QCs. Service events violating these rules are rejected by the
+// protocol state, which enters epoch fallback mode instead of transitioning to the epoch.
+// An epoch set up by an EpochRecover event starts after the extensions of the previous
+// epoch, which the light follower does not know, so only its start after the previous
+// epoch's original final view is checked.
+// Any error return indicates that the service events are invalid.
+func isValidNextEpoch(previous *epochInfo, setup *flow.EpochSetup, commit *flow.EpochCommit, recovery bool) error {
+	err := isValidNextEpochSetup(previous, setup, recovery)
+	if err != nil {
+		return err
+	}
+	return protocol.IsValidEpochCommit(commit, setup)
+}
+
+// isValidNextEpochSetup checks the EpochSetup event of the epoch following the previous
+// epoch, see isValidNextEpoch.
+// Any error return indicates that the service event is invalid.
+func isValidNextEpochSetup(previous *epochInfo, setup *flow.EpochSetup, recovery bool) error {
+	if setup.Counter != previous.counter+1 {
+		return fmt.Errorf("epoch counter %d does not follow epoch %d", setup.Counter, previous.counter)
+	}
+	if recovery && setup.FirstView <= previous.FinalView() {
+		return fmt.Errorf("first view %d of recovery epoch is not after final view %d of epoch %d", setup.FirstView, previous.FinalView(), previous.counter)
+	}
+	if !recovery && setup.FirstView != previous.FinalView()+1 {
+		return fmt.Errorf("first view %d does not directly follow final view %d of epoch %d", setup.FirstView, previous.FinalView(), previous.counter)
+	}
+	return protocol.IsValidEpochSetup(setup, true)
+}
+
+// epochInfoByView returns the epoch which includes the given view. Once a subsequent epoch is
+// known, an epoch ends at the view before the subsequent epoch's first view, which accounts
+// for extensions of the epoch.
+// Error returns:
+//   - model.ErrViewForUnknownEpoch if no followed epoch includes the given view
+func (c *committee) epochInfoByView(view uint64) (*epochInfo, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for i := len(c.epochs) - 1; i >= 0; i-- {
+		epoch := c.epochs[i]
+		if view < epoch.FirstView() {
+			continue
+		}
+		if i == len(c.epochs)-1 && view > epoch.FinalView() {
+			break
+		}
+		return epoch, nil
+	}
+	return nil, model.ErrViewForUnknownEpoch
+}
+
+// IdentitiesByEpoch returns the consensus committee of the epoch which includes the given view.
+// Error returns:
+//   - model.ErrViewForUnknownEpoch if no followed epoch includes the given view
+func (c *committee) IdentitiesByEpoch(view uint64) (flow.IdentitySkeletonList, error) {
+	epoch, err := c.epochInfoByView(view)
+	if err != nil {
+		return nil, err
+	}
+	return epoch.initialCommittee, nil
+}
+
+// IdentityByEpoch returns the identity of the given consensus participant in the epoch which
+// includes the given view.
+// Error returns:
+//   - model.ErrViewForUnknownEpoch if no followed epoch includes the given view
+//   - model.InvalidSignerError if the node is not an authorized consensus participant
+func (c *committee) IdentityByEpoch(view uint64, participantID flow.Identifier) (*flow.IdentitySkeleton, error) {
+	epoch, err := c.epochInfoByView(view)
+	if err != nil {
+		return nil, err
+	}
+	identity, ok := epoch.initialCommitteeMap[participantID]
+	if !ok {
+		return nil, model.NewInvalidSignerErrorf("id %v is not a valid node id", participantID)
+	}
+	return identity, nil
+}
+
+// LeaderForView returns the leader of the given view. Views of an extension of the epoch are
+// outside the epoch's leader selection, for which an error is returned.
+// Error returns:
+//   - model.ErrViewForUnknownEpoch if no followed epoch includes the given view
+func (c *committee) LeaderForView(view uint64) (flow.Identifier, error) {
+	epoch, err := c.epochInfoByView(view)
+	if err != nil {
+		return flow.ZeroID, err
+	}
+	return epoch.LeaderForView(view)
+}
+
+// QuorumThresholdForView returns the minimum weight required for a QC in the given view.
+// Error returns:
+//   - model.ErrViewForUnknownEpoch if no followed epoch includes the given view
+func (c *committee) QuorumThresholdForView(view uint64) (uint64, error) {
+	epoch, err := c.epochInfoByView(view)
+	if err != nil {
+		return 0, err
+	}
+	return epoch.weightThresholdForQC, nil
+}
+
+// TimeoutThresholdForView returns the minimum weight of timeouts for timing out the given view.
+// Error returns:
+//   - model.ErrViewForUnknownEpoch if no followed epoch includes the given view
+func (c *committee) TimeoutThresholdForView(view uint64) (uint64, error) {
+	epoch, err := c.epochInfoByView(view)
+	if err != nil {
+		return 0, err
+	}
+	return epoch.weightThresholdForTO, nil
+}
+
+// Self returns the zero ID, as the light follower is not a consensus participant.
+func (c *committee) Self() flow.Identifier {
+	return flow.ZeroID
+}
+
+// DKG returns the DKG of the epoch which includes the given view.
+// Error returns:
+//   - model.ErrViewForUnknownEpoch if no followed epoch includes the given view
+func (c *committee) DKG(view uint64) (hotstuff.DKG, error) {
+	epoch, err := c.epochInfoByView(view)
+	if err != nil {
+		return nil, err
+	}
+	return epoch.dkg, nil
+}
@@ -0,0 +1,75 @@
+package light
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+var (
+	// ErrUnknownParent indicates that the parent of a header has not been added to the follower.
+	ErrUnknownParent = errors.New("unknown parent")
+	// ErrUnknownEpoch indicates that a header or its QC is for a view outside the followed epochs,
+	// because the service events of a subsequent epoch have not been added yet.
+	ErrUnknownEpoch = errors.New("view for unknown epoch")
+	// ErrTooFarAhead indicates that a header's view is too far ahead of the finalized view, see
+	// MaxViewsAhead. The header can be added again once the follower has finalized further headers.
+	ErrTooFarAhead = errors.New("view too far ahead of finalized view")
+)
+
+// InvalidHeaderError indicates that a header is invalid, in particular that its QC is invalid.
+type InvalidHeaderError struct {
+	BlockID flow.Identifier
+	Height  uint64
+	Err     error
+}
+
+func NewInvalidHeaderErrorf(header *flow.Header, msg string, args ...interface{}) error {
+	return InvalidHeaderError{
+		BlockID: header.ID(),
+		Height:  header.Height,
+		Err:     fmt.Errorf(msg, args...),
+	}
+}
+
+func (e InvalidHeaderError) Error() string {
+	return fmt.Sprintf("invalid header %x at height %d: %s", e.BlockID, e.Height, e.Err.Error())
+}
+
+func (e InvalidHeaderError) Unwrap() error {
+	return e.Err
+}
+
+// IsInvalidHeaderError returns whether an error is InvalidHeaderError
+func IsInvalidHeaderError(err error) bool {
+	var e InvalidHeaderError
+	return errors.As(err, &e)
+}
+
+// InvalidProofError indicates that a SealedResultProof is invalid.
+type InvalidProofError struct {
+	ResultID flow.Identifier
+	Err      error
+}
+
+func NewInvalidProofErrorf(proof *SealedResultProof, msg string, args ...interface{}) error {
+	return InvalidProofError{
+		ResultID: proof.Result.ID(),
+		Err:      fmt.Errorf(msg, args...),
+	}
+}
+
+func (e InvalidProofError) Error() string {
+	return fmt.Sprintf("invalid proof for sealed result %x: %s", e.ResultID, e.Err.Error())
+}
+
+func (e InvalidProofError) Unwrap() error {
+	return e.Err
+}
+
+// IsInvalidProofError returns whether an error is InvalidProofError
+func IsInvalidProofError(err error) bool {
+	var e InvalidProofError
+	return errors.As(err, &e)
+}
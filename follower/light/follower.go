@@ -0,0 +1,553 @@
+package light
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/consensus/hotstuff/signature"
+	"github.com/onflow/flow-go/consensus/hotstuff/validator"
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// MaxViewsAhead is the maximum distance of the views of the headers added to the follower from
+// the view of the latest finalized header.
+const MaxViewsAhead = 10_000
+
+// OnHeaderFinalizedConsumer is notified of every header finalized by the light follower, in
+// order of height. Consumers are called synchronously and must be non-blocking.
+type OnHeaderFinalizedConsumer = func(header *flow.Header)
+
+// Follower is a light-weight alternative to the follower.ConsensusFollower for services which
+// only need the finalized block headers. It does not maintain a protocol state nor process
+// block payloads. Instead, it verifies the QC contained in each header against the consensus
+// committee of the header's epoch and determines finality from the certified headers, using
+// the same two-chain rule as HotStuff: a block is finalized once its direct child (the child
+// with the subsequent view) is certified.
+//
+// The follower does not retrieve headers itself. Headers must be added in ancestor-first
+// order, e.g. as retrieved from an Access node by the light-follower util command. To follow the committee across epochs, the
+// EpochSetup and EpochCommit service events of each subsequent epoch must be added, together
+// with the proof that they were sealed in a finalized block (see AddSealedResult). Epoch
+// extensions are not followed: while the latest epoch is extended, headers can only be
+// verified once the recovery epoch has been added.
+//
+// The follower persists only the finalized headers and the epoch service events. All methods
+// are concurrency safe.
+type Follower struct {
+	log       zerolog.Logger
+	db        storage.DB
+	chainID   flow.ChainID
+	committee *committee
+	validator hotstuff.Validator
+
+	mu        sync.Mutex
+	finalized *flow.Header
+	// pending holds the verified headers descending from the latest finalized header
+	pending map[flow.Identifier]*flow.Header
+	// pendingSetup is the EpochSetup event of the next epoch, while its EpochCommit event is unknown
+	pendingSetup *flow.EpochSetup
+	consumers    []OnHeaderFinalizedConsumer
+}
+
+// IsBootstrapped returns true if the light follower database has been bootstrapped.
+// No errors are expected during normal operation.
+func IsBootstrapped(db storage.DB) (bool, error) {
+	var height uint64
+	err := operation.RetrieveFinalizedHeight(db.Reader(), &height)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not retrieve finalized height: %w", err)
+	}
+	return true, nil
+}
+
+// Bootstrap initializes an empty light follower database with a trusted root: a finalized
+// header, the QC certifying it and the service events of the epoch containing the root view.
+// The root is trusted without verification, it is typically taken from a root protocol snapshot.
+// No errors are expected during normal operation.
+func Bootstrap(db storage.DB, root *flow.Header, rootQC *flow.QuorumCertificate, setup *flow.EpochSetup, commit *flow.EpochCommit) error {
+	bootstrapped, err := IsBootstrapped(db)
+	if err != nil {
+		return err
+	}
+	if bootstrapped {
+		return fmt.Errorf("light follower database is already bootstrapped")
+	}
+	if rootQC.BlockID != root.ID() || rootQC.View != root.View {
+		return fmt.Errorf("root QC (block %v, view %d) does not certify root block (block %v, view %d)", rootQC.BlockID, rootQC.View, root.ID(), root.View)
+	}
+	if setup.Counter != commit.Counter {
+		return fmt.Errorf("inconsistent root epoch service events for epochs %d and %d", setup.Counter, commit.Counter)
+	}
+	if root.View < setup.FirstView || root.View > setup.FinalView {
+		return fmt.Errorf("root view %d is not within root epoch views [%d, %d]", root.View, setup.FirstView, setup.FinalView)
+	}
+
+	return db.WithReaderBatchWriter(func(rw storage.ReaderBatchWriter) error {
+		w := rw.Writer()
+		err := operation.InsertHeader(w, root.ID(), root)
+		if err != nil {
+			return fmt.Errorf("could not insert root header: %w", err)
+		}
+		err = operation.IndexFinalizedBlockByHeight(w, root.Height, root.ID())
+		if err != nil {
+			return fmt.Errorf("could not index root header: %w", err)
+		}
+		err = operation.UpsertFinalizedHeight(w, root.Height)
+		if err != nil {
+			return fmt.Errorf("could not insert finalized height: %w", err)
+		}
+		err = operation.UpsertLightEpochSetup(w, setup)
+		if err != nil {
+			return fmt.Errorf("could not insert root epoch setup: %w", err)
+		}
+		err = operation.UpsertLightEpochCommit(w, commit)
+		if err != nil {
+			return fmt.Errorf("could not insert root epoch commit: %w", err)
+		}
+		return nil
+	})
+}
+
+// New creates a light follower from a bootstrapped database, continuing from the latest
+// persisted finalized header.
+// No errors are expected during normal operation.
+func New(log zerolog.Logger, db storage.DB) (*Follower, error) {
+	var height uint64
+	err := operation.RetrieveFinalizedHeight(db.Reader(), &height)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve finalized height (database not bootstrapped?): %w", err)
+	}
+	finalized, err := headerByHeight(db.Reader(), height)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Follower{
+		log:       log.With().Str("component", "light_follower").Logger(),
+		db:        db,
+		chainID:   finalized.ChainID,
+		committee: &committee{},
+		finalized: finalized,
+		pending:   make(map[flow.Identifier]*flow.Header),
+	}
+	f.validator = validator.New(f.committee, verification.NewCombinedVerifier(f.committee, signature.NewConsensusSigDataPacker(f.committee)))
+
+	err = operation.TraverseLightEpochSetups(db.Reader(), func(setup *flow.EpochSetup) error {
+		var commit flow.EpochCommit
+		err := operation.RetrieveLightEpochCommit(db.Reader(), setup.Counter, &commit)
+		if errors.Is(err, storage.ErrNotFound) {
+			f.pendingSetup = setup
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not retrieve epoch commit for epoch %d: %w", setup.Counter, err)
+		}
+		return f.committee.addEpoch(setup, &commit)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not load epochs: %w", err)
+	}
+	return f, nil
+}
+
+// AddOnHeaderFinalizedConsumer subscribes the consumer to the headers finalized from now on.
+func (f *Follower) AddOnHeaderFinalizedConsumer(consumer OnHeaderFinalizedConsumer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.consumers = append(f.consumers, consumer)
+}
+
+// FinalizedHeader returns the latest finalized header.
+func (f *Follower) FinalizedHeader() *flow.Header {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.finalized
+}
+
+// HeaderByHeight returns the finalized header with the given height.
+// Expected errors during normal operations:
+//   - storage.ErrNotFound if no header with the given height has been finalized by the follower
+func (f *Follower) HeaderByHeight(height uint64) (*flow.Header, error) {
+	return headerByHeight(f.db.Reader(), height)
+}
+
+// AddHeader verifies the header, its proposer's signature and the QC it contains for its
+// parent, and finalizes the ancestors of the header according to the two-chain rule. Headers
+// at or below the finalized height are ignored. To bound the number of pending headers, headers more than MaxViewsAhead
+// views ahead of the finalized header are rejected, and can be added again once the follower
+// has finalized further headers.
+// Expected errors during normal operations:
+//   - ErrUnknownParent if the header's parent has not been added
+//   - ErrTooFarAhead if the header's view is too far ahead of the finalized view
+//   - ErrUnknownEpoch if the view of the header or its parent is not within a followed epoch
+//   - InvalidHeaderError if the header, its proposer's signature or its QC is invalid
+func (f *Follower) AddHeader(header *flow.Header) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	blockID := header.ID()
+	if header.Height <= f.finalized.Height {
+		return nil
+	}
+	if _, ok := f.pending[blockID]; ok {
+		return nil
+	}
+	if header.View <= f.finalized.View {
+		return NewInvalidHeaderErrorf(header, "view %d is not above finalized view %d", header.View, f.finalized.View)
+	}
+	if header.View > f.finalized.View+MaxViewsAhead {
+		return fmt.Errorf("view %d of block %v exceeds finalized view %d by more than %d: %w", header.View, blockID, f.finalized.View, MaxViewsAhead, ErrTooFarAhead)
+	}
+
+	var parent *flow.Header
+	if header.ParentID == f.finalized.ID() {
+		parent = f.finalized
+	} else if parent = f.pending[header.ParentID]; parent == nil {
+		return fmt.Errorf("parent %v of block %v: %w", header.ParentID, blockID, ErrUnknownParent)
+	}
+
+	if header.ChainID != f.chainID {
+		return NewInvalidHeaderErrorf(header, "chain ID %v does not match followed chain %v", header.ChainID, f.chainID)
+	}
+	if header.Height != parent.Height+1 {
+		return NewInvalidHeaderErrorf(header, "height %d does not extend parent height %d", header.Height, parent.Height)
+	}
+	if header.ParentView != parent.View || header.View <= parent.View {
+		return NewInvalidHeaderErrorf(header, "views (parent view %d, view %d) inconsistent with parent view %d", header.ParentView, header.View, parent.View)
+	}
+	err := f.validateProposer(header)
+	if err != nil {
+		return err
+	}
+	err = f.validator.ValidateQC(header.QuorumCertificate())
+	if err != nil {
+		if model.IsInvalidQCError(err) {
+			return NewInvalidHeaderErrorf(header, "invalid QC for parent: %s", err.Error())
+		}
+		if errors.Is(err, model.ErrViewForUnknownEpoch) {
+			return fmt.Errorf("could not verify QC for view %d of block %v: %w", header.ParentView, blockID, ErrUnknownEpoch)
+		}
+		return fmt.Errorf("could not validate QC of block %v: %w", blockID, err)
+	}
+	f.pending[blockID] = header
+
+	// The header certifies its parent. By the two-chain rule, the parent's parent is finalized if
+	// the parent is its direct child.
+	if parent == f.finalized || parent.View != parent.ParentView+1 {
+		return nil
+	}
+	return f.finalize(parent.ParentID)
+}
+
+// validateProposer checks that the header is proposed by the leader of its view, and signed by
+// the proposer. Without it, anyone could add any number of made-up headers reusing a valid QC.
+// Timeout certificates are not verified, as finality only depends on the QCs.
+// Expected errors during normal operations:
+//   - ErrUnknownEpoch if the header's view is not within a followed epoch
+//   - InvalidHeaderError if the proposer is not the leader or its signature is invalid
+func (f *Follower) validateProposer(header *flow.Header) error {
+	leader, err := f.committee.LeaderForView(header.View)
+	if errors.Is(err, model.ErrViewForUnknownEpoch) {
+		return fmt.Errorf("could not determine leader for view %d of block %v: %w", header.View, header.ID(), ErrUnknownEpoch)
+	}
+	if err != nil {
+		return fmt.Errorf("could not determine leader for view %d: %w", header.View, err)
+	}
+	if leader != header.ProposerID {
+		return NewInvalidHeaderErrorf(header, "proposer %v is not the leader %v of view %d", header.ProposerID, leader, header.View)
+	}
+
+	_, err = f.validator.ValidateVote(model.SignedProposalFromFlow(header).ProposerVote())
+	if model.IsInvalidVoteError(err) {
+		return NewInvalidHeaderErrorf(header, "invalid proposer signature: %s", err.Error())
+	}
+	if errors.Is(err, model.ErrViewForUnknownEpoch) {
+		return fmt.Errorf("could not verify proposer signature of block %v: %w", header.ID(), ErrUnknownEpoch)
+	}
+	if err != nil {
+		return fmt.Errorf("could not verify proposer signature of block %v: %w", header.ID(), err)
+	}
+	return nil
+}
+
+// finalize finalizes the pending block with the given ID and its pending ancestors.
+// Caller must hold the lock.
+// No errors are expected during normal operation.
+func (f *Follower) finalize(blockID flow.Identifier) error {
+	finalizedID := f.finalized.ID()
+	if blockID == finalizedID {
+		return nil
+	}
+
+	var newlyFinalized []*flow.Header
+	for id := blockID; id != finalizedID; {
+		header, ok := f.pending[id]
+		if !ok {
+			// all pending headers descend from the finalized header, so the ancestors of a pending
+			// header can only be missing if conflicting blocks were finalized
+			return fmt.Errorf("finalized block %v does not descend from latest finalized block %v", blockID, finalizedID)
+		}
+		newlyFinalized = append(newlyFinalized, header)
+		id = header.ParentID
+	}
+
+	latest := newlyFinalized[0]
+	err := f.db.WithReaderBatchWriter(func(rw storage.ReaderBatchWriter) error {
+		w := rw.Writer()
+		for _, header := range newlyFinalized {
+			err := operation.InsertHeader(w, header.ID(), header)
+			if err != nil {
+				return fmt.Errorf("could not insert header %v: %w", header.ID(), err)
+			}
+			err = operation.IndexFinalizedBlockByHeight(w, header.Height, header.ID())
+			if err != nil {
+				return fmt.Errorf("could not index header %v: %w", header.ID(), err)
+			}
+		}
+		return operation.UpsertFinalizedHeight(w, latest.Height)
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist finalized headers: %w", err)
+	}
+
+	f.finalized = latest
+	for id, header := range f.pending {
+		if header.Height <= latest.Height {
+			delete(f.pending, id)
+		}
+	}
+	for i := len(newlyFinalized) - 1; i >= 0; i-- {
+		for _, consumer := range f.consumers {
+			consumer(newlyFinalized[i])
+		}
+	}
+	f.log.Debug().Uint64("height", latest.Height).Uint64("view", latest.View).Msg("finalized header")
+	return nil
+}
+
+// AddSealedResult processes the epoch service events emitted in the given execution result,
+// once the proof is verified that the result was sealed in a finalized block. EpochSetup and
+// EpochCommit events (or an EpochRecover event) of the epoch following the latest followed
+// epoch add the epoch to the committee. Events of already followed epochs are ignored.
+//
+// The service events are checked against the rules the protocol state enforces before they
+// are persisted. Events violating these rules are ignored, as the protocol state enters epoch
+// fallback mode instead of transitioning to the epoch. Since the light follower cannot check
+// all conditions for entering epoch fallback mode (e.g. events sealed too late in the epoch),
+// an EpochRecover event replaces the latest epoch, as long as no header of that epoch has been
+// finalized by the follower.
+// Expected errors during normal operations:
+//   - InvalidProofError if the proof is invalid
+func (f *Follower) AddSealedResult(proof *SealedResultProof) error {
+	header, err := f.verifySealedResultProof(proof)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, event := range proof.Result.ServiceEvents {
+		switch ev := event.Event.(type) {
+		case *flow.EpochSetup:
+			err = f.processEpochSetup(ev)
+		case *flow.EpochCommit:
+			err = f.processEpochCommit(ev, header)
+		case *flow.EpochRecover:
+			err = f.processEpochRecover(ev, header)
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processEpochSetup persists a valid EpochSetup event of the epoch following the latest
+// followed epoch, until the epoch's EpochCommit event is sealed.
+// Caller must hold the lock.
+// No errors are expected during normal operation.
+func (f *Follower) processEpochSetup(setup *flow.EpochSetup) error {
+	latest, _ := f.committee.latestEpochs()
+	if setup.Counter != latest.counter+1 {
+		return nil
+	}
+	log := f.log.With().Uint64("epoch", setup.Counter).Hex("setup_id", logging.ID(setup.ID())).Logger()
+	if f.pendingSetup != nil {
+		if f.pendingSetup.ID() != setup.ID() {
+			// the protocol state rejects duplicate EpochSetup events
+			log.Warn().Msg("ignoring duplicate epoch setup event")
+		}
+		return nil
+	}
+	err := isValidNextEpochSetup(latest, setup, false)
+	if err != nil {
+		log.Warn().Err(err).Msg("ignoring invalid epoch setup event")
+		return nil
+	}
+
+	err = f.db.WithReaderBatchWriter(func(rw storage.ReaderBatchWriter) error {
+		return operation.UpsertLightEpochSetup(rw.Writer(), setup)
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist epoch setup for epoch %d: %w", setup.Counter, err)
+	}
+	f.pendingSetup = setup
+	return nil
+}
+
+// processEpochCommit adds the epoch following the latest followed epoch to the committee,
+// if the EpochCommit event is valid for the pending EpochSetup event.
+// Caller must hold the lock.
+// No errors are expected during normal operation.
+func (f *Follower) processEpochCommit(commit *flow.EpochCommit, sealingHeader *flow.Header) error {
+	latest, _ := f.committee.latestEpochs()
+	if commit.Counter != latest.counter+1 || f.pendingSetup == nil {
+		return nil
+	}
+	err := protocol.IsValidEpochCommit(commit, f.pendingSetup)
+	if err != nil {
+		f.log.Warn().Err(err).Uint64("epoch", commit.Counter).Msg("ignoring invalid epoch commit event")
+		return nil
+	}
+
+	err = f.committee.addEpoch(f.pendingSetup, commit)
+	if err != nil {
+		return fmt.Errorf("could not add epoch %d: %w", commit.Counter, err)
+	}
+	err = f.db.WithReaderBatchWriter(func(rw storage.ReaderBatchWriter) error {
+		return operation.UpsertLightEpochCommit(rw.Writer(), commit)
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist epoch commit for epoch %d: %w", commit.Counter, err)
+	}
+	f.pendingSetup = nil
+	f.log.Info().Uint64("epoch", commit.Counter).Uint64("sealing_height", sealingHeader.Height).Msg("following committee of new epoch")
+	return nil
+}
+
+// processEpochRecover adds the recovery epoch to the committee if it follows the latest
+// followed epoch. If the recovery epoch has the counter of the latest followed epoch, it
+// replaces the latest epoch unless a header of the latest epoch has already been finalized:
+// the EpochSetup and EpochCommit events the latest epoch was added for have then been
+// rejected by the protocol state, for reasons the follower could not check.
+// Caller must hold the lock.
+// No errors are expected during normal operation.
+func (f *Follower) processEpochRecover(epochRecover *flow.EpochRecover, sealingHeader *flow.Header) error {
+	setup, commit := &epochRecover.EpochSetup, &epochRecover.EpochCommit
+	latest, previous := f.committee.latestEpochs()
+	log := f.log.With().Uint64("epoch", setup.Counter).Uint64("sealing_height", sealingHeader.Height).Logger()
+
+	replace := false
+	switch {
+	case setup.Counter == latest.counter+1:
+		previous = latest
+	case setup.Counter == latest.counter && previous != nil:
+		if latest.setupID == setup.ID() && latest.commitID == commit.ID() {
+			return nil
+		}
+		if f.finalized.View >= latest.FirstView() {
+			log.Warn().Msg("ignoring epoch recover event for epoch with finalized headers")
+			return nil
+		}
+		replace = true
+	default:
+		return nil
+	}
+
+	err := isValidNextEpoch(previous, setup, commit, true)
+	if err != nil {
+		log.Warn().Err(err).Msg("ignoring invalid epoch recover event")
+		return nil
+	}
+
+	if replace {
+		err = f.committee.replaceLatestEpoch(setup, commit)
+	} else {
+		err = f.committee.addEpoch(setup, commit)
+	}
+	if err != nil {
+		return fmt.Errorf("could not add recovery epoch %d: %w", setup.Counter, err)
+	}
+	err = f.db.WithReaderBatchWriter(func(rw storage.ReaderBatchWriter) error {
+		err := operation.UpsertLightEpochSetup(rw.Writer(), setup)
+		if err != nil {
+			return err
+		}
+		return operation.UpsertLightEpochCommit(rw.Writer(), commit)
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist recovery epoch %d: %w", setup.Counter, err)
+	}
+	f.pendingSetup = nil
+
+	if replace {
+		// pending headers whose QCs were verified against the replaced committee must be verified again
+		for id, header := range f.pending {
+			if header.ParentView >= latest.FirstView() {
+				delete(f.pending, id)
+			}
+		}
+		log.Warn().Msg("replaced committee of unconfirmed epoch with recovery epoch")
+		return nil
+	}
+	log.Info().Msg("following committee of recovery epoch")
+	return nil
+}
+
+// verifySealedResultProof verifies that the proof's result was sealed in a finalized block,
+// and returns the header of the sealing block.
+// Expected errors during normal operations:
+//   - InvalidProofError if the proof is invalid
+func (f *Follower) verifySealedResultProof(proof *SealedResultProof) (*flow.Header, error) {
+	var header flow.Header
+	err := operation.RetrieveHeader(f.db.Reader(), proof.SealingBlockID, &header)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, NewInvalidProofErrorf(proof, "sealing block %v is not finalized", proof.SealingBlockID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve sealing block %v: %w", proof.SealingBlockID, err)
+	}
+	if proof.Payload.Hash() != header.PayloadHash {
+		return nil, NewInvalidProofErrorf(proof, "payload does not match payload hash of sealing block %v", proof.SealingBlockID)
+	}
+	resultID := proof.Result.ID()
+	for _, seal := range proof.Payload.Seals {
+		if seal.ResultID == resultID {
+			return &header, nil
+		}
+	}
+	return nil, NewInvalidProofErrorf(proof, "sealing block %v does not seal result %v", proof.SealingBlockID, resultID)
+}
+
+// headerByHeight retrieves the finalized header with the given height.
+// Expected errors during normal operations:
+//   - storage.ErrNotFound if no header with the given height is finalized
+func headerByHeight(r storage.Reader, height uint64) (*flow.Header, error) {
+	var blockID flow.Identifier
+	err := operation.LookupBlockHeight(r, height, &blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not look up block at height %d: %w", height, err)
+	}
+	var header flow.Header
+	err = operation.RetrieveHeader(r, blockID, &header)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve header %v: %w", blockID, err)
+	}
+	return &header, nil
+}
@@ -0,0 +1,392 @@
+package light
+
+import (
+	"testing"
+
+	"github.com/onflow/crypto"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/signature"
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	msig "github.com/onflow/flow-go/module/signature"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation/dbtest"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// testEpoch is an epoch with a consensus committee of four nodes, whose keys are known so
+// that valid QCs can be constructed.
+type testEpoch struct {
+	setup       *flow.EpochSetup
+	commit      *flow.EpochCommit
+	consensus   flow.IdentityList
+	stakingKeys []crypto.PrivateKey
+	beaconKeys  []crypto.PrivateKey
+	committee   *committee
+}
+
+func newTestEpoch(t *testing.T, counter, firstView, finalView uint64) *testEpoch {
+	const n = 4
+	identities := unittest.IdentityListFixture(n, unittest.WithRole(flow.RoleConsensus)).Sort(flow.Canonical[flow.Identity])
+	stakingKeys := make([]crypto.PrivateKey, n)
+	for i, identity := range identities {
+		stakingKeys[i] = unittest.StakingPrivKeyFixture()
+		identity.StakingPubKey = stakingKeys[i].PublicKey()
+	}
+	beaconKeys, beaconPubKeys, groupKey, err := crypto.BLSThresholdKeyGen(n, msig.RandomBeaconThreshold(n), unittest.SeedFixture(crypto.KeyGenSeedMinLen))
+	require.NoError(t, err)
+	// a valid epoch requires nodes of all roles
+	participants := append(identities.Copy(), unittest.IdentityListFixture(3, unittest.WithAllRolesExcept(flow.RoleConsensus, flow.RoleAccess))...)
+
+	setup := unittest.EpochSetupFixture(func(setup *flow.EpochSetup) {
+		setup.Counter = counter
+		setup.FirstView = firstView
+		setup.FinalView = finalView
+		setup.Participants = participants.Sort(flow.Canonical[flow.Identity]).ToSkeleton()
+	})
+	commit := unittest.EpochCommitFixture(unittest.CommitWithCounter(counter), unittest.WithClusterQCsFromAssignments(setup.Assignments), func(commit *flow.EpochCommit) {
+		commit.DKGGroupKey = groupKey
+		commit.DKGParticipantKeys = beaconPubKeys
+		commit.DKGIndexMap = make(flow.DKGIndexMap)
+		for i, identity := range identities {
+			commit.DKGIndexMap[identity.NodeID] = i
+		}
+	})
+
+	c := &committee{}
+	require.NoError(t, c.addEpoch(setup, commit))
+	return &testEpoch{setup: setup, commit: commit, consensus: identities, stakingKeys: stakingKeys, beaconKeys: beaconKeys, committee: c}
+}
+
+// certify sets the QC for the parent block in the header, signed by all committee members.
+func (e *testEpoch) certify(t *testing.T, header *flow.Header, parent *flow.Header) {
+	msg := verification.MakeVoteMessage(parent.View, parent.ID())
+	stakingSigs := make([]crypto.Signature, 0, len(e.stakingKeys))
+	beaconSigs := make([]crypto.Signature, 0, len(e.beaconKeys))
+	beaconSigners := make([]int, 0, len(e.beaconKeys))
+	for i := range e.stakingKeys {
+		sig, err := e.stakingKeys[i].Sign(msg, msig.NewBLSHasher(msig.ConsensusVoteTag))
+		require.NoError(t, err)
+		stakingSigs = append(stakingSigs, sig)
+		sig, err = e.beaconKeys[i].Sign(msg, msig.NewBLSHasher(msig.RandomBeaconTag))
+		require.NoError(t, err)
+		beaconSigs = append(beaconSigs, sig)
+		beaconSigners = append(beaconSigners, i)
+	}
+	aggregatedStakingSig, err := crypto.AggregateBLSSignatures(stakingSigs)
+	require.NoError(t, err)
+	beaconSig, err := crypto.BLSReconstructThresholdSignature(len(e.beaconKeys), msig.RandomBeaconThreshold(len(e.beaconKeys)), beaconSigs, beaconSigners)
+	require.NoError(t, err)
+
+	signerIndices, sigData, err := signature.NewConsensusSigDataPacker(e.committee).Pack(parent.View, &hotstuff.BlockSignatureData{
+		StakingSigners:               e.consensus.NodeIDs(),
+		AggregatedStakingSig:         aggregatedStakingSig,
+		ReconstructedRandomBeaconSig: beaconSig,
+	})
+	require.NoError(t, err)
+	header.ParentVoterIndices = signerIndices
+	header.ParentVoterSigData = sigData
+}
+
+// propose sets the leader of the header's view in the epoch as the proposer of the header, and
+// signs the header with the leader's staking and random beacon keys. It must be called after the header has been
+// completed, since the signature covers the header ID.
+func (e *testEpoch) propose(t *testing.T, header *flow.Header) {
+	leader, err := e.committee.LeaderForView(header.View)
+	require.NoError(t, err)
+	header.ProposerID = leader
+	index, ok := e.consensus.GetIndex(leader)
+	require.True(t, ok)
+
+	msg := verification.MakeVoteMessage(header.View, header.ID())
+	stakingSig, err := e.stakingKeys[index].Sign(msg, msig.NewBLSHasher(msig.ConsensusVoteTag))
+	require.NoError(t, err)
+	beaconSig, err := e.beaconKeys[index].Sign(msg, msig.NewBLSHasher(msig.RandomBeaconTag))
+	require.NoError(t, err)
+	header.ProposerSigData = msig.EncodeDoubleSig(stakingSig, beaconSig)
+}
+
+// child returns a child of the parent header with the given view, certifying the parent and
+// proposed by the leader of the view in the epoch.
+func (e *testEpoch) child(t *testing.T, parent *flow.Header, view uint64) *flow.Header {
+	return e.childProposedBy(t, parent, view, e)
+}
+
+// childProposedBy returns a child of the parent header with the given view, certifying the
+// parent and proposed by the leader of the view in the given epoch.
+func (e *testEpoch) childProposedBy(t *testing.T, parent *flow.Header, view uint64, proposer *testEpoch) *flow.Header {
+	header := unittest.BlockHeaderWithParentFixture(parent)
+	header.ChainID = parent.ChainID
+	header.View = view
+	header.ParentView = parent.View
+	header.LastViewTC = nil
+	e.certify(t, header, parent)
+	proposer.propose(t, header)
+	return header
+}
+
+// bootstrap bootstraps the database with a root header at the given view in the epoch.
+func (e *testEpoch) bootstrap(t *testing.T, db storage.DB, view uint64) *flow.Header {
+	root := unittest.BlockHeaderFixture(unittest.HeaderWithView(view))
+	root.ChainID = flow.Emulator
+	rootQC := unittest.QuorumCertificateFixture(unittest.QCWithRootBlockID(root.ID()))
+	rootQC.View = view
+	require.NoError(t, Bootstrap(db, root, rootQC, e.setup, e.commit))
+	bootstrapped, err := IsBootstrapped(db)
+	require.NoError(t, err)
+	require.True(t, bootstrapped)
+	return root
+}
+
+// TestFollower_Finalization verifies that headers are finalized according to the two-chain
+// rule, that invalid headers are rejected and that the follower continues after a restart.
+func TestFollower_Finalization(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		epoch := newTestEpoch(t, 1, 0, 1000)
+		root := epoch.bootstrap(t, db, 10)
+		follower, err := New(zerolog.Nop(), db)
+		require.NoError(t, err)
+		var finalized []*flow.Header
+		follower.AddOnHeaderFinalizedConsumer(func(header *flow.Header) {
+			finalized = append(finalized, header)
+		})
+
+		h1 := epoch.child(t, root, 11)
+		h2 := epoch.child(t, h1, 12)
+		h3 := epoch.child(t, h2, 13)
+		h4 := epoch.child(t, h3, 15) // h3 is not finalized by the certification of h4
+		h5 := epoch.child(t, h4, 16)
+		h6 := epoch.child(t, h5, 17)
+		for _, header := range []*flow.Header{h1, h2, h3} {
+			require.NoError(t, follower.AddHeader(header))
+		}
+		assert.Equal(t, []*flow.Header{h1}, finalized)
+		for _, header := range []*flow.Header{h4, h5} {
+			require.NoError(t, follower.AddHeader(header))
+		}
+		assert.Equal(t, []*flow.Header{h1, h2}, finalized)
+		require.NoError(t, follower.AddHeader(h6))
+		assert.Equal(t, []*flow.Header{h1, h2, h3, h4}, finalized)
+		assert.Equal(t, h4.ID(), follower.FinalizedHeader().ID())
+		stored, err := follower.HeaderByHeight(h3.Height)
+		require.NoError(t, err)
+		assert.Equal(t, h3.ID(), stored.ID())
+		_, err = follower.HeaderByHeight(h5.Height)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+
+		// finalized and known headers are ignored
+		require.NoError(t, follower.AddHeader(h2))
+		require.NoError(t, follower.AddHeader(h6))
+
+		// headers with unknown parents or invalid QCs are rejected
+		orphan := epoch.child(t, unittest.BlockHeaderWithParentFixture(h6), 30)
+		assert.ErrorIs(t, follower.AddHeader(orphan), ErrUnknownParent)
+		forged := epoch.child(t, h6, 18)
+		forged.ParentVoterSigData = h6.ParentVoterSigData
+		epoch.propose(t, forged)
+		assert.True(t, IsInvalidHeaderError(follower.AddHeader(forged)))
+		other := newTestEpoch(t, 1, 0, 1000)
+		forged = other.childProposedBy(t, h6, 18, epoch)
+		assert.True(t, IsInvalidHeaderError(follower.AddHeader(forged)))
+
+		// headers which are not signed by the leader of their view are rejected, even with a valid QC
+		forged = epoch.child(t, h6, 18)
+		forged.PayloadHash = unittest.IdentifierFixture()
+		assert.True(t, IsInvalidHeaderError(follower.AddHeader(forged)))
+		forged = epoch.child(t, h6, 18)
+		forged.ProposerID = epoch.consensus.Filter(filter.Not(filter.HasNodeID[flow.Identity](forged.ProposerID)))[0].NodeID
+		assert.True(t, IsInvalidHeaderError(follower.AddHeader(forged)))
+
+		// the follower continues from the persisted finalized header after a restart
+		restarted, err := New(zerolog.Nop(), db)
+		require.NoError(t, err)
+		assert.Equal(t, h4.ID(), restarted.FinalizedHeader().ID())
+		for _, header := range []*flow.Header{h5, h6, epoch.child(t, h6, 18)} {
+			require.NoError(t, restarted.AddHeader(header))
+		}
+		assert.Equal(t, h5.ID(), restarted.FinalizedHeader().ID())
+	})
+}
+
+// TestFollower_EpochTransition verifies that the follower follows the committee into the next
+// epoch once the epoch's service events are proven to be sealed.
+func TestFollower_EpochTransition(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		epoch1 := newTestEpoch(t, 1, 0, 19)
+		epoch2 := newTestEpoch(t, 2, 20, 1000)
+		root := epoch1.bootstrap(t, db, 10)
+		follower, err := New(zerolog.Nop(), db)
+		require.NoError(t, err)
+
+		// the service events of epoch 2 are sealed in the payload of h1
+		setupResult := unittest.ExecutionResultFixture()
+		setupResult.ServiceEvents = []flow.ServiceEvent{{Type: flow.ServiceEventSetup, Event: epoch2.setup}}
+		commitResult := unittest.ExecutionResultFixture()
+		commitResult.ServiceEvents = []flow.ServiceEvent{{Type: flow.ServiceEventCommit, Event: epoch2.commit}}
+		payload := &flow.Payload{Seals: []*flow.Seal{
+			unittest.Seal.Fixture(unittest.Seal.WithResult(setupResult)),
+			unittest.Seal.Fixture(unittest.Seal.WithResult(commitResult)),
+		}}
+		h1 := epoch1.child(t, root, 11)
+		h1.PayloadHash = payload.Hash()
+		epoch1.propose(t, h1)
+		h2 := epoch1.child(t, h1, 19)
+		h3 := epoch1.childProposedBy(t, h2, 20, epoch2) // QC for view 19 signed by the committee of epoch 1, proposed in epoch 2
+		h4 := epoch2.child(t, h3, 21)                   // QC for view 20, signed by the committee of epoch 2
+		for _, header := range []*flow.Header{h1, h2} {
+			require.NoError(t, follower.AddHeader(header))
+		}
+		assert.ErrorIs(t, follower.AddHeader(h3), ErrUnknownEpoch)
+		_, err = follower.HeaderByHeight(h1.Height)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		// proofs must reference a finalized block sealing the result
+		err = follower.AddSealedResult(&SealedResultProof{SealingBlockID: h1.ID(), Payload: payload, Result: setupResult})
+		assert.True(t, IsInvalidProofError(err))
+		finalizer := epoch1.child(t, h1, 12)
+		require.NoError(t, follower.AddHeader(finalizer))
+		require.NoError(t, follower.AddHeader(epoch1.child(t, finalizer, 13)))
+		require.Equal(t, h1.ID(), follower.FinalizedHeader().ID())
+		err = follower.AddSealedResult(&SealedResultProof{SealingBlockID: h1.ID(), Payload: &flow.Payload{}, Result: setupResult})
+		assert.True(t, IsInvalidProofError(err))
+		err = follower.AddSealedResult(&SealedResultProof{SealingBlockID: h1.ID(), Payload: payload, Result: unittest.ExecutionResultFixture()})
+		assert.True(t, IsInvalidProofError(err))
+
+		require.NoError(t, follower.AddSealedResult(&SealedResultProof{SealingBlockID: h1.ID(), Payload: payload, Result: setupResult}))
+		assert.ErrorIs(t, follower.AddHeader(h3), ErrUnknownEpoch)
+
+		// a restarted follower retains the pending EpochSetup event
+		follower, err = New(zerolog.Nop(), db)
+		require.NoError(t, err)
+		require.NoError(t, follower.AddSealedResult(&SealedResultProof{SealingBlockID: h1.ID(), Payload: payload, Result: commitResult}))
+		for _, header := range []*flow.Header{h2, h3, h4, epoch2.child(t, h4, 22)} {
+			require.NoError(t, follower.AddHeader(header))
+		}
+		assert.Equal(t, h3.ID(), follower.FinalizedHeader().ID())
+
+		// the committee of epoch 2 is loaded after a restart
+		follower, err = New(zerolog.Nop(), db)
+		require.NoError(t, err)
+		latest, _ := follower.committee.latestEpochs()
+		assert.Equal(t, uint64(2), latest.counter)
+	})
+}
+
+// TestFollower_PendingBounds verifies that headers whose views are not above the finalized
+// view or too far ahead of it are rejected.
+func TestFollower_PendingBounds(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		epoch := newTestEpoch(t, 1, 0, 2*MaxViewsAhead)
+		root := epoch.bootstrap(t, db, 10)
+		follower, err := New(zerolog.Nop(), db)
+		require.NoError(t, err)
+
+		stale := epoch.child(t, root, 11)
+		stale.View = root.View
+		assert.True(t, IsInvalidHeaderError(follower.AddHeader(stale)))
+
+		ahead := epoch.child(t, root, root.View+MaxViewsAhead+1)
+		assert.ErrorIs(t, follower.AddHeader(ahead), ErrTooFarAhead)
+		require.NoError(t, follower.AddHeader(epoch.child(t, root, root.View+MaxViewsAhead)))
+	})
+}
+
+// sealedResults returns a header sealing results with the given service events, and the
+// proofs of the results once the header is finalized.
+func sealedResults(t *testing.T, epoch *testEpoch, parent *flow.Header, view uint64, events ...[]flow.ServiceEvent) (*flow.Header, []*SealedResultProof) {
+	payload := &flow.Payload{}
+	results := make([]*flow.ExecutionResult, 0, len(events))
+	for _, serviceEvents := range events {
+		result := unittest.ExecutionResultFixture()
+		result.ServiceEvents = serviceEvents
+		results = append(results, result)
+		payload.Seals = append(payload.Seals, unittest.Seal.Fixture(unittest.Seal.WithResult(result)))
+	}
+	header := epoch.child(t, parent, view)
+	header.PayloadHash = payload.Hash()
+	epoch.propose(t, header)
+
+	proofs := make([]*SealedResultProof, 0, len(results))
+	for _, result := range results {
+		proofs = append(proofs, &SealedResultProof{SealingBlockID: header.ID(), Payload: payload, Result: result})
+	}
+	return header, proofs
+}
+
+// TestFollower_InvalidEpochEvents verifies that service events rejected by the protocol
+// state are ignored, and that an EpochRecover event replaces an epoch none of whose headers
+// has been finalized.
+func TestFollower_InvalidEpochEvents(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		epoch1 := newTestEpoch(t, 1, 0, 99)
+		root := epoch1.bootstrap(t, db, 10)
+		follower, err := New(zerolog.Nop(), db)
+		require.NoError(t, err)
+
+		// epoch 2 does not start directly after epoch 1
+		gap := newTestEpoch(t, 2, 101, 1000)
+		// the service events of the phantom epoch pass the checks of the follower, while the protocol
+		// state entered epoch fallback mode, and recovered with the service events of the recovery epoch
+		phantom := newTestEpoch(t, 2, 100, 1000)
+		recovery := newTestEpoch(t, 2, 150, 1000)
+		other := newTestEpoch(t, 2, 140, 1000)
+		invalidCommit := *phantom.commit
+		invalidCommit.ClusterQCs = nil
+
+		h1, proofs := sealedResults(t, epoch1, root, 11,
+			[]flow.ServiceEvent{{Type: flow.ServiceEventSetup, Event: gap.setup}},
+			[]flow.ServiceEvent{{Type: flow.ServiceEventSetup, Event: phantom.setup}},
+			[]flow.ServiceEvent{{Type: flow.ServiceEventCommit, Event: &invalidCommit}},
+			[]flow.ServiceEvent{{Type: flow.ServiceEventCommit, Event: phantom.commit}},
+			[]flow.ServiceEvent{{Type: flow.ServiceEventRecover, Event: &flow.EpochRecover{EpochSetup: *recovery.setup, EpochCommit: *recovery.commit}}},
+			[]flow.ServiceEvent{{Type: flow.ServiceEventRecover, Event: &flow.EpochRecover{EpochSetup: *other.setup, EpochCommit: *other.commit}}},
+		)
+		h2 := epoch1.child(t, h1, 12)
+		h3 := epoch1.child(t, h2, 13)
+		for _, header := range []*flow.Header{h1, h2, h3} {
+			require.NoError(t, follower.AddHeader(header))
+		}
+		require.Equal(t, h1.ID(), follower.FinalizedHeader().ID())
+
+		// the setup event with the gap is ignored
+		require.NoError(t, follower.AddSealedResult(proofs[0]))
+		assert.Nil(t, follower.pendingSetup)
+		require.NoError(t, follower.AddSealedResult(proofs[1]))
+		assert.Equal(t, phantom.setup, follower.pendingSetup)
+		// the invalid commit event is ignored
+		require.NoError(t, follower.AddSealedResult(proofs[2]))
+		latest, _ := follower.committee.latestEpochs()
+		assert.Equal(t, uint64(1), latest.counter)
+		require.NoError(t, follower.AddSealedResult(proofs[3]))
+		latest, _ = follower.committee.latestEpochs()
+		assert.Equal(t, phantom.setup.ID(), latest.setupID)
+
+		// the recovery epoch replaces the phantom epoch, also after a restart
+		require.NoError(t, follower.AddSealedResult(proofs[4]))
+		latest, _ = follower.committee.latestEpochs()
+		assert.Equal(t, recovery.setup.ID(), latest.setupID)
+		follower, err = New(zerolog.Nop(), db)
+		require.NoError(t, err)
+		latest, _ = follower.committee.latestEpochs()
+		assert.Equal(t, recovery.setup.ID(), latest.setupID)
+		assert.Equal(t, recovery.commit.ID(), latest.commitID)
+
+		// once a header of the recovery epoch is finalized, the epoch is not replaced anymore (the pending
+		// headers are not persisted, so they have to be added again after the restart)
+		h4 := epoch1.childProposedBy(t, h3, 150, recovery)
+		h5 := recovery.child(t, h4, 151)
+		h6 := recovery.child(t, h5, 152)
+		for _, header := range []*flow.Header{h2, h3, h4, h5, h6} {
+			require.NoError(t, follower.AddHeader(header))
+		}
+		require.Equal(t, h4.ID(), follower.FinalizedHeader().ID())
+		require.NoError(t, follower.AddSealedResult(proofs[5]))
+		latest, _ = follower.committee.latestEpochs()
+		assert.Equal(t, recovery.setup.ID(), latest.setupID)
+	})
+}
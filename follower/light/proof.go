@@ -0,0 +1,18 @@
+package light
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// SealedResultProof proves that an execution result, and hence the service events it
+// contains, was sealed: the payload of a finalized block includes a seal for the result.
+// The payload is authenticated by the payload hash of the finalized header, and the result
+// (including its service events) by the result ID committed to by the seal.
+type SealedResultProof struct {
+	// SealingBlockID is the ID of the finalized block whose payload seals the result.
+	SealingBlockID flow.Identifier
+	// Payload is the payload of the sealing block.
+	Payload *flow.Payload
+	// Result is the sealed execution result.
+	Result *flow.ExecutionResult
+}
@@ -339,6 +339,21 @@ func WithReusableCadenceRuntimePool(
 	}
 }
 
+// WithContractFunctionProfiling counts the contract functions invoked by
+// procedures, see ProcedureOutput.ContractFunctionInvocations.  Invocations are
+// recorded by Cadence tracing: if the runtime pool does not have Cadence
+// tracing enabled, a pool with tracing enabled is created, and the traces are
+// only used to count the invocations, i.e. they are not emitted as spans.
+func WithContractFunctionProfiling() Option {
+	return func(ctx Context) Context {
+		if !ctx.ReusableCadenceRuntimePool.TracingEnabled() {
+			ctx.ReusableCadenceRuntimePool = ctx.ReusableCadenceRuntimePool.WithTracingEnabled()
+			ctx.CadenceTraceSpansDisabled = true
+		}
+		return ctx
+	}
+}
+
 // WithDerivedBlockData sets the derived data cache storage to be used by the
 // transaction/script.
 func WithDerivedBlockData(derivedBlockData *derived.DerivedBlockData) Option {
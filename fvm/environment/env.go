@@ -28,6 +28,7 @@ type Environment interface {
 	// ProgramLogger
 	LoggerProvider
 	Logs() []string
+	ContractFunctionInvocations() map[string]uint
 
 	// EventEmitter
 	Events() flow.EventsList
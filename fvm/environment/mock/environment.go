@@ -366,6 +366,26 @@ func (_m *Environment) ComputationUsed() (uint64, error) {
 	return r0, r1
 }
 
+// ContractFunctionInvocations provides a mock function with given fields:
+func (_m *Environment) ContractFunctionInvocations() map[string]uint {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContractFunctionInvocations")
+	}
+
+	var r0 map[string]uint
+	if rf, ok := ret.Get(0).(func() map[string]uint); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]uint)
+		}
+	}
+
+	return r0
+}
+
 // ConvertedServiceEvents provides a mock function with given fields:
 func (_m *Environment) ConvertedServiceEvents() flow.ServiceEventList {
 	ret := _m.Called()
@@ -1,6 +1,7 @@
 package environment
 
 import (
+	"strings"
 	"time"
 
 	"github.com/onflow/cadence/common"
@@ -69,11 +70,20 @@ func (NoopMetricsReporter) EVMTransactionExecuted(_ uint64, _ bool, _ bool) {}
 // EVMBlockExecuted is a noop
 func (NoopMetricsReporter) EVMBlockExecuted(_ int, _ uint64, _ float64) {}
 
+// cadenceFunctionTracePrefix is the prefix of the operation name Cadence uses
+// when tracing function invocations.
+const cadenceFunctionTracePrefix = "function."
+
 type ProgramLoggerParams struct {
 	zerolog.Logger
 
 	CadenceLoggingEnabled bool
 
+	// CadenceTraceSpansDisabled disables emitting the traces recorded by
+	// Cadence as spans.  It is set when Cadence tracing is only enabled to
+	// count the contract function invocations.
+	CadenceTraceSpansDisabled bool
+
 	MetricsReporter
 }
 
@@ -91,6 +101,11 @@ type ProgramLogger struct {
 	ProgramLoggerParams
 
 	logs []string
+
+	// contractFunctionInvocations counts the Cadence functions invoked, keyed
+	// by the invoking location and function name.  This is only populated
+	// when Cadence tracing is enabled, see fvm.WithContractFunctionProfiling.
+	contractFunctionInvocations map[string]uint
 }
 
 func NewProgramLogger(
@@ -136,12 +151,31 @@ func (logger *ProgramLogger) Logs() []string {
 	return logger.logs
 }
 
+// ContractFunctionInvocations returns the number of invocations of each
+// Cadence function, keyed by "<location>:<function>".  The result is nil
+// unless Cadence tracing is enabled, see fvm.WithContractFunctionProfiling.
+func (logger *ProgramLogger) ContractFunctionInvocations() map[string]uint {
+	return logger.contractFunctionInvocations
+}
+
 func (logger *ProgramLogger) RecordTrace(
 	operation string,
 	location common.Location,
 	duration time.Duration,
 	attrs []attribute.KeyValue,
 ) {
+	if location != nil && strings.HasPrefix(operation, cadenceFunctionTracePrefix) {
+		if logger.contractFunctionInvocations == nil {
+			logger.contractFunctionInvocations = make(map[string]uint)
+		}
+		name := strings.TrimPrefix(operation, cadenceFunctionTracePrefix)
+		logger.contractFunctionInvocations[location.String()+":"+name]++
+	}
+
+	if logger.CadenceTraceSpansDisabled {
+		return
+	}
+
 	if location != nil {
 		attrs = append(attrs, attribute.String("location", location.String()))
 	}
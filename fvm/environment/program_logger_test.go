@@ -0,0 +1,49 @@
+package environment_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onflow/cadence/common"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/fvm/environment"
+	"github.com/onflow/flow-go/fvm/tracing"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/module/trace"
+)
+
+func TestProgramLogger_RecordTrace(t *testing.T) {
+	location := common.AddressLocation{Name: "Foo"}
+
+	t.Run("traces are emitted as spans", func(t *testing.T) {
+		tracer := mockmodule.NewTracer(t)
+		tracer.On("StartSpanFromParent", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(trace.NoopSpan).
+			Twice()
+		logger := environment.NewProgramLogger(
+			tracing.TracerSpan{Tracer: tracer, Span: trace.NoopSpan},
+			environment.DefaultProgramLoggerParams())
+
+		logger.RecordTrace("function.bar", location, time.Millisecond, nil)
+		logger.RecordTrace("parseProgram", location, time.Millisecond, nil)
+
+		require.Equal(t, map[string]uint{location.String() + ":bar": 1}, logger.ContractFunctionInvocations())
+	})
+
+	t.Run("invocations are counted without spans", func(t *testing.T) {
+		tracer := mockmodule.NewTracer(t)
+		params := environment.DefaultProgramLoggerParams()
+		params.CadenceTraceSpansDisabled = true
+		logger := environment.NewProgramLogger(
+			tracing.TracerSpan{Tracer: tracer, Span: trace.NoopSpan},
+			params)
+
+		logger.RecordTrace("function.bar", location, time.Millisecond, nil)
+		logger.RecordTrace("function.bar", location, time.Millisecond, nil)
+		logger.RecordTrace("parseProgram", location, time.Millisecond, nil)
+
+		require.Equal(t, map[string]uint{location.String() + ":bar": 2}, logger.ContractFunctionInvocations())
+	})
+}
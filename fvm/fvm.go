@@ -36,6 +36,10 @@ type ProcedureOutput struct {
 	MemoryEstimate         uint64
 	Err                    errors.CodedError
 
+	// Only populated when Cadence tracing is enabled, see
+	// WithContractFunctionProfiling.
+	ContractFunctionInvocations map[string]uint
+
 	// Output only by script.
 	Value cadence.Value
 }
@@ -58,6 +62,7 @@ func (output *ProcedureOutput) PopulateEnvironmentValues(
 	output.MemoryEstimate = memoryUsed
 
 	output.ComputationIntensities = env.ComputationIntensities()
+	output.ContractFunctionInvocations = env.ContractFunctionInvocations()
 
 	// if tx failed this will only contain fee deduction events
 	output.Events = env.Events()
@@ -230,6 +230,23 @@ func NewCustomReusableCadenceRuntimePool(
 	)
 }
 
+// TracingEnabled returns whether the runtimes of the pool record Cadence traces.
+func (pool ReusableCadenceRuntimePool) TracingEnabled() bool {
+	return pool.config.TracingEnabled
+}
+
+// WithTracingEnabled returns a new pool of the same size and configuration as this pool, whose
+// runtimes record Cadence traces.  The runtimes of this pool are not shared with the new pool.
+func (pool ReusableCadenceRuntimePool) WithTracingEnabled() ReusableCadenceRuntimePool {
+	config := pool.config
+	config.TracingEnabled = true
+	return newReusableCadenceRuntimePool(
+		cap(pool.pool),
+		config,
+		pool.newCustomRuntime,
+	)
+}
+
 func (pool ReusableCadenceRuntimePool) newRuntime() runtime.Runtime {
 	if pool.newCustomRuntime != nil {
 		return pool.newCustomRuntime(pool.config)
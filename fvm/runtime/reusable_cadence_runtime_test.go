@@ -77,3 +77,20 @@ func TestReusableCadenceRuntimePoolSharing(t *testing.T) {
 
 	require.Same(t, entry, entry2)
 }
+
+func TestReusableCadenceRuntimePoolWithTracingEnabled(t *testing.T) {
+	pool := NewReusableCadenceRuntimePool(100, runtime.Config{})
+	require.False(t, pool.TracingEnabled())
+
+	tracingPool := pool.WithTracingEnabled()
+	require.True(t, tracingPool.TracingEnabled())
+	require.False(t, pool.TracingEnabled())
+	require.Equal(t, cap(pool.pool), cap(tracingPool.pool))
+
+	entry := tracingPool.Borrow(nil)
+	require.NotNil(t, entry)
+	tracingPool.Return(entry)
+
+	entry2 := pool.Borrow(nil)
+	require.NotSame(t, entry, entry2)
+}
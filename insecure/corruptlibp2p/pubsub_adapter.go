@@ -126,6 +126,12 @@ func (c *CorruptGossipSubAdapter) PeerScoreExposer() p2p.PeerScoreExposer {
 	return c.peerScoreExposer
 }
 
+// ScoringRegistryExposer returns the scoring registry exposer for the gossipsub adapter.
+// The corrupt gossipsub adapter does not expose its scoring registry, hence the returned exposer is nil.
+func (c *CorruptGossipSubAdapter) ScoringRegistryExposer() p2p.ScoringRegistryExposer {
+	return nil
+}
+
 func NewCorruptGossipSubAdapter(ctx context.Context,
 	logger zerolog.Logger,
 	h host.Host,
@@ -47,6 +47,7 @@ const (
 	DefaultTracing            = true
 	DefaultCadenceTracing     = false
 	DefaultExtensiveTracing   = false
+	DefaultNetworkFaults      = false
 	DefaultConsensusDelay     = 800 * time.Millisecond
 	DefaultCollectionDelay    = 950 * time.Millisecond
 )
@@ -70,6 +71,7 @@ var (
 	tracing                     bool
 	cadenceTracing              bool
 	extensiveTracing            bool
+	networkFaults               bool
 	consensusDelay              time.Duration
 	collectionDelay             time.Duration
 	logLevel                    string
@@ -96,6 +98,7 @@ func init() {
 	flag.BoolVar(&tracing, "tracing", DefaultTracing, "whether to enable low-overhead tracing in flow")
 	flag.BoolVar(&cadenceTracing, "cadence-tracing", DefaultCadenceTracing, "whether to enable the tracing in cadance")
 	flag.BoolVar(&extensiveTracing, "extensive-tracing", DefaultExtensiveTracing, "enables high-overhead tracing in fvm")
+	flag.BoolVar(&networkFaults, "network-fault-injection", DefaultNetworkFaults, "whether to enable the injection of network faults via admin commands")
 	flag.DurationVar(&consensusDelay, "consensus-delay", DefaultConsensusDelay, "delay on consensus node block proposals")
 	flag.DurationVar(&collectionDelay, "collection-delay", DefaultCollectionDelay, "delay on collection node block proposals")
 	flag.StringVar(&logLevel, "loglevel", DefaultLogLevel, "log level for all nodes")
@@ -508,6 +511,7 @@ func defaultService(name, role, dataDir, profilerDir string, i int) Service {
 			fmt.Sprintf("--profiler-enabled=%t", profiler),
 			fmt.Sprintf("--profile-uploader-enabled=%t", profileUploader),
 			fmt.Sprintf("--tracer-enabled=%t", tracing),
+			fmt.Sprintf("--network-fault-injection=%t", networkFaults),
 			"--profiler-dir=/profiler",
 			"--profiler-interval=2m",
 			fmt.Sprintf("--admin-addr=0.0.0.0:%s", testnet.AdminPort),
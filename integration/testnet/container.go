@@ -498,6 +498,11 @@ func (c *Container) GhostClient() (*ghostclient.GhostClient, error) {
 	return ghostclient.NewGhostClient(c.Addr(GRPCPort))
 }
 
+// AdminClient returns a client for the admin server of the node.
+func (c *Container) AdminClient() *client.AdminClient {
+	return client.NewAdminClient(fmt.Sprintf("localhost:%s", c.Port(AdminPort)))
+}
+
 // HealthcheckCallback returns a Docker healthcheck function that pings the node's GRPC
 // service exposed at the given port.
 func (c *Container) HealthcheckCallback() func() error {
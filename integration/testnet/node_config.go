@@ -150,6 +150,12 @@ func WithAdditionalFlagf(format string, a ...any) func(config *NodeConfig) {
 	return WithAdditionalFlag(fmt.Sprintf(format, a...))
 }
 
+// WithNetworkFaultInjection enables the injection of network faults into the messages exchanged by the node,
+// which are controlled at runtime with the network fault admin commands, see Container.AdminClient.
+func WithNetworkFaultInjection() func(config *NodeConfig) {
+	return WithAdditionalFlag("--network-fault-injection=true")
+}
+
 // WithMetricsServer exposes the metrics server
 func WithMetricsServer() func(config *NodeConfig) {
 	return func(config *NodeConfig) {
@@ -0,0 +1,61 @@
+package access
+
+import (
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// EpochTransition identifies an upcoming transition of the Epoch Preparation Protocol or the
+// switchover to the next epoch.
+type EpochTransition string
+
+const (
+	// EpochTransitionStakingAuctionEnd is the end of the staking auction, after which the
+	// EpochSetup event for the next epoch is emitted.
+	EpochTransitionStakingAuctionEnd EpochTransition = "staking_auction_end"
+	// EpochTransitionDKGPhase1End is the end of phase 1 of the DKG for the next epoch.
+	EpochTransitionDKGPhase1End EpochTransition = "dkg_phase_1_end"
+	// EpochTransitionDKGPhase2End is the end of phase 2 of the DKG for the next epoch.
+	EpochTransitionDKGPhase2End EpochTransition = "dkg_phase_2_end"
+	// EpochTransitionDKGPhase3End is the end of phase 3 of the DKG for the next epoch, after
+	// which the EpochCommit event for the next epoch is emitted.
+	EpochTransitionDKGPhase3End EpochTransition = "dkg_phase_3_end"
+	// EpochTransitionCommitDeadline is the last view by which the EpochCommit event for the next
+	// epoch must be finalized. Otherwise, epoch fallback mode is triggered.
+	EpochTransitionCommitDeadline EpochTransition = "epoch_commit_deadline"
+	// EpochTransitionSwitchover is the switchover to the next epoch. In epoch fallback mode, this
+	// is the end of the current epoch extension, unless the epoch is extended further.
+	EpochTransitionSwitchover EpochTransition = "epoch_switchover"
+)
+
+// EpochTransitionForecast is the projected wall-clock time of an upcoming epoch transition.
+type EpochTransitionForecast struct {
+	Transition EpochTransition
+	// View is the final view before the transition.
+	View uint64
+	// Time is the projected time at which the view ends.
+	Time time.Time
+	// Estimated is true if the view of the transition is not yet determined by the protocol
+	// state, but estimated from the timing of the preparation of the current epoch.
+	Estimated bool
+}
+
+// EpochForecast projects when the upcoming transitions of the current epoch will happen, as of
+// the latest finalized block.
+type EpochForecast struct {
+	EpochCounter           uint64
+	EpochPhase             flow.EpochPhase
+	EpochFallbackTriggered bool
+	// CurrentView is the view of the latest finalized block.
+	CurrentView uint64
+	// FinalView is the final view of the current epoch, including its extensions.
+	FinalView uint64
+	// TargetEndTime is the end time of the current epoch targeted by the cruise controller.
+	TargetEndTime time.Time
+	Extensions    []flow.EpochExtension
+	// ViewDuration is the projected duration of the remaining views of the epoch.
+	ViewDuration time.Duration
+	// Transitions are the upcoming transitions ordered by view.
+	Transitions []EpochTransitionForecast
+}
@@ -0,0 +1,84 @@
+package access
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// KVStoreVersionUpgradeSchedule is an upgrade of the protocol state key-value store to a new
+// version, scheduled to take effect at the given view.
+type KVStoreVersionUpgradeSchedule struct {
+	NewVersion     uint64
+	ActivationView uint64
+}
+
+// ProtocolKVStore is the contents of the protocol state key-value store as of a block.
+type ProtocolKVStore struct {
+	ID      flow.Identifier
+	Version uint64
+	// VersionUpgrade is the most recently scheduled version upgrade, nil if none has been
+	// scheduled. It is retained after the upgrade took effect, until a newer upgrade is scheduled.
+	VersionUpgrade              *KVStoreVersionUpgradeSchedule
+	EpochStateID                flow.Identifier
+	EpochExtensionViewCount     uint64
+	FinalizationSafetyThreshold uint64
+	// Data is the complete key-value store in the encoding of its version.
+	Data []byte
+}
+
+// ProtocolStateAtHeight is the protocol state as of the finalized block at the given height.
+type ProtocolStateAtHeight struct {
+	Height     uint64
+	BlockID    flow.Identifier
+	View       uint64
+	KVStore    ProtocolKVStore
+	EpochPhase flow.EpochPhase
+	EpochState *flow.RichEpochStateEntry
+}
+
+// IdentityChangeType is the kind of change of a node's entry in the identity table.
+type IdentityChangeType string
+
+const (
+	IdentityAdded   IdentityChangeType = "added"
+	IdentityRemoved IdentityChangeType = "removed"
+	IdentityChanged IdentityChangeType = "changed"
+)
+
+// IdentityChange is a change of a node's entry in the identity table of the current epoch.
+type IdentityChange struct {
+	NodeID flow.Identifier
+	Type   IdentityChangeType
+	// Before is the node's identity at the start height, nil if the node was added.
+	Before *flow.Identity
+	// After is the node's identity at the end height, nil if the node was removed.
+	After *flow.Identity
+}
+
+// KVStoreVersionUpgrade is an upgrade of the protocol state key-value store, which took effect
+// at the finalized block with the given height.
+type KVStoreVersionUpgrade struct {
+	Height      uint64
+	BlockID     flow.Identifier
+	View        uint64
+	FromVersion uint64
+	ToVersion   uint64
+}
+
+// ProtocolStateDiff is the difference between the protocol states of two finalized blocks.
+type ProtocolStateDiff struct {
+	StartHeight                 uint64
+	EndHeight                   uint64
+	StartEpochCounter           uint64
+	EndEpochCounter             uint64
+	StartEpochPhase             flow.EpochPhase
+	EndEpochPhase               flow.EpochPhase
+	StartEpochFallbackTriggered bool
+	EndEpochFallbackTriggered   bool
+	// IdentityChanges are the changes of the current epoch's identity table, ordered by node ID.
+	IdentityChanges []IdentityChange
+	// Ejections are the nodes which were ejected between the two blocks, ordered by node ID.
+	Ejections []flow.Identifier
+	// VersionUpgrades are the upgrades of the key-value store between the two blocks, in the
+	// order they took effect.
+	VersionUpgrades []KVStoreVersionUpgrade
+}
@@ -0,0 +1,107 @@
+package dkg
+
+import (
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// PhaseTransition records when the local DKG instance entered a phase.
+type PhaseTransition struct {
+	Phase string
+	Time  time.Time
+}
+
+// Complaint is a complaint raised by the local DKG instance against another participant.
+type Complaint struct {
+	Phase   string
+	Accused flow.Identifier
+	Reason  string
+	// Disqualified is true if the local instance disqualified the accused participant, rather
+	// than only flagging its misbehaviour.
+	Disqualified bool
+	Time         time.Time
+}
+
+// DealerStatus is the progress of a single DKG participant, as observed by the local instance.
+type DealerStatus struct {
+	NodeID flow.Identifier
+	Index  int
+	// BroadcastMessages is the number of broadcast messages received from the participant via
+	// the DKG smart contract, per phase.
+	BroadcastMessages map[string]uint64
+	// PrivateMessages is the number of private messages received from the participant.
+	PrivateMessages uint64
+}
+
+// PrivateShareReceived returns true if a private message, carrying the local participant's share
+// of the dealer's secret, has been received from the dealer.
+func (d *DealerStatus) PrivateShareReceived() bool {
+	return d.PrivateMessages > 0
+}
+
+// Result is the outcome of the local DKG instance.
+type Result string
+
+const (
+	ResultPending Result = "pending"
+	ResultSuccess Result = "success"
+	ResultFailure Result = "failure"
+)
+
+// Status records the progress of the local DKG instance preparing the given epoch: the phase
+// transitions, the messages seen per dealer, the complaints raised and the final result.
+type Status struct {
+	// EpochCounter is the counter of the epoch the DKG is preparing.
+	EpochCounter uint64
+	InstanceID   string
+	MyIndex      int
+	Phase        string
+	Transitions  []PhaseTransition
+	Dealers      []*DealerStatus
+	// BroadcastsSent is the number of broadcast messages sent by the local instance.
+	BroadcastsSent uint64
+	Complaints     []Complaint
+	Result         Result
+	// FailureReason describes why the local DKG instance failed, empty unless Result is ResultFailure.
+	FailureReason string
+	// ResultSubmitted is true once the local result has been published to the DKG smart contract.
+	ResultSubmitted bool
+	LastUpdate      time.Time
+}
+
+// NewStatus returns the initial status of a DKG instance with the given committee.
+func NewStatus(epochCounter uint64, instanceID string, committee flow.IdentitySkeletonList, myIndex int) *Status {
+	dealers := make([]*DealerStatus, 0, len(committee))
+	for i, participant := range committee {
+		dealers = append(dealers, &DealerStatus{
+			NodeID:            participant.NodeID,
+			Index:             i,
+			BroadcastMessages: make(map[string]uint64),
+		})
+	}
+	return &Status{
+		EpochCounter: epochCounter,
+		InstanceID:   instanceID,
+		MyIndex:      myIndex,
+		Dealers:      dealers,
+		Result:       ResultPending,
+	}
+}
+
+// Copy returns a deep copy of the status.
+func (s *Status) Copy() *Status {
+	cpy := *s
+	cpy.Transitions = append([]PhaseTransition(nil), s.Transitions...)
+	cpy.Complaints = append([]Complaint(nil), s.Complaints...)
+	cpy.Dealers = make([]*DealerStatus, 0, len(s.Dealers))
+	for _, dealer := range s.Dealers {
+		dealerCpy := *dealer
+		dealerCpy.BroadcastMessages = make(map[string]uint64, len(dealer.BroadcastMessages))
+		for phase, count := range dealer.BroadcastMessages {
+			dealerCpy.BroadcastMessages[phase] = count
+		}
+		cpy.Dealers = append(cpy.Dealers, &dealerCpy)
+	}
+	return &cpy
+}
@@ -126,9 +126,12 @@ func (h Header) MarshalJSON() ([]byte, error) {
 func (h *Header) UnmarshalJSON(data []byte) error {
 
 	// we use an alias to avoid endless recursion; the alias will not have the
-	// unmarshal function and decode like a raw header
-	type Decodable *Header
-	err := json.Unmarshal(data, Decodable(h))
+	// unmarshal function and decode like a raw header. The alias must not be a
+	// pointer type, as the decoder would resolve it to *Header again.
+	type Decodable Header
+	var decodable Decodable
+	err := json.Unmarshal(data, &decodable)
+	*h = Header(decodable)
 
 	// NOTE: the timezone check is not required for JSON, as it already encodes
 	// timezones, but it doesn't hurt to add it in case someone messes with the
@@ -30,6 +30,29 @@ func TestHeaderEncodingJSON(t *testing.T) {
 	assert.Equal(t, *header, decoded)
 }
 
+// TestHeaderDecodingJSON_Nested is a regression test for Header.UnmarshalJSON, which
+// used to decode through a named pointer type to *Header. The JSON decoder resolves such
+// a pointer to *Header again and calls UnmarshalJSON recursively until the stack
+// overflows. Headers nested in other structures, such as slashing evidence, are decoded
+// the same way.
+func TestHeaderDecodingJSON_Nested(t *testing.T) {
+	type container struct {
+		Headers []*flow.Header
+	}
+	headers := []*flow.Header{unittest.BlockHeaderFixture(), unittest.BlockHeaderFixture()}
+	data, err := json.Marshal(container{Headers: headers})
+	require.NoError(t, err)
+
+	var decoded container
+	err = json.Unmarshal(data, &decoded)
+	require.NoError(t, err)
+	require.Len(t, decoded.Headers, len(headers))
+	for i, header := range headers {
+		assert.Equal(t, header.ID(), decoded.Headers[i].ID())
+		assert.Equal(t, time.UTC, decoded.Headers[i].Timestamp.Location())
+	}
+}
+
 func TestHeaderFingerprint(t *testing.T) {
 	header := unittest.BlockHeaderFixture()
 	header.LastViewTC = helper.MakeTC()
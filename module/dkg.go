@@ -3,6 +3,7 @@ package module
 import (
 	"github.com/onflow/crypto"
 
+	"github.com/onflow/flow-go/model/dkg"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/messages"
 )
@@ -102,3 +103,15 @@ type DKGControllerFactory interface {
 	// Create instantiates a new DKGController.
 	Create(dkgInstanceID string, participants flow.IdentitySkeletonList, seed []byte) (DKGController, error)
 }
+
+// DKGStatusProvider reports the progress of the local DKG instances, for diagnostics.
+type DKGStatusProvider interface {
+
+	// DKGStatus returns the status of the DKG preparing the given epoch, and false if no DKG
+	// was run by this node for the epoch.
+	DKGStatus(epochCounter uint64) (*dkg.Status, bool)
+
+	// LatestDKGStatus returns the status of the most recent DKG run by this node, and false if
+	// this node has not run any DKG yet.
+	LatestDKGStatus() (*dkg.Status, bool)
+}
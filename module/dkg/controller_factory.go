@@ -21,6 +21,18 @@ type ControllerFactory struct {
 	me                 module.Local
 	dkgContractClients []module.DKGContractClient
 	tunnel             *BrokerTunnel
+	tracker            *StatusTracker
+}
+
+// ControllerFactoryOpt is a functional option for the ControllerFactory.
+type ControllerFactoryOpt func(*ControllerFactory)
+
+// WithStatusTracker hooks the given status tracker into the DKG instances
+// created by the factory, so their progress is recorded.
+func WithStatusTracker(tracker *StatusTracker) ControllerFactoryOpt {
+	return func(f *ControllerFactory) {
+		f.tracker = tracker
+	}
 }
 
 // NewControllerFactory creates a new factory that generates Controllers with
@@ -30,14 +42,19 @@ func NewControllerFactory(
 	me module.Local,
 	dkgContractClients []module.DKGContractClient,
 	tunnel *BrokerTunnel,
+	opts ...ControllerFactoryOpt,
 ) *ControllerFactory {
 
-	return &ControllerFactory{
+	f := &ControllerFactory{
 		log:                log,
 		me:                 me,
 		dkgContractClients: dkgContractClients,
 		tunnel:             tunnel,
 	}
+	for _, apply := range opts {
+		apply(f)
+	}
+	return f
 }
 
 // Create creates a new epoch-specific Controller equipped with a broker which
@@ -59,6 +76,7 @@ func (f *ControllerFactory) Create(
 		return nil, fmt.Errorf("failed to create controller factory, node %s is not part of DKG committee", f.me.NodeID().String())
 	}
 
+	var broker module.DKGBroker
 	broker, err := NewBroker(
 		f.log,
 		dkgInstanceID,
@@ -71,6 +89,13 @@ func (f *ControllerFactory) Create(
 	if err != nil {
 		return nil, fmt.Errorf("could not create DKG broker: %w", err)
 	}
+	if f.tracker != nil {
+		err = f.tracker.track(dkgInstanceID, participants, int(myIndex))
+		if err != nil {
+			return nil, err
+		}
+		broker = &trackedBroker{DKGBroker: broker, tracker: f.tracker, dkgInstanceID: dkgInstanceID}
+	}
 
 	n := len(participants)
 	threshold := signature.RandomBeaconThreshold(n)
@@ -78,6 +103,9 @@ func (f *ControllerFactory) Create(
 	if err != nil {
 		return nil, err
 	}
+	if f.tracker != nil {
+		dkg = &trackedDKGState{DKGState: dkg, tracker: f.tracker, dkgInstanceID: dkgInstanceID}
+	}
 
 	controller := NewController(
 		f.log,
@@ -2,6 +2,8 @@ package dkg
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/onflow/flow-go/model/flow"
 )
@@ -11,3 +13,18 @@ import (
 func CanonicalInstanceID(chainID flow.ChainID, epochCounter uint64) string {
 	return fmt.Sprintf("dkg-%s-%d", chainID.String(), epochCounter)
 }
+
+// EpochCounterOfInstanceID returns the epoch counter of the given canonical
+// DKG instance ID.
+// No errors are expected for instance IDs created by CanonicalInstanceID.
+func EpochCounterOfInstanceID(dkgInstanceID string) (uint64, error) {
+	separator := strings.LastIndex(dkgInstanceID, "-")
+	if !strings.HasPrefix(dkgInstanceID, "dkg-") || separator < len("dkg-") {
+		return 0, fmt.Errorf("invalid dkg instance id %q", dkgInstanceID)
+	}
+	epochCounter, err := strconv.ParseUint(dkgInstanceID[separator+1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid epoch counter in dkg instance id %q: %w", dkgInstanceID, err)
+	}
+	return epochCounter, nil
+}
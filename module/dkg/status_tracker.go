@@ -0,0 +1,306 @@
+package dkg
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onflow/crypto"
+	"github.com/rs/zerolog"
+
+	dkgmodel "github.com/onflow/flow-go/model/dkg"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/storage"
+)
+
+const (
+	messageTypeBroadcast = "broadcast"
+	messageTypePrivate   = "private"
+)
+
+// StatusTracker records the progress of the local DKG instances: the phase transitions, the
+// messages received per dealer, the complaints raised against other participants and the final
+// result. It is hooked into the DKG instances created by the ControllerFactory (see
+// WithStatusTracker), reports the progress as metrics, and persists the status of each epoch's
+// DKG, so the history is retained across restarts.
+//
+// Message counts are persisted along with the next phase transition, complaint or result.
+type StatusTracker struct {
+	log     zerolog.Logger
+	metrics module.DKGMetrics
+	store   storage.DKGStatuses
+
+	mu sync.Mutex
+	// statuses holds the status of each epoch's DKG by epoch counter
+	statuses map[uint64]*dkgmodel.Status
+	// instances maps the instance ID of each tracked DKG instance to its epoch counter
+	instances map[string]uint64
+}
+
+var _ module.DKGStatusProvider = (*StatusTracker)(nil)
+
+// NewStatusTracker creates a DKG status tracker, which includes the statuses already persisted
+// in the given store.
+// No errors are expected during normal operation.
+func NewStatusTracker(log zerolog.Logger, metrics module.DKGMetrics, store storage.DKGStatuses) (*StatusTracker, error) {
+	persisted, err := store.All()
+	if err != nil {
+		return nil, fmt.Errorf("could not load persisted dkg statuses: %w", err)
+	}
+
+	t := &StatusTracker{
+		log:       log.With().Str("component", "dkg_status_tracker").Logger(),
+		metrics:   metrics,
+		store:     store,
+		statuses:  make(map[uint64]*dkgmodel.Status, len(persisted)),
+		instances: make(map[string]uint64),
+	}
+	for _, status := range persisted {
+		t.statuses[status.EpochCounter] = status
+	}
+	return t, nil
+}
+
+// DKGStatus returns the status of the DKG preparing the given epoch, and false if no DKG was
+// run by this node for the epoch.
+func (t *StatusTracker) DKGStatus(epochCounter uint64) (*dkgmodel.Status, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	status, ok := t.statuses[epochCounter]
+	if !ok {
+		return nil, false
+	}
+	return status.Copy(), true
+}
+
+// LatestDKGStatus returns the status of the most recent DKG run by this node, and false if this
+// node has not run any DKG yet.
+func (t *StatusTracker) LatestDKGStatus() (*dkgmodel.Status, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	counters := make([]uint64, 0, len(t.statuses))
+	for counter := range t.statuses {
+		counters = append(counters, counter)
+	}
+	if len(counters) == 0 {
+		return nil, false
+	}
+	sort.Slice(counters, func(i, j int) bool { return counters[i] < counters[j] })
+	return t.statuses[counters[len(counters)-1]].Copy(), true
+}
+
+// track starts tracking the DKG instance with the given canonical instance ID, replacing any
+// previous status of the instance's epoch.
+// No errors are expected for instance IDs created by CanonicalInstanceID.
+func (t *StatusTracker) track(dkgInstanceID string, committee flow.IdentitySkeletonList, myIndex int) error {
+	epochCounter, err := EpochCounterOfInstanceID(dkgInstanceID)
+	if err != nil {
+		return fmt.Errorf("could not track dkg instance: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	status := dkgmodel.NewStatus(epochCounter, dkgInstanceID, committee, myIndex)
+	status.Phase = Init.String()
+	status.LastUpdate = time.Now()
+	t.statuses[epochCounter] = status
+	t.instances[dkgInstanceID] = epochCounter
+	t.persist(status)
+	return nil
+}
+
+// phaseStarted records that the given DKG instance entered the given phase.
+func (t *StatusTracker) phaseStarted(dkgInstanceID string, phase State) {
+	t.update(dkgInstanceID, true, func(status *dkgmodel.Status) {
+		status.Phase = phase.String()
+		status.Transitions = append(status.Transitions, dkgmodel.PhaseTransition{Phase: phase.String(), Time: status.LastUpdate})
+	})
+	t.metrics.DKGPhase(uint32(phase))
+}
+
+// nextPhaseStarted records that the given DKG instance entered the phase following its current phase.
+func (t *StatusTracker) nextPhaseStarted(dkgInstanceID string) {
+	next := Phase2
+	t.mu.Lock()
+	if epochCounter, ok := t.instances[dkgInstanceID]; ok && t.statuses[epochCounter].Phase == Phase2.String() {
+		next = Phase3
+	}
+	t.mu.Unlock()
+	t.phaseStarted(dkgInstanceID, next)
+}
+
+// messageReceived records a broadcast or private message received from the given dealer.
+func (t *StatusTracker) messageReceived(dkgInstanceID string, messageType string, dealer int) {
+	t.update(dkgInstanceID, false, func(status *dkgmodel.Status) {
+		if dealer < 0 || dealer >= len(status.Dealers) {
+			return
+		}
+		if messageType == messageTypePrivate {
+			status.Dealers[dealer].PrivateMessages++
+		} else {
+			status.Dealers[dealer].BroadcastMessages[status.Phase]++
+		}
+	})
+	t.metrics.DKGMessageReceived(messageType)
+}
+
+// broadcastSent records a broadcast message sent by the given DKG instance.
+func (t *StatusTracker) broadcastSent(dkgInstanceID string) {
+	t.update(dkgInstanceID, false, func(status *dkgmodel.Status) {
+		status.BroadcastsSent++
+	})
+}
+
+// complaint records a complaint of the given DKG instance against the given participant.
+func (t *StatusTracker) complaint(dkgInstanceID string, accused int, reason string, disqualified bool) {
+	t.update(dkgInstanceID, true, func(status *dkgmodel.Status) {
+		var accusedID flow.Identifier
+		if accused >= 0 && accused < len(status.Dealers) {
+			accusedID = status.Dealers[accused].NodeID
+		}
+		status.Complaints = append(status.Complaints, dkgmodel.Complaint{
+			Phase:        status.Phase,
+			Accused:      accusedID,
+			Reason:       reason,
+			Disqualified: disqualified,
+			Time:         status.LastUpdate,
+		})
+	})
+	t.metrics.DKGComplaint(disqualified)
+}
+
+// ended records the outcome of the given DKG instance, where a non-nil error indicates that
+// the instance failed to compute its key share.
+func (t *StatusTracker) ended(dkgInstanceID string, err error) {
+	result := dkgmodel.ResultSuccess
+	if err != nil {
+		result = dkgmodel.ResultFailure
+	}
+	t.update(dkgInstanceID, true, func(status *dkgmodel.Status) {
+		status.Phase = End.String()
+		status.Transitions = append(status.Transitions, dkgmodel.PhaseTransition{Phase: End.String(), Time: status.LastUpdate})
+		status.Result = result
+		if err != nil {
+			status.FailureReason = err.Error()
+		}
+	})
+	t.metrics.DKGPhase(uint32(End))
+	t.metrics.DKGResult(string(result))
+}
+
+// resultSubmitted records that the given DKG instance published its result to the DKG smart contract.
+func (t *StatusTracker) resultSubmitted(dkgInstanceID string) {
+	t.update(dkgInstanceID, true, func(status *dkgmodel.Status) {
+		status.ResultSubmitted = true
+	})
+}
+
+// update applies the given function to the status of the given DKG instance, and persists the
+// updated status if requested. Events of untracked instances are ignored.
+func (t *StatusTracker) update(dkgInstanceID string, persist bool, apply func(status *dkgmodel.Status)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	epochCounter, ok := t.instances[dkgInstanceID]
+	if !ok {
+		return
+	}
+	status := t.statuses[epochCounter]
+	status.LastUpdate = time.Now()
+	apply(status)
+	if persist {
+		t.persist(status)
+	}
+}
+
+// persist stores the given status. As the status is purely diagnostic, failures are logged
+// rather than escalated.
+// Caller must hold the lock.
+func (t *StatusTracker) persist(status *dkgmodel.Status) {
+	err := t.store.Store(status)
+	if err != nil {
+		t.log.Error().Err(err).Uint64("epoch_counter", status.EpochCounter).Msg("could not persist dkg status")
+	}
+}
+
+// trackedBroker reports the messages sent, the complaints raised and the result submitted by a
+// DKG instance to the status tracker.
+type trackedBroker struct {
+	module.DKGBroker
+	tracker       *StatusTracker
+	dkgInstanceID string
+}
+
+var _ module.DKGBroker = (*trackedBroker)(nil)
+
+func (b *trackedBroker) Broadcast(data []byte) {
+	b.tracker.broadcastSent(b.dkgInstanceID)
+	b.DKGBroker.Broadcast(data)
+}
+
+func (b *trackedBroker) Disqualify(index int, log string) {
+	b.tracker.complaint(b.dkgInstanceID, index, log, true)
+	b.DKGBroker.Disqualify(index, log)
+}
+
+func (b *trackedBroker) FlagMisbehavior(index int, log string) {
+	b.tracker.complaint(b.dkgInstanceID, index, log, false)
+	b.DKGBroker.FlagMisbehavior(index, log)
+}
+
+func (b *trackedBroker) SubmitResult(groupKey crypto.PublicKey, pubKeys []crypto.PublicKey) error {
+	err := b.DKGBroker.SubmitResult(groupKey, pubKeys)
+	if err != nil {
+		return err
+	}
+	b.tracker.resultSubmitted(b.dkgInstanceID)
+	return nil
+}
+
+// trackedDKGState reports the phase transitions, the messages received and the outcome of a DKG
+// instance to the status tracker.
+type trackedDKGState struct {
+	crypto.DKGState
+	tracker       *StatusTracker
+	dkgInstanceID string
+}
+
+var _ crypto.DKGState = (*trackedDKGState)(nil)
+
+func (s *trackedDKGState) Start(seed []byte) error {
+	err := s.DKGState.Start(seed)
+	if err != nil {
+		return err
+	}
+	s.tracker.phaseStarted(s.dkgInstanceID, Phase1)
+	return nil
+}
+
+func (s *trackedDKGState) NextTimeout() error {
+	err := s.DKGState.NextTimeout()
+	if err != nil {
+		return err
+	}
+	s.tracker.nextPhaseStarted(s.dkgInstanceID)
+	return nil
+}
+
+func (s *trackedDKGState) HandleBroadcastMsg(orig int, msg []byte) error {
+	s.tracker.messageReceived(s.dkgInstanceID, messageTypeBroadcast, orig)
+	return s.DKGState.HandleBroadcastMsg(orig, msg)
+}
+
+func (s *trackedDKGState) HandlePrivateMsg(orig int, msg []byte) error {
+	s.tracker.messageReceived(s.dkgInstanceID, messageTypePrivate, orig)
+	return s.DKGState.HandlePrivateMsg(orig, msg)
+}
+
+func (s *trackedDKGState) End() (crypto.PrivateKey, crypto.PublicKey, []crypto.PublicKey, error) {
+	privateShare, groupKey, pubKeys, err := s.DKGState.End()
+	s.tracker.ended(s.dkgInstanceID, err)
+	return privateShare, groupKey, pubKeys, err
+}
@@ -0,0 +1,109 @@
+package dkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/onflow/crypto"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	dkgmodel "github.com/onflow/flow-go/model/dkg"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	module "github.com/onflow/flow-go/module/mock"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestStatusTracker verifies that the tracker records the progress of a DKG instance reported by
+// the tracked broker and DKG state, persists it and retains the history of previous epochs.
+func TestStatusTracker(t *testing.T) {
+	committee := unittest.IdentityListFixture(3).Sort(flow.Canonical[flow.Identity]).ToSkeleton()
+	previous := dkgmodel.NewStatus(1, CanonicalInstanceID(flow.Testnet, 1), committee, 0)
+	previous.Result = dkgmodel.ResultSuccess
+
+	store := storagemock.NewDKGStatuses(t)
+	store.On("All").Return([]*dkgmodel.Status{previous}, nil)
+	var persisted *dkgmodel.Status
+	store.On("Store", mock.Anything).Run(func(args mock.Arguments) {
+		persisted = args.Get(0).(*dkgmodel.Status).Copy()
+	}).Return(nil)
+
+	tracker, err := NewStatusTracker(zerolog.Nop(), metrics.NewNoopCollector(), store)
+	require.NoError(t, err)
+
+	latest, ok := tracker.LatestDKGStatus()
+	require.True(t, ok)
+	assert.Equal(t, previous, latest)
+	_, ok = tracker.DKGStatus(2)
+	assert.False(t, ok)
+
+	dkgInstanceID := CanonicalInstanceID(flow.Testnet, 2)
+	require.NoError(t, tracker.track(dkgInstanceID, committee, 1))
+
+	innerBroker := module.NewDKGBroker(t)
+	innerBroker.On("Broadcast", mock.Anything).Return()
+	innerBroker.On("FlagMisbehavior", 2, "invalid share").Return()
+	innerBroker.On("SubmitResult", mock.Anything, mock.Anything).Return(nil)
+	broker := &trackedBroker{DKGBroker: innerBroker, tracker: tracker, dkgInstanceID: dkgInstanceID}
+	state := &trackedDKGState{DKGState: &stubDKGState{endErr: errors.New("not enough shares")}, tracker: tracker, dkgInstanceID: dkgInstanceID}
+
+	require.NoError(t, state.Start(nil))
+	require.NoError(t, state.HandleBroadcastMsg(0, nil))
+	require.NoError(t, state.HandlePrivateMsg(0, nil))
+	broker.Broadcast(nil)
+	require.NoError(t, state.NextTimeout())
+	require.NoError(t, state.HandleBroadcastMsg(0, nil))
+	broker.FlagMisbehavior(2, "invalid share")
+	require.NoError(t, state.NextTimeout())
+	_, _, _, err = state.End()
+	require.Error(t, err)
+	require.NoError(t, broker.SubmitResult(nil, nil))
+
+	status, ok := tracker.DKGStatus(2)
+	require.True(t, ok)
+	assert.Equal(t, dkgInstanceID, status.InstanceID)
+	assert.Equal(t, 1, status.MyIndex)
+	assert.Equal(t, End.String(), status.Phase)
+	phases := make([]string, 0, len(status.Transitions))
+	for _, transition := range status.Transitions {
+		phases = append(phases, transition.Phase)
+	}
+	assert.Equal(t, []string{Phase1.String(), Phase2.String(), Phase3.String(), End.String()}, phases)
+	assert.Equal(t, map[string]uint64{Phase1.String(): 1, Phase2.String(): 1}, status.Dealers[0].BroadcastMessages)
+	assert.True(t, status.Dealers[0].PrivateShareReceived())
+	assert.False(t, status.Dealers[2].PrivateShareReceived())
+	assert.Equal(t, uint64(1), status.BroadcastsSent)
+	require.Len(t, status.Complaints, 1)
+	assert.Equal(t, committee[2].NodeID, status.Complaints[0].Accused)
+	assert.Equal(t, Phase2.String(), status.Complaints[0].Phase)
+	assert.False(t, status.Complaints[0].Disqualified)
+	assert.Equal(t, dkgmodel.ResultFailure, status.Result)
+	assert.Equal(t, "not enough shares", status.FailureReason)
+	assert.True(t, status.ResultSubmitted)
+	assert.Equal(t, status, persisted)
+
+	latest, ok = tracker.LatestDKGStatus()
+	require.True(t, ok)
+	assert.Equal(t, uint64(2), latest.EpochCounter)
+	previousStatus, ok := tracker.DKGStatus(1)
+	require.True(t, ok)
+	assert.Equal(t, previous, previousStatus)
+}
+
+// stubDKGState is a DKG state machine accepting all inputs, whose End returns the configured error.
+type stubDKGState struct {
+	crypto.DKGState
+	endErr error
+}
+
+func (s *stubDKGState) Start([]byte) error                   { return nil }
+func (s *stubDKGState) NextTimeout() error                   { return nil }
+func (s *stubDKGState) HandleBroadcastMsg(int, []byte) error { return nil }
+func (s *stubDKGState) HandlePrivateMsg(int, []byte) error   { return nil }
+func (s *stubDKGState) End() (crypto.PrivateKey, crypto.PublicKey, []crypto.PublicKey, error) {
+	return nil, nil, nil, s.endErr
+}
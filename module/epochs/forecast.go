@@ -0,0 +1,128 @@
+package epochs
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/cruisectl"
+	accessmodel "github.com/onflow/flow-go/model/access"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+)
+
+// ForecastEpoch projects the wall-clock times of the upcoming transitions of the current epoch, as
+// of the given snapshot and the current time. The projection follows the cruise controller, which
+// steers the view rate such that the epoch ends at its target end time, within its limits of
+// authority on the view duration. Hence, the remaining views of the epoch are projected to proceed
+// at the constant rate reaching the target end time, constrained by the given timing config.
+//
+// The views of the DKG phases are determined by the next epoch's EpochSetup event. Before the
+// event is sealed, i.e. during the staking phase, the views of the staking auction end and the
+// DKG phases are estimated assuming the same timing relative to the epoch switchover as for the
+// preparation of the current epoch.
+// No errors are expected during normal operation.
+func ForecastEpoch(snapshot protocol.Snapshot, timing *cruisectl.TimingConfig, now time.Time) (*accessmodel.EpochForecast, error) {
+	head, err := snapshot.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get snapshot head: %w", err)
+	}
+	epochState, err := snapshot.EpochProtocolState()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch protocol state: %w", err)
+	}
+	kvstore, err := snapshot.ProtocolState()
+	if err != nil {
+		return nil, fmt.Errorf("could not get protocol state: %w", err)
+	}
+	current, err := snapshot.Epochs().Current()
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch: %w", err)
+	}
+
+	forecast := &accessmodel.EpochForecast{
+		EpochCounter:           current.Counter(),
+		EpochPhase:             epochState.EpochPhase(),
+		EpochFallbackTriggered: epochState.EpochFallbackTriggered(),
+		CurrentView:            head.View,
+		FinalView:              current.FinalView(),
+		TargetEndTime:          time.Unix(int64(current.TargetEndTime()), 0).UTC(),
+		Extensions:             epochState.EpochExtensions(),
+	}
+	remainingViews := current.FinalView() + 1 - head.View
+	forecast.ViewDuration = projectedViewDuration(forecast.TargetEndTime.Sub(now), remainingViews, timing)
+
+	var transitions []accessmodel.EpochTransitionForecast
+	add := func(transition accessmodel.EpochTransition, view uint64, estimated bool) {
+		if view < head.View {
+			return
+		}
+		transitions = append(transitions, accessmodel.EpochTransitionForecast{
+			Transition: transition,
+			View:       view,
+			Time:       now.Add(time.Duration(view+1-head.View) * forecast.ViewDuration),
+			Estimated:  estimated,
+		})
+	}
+
+	entry := epochState.Entry()
+	switch forecast.EpochPhase {
+	case flow.EpochPhaseStaking:
+		estimateEpochPreparation(entry.CurrentEpochSetup, add)
+	case flow.EpochPhaseSetup:
+		add(accessmodel.EpochTransitionDKGPhase1End, entry.NextEpochSetup.DKGPhase1FinalView, false)
+		add(accessmodel.EpochTransitionDKGPhase2End, entry.NextEpochSetup.DKGPhase2FinalView, false)
+		add(accessmodel.EpochTransitionDKGPhase3End, entry.NextEpochSetup.DKGPhase3FinalView, false)
+	}
+	if forecast.EpochPhase == flow.EpochPhaseStaking || forecast.EpochPhase == flow.EpochPhaseSetup {
+		threshold := kvstore.GetFinalizationSafetyThreshold()
+		if current.FinalView() > threshold {
+			add(accessmodel.EpochTransitionCommitDeadline, current.FinalView()-threshold-1, false)
+		}
+	}
+	add(accessmodel.EpochTransitionSwitchover, current.FinalView(), false)
+
+	sort.SliceStable(transitions, func(i, j int) bool {
+		return transitions[i].View < transitions[j].View
+	})
+	forecast.Transitions = transitions
+	return forecast, nil
+}
+
+// estimateEpochPreparation estimates the views of the staking auction end and the DKG phases for
+// the next epoch, assuming the same timing relative to the epoch switchover as for the preparation
+// of the current epoch, whose DKG phase views precede the current epoch's first view. No estimates
+// are added if the current epoch's DKG phase views are inconsistent with this assumption, which is
+// the case for the first epoch after a spork.
+func estimateEpochPreparation(setup *flow.EpochSetup, add func(accessmodel.EpochTransition, uint64, bool)) {
+	if setup.DKGPhase1FinalView >= setup.DKGPhase2FinalView ||
+		setup.DKGPhase2FinalView >= setup.DKGPhase3FinalView ||
+		setup.DKGPhase3FinalView >= setup.FirstView {
+		return
+	}
+	phaseLength := setup.DKGPhase2FinalView - setup.DKGPhase1FinalView
+	untilSwitchover := setup.FirstView - setup.DKGPhase1FinalView + phaseLength
+	nextFirstView := setup.FinalView + 1
+	if untilSwitchover >= nextFirstView {
+		return
+	}
+
+	stakingAuctionEnd := nextFirstView - untilSwitchover
+	add(accessmodel.EpochTransitionStakingAuctionEnd, stakingAuctionEnd, true)
+	add(accessmodel.EpochTransitionDKGPhase1End, stakingAuctionEnd+phaseLength, true)
+	add(accessmodel.EpochTransitionDKGPhase2End, nextFirstView-(setup.FirstView-setup.DKGPhase2FinalView), true)
+	add(accessmodel.EpochTransitionDKGPhase3End, nextFirstView-(setup.FirstView-setup.DKGPhase3FinalView), true)
+}
+
+// projectedViewDuration returns the view duration the cruise controller converges to for reaching
+// the target end time in the given remaining time and views, constrained by its limits of authority.
+func projectedViewDuration(remainingTime time.Duration, remainingViews uint64, timing *cruisectl.TimingConfig) time.Duration {
+	duration := remainingTime / time.Duration(remainingViews)
+	if duration < timing.MinViewDuration.Load() {
+		return timing.MinViewDuration.Load()
+	}
+	if duration > timing.MaxViewDuration.Load() {
+		return timing.MaxViewDuration.Load()
+	}
+	return duration
+}
@@ -0,0 +1,119 @@
+package epochs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/cruisectl"
+	accessmodel "github.com/onflow/flow-go/model/access"
+	"github.com/onflow/flow-go/model/flow"
+	mockprotocol "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// forecastSnapshot mocks a snapshot at the given view of an epoch with views [1000, finalView],
+// whose preparation had a staking auction ending at view 899 and DKG phases of 30 views each.
+func forecastSnapshot(t *testing.T, view uint64, phase flow.EpochPhase, finalView uint64, targetEndTime time.Time, entry *flow.RichEpochStateEntry) *mockprotocol.Snapshot {
+	entry.CurrentEpochSetup = unittest.EpochSetupFixture(unittest.WithFirstView(1000), unittest.WithFinalView(1999))
+	entry.CurrentEpochSetup.DKGPhase1FinalView = 929
+	entry.CurrentEpochSetup.DKGPhase2FinalView = 959
+	entry.CurrentEpochSetup.DKGPhase3FinalView = 989
+
+	epochState := mockprotocol.NewEpochProtocolState(t)
+	epochState.On("EpochPhase").Return(phase)
+	epochState.On("EpochFallbackTriggered").Return(phase == flow.EpochPhaseFallback)
+	epochState.On("EpochExtensions").Return(entry.CurrentEpoch.EpochExtensions)
+	epochState.On("Entry").Return(entry)
+	kvstore := mockprotocol.NewKVStoreReader(t)
+	kvstore.On("GetFinalizationSafetyThreshold").Return(uint64(5)).Maybe()
+	current := mockprotocol.NewCommittedEpoch(t)
+	current.On("Counter").Return(entry.CurrentEpochSetup.Counter)
+	current.On("FinalView").Return(finalView)
+	current.On("TargetEndTime").Return(uint64(targetEndTime.Unix()))
+	epochs := mockprotocol.NewEpochQuery(t)
+	epochs.On("Current").Return(current, nil)
+
+	snapshot := mockprotocol.NewSnapshot(t)
+	snapshot.On("Head").Return(unittest.BlockHeaderFixture(unittest.HeaderWithView(view)), nil)
+	snapshot.On("EpochProtocolState").Return(epochState, nil)
+	snapshot.On("ProtocolState").Return(kvstore, nil)
+	snapshot.On("Epochs").Return(epochs)
+	return snapshot
+}
+
+// TestForecastEpoch verifies the projected transitions in the different epoch phases.
+func TestForecastEpoch(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0).UTC()
+	timing := &cruisectl.TimingConfig{
+		MinViewDuration: atomic.NewDuration(100 * time.Millisecond),
+		MaxViewDuration: atomic.NewDuration(2 * time.Second),
+	}
+	at := func(view uint64) time.Time {
+		return now.Add(time.Duration(view+1-1500) * time.Second)
+	}
+
+	t.Run("staking phase", func(t *testing.T) {
+		entry := unittest.EpochStateFixture()
+		entry.CurrentEpoch.EpochExtensions = nil
+		snapshot := forecastSnapshot(t, 1500, flow.EpochPhaseStaking, 1999, now.Add(500*time.Second), entry)
+
+		forecast, err := ForecastEpoch(snapshot, timing, now)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1500), forecast.CurrentView)
+		assert.False(t, forecast.EpochFallbackTriggered)
+		assert.Equal(t, time.Second, forecast.ViewDuration)
+		assert.Equal(t, []accessmodel.EpochTransitionForecast{
+			{Transition: accessmodel.EpochTransitionStakingAuctionEnd, View: 1899, Time: at(1899), Estimated: true},
+			{Transition: accessmodel.EpochTransitionDKGPhase1End, View: 1929, Time: at(1929), Estimated: true},
+			{Transition: accessmodel.EpochTransitionDKGPhase2End, View: 1959, Time: at(1959), Estimated: true},
+			{Transition: accessmodel.EpochTransitionDKGPhase3End, View: 1989, Time: at(1989), Estimated: true},
+			{Transition: accessmodel.EpochTransitionCommitDeadline, View: 1993, Time: at(1993)},
+			{Transition: accessmodel.EpochTransitionSwitchover, View: 1999, Time: at(1999)},
+		}, forecast.Transitions)
+	})
+
+	t.Run("setup phase", func(t *testing.T) {
+		entry := unittest.EpochStateFixture(unittest.WithNextEpochProtocolState())
+		entry.CurrentEpoch.EpochExtensions = nil
+		entry.NextEpochSetup.DKGPhase1FinalView = 1510
+		entry.NextEpochSetup.DKGPhase2FinalView = 1520
+		entry.NextEpochSetup.DKGPhase3FinalView = 1530
+		// the epoch is behind schedule, so the view duration is limited by the cruise controller
+		snapshot := forecastSnapshot(t, 1500, flow.EpochPhaseSetup, 1999, now.Add(10*time.Second), entry)
+
+		forecast, err := ForecastEpoch(snapshot, timing, now)
+		require.NoError(t, err)
+		assert.Equal(t, 100*time.Millisecond, forecast.ViewDuration)
+		transitions := make([]accessmodel.EpochTransition, 0, len(forecast.Transitions))
+		for _, transition := range forecast.Transitions {
+			assert.False(t, transition.Estimated)
+			transitions = append(transitions, transition.Transition)
+		}
+		assert.Equal(t, []accessmodel.EpochTransition{
+			accessmodel.EpochTransitionDKGPhase1End,
+			accessmodel.EpochTransitionDKGPhase2End,
+			accessmodel.EpochTransitionDKGPhase3End,
+			accessmodel.EpochTransitionCommitDeadline,
+			accessmodel.EpochTransitionSwitchover,
+		}, transitions)
+		assert.Equal(t, now.Add(500*time.Second/10), forecast.Transitions[4].Time)
+	})
+
+	t.Run("epoch fallback mode", func(t *testing.T) {
+		entry := unittest.EpochStateFixture()
+		entry.CurrentEpoch.EpochExtensions = []flow.EpochExtension{{FirstView: 2000, FinalView: 2999}}
+		snapshot := forecastSnapshot(t, 2500, flow.EpochPhaseFallback, 2999, now.Add(500*time.Second), entry)
+
+		forecast, err := ForecastEpoch(snapshot, timing, now)
+		require.NoError(t, err)
+		assert.True(t, forecast.EpochFallbackTriggered)
+		assert.Equal(t, entry.CurrentEpoch.EpochExtensions, forecast.Extensions)
+		assert.Equal(t, []accessmodel.EpochTransitionForecast{
+			{Transition: accessmodel.EpochTransitionSwitchover, View: 2999, Time: now.Add(500 * time.Second)},
+		}, forecast.Transitions)
+	})
+}
@@ -55,6 +55,21 @@ const (
 	codeChunkExecutionDataV2 // includes transaction results
 )
 
+// format versions identify the format of the serialized data, i.e., how the data following the header code is
+// compressed. The format version is written as a single byte before the header code. The most significant bit is
+// set for all format versions, which distinguishes them from the header codes: data serialized in the legacy format,
+// i.e., without a format version byte, starts with the header code. This allows adding new formats in a backwards
+// compatible way, as data in any format can be deserialized regardless of the format used for serialization.
+//
+// CAUTION: the execution data ID is derived from the serialized data, hence changing the format used by the
+// DefaultSerializer changes the IDs of all execution data, which is a breaking change of the protocol.
+const (
+	formatVersionFlag byte = 0x80
+
+	// FormatVersionZstd identifies data compressed with the Zstandard compressor, without dictionaries.
+	FormatVersionZstd = formatVersionFlag | 1
+)
+
 // getCode returns the header code for the given value's type.
 // It returns an error if the type is not supported.
 func getCode(v interface{}) (byte, error) {
@@ -103,17 +118,53 @@ type Serializer interface {
 // compressing them using the given codec and compressor.
 //
 // The serialized data is prefixed with a single byte header that identifies the underlying
-// data format. This allows adding new data types in a backwards compatible way.
+// data format. This allows adding new data types in a backwards compatible way. Optionally,
+// the header is preceded by a format version byte, which identifies the compression of the data.
 type serializer struct {
-	codec      encoding.Codec
+	codec encoding.Codec
+	// compressor is the compressor of the data serialized in the legacy format, i.e., without format version byte.
 	compressor network.Compressor
+	// formatVersion is the format version of the serialized data, 0 for the legacy format.
+	formatVersion byte
+	// formats are the compressors of the supported format versions.
+	formats map[byte]network.Compressor
 }
 
 // NewSerializer returns a new Execution Data serializer using the provided encoder and compressor.
+// Data is serialized in the legacy format, i.e., without format version byte.
 func NewSerializer(codec encoding.Codec, compressor network.Compressor) *serializer {
 	return &serializer{
 		codec:      codec,
 		compressor: compressor,
+		formats:    defaultFormats(),
+	}
+}
+
+// NewZstdSerializer returns a new Execution Data serializer using the provided encoder, which serializes data in
+// the FormatVersionZstd format, i.e., compressed with the Zstandard compressor. Data serialized in the legacy format
+// is deserialized with the provided legacy compressor.
+//
+// CAUTION: this serializer is not used by any node yet. As the execution data ID is derived from the serialized
+// data, switching to it is a protocol upgrade: it must only happen once all nodes run a version whose
+// DefaultSerializer deserializes the FormatVersionZstd format, which is the case for this version.
+func NewZstdSerializer(codec encoding.Codec, legacyCompressor network.Compressor) *serializer {
+	return &serializer{
+		codec:         codec,
+		compressor:    legacyCompressor,
+		formatVersion: FormatVersionZstd,
+		formats:       defaultFormats(),
+	}
+}
+
+// defaultFormats returns the compressors of the supported format versions.
+func defaultFormats() map[byte]network.Compressor {
+	// no dictionaries are used, hence creating the compressor never fails
+	zstdCompressor, err := compressor.NewZstdCompressor()
+	if err != nil {
+		panic(fmt.Errorf("could not create zstd compressor: %w", err))
+	}
+	return map[byte]network.Compressor{
+		FormatVersionZstd: zstdCompressor,
 	}
 }
 
@@ -126,82 +177,112 @@ func (s *serializer) writePrototype(w io.Writer, v interface{}) error {
 		return err
 	}
 
-	if bw, ok := w.(io.ByteWriter); ok {
-		err = bw.WriteByte(code)
-	} else {
-		_, err = w.Write([]byte{code})
-	}
-
-	if err != nil {
+	if err = writeByte(w, code); err != nil {
 		return fmt.Errorf("failed to write code: %w", err)
 	}
 
 	return nil
 }
 
+// writeByte writes a single byte to the given writer.
+func writeByte(w io.Writer, b byte) error {
+	if bw, ok := w.(io.ByteWriter); ok {
+		return bw.WriteByte(b)
+	}
+	_, err := w.Write([]byte{b})
+	return err
+}
+
+// readByte reads a single byte from the given reader.
+func readByte(r io.Reader) (byte, error) {
+	if br, ok := r.(io.ByteReader); ok {
+		return br.ReadByte()
+	}
+	var buf [1]byte
+	_, err := io.ReadFull(r, buf[:])
+	return buf[0], err
+}
+
 // Serialize encodes and compresses the given value to the given writer.
 // No errors are expected during normal operation.
 func (s *serializer) Serialize(w io.Writer, v interface{}) error {
+	comp := s.compressor
+	if s.formatVersion != 0 {
+		comp = s.formats[s.formatVersion]
+		if err := writeByte(w, s.formatVersion); err != nil {
+			return fmt.Errorf("failed to write format version: %w", err)
+		}
+	}
+
 	if err := s.writePrototype(w, v); err != nil {
 		return fmt.Errorf("failed to write prototype: %w", err)
 	}
 
-	comp, err := s.compressor.NewWriter(w)
+	compWriter, err := comp.NewWriter(w)
 
 	if err != nil {
 		return fmt.Errorf("failed to create compressor writer: %w", err)
 	}
 
-	enc := s.codec.NewEncoder(comp)
+	enc := s.codec.NewEncoder(compWriter)
 
 	if err := enc.Encode(v); err != nil {
 		return fmt.Errorf("failed to encode data: %w", err)
 	}
 
 	// flush data out to the underlying writer
-	if err := comp.Close(); err != nil {
+	if err := compWriter.Close(); err != nil {
 		return fmt.Errorf("failed to close compressor: %w", err)
 	}
 
 	return nil
 }
 
-// readPrototype reads a header code from the given reader and returns a prototype value
-func (s *serializer) readPrototype(r io.Reader) (interface{}, error) {
-	var code byte
-	var err error
+// readPrototype reads the format version, if any, and the header code from the given reader, and returns
+// a prototype value and the compressor of the data.
+func (s *serializer) readPrototype(r io.Reader) (interface{}, network.Compressor, error) {
+	code, err := readByte(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read code: %w", err)
+	}
 
-	if br, ok := r.(io.ByteReader); ok {
-		code, err = br.ReadByte()
-	} else {
-		var buf [1]byte
-		_, err = r.Read(buf[:])
-		code = buf[0]
+	comp := s.compressor
+	if code&formatVersionFlag != 0 {
+		var ok bool
+		comp, ok = s.formats[code]
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid format version: %v", code)
+		}
+		code, err = readByte(r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read code: %w", err)
+		}
 	}
 
+	v, err := getPrototype(code)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read code: %w", err)
+		return nil, nil, err
 	}
-
-	return getPrototype(code)
+	return v, comp, nil
 }
 
 // Deserialize decompresses and decodes the data from the given reader.
 // No errors are expected during normal operation.
 func (s *serializer) Deserialize(r io.Reader) (interface{}, error) {
-	v, err := s.readPrototype(r)
+	v, comp, err := s.readPrototype(r)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to read prototype: %w", err)
 	}
 
-	comp, err := s.compressor.NewReader(r)
+	compReader, err := comp.NewReader(r)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create compressor reader: %w", err)
 	}
+	defer compReader.Close()
 
-	dec := s.codec.NewDecoder(comp)
+	dec := s.codec.NewDecoder(compReader)
 
 	if err := dec.Decode(v); err != nil {
 		return nil, fmt.Errorf("failed to decode data: %w", err)
@@ -7,8 +7,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-go/model/encoding/cbor"
 	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
 	"github.com/onflow/flow-go/module/executiondatasync/execution_data/internal"
+	"github.com/onflow/flow-go/network/compressor"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -60,3 +62,94 @@ func TestSerializeDeserializeChunkExecutionData(t *testing.T) {
 		assert.Equal(t, cedV2, actual)
 	})
 }
+
+// TestZstdSerializer tests that data serialized in the zstd format is prefixed with the format version, and that
+// data in the legacy and the zstd format are deserialized by both the default and the zstd serializer.
+func TestZstdSerializer(t *testing.T) {
+	zstdSerializer := execution_data.NewZstdSerializer(cbor.NewCodec(), compressor.NewLz4Compressor())
+	ced := unittest.ChunkExecutionDataFixture(t, 1024, unittest.WithChunkEvents(unittest.EventsFixture(5)))
+
+	serialize := func(t *testing.T, serializer execution_data.Serializer) []byte {
+		buf := new(bytes.Buffer)
+		err := serializer.Serialize(buf, ced)
+		require.NoError(t, err)
+		return buf.Bytes()
+	}
+	deserialize := func(t *testing.T, serializer execution_data.Serializer, data []byte) {
+		raw, err := serializer.Deserialize(bytes.NewReader(data))
+		require.NoError(t, err)
+		actual, ok := raw.(*execution_data.ChunkExecutionData)
+		require.True(t, ok)
+		assert.Equal(t, ced, actual)
+	}
+
+	t.Run("zstd format is prefixed with format version", func(t *testing.T) {
+		data := serialize(t, zstdSerializer)
+		assert.Equal(t, execution_data.FormatVersionZstd, data[0])
+	})
+
+	// the execution data ID is derived from the serialized data, hence the default serializer must keep
+	// serializing data in the legacy format.
+	t.Run("default serializer serializes data in legacy format", func(t *testing.T) {
+		data := serialize(t, execution_data.DefaultSerializer)
+		assert.NotEqual(t, execution_data.FormatVersionZstd, data[0])
+		assert.Equal(t, data, serialize(t, execution_data.DefaultSerializer))
+	})
+
+	t.Run("zstd serializer deserializes zstd format", func(t *testing.T) {
+		deserialize(t, zstdSerializer, serialize(t, zstdSerializer))
+	})
+
+	t.Run("zstd serializer deserializes legacy format", func(t *testing.T) {
+		deserialize(t, zstdSerializer, serialize(t, execution_data.DefaultSerializer))
+	})
+
+	t.Run("default serializer deserializes zstd format", func(t *testing.T) {
+		deserialize(t, execution_data.DefaultSerializer, serialize(t, zstdSerializer))
+	})
+
+	t.Run("unknown format version", func(t *testing.T) {
+		data := serialize(t, zstdSerializer)
+		data[0] = 0xff
+		_, err := zstdSerializer.Deserialize(bytes.NewReader(data))
+		require.Error(t, err)
+	})
+}
+
+// BenchmarkSerializers compares the serialization and deserialization of chunk execution data in the legacy
+// LZ4 format and in the zstd format. Besides the throughput, it reports the size of the serialized data.
+func BenchmarkSerializers(b *testing.B) {
+	ced := unittest.ChunkExecutionDataFixture(b, 1024*1024, unittest.WithChunkEvents(unittest.EventsFixture(100)))
+	serializers := []struct {
+		name       string
+		serializer execution_data.Serializer
+	}{
+		{name: "lz4", serializer: execution_data.DefaultSerializer},
+		{name: "zstd", serializer: execution_data.NewZstdSerializer(cbor.NewCodec(), compressor.NewLz4Compressor())},
+	}
+
+	for _, s := range serializers {
+		b.Run(s.name+"/serialize", func(b *testing.B) {
+			buf := new(bytes.Buffer)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				err := s.serializer.Serialize(buf, ced)
+				require.NoError(b, err)
+			}
+			b.ReportMetric(float64(buf.Len()), "bytes")
+		})
+		b.Run(s.name+"/deserialize", func(b *testing.B) {
+			buf := new(bytes.Buffer)
+			err := s.serializer.Serialize(buf, ced)
+			require.NoError(b, err)
+			data := buf.Bytes()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := s.serializer.Deserialize(bytes.NewReader(data))
+				require.NoError(b, err)
+			}
+		})
+	}
+}
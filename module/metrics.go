@@ -397,6 +397,13 @@ type NetworkInboundQueueMetrics interface {
 
 	// QueueDuration tracks the time spent by a message with the given priority in the queue
 	QueueDuration(duration time.Duration, priority int)
+
+	// SenderQueueDuration tracks the time spent in the queue by a message from a sender with the given role
+	SenderQueueDuration(duration time.Duration, role string)
+
+	// MessageDroppedBySenderCap increments the metric tracking the number of messages dropped because their sender,
+	// with the given role, already had the maximum number of messages in the queue
+	MessageDroppedBySenderCap(role string)
 }
 
 // NetworkCoreMetrics encapsulates the metrics collectors for the core networking layer functionality.
@@ -443,6 +450,17 @@ type AlspMetrics interface {
 	OnMisbehaviorReported(channel string, misbehaviorType string)
 }
 
+// ChannelBandwidthMetrics encapsulates the metrics collectors for the bandwidth accounting of the networking layer,
+// which accounts the bytes of the messages exchanged by the node per channel, direction and role of the remote node.
+type ChannelBandwidthMetrics interface {
+	// ChannelBytes tracks the bytes of a message exchanged on the given channel in the given direction (inbound or
+	// outbound) with nodes of the given role.
+	ChannelBytes(channel string, direction string, role string, sizeBytes int)
+	// ChannelQuotaExceeded tracks the bytes of a message dropped because it exceeded the bandwidth quota of the
+	// given channel in the given direction.
+	ChannelQuotaExceeded(channel string, direction string, sizeBytes int)
+}
+
 // NetworkMetrics is the blanket abstraction that encapsulates the metrics collectors for the networking layer.
 type NetworkMetrics interface {
 	LibP2PMetrics
@@ -608,6 +626,28 @@ type CruiseCtlMetrics interface {
 	ProposalPublicationDelay(duration time.Duration)
 }
 
+// LeaderMetrics captures the outcomes of the views led by each consensus participant, as
+// observed by the local node.
+type LeaderMetrics interface {
+	// LeaderViewOutcome reports the outcome of a view led by the given node: whether its
+	// proposal was received on time, late or not at all, and whether the view was concluded
+	// by a timeout certificate.
+	LeaderViewOutcome(leaderID flow.Identifier, outcome string, timedOut bool)
+}
+
+// DKGMetrics captures the progress of the local random beacon DKG instance.
+type DKGMetrics interface {
+	// DKGPhase reports the phase the local DKG instance entered.
+	DKGPhase(phase uint32)
+	// DKGMessageReceived reports a broadcast or private DKG message received from another participant.
+	DKGMessageReceived(messageType string)
+	// DKGComplaint reports a complaint raised by the local DKG instance against another participant,
+	// and whether the participant was disqualified.
+	DKGComplaint(disqualified bool)
+	// DKGResult reports the outcome of the local DKG instance.
+	DKGResult(outcome string)
+}
+
 type CollectionMetrics interface {
 	TransactionValidationMetrics
 	// TransactionIngested is called when a new transaction is ingested by the
@@ -1000,6 +1040,14 @@ type ExecutionMetrics interface {
 	// ExecutionLastChunkDataPackPrunedHeight reports last chunk data pack pruned height
 	ExecutionLastChunkDataPackPrunedHeight(height uint64)
 
+	// ExecutionLastCheckedSealedHeight reports the last sealed height whose execution result
+	// was compared against the sealed result by the checker
+	ExecutionLastCheckedSealedHeight(height uint64)
+
+	// ExecutionForkDetected reports the height and first diverging chunk index of an execution
+	// result which differs from the sealed result
+	ExecutionForkDetected(height uint64, chunkIndex int)
+
 	// ExecutionTargetChunkDataPackPrunedHeight reports the target height for chunk data pack to be pruned
 	ExecutionTargetChunkDataPackPrunedHeight(height uint64)
 
@@ -1015,6 +1063,11 @@ type ExecutionMetrics interface {
 	// ExecutionCollectionExecuted reports the total time and computation spent on executing a collection
 	ExecutionCollectionExecuted(dur time.Duration, stats CollectionExecutionResultStats)
 
+	// ExecutionBlockConflictScheduling reports the number of transactions in a block which were
+	// serialized because they were predicted to conflict, and how many of those would have
+	// required a conflict retry had they been executed speculatively
+	ExecutionBlockConflictScheduling(serialized int, retriesAvoided int)
+
 	// ExecutionTransactionExecuted reports stats on executing a single transaction
 	ExecutionTransactionExecuted(dur time.Duration, stats TransactionExecutionResultStats, info TransactionExecutionResultInfo)
 
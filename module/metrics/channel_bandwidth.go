@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/onflow/flow-go/module"
+)
+
+// ChannelBandwidthMetrics captures the bytes of the messages exchanged by the node per channel, direction and role
+// of the remote node, and the bytes dropped because they exceeded the bandwidth quota of their channel.
+type ChannelBandwidthMetrics struct {
+	bytes                 *prometheus.CounterVec
+	messages              *prometheus.CounterVec
+	quotaExceeded         *prometheus.CounterVec
+	quotaExceededMessages *prometheus.CounterVec
+}
+
+var _ module.ChannelBandwidthMetrics = (*ChannelBandwidthMetrics)(nil)
+
+func NewChannelBandwidthMetrics() *ChannelBandwidthMetrics {
+	return &ChannelBandwidthMetrics{
+		bytes: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "channel_bytes_total",
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemBandwidth,
+			Help:      "The bytes of the messages exchanged by the node, by channel, direction and role of the remote node",
+		}, []string{LabelChannel, LabelDirection, LabelNodeRole}),
+		messages: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "channel_messages_total",
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemBandwidth,
+			Help:      "The number of messages exchanged by the node, by channel, direction and role of the remote node",
+		}, []string{LabelChannel, LabelDirection, LabelNodeRole}),
+		quotaExceeded: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "quota_exceeded_bytes_total",
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemBandwidth,
+			Help:      "The bytes of the messages dropped because they exceeded the bandwidth quota of their channel, by channel and direction",
+		}, []string{LabelChannel, LabelDirection}),
+		quotaExceededMessages: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "quota_exceeded_messages_total",
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemBandwidth,
+			Help:      "The number of messages dropped because they exceeded the bandwidth quota of their channel, by channel and direction",
+		}, []string{LabelChannel, LabelDirection}),
+	}
+}
+
+func (m *ChannelBandwidthMetrics) ChannelBytes(channel string, direction string, role string, sizeBytes int) {
+	m.bytes.WithLabelValues(channel, direction, role).Add(float64(sizeBytes))
+	m.messages.WithLabelValues(channel, direction, role).Inc()
+}
+
+func (m *ChannelBandwidthMetrics) ChannelQuotaExceeded(channel string, direction string, sizeBytes int) {
+	m.quotaExceeded.WithLabelValues(channel, direction).Add(float64(sizeBytes))
+	m.quotaExceededMessages.WithLabelValues(channel, direction).Inc()
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/onflow/flow-go/module"
+)
+
+// DKGMetrics captures the progress of the local random beacon DKG instance.
+type DKGMetrics struct {
+	phase            prometheus.Gauge
+	messagesReceived *prometheus.CounterVec
+	complaints       *prometheus.CounterVec
+	results          *prometheus.CounterVec
+}
+
+var _ module.DKGMetrics = (*DKGMetrics)(nil)
+
+func NewDKGMetrics() *DKGMetrics {
+	return &DKGMetrics{
+		phase: promauto.NewGauge(prometheus.GaugeOpts{
+			Name:      "phase",
+			Namespace: namespaceConsensus,
+			Subsystem: subsystemDKG,
+			Help:      "The phase of the local DKG instance (0: init, 1-3: phases 1-3, 4: end, 5: shutdown)",
+		}),
+		messagesReceived: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "messages_received_total",
+			Namespace: namespaceConsensus,
+			Subsystem: subsystemDKG,
+			Help:      "The number of broadcast and private DKG messages received from other participants",
+		}, []string{LabelMessage}),
+		complaints: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "complaints_total",
+			Namespace: namespaceConsensus,
+			Subsystem: subsystemDKG,
+			Help:      "The number of complaints raised by the local DKG instance, by whether the accused participant was disqualified",
+		}, []string{LabelDisqualified}),
+		results: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "results_total",
+			Namespace: namespaceConsensus,
+			Subsystem: subsystemDKG,
+			Help:      "The number of completed local DKG instances, by outcome",
+		}, []string{LabelOutcome}),
+	}
+}
+
+func (m *DKGMetrics) DKGPhase(phase uint32) {
+	m.phase.Set(float64(phase))
+}
+
+func (m *DKGMetrics) DKGMessageReceived(messageType string) {
+	m.messagesReceived.WithLabelValues(messageType).Inc()
+}
+
+func (m *DKGMetrics) DKGComplaint(disqualified bool) {
+	m.complaints.WithLabelValues(strconv.FormatBool(disqualified)).Inc()
+}
+
+func (m *DKGMetrics) DKGResult(outcome string) {
+	m.results.WithLabelValues(outcome).Inc()
+}
@@ -22,6 +22,9 @@ type ExecutionCollector struct {
 	lastFinalizedExecutedBlockHeightGauge   prometheus.Gauge
 	lastChunkDataPackPrunedHeightGauge      prometheus.Gauge
 	targetChunkDataPackPrunedHeightGauge    prometheus.Gauge
+	lastCheckedSealedHeightGauge            prometheus.Gauge
+	forkDetectedHeightGauge                 prometheus.Gauge
+	forkDivergingChunkIndexGauge            prometheus.Gauge
 	stateStorageDiskTotal                   prometheus.Gauge
 	storageStateCommitment                  prometheus.Gauge
 	checkpointSize                          prometheus.Gauge
@@ -66,6 +69,8 @@ type ExecutionCollector struct {
 	transactionInterpretTime                prometheus.Histogram
 	transactionExecutionTime                prometheus.Histogram
 	transactionConflictRetries              prometheus.Histogram
+	transactionsSerialized                  prometheus.Counter
+	transactionConflictRetriesAvoided       prometheus.Counter
 	transactionMemoryEstimate               prometheus.Histogram
 	transactionComputationUsed              prometheus.Histogram
 	transactionNormalizedTimePerComputation prometheus.Histogram
@@ -413,6 +418,20 @@ func NewExecutionCollector(tracer module.Tracer) *ExecutionCollector {
 		Buckets:   []float64{0, 1, 2, 3, 4, 5, 10, 20, 30, 40, 50, 100},
 	})
 
+	transactionsSerialized := promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemRuntime,
+		Name:      "transactions_serialized_total",
+		Help:      "the number of transactions executed serially because the conflict-aware scheduler predicted a conflict",
+	})
+
+	transactionConflictRetriesAvoided := promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemRuntime,
+		Name:      "transaction_conflict_retries_avoided_total",
+		Help:      "the number of serialized transactions which would have conflicted if executed speculatively",
+	})
+
 	transactionComputationUsed := promauto.NewHistogram(prometheus.HistogramOpts{
 		Namespace: namespaceExecution,
 		Subsystem: subsystemRuntime,
@@ -589,6 +608,8 @@ func NewExecutionCollector(tracer module.Tracer) *ExecutionCollector {
 		transactionInterpretTime:                transactionInterpretTime,
 		transactionExecutionTime:                transactionExecutionTime,
 		transactionConflictRetries:              transactionConflictRetries,
+		transactionsSerialized:                  transactionsSerialized,
+		transactionConflictRetriesAvoided:       transactionConflictRetriesAvoided,
 		transactionComputationUsed:              transactionComputationUsed,
 		transactionNormalizedTimePerComputation: transactionNormalizedTimePerComputation,
 		transactionMemoryEstimate:               transactionMemoryEstimate,
@@ -669,6 +690,27 @@ func NewExecutionCollector(tracer module.Tracer) *ExecutionCollector {
 			Help:      "the target height for pruning chunk data pack",
 		}),
 
+		lastCheckedSealedHeightGauge: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemRuntime,
+			Name:      "last_checked_sealed_height",
+			Help:      "the last sealed height whose execution result was checked against the sealed result",
+		}),
+
+		forkDetectedHeightGauge: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemRuntime,
+			Name:      "fork_detected_height",
+			Help:      "the height of the first block whose execution result differs from the sealed result, zero if none",
+		}),
+
+		forkDivergingChunkIndexGauge: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemRuntime,
+			Name:      "fork_diverging_chunk_index",
+			Help:      "the index of the first chunk which differs from the sealed result, -1 if the chunks match",
+		}),
+
 		stateStorageDiskTotal: promauto.NewGauge(prometheus.GaugeOpts{
 			Namespace: namespaceExecution,
 			Subsystem: subsystemStateStorage,
@@ -847,6 +889,13 @@ func (ec *ExecutionCollector) ExecutionBlockCachedPrograms(programs int) {
 	ec.blockCachedPrograms.Set(float64(programs))
 }
 
+// ExecutionBlockConflictScheduling reports the number of transactions in a block which were
+// serialized by the conflict-aware scheduler, and how many of them would otherwise have conflicted.
+func (ec *ExecutionCollector) ExecutionBlockConflictScheduling(serialized int, retriesAvoided int) {
+	ec.transactionsSerialized.Add(float64(serialized))
+	ec.transactionConflictRetriesAvoided.Add(float64(retriesAvoided))
+}
+
 // ExecutionTransactionExecuted reports stats for executing a transaction
 func (ec *ExecutionCollector) ExecutionTransactionExecuted(
 	dur time.Duration,
@@ -917,6 +966,17 @@ func (ec *ExecutionCollector) ExecutionTargetChunkDataPackPrunedHeight(height ui
 	ec.targetChunkDataPackPrunedHeightGauge.Set(float64(height))
 }
 
+// ExecutionLastCheckedSealedHeight reports the last sealed height checked by the checker
+func (ec *ExecutionCollector) ExecutionLastCheckedSealedHeight(height uint64) {
+	ec.lastCheckedSealedHeightGauge.Set(float64(height))
+}
+
+// ExecutionForkDetected reports the height and first diverging chunk index of an execution fork
+func (ec *ExecutionCollector) ExecutionForkDetected(height uint64, chunkIndex int) {
+	ec.forkDetectedHeightGauge.Set(float64(height))
+	ec.forkDivergingChunkIndexGauge.Set(float64(chunkIndex))
+}
+
 // ForestApproxMemorySize records approximate memory usage of forest (all in-memory trees)
 func (ec *ExecutionCollector) ForestApproxMemorySize(bytes uint64) {
 	ec.forestApproxMemorySize.Set(float64(bytes))
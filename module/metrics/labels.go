@@ -25,6 +25,10 @@ const (
 	LabelService             = "service"
 	LabelRejectionReason     = "rejection_reason"
 	LabelAccountAddress      = "acct_address" // Account address for a machine account
+	LabelOutcome             = "outcome"
+	LabelTimedOut            = "timed_out"
+	LabelDisqualified        = "disqualified"
+	LabelDirection           = "direction"
 )
 
 const (
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+)
+
+// LeaderMetrics captures the outcomes of the views led by each consensus participant, as
+// observed by the local node.
+type LeaderMetrics struct {
+	viewOutcomes *prometheus.CounterVec
+}
+
+var _ module.LeaderMetrics = (*LeaderMetrics)(nil)
+
+func NewLeaderMetrics() *LeaderMetrics {
+	return &LeaderMetrics{
+		viewOutcomes: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "leader_view_outcomes_total",
+			Namespace: namespaceConsensus,
+			Subsystem: subsystemHotstuff,
+			Help:      "The number of views led by each node, by whether the proposal was on time, late or missing, and whether the view timed out",
+		}, []string{LabelNodeID, LabelOutcome, LabelTimedOut}),
+	}
+}
+
+func (m *LeaderMetrics) LeaderViewOutcome(leaderID flow.Identifier, outcome string, timedOut bool) {
+	m.viewOutcomes.WithLabelValues(leaderID.String(), outcome, strconv.FormatBool(timedOut)).Inc()
+}
@@ -31,6 +31,7 @@ const (
 	subsystemRateLimiting = "ratelimit"
 	subsystemAlsp         = "alsp"
 	subsystemSecurity     = "security"
+	subsystemBandwidth    = "bandwidth"
 )
 
 // Storage subsystems represent the various components of the storage layer.
@@ -65,6 +66,7 @@ const (
 	subsystemHotstuff    = "hotstuff"
 	subsystemCruiseCtl   = "cruisectl"
 	subsystemMatchEngine = "match"
+	subsystemDKG         = "dkg"
 )
 
 // Execution Subsystems
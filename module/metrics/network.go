@@ -34,6 +34,8 @@ type NetworkCollector struct {
 	duplicateMessagesDropped     *prometheus.CounterVec
 	queueSize                    *prometheus.GaugeVec
 	queueDuration                *prometheus.HistogramVec
+	senderQueueDuration          *prometheus.HistogramVec
+	senderCapDropped             *prometheus.CounterVec
 	numMessagesProcessing        *prometheus.GaugeVec
 	numDirectMessagesSending     *prometheus.GaugeVec
 	inboundProcessTime           *prometheus.CounterVec
@@ -175,6 +177,25 @@ func NewNetworkCollector(logger zerolog.Logger, opts ...NetworkCollectorOpt) *Ne
 		}, []string{LabelPriority},
 	)
 
+	nc.senderQueueDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemQueue,
+			Name:      nc.prefix + "message_queue_sender_duration_seconds",
+			Help:      "duration [seconds; measured with float64 precision] of how long a message spent in the queue before delivered to an engine, by role of the sender.",
+			Buckets:   []float64{0.01, 0.1, 0.5, 1, 2, 5}, // 10ms, 100ms, 500ms, 1s, 2s, 5s
+		}, []string{LabelNodeRole},
+	)
+
+	nc.senderCapDropped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemQueue,
+			Name:      nc.prefix + "sender_cap_dropped_messages_total",
+			Help:      "the number of messages dropped because their sender already had the maximum number of messages in the queue, by role of the sender",
+		}, []string{LabelNodeRole},
+	)
+
 	nc.numMessagesProcessing = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: namespaceNetwork,
@@ -286,6 +307,17 @@ func (nc *NetworkCollector) QueueDuration(duration time.Duration, priority int)
 	nc.queueDuration.WithLabelValues(strconv.Itoa(priority)).Observe(duration.Seconds())
 }
 
+// SenderQueueDuration tracks the time spent in the queue by a message from a sender with the given role
+func (nc *NetworkCollector) SenderQueueDuration(duration time.Duration, role string) {
+	nc.senderQueueDuration.WithLabelValues(role).Observe(duration.Seconds())
+}
+
+// MessageDroppedBySenderCap increments the metric tracking the number of messages dropped because their sender,
+// with the given role, already had the maximum number of messages in the queue
+func (nc *NetworkCollector) MessageDroppedBySenderCap(role string) {
+	nc.senderCapDropped.WithLabelValues(role).Inc()
+}
+
 // MessageProcessingStarted increments the metric tracking the number of messages being processed by the node.
 func (nc *NetworkCollector) MessageProcessingStarted(topic string) {
 	nc.numMessagesProcessing.WithLabelValues(topic).Inc()
@@ -39,9 +39,12 @@ var _ module.BackendScriptsMetrics = (*NoopCollector)(nil)
 var _ module.TransactionMetrics = (*NoopCollector)(nil)
 var _ module.TransactionValidationMetrics = (*NoopCollector)(nil)
 var _ module.HotstuffMetrics = (*NoopCollector)(nil)
+var _ module.LeaderMetrics = (*NoopCollector)(nil)
+var _ module.DKGMetrics = (*NoopCollector)(nil)
 var _ module.EngineMetrics = (*NoopCollector)(nil)
 var _ module.HeroCacheMetrics = (*NoopCollector)(nil)
 var _ module.NetworkMetrics = (*NoopCollector)(nil)
+var _ module.ChannelBandwidthMetrics = (*NoopCollector)(nil)
 
 func (nc *NoopCollector) Peers(prefix string, n int)                                             {}
 func (nc *NoopCollector) Wantlist(prefix string, n int)                                          {}
@@ -58,6 +61,8 @@ func (nc *NoopCollector) NetworkDuplicateMessagesDropped(topic string, messageTy
 func (nc *NoopCollector) MessageAdded(priority int)                                              {}
 func (nc *NoopCollector) MessageRemoved(priority int)                                            {}
 func (nc *NoopCollector) QueueDuration(duration time.Duration, priority int)                     {}
+func (nc *NoopCollector) SenderQueueDuration(duration time.Duration, role string)                {}
+func (nc *NoopCollector) MessageDroppedBySenderCap(role string)                                  {}
 func (nc *NoopCollector) MessageProcessingStarted(topic string)                                  {}
 func (nc *NoopCollector) MessageProcessingFinished(topic string, duration time.Duration)         {}
 func (nc *NoopCollector) DirectMessageStarted(topic string)                                      {}
@@ -123,6 +128,13 @@ func (nc *NoopCollector) SignerProcessingDuration(duration time.Duration)
 func (nc *NoopCollector) ValidatorProcessingDuration(duration time.Duration)             {}
 func (nc *NoopCollector) PayloadProductionDuration(duration time.Duration)               {}
 func (nc *NoopCollector) TimeoutCollectorsRange(uint64, uint64, int)                     {}
+func (nc *NoopCollector) LeaderViewOutcome(flow.Identifier, string, bool)                {}
+func (nc *NoopCollector) DKGPhase(uint32)                                                {}
+func (nc *NoopCollector) DKGMessageReceived(string)                                      {}
+func (nc *NoopCollector) DKGComplaint(bool)                                              {}
+func (nc *NoopCollector) DKGResult(string)                                               {}
+func (nc *NoopCollector) ChannelBytes(string, string, string, int)                       {}
+func (nc *NoopCollector) ChannelQuotaExceeded(string, string, int)                       {}
 func (nc *NoopCollector) TransactionIngested(txID flow.Identifier)                       {}
 func (nc *NoopCollector) ClusterBlockProposed(*cluster.Block)                            {}
 func (nc *NoopCollector) ClusterBlockFinalized(*cluster.Block)                           {}
@@ -169,12 +181,15 @@ func (nc *NoopCollector) ExecutionLastFinalizedExecutedBlockHeight(height uint64
 func (nc *NoopCollector) ExecutionBlockExecuted(_ time.Duration, _ module.BlockExecutionResultStats) {
 }
 func (nc *NoopCollector) ExecutionLastChunkDataPackPrunedHeight(height uint64)   {}
+func (nc *NoopCollector) ExecutionLastCheckedSealedHeight(height uint64)         {}
+func (nc *NoopCollector) ExecutionForkDetected(height uint64, chunkIndex int)    {}
 func (nc *NoopCollector) ExecutionTargetChunkDataPackPrunedHeight(height uint64) {}
 
 func (nc *NoopCollector) ExecutionCollectionExecuted(_ time.Duration, _ module.CollectionExecutionResultStats) {
 }
 func (nc *NoopCollector) ExecutionBlockExecutionEffortVectorComponent(_ string, _ uint) {}
 func (nc *NoopCollector) ExecutionBlockCachedPrograms(programs int)                     {}
+func (nc *NoopCollector) ExecutionBlockConflictScheduling(_ int, _ int)                 {}
 func (nc *NoopCollector) ExecutionTransactionExecuted(_ time.Duration, _ module.TransactionExecutionResultStats, _ module.TransactionExecutionResultInfo) {
 }
 func (nc *NoopCollector) ExecutionChunkDataPackGenerated(_, _ int)                              {}
@@ -0,0 +1,87 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mock
+
+import (
+	dkg "github.com/onflow/flow-go/model/dkg"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DKGStatusProvider is an autogenerated mock type for the DKGStatusProvider type
+type DKGStatusProvider struct {
+	mock.Mock
+}
+
+// DKGStatus provides a mock function with given fields: epochCounter
+func (_m *DKGStatusProvider) DKGStatus(epochCounter uint64) (*dkg.Status, bool) {
+	ret := _m.Called(epochCounter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DKGStatus")
+	}
+
+	var r0 *dkg.Status
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(uint64) (*dkg.Status, bool)); ok {
+		return rf(epochCounter)
+	}
+	if rf, ok := ret.Get(0).(func(uint64) *dkg.Status); ok {
+		r0 = rf(epochCounter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dkg.Status)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint64) bool); ok {
+		r1 = rf(epochCounter)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// LatestDKGStatus provides a mock function with given fields:
+func (_m *DKGStatusProvider) LatestDKGStatus() (*dkg.Status, bool) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for LatestDKGStatus")
+	}
+
+	var r0 *dkg.Status
+	var r1 bool
+	if rf, ok := ret.Get(0).(func() (*dkg.Status, bool)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *dkg.Status); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dkg.Status)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// NewDKGStatusProvider creates a new instance of DKGStatusProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDKGStatusProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DKGStatusProvider {
+	mock := &DKGStatusProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
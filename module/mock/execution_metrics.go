@@ -36,6 +36,11 @@ func (_m *ExecutionMetrics) ExecutionBlockCachedPrograms(programs int) {
 	_m.Called(programs)
 }
 
+// ExecutionBlockConflictScheduling provides a mock function with given fields: serialized, retriesAvoided
+func (_m *ExecutionMetrics) ExecutionBlockConflictScheduling(serialized int, retriesAvoided int) {
+	_m.Called(serialized, retriesAvoided)
+}
+
 // ExecutionBlockDataUploadFinished provides a mock function with given fields: dur
 func (_m *ExecutionMetrics) ExecutionBlockDataUploadFinished(dur time.Duration) {
 	_m.Called(dur)
@@ -86,6 +91,16 @@ func (_m *ExecutionMetrics) ExecutionComputationResultUploaded() {
 	_m.Called()
 }
 
+// ExecutionForkDetected provides a mock function with given fields: height, chunkIndex
+func (_m *ExecutionMetrics) ExecutionForkDetected(height uint64, chunkIndex int) {
+	_m.Called(height, chunkIndex)
+}
+
+// ExecutionLastCheckedSealedHeight provides a mock function with given fields: height
+func (_m *ExecutionMetrics) ExecutionLastCheckedSealedHeight(height uint64) {
+	_m.Called(height)
+}
+
 // ExecutionLastChunkDataPackPrunedHeight provides a mock function with given fields: height
 func (_m *ExecutionMetrics) ExecutionLastChunkDataPackPrunedHeight(height uint64) {
 	_m.Called(height)
@@ -30,6 +30,11 @@ func (_m *NetworkCoreMetrics) MessageAdded(priority int) {
 	_m.Called(priority)
 }
 
+// MessageDroppedBySenderCap provides a mock function with given fields: role
+func (_m *NetworkCoreMetrics) MessageDroppedBySenderCap(role string) {
+	_m.Called(role)
+}
+
 // MessageProcessingFinished provides a mock function with given fields: topic, duration
 func (_m *NetworkCoreMetrics) MessageProcessingFinished(topic string, duration time.Duration) {
 	_m.Called(topic, duration)
@@ -75,6 +80,11 @@ func (_m *NetworkCoreMetrics) QueueDuration(duration time.Duration, priority int
 	_m.Called(duration, priority)
 }
 
+// SenderQueueDuration provides a mock function with given fields: duration, role
+func (_m *NetworkCoreMetrics) SenderQueueDuration(duration time.Duration, role string) {
+	_m.Called(duration, role)
+}
+
 // UnicastMessageSendingCompleted provides a mock function with given fields: topic
 func (_m *NetworkCoreMetrics) UnicastMessageSendingCompleted(topic string) {
 	_m.Called(topic)
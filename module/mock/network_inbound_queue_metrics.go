@@ -18,6 +18,11 @@ func (_m *NetworkInboundQueueMetrics) MessageAdded(priority int) {
 	_m.Called(priority)
 }
 
+// MessageDroppedBySenderCap provides a mock function with given fields: role
+func (_m *NetworkInboundQueueMetrics) MessageDroppedBySenderCap(role string) {
+	_m.Called(role)
+}
+
 // MessageRemoved provides a mock function with given fields: priority
 func (_m *NetworkInboundQueueMetrics) MessageRemoved(priority int) {
 	_m.Called(priority)
@@ -28,6 +33,11 @@ func (_m *NetworkInboundQueueMetrics) QueueDuration(duration time.Duration, prio
 	_m.Called(duration, priority)
 }
 
+// SenderQueueDuration provides a mock function with given fields: duration, role
+func (_m *NetworkInboundQueueMetrics) SenderQueueDuration(duration time.Duration, role string) {
+	_m.Called(duration, role)
+}
+
 // NewNetworkInboundQueueMetrics creates a new instance of NetworkInboundQueueMetrics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewNetworkInboundQueueMetrics(t interface {
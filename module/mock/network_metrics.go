@@ -137,6 +137,11 @@ func (_m *NetworkMetrics) MessageAdded(priority int) {
 	_m.Called(priority)
 }
 
+// MessageDroppedBySenderCap provides a mock function with given fields: role
+func (_m *NetworkMetrics) MessageDroppedBySenderCap(role string) {
+	_m.Called(role)
+}
+
 // MessageProcessingFinished provides a mock function with given fields: topic, duration
 func (_m *NetworkMetrics) MessageProcessingFinished(topic string, duration time.Duration) {
 	_m.Called(topic, duration)
@@ -517,6 +522,11 @@ func (_m *NetworkMetrics) RoutingTablePeerRemoved() {
 	_m.Called()
 }
 
+// SenderQueueDuration provides a mock function with given fields: duration, role
+func (_m *NetworkMetrics) SenderQueueDuration(duration time.Duration, role string) {
+	_m.Called(duration, role)
+}
+
 // SetWarningStateCount provides a mock function with given fields: _a0
 func (_m *NetworkMetrics) SetWarningStateCount(_a0 uint) {
 	_m.Called(_a0)
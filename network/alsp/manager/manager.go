@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -21,6 +22,7 @@ import (
 	"github.com/onflow/flow-go/network/alsp/internal"
 	"github.com/onflow/flow-go/network/alsp/model"
 	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/logging"
 )
 
@@ -39,6 +41,9 @@ var (
 	// ErrHeartBeatIntervalNotSet is returned when the heartbeat interval is not set, it is a fatal irrecoverable error,
 	// and the ALSP module cannot be initialized.
 	ErrHeartBeatIntervalNotSet = errors.New("heartbeat interval is not set")
+	// ErrSnapshotIntervalNotSet is returned when the spam records are persisted but the snapshot interval is not set,
+	// it is a fatal irrecoverable error, and the ALSP module cannot be initialized.
+	ErrSnapshotIntervalNotSet = errors.New("snapshot interval is not set")
 )
 
 type SpamRecordCacheFactory func(zerolog.Logger, uint32, module.HeroCacheMetrics) alsp.SpamRecordCache
@@ -91,9 +96,19 @@ type MisbehaviorReportManager struct {
 
 	// decayFunc is the function that calculates the decay of the spam record.
 	decayFunc SpamRecordDecayFunc
+
+	// snapshots is the storage for the snapshots of the spam records, nil if the spam records are not persisted.
+	snapshots storage.SpamRecordSnapshots
+	// snapshotLock serializes taking and storing snapshots of the spam records.
+	snapshotLock sync.Mutex
+	// restoredDisallowListings are the nodes that were disallow-listed when the restored snapshot was taken, and are
+	// still disallow-listed after decaying their penalties for the downtime. The disallow-listing consumer is notified
+	// about them on startup.
+	restoredDisallowListings flow.IdentifierList
 }
 
 var _ network.MisbehaviorReportManager = (*MisbehaviorReportManager)(nil)
+var _ alsp.SpamRecordAdmin = (*MisbehaviorReportManager)(nil)
 
 type MisbehaviorReportManagerConfig struct {
 	Logger zerolog.Logger
@@ -119,7 +134,13 @@ type MisbehaviorReportManagerConfig struct {
 	// HeartBeatInterval is the interval between the heartbeats. Heartbeat is a recurring event that is used to
 	// apply recurring actions, e.g., decay the penalty of the misbehaving nodes.
 	HeartBeatInterval time.Duration
-	Opts              []MisbehaviorReportManagerOption
+	// SpamRecordSnapshots is the storage for the snapshots of the spam records, which allows the penalties to survive
+	// restarts of the node. If nil, the spam records are kept in memory only, and a restart gives every misbehaving
+	// node a clean slate.
+	SpamRecordSnapshots storage.SpamRecordSnapshots
+	// SnapshotInterval is the interval between two snapshots of the spam records. Only used if SpamRecordSnapshots is set.
+	SnapshotInterval time.Duration
+	Opts             []MisbehaviorReportManagerOption
 }
 
 // validate validates the MisbehaviorReportManagerConfig instance. It returns an error if the config is invalid.
@@ -142,6 +163,9 @@ func (c MisbehaviorReportManagerConfig) validate() error {
 	if c.HeartBeatInterval == 0 {
 		return ErrHeartBeatIntervalNotSet
 	}
+	if c.SpamRecordSnapshots != nil && c.SnapshotInterval == 0 {
+		return ErrSnapshotIntervalNotSet
+	}
 	return nil
 }
 
@@ -172,6 +196,7 @@ func NewMisbehaviorReportManager(cfg *MisbehaviorReportManagerConfig, consumer n
 		disallowListingConsumer: consumer,
 		cacheFactory:            defaultSpamRecordCacheFactory(),
 		decayFunc:               defaultSpamRecordDecayFunc(),
+		snapshots:               cfg.SpamRecordSnapshots,
 	}
 
 	store := queue.NewHeroStore(
@@ -193,6 +218,13 @@ func NewMisbehaviorReportManager(cfg *MisbehaviorReportManagerConfig, consumer n
 		cfg.SpamRecordCacheSize,
 		metrics.ApplicationLayerSpamRecordCacheMetricFactory(cfg.HeroCacheMetricsFactory, cfg.NetworkType))
 
+	if m.snapshots != nil {
+		err := m.restoreSpamRecords(cfg.HeartBeatInterval)
+		if err != nil {
+			return nil, fmt.Errorf("could not restore spam records: %w", err)
+		}
+	}
+
 	builder := component.NewComponentManagerBuilder()
 	builder.AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
 		ready()
@@ -201,6 +233,13 @@ func NewMisbehaviorReportManager(cfg *MisbehaviorReportManagerConfig, consumer n
 	for i := 0; i < defaultMisbehaviorReportManagerWorkers; i++ {
 		builder.AddWorker(m.workerPool.WorkerLogic())
 	}
+	if m.snapshots != nil {
+		builder.AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+			m.notifyRestoredDisallowListings()
+			ready()
+			m.snapshotLoop(ctx, cfg.SnapshotInterval) // blocking call
+		})
+	}
 
 	m.Component = builder.Build()
 
@@ -315,12 +354,7 @@ func (m *MisbehaviorReportManager) onHeartbeat() error {
 			// as long as record.Penalty is NOT below model.DisallowListingThreshold,
 			// the node is considered allow-listed and can conduct inbound and outbound connections.
 			// Once it falls below model.DisallowListingThreshold, it needs to be disallow listed.
-			if record.Penalty < model.DisallowListingThreshold && !record.DisallowListed {
-				// cutoff counter keeps track of how many times the penalty has been below the threshold.
-				record.CutoffCounter++
-				record.DisallowListed = true
-				// Adjusts decay dynamically based on how many times the node was disallow-listed (cutoff).
-				record.Decay = m.adjustDecayFunc(record.CutoffCounter)
+			if m.cutoff(&record) {
 				m.logger.Warn().
 					Str("key", logging.KeySuspicious).
 					Hex("identifier", logging.ID(id)).
@@ -393,6 +427,21 @@ func (m *MisbehaviorReportManager) onHeartbeat() error {
 	return nil
 }
 
+// cutoff disallow-lists the spam record if its penalty is below model.DisallowListingThreshold and it is not
+// disallow-listed yet: the cutoff counter is incremented and the decay speed is adjusted to the number of cutoffs.
+// Returns true if the record was cut off, in which case the caller must notify the disallow-listing consumer.
+func (m *MisbehaviorReportManager) cutoff(record *model.ProtocolSpamRecord) bool {
+	if record.Penalty >= model.DisallowListingThreshold || record.DisallowListed {
+		return false
+	}
+	// cutoff counter keeps track of how many times the penalty has been below the threshold.
+	record.CutoffCounter++
+	record.DisallowListed = true
+	// Adjusts decay dynamically based on how many times the node was disallow-listed (cutoff).
+	record.Decay = m.adjustDecayFunc(record.CutoffCounter)
+	return true
+}
+
 // processMisbehaviorReport is the worker function that processes the misbehavior reports.
 // It is called by the worker pool.
 // It applies the penalty to the misbehaving node and updates the spam record cache.
@@ -448,6 +497,171 @@ func (m *MisbehaviorReportManager) processMisbehaviorReport(report internal.Repo
 	return nil
 }
 
+// SpamRecords returns the spam records of all nodes that have been reported for misbehavior, ordered by node ID.
+// Note that the returned records are copies of the records in the cache.
+func (m *MisbehaviorReportManager) SpamRecords() []model.ProtocolSpamRecord {
+	ids := flow.IdentifierList(m.cache.Identities()).Sort(flow.IdentifierCanonical)
+	records := make([]model.ProtocolSpamRecord, 0, len(ids))
+	for _, id := range ids {
+		record, ok := m.cache.Get(id)
+		if !ok {
+			// the record was removed concurrently
+			continue
+		}
+		records = append(records, *record)
+	}
+	return records
+}
+
+// SpamRecord returns the spam record of the given node.
+// Returns the record and true if the record exists, nil and false otherwise.
+// Note that the returned record is a copy of the record in the cache.
+func (m *MisbehaviorReportManager) SpamRecord(originId flow.Identifier) (*model.ProtocolSpamRecord, bool) {
+	return m.cache.Get(originId)
+}
+
+// ResetSpamRecord removes the spam record of the given node, including its penalty, cutoff counter and decay
+// speed. If the node is disallow-listed by the ALSP module, it is allow-listed again. If the spam records are
+// persisted, a new snapshot is stored right away, so that the reset is not undone by a restart.
+// Returns true if the record is removed, false otherwise (i.e., the record does not exist).
+func (m *MisbehaviorReportManager) ResetSpamRecord(originId flow.Identifier) bool {
+	if !m.cache.Remove(originId) {
+		return false
+	}
+	m.logger.Warn().
+		Hex("identifier", logging.ID(originId)).
+		Msg("spam record reset, allow-listing node")
+	// the node may have been disallow-listed concurrently to the removal of its record, so the allow-listing
+	// notification is sent unconditionally; allow-listing a node that is not disallow-listed has no effect.
+	m.disallowListingConsumer.OnAllowListNotification(&network.AllowListingUpdate{
+		FlowIds: flow.IdentifierList{originId},
+		Cause:   network.DisallowListedCauseAlsp,
+	})
+
+	if m.snapshots != nil {
+		err := m.snapshotSpamRecords()
+		if err != nil {
+			m.logger.Error().Err(err).Msg("failed to persist spam records after reset")
+		}
+	}
+	return true
+}
+
+// restoreSpamRecords restores the spam records from the latest snapshot, if any. The penalties are decayed for the
+// time elapsed since the snapshot was taken, i.e., for the downtime of the node, as if the heartbeats had continued:
+// records whose penalty dropped below the disallow-listing threshold since the last heartbeat before the snapshot are
+// cut off as by the next heartbeat, and then the penalty of each record decays linearly by the record's decay speed
+// for each missed heartbeat. Records which are still disallow-listed after the decay are remembered, so that the
+// disallow-listing consumer can be notified on startup.
+// No errors are expected during normal operation.
+func (m *MisbehaviorReportManager) restoreSpamRecords(heartbeatInterval time.Duration) error {
+	snapshot, err := m.snapshots.Latest()
+	if errors.Is(err, storage.ErrNotFound) {
+		m.logger.Info().Msg("no spam record snapshot found, starting with empty spam records")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not retrieve spam record snapshot: %w", err)
+	}
+
+	downtime := time.Since(snapshot.Time)
+	missedHeartbeats := float64(0)
+	if downtime > 0 {
+		missedHeartbeats = math.Floor(float64(downtime) / float64(heartbeatInterval))
+	}
+	for _, record := range snapshot.Records {
+		record := record
+		if m.cutoff(&record) {
+			m.logger.Warn().
+				Str("key", logging.KeySuspicious).
+				Hex("identifier", logging.ID(record.OriginId)).
+				Float64("penalty", record.Penalty).
+				Uint64("cutoff_counter", record.CutoffCounter).
+				Float64("decay_speed", record.Decay).
+				Msg("restored node penalty is below threshold, disallow listing")
+		}
+		record.Penalty = math.Min(record.Penalty+missedHeartbeats*record.Decay, 0)
+		if record.Penalty == 0 {
+			record.DisallowListed = false
+		}
+		_, err := m.cache.AdjustWithInit(record.OriginId, func(model.ProtocolSpamRecord) (model.ProtocolSpamRecord, error) {
+			return record, nil
+		})
+		if err != nil {
+			return fmt.Errorf("could not restore spam record %x: %w", record.OriginId, err)
+		}
+		if record.DisallowListed {
+			m.restoredDisallowListings = append(m.restoredDisallowListings, record.OriginId)
+		}
+	}
+
+	m.logger.Info().
+		Time("snapshot_time", snapshot.Time).
+		Dur("downtime", downtime).
+		Int("records", len(snapshot.Records)).
+		Int("disallow_listed", len(m.restoredDisallowListings)).
+		Msg("spam records restored from snapshot")
+	return nil
+}
+
+// notifyRestoredDisallowListings notifies the disallow-listing consumer about the nodes which are still
+// disallow-listed according to the restored spam records.
+func (m *MisbehaviorReportManager) notifyRestoredDisallowListings() {
+	for _, id := range m.restoredDisallowListings {
+		m.logger.Warn().
+			Str("key", logging.KeySuspicious).
+			Hex("identifier", logging.ID(id)).
+			Msg("node is disallow-listed according to restored spam record, initiating disallow listing")
+		m.disallowListingConsumer.OnDisallowListNotification(&network.DisallowListingUpdate{
+			FlowIds: flow.IdentifierList{id},
+			Cause:   network.DisallowListedCauseAlsp,
+		})
+	}
+	m.restoredDisallowListings = nil
+}
+
+// snapshotLoop persists a snapshot of the spam records at the given intervals, and a final snapshot on shutdown.
+// Failing to store a snapshot is not fatal, as the spam records are intact in memory: it is retried at the next
+// interval. It is a blocking function, and should be called in a separate goroutine. It returns when the context
+// is canceled.
+func (m *MisbehaviorReportManager) snapshotLoop(ctx irrecoverable.SignalerContext, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			err := m.snapshotSpamRecords()
+			if err != nil {
+				m.logger.Error().Err(err).Msg("failed to persist spam records on shutdown")
+			}
+			return
+		case <-ticker.C:
+			err := m.snapshotSpamRecords()
+			if err != nil {
+				m.logger.Error().Err(err).Dur("retry_in", interval).Msg("failed to persist spam records")
+			}
+		}
+	}
+}
+
+// snapshotSpamRecords takes a snapshot of the current spam records and persists it.
+// No errors are expected during normal operation.
+func (m *MisbehaviorReportManager) snapshotSpamRecords() error {
+	m.snapshotLock.Lock()
+	defer m.snapshotLock.Unlock()
+
+	snapshot := &model.SpamRecordSnapshot{
+		Time:    time.Now(),
+		Records: m.SpamRecords(),
+	}
+	err := m.snapshots.Store(snapshot)
+	if err != nil {
+		return fmt.Errorf("could not store spam record snapshot: %w", err)
+	}
+	m.logger.Trace().Int("records", len(snapshot.Records)).Msg("spam record snapshot stored")
+	return nil
+}
+
 // adjustDecayFunc calculates the decay value of the spam record cache. This allows the decay to be different on subsequent disallow listings.
 // It returns the decay speed for the given cutoff counter.
 // The cutoff counter is the number of times that the node has been disallow-listed.
@@ -2,6 +2,7 @@ package alspmgr_test
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"math/rand"
 	"sync"
@@ -33,6 +34,10 @@ import (
 	p2ptest "github.com/onflow/flow-go/network/p2p/test"
 	"github.com/onflow/flow-go/network/slashing"
 	"github.com/onflow/flow-go/network/underlay"
+	"github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/storage/operation/dbtest"
+	"github.com/onflow/flow-go/storage/store"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -704,6 +709,14 @@ func TestMisbehaviorReportManager_InitializationError(t *testing.T) {
 		require.ErrorIs(t, err, alspmgr.ErrSpamRecordCacheSizeNotSet)
 		assert.Nil(t, m)
 	})
+
+	t.Run("missing snapshot interval", func(t *testing.T) {
+		cfg := managerCfgFixture(t)
+		cfg.SpamRecordSnapshots = storagemock.NewSpamRecordSnapshots(t)
+		m, err := alspmgr.NewMisbehaviorReportManager(cfg, consumer)
+		require.ErrorIs(t, err, alspmgr.ErrSnapshotIntervalNotSet)
+		assert.Nil(t, m)
+	})
 }
 
 // TestHandleMisbehaviorReport_SinglePenaltyReport tests the handling of a single misbehavior report.
@@ -1781,6 +1794,125 @@ func createRandomMisbehaviorReports(t *testing.T, numReports int) []network.Misb
 }
 
 // managerCfgFixture creates a new MisbehaviorReportManagerConfig with default values for testing.
+// TestMisbehaviorReportManager_PersistedSpamRecords tests that the spam records are restored from the latest snapshot
+// with the penalties decayed for the downtime, that the nodes which are still disallow-listed are disallow-listed
+// again on startup, that resetting a spam record allow-lists the node and is persisted right away, and that a final
+// snapshot is stored on shutdown.
+func TestMisbehaviorReportManager_PersistedSpamRecords(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		snapshots := store.NewSpamRecordSnapshots(db)
+		cfg := managerCfgFixture(t)
+		// long intervals, so that no heartbeat nor periodic snapshot happens during the test
+		cfg.HeartBeatInterval = time.Hour
+		cfg.SnapshotInterval = time.Hour
+		cfg.SpamRecordSnapshots = snapshots
+		consumer := mocknetwork.NewDisallowListNotificationConsumer(t)
+
+		ids := unittest.IdentifierListFixture(4).Sort(flow.IdentifierCanonical)
+		stillDisallowListed, recovered, penalized, crossed := ids[0], ids[1], ids[2], ids[3]
+		// the node was down for 10 heartbeats
+		require.NoError(t, snapshots.Store(&model.SpamRecordSnapshot{
+			Time: time.Now().Add(-10*time.Hour - 30*time.Minute),
+			Records: []model.ProtocolSpamRecord{
+				{OriginId: stillDisallowListed, Decay: 5, CutoffCounter: 2, DisallowListed: true, Penalty: -100},
+				{OriginId: recovered, Decay: 5, CutoffCounter: 1, DisallowListed: true, Penalty: -20},
+				{OriginId: penalized, Decay: 1, Penalty: -30},
+				// the penalty dropped below the threshold after the last heartbeat before the snapshot, so the
+				// record is cut off before its penalty is decayed with the slowed down decay speed
+				{OriginId: crossed, Decay: 5, CutoffCounter: 1, Penalty: model.DisallowListingThreshold - 100},
+			},
+		}))
+
+		m, err := alspmgr.NewMisbehaviorReportManager(cfg, consumer)
+		require.NoError(t, err)
+
+		for _, id := range []flow.Identifier{stillDisallowListed, crossed} {
+			consumer.On("OnDisallowListNotification", &network.DisallowListingUpdate{
+				FlowIds: flow.IdentifierList{id},
+				Cause:   network.DisallowListedCauseAlsp,
+			}).Return().Once()
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		signalerCtx := irrecoverable.NewMockSignalerContext(t, ctx)
+		m.Start(signalerCtx)
+		unittest.RequireCloseBefore(t, m.Ready(), 100*time.Millisecond, "ALSP manager did not start")
+
+		require.Equal(t, []model.ProtocolSpamRecord{
+			{OriginId: stillDisallowListed, Decay: 5, CutoffCounter: 2, DisallowListed: true, Penalty: -50},
+			{OriginId: recovered, Decay: 5, CutoffCounter: 1, DisallowListed: false, Penalty: 0},
+			{OriginId: penalized, Decay: 1, Penalty: -20},
+			{OriginId: crossed, Decay: 100, CutoffCounter: 2, DisallowListed: true, Penalty: model.DisallowListingThreshold + 900},
+		}, m.SpamRecords())
+
+		// resetting the spam record allow-lists the node and persists the reset
+		consumer.On("OnAllowListNotification", &network.AllowListingUpdate{
+			FlowIds: flow.IdentifierList{stillDisallowListed},
+			Cause:   network.DisallowListedCauseAlsp,
+		}).Return().Once()
+		require.True(t, m.ResetSpamRecord(stillDisallowListed))
+		require.False(t, m.ResetSpamRecord(unittest.IdentifierFixture()))
+		_, ok := m.SpamRecord(stillDisallowListed)
+		require.False(t, ok)
+		snapshot, err := snapshots.Latest()
+		require.NoError(t, err)
+		require.Len(t, snapshot.Records, 3)
+
+		// a final snapshot is stored on shutdown
+		record, ok := m.SpamRecord(penalized)
+		require.True(t, ok)
+		require.Equal(t, float64(-20), record.Penalty)
+		m.HandleMisbehaviorReport(channels.TestNetworkChannel, misbehaviorReportFixtureWithPenalty(t, penalized, -5))
+		require.Eventually(t, func() bool {
+			record, ok := m.SpamRecord(penalized)
+			return ok && record.Penalty == -25
+		}, time.Second, 10*time.Millisecond)
+		cancel()
+		unittest.RequireCloseBefore(t, m.Done(), 100*time.Millisecond, "ALSP manager did not stop")
+
+		snapshot, err = snapshots.Latest()
+		require.NoError(t, err)
+		require.Equal(t, []model.ProtocolSpamRecord{
+			{OriginId: recovered, Decay: 5, CutoffCounter: 1, DisallowListed: false, Penalty: 0},
+			{OriginId: penalized, Decay: 1, Penalty: -25},
+			{OriginId: crossed, Decay: 100, CutoffCounter: 2, DisallowListed: true, Penalty: model.DisallowListingThreshold + 900},
+		}, snapshot.Records)
+	})
+}
+
+// TestMisbehaviorReportManager_SnapshotStoreFailure tests that failing to store a periodic snapshot of the spam records
+// is not fatal, and that storing the snapshot is retried at the next interval.
+func TestMisbehaviorReportManager_SnapshotStoreFailure(t *testing.T) {
+	snapshots := storagemock.NewSpamRecordSnapshots(t)
+	cfg := managerCfgFixture(t)
+	cfg.SnapshotInterval = 10 * time.Millisecond
+	cfg.SpamRecordSnapshots = snapshots
+	consumer := mocknetwork.NewDisallowListNotificationConsumer(t)
+
+	snapshots.On("Latest").Return(nil, storage.ErrNotFound).Once()
+	snapshots.On("Store", mock.Anything).Return(fmt.Errorf("disk full")).Once()
+	stored := make(chan struct{})
+	snapshots.On("Store", mock.Anything).Return(nil).Run(func(mock.Arguments) {
+		select {
+		case <-stored:
+		default:
+			close(stored)
+		}
+	})
+
+	m, err := alspmgr.NewMisbehaviorReportManager(cfg, consumer)
+	require.NoError(t, err)
+
+	// the mock signaler context fails the test if an error is thrown
+	ctx, cancel := context.WithCancel(context.Background())
+	signalerCtx := irrecoverable.NewMockSignalerContext(t, ctx)
+	m.Start(signalerCtx)
+	unittest.RequireCloseBefore(t, m.Ready(), 100*time.Millisecond, "ALSP manager did not start")
+
+	unittest.RequireCloseBefore(t, stored, time.Second, "snapshot was not stored after a failure")
+	cancel()
+	unittest.RequireCloseBefore(t, m.Done(), 100*time.Millisecond, "ALSP manager did not stop")
+}
+
 func managerCfgFixture(t *testing.T) *alspmgr.MisbehaviorReportManagerConfig {
 	c, err := config.DefaultConfig()
 	require.NoError(t, err)
@@ -43,6 +43,10 @@ const (
 
 	// UnauthorizedPublishOnChannel is a misbehavior that is reported when a message not authorized to be sent via pubsub is received via pubsub.
 	UnauthorizedPublishOnChannel network.Misbehavior = "unauthorized-pubsub-on-channel"
+
+	// InboundQueueCapExceeded is a misbehavior that is reported when a message is dropped by the networking layer because
+	// its sender already has the maximum number of messages in the inbound message queue.
+	InboundQueueCapExceeded network.Misbehavior = "inbound-queue-cap-exceeded"
 )
 
 func AllMisbehaviorTypes() []network.Misbehavior {
@@ -58,5 +62,6 @@ func AllMisbehaviorTypes() []network.Misbehavior {
 		UnauthorizedUnicastOnChannel,
 		UnauthorizedPublishOnChannel,
 		UnAuthorizedSender,
+		InboundQueueCapExceeded,
 	}
 }
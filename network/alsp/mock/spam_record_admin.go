@@ -0,0 +1,97 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mockalsp
+
+import (
+	flow "github.com/onflow/flow-go/model/flow"
+	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/onflow/flow-go/network/alsp/model"
+)
+
+// SpamRecordAdmin is an autogenerated mock type for the SpamRecordAdmin type
+type SpamRecordAdmin struct {
+	mock.Mock
+}
+
+// ResetSpamRecord provides a mock function with given fields: originId
+func (_m *SpamRecordAdmin) ResetSpamRecord(originId flow.Identifier) bool {
+	ret := _m.Called(originId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetSpamRecord")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(flow.Identifier) bool); ok {
+		r0 = rf(originId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// SpamRecord provides a mock function with given fields: originId
+func (_m *SpamRecordAdmin) SpamRecord(originId flow.Identifier) (*model.ProtocolSpamRecord, bool) {
+	ret := _m.Called(originId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SpamRecord")
+	}
+
+	var r0 *model.ProtocolSpamRecord
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(flow.Identifier) (*model.ProtocolSpamRecord, bool)); ok {
+		return rf(originId)
+	}
+	if rf, ok := ret.Get(0).(func(flow.Identifier) *model.ProtocolSpamRecord); ok {
+		r0 = rf(originId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ProtocolSpamRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(flow.Identifier) bool); ok {
+		r1 = rf(originId)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// SpamRecords provides a mock function with given fields:
+func (_m *SpamRecordAdmin) SpamRecords() []model.ProtocolSpamRecord {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SpamRecords")
+	}
+
+	var r0 []model.ProtocolSpamRecord
+	if rf, ok := ret.Get(0).(func() []model.ProtocolSpamRecord); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ProtocolSpamRecord)
+		}
+	}
+
+	return r0
+}
+
+// NewSpamRecordAdmin creates a new instance of SpamRecordAdmin. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSpamRecordAdmin(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SpamRecordAdmin {
+	mock := &SpamRecordAdmin{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
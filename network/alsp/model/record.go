@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/onflow/flow-go/model/flow"
 )
 
@@ -30,6 +32,17 @@ type ProtocolSpamRecord struct {
 	Penalty float64
 }
 
+// SpamRecordSnapshot is a snapshot of the spam records of all misbehaving nodes. Snapshots are persisted periodically,
+// so that penalties survive restarts of the node rather than giving misbehaving nodes a clean slate.
+type SpamRecordSnapshot struct {
+	// Time is the time the snapshot was taken. When the snapshot is restored, the penalties are decayed for the
+	// time elapsed since the snapshot was taken, i.e., for the downtime of the node.
+	Time time.Time
+
+	// Records are the spam records of all misbehaving nodes at the time of the snapshot.
+	Records []ProtocolSpamRecord
+}
+
 // RecordAdjustFunc is a function that is used to adjust the fields of a ProtocolSpamRecord.
 // The function is called with the current record and should return the adjusted record.
 // Returned error indicates that the adjustment is not applied, and the record should not be updated.
@@ -0,0 +1,24 @@
+package alsp
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/alsp/model"
+)
+
+// SpamRecordAdmin exposes the spam records of the ALSP module for administrative purposes, i.e., to inspect the
+// penalties of misbehaving nodes and to reset the penalties of nodes which are known to be benign.
+type SpamRecordAdmin interface {
+	// SpamRecords returns the spam records of all nodes that have been reported for misbehavior, ordered by node ID.
+	// Note that the returned records are copies of the records in the cache.
+	SpamRecords() []model.ProtocolSpamRecord
+
+	// SpamRecord returns the spam record of the given node.
+	// Returns the record and true if the record exists, nil and false otherwise.
+	// Note that the returned record is a copy of the record in the cache.
+	SpamRecord(originId flow.Identifier) (*model.ProtocolSpamRecord, bool)
+
+	// ResetSpamRecord removes the spam record of the given node, including its penalty, cutoff counter and decay
+	// speed. If the node is disallow-listed by the ALSP module, it is allow-listed again.
+	// Returns true if the record is removed, false otherwise (i.e., the record does not exist).
+	ResetSpamRecord(originId flow.Identifier) bool
+}
@@ -0,0 +1,321 @@
+// Package bandwidth implements the accounting of the bytes of the messages exchanged by the node per channel,
+// direction and role of the remote node, as well as per-channel bandwidth quotas. Quotas allow operators to
+// protect the bandwidth required by critical protocols (e.g. block execution) from traffic spikes on less
+// critical channels (e.g. verification and sync), complementing the per-peer unicast bandwidth rate limiter.
+package bandwidth
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+const (
+	// RoleUnknown is the role accounted for messages exchanged with nodes which are not in the identity table.
+	RoleUnknown = "unknown"
+	// RoleMixed is the role accounted for messages published to nodes of different roles.
+	RoleMixed = "mixed"
+)
+
+// Usage is the bandwidth used on a channel in one direction with nodes of one role.
+type Usage struct {
+	Channel   channels.Channel
+	Direction Direction
+	Role      string
+	Bytes     uint64
+	Messages  uint64
+}
+
+// QuotaUsage is a configured quota together with the messages dropped because they exceeded it.
+type QuotaUsage struct {
+	Quota
+	DroppedBytes    uint64
+	DroppedMessages uint64
+}
+
+// Report is the bandwidth used by the node since the accountant was created.
+type Report struct {
+	// Usage is ordered by channel, direction and role.
+	Usage []Usage
+	// Quotas are ordered by channel and direction.
+	Quotas []QuotaUsage
+}
+
+type usageKey struct {
+	channel   channels.Channel
+	direction Direction
+	role      string
+}
+
+type limiter struct {
+	quota           Quota
+	droppedBytes    *atomic.Uint64
+	droppedMessages *atomic.Uint64
+	// channel limits the bandwidth of the messages of all origins
+	channel *bucket
+
+	mu sync.Mutex
+	// origins limit the bandwidth per origin of inbound messages, if the quota limits the bandwidth per origin.
+	// Nodes which are not in the identity table share the bucket of flow.ZeroID. Hence, the number of buckets is
+	// bounded by the number of nodes which have been in the identity table.
+	origins map[flow.Identifier]*bucket
+}
+
+// reserve takes the bytes of a message of the given size and origin from the per-origin limit of the quota, if any,
+// and from the channel-wide limit. Messages larger than the burst of a limit consume the full burst.
+// Returns false if either limit is exceeded, in which case nothing is taken.
+func (l *limiter) reserve(originID flow.Identifier, size int) (*Reservation, bool) {
+	now := time.Now()
+	reservation := &Reservation{}
+	if l.quota.OriginBytesPerSecond > 0 {
+		l.mu.Lock()
+		origin, ok := l.origins[originID]
+		if !ok {
+			origin = newBucket(l.quota.OriginBytesPerSecond, l.quota.OriginBurst)
+			l.origins[originID] = origin
+		}
+		l.mu.Unlock()
+		n := min(size, l.quota.OriginBurst)
+		if !origin.take(now, n) {
+			return nil, false
+		}
+		reservation.add(origin, n)
+	}
+
+	n := min(size, l.quota.Burst)
+	if !l.channel.take(now, n) {
+		// the bandwidth of an origin is only consumed by the messages allowed on the channel
+		reservation.Cancel()
+		return nil, false
+	}
+	reservation.add(l.channel, n)
+	return reservation, true
+}
+
+// Reservation is the bandwidth of a message taken from the quota of its channel. A nil reservation reserves nothing,
+// and can be cancelled.
+type Reservation struct {
+	buckets []*bucket
+	bytes   []int
+}
+
+func (r *Reservation) add(b *bucket, n int) {
+	r.buckets = append(r.buckets, b)
+	r.bytes = append(r.bytes, n)
+}
+
+// Cancel returns the reserved bandwidth to the quota. It must be called if the message is not sent after all, and
+// at most once.
+func (r *Reservation) Cancel() {
+	if r == nil {
+		return
+	}
+	for i, b := range r.buckets {
+		b.refund(r.bytes[i])
+	}
+}
+
+// Accountant accounts the bytes of the messages exchanged by the node per channel, direction and role of the
+// remote node, and enforces the configured per-channel quotas. Quotas limit the bandwidth of all messages on a
+// channel, and inbound quotas can additionally limit the bandwidth of each origin (see Quota). Nodes which are not in
+// the identity table share one origin limit per channel. Accounted bytes are the size of the encoded messages,
+// excluding the overhead of the libp2p streams and of the GossipSub router.
+//
+// A nil accountant accounts nothing and enforces no quotas, so it can always be installed in the network.
+//
+// All methods are concurrency safe.
+type Accountant struct {
+	log        zerolog.Logger
+	metrics    module.ChannelBandwidthMetrics
+	idProvider module.IdentityProvider
+	// limiters are immutable after construction, hence not protected by the lock
+	limiters map[quotaKey]*limiter
+
+	mu    sync.Mutex
+	usage map[usageKey]*Usage
+}
+
+// NewAccountant creates an accountant enforcing the given quotas.
+// Returns an error if any quota is invalid, or if there are several quotas for the same channel and direction.
+func NewAccountant(
+	log zerolog.Logger,
+	metrics module.ChannelBandwidthMetrics,
+	idProvider module.IdentityProvider,
+	quotas []Quota,
+) (*Accountant, error) {
+	limiters := make(map[quotaKey]*limiter, len(quotas))
+	for _, quota := range quotas {
+		if err := quota.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid quota %s: %w", quota, err)
+		}
+		key := quotaKey{channel: quota.Channel, direction: quota.Direction}
+		if _, ok := limiters[key]; ok {
+			return nil, fmt.Errorf("duplicate %s quota for channel %s", quota.Direction, quota.Channel)
+		}
+		limiters[key] = &limiter{
+			quota:           quota,
+			droppedBytes:    atomic.NewUint64(0),
+			droppedMessages: atomic.NewUint64(0),
+			channel:         newBucket(quota.BytesPerSecond, quota.Burst),
+			origins:         make(map[flow.Identifier]*bucket),
+		}
+	}
+
+	return &Accountant{
+		log:        log.With().Str("component", "bandwidth_accountant").Logger(),
+		metrics:    metrics,
+		idProvider: idProvider,
+		limiters:   limiters,
+		usage:      make(map[usageKey]*Usage),
+	}, nil
+}
+
+// AllowOutbound reserves the bandwidth of a message of the given size from the outbound quota of the given channel.
+// It must be called before sending the message, and OnOutboundSent once the message has been sent. If the message
+// cannot be sent, the returned reservation must be cancelled to return the bandwidth to the quota.
+// Expected errors during normal operations:
+//   - QuotaExceededError if the message exceeds the outbound quota of the channel.
+func (a *Accountant) AllowOutbound(channel channels.Channel, size int) (*Reservation, error) {
+	if a == nil {
+		return nil, nil
+	}
+	return a.enforce(channel, DirectionOutbound, flow.ZeroID, size)
+}
+
+// OnOutboundSent accounts a message of the given size sent on the given channel to the given targets.
+func (a *Accountant) OnOutboundSent(channel channels.Channel, size int, targetIDs ...flow.Identifier) {
+	if a == nil {
+		return
+	}
+
+	role := ""
+	for _, targetID := range targetIDs {
+		targetRole := a.roleOf(targetID)
+		if role != "" && role != targetRole {
+			role = RoleMixed
+			break
+		}
+		role = targetRole
+	}
+	if role == "" {
+		role = RoleUnknown
+	}
+	a.account(channel, DirectionOutbound, role, size)
+}
+
+// OnInbound accounts a message of the given size received on the given channel from the given origin, and checks
+// whether it exceeds the inbound quota of the channel, or the limit of the quota for the origin. Messages are accounted
+// regardless of the quota, since they have consumed bandwidth when received.
+// Expected errors during normal operations:
+//   - QuotaExceededError if the message exceeds the inbound quota of the channel, in which case it must be dropped.
+func (a *Accountant) OnInbound(channel channels.Channel, originID flow.Identifier, size int) error {
+	if a == nil {
+		return nil
+	}
+	role := a.roleOf(originID)
+	a.account(channel, DirectionInbound, role, size)
+	if role == RoleUnknown {
+		originID = flow.ZeroID
+	}
+	_, err := a.enforce(channel, DirectionInbound, originID, size)
+	return err
+}
+
+// Report returns the bandwidth used by the node since the accountant was created.
+func (a *Accountant) Report() Report {
+	if a == nil {
+		return Report{}
+	}
+
+	report := Report{}
+	a.mu.Lock()
+	for _, usage := range a.usage {
+		report.Usage = append(report.Usage, *usage)
+	}
+	a.mu.Unlock()
+	sort.Slice(report.Usage, func(i, j int) bool {
+		x, y := report.Usage[i], report.Usage[j]
+		if x.Channel != y.Channel {
+			return x.Channel < y.Channel
+		}
+		if x.Direction != y.Direction {
+			return x.Direction < y.Direction
+		}
+		return x.Role < y.Role
+	})
+
+	for _, l := range a.limiters {
+		report.Quotas = append(report.Quotas, QuotaUsage{
+			Quota:           l.quota,
+			DroppedBytes:    l.droppedBytes.Load(),
+			DroppedMessages: l.droppedMessages.Load(),
+		})
+	}
+	sort.Slice(report.Quotas, func(i, j int) bool {
+		x, y := report.Quotas[i], report.Quotas[j]
+		if x.Channel != y.Channel {
+			return x.Channel < y.Channel
+		}
+		return x.Direction < y.Direction
+	})
+	return report
+}
+
+// enforce reserves the bandwidth of a message of the given size from the quota of the given channel and direction,
+// if any.
+// Expected errors during normal operations:
+//   - QuotaExceededError if the message exceeds the quota.
+func (a *Accountant) enforce(channel channels.Channel, direction Direction, originID flow.Identifier, size int) (*Reservation, error) {
+	l, ok := a.limiters[quotaKey{channel: quotaChannel(channel), direction: direction}]
+	if !ok {
+		return nil, nil
+	}
+
+	reservation, ok := l.reserve(originID, size)
+	if ok {
+		return reservation, nil
+	}
+
+	l.droppedBytes.Add(uint64(size))
+	l.droppedMessages.Inc()
+	a.metrics.ChannelQuotaExceeded(channel.String(), string(direction), size)
+	a.log.Debug().
+		Str("channel", channel.String()).
+		Str("direction", string(direction)).
+		Hex("origin_id", logging.ID(originID)).
+		Int("size", size).
+		Msg("dropping message exceeding the bandwidth quota of its channel")
+	return nil, NewQuotaExceededError(channel, direction, size)
+}
+
+func (a *Accountant) account(channel channels.Channel, direction Direction, role string, size int) {
+	a.metrics.ChannelBytes(channel.String(), string(direction), role, size)
+
+	key := usageKey{channel: channel, direction: direction, role: role}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	usage, ok := a.usage[key]
+	if !ok {
+		usage = &Usage{Channel: channel, Direction: direction, Role: role}
+		a.usage[key] = usage
+	}
+	usage.Bytes += uint64(size)
+	usage.Messages++
+}
+
+func (a *Accountant) roleOf(nodeID flow.Identifier) string {
+	identity, ok := a.idProvider.ByNodeID(nodeID)
+	if !ok {
+		return RoleUnknown
+	}
+	return identity.Role.String()
+}
@@ -0,0 +1,239 @@
+package bandwidth_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/bandwidth"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestAccountant_Usage tests that the accountant accounts the bytes and messages per channel, direction and role
+// of the remote node, and that messages published to nodes of different roles are accounted as mixed.
+func TestAccountant_Usage(t *testing.T) {
+	consensus := unittest.IdentityFixture(unittest.WithRole(flow.RoleConsensus))
+	execution := unittest.IdentityFixture(unittest.WithRole(flow.RoleExecution))
+	unknown := unittest.IdentifierFixture()
+	idProvider := mock.NewIdentityProvider(t)
+	idProvider.On("ByNodeID", consensus.NodeID).Return(consensus, true)
+	idProvider.On("ByNodeID", execution.NodeID).Return(execution, true)
+	idProvider.On("ByNodeID", unknown).Return(nil, false)
+
+	accountant, err := bandwidth.NewAccountant(unittest.Logger(), metrics.NewNoopCollector(), idProvider, nil)
+	require.NoError(t, err)
+
+	_, err = accountant.AllowOutbound(channels.SyncCommittee, 100)
+	require.NoError(t, err)
+	accountant.OnOutboundSent(channels.SyncCommittee, 100, consensus.NodeID)
+	accountant.OnOutboundSent(channels.SyncCommittee, 50, consensus.NodeID)
+	accountant.OnOutboundSent(channels.SyncCommittee, 10, consensus.NodeID, execution.NodeID)
+	require.NoError(t, accountant.OnInbound(channels.SyncCommittee, execution.NodeID, 20))
+	require.NoError(t, accountant.OnInbound(channels.RequestChunks, unknown, 30))
+
+	require.Equal(t, bandwidth.Report{
+		Usage: []bandwidth.Usage{
+			{Channel: channels.RequestChunks, Direction: bandwidth.DirectionInbound, Role: bandwidth.RoleUnknown, Bytes: 30, Messages: 1},
+			{Channel: channels.SyncCommittee, Direction: bandwidth.DirectionInbound, Role: flow.RoleExecution.String(), Bytes: 20, Messages: 1},
+			{Channel: channels.SyncCommittee, Direction: bandwidth.DirectionOutbound, Role: flow.RoleConsensus.String(), Bytes: 150, Messages: 2},
+			{Channel: channels.SyncCommittee, Direction: bandwidth.DirectionOutbound, Role: bandwidth.RoleMixed, Bytes: 10, Messages: 1},
+		},
+	}, accountant.Report())
+}
+
+// TestAccountant_Quotas tests that messages exceeding the quota of their channel and direction are rejected and
+// reported, while messages on other channels or in the other direction are not affected.
+func TestAccountant_Quotas(t *testing.T) {
+	nodeID := unittest.IdentifierFixture()
+	idProvider := mock.NewIdentityProvider(t)
+	idProvider.On("ByNodeID", nodeID).Return(nil, false)
+
+	quota := bandwidth.Quota{
+		Channel:        channels.RequestChunks,
+		Direction:      bandwidth.DirectionInbound,
+		BytesPerSecond: 1,
+		Burst:          100,
+	}
+	accountant, err := bandwidth.NewAccountant(unittest.Logger(), metrics.NewNoopCollector(), idProvider, []bandwidth.Quota{quota})
+	require.NoError(t, err)
+
+	// the burst allows 100 bytes at once, after which the quota is exhausted
+	require.NoError(t, accountant.OnInbound(channels.RequestChunks, nodeID, 60))
+	require.NoError(t, accountant.OnInbound(channels.RequestChunks, nodeID, 40))
+	err = accountant.OnInbound(channels.RequestChunks, nodeID, 60)
+	require.True(t, bandwidth.IsQuotaExceededError(err), err)
+
+	// the quota does not apply to other channels and to the other direction
+	require.NoError(t, accountant.OnInbound(channels.SyncCommittee, nodeID, 1000))
+	_, err = accountant.AllowOutbound(channels.RequestChunks, 1000)
+	require.NoError(t, err)
+
+	report := accountant.Report()
+	require.Equal(t, []bandwidth.QuotaUsage{{Quota: quota, DroppedBytes: 60, DroppedMessages: 1}}, report.Quotas)
+	// messages dropped because of the quota have been received, hence they are accounted
+	require.Equal(t, uint64(160), report.Usage[0].Bytes)
+	require.Equal(t, uint64(3), report.Usage[0].Messages)
+}
+
+// TestAccountant_InboundQuotaPerOrigin tests that the origin limit of an inbound quota applies to each origin
+// separately, so that an origin exhausting its limit does not affect other origins, while origins which are not in
+// the identity table share a limit. The channel-wide limit applies to all origins together.
+func TestAccountant_InboundQuotaPerOrigin(t *testing.T) {
+	flooder := unittest.IdentityFixture(unittest.WithRole(flow.RoleVerification))
+	other := unittest.IdentityFixture(unittest.WithRole(flow.RoleVerification))
+	third := unittest.IdentityFixture(unittest.WithRole(flow.RoleVerification))
+	unknown1 := unittest.IdentifierFixture()
+	unknown2 := unittest.IdentifierFixture()
+	idProvider := mock.NewIdentityProvider(t)
+	for _, identity := range []*flow.Identity{flooder, other, third} {
+		idProvider.On("ByNodeID", identity.NodeID).Return(identity, true)
+	}
+	idProvider.On("ByNodeID", unknown1).Return(nil, false)
+	idProvider.On("ByNodeID", unknown2).Return(nil, false)
+
+	accountant, err := bandwidth.NewAccountant(unittest.Logger(), metrics.NewNoopCollector(), idProvider, []bandwidth.Quota{{
+		Channel:              channels.RequestChunks,
+		Direction:            bandwidth.DirectionInbound,
+		BytesPerSecond:       1,
+		Burst:                350,
+		OriginBytesPerSecond: 1,
+		OriginBurst:          100,
+	}})
+	require.NoError(t, err)
+
+	require.NoError(t, accountant.OnInbound(channels.RequestChunks, flooder.NodeID, 100))
+	err = accountant.OnInbound(channels.RequestChunks, flooder.NodeID, 100)
+	require.True(t, bandwidth.IsQuotaExceededError(err), err)
+	require.NoError(t, accountant.OnInbound(channels.RequestChunks, other.NodeID, 100))
+
+	require.NoError(t, accountant.OnInbound(channels.RequestChunks, unknown1, 100))
+	err = accountant.OnInbound(channels.RequestChunks, unknown2, 100)
+	require.True(t, bandwidth.IsQuotaExceededError(err), err)
+
+	// the channel-wide limit is exhausted by the origins together, which does not consume the limit of the origin
+	err = accountant.OnInbound(channels.RequestChunks, third.NodeID, 100)
+	require.True(t, bandwidth.IsQuotaExceededError(err), err)
+	require.NoError(t, accountant.OnInbound(channels.RequestChunks, third.NodeID, 50))
+
+	require.Equal(t, uint64(3), accountant.Report().Quotas[0].DroppedMessages)
+}
+
+// TestAccountant_ChannelQuota tests that quotas without origin limit apply to the messages of all origins together.
+func TestAccountant_ChannelQuota(t *testing.T) {
+	origins := unittest.IdentityListFixture(3, unittest.WithRole(flow.RoleVerification))
+	idProvider := mock.NewIdentityProvider(t)
+	for _, identity := range origins {
+		idProvider.On("ByNodeID", identity.NodeID).Return(identity, true)
+	}
+
+	accountant, err := bandwidth.NewAccountant(unittest.Logger(), metrics.NewNoopCollector(), idProvider, []bandwidth.Quota{{
+		Channel:        channels.RequestChunks,
+		Direction:      bandwidth.DirectionInbound,
+		BytesPerSecond: 1,
+		Burst:          200,
+	}})
+	require.NoError(t, err)
+
+	require.NoError(t, accountant.OnInbound(channels.RequestChunks, origins[0].NodeID, 100))
+	require.NoError(t, accountant.OnInbound(channels.RequestChunks, origins[1].NodeID, 100))
+	err = accountant.OnInbound(channels.RequestChunks, origins[2].NodeID, 100)
+	require.True(t, bandwidth.IsQuotaExceededError(err), err)
+}
+
+// TestAccountant_ClusterChannelQuota tests that quotas configured for a cluster channel prefix apply to the channels
+// of all clusters with that prefix.
+func TestAccountant_ClusterChannelQuota(t *testing.T) {
+	accountant, err := bandwidth.NewAccountant(unittest.Logger(), metrics.NewNoopCollector(), mock.NewIdentityProvider(t), []bandwidth.Quota{{
+		Channel:        channels.SyncClusterPrefix,
+		Direction:      bandwidth.DirectionOutbound,
+		BytesPerSecond: 1,
+		Burst:          100,
+	}})
+	require.NoError(t, err)
+
+	_, err = accountant.AllowOutbound(channels.SyncCluster(flow.ChainID("cluster-1")), 100)
+	require.NoError(t, err)
+	_, err = accountant.AllowOutbound(channels.SyncCluster(flow.ChainID("cluster-2")), 100)
+	require.True(t, bandwidth.IsQuotaExceededError(err), err)
+	_, err = accountant.AllowOutbound(channels.ConsensusCluster(flow.ChainID("cluster-1")), 100)
+	require.NoError(t, err)
+
+	// quotas must be configured for the cluster channel prefix
+	_, err = bandwidth.NewAccountant(unittest.Logger(), metrics.NewNoopCollector(), mock.NewIdentityProvider(t), []bandwidth.Quota{{
+		Channel:        channels.SyncCluster(flow.ChainID("cluster-1")),
+		Direction:      bandwidth.DirectionOutbound,
+		BytesPerSecond: 1,
+		Burst:          100,
+	}})
+	require.Error(t, err)
+}
+
+// TestAccountant_CancelOutbound tests that cancelling the reservation of a message which could not be sent returns
+// its bandwidth to the quota.
+func TestAccountant_CancelOutbound(t *testing.T) {
+	accountant, err := bandwidth.NewAccountant(unittest.Logger(), metrics.NewNoopCollector(), mock.NewIdentityProvider(t), []bandwidth.Quota{{
+		Channel:        channels.SyncCommittee,
+		Direction:      bandwidth.DirectionOutbound,
+		BytesPerSecond: 1,
+		Burst:          100,
+	}})
+	require.NoError(t, err)
+
+	reservation, err := accountant.AllowOutbound(channels.SyncCommittee, 100)
+	require.NoError(t, err)
+	_, err = accountant.AllowOutbound(channels.SyncCommittee, 100)
+	require.True(t, bandwidth.IsQuotaExceededError(err), err)
+
+	reservation.Cancel()
+	_, err = accountant.AllowOutbound(channels.SyncCommittee, 100)
+	require.NoError(t, err)
+}
+
+// TestAccountant_MessageLargerThanBurst tests that a message larger than the burst of the quota is allowed if the
+// full burst is available.
+func TestAccountant_MessageLargerThanBurst(t *testing.T) {
+	accountant, err := bandwidth.NewAccountant(unittest.Logger(), metrics.NewNoopCollector(), mock.NewIdentityProvider(t), []bandwidth.Quota{{
+		Channel:        channels.SyncCommittee,
+		Direction:      bandwidth.DirectionOutbound,
+		BytesPerSecond: 1,
+		Burst:          100,
+	}})
+	require.NoError(t, err)
+
+	_, err = accountant.AllowOutbound(channels.SyncCommittee, 1000)
+	require.NoError(t, err)
+	_, err = accountant.AllowOutbound(channels.SyncCommittee, 1000)
+	require.True(t, bandwidth.IsQuotaExceededError(err), err)
+}
+
+// TestAccountant_InvalidQuotas tests that the accountant cannot be created with invalid or duplicate quotas.
+func TestAccountant_InvalidQuotas(t *testing.T) {
+	quota := bandwidth.Quota{
+		Channel:        channels.SyncCommittee,
+		Direction:      bandwidth.DirectionOutbound,
+		BytesPerSecond: 1,
+		Burst:          1,
+	}
+	_, err := bandwidth.NewAccountant(unittest.Logger(), metrics.NewNoopCollector(), mock.NewIdentityProvider(t), []bandwidth.Quota{quota, quota})
+	require.Error(t, err)
+
+	quota.BytesPerSecond = 0
+	_, err = bandwidth.NewAccountant(unittest.Logger(), metrics.NewNoopCollector(), mock.NewIdentityProvider(t), []bandwidth.Quota{quota})
+	require.Error(t, err)
+}
+
+// TestAccountant_Nil tests that a nil accountant accounts nothing and enforces no quotas.
+func TestAccountant_Nil(t *testing.T) {
+	var accountant *bandwidth.Accountant
+
+	reservation, err := accountant.AllowOutbound(channels.SyncCommittee, 1000)
+	require.NoError(t, err)
+	reservation.Cancel()
+	accountant.OnOutboundSent(channels.SyncCommittee, 1000, unittest.IdentifierFixture())
+	require.NoError(t, accountant.OnInbound(channels.SyncCommittee, unittest.IdentifierFixture(), 1000))
+	require.Equal(t, bandwidth.Report{}, accountant.Report())
+}
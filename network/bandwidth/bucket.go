@@ -0,0 +1,51 @@
+package bandwidth
+
+import (
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket of bytes. Unlike rate.Limiter, it allows returning the bytes of messages which have not
+// been sent after all, regardless of the time elapsed since they were taken.
+//
+// All methods are concurrency safe.
+type bucket struct {
+	bytesPerSecond float64
+	burst          float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newBucket creates a full bucket.
+func newBucket(bytesPerSecond int, burst int) *bucket {
+	return &bucket{
+		bytesPerSecond: float64(bytesPerSecond),
+		burst:          float64(burst),
+		tokens:         float64(burst),
+		last:           time.Now(),
+	}
+}
+
+// take takes n bytes from the bucket if they are available at the given time, and reports whether they were taken.
+func (b *bucket) take(now time.Time, n int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if now.After(b.last) {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.bytesPerSecond)
+		b.last = now
+	}
+	if b.tokens < float64(n) {
+		return false
+	}
+	b.tokens -= float64(n)
+	return true
+}
+
+// refund returns n previously taken bytes to the bucket.
+func (b *bucket) refund(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.burst, b.tokens+float64(n))
+}
@@ -0,0 +1,34 @@
+package bandwidth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go/network/channels"
+)
+
+// QuotaExceededError indicates that a message was dropped because it exceeded the bandwidth quota of its channel.
+type QuotaExceededError struct {
+	Channel   channels.Channel
+	Direction Direction
+	Size      int
+}
+
+// NewQuotaExceededError creates a QuotaExceededError for a message of the given size on the given channel.
+func NewQuotaExceededError(channel channels.Channel, direction Direction, size int) error {
+	return QuotaExceededError{
+		Channel:   channel,
+		Direction: direction,
+		Size:      size,
+	}
+}
+
+func (e QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s message of %d bytes exceeds the bandwidth quota of channel %s", e.Direction, e.Size, e.Channel)
+}
+
+// IsQuotaExceededError returns whether the given error is a QuotaExceededError.
+func IsQuotaExceededError(err error) bool {
+	var e QuotaExceededError
+	return errors.As(err, &e)
+}
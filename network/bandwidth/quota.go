@@ -0,0 +1,169 @@
+package bandwidth
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/onflow/flow-go/network/channels"
+)
+
+// Direction is the direction of the messages accounted by the accountant.
+type Direction string
+
+const (
+	// DirectionInbound denotes the messages received by the node.
+	DirectionInbound Direction = "inbound"
+	// DirectionOutbound denotes the messages sent by the node.
+	DirectionOutbound Direction = "outbound"
+)
+
+// ParseDirection parses the given string into a direction.
+// Returns an error if the string is not a valid direction.
+func ParseDirection(str string) (Direction, error) {
+	switch d := Direction(str); d {
+	case DirectionInbound, DirectionOutbound:
+		return d, nil
+	default:
+		return "", fmt.Errorf("invalid direction %q, expected one of: %s, %s", str, DirectionInbound, DirectionOutbound)
+	}
+}
+
+// Quota limits the bandwidth of the messages exchanged on a channel in one direction. Messages exceeding the quota
+// are dropped: outbound messages are not sent and inbound messages are not delivered to the engines.
+//
+// Inbound quotas can additionally limit the bandwidth of each origin, so that a single node exhausting the quota of
+// the channel does not cause the messages of the other nodes to be dropped. The channel-wide limit still applies to
+// the messages of all origins together.
+//
+// The quotas of cluster channels are configured for the cluster channel prefix (e.g. sync-cluster), and apply to the
+// channels of all clusters with that prefix.
+type Quota struct {
+	Channel   channels.Channel
+	Direction Direction
+	// BytesPerSecond is the sustained bandwidth allowed on the channel.
+	BytesPerSecond int
+	// Burst is the maximum number of bytes allowed at once. Messages larger than the burst are allowed if the
+	// full burst is available.
+	Burst int
+	// OriginBytesPerSecond is the sustained bandwidth allowed on the channel for each origin of inbound messages,
+	// zero if the bandwidth is not limited per origin.
+	OriginBytesPerSecond int
+	// OriginBurst is the maximum number of bytes allowed at once for each origin of inbound messages.
+	OriginBurst int
+}
+
+// ParseQuota parses a quota of the form
+// "<channel>:<inbound|outbound>:<bytes-per-second>[:<burst>[:<origin-bytes-per-second>[:<origin-burst>]]]".
+// The burst defaults to the bytes per second if omitted, and the origin burst to the origin bytes per second.
+// Returns an error if the quota is malformed.
+func ParseQuota(str string) (Quota, error) {
+	parts := strings.Split(str, ":")
+	if len(parts) < 3 || len(parts) > 6 {
+		return Quota{}, fmt.Errorf("invalid quota %q, expected <channel>:<inbound|outbound>:<bytes-per-second>[:<burst>[:<origin-bytes-per-second>[:<origin-burst>]]]", str)
+	}
+
+	quota := Quota{Channel: channels.Channel(parts[0])}
+	if err := channels.IsValidFlowChannel(quota.Channel); err != nil {
+		return Quota{}, fmt.Errorf("invalid quota %q: %w", str, err)
+	}
+	var err error
+	quota.Direction, err = ParseDirection(parts[1])
+	if err != nil {
+		return Quota{}, fmt.Errorf("invalid quota %q: %w", str, err)
+	}
+	quota.BytesPerSecond, err = strconv.Atoi(parts[2])
+	if err != nil {
+		return Quota{}, fmt.Errorf("invalid quota %q: invalid bytes per second: %w", str, err)
+	}
+	quota.Burst = quota.BytesPerSecond
+	if len(parts) > 3 {
+		quota.Burst, err = strconv.Atoi(parts[3])
+		if err != nil {
+			return Quota{}, fmt.Errorf("invalid quota %q: invalid burst: %w", str, err)
+		}
+	}
+	if len(parts) > 4 {
+		quota.OriginBytesPerSecond, err = strconv.Atoi(parts[4])
+		if err != nil {
+			return Quota{}, fmt.Errorf("invalid quota %q: invalid origin bytes per second: %w", str, err)
+		}
+		quota.OriginBurst = quota.OriginBytesPerSecond
+	}
+	if len(parts) > 5 {
+		quota.OriginBurst, err = strconv.Atoi(parts[5])
+		if err != nil {
+			return Quota{}, fmt.Errorf("invalid quota %q: invalid origin burst: %w", str, err)
+		}
+	}
+
+	if err := quota.Validate(); err != nil {
+		return Quota{}, fmt.Errorf("invalid quota %q: %w", str, err)
+	}
+	return quota, nil
+}
+
+// ParseQuotas parses the given quotas, see ParseQuota.
+// Returns an error if any quota is malformed.
+func ParseQuotas(strs []string) ([]Quota, error) {
+	quotas := make([]Quota, 0, len(strs))
+	for _, str := range strs {
+		quota, err := ParseQuota(str)
+		if err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, quota)
+	}
+	return quotas, nil
+}
+
+// Validate returns an error if the quota is invalid.
+func (q Quota) Validate() error {
+	if _, err := ParseDirection(string(q.Direction)); err != nil {
+		return err
+	}
+	if prefix, ok := channels.ClusterChannelPrefix(q.Channel); ok && q.Channel.String() != prefix {
+		return fmt.Errorf("quotas of cluster channels must be configured for the cluster channel prefix %s", prefix)
+	}
+	if q.BytesPerSecond <= 0 {
+		return fmt.Errorf("bytes per second must be positive, got %d", q.BytesPerSecond)
+	}
+	if q.Burst <= 0 {
+		return fmt.Errorf("burst must be positive, got %d", q.Burst)
+	}
+	if q.OriginBytesPerSecond == 0 && q.OriginBurst == 0 {
+		return nil
+	}
+	if q.Direction != DirectionInbound {
+		return fmt.Errorf("origin limits only apply to inbound quotas")
+	}
+	if q.OriginBytesPerSecond <= 0 {
+		return fmt.Errorf("origin bytes per second must be positive, got %d", q.OriginBytesPerSecond)
+	}
+	if q.OriginBurst <= 0 {
+		return fmt.Errorf("origin burst must be positive, got %d", q.OriginBurst)
+	}
+	return nil
+}
+
+// String returns the quota in the format accepted by ParseQuota.
+func (q Quota) String() string {
+	if q.OriginBytesPerSecond == 0 {
+		return fmt.Sprintf("%s:%s:%d:%d", q.Channel, q.Direction, q.BytesPerSecond, q.Burst)
+	}
+	return fmt.Sprintf("%s:%s:%d:%d:%d:%d", q.Channel, q.Direction, q.BytesPerSecond, q.Burst, q.OriginBytesPerSecond, q.OriginBurst)
+}
+
+type quotaKey struct {
+	channel   channels.Channel
+	direction Direction
+}
+
+// quotaChannel returns the channel of the quotas applying to messages on the given channel, i.e. the cluster
+// channel prefix for cluster channels, and the channel itself otherwise.
+func quotaChannel(channel channels.Channel) channels.Channel {
+	if prefix, ok := channels.ClusterChannelPrefix(channel); ok {
+		return channels.Channel(prefix)
+	}
+	return channel
+}
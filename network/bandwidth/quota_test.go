@@ -0,0 +1,65 @@
+package bandwidth_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/network/bandwidth"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+// TestParseQuota tests parsing quotas with and without burst, and that malformed quotas are rejected.
+func TestParseQuota(t *testing.T) {
+	quota, err := bandwidth.ParseQuota("sync-committee:outbound:1000")
+	require.NoError(t, err)
+	require.Equal(t, bandwidth.Quota{
+		Channel:        channels.SyncCommittee,
+		Direction:      bandwidth.DirectionOutbound,
+		BytesPerSecond: 1000,
+		Burst:          1000,
+	}, quota)
+
+	quota, err = bandwidth.ParseQuota("request-chunks:inbound:1000:5000")
+	require.NoError(t, err)
+	require.Equal(t, bandwidth.Quota{
+		Channel:        channels.RequestChunks,
+		Direction:      bandwidth.DirectionInbound,
+		BytesPerSecond: 1000,
+		Burst:          5000,
+	}, quota)
+	require.Equal(t, "request-chunks:inbound:1000:5000", quota.String())
+
+	quota, err = bandwidth.ParseQuota("request-chunks:inbound:1000:5000:100")
+	require.NoError(t, err)
+	require.Equal(t, bandwidth.Quota{
+		Channel:              channels.RequestChunks,
+		Direction:            bandwidth.DirectionInbound,
+		BytesPerSecond:       1000,
+		Burst:                5000,
+		OriginBytesPerSecond: 100,
+		OriginBurst:          100,
+	}, quota)
+	require.Equal(t, "request-chunks:inbound:1000:5000:100:100", quota.String())
+
+	quota, err = bandwidth.ParseQuota("sync-cluster:outbound:1000")
+	require.NoError(t, err)
+	require.Equal(t, channels.Channel(channels.SyncClusterPrefix), quota.Channel)
+
+	for _, str := range []string{
+		"",
+		"sync-committee:outbound",
+		"sync-committee:outbound:1000:1000:1000",
+		"request-chunks:inbound:1000:1000:1000:1000:1000",
+		"request-chunks:inbound:1000:1000:1000:-1",
+		"sync-cluster-cluster-1:outbound:1000",
+		"unknown:outbound:1000",
+		"sync-committee:both:1000",
+		"sync-committee:outbound:fast",
+		"sync-committee:outbound:0",
+		"sync-committee:outbound:1000:-1",
+	} {
+		_, err := bandwidth.ParseQuota(str)
+		require.Error(t, err, str)
+	}
+}
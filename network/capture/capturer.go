@@ -0,0 +1,364 @@
+// Package capture implements an opt-in capture of the Flow messages exchanged by the network
+// layer, to debug the interaction with misbehaving peers. Captured messages are written to a
+// rotating set of local files, which can be read with ReadCapture and decoded with the
+// `decode-network-capture` util command.
+package capture
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/codec"
+	"github.com/onflow/flow-go/network/message"
+)
+
+// Config configures a capture session.
+type Config struct {
+	// Dir is the directory the capture files are written to.
+	Dir string
+	// MaxFileBytes is the size after which a new capture file is started.
+	MaxFileBytes int64
+	// MaxFiles is the number of capture files retained, older files are removed.
+	MaxFiles int
+	// BufferSize is the number of records buffered for writing. Records are dropped
+	// while the buffer is full.
+	BufferSize int
+	// IncludePayload determines whether the encoded message is captured in addition to its metadata.
+	IncludePayload bool
+	// Channels restricts the capture to messages on the given channels. If empty, messages
+	// on all channels are captured.
+	Channels []channels.Channel
+	// Peers restricts the capture to messages exchanged with the given libp2p peers.
+	Peers []peer.ID
+	// NodeIDs restricts the capture to messages exchanged with the given Flow nodes, i.e.
+	// inbound messages originating from and outbound messages targeting one of the nodes.
+	// If both Peers and NodeIDs are empty, messages exchanged with all peers are captured.
+	NodeIDs flow.IdentifierList
+}
+
+// DefaultConfig returns the default configuration, which retains up to 256 MiB of captured
+// messages on all channels, without payloads.
+func DefaultConfig(dir string) Config {
+	return Config{
+		Dir:          dir,
+		MaxFileBytes: 32 * 1024 * 1024,
+		MaxFiles:     8,
+		BufferSize:   10_000,
+	}
+}
+
+// Status is the state of the capturer.
+type Status struct {
+	Enabled bool
+	// Config is the configuration of the current capture session, nil if disabled.
+	Config *Config
+	// Captured is the number of records captured in the current session.
+	Captured uint64
+	// Dropped is the number of records dropped in the current session because the buffer was full.
+	Dropped uint64
+	// Failed is set if writing to the capture files failed, after which records are discarded.
+	Failed bool
+}
+
+// Capturer records the inbound and outbound messages of the network layer while a capture
+// session is enabled. Sessions are started and stopped at runtime, e.g. via admin command.
+// While disabled, the capture hooks return immediately, so the capturer can always be
+// installed in the network.
+//
+// Records are buffered and written in the background, so the capturer never blocks the
+// network. Records are dropped while the buffer is full.
+//
+// All methods are concurrency safe.
+type Capturer struct {
+	log    zerolog.Logger
+	nodeID flow.Identifier
+
+	// enabled allows the capture hooks to skip acquiring the lock while no session is running
+	enabled *atomic.Bool
+	// mu guards session; the hooks hold the read lock while queueing records, so that the
+	// records channel is not closed concurrently
+	mu      sync.RWMutex
+	session *session
+}
+
+// NewCapturer creates a disabled capturer for the node with the given ID.
+func NewCapturer(log zerolog.Logger, nodeID flow.Identifier) *Capturer {
+	return &Capturer{
+		log:     log.With().Str("component", "network_capture").Logger(),
+		nodeID:  nodeID,
+		enabled: atomic.NewBool(false),
+	}
+}
+
+// Enable starts a new capture session with the given configuration, which replaces the
+// current session if the capturer is already enabled. If the new session cannot be started,
+// the capturer is left disabled.
+// No errors are expected during normal operation, provided the configuration is valid
+// and the capture directory is writable.
+func (c *Capturer) Enable(config Config) error {
+	if config.Dir == "" {
+		return fmt.Errorf("capture directory must be set")
+	}
+	if config.MaxFileBytes <= 0 || config.MaxFiles <= 0 || config.BufferSize <= 0 {
+		return fmt.Errorf("max file bytes, max files and buffer size must be positive")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stop()
+	s, err := newSession(c.log, config)
+	if err != nil {
+		return fmt.Errorf("could not start capture session: %w", err)
+	}
+	c.session = s
+	c.enabled.Store(true)
+	c.log.Info().
+		Str("dir", config.Dir).
+		Bool("include_payload", config.IncludePayload).
+		Int("channels", len(config.Channels)).
+		Int("peers", len(config.Peers)+len(config.NodeIDs)).
+		Msg("network message capture enabled")
+	return nil
+}
+
+// Disable stops the current capture session, if any, after writing all buffered records.
+func (c *Capturer) Disable() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session != nil {
+		c.stop()
+		c.log.Info().Msg("network message capture disabled")
+	}
+}
+
+// stop stops the current session and waits until its records are written.
+// Must be called while holding the write lock.
+func (c *Capturer) stop() {
+	if c.session == nil {
+		return
+	}
+	c.enabled.Store(false)
+	c.session.close()
+	c.session = nil
+}
+
+// Status returns the state of the capturer.
+func (c *Capturer) Status() Status {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.session == nil {
+		return Status{}
+	}
+	config := c.session.config
+	return Status{
+		Enabled:  true,
+		Config:   &config,
+		Captured: c.session.captured.Load(),
+		Dropped:  c.session.dropped.Load(),
+		Failed:   c.session.failed.Load(),
+	}
+}
+
+// CaptureInbound captures the message received from the given peer, whose Flow ID is originID.
+// Messages are captured before they are decoded, so that malformed messages are captured as well.
+func (c *Capturer) CaptureInbound(msg *message.Message, protocol message.ProtocolType, peerID peer.ID, originID flow.Identifier) {
+	if c == nil || !c.enabled.Load() {
+		return
+	}
+	c.capture(&Record{
+		Direction: Inbound,
+		Protocol:  protocol,
+		Channel:   channels.Channel(msg.ChannelID),
+		PeerID:    peerID.String(),
+		OriginID:  originID,
+		Size:      msg.Size(),
+	}, msg.Payload)
+}
+
+// CaptureOutbound captures the message sent on the given channel with the given protocol. For
+// unicast messages, peerID is the peer the message was sent to, for pubsub messages it is empty.
+func (c *Capturer) CaptureOutbound(protocol message.ProtocolType, channel channels.Channel, msg network.OutgoingMessageScope, peerID peer.ID) {
+	if c == nil || !c.enabled.Load() {
+		return
+	}
+	rec := &Record{
+		Direction: Outbound,
+		Protocol:  protocol,
+		Channel:   channel,
+		OriginID:  c.nodeID,
+		TargetIDs: msg.TargetIds(),
+		Size:      msg.Size(),
+	}
+	if peerID != "" {
+		rec.PeerID = peerID.String()
+	}
+	c.capture(rec, msg.Proto().Payload)
+}
+
+// capture completes the record and queues it for writing, if it passes the session's filters.
+func (c *Capturer) capture(rec *Record, payload []byte) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s := c.session
+	if s == nil || !s.matches(rec) {
+		return
+	}
+	rec.Timestamp = time.Now().UnixNano()
+	if code, err := codec.MessageCodeFromPayload(payload); err == nil {
+		rec.Code = code
+	}
+	if s.config.IncludePayload {
+		// the payload may be retained by the network layer, so it is copied before being queued
+		rec.Payload = append([]byte(nil), payload...)
+	}
+	s.queue(rec)
+}
+
+// session is a single capture session, which writes records to the configured directory
+// until it is closed.
+type session struct {
+	log      zerolog.Logger
+	config   Config
+	channels map[channels.Channel]struct{}
+	peers    map[string]struct{}
+	nodeIDs  map[flow.Identifier]struct{}
+
+	writer   *rotatingWriter
+	records  chan *Record
+	done     chan struct{}
+	captured *atomic.Uint64
+	dropped  *atomic.Uint64
+	failed   *atomic.Bool
+}
+
+// newSession creates a session, which starts a new capture file in the configured directory
+// and writes records in the background until it is closed.
+// No errors are expected during normal operation.
+func newSession(log zerolog.Logger, config Config) (*session, error) {
+	writer, err := newRotatingWriter(config.Dir, config.MaxFileBytes, config.MaxFiles)
+	if err != nil {
+		return nil, err
+	}
+	s := &session{
+		log:      log,
+		config:   config,
+		channels: make(map[channels.Channel]struct{}, len(config.Channels)),
+		peers:    make(map[string]struct{}, len(config.Peers)),
+		nodeIDs:  make(map[flow.Identifier]struct{}, len(config.NodeIDs)),
+		writer:   writer,
+		records:  make(chan *Record, config.BufferSize),
+		done:     make(chan struct{}),
+		captured: atomic.NewUint64(0),
+		dropped:  atomic.NewUint64(0),
+		failed:   atomic.NewBool(false),
+	}
+	for _, channel := range config.Channels {
+		s.channels[channel] = struct{}{}
+	}
+	for _, peerID := range config.Peers {
+		s.peers[peerID.String()] = struct{}{}
+	}
+	for _, nodeID := range config.NodeIDs {
+		s.nodeIDs[nodeID] = struct{}{}
+	}
+	go s.writeRecords()
+	return s, nil
+}
+
+// matches returns true if the record passes the channel and peer filters of the session.
+func (s *session) matches(rec *Record) bool {
+	if len(s.channels) > 0 {
+		if _, ok := s.channels[rec.Channel]; !ok {
+			return false
+		}
+	}
+	if len(s.peers) == 0 && len(s.nodeIDs) == 0 {
+		return true
+	}
+	if _, ok := s.peers[rec.PeerID]; ok && rec.PeerID != "" {
+		return true
+	}
+	if rec.Direction == Inbound {
+		_, ok := s.nodeIDs[rec.OriginID]
+		return ok
+	}
+	for _, targetID := range rec.TargetIDs {
+		if _, ok := s.nodeIDs[targetID]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// queue queues the record for writing, or drops it if the buffer is full.
+func (s *session) queue(rec *Record) {
+	select {
+	case s.records <- rec:
+		s.captured.Inc()
+	default:
+		s.dropped.Inc()
+	}
+}
+
+// close stops accepting records and waits until the queued records are written and the
+// capture file is closed. Must not be called concurrently with queue.
+func (s *session) close() {
+	close(s.records)
+	<-s.done
+}
+
+// writeRecords writes queued records to the capture files and flushes them periodically,
+// until the records channel is closed.
+func (s *session) writeRecords() {
+	defer close(s.done)
+
+	flushTicker := time.NewTicker(time.Second)
+	defer flushTicker.Stop()
+
+	for {
+		select {
+		case rec, ok := <-s.records:
+			if !ok {
+				err := s.writer.Close()
+				if err != nil {
+					s.log.Error().Err(err).Msg("could not close capture file")
+				}
+				return
+			}
+			if s.failed.Load() {
+				continue
+			}
+			err := s.writer.Write(rec)
+			if err != nil {
+				s.fail(err)
+			}
+		case <-flushTicker.C:
+			if s.failed.Load() {
+				continue
+			}
+			err := s.writer.Flush()
+			if err != nil {
+				s.fail(err)
+			}
+		}
+	}
+}
+
+// fail stops writing records after a write error. The capture is a diagnostic tool, so
+// failing to write captures must not interrupt the network.
+func (s *session) fail(err error) {
+	s.failed.Store(true)
+	s.log.Error().Err(err).Msg("could not write network capture, discarding captured messages")
+}
@@ -0,0 +1,172 @@
+package capture
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/codec"
+	"github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestCapturer verifies that inbound and outbound messages are captured while enabled, subject
+// to the channel and peer filters, and that captured payloads decode to the original message.
+func TestCapturer(t *testing.T) {
+	dir := t.TempDir()
+	nodeID := unittest.IdentifierFixture()
+	remoteID := unittest.IdentifierFixture()
+	remotePeer := unittest.PeerIdFixture(t)
+	otherPeer := unittest.PeerIdFixture(t)
+	sporkID := unittest.IdentifierFixture()
+	networkCodec := cbor.NewCodec()
+
+	request := &messages.EntityRequest{Nonce: 42, EntityIDs: unittest.IdentifierListFixture(2)}
+	payload, err := networkCodec.Encode(request)
+	require.NoError(t, err)
+	inbound := func(channel channels.Channel) *message.Message {
+		return &message.Message{ChannelID: channel.String(), Payload: payload}
+	}
+	outbound := func(channel channels.Channel, targetID flow.Identifier) *message.OutgoingMessageScope {
+		scope, err := message.NewOutgoingScope(
+			flow.IdentifierList{targetID},
+			channels.TopicFromChannel(channel, sporkID),
+			request,
+			networkCodec.Encode,
+			message.ProtocolTypeUnicast)
+		require.NoError(t, err)
+		return scope
+	}
+
+	capturer := NewCapturer(zerolog.Nop(), nodeID)
+	// nothing is captured while disabled
+	capturer.CaptureInbound(inbound(channels.RequestCollections), message.ProtocolTypePubSub, remotePeer, remoteID)
+	assert.False(t, capturer.Status().Enabled)
+
+	config := DefaultConfig(dir)
+	config.IncludePayload = true
+	config.Channels = []channels.Channel{channels.RequestCollections}
+	config.Peers = []peer.ID{remotePeer}
+	config.NodeIDs = flow.IdentifierList{remoteID}
+	require.NoError(t, capturer.Enable(config))
+
+	capturer.CaptureInbound(inbound(channels.RequestCollections), message.ProtocolTypeUnicast, remotePeer, remoteID)
+	// filtered by channel
+	capturer.CaptureInbound(inbound(channels.ConsensusCommittee), message.ProtocolTypeUnicast, remotePeer, remoteID)
+	// filtered by peer
+	capturer.CaptureInbound(inbound(channels.RequestCollections), message.ProtocolTypeUnicast, otherPeer, unittest.IdentifierFixture())
+	// matches by target node ID of a pubsub message
+	capturer.CaptureOutbound(message.ProtocolTypePubSub, channels.RequestCollections, outbound(channels.RequestCollections, remoteID), "")
+	// filtered by target node ID
+	capturer.CaptureOutbound(message.ProtocolTypePubSub, channels.RequestCollections, outbound(channels.RequestCollections, unittest.IdentifierFixture()), "")
+
+	status := capturer.Status()
+	assert.True(t, status.Enabled)
+	assert.Equal(t, uint64(2), status.Captured)
+	assert.Equal(t, uint64(0), status.Dropped)
+	capturer.Disable()
+	assert.False(t, capturer.Status().Enabled)
+
+	records, err := ReadCapture(dir)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	in := records[0]
+	assert.Equal(t, Inbound, in.Direction)
+	assert.Equal(t, message.ProtocolTypeUnicast, in.Protocol)
+	assert.Equal(t, channels.RequestCollections, in.Channel)
+	assert.Equal(t, remotePeer.String(), in.PeerID)
+	assert.Equal(t, remoteID, in.OriginID)
+	assert.Equal(t, codec.CodeEntityRequest, in.Code)
+	assert.Equal(t, inbound(channels.RequestCollections).Size(), in.Size)
+	decoded, err := networkCodec.Decode(in.Payload)
+	require.NoError(t, err)
+	assert.Equal(t, request, decoded)
+
+	out := records[1]
+	assert.Equal(t, Outbound, out.Direction)
+	assert.Equal(t, message.ProtocolTypePubSub, out.Protocol)
+	assert.Empty(t, out.PeerID)
+	assert.Equal(t, nodeID, out.OriginID)
+	assert.Equal(t, flow.IdentifierList{remoteID}, out.TargetIDs)
+	assert.Equal(t, codec.CodeEntityRequest, out.Code)
+	assert.False(t, out.Time().Before(in.Time()))
+
+	t.Run("without payload", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, capturer.Enable(DefaultConfig(dir)))
+		capturer.CaptureInbound(inbound(channels.ConsensusCommittee), message.ProtocolTypePubSub, otherPeer, remoteID)
+		capturer.Disable()
+
+		records, err := ReadCapture(dir)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, codec.CodeEntityRequest, records[0].Code)
+		assert.Nil(t, records[0].Payload)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		assert.Error(t, capturer.Enable(Config{}))
+		assert.False(t, capturer.Status().Enabled)
+	})
+}
+
+// TestRotatingWriter verifies that records are split across files, old files are removed and
+// truncated records left behind by a crash are ignored.
+func TestRotatingWriter(t *testing.T) {
+	dir := t.TempDir()
+
+	writer, err := newRotatingWriter(dir, 1024, 3)
+	require.NoError(t, err)
+	var written []*Record
+	for i := 0; i < 100; i++ {
+		record := &Record{
+			Timestamp: int64(1_700_000_000_000 + i),
+			Direction: Inbound,
+			Protocol:  message.ProtocolTypePubSub,
+			Channel:   channels.ConsensusCommittee,
+			PeerID:    unittest.PeerIdFixture(t).String(),
+			OriginID:  unittest.IdentifierFixture(),
+			Code:      codec.CodeBlockProposal,
+			Size:      i,
+			Payload:   unittest.RandomBytes(16),
+		}
+		require.NoError(t, writer.Write(record))
+		written = append(written, record)
+	}
+	require.NoError(t, writer.Close())
+
+	seqs, err := listFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, seqs, 3)
+
+	records, err := ReadCapture(dir)
+	require.NoError(t, err)
+	require.NotEmpty(t, records)
+	require.Less(t, len(records), len(written))
+	// the retained records are the most recent ones
+	assert.Equal(t, written[len(written)-len(records):], records)
+
+	// a single file can be read on its own
+	newest := filepath.Join(dir, fileName(seqs[len(seqs)-1]))
+	fileRecords, err := ReadCapture(newest)
+	require.NoError(t, err)
+	assert.Equal(t, written[len(written)-len(fileRecords):], fileRecords)
+
+	// truncate the last record of the newest file
+	info, err := os.Stat(newest)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(newest, info.Size()-3))
+	truncated, err := ReadCapture(dir)
+	require.NoError(t, err)
+	assert.Len(t, truncated, len(records)-1)
+}
@@ -0,0 +1,280 @@
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/onflow/flow-go/model/encoding/cbor"
+)
+
+const (
+	// FileExtension is the extension of message capture files.
+	FileExtension = ".ncap"
+
+	fileMagic   = "NCAP"
+	fileVersion = 1
+
+	// maxRecordLen bounds the length of a single encoded record, larger length prefixes
+	// indicate a corrupt file. Records include the message payload if payload capture is
+	// enabled, so the bound is set well above the largest expected network message.
+	maxRecordLen = 64 * 1024 * 1024
+)
+
+// fileHeaderLen is the length of the header at the start of every capture file:
+// magic | version u8
+var fileHeaderLen = len(fileMagic) + 1
+
+// errCorruptRecord is returned when a capture file contains a record with an invalid length.
+var errCorruptRecord = errors.New("corrupt capture record")
+
+// rotatingWriter writes records to a sequence of files in a directory. A new file is started
+// once the current file exceeds maxFileBytes, and the oldest files are removed once there
+// are more than maxFiles. Files are named by their zero-padded sequence number, so that
+// their lexicographic order is the order in which they were written.
+//
+// Not concurrency safe.
+type rotatingWriter struct {
+	dir          string
+	maxFileBytes int64
+	maxFiles     int
+
+	seq     uint64
+	file    *os.File
+	buf     *bufio.Writer
+	written int64
+	scratch []byte
+}
+
+// newRotatingWriter creates the directory if necessary and starts a new capture file, which
+// follows any files already present in the directory.
+// No errors are expected during normal operation.
+func newRotatingWriter(dir string, maxFileBytes int64, maxFiles int) (*rotatingWriter, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("could not create capture directory %s: %w", dir, err)
+	}
+	seqs, err := listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &rotatingWriter{
+		dir:          dir,
+		maxFileBytes: maxFileBytes,
+		maxFiles:     maxFiles,
+	}
+	if len(seqs) > 0 {
+		w.seq = seqs[len(seqs)-1]
+	}
+	err = w.rotate()
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write appends the record to the current file, starting a new file if the current file is full.
+// No errors are expected during normal operation.
+func (w *rotatingWriter) Write(r *Record) error {
+	if w.written >= w.maxFileBytes {
+		err := w.rotate()
+		if err != nil {
+			return err
+		}
+	}
+	body, err := cbor.EncMode.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("could not encode record: %w", err)
+	}
+	w.scratch = binary.AppendUvarint(w.scratch[:0], uint64(len(body)))
+	w.scratch = append(w.scratch, body...)
+	n, err := w.buf.Write(w.scratch)
+	w.written += int64(n)
+	if err != nil {
+		return fmt.Errorf("could not write record: %w", err)
+	}
+	return nil
+}
+
+// Flush writes buffered records to the current file.
+// No errors are expected during normal operation.
+func (w *rotatingWriter) Flush() error {
+	err := w.buf.Flush()
+	if err != nil {
+		return fmt.Errorf("could not flush records: %w", err)
+	}
+	return nil
+}
+
+// Close flushes buffered records and closes the current file.
+// No errors are expected during normal operation.
+func (w *rotatingWriter) Close() error {
+	err := w.Flush()
+	if err != nil {
+		_ = w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// rotate closes the current file, if any, starts the next file and removes the oldest files
+// exceeding the retention limit.
+func (w *rotatingWriter) rotate() error {
+	if w.file != nil {
+		err := w.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	w.seq++
+	file, err := os.OpenFile(filepath.Join(w.dir, fileName(w.seq)), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("could not create capture file: %w", err)
+	}
+	w.file = file
+	w.buf = bufio.NewWriter(file)
+
+	header := make([]byte, 0, fileHeaderLen)
+	header = append(header, fileMagic...)
+	header = append(header, fileVersion)
+	_, err = w.buf.Write(header)
+	if err != nil {
+		return fmt.Errorf("could not write file header: %w", err)
+	}
+	w.written = int64(len(header))
+
+	seqs, err := listFiles(w.dir)
+	if err != nil {
+		return err
+	}
+	for len(seqs) > w.maxFiles {
+		err = os.Remove(filepath.Join(w.dir, fileName(seqs[0])))
+		if err != nil {
+			return fmt.Errorf("could not remove old capture file: %w", err)
+		}
+		seqs = seqs[1:]
+	}
+	return nil
+}
+
+func fileName(seq uint64) string {
+	return fmt.Sprintf("%020d%s", seq, FileExtension)
+}
+
+// listFiles returns the sequence numbers of the capture files in the directory, in ascending order.
+func listFiles(dir string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read capture directory %s: %w", dir, err)
+	}
+	var seqs []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, FileExtension) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, FileExtension), 10, 64)
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	return seqs, nil
+}
+
+// ReadCapture reads all records from the given capture file, or from all capture files in the
+// given directory in the order they were written. A truncated record at the end of a file, which
+// is left behind if the node crashed or the file is still being written, is ignored.
+// No errors are expected for files written by the capturer.
+func ReadCapture(path string) ([]*Record, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not access capture %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		records, err := readFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read capture file %s: %w", path, err)
+		}
+		return records, nil
+	}
+
+	seqs, err := listFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(seqs) == 0 {
+		return nil, fmt.Errorf("no capture files in %s", path)
+	}
+	var records []*Record
+	for _, seq := range seqs {
+		file := filepath.Join(path, fileName(seq))
+		fileRecords, err := readFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("could not read capture file %s: %w", file, err)
+		}
+		records = append(records, fileRecords...)
+	}
+	return records, nil
+}
+
+// readFile reads all complete records from a single capture file.
+func readFile(path string) ([]*Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+
+	header := make([]byte, fileHeaderLen)
+	_, err = io.ReadFull(reader, header)
+	if err != nil {
+		return nil, fmt.Errorf("could not read file header: %w", err)
+	}
+	if !bytes.Equal(header[:len(fileMagic)], []byte(fileMagic)) {
+		return nil, fmt.Errorf("not a message capture")
+	}
+	if version := header[len(fileMagic)]; version != fileVersion {
+		return nil, fmt.Errorf("unsupported capture version %d", version)
+	}
+
+	var records []*Record
+	for {
+		length, err := binary.ReadUvarint(reader)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return records, nil
+			}
+			return nil, fmt.Errorf("could not read record length: %w", err)
+		}
+		if length > maxRecordLen {
+			return nil, fmt.Errorf("record of length %d at index %d: %w", length, len(records), errCorruptRecord)
+		}
+		body := make([]byte, length)
+		_, err = io.ReadFull(reader, body)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return records, nil
+			}
+			return nil, fmt.Errorf("could not read record: %w", err)
+		}
+		var record Record
+		err = cbor.DefaultDecMode.Unmarshal(body, &record)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode record at index %d: %w", len(records), err)
+		}
+		records = append(records, &record)
+	}
+}
@@ -0,0 +1,61 @@
+package capture
+
+import (
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/codec"
+	"github.com/onflow/flow-go/network/message"
+)
+
+// Direction is the direction of a captured message.
+type Direction uint8
+
+const (
+	// Inbound messages were received from a remote peer.
+	Inbound Direction = iota + 1
+	// Outbound messages were sent by the local node.
+	Outbound
+)
+
+// String returns the string representation of the direction.
+func (d Direction) String() string {
+	switch d {
+	case Inbound:
+		return "inbound"
+	case Outbound:
+		return "outbound"
+	default:
+		return "unknown"
+	}
+}
+
+// Record is a single captured Flow message.
+type Record struct {
+	// Timestamp is the time the message was sent or received, in nanoseconds since the Unix epoch.
+	Timestamp int64
+	Direction Direction
+	Protocol  message.ProtocolType
+	Channel   channels.Channel
+	// PeerID is the libp2p peer ID of the sender of an inbound message or the recipient of an
+	// outbound unicast message, empty for outbound pubsub messages.
+	PeerID string
+	// OriginID is the Flow ID of the sender of an inbound message, the ID of the local node
+	// for outbound messages.
+	OriginID flow.Identifier
+	// TargetIDs are the Flow IDs of the recipients of an outbound message, empty for inbound messages.
+	TargetIDs flow.IdentifierList
+	// Code is the codec code of the message, which determines its type.
+	Code codec.MessageCode
+	// Size is the size of the network message in bytes.
+	Size int
+	// Payload is the encoded message, including the leading codec code. It is only captured if
+	// payload capture is enabled, and nil otherwise.
+	Payload []byte
+}
+
+// Time returns the time the message was sent or received.
+func (r *Record) Time() time.Time {
+	return time.Unix(0, r.Timestamp).UTC()
+}
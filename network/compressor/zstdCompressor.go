@@ -0,0 +1,156 @@
+package compressor
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/dict"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/onflow/flow-go/network"
+)
+
+const (
+	// zstdMaxWindowSize is the maximum window size accepted by the zstd decoder. It bounds the memory used for
+	// decompressing a stream, regardless of the window size announced by the (potentially byzantine) sender.
+	// It matches the largest window used by the encoder, which is 8 MiB for all encoder levels.
+	zstdMaxWindowSize = 8 << 20
+
+	// ZstdMaxDictionarySize is the maximum size of dictionaries trained with TrainZstdDictionary.
+	ZstdMaxDictionarySize = 64 << 10
+)
+
+var _ network.Compressor = (*ZstdCompressor)(nil)
+
+// ZstdCompressor is a Zstandard compressor. Optionally, it uses shared dictionaries, which considerably improve the
+// compression of small messages with a common structure, e.g., Flow messages of the same type. Dictionaries must be
+// shared between the compressing and decompressing parties: data compressed with a dictionary can only be
+// decompressed by a compressor that has the same dictionary, which is identified by the dictionary ID stored in
+// the compressed data.
+//
+// No dictionaries are shipped with the node software at the moment, hence the zstd unicast protocol and the
+// execution data serializer compress without dictionaries. Using dictionaries for either requires distributing the
+// same dictionaries to all nodes first, e.g., as part of a protocol upgrade.
+type ZstdCompressor struct {
+	encoderOpts  []zstd.EOption
+	decoderOpts  []zstd.DOption
+	dictionaryID uint32
+}
+
+type zstdConfig struct {
+	level        zstd.EncoderLevel
+	dictionaries [][]byte
+}
+
+// ZstdOption is an option for the ZstdCompressor.
+type ZstdOption func(*zstdConfig)
+
+// WithZstdLevel sets the encoder level of the compressor, the default is zstd.SpeedDefault.
+func WithZstdLevel(level zstd.EncoderLevel) ZstdOption {
+	return func(c *zstdConfig) {
+		c.level = level
+	}
+}
+
+// WithZstdDictionaries sets the dictionaries of the compressor. Data is compressed with the first dictionary, and
+// data compressed with any of the dictionaries can be decompressed. Dictionaries must be in the zstd dictionary
+// format, as created by TrainZstdDictionary.
+func WithZstdDictionaries(dictionaries ...[]byte) ZstdOption {
+	return func(c *zstdConfig) {
+		c.dictionaries = dictionaries
+	}
+}
+
+// NewZstdCompressor creates a new Zstandard compressor.
+// No errors are expected during normal operation, unless invalid dictionaries are provided.
+func NewZstdCompressor(opts ...ZstdOption) (*ZstdCompressor, error) {
+	config := &zstdConfig{
+		level: zstd.SpeedDefault,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	z := &ZstdCompressor{
+		// a single goroutine per stream, streams are compressed concurrently by their own goroutines
+		encoderOpts: []zstd.EOption{
+			zstd.WithEncoderConcurrency(1),
+			zstd.WithEncoderLevel(config.level),
+		},
+		decoderOpts: []zstd.DOption{
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderMaxWindow(zstdMaxWindowSize),
+		},
+	}
+	if len(config.dictionaries) > 0 {
+		for i, d := range config.dictionaries {
+			info, err := zstd.InspectDictionary(d)
+			if err != nil {
+				return nil, fmt.Errorf("invalid zstd dictionary at index %d: %w", i, err)
+			}
+			if i == 0 {
+				z.dictionaryID = info.ID()
+			}
+		}
+		z.encoderOpts = append(z.encoderOpts, zstd.WithEncoderDict(config.dictionaries[0]))
+		z.decoderOpts = append(z.decoderOpts, zstd.WithDecoderDicts(config.dictionaries...))
+	}
+	return z, nil
+}
+
+// DictionaryID returns the ID of the dictionary used for compression, or 0 if no dictionary is used.
+func (z *ZstdCompressor) DictionaryID() uint32 {
+	return z.dictionaryID
+}
+
+func (z *ZstdCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	d, err := zstd.NewReader(r, z.decoderOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create zstd reader: %w", err)
+	}
+	return d.IOReadCloser(), nil
+}
+
+func (z *ZstdCompressor) NewWriter(w io.Writer) (network.WriteCloseFlusher, error) {
+	e, err := zstd.NewWriter(w, z.encoderOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create zstd writer: %w", err)
+	}
+	return &zstdWriteCloseFlusher{w: e}, nil
+}
+
+type zstdWriteCloseFlusher struct {
+	w *zstd.Encoder
+}
+
+func (zstdW *zstdWriteCloseFlusher) Write(p []byte) (int, error) {
+	return zstdW.w.Write(p)
+}
+
+func (zstdW *zstdWriteCloseFlusher) Close() error {
+	return zstdW.w.Close()
+}
+
+func (zstdW *zstdWriteCloseFlusher) Flush() error {
+	return zstdW.w.Flush()
+}
+
+// TrainZstdDictionary trains a zstd dictionary with the given ID on the given samples, e.g., encoded Flow messages
+// of the types the dictionary is meant for. The samples should be representative of the compressed data, and
+// there should be many of them; the returned dictionary is at most ZstdMaxDictionarySize bytes.
+// The dictionary ID must be unique among the dictionaries shared by a set of nodes, and must not be 0.
+// No errors are expected during normal operation, unless the samples are insufficient to train a dictionary.
+func TrainZstdDictionary(id uint32, samples [][]byte) ([]byte, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("zstd dictionary ID must not be 0")
+	}
+	d, err := dict.BuildZstdDict(samples, dict.Options{
+		MaxDictSize: ZstdMaxDictionarySize,
+		HashBytes:   6,
+		ZstdDictID:  id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not train zstd dictionary: %w", err)
+	}
+	return d, nil
+}
@@ -0,0 +1,317 @@
+package compressor_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/encoding/cbor"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/compressor"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestZstdRoundTrip evaluates that reading what has been written by the zstd compressor yields the same data,
+// and that the data is compressed when written.
+func TestZstdRoundTrip(t *testing.T) {
+	zstdComp, err := compressor.NewZstdCompressor()
+	require.NoError(t, err)
+	require.Zero(t, zstdComp.DictionaryID())
+
+	data := bytes.Repeat([]byte("hello world, hello world!"), 100)
+	compressed := compress(t, zstdComp, data)
+	require.Less(t, len(compressed), len(data))
+	require.Equal(t, data, decompress(t, zstdComp, compressed))
+}
+
+// TestZstdMaxWindowSize evaluates that streams announcing a larger window than the one used by the encoder are
+// rejected, as decompressing them could use an excessive amount of memory.
+func TestZstdMaxWindowSize(t *testing.T) {
+	zstdComp, err := compressor.NewZstdCompressor()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w, err := zstd.NewWriter(&buf, zstd.WithWindowSize(16<<20))
+	require.NoError(t, err)
+	// the encoder announces its window size only if the data spans multiple blocks
+	_, err = w.Write(bytes.Repeat([]byte("hello world, hello world!"), 1<<16))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	r, err := zstdComp.NewReader(&buf)
+	require.NoError(t, err)
+	defer r.Close()
+	_, err = io.ReadAll(r)
+	require.Error(t, err)
+}
+
+// TestZstdDictionary evaluates that data compressed with a dictionary trained on Flow messages can only be
+// decompressed by a compressor which has the dictionary, and that the dictionary improves the compression
+// of small messages.
+func TestZstdDictionary(t *testing.T) {
+	samples := make([][]byte, 0, 500)
+	for i := 0; i < 500; i++ {
+		samples = append(samples, tokenTransferEventPayload(rand.Uint64()))
+	}
+
+	dictionary, err := compressor.TrainZstdDictionary(42, samples)
+	require.NoError(t, err)
+	require.LessOrEqual(t, len(dictionary), compressor.ZstdMaxDictionarySize)
+
+	withDict, err := compressor.NewZstdCompressor(compressor.WithZstdDictionaries(dictionary))
+	require.NoError(t, err)
+	require.Equal(t, uint32(42), withDict.DictionaryID())
+	withoutDict, err := compressor.NewZstdCompressor()
+	require.NoError(t, err)
+
+	message := tokenTransferEventPayload(rand.Uint64())
+
+	t.Run("round trip", func(t *testing.T) {
+		compressed := compress(t, withDict, message)
+		require.Equal(t, message, decompress(t, withDict, compressed))
+	})
+
+	t.Run("dictionary improves compression of small messages", func(t *testing.T) {
+		require.Less(t, len(compress(t, withDict, message)), len(compress(t, withoutDict, message)))
+	})
+
+	t.Run("decompression without dictionary fails", func(t *testing.T) {
+		r, err := withoutDict.NewReader(bytes.NewReader(compress(t, withDict, message)))
+		require.NoError(t, err)
+		defer r.Close()
+		_, err = io.ReadAll(r)
+		require.Error(t, err)
+	})
+
+	t.Run("data compressed without dictionary is decompressed by compressor with dictionary", func(t *testing.T) {
+		compressed := compress(t, withoutDict, message)
+		require.Equal(t, message, decompress(t, withDict, compressed))
+	})
+
+	t.Run("invalid dictionary", func(t *testing.T) {
+		_, err := compressor.NewZstdCompressor(compressor.WithZstdDictionaries([]byte("not a dictionary")))
+		require.Error(t, err)
+	})
+
+	t.Run("dictionary ID must not be zero", func(t *testing.T) {
+		_, err := compressor.TrainZstdDictionary(0, samples)
+		require.Error(t, err)
+	})
+}
+
+// BenchmarkCompressors compares the zstd compressor, with and without dictionary, with the lz4 compressor on
+// cbor encoded chunk data packs and chunk execution data shaped like the ones on mainnet, i.e., consisting
+// of token transfer transactions, their events and register updates.
+// Besides the throughput, it reports the compression ratio as the size of the compressed data relative to
+// the size of the uncompressed data.
+func BenchmarkCompressors(b *testing.B) {
+	marshaler := cbor.NewMarshaler()
+	rng := rand.New(rand.NewSource(1))
+
+	encode := func(v interface{}) []byte {
+		data, err := marshaler.Marshal(v)
+		require.NoError(b, err)
+		return data
+	}
+	inputs := []struct {
+		name string
+		data []byte
+	}{
+		{name: "chunk data pack", data: encode(realisticChunkDataPack(rng, 100))},
+		{name: "chunk execution data", data: encode(realisticChunkExecutionData(rng, 100))},
+		{name: "event payload", data: tokenTransferEventPayload(rng.Uint64())},
+	}
+
+	samples := make([][]byte, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		samples = append(samples, tokenTransferEventPayload(rng.Uint64()))
+	}
+	for i := 0; i < 10; i++ {
+		samples = append(samples, encode(realisticChunkDataPack(rng, 10)))
+		samples = append(samples, encode(realisticChunkExecutionData(rng, 10)))
+	}
+	dictionary, err := compressor.TrainZstdDictionary(1, samples)
+	require.NoError(b, err)
+
+	zstdDefault, err := compressor.NewZstdCompressor()
+	require.NoError(b, err)
+	zstdFastest, err := compressor.NewZstdCompressor(compressor.WithZstdLevel(zstd.SpeedFastest))
+	require.NoError(b, err)
+	zstdDict, err := compressor.NewZstdCompressor(compressor.WithZstdDictionaries(dictionary))
+	require.NoError(b, err)
+	compressors := []struct {
+		name string
+		comp network.Compressor
+	}{
+		{name: "lz4", comp: compressor.NewLz4Compressor()},
+		{name: "zstd", comp: zstdDefault},
+		{name: "zstd fastest", comp: zstdFastest},
+		{name: "zstd dictionary", comp: zstdDict},
+	}
+
+	for _, input := range inputs {
+		for _, c := range compressors {
+			b.Run(fmt.Sprintf("%s/%s/compress", input.name, c.name), func(b *testing.B) {
+				var compressed []byte
+				b.SetBytes(int64(len(input.data)))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					compressed = compress(b, c.comp, input.data)
+				}
+				b.ReportMetric(float64(len(compressed))/float64(len(input.data)), "ratio")
+			})
+			b.Run(fmt.Sprintf("%s/%s/decompress", input.name, c.name), func(b *testing.B) {
+				compressed := compress(b, c.comp, input.data)
+				// pierrec/lz4 v2 fails to decompress some of the data it compresses (e.g., chunk data packs
+				// with transactions sharing the same script), hence the round trip is checked before benchmarking.
+				if _, err := roundTrip(c.comp, compressed); err != nil {
+					b.Skipf("%s cannot decompress %s: %v", c.name, input.name, err)
+				}
+				b.SetBytes(int64(len(input.data)))
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					decompress(b, c.comp, compressed)
+				}
+			})
+		}
+	}
+}
+
+// compress compresses the given data with the given compressor.
+func compress(t testing.TB, comp network.Compressor, data []byte) []byte {
+	buf := new(bytes.Buffer)
+	w, err := comp.NewWriter(buf)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// decompress decompresses the given data with the given compressor.
+func decompress(t testing.TB, comp network.Compressor, data []byte) []byte {
+	decompressed, err := roundTrip(comp, data)
+	require.NoError(t, err)
+	return decompressed
+}
+
+// roundTrip decompresses the given data with the given compressor, returning the error of the decompression.
+func roundTrip(comp network.Compressor, data []byte) ([]byte, error) {
+	r, err := comp.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+const tokenTransferScript = `import FungibleToken from 0xf233dcee88fe0abe
+import FlowToken from 0x1654653399040a61
+
+transaction(amount: UFix64, to: Address) {
+    let sentVault: @{FungibleToken.Vault}
+
+    prepare(signer: auth(BorrowValue) &Account) {
+        let vaultRef = signer.storage.borrow<auth(FungibleToken.Withdraw) &FlowToken.Vault>(from: /storage/flowTokenVault)
+            ?? panic("Could not borrow reference to the owner's Vault!")
+        self.sentVault <- vaultRef.withdraw(amount: amount)
+    }
+
+    execute {
+        let receiverRef = getAccount(to).capabilities.borrow<&{FungibleToken.Receiver}>(/public/flowTokenReceiver)
+            ?? panic("Could not borrow receiver reference to the recipient's Vault")
+        receiverRef.deposit(from: <-self.sentVault)
+    }
+}`
+
+// tokenTransferEventPayload returns the JSON-CDC payload of a FlowToken deposit event of the given amount.
+func tokenTransferEventPayload(amount uint64) []byte {
+	return []byte(fmt.Sprintf(`{"type":"Event","value":{"id":"A.1654653399040a61.FlowToken.TokensDeposited",`+
+		`"fields":[{"name":"amount","value":{"type":"UFix64","value":"%d.%08d"}},`+
+		`{"name":"to","value":{"type":"Optional","value":{"type":"Address","value":"0x%s"}}}]}}`,
+		amount%1000, amount%100_000_000, unittest.AddressFixture().Hex()))
+}
+
+// realisticTransactions returns a collection of the given number of token transfer transactions.
+func realisticTransactions(rng *rand.Rand, count int) *flow.Collection {
+	txs := make([]*flow.TransactionBody, count)
+	for i := range txs {
+		tx := unittest.TransactionBodyFixture(func(tb *flow.TransactionBody) {
+			tb.Script = []byte(tokenTransferScript)
+			tb.Arguments = [][]byte{
+				[]byte(fmt.Sprintf(`{"type":"UFix64","value":"%d.00000000"}`, rng.Intn(1000))),
+				[]byte(fmt.Sprintf(`{"type":"Address","value":"0x%s"}`, unittest.AddressFixture().Hex())),
+			}
+			tb.GasLimit = 9999
+		})
+		txs[i] = &tx
+	}
+	return &flow.Collection{Transactions: txs}
+}
+
+// realisticChunkDataPack returns a chunk data pack of the given number of token transfer transactions, with a
+// proof of the size of a typical proof for the registers touched by the transactions.
+func realisticChunkDataPack(rng *rand.Rand, transactions int) *flow.ChunkDataPack {
+	return unittest.ChunkDataPackFixture(unittest.IdentifierFixture(), func(cdp *flow.ChunkDataPack) {
+		cdp.Collection = realisticTransactions(rng, transactions)
+		// the proof mostly consists of hashes, which are incompressible
+		cdp.Proof = unittest.RandomBytes(transactions * 1024)
+	})
+}
+
+// realisticChunkExecutionData returns a chunk execution data of the given number of token transfer transactions,
+// with their events, results and register updates.
+func realisticChunkExecutionData(rng *rand.Rand, transactions int) *execution_data.ChunkExecutionData {
+	collection := realisticTransactions(rng, transactions)
+	results := make([]flow.LightTransactionResult, 0, transactions)
+	events := make(flow.EventsList, 0, 4*transactions)
+	trieUpdate := &ledger.TrieUpdate{RootHash: ledger.RootHash(unittest.StateCommitmentFixture())}
+	for i, tx := range collection.Transactions {
+		txID := tx.ID()
+		results = append(results, flow.LightTransactionResult{
+			TransactionID:   txID,
+			ComputationUsed: uint64(rng.Intn(100)),
+		})
+		for _, eventType := range []flow.EventType{
+			"A.1654653399040a61.FlowToken.TokensWithdrawn",
+			"A.1654653399040a61.FlowToken.TokensDeposited",
+			"A.f919ee77447b7497.FlowFees.FeesDeducted",
+			"flow.AccountContractUpdated",
+		} {
+			event := unittest.EventFixture(eventType, uint32(i), uint32(len(events)), txID, 0)
+			event.Payload = tokenTransferEventPayload(rng.Uint64())
+			events = append(events, event)
+		}
+		for _, owner := range tx.Authorizers {
+			for _, register := range []string{"flowTokenVault", "$account_status", "storage_used"} {
+				var path ledger.Path
+				_, _ = rng.Read(path[:])
+				value := make([]byte, 8+rng.Intn(32))
+				_, _ = rng.Read(value)
+				trieUpdate.Paths = append(trieUpdate.Paths, path)
+				trieUpdate.Payloads = append(trieUpdate.Payloads, ledger.NewPayload(
+					ledger.NewKey([]ledger.KeyPart{
+						ledger.NewKeyPart(ledger.KeyPartOwner, owner.Bytes()),
+						ledger.NewKeyPart(ledger.KeyPartKey, []byte(register)),
+					}),
+					value,
+				))
+			}
+		}
+	}
+	return &execution_data.ChunkExecutionData{
+		Collection:         collection,
+		Events:             events,
+		TrieUpdate:         trieUpdate,
+		TransactionResults: results,
+	}
+}
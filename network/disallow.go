@@ -19,6 +19,8 @@ const (
 	DisallowListedCauseAdmin DisallowListedCause = "disallow-listed-admin"
 	// DisallowListedCauseAlsp is the cause of disallow-listing a node by the ALSP (Application Layer Spam Prevention).
 	DisallowListedCauseAlsp DisallowListedCause = "disallow-listed-alsp"
+	// DisallowListedCauseOperator is the cause of disallow-listing a node or peer by the operator-managed disallow list.
+	DisallowListedCauseOperator DisallowListedCause = "disallow-listed-operator"
 )
 
 // DisallowListingUpdate is a notification of a new disallow list update, it contains a list of Flow identities that
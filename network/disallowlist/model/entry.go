@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Entry is an entry of the operator-managed disallow list. An entry disallow-lists either a node, given by its
+// node ID, or a peer, given by its libp2p peer ID; the latter allows disallow-listing peers which are not
+// (or no longer) part of the identity table, e.g., unstaked peers of the public network.
+type Entry struct {
+	// NodeID is the node ID of the disallow-listed node, flow.ZeroID if the entry is for a peer ID.
+	NodeID flow.Identifier
+
+	// PeerID is the peer ID of the disallow-listed peer, empty if the entry is for a node ID.
+	PeerID peer.ID
+
+	// Reason is the operator-provided reason for disallow-listing the node.
+	Reason string
+
+	// Added is the time the entry was added to the disallow list.
+	Added time.Time
+
+	// Expiry is the time the entry expires, i.e., the node is allow-listed again. Zero if the entry never expires.
+	Expiry time.Time
+}
+
+// Key returns the key identifying the entry in the disallow list, i.e., the hex-encoded node ID for node ID
+// entries, and the peer ID for peer ID entries.
+func (e *Entry) Key() string {
+	if e.NodeID != flow.ZeroID {
+		return e.NodeID.String()
+	}
+	return e.PeerID.String()
+}
+
+// Expired returns true if the entry has an expiry that is not after the given time.
+func (e *Entry) Expired(now time.Time) bool {
+	return !e.Expiry.IsZero() && !e.Expiry.After(now)
+}
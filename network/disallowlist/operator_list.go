@@ -0,0 +1,344 @@
+package disallowlist
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/disallowlist/model"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// updateInterval is the interval at which expired entries are removed from the disallow list, and the entries of
+// nodes which could not be translated to peer IDs are applied again.
+const updateInterval = 10 * time.Second
+
+// ErrUnknownNode is returned when adding an entry for a node ID which cannot be translated to a peer ID, e.g., because
+// the node is not part of the identity table.
+var ErrUnknownNode = errors.New("node ID cannot be translated to a peer ID")
+
+// DisallowListConsumer is the consumer of the operator-managed disallow list, i.e., the libp2p node, which keeps
+// the disallow-listed peers in its disallow list cache. The connection gater consults the cache on every new
+// connection, so disallow-listing a peer takes effect immediately for new connections, and existing connections
+// are pruned on the next peer update.
+type DisallowListConsumer interface {
+	p2p.DisallowListNotificationConsumer
+
+	// RequestPeerUpdate requests an update to the peer connections of the node, which prunes the connections
+	// to disallow-listed peers.
+	RequestPeerUpdate()
+}
+
+// OperatorDisallowList is the disallow list managed by the node operator via admin commands. Unlike the
+// disallow-listing by the ALSP module, entries are added and removed explicitly by the operator, may carry an
+// expiry, and are persisted to the node database, so that they survive restarts of the node.
+// Entries are applied to the disallow list cache of the libp2p node with the network.DisallowListedCauseOperator
+// cause, so that removing an entry does not allow-list a peer which is disallow-listed for other causes.
+// Persisted entries of nodes which cannot be translated to peer IDs on startup (e.g., because they are not part of
+// the current identity table) are applied once the identity table includes them.
+type OperatorDisallowList struct {
+	component.Component
+	logger       zerolog.Logger
+	storage      storage.DisallowListEntries
+	idTranslator p2p.IDTranslator
+	consumer     DisallowListConsumer
+
+	// lock protects the entries, and serializes their persistence.
+	lock    sync.Mutex
+	entries map[string]model.Entry
+	// unapplied are the keys of the entries whose node ID could not be translated to a peer ID yet.
+	unapplied map[string]struct{}
+}
+
+// NewOperatorDisallowList creates a new operator-managed disallow list, loading the entries persisted in the
+// given storage. The loaded entries are applied when the component is started; expired entries are dropped.
+// No errors are expected during normal operation.
+func NewOperatorDisallowList(
+	logger zerolog.Logger,
+	entries storage.DisallowListEntries,
+	idTranslator p2p.IDTranslator,
+	consumer DisallowListConsumer,
+) (*OperatorDisallowList, error) {
+	stored, err := entries.All()
+	if err != nil {
+		return nil, fmt.Errorf("could not load operator disallow list: %w", err)
+	}
+
+	l := &OperatorDisallowList{
+		logger:       logger.With().Str("component", "operator_disallow_list").Logger(),
+		storage:      entries,
+		idTranslator: idTranslator,
+		consumer:     consumer,
+		entries:      make(map[string]model.Entry, len(stored)),
+		unapplied:    make(map[string]struct{}),
+	}
+	for _, entry := range stored {
+		l.entries[entry.Key()] = entry
+	}
+
+	l.Component = component.NewComponentManagerBuilder().
+		AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+			err := l.start()
+			if err != nil {
+				ctx.Throw(err)
+			}
+			ready()
+			l.updateLoop(ctx) // blocking call
+		}).
+		Build()
+
+	return l, nil
+}
+
+// Add adds the given entries to the disallow list, replacing the existing entries for the same node or peer
+// (e.g., to change the reason or the expiry), and disallow-lists the nodes and peers right away.
+// Expected errors during normal operations:
+//   - ErrUnknownNode if the node ID of an entry cannot be translated to a peer ID, in which case no entry is added
+func (l *OperatorDisallowList) Add(entries ...model.Entry) error {
+	pids := make([]peer.ID, 0, len(entries))
+	for _, entry := range entries {
+		pid, err := l.peerID(entry)
+		if err != nil {
+			return fmt.Errorf("could not add entry %s: %w", entry.Key(), err)
+		}
+		pids = append(pids, pid)
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	updated := l.copyEntries()
+	for _, entry := range entries {
+		updated[entry.Key()] = entry
+	}
+	err := l.persist(updated)
+	if err != nil {
+		return err
+	}
+	l.entries = updated
+
+	for i, entry := range entries {
+		l.logger.Warn().
+			Str("key", logging.KeySuspicious).
+			Str("entry", entry.Key()).
+			Str("reason", entry.Reason).
+			Time("expiry", entry.Expiry).
+			Msg("node disallow-listed by operator")
+		delete(l.unapplied, entry.Key())
+		l.consumer.OnDisallowListNotification(pids[i], network.DisallowListedCauseOperator)
+	}
+	l.consumer.RequestPeerUpdate()
+	return nil
+}
+
+// Remove removes the entries with the given keys (see model.Entry.Key) from the disallow list, and allow-lists
+// the nodes and peers, unless they are disallow-listed for other causes.
+// Returns the removed entries; keys without an entry are ignored.
+// No errors are expected during normal operation.
+func (l *OperatorDisallowList) Remove(keys ...string) ([]model.Entry, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.remove(keys)
+}
+
+// Entries returns the entries of the disallow list, ordered by key.
+func (l *OperatorDisallowList) Entries() []model.Entry {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	entries := make([]model.Entry, 0, len(l.entries))
+	for _, entry := range l.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key() < entries[j].Key()
+	})
+	return entries
+}
+
+// start applies the loaded entries, and drops the entries which expired while the node was down.
+// No errors are expected during normal operation.
+func (l *OperatorDisallowList) start() error {
+	err := l.removeExpired(time.Now())
+	if err != nil {
+		return err
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for key := range l.entries {
+		l.unapplied[key] = struct{}{}
+	}
+	l.applyUnapplied()
+	for key := range l.unapplied {
+		l.logger.Warn().
+			Hex("node_id", logging.ID(l.entries[key].NodeID)).
+			Msg("could not translate node ID of operator disallow list entry to peer ID, entry is applied once the node is known")
+	}
+	l.logger.Info().
+		Int("entries", len(l.entries)).
+		Int("unapplied", len(l.unapplied)).
+		Msg("operator disallow list applied")
+	return nil
+}
+
+// updateLoop periodically removes the expired entries from the disallow list, and applies the entries which could
+// not be applied before.
+// It is a blocking function, and should be called in a separate goroutine. It returns when the context is canceled.
+func (l *OperatorDisallowList) updateLoop(ctx irrecoverable.SignalerContext) {
+	ticker := time.NewTicker(updateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			err := l.removeExpired(now)
+			if err != nil {
+				// any error returned from persisting the disallow list is considered irrecoverable.
+				ctx.Throw(err)
+			}
+			l.lock.Lock()
+			applied := l.applyUnapplied()
+			l.lock.Unlock()
+			if applied {
+				l.consumer.RequestPeerUpdate()
+			}
+		}
+	}
+}
+
+// applyUnapplied disallow-lists the peers of the unapplied entries whose node IDs can be translated to peer IDs now.
+// Returns true if any entry has been applied.
+// Must be called while holding the lock.
+func (l *OperatorDisallowList) applyUnapplied() bool {
+	applied := false
+	for key := range l.unapplied {
+		entry := l.entries[key]
+		pid, err := l.peerID(entry)
+		if err != nil {
+			continue
+		}
+		delete(l.unapplied, key)
+		l.consumer.OnDisallowListNotification(pid, network.DisallowListedCauseOperator)
+		applied = true
+		if entry.NodeID != flow.ZeroID {
+			l.logger.Info().Hex("node_id", logging.ID(entry.NodeID)).Msg("operator disallow list entry applied")
+		}
+	}
+	return applied
+}
+
+// removeExpired removes the entries which expired at the given time.
+// No errors are expected during normal operation.
+func (l *OperatorDisallowList) removeExpired(now time.Time) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var expired []string
+	for key, entry := range l.entries {
+		if entry.Expired(now) {
+			expired = append(expired, key)
+		}
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+	_, err := l.remove(expired)
+	return err
+}
+
+// remove removes the entries with the given keys and allow-lists their nodes and peers.
+// Must be called while holding the lock.
+// No errors are expected during normal operation.
+func (l *OperatorDisallowList) remove(keys []string) ([]model.Entry, error) {
+	updated := l.copyEntries()
+	removed := make([]model.Entry, 0, len(keys))
+	for _, key := range keys {
+		entry, ok := updated[key]
+		if !ok {
+			continue
+		}
+		delete(updated, key)
+		removed = append(removed, entry)
+	}
+	if len(removed) == 0 {
+		return removed, nil
+	}
+	err := l.persist(updated)
+	if err != nil {
+		return nil, err
+	}
+	l.entries = updated
+
+	for _, entry := range removed {
+		l.logger.Info().
+			Str("entry", entry.Key()).
+			Bool("expired", entry.Expired(time.Now())).
+			Msg("node removed from operator disallow list")
+		if _, ok := l.unapplied[entry.Key()]; ok {
+			// the entry has never been applied, hence there is nothing to allow-list
+			delete(l.unapplied, entry.Key())
+			continue
+		}
+		pid, err := l.peerID(entry)
+		if err != nil {
+			l.logger.Warn().
+				Err(err).
+				Hex("node_id", logging.ID(entry.NodeID)).
+				Msg("could not translate node ID of removed operator disallow list entry to peer ID, peer is not allow-listed")
+			continue
+		}
+		l.consumer.OnAllowListNotification(pid, network.DisallowListedCauseOperator)
+	}
+	return removed, nil
+}
+
+// peerID returns the peer ID of the given entry, translating the node ID of node ID entries.
+// Expected errors during normal operations:
+//   - ErrUnknownNode if the node ID cannot be translated, e.g., because the node is not part of the identity table
+func (l *OperatorDisallowList) peerID(entry model.Entry) (peer.ID, error) {
+	if entry.NodeID == flow.ZeroID {
+		return entry.PeerID, nil
+	}
+	pid, err := l.idTranslator.GetPeerID(entry.NodeID)
+	if err != nil {
+		return "", fmt.Errorf("%w: node %v: %s", ErrUnknownNode, entry.NodeID, err.Error())
+	}
+	return pid, nil
+}
+
+// persist stores the given entries.
+// No errors are expected during normal operation.
+func (l *OperatorDisallowList) persist(entries map[string]model.Entry) error {
+	list := make([]model.Entry, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, entry)
+	}
+	err := l.storage.Store(list)
+	if err != nil {
+		return fmt.Errorf("could not persist operator disallow list: %w", err)
+	}
+	return nil
+}
+
+// copyEntries returns a copy of the entries. Must be called while holding the lock.
+func (l *OperatorDisallowList) copyEntries() map[string]model.Entry {
+	entries := make(map[string]model.Entry, len(l.entries))
+	for key, entry := range l.entries {
+		entries[key] = entry
+	}
+	return entries
+}
@@ -0,0 +1,132 @@
+package disallowlist_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/disallowlist"
+	"github.com/onflow/flow-go/network/disallowlist/model"
+	"github.com/onflow/flow-go/network/p2p"
+	mockp2p "github.com/onflow/flow-go/network/p2p/mock"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation/dbtest"
+	"github.com/onflow/flow-go/storage/store"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// consumerFixture is a DisallowListConsumer recording the requested peer updates.
+type consumerFixture struct {
+	*mockp2p.DisallowListNotificationConsumer
+	peerUpdates atomic.Int32
+}
+
+func (c *consumerFixture) RequestPeerUpdate() {
+	c.peerUpdates.Add(1)
+}
+
+// TestOperatorDisallowList tests that the persisted entries are applied on startup with the expired entries
+// dropped, and that added and removed entries are persisted and applied right away with the operator cause.
+func TestOperatorDisallowList(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		entries := store.NewDisallowListEntries(db)
+		idTranslator := mockp2p.NewIDTranslator(t)
+		consumer := &consumerFixture{DisallowListNotificationConsumer: mockp2p.NewDisallowListNotificationConsumer(t)}
+
+		nodeID := unittest.IdentifierFixture()
+		nodePeerID := unittest.PeerIdFixture(t)
+		idTranslator.On("GetPeerID", nodeID).Return(nodePeerID, nil)
+		nodeEntry := model.Entry{NodeID: nodeID, Reason: "spamming", Added: time.Unix(1_700_000_000, 0)}
+		expiredEntry := model.Entry{PeerID: unittest.PeerIdFixture(t), Reason: "spamming", Added: time.Unix(1_700_000_000, 0), Expiry: time.Now().Add(-time.Minute)}
+		require.NoError(t, entries.Store([]model.Entry{nodeEntry, expiredEntry}))
+
+		list, err := disallowlist.NewOperatorDisallowList(unittest.Logger(), entries, idTranslator, consumer)
+		require.NoError(t, err)
+
+		consumer.On("OnAllowListNotification", expiredEntry.PeerID, network.DisallowListedCauseOperator).Return().Once()
+		consumer.On("OnDisallowListNotification", nodePeerID, network.DisallowListedCauseOperator).Return().Once()
+		ctx, cancel := context.WithCancel(context.Background())
+		signalerCtx := irrecoverable.NewMockSignalerContext(t, ctx)
+		list.Start(signalerCtx)
+		unittest.RequireCloseBefore(t, list.Ready(), 100*time.Millisecond, "operator disallow list did not start")
+		defer func() {
+			cancel()
+			unittest.RequireCloseBefore(t, list.Done(), 100*time.Millisecond, "operator disallow list did not stop")
+		}()
+
+		requireEntries := func(expected ...model.Entry) {
+			keys := func(entries []model.Entry) []string {
+				result := make([]string, 0, len(entries))
+				for _, entry := range entries {
+					result = append(result, entry.Key())
+				}
+				return result
+			}
+			require.ElementsMatch(t, keys(expected), keys(list.Entries()))
+			persisted, err := entries.All()
+			require.NoError(t, err)
+			require.ElementsMatch(t, keys(expected), keys(persisted))
+		}
+		requireEntries(nodeEntry)
+
+		// added entries are applied right away
+		peerEntry := model.Entry{PeerID: unittest.PeerIdFixture(t), Reason: "eclipse attempt", Added: time.Now(), Expiry: time.Now().Add(time.Hour)}
+		unknownNodeEntry := model.Entry{NodeID: unittest.IdentifierFixture(), Reason: "spamming", Added: time.Now()}
+		idTranslator.On("GetPeerID", unknownNodeEntry.NodeID).Return(peer.ID(""), p2p.ErrUnknownId)
+		consumer.On("OnDisallowListNotification", peerEntry.PeerID, network.DisallowListedCauseOperator).Return().Once()
+		// entries of nodes which cannot be translated to peer IDs are rejected
+		err = list.Add(peerEntry, unknownNodeEntry)
+		require.ErrorIs(t, err, disallowlist.ErrUnknownNode)
+		require.Equal(t, int32(0), consumer.peerUpdates.Load())
+		requireEntries(nodeEntry)
+		require.NoError(t, list.Add(peerEntry))
+		require.Equal(t, int32(1), consumer.peerUpdates.Load())
+		requireEntries(nodeEntry, peerEntry)
+
+		// removed entries are allow-listed right away
+		consumer.On("OnAllowListNotification", nodePeerID, network.DisallowListedCauseOperator).Return().Once()
+		removed, err := list.Remove(nodeEntry.Key(), unittest.IdentifierFixture().String())
+		require.NoError(t, err)
+		require.Len(t, removed, 1)
+		require.Equal(t, nodeEntry.Key(), removed[0].Key())
+		requireEntries(peerEntry)
+	})
+}
+
+// TestOperatorDisallowList_UnknownNode tests that persisted entries of nodes which cannot be translated to peer IDs
+// on startup are not applied, and are not allow-listed when removed.
+func TestOperatorDisallowList_UnknownNode(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		entries := store.NewDisallowListEntries(db)
+		idTranslator := mockp2p.NewIDTranslator(t)
+		consumer := &consumerFixture{DisallowListNotificationConsumer: mockp2p.NewDisallowListNotificationConsumer(t)}
+
+		nodeEntry := model.Entry{NodeID: unittest.IdentifierFixture(), Reason: "spamming", Added: time.Now()}
+		idTranslator.On("GetPeerID", nodeEntry.NodeID).Return(peer.ID(""), p2p.ErrUnknownId)
+		require.NoError(t, entries.Store([]model.Entry{nodeEntry}))
+
+		list, err := disallowlist.NewOperatorDisallowList(unittest.Logger(), entries, idTranslator, consumer)
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		list.Start(irrecoverable.NewMockSignalerContext(t, ctx))
+		unittest.RequireCloseBefore(t, list.Ready(), 100*time.Millisecond, "operator disallow list did not start")
+		defer func() {
+			cancel()
+			unittest.RequireCloseBefore(t, list.Done(), 100*time.Millisecond, "operator disallow list did not stop")
+		}()
+
+		// the entry is kept, so that it is applied once the node is known
+		require.Len(t, list.Entries(), 1)
+		removed, err := list.Remove(nodeEntry.Key())
+		require.NoError(t, err)
+		require.Len(t, removed, 1)
+		consumer.AssertNotCalled(t, "OnDisallowListNotification")
+		consumer.AssertNotCalled(t, "OnAllowListNotification")
+	})
+}
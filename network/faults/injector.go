@@ -0,0 +1,209 @@
+// Package faults implements the injection of network faults, i.e., delays, losses, duplicate deliveries and
+// partitions, into the messages exchanged by the engines of a node, to test the protocols under adverse network
+// conditions in integration tests and on localnet. Faults are injected at the Flow networking layer by wrapping
+// the engine registry of the node, hence no root privileges or traffic control tools (tc/netem) are needed.
+// The fault rules can be changed at runtime, e.g. via admin commands.
+package faults
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+// Fault is the fault injected into the delivery of a single message.
+type Fault struct {
+	// Drop is true if the message is not delivered.
+	Drop bool
+	// Partitioned is true if the message is dropped because of a partition.
+	Partitioned bool
+	// Delay is the delay before the message is delivered.
+	Delay time.Duration
+	// Duplicate is true if the message is delivered twice.
+	Duplicate bool
+}
+
+// Stats counts the faults injected since the injector was created.
+type Stats struct {
+	Dropped    uint64
+	Delayed    uint64
+	Duplicated uint64
+}
+
+// Injector decides on the faults injected into the messages exchanged with remote nodes, based on a set of
+// rules which can be changed at runtime. Without rules, no faults are injected and the overhead is negligible.
+//
+// All methods are concurrency safe.
+type Injector struct {
+	log        zerolog.Logger
+	idProvider module.IdentityProvider
+
+	// active allows skipping the rules lock while there are no rules
+	active *atomic.Bool
+	mu     sync.RWMutex
+	// rules are ordered by the time they were first added
+	rules []Rule
+
+	dropped    *atomic.Uint64
+	delayed    *atomic.Uint64
+	duplicated *atomic.Uint64
+}
+
+// NewInjector creates an injector without rules. The identity provider is used to look up the roles of remote nodes.
+func NewInjector(log zerolog.Logger, idProvider module.IdentityProvider) *Injector {
+	return &Injector{
+		log:        log.With().Str("component", "network_faults").Logger(),
+		idProvider: idProvider,
+		active:     atomic.NewBool(false),
+		dropped:    atomic.NewUint64(0),
+		delayed:    atomic.NewUint64(0),
+		duplicated: atomic.NewUint64(0),
+	}
+}
+
+// AddRule adds the given rule, replacing the rule with the same ID if it exists.
+// Returns an error if the rule is invalid.
+func (i *Injector) AddRule(rule Rule) error {
+	if err := rule.Validate(); err != nil {
+		return fmt.Errorf("invalid rule: %w", err)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	replaced := false
+	for j := range i.rules {
+		if i.rules[j].ID == rule.ID {
+			i.rules[j] = rule
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		i.rules = append(i.rules, rule)
+	}
+	i.active.Store(true)
+
+	i.log.Warn().
+		Str("rule_id", rule.ID).
+		Bool("replaced", replaced).
+		Msg("network fault rule added")
+	return nil
+}
+
+// RemoveRules removes the rules with the given IDs, and returns the IDs of the rules which did not exist.
+func (i *Injector) RemoveRules(ids ...string) []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var notFound []string
+	for _, id := range ids {
+		found := false
+		for j := range i.rules {
+			if i.rules[j].ID == id {
+				i.rules = append(i.rules[:j], i.rules[j+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			notFound = append(notFound, id)
+		}
+	}
+	i.active.Store(len(i.rules) > 0)
+
+	i.log.Info().Strs("rule_ids", ids).Msg("network fault rules removed")
+	return notFound
+}
+
+// Clear removes all rules.
+func (i *Injector) Clear() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.rules = nil
+	i.active.Store(false)
+
+	i.log.Info().Msg("all network fault rules removed")
+}
+
+// Rules returns the current rules, ordered by the time they were first added.
+func (i *Injector) Rules() []Rule {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	rules := make([]Rule, len(i.rules))
+	copy(rules, i.rules)
+	return rules
+}
+
+// Stats returns the number of faults injected since the injector was created.
+func (i *Injector) Stats() Stats {
+	return Stats{
+		Dropped:    i.dropped.Load(),
+		Delayed:    i.delayed.Load(),
+		Duplicated: i.duplicated.Load(),
+	}
+}
+
+// Fault decides on the fault injected into a message in the given direction on the given channel, exchanged
+// with the given remote node. The faults of all rules applying to the message accumulate.
+func (i *Injector) Fault(direction Direction, channel channels.Channel, nodeID flow.Identifier) Fault {
+	var fault Fault
+	if !i.active.Load() {
+		return fault
+	}
+
+	// the role of the remote node is only looked up if a rule is restricted to roles
+	var role *flow.Role
+	lookedUp := false
+	roleOf := func() *flow.Role {
+		if !lookedUp {
+			lookedUp = true
+			if identity, ok := i.idProvider.ByNodeID(nodeID); ok {
+				role = &identity.Role
+			}
+		}
+		return role
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	for _, rule := range i.rules {
+		if !rule.matches(direction, channel, nodeID, roleOf) {
+			continue
+		}
+		if rule.Partition {
+			fault.Drop = true
+			fault.Partitioned = true
+		}
+		if rule.DropRate > 0 && rand.Float64() < rule.DropRate {
+			fault.Drop = true
+		}
+		if rule.DuplicateRate > 0 && rand.Float64() < rule.DuplicateRate {
+			fault.Duplicate = true
+		}
+		fault.Delay += rule.Delay.sample()
+	}
+
+	if fault.Drop {
+		i.dropped.Inc()
+		return Fault{Drop: true, Partitioned: fault.Partitioned}
+	}
+	if fault.Delay > 0 {
+		i.delayed.Inc()
+	}
+	if fault.Duplicate {
+		i.duplicated.Inc()
+	}
+	return fault
+}
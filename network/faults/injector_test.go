@@ -0,0 +1,131 @@
+package faults_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/faults"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestInjector_NoRules tests that no faults are injected without rules.
+func TestInjector_NoRules(t *testing.T) {
+	injector := faults.NewInjector(unittest.Logger(), mock.NewIdentityProvider(t))
+
+	fault := injector.Fault(faults.DirectionOutbound, channels.ConsensusCommittee, unittest.IdentifierFixture())
+	require.Equal(t, faults.Fault{}, fault)
+	require.Equal(t, faults.Stats{}, injector.Stats())
+}
+
+// TestInjector_OneWayPartition tests that a partition rule drops the messages in its direction to nodes of its
+// roles, and does not affect messages in the other direction or to nodes of other roles.
+func TestInjector_OneWayPartition(t *testing.T) {
+	consensus := unittest.IdentityFixture(unittest.WithRole(flow.RoleConsensus))
+	execution := unittest.IdentityFixture(unittest.WithRole(flow.RoleExecution))
+	idProvider := mock.NewIdentityProvider(t)
+	idProvider.On("ByNodeID", consensus.NodeID).Return(consensus, true)
+	idProvider.On("ByNodeID", execution.NodeID).Return(execution, true)
+
+	injector := faults.NewInjector(unittest.Logger(), idProvider)
+	require.NoError(t, injector.AddRule(faults.Rule{
+		ID:        "partition",
+		Direction: faults.DirectionOutbound,
+		Roles:     flow.RoleList{flow.RoleConsensus},
+		Partition: true,
+	}))
+
+	fault := injector.Fault(faults.DirectionOutbound, channels.ConsensusCommittee, consensus.NodeID)
+	require.True(t, fault.Drop)
+	require.True(t, fault.Partitioned)
+
+	require.False(t, injector.Fault(faults.DirectionInbound, channels.ConsensusCommittee, consensus.NodeID).Drop)
+	require.False(t, injector.Fault(faults.DirectionOutbound, channels.ConsensusCommittee, execution.NodeID).Drop)
+	require.Equal(t, faults.Stats{Dropped: 1}, injector.Stats())
+}
+
+// TestInjector_AccumulatedFaults tests that the faults of all rules applying to a message accumulate, and that
+// rules restricted to other channels or nodes do not apply.
+func TestInjector_AccumulatedFaults(t *testing.T) {
+	nodeID := unittest.IdentifierFixture()
+	injector := faults.NewInjector(unittest.Logger(), mock.NewIdentityProvider(t))
+	require.NoError(t, injector.AddRule(faults.Rule{
+		ID:        "delay",
+		Direction: faults.DirectionBoth,
+		Delay:     faults.Delay{Base: time.Second},
+	}))
+	require.NoError(t, injector.AddRule(faults.Rule{
+		ID:            "duplicate",
+		Direction:     faults.DirectionInbound,
+		Channels:      channels.ChannelList{channels.SyncCommittee},
+		DuplicateRate: 1,
+		Delay: faults.Delay{
+			Base:         time.Second,
+			Jitter:       time.Second,
+			Distribution: faults.DistributionUniform,
+		},
+	}))
+	require.NoError(t, injector.AddRule(faults.Rule{
+		ID:        "other node",
+		Direction: faults.DirectionBoth,
+		NodeIDs:   flow.IdentifierList{unittest.IdentifierFixture()},
+		Partition: true,
+	}))
+
+	fault := injector.Fault(faults.DirectionInbound, channels.SyncCommittee, nodeID)
+	require.False(t, fault.Drop)
+	require.True(t, fault.Duplicate)
+	require.GreaterOrEqual(t, fault.Delay, 2*time.Second)
+	require.LessOrEqual(t, fault.Delay, 3*time.Second)
+
+	fault = injector.Fault(faults.DirectionInbound, channels.ConsensusCommittee, nodeID)
+	require.Equal(t, faults.Fault{Delay: time.Second}, fault)
+	require.Equal(t, faults.Stats{Delayed: 2, Duplicated: 1}, injector.Stats())
+}
+
+// TestInjector_Rules tests adding, replacing and removing rules.
+func TestInjector_Rules(t *testing.T) {
+	injector := faults.NewInjector(unittest.Logger(), mock.NewIdentityProvider(t))
+
+	first := faults.Rule{ID: "first", Direction: faults.DirectionBoth, DropRate: 0.5}
+	second := faults.Rule{ID: "second", Direction: faults.DirectionInbound, Partition: true}
+	require.NoError(t, injector.AddRule(first))
+	require.NoError(t, injector.AddRule(second))
+
+	// adding a rule with an existing ID replaces the rule in place
+	first.DropRate = 0.1
+	require.NoError(t, injector.AddRule(first))
+	require.Equal(t, []faults.Rule{first, second}, injector.Rules())
+
+	require.Equal(t, []string{"unknown"}, injector.RemoveRules("first", "unknown"))
+	require.Equal(t, []faults.Rule{second}, injector.Rules())
+
+	injector.Clear()
+	require.Empty(t, injector.Rules())
+}
+
+// TestRule_Validate tests that invalid rules are rejected.
+func TestRule_Validate(t *testing.T) {
+	valid := faults.Rule{ID: "valid", Direction: faults.DirectionBoth, DropRate: 0.5}
+	require.NoError(t, valid.Validate())
+
+	for name, modify := range map[string]func(*faults.Rule){
+		"missing ID":           func(r *faults.Rule) { r.ID = "" },
+		"invalid direction":    func(r *faults.Rule) { r.Direction = "sideways" },
+		"drop rate above one":  func(r *faults.Rule) { r.DropRate = 1.5 },
+		"negative duplicate":   func(r *faults.Rule) { r.DuplicateRate = -0.1 },
+		"negative delay":       func(r *faults.Rule) { r.Delay.Base = -time.Second },
+		"invalid distribution": func(r *faults.Rule) { r.Delay = faults.Delay{Jitter: time.Second, Distribution: "normal"} },
+		"no fault":             func(r *faults.Rule) { r.DropRate = 0 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			rule := valid
+			modify(&rule)
+			require.Error(t, rule.Validate())
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package faults
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+// Network is an engine registry which injects faults into the messages exchanged by the engines registered with
+// it. Outbound messages are faulted by the conduits returned by Register, inbound messages before they are handed
+// to the message processors of the engines. Delayed messages are delivered asynchronously, hence messages may be
+// reordered. Blob and ping services are not faulted.
+type Network struct {
+	network.EngineRegistry
+	injector *Injector
+	log      zerolog.Logger
+}
+
+var _ network.EngineRegistry = (*Network)(nil)
+
+// NewNetwork wraps the given engine registry to inject the faults decided by the given injector.
+func NewNetwork(net network.EngineRegistry, injector *Injector) *Network {
+	return &Network{
+		EngineRegistry: net,
+		injector:       injector,
+		log:            injector.log,
+	}
+}
+
+// Register registers the engine on the given channel with the wrapped engine registry, and returns a conduit
+// which injects faults into the outbound messages of the engine.
+// No errors are expected during normal operation.
+func (n *Network) Register(channel channels.Channel, processor network.MessageProcessor) (network.Conduit, error) {
+	con, err := n.EngineRegistry.Register(channel, &messageProcessor{
+		MessageProcessor: processor,
+		injector:         n.injector,
+		log:              n.log.With().Str("channel", channel.String()).Logger(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Conduit{
+		Conduit:  con,
+		channel:  channel,
+		injector: n.injector,
+		log:      n.log.With().Str("channel", channel.String()).Logger(),
+	}, nil
+}
+
+// messageProcessor injects faults into the inbound messages of an engine.
+type messageProcessor struct {
+	network.MessageProcessor
+	injector *Injector
+	log      zerolog.Logger
+}
+
+var _ network.MessageProcessor = (*messageProcessor)(nil)
+
+func (p *messageProcessor) Process(channel channels.Channel, originID flow.Identifier, message interface{}) error {
+	fault := p.injector.Fault(DirectionInbound, channel, originID)
+	if fault.Drop {
+		return nil
+	}
+
+	process := func() error {
+		err := p.MessageProcessor.Process(channel, originID, message)
+		if err == nil && fault.Duplicate {
+			err = p.MessageProcessor.Process(channel, originID, message)
+		}
+		return err
+	}
+	if fault.Delay == 0 {
+		return process()
+	}
+	time.AfterFunc(fault.Delay, func() {
+		if err := process(); err != nil {
+			p.log.Warn().Err(err).Hex("origin_id", originID[:]).Msg("could not process delayed message")
+		}
+	})
+	return nil
+}
+
+// Conduit injects faults into the outbound messages of an engine.
+type Conduit struct {
+	network.Conduit
+	channel  channels.Channel
+	injector *Injector
+	log      zerolog.Logger
+}
+
+var _ network.Conduit = (*Conduit)(nil)
+
+// Publish publishes the event to the targets which are not dropped by the injected faults. Delayed targets are
+// published to individually once their delay has elapsed.
+// Returns the errors of the wrapped conduit for the targets which are not delayed.
+func (c *Conduit) Publish(event interface{}, targetIDs ...flow.Identifier) error {
+	if len(targetIDs) == 0 {
+		return c.Conduit.Publish(event, targetIDs...)
+	}
+
+	immediate := make(flow.IdentifierList, 0, len(targetIDs))
+	var duplicates flow.IdentifierList
+	for _, targetID := range targetIDs {
+		fault := c.injector.Fault(DirectionOutbound, c.channel, targetID)
+		switch {
+		case fault.Drop:
+		case fault.Delay > 0:
+			c.sendDelayed(fault, targetID, func() error {
+				err := c.Conduit.Publish(event, targetID)
+				if err == nil && fault.Duplicate {
+					err = c.Conduit.Publish(event, targetID)
+				}
+				return err
+			})
+		default:
+			immediate = append(immediate, targetID)
+			if fault.Duplicate {
+				duplicates = append(duplicates, targetID)
+			}
+		}
+	}
+
+	if len(immediate) == 0 {
+		return nil
+	}
+	err := c.Conduit.Publish(event, immediate...)
+	if err != nil {
+		return err
+	}
+	if len(duplicates) > 0 {
+		return c.Conduit.Publish(event, duplicates...)
+	}
+	return nil
+}
+
+// Unicast sends the event to the target, unless it is dropped by the injected faults. Partitioned targets are
+// reported as unreachable, while randomly dropped events are lost silently.
+// Returns the errors of the wrapped conduit if the event is not delayed.
+func (c *Conduit) Unicast(event interface{}, targetID flow.Identifier) error {
+	fault := c.injector.Fault(DirectionOutbound, c.channel, targetID)
+	if fault.Partitioned {
+		return network.NewPeerUnreachableError(fmt.Errorf("target %v is partitioned by network fault injection", targetID))
+	}
+	if fault.Drop {
+		return nil
+	}
+
+	send := func() error {
+		err := c.Conduit.Unicast(event, targetID)
+		if err == nil && fault.Duplicate {
+			err = c.Conduit.Unicast(event, targetID)
+		}
+		return err
+	}
+	if fault.Delay == 0 {
+		return send()
+	}
+	c.sendDelayed(fault, targetID, send)
+	return nil
+}
+
+// Multicast sends the event to num targets selected from the targets which are not dropped by the injected
+// faults. The event is sent once the largest delay drawn for the remaining targets has elapsed, and it is sent
+// twice if it is duplicated for any of them.
+// Returns the errors of the wrapped conduit if the event is not delayed.
+func (c *Conduit) Multicast(event interface{}, num uint, targetIDs ...flow.Identifier) error {
+	if len(targetIDs) == 0 {
+		return c.Conduit.Multicast(event, num, targetIDs...)
+	}
+
+	remaining := make(flow.IdentifierList, 0, len(targetIDs))
+	var fault Fault
+	for _, targetID := range targetIDs {
+		f := c.injector.Fault(DirectionOutbound, c.channel, targetID)
+		if f.Drop {
+			continue
+		}
+		remaining = append(remaining, targetID)
+		if f.Delay > fault.Delay {
+			fault.Delay = f.Delay
+		}
+		fault.Duplicate = fault.Duplicate || f.Duplicate
+	}
+	if len(remaining) == 0 {
+		return nil
+	}
+
+	send := func() error {
+		err := c.Conduit.Multicast(event, num, remaining...)
+		if err == nil && fault.Duplicate {
+			err = c.Conduit.Multicast(event, num, remaining...)
+		}
+		return err
+	}
+	if fault.Delay == 0 {
+		return send()
+	}
+	c.sendDelayed(fault, flow.ZeroID, send)
+	return nil
+}
+
+// sendDelayed sends a message once the delay of the fault has elapsed. Errors are logged, as they cannot be
+// returned to the engine anymore.
+func (c *Conduit) sendDelayed(fault Fault, targetID flow.Identifier, send func() error) {
+	time.AfterFunc(fault.Delay, func() {
+		if err := send(); err != nil {
+			c.log.Debug().Err(err).Hex("target_id", targetID[:]).Msg("could not send delayed message")
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package faults_test
+
+import (
+	"testing"
+	"time"
+
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/faults"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestNetwork_Outbound tests that the conduits of the fault injecting network drop, delay and duplicate the outbound
+// messages according to the rules of the injector.
+func TestNetwork_Outbound(t *testing.T) {
+	partitioned := unittest.IdentifierFixture()
+	delayed := unittest.IdentifierFixture()
+	duplicated := unittest.IdentifierFixture()
+	unaffected := unittest.IdentifierFixture()
+
+	injector := faults.NewInjector(unittest.Logger(), mock.NewIdentityProvider(t))
+	require.NoError(t, injector.AddRule(faults.Rule{ID: "partition", Direction: faults.DirectionOutbound, NodeIDs: flow.IdentifierList{partitioned}, Partition: true}))
+	require.NoError(t, injector.AddRule(faults.Rule{ID: "delay", Direction: faults.DirectionOutbound, NodeIDs: flow.IdentifierList{delayed}, Delay: faults.Delay{Base: 10 * time.Millisecond}}))
+	require.NoError(t, injector.AddRule(faults.Rule{ID: "duplicate", Direction: faults.DirectionOutbound, NodeIDs: flow.IdentifierList{duplicated}, DuplicateRate: 1}))
+
+	con := mocknetwork.NewConduit(t)
+	registry := mocknetwork.NewEngineRegistry(t)
+	registry.On("Register", channels.ConsensusCommittee, testifymock.Anything).Return(con, nil).Once()
+	net := faults.NewNetwork(registry, injector)
+	faulty, err := net.Register(channels.ConsensusCommittee, mocknetwork.NewMessageProcessor(t))
+	require.NoError(t, err)
+
+	event := "event"
+
+	t.Run("publish", func(t *testing.T) {
+		con.On("Publish", event, duplicated, unaffected).Return(nil).Once()
+		con.On("Publish", event, duplicated).Return(nil).Once()
+		sent := make(chan struct{})
+		con.On("Publish", event, delayed).Return(nil).Run(func(testifymock.Arguments) { close(sent) }).Once()
+
+		require.NoError(t, faulty.Publish(event, partitioned, delayed, duplicated, unaffected))
+		unittest.RequireCloseBefore(t, sent, time.Second, "delayed message was not published")
+	})
+
+	t.Run("unicast", func(t *testing.T) {
+		err := faulty.Unicast(event, partitioned)
+		require.True(t, network.IsPeerUnreachableError(err))
+
+		con.On("Unicast", event, duplicated).Return(nil).Twice()
+		require.NoError(t, faulty.Unicast(event, duplicated))
+	})
+
+	t.Run("multicast", func(t *testing.T) {
+		con.On("Multicast", event, uint(1), unaffected).Return(nil).Once()
+		require.NoError(t, faulty.Multicast(event, 1, partitioned, unaffected))
+	})
+}
+
+// TestNetwork_Inbound tests that the fault injecting network drops and delays inbound messages before handing them
+// to the message processor of the engine.
+func TestNetwork_Inbound(t *testing.T) {
+	partitioned := unittest.IdentifierFixture()
+	delayed := unittest.IdentifierFixture()
+
+	injector := faults.NewInjector(unittest.Logger(), mock.NewIdentityProvider(t))
+	require.NoError(t, injector.AddRule(faults.Rule{ID: "partition", Direction: faults.DirectionInbound, NodeIDs: flow.IdentifierList{partitioned}, Partition: true}))
+	require.NoError(t, injector.AddRule(faults.Rule{ID: "delay", Direction: faults.DirectionInbound, NodeIDs: flow.IdentifierList{delayed}, Delay: faults.Delay{Base: 10 * time.Millisecond}}))
+
+	var processor network.MessageProcessor
+	registry := mocknetwork.NewEngineRegistry(t)
+	registry.On("Register", channels.ConsensusCommittee, testifymock.Anything).
+		Run(func(args testifymock.Arguments) {
+			processor = args.Get(1).(network.MessageProcessor)
+		}).
+		Return(mocknetwork.NewConduit(t), nil).Once()
+
+	engine := mocknetwork.NewMessageProcessor(t)
+	_, err := faults.NewNetwork(registry, injector).Register(channels.ConsensusCommittee, engine)
+	require.NoError(t, err)
+
+	event := "event"
+	processed := make(chan struct{})
+	engine.On("Process", channels.ConsensusCommittee, delayed, event).Return(nil).Run(func(testifymock.Arguments) { close(processed) }).Once()
+
+	require.NoError(t, processor.Process(channels.ConsensusCommittee, partitioned, event))
+	require.NoError(t, processor.Process(channels.ConsensusCommittee, delayed, event))
+	unittest.RequireCloseBefore(t, processed, time.Second, "delayed message was not processed")
+}
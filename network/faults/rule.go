@@ -0,0 +1,159 @@
+package faults
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+// Direction is the direction of the messages a fault rule applies to, relative to the local node.
+type Direction string
+
+const (
+	// DirectionInbound applies the rule to messages received from remote nodes.
+	DirectionInbound Direction = "inbound"
+	// DirectionOutbound applies the rule to messages sent to remote nodes.
+	DirectionOutbound Direction = "outbound"
+	// DirectionBoth applies the rule to messages in both directions.
+	DirectionBoth Direction = "both"
+)
+
+// ParseDirection parses the given string as a direction.
+// Returns an error if the string is not a valid direction.
+func ParseDirection(str string) (Direction, error) {
+	switch d := Direction(str); d {
+	case DirectionInbound, DirectionOutbound, DirectionBoth:
+		return d, nil
+	default:
+		return "", fmt.Errorf("invalid direction %q, expected one of %q, %q or %q", str, DirectionInbound, DirectionOutbound, DirectionBoth)
+	}
+}
+
+// includes returns true if the direction covers the given direction.
+func (d Direction) includes(other Direction) bool {
+	return d == DirectionBoth || d == other
+}
+
+// Distribution is the distribution of the random jitter added to the base delay of a message.
+type Distribution string
+
+const (
+	// DistributionConstant adds no jitter, i.e., all messages are delayed by the base delay.
+	DistributionConstant Distribution = "constant"
+	// DistributionUniform adds a jitter drawn uniformly from [0, jitter].
+	DistributionUniform Distribution = "uniform"
+	// DistributionExponential adds a jitter drawn from an exponential distribution with mean jitter,
+	// which models the long tail of delays observed on congested links.
+	DistributionExponential Distribution = "exponential"
+)
+
+// ParseDistribution parses the given string as a delay distribution.
+// Returns an error if the string is not a valid distribution.
+func ParseDistribution(str string) (Distribution, error) {
+	switch d := Distribution(str); d {
+	case DistributionConstant, DistributionUniform, DistributionExponential:
+		return d, nil
+	default:
+		return "", fmt.Errorf("invalid distribution %q, expected one of %q, %q or %q", str, DistributionConstant, DistributionUniform, DistributionExponential)
+	}
+}
+
+// Delay is the delay injected into the delivery of messages: the base delay plus a random jitter drawn
+// from the distribution.
+type Delay struct {
+	Base         time.Duration
+	Jitter       time.Duration
+	Distribution Distribution
+}
+
+// sample draws a delay from the distribution.
+func (d Delay) sample() time.Duration {
+	if d.Jitter <= 0 {
+		return d.Base
+	}
+	switch d.Distribution {
+	case DistributionUniform:
+		return d.Base + time.Duration(rand.Int63n(int64(d.Jitter)+1))
+	case DistributionExponential:
+		return d.Base + time.Duration(rand.ExpFloat64()*float64(d.Jitter))
+	default:
+		return d.Base
+	}
+}
+
+// Rule is a fault injected into the messages exchanged with remote nodes. A rule applies to the messages in
+// its direction which match all of its filters; an empty filter matches all messages. Several rules can
+// apply to the same message, in which case their faults accumulate: the message is dropped if any rule
+// drops it, the delays are summed up, and the message is duplicated if any rule duplicates it.
+type Rule struct {
+	// ID identifies the rule, adding a rule with the ID of an existing rule replaces the existing rule.
+	ID string
+	// Direction is the direction of the messages the rule applies to.
+	Direction Direction
+	// Channels restricts the rule to messages on the given channels.
+	Channels channels.ChannelList
+	// Roles restricts the rule to messages exchanged with nodes of the given roles.
+	Roles flow.RoleList
+	// NodeIDs restricts the rule to messages exchanged with the given nodes.
+	NodeIDs flow.IdentifierList
+	// Partition drops all messages the rule applies to. Combined with a direction, it models one-way partitions.
+	Partition bool
+	// DropRate is the probability in [0, 1] that a message is dropped.
+	DropRate float64
+	// DuplicateRate is the probability in [0, 1] that a message is delivered twice.
+	DuplicateRate float64
+	// Delay is the delay injected into the delivery of messages.
+	Delay Delay
+}
+
+// Validate returns an error if the rule is invalid.
+func (r Rule) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("rule ID must be set")
+	}
+	if _, err := ParseDirection(string(r.Direction)); err != nil {
+		return err
+	}
+	if r.DropRate < 0 || r.DropRate > 1 {
+		return fmt.Errorf("drop rate must be in [0, 1], got %f", r.DropRate)
+	}
+	if r.DuplicateRate < 0 || r.DuplicateRate > 1 {
+		return fmt.Errorf("duplicate rate must be in [0, 1], got %f", r.DuplicateRate)
+	}
+	if r.Delay.Base < 0 || r.Delay.Jitter < 0 {
+		return fmt.Errorf("delay must not be negative")
+	}
+	if r.Delay.Jitter > 0 {
+		if _, err := ParseDistribution(string(r.Delay.Distribution)); err != nil {
+			return err
+		}
+	}
+	if !r.Partition && r.DropRate == 0 && r.DuplicateRate == 0 && r.Delay.Base == 0 && r.Delay.Jitter == 0 {
+		return fmt.Errorf("rule must inject at least one fault")
+	}
+	return nil
+}
+
+// matches returns true if the rule applies to a message in the given direction on the given channel,
+// exchanged with the given remote node. roleOf returns the role of the remote node, nil if the node is unknown.
+func (r Rule) matches(direction Direction, channel channels.Channel, nodeID flow.Identifier, roleOf func() *flow.Role) bool {
+	if !r.Direction.includes(direction) {
+		return false
+	}
+	if len(r.Channels) > 0 && !r.Channels.Contains(channel) {
+		return false
+	}
+	if len(r.NodeIDs) > 0 && !r.NodeIDs.Contains(nodeID) {
+		return false
+	}
+	if len(r.Roles) > 0 {
+		role := roleOf()
+		if role == nil || !r.Roles.Contains(*role) {
+			return false
+		}
+	}
+	return true
+}
@@ -33,6 +33,19 @@ type Config struct {
 	DNSCacheTTL time.Duration `validate:"gt=0s" mapstructure:"dns-cache-ttl"`
 	// DisallowListNotificationCacheSize size of the queue for notifications about new peers in the disallow list.
 	DisallowListNotificationCacheSize uint32 `validate:"gt=0" mapstructure:"disallow-list-notification-cache-size"`
+	// ChannelBandwidthQuotas limits the bandwidth of the messages exchanged on individual channels, each quota
+	// in the form "<channel>:<inbound|outbound>:<bytes-per-second>[:<burst>[:<origin-bytes-per-second>[:<origin-burst>]]]".
+	// Inbound quotas can additionally limit the bandwidth of each origin. Quotas of cluster channels are configured
+	// for the cluster channel prefix. Messages exceeding the quota of their channel are dropped.
+	ChannelBandwidthQuotas []string `mapstructure:"channel-bandwidth-quotas"`
+	// InboundQueueMaxMessagesPerSender is the maximum number of messages of a single sender in the inbound message
+	// queue. Messages of a sender exceeding it are dropped and reported to ALSP. Zero disables the limit.
+	// The default (5000) is far above the bursts of honest senders, e.g. sync requests and responses are limited
+	// to a few messages in flight per peer, each carrying up to 64 blocks.
+	InboundQueueMaxMessagesPerSender uint `mapstructure:"inbound-queue-max-messages-per-sender"`
+	// InboundQueueStakeWeighted determines whether the weights of the senders in the inbound message queue are
+	// scaled by their staking weight, in addition to their role.
+	InboundQueueStakeWeighted bool `mapstructure:"inbound-queue-stake-weighted"`
 }
 
 // AlspConfig is the config for the Application Layer Spam Prevention (ALSP) protocol.
@@ -56,6 +69,10 @@ type AlspConfig struct {
 	// events that are used to perform critical ALSP tasks, such as updating the spam records cache.
 	HearBeatInterval time.Duration `mapstructure:"alsp-heart-beat-interval"`
 
+	// SnapshotInterval is the interval between two snapshots of the spam records persisted to the node database.
+	// The snapshots allow the penalties of misbehaving nodes to survive restarts of the node.
+	SnapshotInterval time.Duration `mapstructure:"alsp-snapshot-interval"`
+
 	SyncEngine SyncEngineAlspConfig `mapstructure:",squash"`
 }
 
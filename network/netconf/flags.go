@@ -19,6 +19,9 @@ const (
 	peerUpdateInterval                = "peerupdate-interval"
 	dnsCacheTTL                       = "dns-cache-ttl"
 	disallowListNotificationCacheSize = "disallow-list-notification-cache-size"
+	channelBandwidthQuotas            = "channel-bandwidth-quotas"
+	inboundQueueMaxMessagesPerSender  = "inbound-queue-max-messages-per-sender"
+	inboundQueueStakeWeighted         = "inbound-queue-stake-weighted"
 	// resource manager config
 	rootResourceManagerPrefix  = "libp2p-resource-manager"
 	memoryLimitRatioPrefix     = "memory-limit-ratio"
@@ -40,6 +43,7 @@ const (
 	alspSpamRecordCacheSize            = "alsp-spam-record-cache-size"
 	alspSpamRecordQueueSize            = "alsp-spam-report-queue-size"
 	alspHearBeatInterval               = "alsp-heart-beat-interval"
+	alspSnapshotInterval               = "alsp-snapshot-interval"
 	alspSyncEngineBatchRequestBaseProb = "alsp-sync-engine-batch-request-base-prob"
 	alspSyncEngineRangeRequestBaseProb = "alsp-sync-engine-range-request-base-prob"
 	alspSyncEngineSyncRequestProb      = "alsp-sync-engine-sync-request-prob"
@@ -49,6 +53,9 @@ func AllFlagNames() []string {
 	allFlags := []string{
 		networkingConnectionPruning,
 		preferredUnicastsProtocols,
+		channelBandwidthQuotas,
+		inboundQueueMaxMessagesPerSender,
+		inboundQueueStakeWeighted,
 		receivedMessageCacheSize,
 		peerUpdateInterval,
 		BuildFlagName(unicastKey, MessageTimeoutKey),
@@ -74,6 +81,7 @@ func AllFlagNames() []string {
 		alspSpamRecordCacheSize,
 		alspSpamRecordQueueSize,
 		alspHearBeatInterval,
+		alspSnapshotInterval,
 		alspSyncEngineBatchRequestBaseProb,
 		alspSyncEngineRangeRequestBaseProb,
 		alspSyncEngineSyncRequestProb,
@@ -230,6 +238,18 @@ func InitializeNetworkFlags(flags *pflag.FlagSet, config *Config) {
 	flags.Duration(dnsCacheTTL, config.DNSCacheTTL, "time-to-live for dns cache")
 	flags.StringSlice(
 		preferredUnicastsProtocols, config.PreferredUnicastProtocols, "preferred unicast protocols in ascending order of preference")
+	flags.StringSlice(
+		channelBandwidthQuotas,
+		config.ChannelBandwidthQuotas,
+		"per-channel bandwidth quotas in the form <channel>:<inbound|outbound>:<bytes-per-second>[:<burst>[:<origin-bytes-per-second>[:<origin-burst>]]], inbound quotas can additionally limit each origin, cluster channels are configured by prefix (e.g. sync-cluster), messages exceeding the quota of their channel are dropped")
+	flags.Uint(
+		inboundQueueMaxMessagesPerSender,
+		config.InboundQueueMaxMessagesPerSender,
+		"maximum number of messages of a single sender in the inbound message queue, messages exceeding it are dropped and reported to alsp (0 disables the limit)")
+	flags.Bool(
+		inboundQueueStakeWeighted,
+		config.InboundQueueStakeWeighted,
+		"scale the weights of the senders in the inbound message queue by their staking weight, in addition to their role")
 	flags.Uint32(receivedMessageCacheSize, config.NetworkReceivedMessageCacheSize, "incoming message cache size at networking layer")
 	flags.Uint32(
 		disallowListNotificationCacheSize,
@@ -315,6 +335,9 @@ func InitializeNetworkFlags(flags *pflag.FlagSet, config *Config) {
 	flags.Duration(alspHearBeatInterval,
 		config.AlspConfig.HearBeatInterval,
 		"interval between two consecutive heartbeat events at alsp, recommended to leave it as default unless you know what you are doing.")
+	flags.Duration(alspSnapshotInterval,
+		config.AlspConfig.SnapshotInterval,
+		"interval between two consecutive snapshots of the alsp spam records persisted to the node database, only used by staked nodes.")
 	flags.Float32(alspSyncEngineBatchRequestBaseProb,
 		config.AlspConfig.SyncEngine.BatchRequestBaseProb,
 		"base probability of creating a misbehavior report for a batch request message")
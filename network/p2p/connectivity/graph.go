@@ -0,0 +1,190 @@
+package connectivity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+// Graph is the connectivity graph of the network, merged from the snapshots of one or more nodes.
+type Graph struct {
+	// Nodes are ordered by peer ID.
+	Nodes []GraphNode `json:"nodes"`
+	// Edges are ordered by their endpoints.
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a node of the connectivity graph.
+type GraphNode struct {
+	PeerID string `json:"peer_id"`
+	NodeID string `json:"node_id,omitempty"`
+	Role   string `json:"role,omitempty"`
+	// Snapshot is true if the graph includes a snapshot of the node, i.e. all its connections are known.
+	Snapshot bool `json:"snapshot"`
+}
+
+// GraphEdge is a connection between two nodes of the connectivity graph. Edges are undirected, with From being
+// lower than To.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Meshes are the channels of the topics for which either node has the other in its GossipSub mesh.
+	Meshes []string `json:"meshes,omitempty"`
+	// OneSided is true if only one of the nodes reported the connection although both have a snapshot, which
+	// indicates a connection being set up or torn down, or snapshots taken at different times.
+	OneSided bool `json:"one_sided,omitempty"`
+}
+
+type edgeKey struct {
+	from, to string
+}
+
+func newEdgeKey(a, b string) edgeKey {
+	if a > b {
+		a, b = b, a
+	}
+	return edgeKey{from: a, to: b}
+}
+
+// Merge merges the given snapshots into a connectivity graph. The nodes are the nodes the snapshots were taken
+// on and their connected peers, and the edges are the connections reported by any of the snapshots.
+func Merge(snapshots ...Snapshot) Graph {
+	nodes := make(map[string]*GraphNode)
+	addNode := func(peerID, nodeID, role string) *GraphNode {
+		node, ok := nodes[peerID]
+		if !ok {
+			node = &GraphNode{PeerID: peerID}
+			nodes[peerID] = node
+		}
+		if node.NodeID == "" {
+			node.NodeID = nodeID
+		}
+		if node.Role == "" {
+			node.Role = role
+		}
+		return node
+	}
+
+	// reported tracks which endpoints reported each connection
+	reported := make(map[edgeKey]map[string]struct{})
+	meshes := make(map[edgeKey]map[string]struct{})
+	for _, snapshot := range snapshots {
+		addNode(snapshot.PeerID, snapshot.NodeID, snapshot.Role).Snapshot = true
+		for _, p := range snapshot.Peers {
+			addNode(p.PeerID, p.NodeID, p.Role)
+			key := newEdgeKey(snapshot.PeerID, p.PeerID)
+			if reported[key] == nil {
+				reported[key] = make(map[string]struct{})
+			}
+			reported[key][snapshot.PeerID] = struct{}{}
+		}
+		for _, mesh := range snapshot.Meshes {
+			name := mesh.Topic
+			if channel, ok := channels.ChannelFromTopic(channels.Topic(mesh.Topic)); ok {
+				name = channel.String()
+			}
+			for _, peerID := range mesh.Peers {
+				key := newEdgeKey(snapshot.PeerID, peerID)
+				if meshes[key] == nil {
+					meshes[key] = make(map[string]struct{})
+				}
+				meshes[key][name] = struct{}{}
+			}
+		}
+	}
+
+	graph := Graph{
+		Nodes: make([]GraphNode, 0, len(nodes)),
+		Edges: make([]GraphEdge, 0, len(reported)),
+	}
+	for _, node := range nodes {
+		graph.Nodes = append(graph.Nodes, *node)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool { return graph.Nodes[i].PeerID < graph.Nodes[j].PeerID })
+
+	for key, reporters := range reported {
+		edge := GraphEdge{From: key.from, To: key.to}
+		_, fromReported := reporters[key.from]
+		_, toReported := reporters[key.to]
+		edge.OneSided = nodes[key.from].Snapshot && nodes[key.to].Snapshot && !(fromReported && toReported)
+		for name := range meshes[key] {
+			edge.Meshes = append(edge.Meshes, name)
+		}
+		sort.Strings(edge.Meshes)
+		graph.Edges = append(graph.Edges, edge)
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From != graph.Edges[j].From {
+			return graph.Edges[i].From < graph.Edges[j].From
+		}
+		return graph.Edges[i].To < graph.Edges[j].To
+	})
+
+	return graph
+}
+
+// roleColors are the fill colors of the nodes in the DOT graph by role.
+var roleColors = map[string]string{
+	flow.RoleCollection.String():   "lightblue",
+	flow.RoleConsensus.String():    "lightcoral",
+	flow.RoleExecution.String():    "lightgreen",
+	flow.RoleVerification.String(): "khaki",
+	flow.RoleAccess.String():       "plum",
+}
+
+// DOT renders the graph in the Graphviz DOT language. Nodes are labeled with their role and IDs and colored by
+// role, edges are labeled with the channels of the GossipSub meshes they are part of, and one-sided edges are
+// dashed. Nodes without a snapshot have a dashed border.
+func (g Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("graph connectivity {\n")
+	b.WriteString("  node [shape=box, style=filled, fontname=\"monospace\"];\n")
+	for _, node := range g.Nodes {
+		role := node.Role
+		if role == "" {
+			role = "unknown"
+		}
+		label := role + "\\n"
+		if node.NodeID != "" {
+			label += "node " + shorten(node.NodeID) + "\\n"
+		}
+		label += "peer " + shorten(node.PeerID)
+
+		color, ok := roleColors[node.Role]
+		if !ok {
+			color = "lightgrey"
+		}
+		style := "filled"
+		if !node.Snapshot {
+			style = "\"filled,dashed\""
+		}
+		fmt.Fprintf(&b, "  %q [label=\"%s\", fillcolor=%s, style=%s];\n", node.PeerID, label, color, style)
+	}
+	for _, edge := range g.Edges {
+		var attrs []string
+		if len(edge.Meshes) > 0 {
+			attrs = append(attrs, fmt.Sprintf("label=%q", strings.Join(edge.Meshes, "\n")), "penwidth=2")
+		}
+		if edge.OneSided {
+			attrs = append(attrs, "style=dashed")
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, "  %q -- %q [%s];\n", edge.From, edge.To, strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(&b, "  %q -- %q;\n", edge.From, edge.To)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// shorten returns the first and last characters of long IDs, to keep the node labels readable.
+func shorten(id string) string {
+	if len(id) <= 16 {
+		return id
+	}
+	return id[:8] + "..." + id[len(id)-6:]
+}
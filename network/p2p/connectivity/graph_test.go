@@ -0,0 +1,65 @@
+package connectivity_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/p2p/connectivity"
+)
+
+// TestMerge tests that snapshots are merged into a graph with one undirected edge per connection, labeled with
+// the mesh channels reported by either side, and that connections reported by one side only are one-sided.
+func TestMerge(t *testing.T) {
+	topic := channels.TopicFromChannel(channels.ConsensusCommittee, flow.ZeroID).String()
+	a := connectivity.Snapshot{
+		PeerID: "a",
+		NodeID: "node-a",
+		Role:   flow.RoleConsensus.String(),
+		Peers: []connectivity.Peer{
+			{PeerID: "b", NodeID: "node-b", Role: flow.RoleConsensus.String()},
+			{PeerID: "c"},
+		},
+		Meshes: []connectivity.Mesh{{Topic: topic, Peers: []string{"b"}}},
+	}
+	b := connectivity.Snapshot{
+		PeerID: "b",
+		NodeID: "node-b",
+		Role:   flow.RoleConsensus.String(),
+		Peers: []connectivity.Peer{
+			{PeerID: "a", NodeID: "node-a", Role: flow.RoleConsensus.String()},
+		},
+	}
+	c := connectivity.Snapshot{
+		PeerID: "c",
+		NodeID: "node-c",
+		Role:   flow.RoleExecution.String(),
+		Peers: []connectivity.Peer{
+			{PeerID: "d"},
+		},
+	}
+
+	graph := connectivity.Merge(a, b, c)
+	assert.Equal(t, []connectivity.GraphNode{
+		{PeerID: "a", NodeID: "node-a", Role: flow.RoleConsensus.String(), Snapshot: true},
+		{PeerID: "b", NodeID: "node-b", Role: flow.RoleConsensus.String(), Snapshot: true},
+		{PeerID: "c", NodeID: "node-c", Role: flow.RoleExecution.String(), Snapshot: true},
+		{PeerID: "d"},
+	}, graph.Nodes)
+	assert.Equal(t, []connectivity.GraphEdge{
+		{From: "a", To: "b", Meshes: []string{channels.ConsensusCommittee.String()}},
+		// c has a snapshot but did not report its connection to a
+		{From: "a", To: "c", OneSided: true},
+		// d has no snapshot, hence its connections are unknown
+		{From: "c", To: "d"},
+	}, graph.Edges)
+
+	dot := graph.DOT()
+	require.Contains(t, dot, `"a" -- "b" [label="consensus-committee", penwidth=2];`)
+	require.Contains(t, dot, `"a" -- "c" [style=dashed];`)
+	require.Contains(t, dot, `"c" -- "d";`)
+	require.Contains(t, dot, `"d" [label="unknown\npeer d", fillcolor=lightgrey, style="filled,dashed"];`)
+}
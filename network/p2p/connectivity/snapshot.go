@@ -0,0 +1,170 @@
+// Package connectivity exports point-in-time snapshots of the connectivity of a node: its connected peers with
+// their roles, addresses and connections, the GossipSub topic meshes it is part of, and its DHT routing table.
+// Snapshots of several nodes can be merged into a network-wide graph, which can be rendered in Graphviz DOT.
+package connectivity
+
+import (
+	"sort"
+	"time"
+
+	dht "github.com/libp2p/go-libp2p-kad-dht"
+	libp2pnet "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network/p2p"
+)
+
+// Snapshot is the connectivity of a node at a point in time.
+type Snapshot struct {
+	PeerID    string    `json:"peer_id"`
+	NodeID    string    `json:"node_id,omitempty"`
+	Role      string    `json:"role,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	// Addresses are the multiaddrs the node listens on.
+	Addresses []string `json:"addresses"`
+	// Peers are the connected peers, ordered by peer ID.
+	Peers []Peer `json:"peers"`
+	// Meshes are the GossipSub meshes of the topics the node is subscribed to, ordered by topic.
+	Meshes []Mesh `json:"meshes"`
+	// RoutingTable lists the peers in the DHT routing table, only for nodes running a DHT.
+	RoutingTable []string `json:"routing_table,omitempty"`
+}
+
+// Peer is a peer connected to the node.
+type Peer struct {
+	PeerID string `json:"peer_id"`
+	// NodeID and Role are empty for peers which are not in the identity table, e.g. unstaked peers.
+	NodeID     string   `json:"node_id,omitempty"`
+	Role       string   `json:"role,omitempty"`
+	Multiaddrs []string `json:"multiaddrs"`
+	// Protected is true if the connection manager protects the connections to the peer from being pruned.
+	Protected bool `json:"protected"`
+	// Tags are the connection manager tags of the peer.
+	Tags        map[string]int `json:"tags,omitempty"`
+	Connections []Connection   `json:"connections"`
+}
+
+// Connection is a connection to a peer.
+type Connection struct {
+	// Direction is "inbound" if the connection was opened by the peer, "outbound" if it was opened by the node.
+	Direction  string    `json:"direction"`
+	RemoteAddr string    `json:"remote_addr"`
+	Opened     time.Time `json:"opened"`
+	// Age is the time since the connection was opened, at the time of the snapshot.
+	Age string `json:"age"`
+}
+
+// Mesh is the GossipSub mesh of the node for a topic.
+type Mesh struct {
+	Topic string   `json:"topic"`
+	Peers []string `json:"peers"`
+}
+
+// Snapshotter takes connectivity snapshots of a node.
+type Snapshotter struct {
+	me           module.Local
+	node         p2p.LibP2PNode
+	idTranslator p2p.IDTranslator
+	idProvider   module.IdentityProvider
+}
+
+// NewSnapshotter creates a snapshotter for the given libp2p node. The ID translator and identity provider are used
+// to resolve the node IDs and roles of the peers.
+func NewSnapshotter(me module.Local, node p2p.LibP2PNode, idTranslator p2p.IDTranslator, idProvider module.IdentityProvider) *Snapshotter {
+	return &Snapshotter{
+		me:           me,
+		node:         node,
+		idTranslator: idTranslator,
+		idProvider:   idProvider,
+	}
+}
+
+// Snapshot returns the current connectivity of the node.
+func (s *Snapshotter) Snapshot() Snapshot {
+	now := time.Now()
+	h := s.node.Host()
+
+	snapshot := Snapshot{
+		PeerID:    s.node.ID().String(),
+		NodeID:    s.me.NodeID().String(),
+		Timestamp: now.UTC(),
+		Addresses: []string{},
+		Peers:     []Peer{},
+		Meshes:    []Mesh{},
+	}
+	if identity, ok := s.idProvider.ByNodeID(s.me.NodeID()); ok {
+		snapshot.Role = identity.Role.String()
+	}
+	for _, addr := range h.Addrs() {
+		snapshot.Addresses = append(snapshot.Addresses, addr.String())
+	}
+
+	peerIDs := h.Network().Peers()
+	sort.Slice(peerIDs, func(i, j int) bool { return peerIDs[i] < peerIDs[j] })
+	for _, pid := range peerIDs {
+		p := Peer{
+			PeerID:      pid.String(),
+			Multiaddrs:  []string{},
+			Protected:   h.ConnManager().IsProtected(pid, ""),
+			Connections: []Connection{},
+		}
+		if flowID, err := s.idTranslator.GetFlowID(pid); err == nil {
+			p.NodeID = flowID.String()
+			if identity, ok := s.idProvider.ByNodeID(flowID); ok {
+				p.Role = identity.Role.String()
+			}
+		}
+		for _, addr := range h.Peerstore().Addrs(pid) {
+			p.Multiaddrs = append(p.Multiaddrs, addr.String())
+		}
+		if info := h.ConnManager().GetTagInfo(pid); info != nil && len(info.Tags) > 0 {
+			p.Tags = info.Tags
+		}
+		for _, conn := range h.Network().ConnsToPeer(pid) {
+			stat := conn.Stat()
+			p.Connections = append(p.Connections, Connection{
+				Direction:  directionString(stat.Direction),
+				RemoteAddr: conn.RemoteMultiaddr().String(),
+				Opened:     stat.Opened.UTC(),
+				Age:        now.Sub(stat.Opened).Truncate(time.Second).String(),
+			})
+		}
+		snapshot.Peers = append(snapshot.Peers, p)
+	}
+
+	topics := s.node.SubscribedTopics()
+	sort.Slice(topics, func(i, j int) bool { return topics[i] < topics[j] })
+	for _, topic := range topics {
+		snapshot.Meshes = append(snapshot.Meshes, Mesh{
+			Topic: topic.String(),
+			Peers: peerIDStrings(s.node.GetLocalMeshPeers(topic)),
+		})
+	}
+
+	if ipfsDHT, ok := s.node.Routing().(*dht.IpfsDHT); ok {
+		snapshot.RoutingTable = peerIDStrings(ipfsDHT.RoutingTable().ListPeers())
+	}
+
+	return snapshot
+}
+
+func directionString(direction libp2pnet.Direction) string {
+	switch direction {
+	case libp2pnet.DirInbound:
+		return "inbound"
+	case libp2pnet.DirOutbound:
+		return "outbound"
+	default:
+		return "unknown"
+	}
+}
+
+func peerIDStrings(peerIDs []peer.ID) []string {
+	strs := make([]string, 0, len(peerIDs))
+	for _, pid := range peerIDs {
+		strs = append(strs, pid.String())
+	}
+	sort.Strings(strs)
+	return strs
+}
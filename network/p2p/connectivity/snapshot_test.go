@@ -0,0 +1,71 @@
+package connectivity_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/p2p/connectivity"
+	p2ptest "github.com/onflow/flow-go/network/p2p/test"
+	"github.com/onflow/flow-go/network/p2p/translator"
+	"github.com/onflow/flow-go/network/p2p/utils"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestSnapshot tests that the snapshot of a node includes its connected peers with their node IDs, roles and
+// connection directions, and that snapshots of several nodes are merged into a graph of their connections.
+func TestSnapshot(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signalerCtx := irrecoverable.NewMockSignalerContext(t, ctx)
+	idProvider := unittest.NewUpdatableIDProvider(flow.IdentityList{})
+
+	nodes, identities := p2ptest.NodesFixture(t, unittest.IdentifierFixture(), t.Name(), 3, idProvider, p2ptest.WithRole(flow.RoleExecution))
+	idProvider.SetIdentities(identities)
+	p2ptest.StartNodes(t, signalerCtx, nodes)
+	defer p2ptest.StopNodes(t, nodes, cancel)
+
+	// the first node connects to the other nodes
+	for _, identity := range identities[1:] {
+		peerInfo, err := utils.PeerAddressInfo(identity.IdentitySkeleton)
+		require.NoError(t, err)
+		require.NoError(t, nodes[0].ConnectToPeer(ctx, peerInfo))
+	}
+
+	snapshotter := func(i int) *connectivity.Snapshotter {
+		me := mockmodule.NewLocal(t)
+		me.On("NodeID").Return(identities[i].NodeID)
+		return connectivity.NewSnapshotter(me, nodes[i], translator.NewIdentityProviderIDTranslator(idProvider), idProvider)
+	}
+
+	snapshot0 := snapshotter(0).Snapshot()
+	assert.Equal(t, nodes[0].ID().String(), snapshot0.PeerID)
+	assert.Equal(t, identities[0].NodeID.String(), snapshot0.NodeID)
+	assert.Equal(t, flow.RoleExecution.String(), snapshot0.Role)
+	assert.NotEmpty(t, snapshot0.Addresses)
+	require.Len(t, snapshot0.Peers, 2)
+	for _, p := range snapshot0.Peers {
+		require.Len(t, p.Connections, 1)
+		assert.Equal(t, "outbound", p.Connections[0].Direction)
+		assert.Equal(t, flow.RoleExecution.String(), p.Role)
+		assert.NotEmpty(t, p.NodeID)
+	}
+
+	snapshot1 := snapshotter(1).Snapshot()
+	require.Len(t, snapshot1.Peers, 1)
+	assert.Equal(t, nodes[0].ID().String(), snapshot1.Peers[0].PeerID)
+	assert.Equal(t, identities[0].NodeID.String(), snapshot1.Peers[0].NodeID)
+	require.Len(t, snapshot1.Peers[0].Connections, 1)
+	assert.Equal(t, "inbound", snapshot1.Peers[0].Connections[0].Direction)
+
+	graph := connectivity.Merge(snapshot0, snapshot1)
+	require.Len(t, graph.Nodes, 3)
+	require.Len(t, graph.Edges, 2)
+	for _, edge := range graph.Edges {
+		assert.False(t, edge.OneSided)
+	}
+}
@@ -1,6 +1,8 @@
 package p2p
 
 import (
+	"time"
+
 	"github.com/libp2p/go-libp2p/core/peer"
 
 	p2pmsg "github.com/onflow/flow-go/network/p2p/message"
@@ -46,6 +48,23 @@ type InvCtrlMsgNotif struct {
 	TopicType CtrlMsgTopicType
 }
 
+// InvCtrlMsgViolation is the record of an invalid control message notification which was applied to the spam
+// record of a peer. It is kept for debugging purposes, i.e., to explain the spam penalty of a peer.
+type InvCtrlMsgViolation struct {
+	// Time is the time the violation was applied to the spam record.
+	Time time.Time
+	// MsgType the control message type.
+	MsgType p2pmsg.ControlMessageType
+	// TopicType reports whether the violation occurred on a cluster-prefixed topic.
+	TopicType CtrlMsgTopicType
+	// Count the number of errors.
+	Count uint64
+	// Error is the message of the error that occurred during validation.
+	Error string
+	// Penalty is the penalty applied to the spam record of the peer.
+	Penalty float64
+}
+
 // NewInvalidControlMessageNotification returns a new *InvCtrlMsgNotif
 // Args:
 //   - peerID: peer id of the offender.
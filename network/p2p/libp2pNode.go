@@ -172,6 +172,8 @@ type LibP2PNode interface {
 type Subscriptions interface {
 	// HasSubscription returns true if the node currently has an active subscription to the topic.
 	HasSubscription(topic channels.Topic) bool
+	// SubscribedTopics returns the topics the node currently has an active subscription to.
+	SubscribedTopics() []channels.Topic
 	// SetUnicastManager sets the unicast manager for the node.
 	SetUnicastManager(uniMgr UnicastManager)
 }
@@ -191,6 +193,10 @@ type PeerScore interface {
 	// PeerScoreExposer returns the node's peer score exposer implementation.
 	// If the node's peer score exposer has not been set, the second return value will be false.
 	PeerScoreExposer() PeerScoreExposer
+
+	// ScoringRegistryExposer returns the node's scoring registry exposer implementation.
+	// If peer scoring is disabled, the returned exposer is nil.
+	ScoringRegistryExposer() ScoringRegistryExposer
 }
 
 // PeerConnections subset of funcs related to underlying libp2p host connections.
@@ -459,6 +459,26 @@ func (_m *LibP2PNode) RoutingTable() *kbucket.RoutingTable {
 	return r0
 }
 
+// ScoringRegistryExposer provides a mock function with given fields:
+func (_m *LibP2PNode) ScoringRegistryExposer() p2p.ScoringRegistryExposer {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScoringRegistryExposer")
+	}
+
+	var r0 p2p.ScoringRegistryExposer
+	if rf, ok := ret.Get(0).(func() p2p.ScoringRegistryExposer); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(p2p.ScoringRegistryExposer)
+		}
+	}
+
+	return r0
+}
+
 // SetComponentManager provides a mock function with given fields: cm
 func (_m *LibP2PNode) SetComponentManager(cm *component.ComponentManager) {
 	_m.Called(cm)
@@ -545,6 +565,26 @@ func (_m *LibP2PNode) Subscribe(topic channels.Topic, topicValidator p2p.TopicVa
 	return r0, r1
 }
 
+// SubscribedTopics provides a mock function with given fields:
+func (_m *LibP2PNode) SubscribedTopics() []channels.Topic {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribedTopics")
+	}
+
+	var r0 []channels.Topic
+	if rf, ok := ret.Get(0).(func() []channels.Topic); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]channels.Topic)
+		}
+	}
+
+	return r0
+}
+
 // Unsubscribe provides a mock function with given fields: topic
 func (_m *LibP2PNode) Unsubscribe(topic channels.Topic) error {
 	ret := _m.Called(topic)
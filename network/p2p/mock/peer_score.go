@@ -32,6 +32,26 @@ func (_m *PeerScore) PeerScoreExposer() p2p.PeerScoreExposer {
 	return r0
 }
 
+// ScoringRegistryExposer provides a mock function with given fields:
+func (_m *PeerScore) ScoringRegistryExposer() p2p.ScoringRegistryExposer {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScoringRegistryExposer")
+	}
+
+	var r0 p2p.ScoringRegistryExposer
+	if rf, ok := ret.Get(0).(func() p2p.ScoringRegistryExposer); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(p2p.ScoringRegistryExposer)
+		}
+	}
+
+	return r0
+}
+
 // NewPeerScore creates a new instance of PeerScore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPeerScore(t interface {
@@ -193,6 +193,26 @@ func (_m *PubSubAdapter) RegisterTopicValidator(topic string, topicValidator p2p
 	return r0
 }
 
+// ScoringRegistryExposer provides a mock function with given fields:
+func (_m *PubSubAdapter) ScoringRegistryExposer() p2p.ScoringRegistryExposer {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScoringRegistryExposer")
+	}
+
+	var r0 p2p.ScoringRegistryExposer
+	if rf, ok := ret.Get(0).(func() p2p.ScoringRegistryExposer); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(p2p.ScoringRegistryExposer)
+		}
+	}
+
+	return r0
+}
+
 // Start provides a mock function with given fields: _a0
 func (_m *PubSubAdapter) Start(_a0 irrecoverable.SignalerContext) {
 	_m.Called(_a0)
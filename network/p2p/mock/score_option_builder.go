@@ -6,7 +6,13 @@ import (
 	irrecoverable "github.com/onflow/flow-go/module/irrecoverable"
 	mock "github.com/stretchr/testify/mock"
 
+	p2p "github.com/onflow/flow-go/network/p2p"
+
+	peer "github.com/libp2p/go-libp2p/core/peer"
+
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
+
+	time "time"
 )
 
 // ScoreOptionBuilder is an autogenerated mock type for the ScoreOptionBuilder type
@@ -66,6 +72,89 @@ func (_m *ScoreOptionBuilder) Done() <-chan struct{} {
 	return r0
 }
 
+// GetAppSpecificScore provides a mock function with given fields: peerID
+func (_m *ScoreOptionBuilder) GetAppSpecificScore(peerID peer.ID) (float64, time.Time, bool) {
+	ret := _m.Called(peerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAppSpecificScore")
+	}
+
+	var r0 float64
+	var r1 time.Time
+	var r2 bool
+	if rf, ok := ret.Get(0).(func(peer.ID) (float64, time.Time, bool)); ok {
+		return rf(peerID)
+	}
+	if rf, ok := ret.Get(0).(func(peer.ID) float64); ok {
+		r0 = rf(peerID)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(peer.ID) time.Time); ok {
+		r1 = rf(peerID)
+	} else {
+		r1 = ret.Get(1).(time.Time)
+	}
+
+	if rf, ok := ret.Get(2).(func(peer.ID) bool); ok {
+		r2 = rf(peerID)
+	} else {
+		r2 = ret.Get(2).(bool)
+	}
+
+	return r0, r1, r2
+}
+
+// GetRecentViolations provides a mock function with given fields: peerID
+func (_m *ScoreOptionBuilder) GetRecentViolations(peerID peer.ID) []p2p.InvCtrlMsgViolation {
+	ret := _m.Called(peerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecentViolations")
+	}
+
+	var r0 []p2p.InvCtrlMsgViolation
+	if rf, ok := ret.Get(0).(func(peer.ID) []p2p.InvCtrlMsgViolation); ok {
+		r0 = rf(peerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]p2p.InvCtrlMsgViolation)
+		}
+	}
+
+	return r0
+}
+
+// GetSpamRecord provides a mock function with given fields: peerID
+func (_m *ScoreOptionBuilder) GetSpamRecord(peerID peer.ID) (p2p.GossipSubSpamRecord, bool) {
+	ret := _m.Called(peerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSpamRecord")
+	}
+
+	var r0 p2p.GossipSubSpamRecord
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(peer.ID) (p2p.GossipSubSpamRecord, bool)); ok {
+		return rf(peerID)
+	}
+	if rf, ok := ret.Get(0).(func(peer.ID) p2p.GossipSubSpamRecord); ok {
+		r0 = rf(peerID)
+	} else {
+		r0 = ret.Get(0).(p2p.GossipSubSpamRecord)
+	}
+
+	if rf, ok := ret.Get(1).(func(peer.ID) bool); ok {
+		r1 = rf(peerID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
 // Ready provides a mock function with given fields:
 func (_m *ScoreOptionBuilder) Ready() <-chan struct{} {
 	ret := _m.Called()
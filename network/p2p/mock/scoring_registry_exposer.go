@@ -0,0 +1,115 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mockp2p
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	p2p "github.com/onflow/flow-go/network/p2p"
+
+	peer "github.com/libp2p/go-libp2p/core/peer"
+
+	time "time"
+)
+
+// ScoringRegistryExposer is an autogenerated mock type for the ScoringRegistryExposer type
+type ScoringRegistryExposer struct {
+	mock.Mock
+}
+
+// GetAppSpecificScore provides a mock function with given fields: peerID
+func (_m *ScoringRegistryExposer) GetAppSpecificScore(peerID peer.ID) (float64, time.Time, bool) {
+	ret := _m.Called(peerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAppSpecificScore")
+	}
+
+	var r0 float64
+	var r1 time.Time
+	var r2 bool
+	if rf, ok := ret.Get(0).(func(peer.ID) (float64, time.Time, bool)); ok {
+		return rf(peerID)
+	}
+	if rf, ok := ret.Get(0).(func(peer.ID) float64); ok {
+		r0 = rf(peerID)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(peer.ID) time.Time); ok {
+		r1 = rf(peerID)
+	} else {
+		r1 = ret.Get(1).(time.Time)
+	}
+
+	if rf, ok := ret.Get(2).(func(peer.ID) bool); ok {
+		r2 = rf(peerID)
+	} else {
+		r2 = ret.Get(2).(bool)
+	}
+
+	return r0, r1, r2
+}
+
+// GetRecentViolations provides a mock function with given fields: peerID
+func (_m *ScoringRegistryExposer) GetRecentViolations(peerID peer.ID) []p2p.InvCtrlMsgViolation {
+	ret := _m.Called(peerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecentViolations")
+	}
+
+	var r0 []p2p.InvCtrlMsgViolation
+	if rf, ok := ret.Get(0).(func(peer.ID) []p2p.InvCtrlMsgViolation); ok {
+		r0 = rf(peerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]p2p.InvCtrlMsgViolation)
+		}
+	}
+
+	return r0
+}
+
+// GetSpamRecord provides a mock function with given fields: peerID
+func (_m *ScoringRegistryExposer) GetSpamRecord(peerID peer.ID) (p2p.GossipSubSpamRecord, bool) {
+	ret := _m.Called(peerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSpamRecord")
+	}
+
+	var r0 p2p.GossipSubSpamRecord
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(peer.ID) (p2p.GossipSubSpamRecord, bool)); ok {
+		return rf(peerID)
+	}
+	if rf, ok := ret.Get(0).(func(peer.ID) p2p.GossipSubSpamRecord); ok {
+		r0 = rf(peerID)
+	} else {
+		r0 = ret.Get(0).(p2p.GossipSubSpamRecord)
+	}
+
+	if rf, ok := ret.Get(1).(func(peer.ID) bool); ok {
+		r1 = rf(peerID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// NewScoringRegistryExposer creates a new instance of ScoringRegistryExposer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewScoringRegistryExposer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ScoringRegistryExposer {
+	mock := &ScoringRegistryExposer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
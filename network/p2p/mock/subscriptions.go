@@ -37,6 +37,26 @@ func (_m *Subscriptions) SetUnicastManager(uniMgr p2p.UnicastManager) {
 	_m.Called(uniMgr)
 }
 
+// SubscribedTopics provides a mock function with given fields:
+func (_m *Subscriptions) SubscribedTopics() []channels.Topic {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribedTopics")
+	}
+
+	var r0 []channels.Topic
+	if rf, ok := ret.Get(0).(func() []channels.Topic); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]channels.Topic)
+		}
+	}
+
+	return r0
+}
+
 // NewSubscriptions creates a new instance of Subscriptions. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewSubscriptions(t interface {
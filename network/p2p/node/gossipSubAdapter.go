@@ -32,7 +32,10 @@ type GossipSubAdapter struct {
 	topicScoreParamFunc func(topic *pubsub.Topic) *pubsub.TopicScoreParams
 	logger              zerolog.Logger
 	peerScoreExposer    p2p.PeerScoreExposer
-	localMeshTracer     p2p.PubSubTracer
+	// scoringRegistryExposer exposes the spam records and recent RPC inspection violations of peers; nil if peer
+	// scoring is disabled.
+	scoringRegistryExposer p2p.ScoringRegistryExposer
+	localMeshTracer        p2p.PubSubTracer
 	// clusterChangeConsumer is a callback that is invoked when the set of active clusters of collection nodes changes.
 	// This callback is implemented by the rpc inspector suite of the GossipSubAdapter, and consumes the cluster changes
 	// to update the rpc inspector state of the recent topics (i.e., channels).
@@ -61,9 +64,10 @@ func NewGossipSubAdapter(
 	builder := component.NewComponentManagerBuilder()
 
 	a := &GossipSubAdapter{
-		gossipSub:             gossipSub,
-		logger:                logger.With().Str("component", "gossipsub-adapter").Logger(),
-		clusterChangeConsumer: clusterChangeConsumer,
+		gossipSub:              gossipSub,
+		logger:                 logger.With().Str("component", "gossipsub-adapter").Logger(),
+		clusterChangeConsumer:  clusterChangeConsumer,
+		scoringRegistryExposer: gossipSubConfig.ScoringRegistryExposer(),
 	}
 
 	topicScoreParamFunc, ok := gossipSubConfig.TopicScoreParamFunc()
@@ -240,6 +244,13 @@ func (g *GossipSubAdapter) PeerScoreExposer() p2p.PeerScoreExposer {
 	return g.peerScoreExposer
 }
 
+// ScoringRegistryExposer returns the scoring registry exposer for the gossipsub adapter. The exposer is a read-only
+// interface for querying the spam records and recent RPC inspection violations of peers.
+// The exposer is only available if the gossipsub adapter was configured with a score option, otherwise it is nil.
+func (g *GossipSubAdapter) ScoringRegistryExposer() p2p.ScoringRegistryExposer {
+	return g.scoringRegistryExposer
+}
+
 // ActiveClustersChanged is called when the active clusters of collection nodes changes.
 // GossipSubAdapter implements this method to forward the call to the clusterChangeConsumer (rpc inspector),
 // which will then update the cluster state of the rpc inspector.
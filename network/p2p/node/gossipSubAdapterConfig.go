@@ -138,6 +138,18 @@ func (g *GossipSubAdapterConfig) ScoringComponent() component.Component {
 	return g.scoreOption
 }
 
+// ScoringRegistryExposer returns the exposer of the scoring registry of the score option.
+// Args:
+// - None
+// Returns:
+// - p2p.ScoringRegistryExposer: the exposer of the scoring registry, nil if the score option is not set.
+func (g *GossipSubAdapterConfig) ScoringRegistryExposer() p2p.ScoringRegistryExposer {
+	if g.scoreOption == nil {
+		return nil
+	}
+	return g.scoreOption
+}
+
 // RpcInspectorComponent returns the component that manages the lifecycle of the inspector suite.
 // This is used to start and stop the inspector suite by the PubSubAdapter.
 // Args:
@@ -465,6 +465,17 @@ func (n *Node) HasSubscription(topic channels.Topic) bool {
 	return ok
 }
 
+// SubscribedTopics returns the topics the node currently has an active subscription to.
+func (n *Node) SubscribedTopics() []channels.Topic {
+	n.RLock()
+	defer n.RUnlock()
+	topics := make([]channels.Topic, 0, len(n.subs))
+	for topic := range n.subs {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
 // Host returns pointer to host object of node.
 func (n *Node) Host() host.Host {
 	return n.host
@@ -565,6 +576,12 @@ func (n *Node) PeerScoreExposer() p2p.PeerScoreExposer {
 	return n.pubSub.PeerScoreExposer()
 }
 
+// ScoringRegistryExposer returns the node's scoring registry exposer implementation.
+// If peer scoring is disabled, the returned exposer is nil.
+func (n *Node) ScoringRegistryExposer() p2p.ScoringRegistryExposer {
+	return n.pubSub.ScoringRegistryExposer()
+}
+
 // SetPubSub sets the node's pubsub implementation.
 // SetPubSub may be called at most once.
 func (n *Node) SetPubSub(ps p2p.PubSubAdapter) {
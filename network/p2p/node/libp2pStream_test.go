@@ -138,6 +138,15 @@ func TestCreateStream_WithPreferredGzipUnicast(t *testing.T) {
 		protocols.FlowGzipProtocolId(sporkId))
 }
 
+// TestCreateStream_WithPreferredZstdUnicast evaluates correctness of creating zstd-compressed tcp unicast streams between two libp2p nodes.
+func TestCreateStream_WithPreferredZstdUnicast(t *testing.T) {
+	sporkId := unittest.IdentifierFixture()
+	testCreateStream(t,
+		sporkId,
+		[]protocols.ProtocolName{protocols.ZstdCompressionUnicast},
+		protocols.FlowZstdProtocolId(sporkId))
+}
+
 // testCreateStreams checks if a new streams of "preferred" type is created each time when CreateStream is called and an existing stream is not
 // reused. The "preferred" stream type is the one with the largest index in `unicasts` list.
 // To check that the streams are of "preferred" type, it evaluates the protocol id of established stream against the input `protocolID`.
@@ -406,6 +415,12 @@ func TestUnicastOverStream_WithGzipStreamCompression(t *testing.T) {
 	testUnicastOverStream(t, p2ptest.WithPreferredUnicasts([]protocols.ProtocolName{protocols.GzipCompressionUnicast}))
 }
 
+// TestUnicastOverStream_WithZstdStreamCompression checks two nodes can send and receive unicast messages on zstd compressed streams
+// when both nodes have zstd stream compression enabled.
+func TestUnicastOverStream_WithZstdStreamCompression(t *testing.T) {
+	testUnicastOverStream(t, p2ptest.WithPreferredUnicasts([]protocols.ProtocolName{protocols.ZstdCompressionUnicast}))
+}
+
 // testUnicastOverStream sends a message from node 1 to node 2 and then from node 2 to node 1 over a unicast stream.
 func testUnicastOverStream(t *testing.T, opts ...p2ptest.NodeFixtureParameterOption) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -71,6 +71,11 @@ type PubSubAdapter interface {
 	// Returns:
 	//    The peer score exposer for the gossipsub adapter.
 	PeerScoreExposer() PeerScoreExposer
+
+	// ScoringRegistryExposer returns the scoring registry exposer for the gossipsub adapter. The exposer is a read-only
+	// interface for querying the spam records and recent RPC inspection violations of peers.
+	// The exposer is only available if peer scoring is enabled, otherwise it is nil.
+	ScoringRegistryExposer() ScoringRegistryExposer
 }
 
 // PubSubAdapterConfig abstracts the configuration for the underlying pubsub implementation.
@@ -126,6 +131,7 @@ type Topic interface {
 // ScoreOptionBuilder abstracts the configuration for the underlying pubsub score implementation.
 type ScoreOptionBuilder interface {
 	component.Component
+	ScoringRegistryExposer
 	// BuildFlowPubSubScoreOption builds the pubsub score options as pubsub.Option for the Flow network.
 	BuildFlowPubSubScoreOption() (*pubsub.PeerScoreParams, *pubsub.PeerScoreThresholds)
 	// TopicScoreParams returns the topic score params for the given topic.
@@ -301,3 +307,18 @@ type PeerScoreExposer interface {
 	// The returned map is keyed by topic name.
 	GetTopicScores(peerID peer.ID) (map[string]TopicScoreSnapshot, bool)
 }
+
+// ScoringRegistryExposer is the interface for the scoring registry that is used to expose the state behind the
+// application specific score of peers, i.e., their spam records and the RPC inspection violations which contributed
+// to their spam penalties.
+type ScoringRegistryExposer interface {
+	// GetAppSpecificScore returns the cached application specific score of the given peer, and the time it was
+	// last updated. Returns false if the score of the peer has not been computed yet.
+	GetAppSpecificScore(peerID peer.ID) (float64, time.Time, bool)
+	// GetSpamRecord returns a copy of the spam record of the given peer.
+	// Returns false if the peer has no spam record.
+	GetSpamRecord(peerID peer.ID) (GossipSubSpamRecord, bool)
+	// GetRecentViolations returns the most recent RPC inspection violations of the given peer which were applied
+	// to its spam record, ordered from oldest to newest.
+	GetRecentViolations(peerID peer.ID) []InvCtrlMsgViolation
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/onflow/flow-go/network/p2p"
+)
+
+// ViolationLog keeps the most recent RPC inspection violations applied to the spam records of peers, so that the
+// spam penalty of a peer can be explained. The log is a ring buffer of a fixed capacity shared by all peers, hence
+// the memory footprint is bounded regardless of the number of peers; once full, the oldest violation is evicted.
+// Note that a peer sending a flood of invalid control messages may evict the violations of other peers from the log.
+// ViolationLog is concurrency safe.
+type ViolationLog struct {
+	lock       sync.RWMutex
+	violations []violationEntry
+	// next is the index of the slot for the next violation.
+	next int
+	// full is true once the ring buffer has wrapped around.
+	full bool
+}
+
+type violationEntry struct {
+	peerID    peer.ID
+	violation p2p.InvCtrlMsgViolation
+}
+
+// NewViolationLog creates a new violation log keeping the given number of most recent violations.
+func NewViolationLog(capacity uint32) *ViolationLog {
+	return &ViolationLog{
+		violations: make([]violationEntry, capacity),
+	}
+}
+
+// Add adds the given violation of the given peer to the log, evicting the oldest violation if the log is full.
+func (l *ViolationLog) Add(peerID peer.ID, violation p2p.InvCtrlMsgViolation) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if len(l.violations) == 0 {
+		return
+	}
+	l.violations[l.next] = violationEntry{peerID: peerID, violation: violation}
+	l.next++
+	if l.next == len(l.violations) {
+		l.next = 0
+		l.full = true
+	}
+}
+
+// ByPeer returns the violations of the given peer kept in the log, ordered from oldest to newest.
+func (l *ViolationLog) ByPeer(peerID peer.ID) []p2p.InvCtrlMsgViolation {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	var violations []p2p.InvCtrlMsgViolation
+	collect := func(entries []violationEntry) {
+		for _, entry := range entries {
+			if entry.peerID == peerID {
+				violations = append(violations, entry.violation)
+			}
+		}
+	}
+	if l.full {
+		collect(l.violations[l.next:])
+	}
+	collect(l.violations[:l.next])
+	return violations
+}
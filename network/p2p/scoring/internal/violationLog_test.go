@@ -0,0 +1,50 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/network/p2p"
+	p2pmsg "github.com/onflow/flow-go/network/p2p/message"
+	"github.com/onflow/flow-go/network/p2p/scoring/internal"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestViolationLog_ByPeer tests that the violation log returns the violations of each peer, ordered from oldest to newest.
+func TestViolationLog_ByPeer(t *testing.T) {
+	log := internal.NewViolationLog(10)
+	peer1 := unittest.PeerIdFixture(t)
+	peer2 := unittest.PeerIdFixture(t)
+
+	require.Empty(t, log.ByPeer(peer1))
+
+	log.Add(peer1, p2p.InvCtrlMsgViolation{MsgType: p2pmsg.CtrlMsgGraft, Count: 1})
+	log.Add(peer2, p2p.InvCtrlMsgViolation{MsgType: p2pmsg.CtrlMsgPrune, Count: 2})
+	log.Add(peer1, p2p.InvCtrlMsgViolation{MsgType: p2pmsg.CtrlMsgIHave, Count: 3})
+
+	require.Equal(t, []p2p.InvCtrlMsgViolation{
+		{MsgType: p2pmsg.CtrlMsgGraft, Count: 1},
+		{MsgType: p2pmsg.CtrlMsgIHave, Count: 3},
+	}, log.ByPeer(peer1))
+	require.Equal(t, []p2p.InvCtrlMsgViolation{
+		{MsgType: p2pmsg.CtrlMsgPrune, Count: 2},
+	}, log.ByPeer(peer2))
+}
+
+// TestViolationLog_Eviction tests that once the violation log is full, the oldest violations are evicted first,
+// and the remaining violations are still returned from oldest to newest.
+func TestViolationLog_Eviction(t *testing.T) {
+	log := internal.NewViolationLog(3)
+	peerID := unittest.PeerIdFixture(t)
+
+	for i := uint64(1); i <= 5; i++ {
+		log.Add(peerID, p2p.InvCtrlMsgViolation{Count: i})
+	}
+
+	violations := log.ByPeer(peerID)
+	require.Len(t, violations, 3)
+	for i, violation := range violations {
+		require.Equal(t, uint64(i+3), violation.Count)
+	}
+}
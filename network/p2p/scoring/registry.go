@@ -23,12 +23,17 @@ import (
 	p2pconfig "github.com/onflow/flow-go/network/p2p/config"
 	p2plogging "github.com/onflow/flow-go/network/p2p/logging"
 	p2pmsg "github.com/onflow/flow-go/network/p2p/message"
+	"github.com/onflow/flow-go/network/p2p/scoring/internal"
 	"github.com/onflow/flow-go/utils/logging"
 )
 
 const (
 	// NotificationSilencedMsg log messages for silenced notifications
 	NotificationSilencedMsg = "ignoring invalid control message notification for peer during silence period"
+
+	// violationLogSize is the number of most recent RPC inspection violations kept by the registry (for all peers)
+	// to explain the spam penalties of peers.
+	violationLogSize = 1000
 )
 
 type SpamRecordInitFunc func() p2p.GossipSubSpamRecord
@@ -78,6 +83,9 @@ type GossipSubAppSpecificScoreRegistry struct {
 	silencePeriodStartTime time.Time
 	// silencePeriodElapsed atomic bool that stores a bool flag which indicates if the silence period is over or not.
 	silencePeriodElapsed *atomic.Bool
+
+	// violations keeps the most recent RPC inspection violations applied to the spam records of peers.
+	violations *internal.ViolationLog
 }
 
 // GossipSubAppSpecificScoreRegistryConfig is the configuration for the GossipSubAppSpecificScoreRegistry.
@@ -155,6 +163,7 @@ func NewGossipSubAppSpecificScoreRegistry(config *GossipSubAppSpecificScoreRegis
 		appSpecificScoreParams:    config.AppSpecificScoreParams,
 		duplicateMessageThreshold: config.DuplicateMessageThreshold,
 		collector:                 config.Collector,
+		violations:                internal.NewViolationLog(violationLogSize),
 	}
 
 	appSpecificScore := queue.NewHeroStore(config.Parameters.ScoreUpdateRequestQueueSize,
@@ -203,6 +212,7 @@ func NewGossipSubAppSpecificScoreRegistry(config *GossipSubAppSpecificScoreRegis
 }
 
 var _ p2p.GossipSubInvCtrlMsgNotifConsumer = (*GossipSubAppSpecificScoreRegistry)(nil)
+var _ p2p.ScoringRegistryExposer = (*GossipSubAppSpecificScoreRegistry)(nil)
 
 // AppSpecificScoreFunc returns the application specific score function that is called by the GossipSub protocol to determine the application specific score of a peer.
 // The application specific score is part of the overall score of a peer, and is used to determine the peer's score based
@@ -439,8 +449,9 @@ func (r *GossipSubAppSpecificScoreRegistry) handleMisbehaviourReport(notificatio
 		return nil
 	}
 
+	penalty := 0.0
 	record, err := r.spamScoreCache.Adjust(notification.PeerID, func(record p2p.GossipSubSpamRecord) p2p.GossipSubSpamRecord {
-		penalty := 0.0
+		penalty = 0.0
 		switch notification.MsgType {
 		case p2pmsg.CtrlMsgGraft:
 			penalty += r.penalty.GraftMisbehaviour
@@ -473,6 +484,18 @@ func (r *GossipSubAppSpecificScoreRegistry) handleMisbehaviourReport(notificatio
 		lg.Fatal().Err(err).Msg("could not adjust application specific penalty for peer")
 	}
 
+	violation := p2p.InvCtrlMsgViolation{
+		Time:      time.Now(),
+		MsgType:   notification.MsgType,
+		TopicType: notification.TopicType,
+		Count:     notification.Count,
+		Penalty:   penalty,
+	}
+	if notification.Error != nil {
+		violation.Error = notification.Error.Error()
+	}
+	r.violations.Add(notification.PeerID, violation)
+
 	lg.Debug().
 		Float64("spam_record_penalty", record.Penalty).
 		Msg("applied misbehaviour penalty and updated application specific penalty")
@@ -480,6 +503,34 @@ func (r *GossipSubAppSpecificScoreRegistry) handleMisbehaviourReport(notificatio
 	return nil
 }
 
+// GetAppSpecificScore returns the cached application specific score of the given peer, and the time it was last
+// updated. Returns false if the score of the peer has not been computed yet.
+func (r *GossipSubAppSpecificScoreRegistry) GetAppSpecificScore(peerID peer.ID) (float64, time.Time, bool) {
+	return r.appScoreCache.Get(peerID)
+}
+
+// GetSpamRecord returns a copy of the spam record of the given peer.
+// Returns false if the peer has no spam record.
+func (r *GossipSubAppSpecificScoreRegistry) GetSpamRecord(peerID peer.ID) (p2p.GossipSubSpamRecord, bool) {
+	record, err, ok := r.spamScoreCache.Get(peerID)
+	if err != nil {
+		// the error is only returned when the decay of the record fails, which is an exception; since the record is
+		// only read for debugging purposes here, the error is logged and the record is reported as missing.
+		r.logger.Error().Err(err).Str("peer_id", p2plogging.PeerId(peerID)).Msg("could not get spam record of peer")
+		return p2p.GossipSubSpamRecord{}, false
+	}
+	if !ok {
+		return p2p.GossipSubSpamRecord{}, false
+	}
+	return *record, true
+}
+
+// GetRecentViolations returns the most recent RPC inspection violations of the given peer which were applied to
+// its spam record, ordered from oldest to newest.
+func (r *GossipSubAppSpecificScoreRegistry) GetRecentViolations(peerID peer.ID) []p2p.InvCtrlMsgViolation {
+	return r.violations.ByPeer(peerID)
+}
+
 // afterSilencePeriod returns true if registry silence period is over, false otherwise.
 func (r *GossipSubAppSpecificScoreRegistry) afterSilencePeriod() bool {
 	if !r.silencePeriodElapsed.Load() {
@@ -0,0 +1,283 @@
+package scoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network/p2p"
+)
+
+// TopicScoreReport is the report of the score of a peer within a topic, as computed by GossipSub.
+type TopicScoreReport struct {
+	TimeInMesh               string  `json:"time_in_mesh"`
+	FirstMessageDeliveries   float64 `json:"first_message_deliveries"`
+	MeshMessageDeliveries    float64 `json:"mesh_message_deliveries"`
+	InvalidMessageDeliveries float64 `json:"invalid_message_deliveries"`
+	Warning                  bool    `json:"warning"`
+}
+
+// GossipSubScoreReport is the report of the score of a peer as computed by GossipSub, i.e., the latest snapshot of
+// the local scoring table taken by the score tracer.
+type GossipSubScoreReport struct {
+	Score              float64                     `json:"score"`
+	AppSpecificScore   float64                     `json:"app_specific_score"`
+	IPColocationFactor float64                     `json:"ip_colocation_factor"`
+	BehaviourPenalty   float64                     `json:"behaviour_penalty"`
+	Topics             map[string]TopicScoreReport `json:"topics"`
+}
+
+// AppSpecificScoreReport is the report of the application specific score of a peer cached by the scoring registry.
+type AppSpecificScoreReport struct {
+	Score       float64   `json:"score"`
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+// SpamRecordReport is the report of the spam record of a peer kept by the scoring registry.
+type SpamRecordReport struct {
+	Penalty             float64   `json:"penalty"`
+	Decay               float64   `json:"decay"`
+	LastDecayAdjustment time.Time `json:"last_decay_adjustment"`
+}
+
+// ViolationReport is the report of an RPC inspection violation which was applied to the spam record of a peer.
+type ViolationReport struct {
+	Time      time.Time `json:"time"`
+	MsgType   string    `json:"msg_type"`
+	TopicType string    `json:"topic_type"`
+	Count     uint64    `json:"count"`
+	Penalty   float64   `json:"penalty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// PeerScoreReport is the report of the scoring state of a peer, explaining how its score came about.
+// Parts of the report are omitted if they are not available, e.g., GossipSub is omitted if the score tracer is
+// disabled, and SpamRecord is omitted if the peer has never been penalized.
+type PeerScoreReport struct {
+	PeerID           string                  `json:"peer_id"`
+	NodeID           string                  `json:"node_id,omitempty"`
+	Role             string                  `json:"role,omitempty"`
+	GossipSub        *GossipSubScoreReport   `json:"gossipsub,omitempty"`
+	AppSpecificScore *AppSpecificScoreReport `json:"app_specific_score,omitempty"`
+	SpamRecord       *SpamRecordReport       `json:"spam_record,omitempty"`
+	RecentViolations []ViolationReport       `json:"recent_violations,omitempty"`
+}
+
+// score returns the score by which the report is ordered, i.e., the GossipSub score if available, otherwise the
+// application specific score if available, otherwise zero.
+func (r PeerScoreReport) score() float64 {
+	switch {
+	case r.GossipSub != nil:
+		return r.GossipSub.Score
+	case r.AppSpecificScore != nil:
+		return r.AppSpecificScore.Score
+	default:
+		return 0
+	}
+}
+
+// ScoreExplorerConfig is the configuration of the ScoreExplorer.
+type ScoreExplorerConfig struct {
+	// ConnectedPeers returns the peers the node is currently connected to.
+	ConnectedPeers func() peer.IDSlice `validate:"required"`
+
+	// PeerScore exposes the score tracer and the scoring registry of the node.
+	PeerScore p2p.PeerScore `validate:"required"`
+
+	// IdProvider is the identity provider used to translate peer ids to Flow identities.
+	IdProvider module.IdentityProvider `validate:"required"`
+}
+
+// ScoreExplorer explains the GossipSub scores of the peers of the node for debugging purposes, i.e., to find out why
+// a specific peer has a low score. It joins the local scoring table of GossipSub exposed by the score tracer with the
+// state of the application specific scoring registry, i.e., the spam records and the recent RPC inspection violations
+// of peers. It is served by the admin server as an admin command and, optionally, as an HTML page (see ServeHTTP).
+type ScoreExplorer struct {
+	connectedPeers func() peer.IDSlice
+	peerScore      p2p.PeerScore
+	idProvider     module.IdentityProvider
+}
+
+var _ http.Handler = (*ScoreExplorer)(nil)
+
+// NewScoreExplorer creates a new ScoreExplorer.
+// Returns an error if the configuration is invalid; any returned error is an irrecoverable error and indicates a bug
+// or misconfiguration.
+func NewScoreExplorer(config *ScoreExplorerConfig) (*ScoreExplorer, error) {
+	if err := validator.New().Struct(config); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return &ScoreExplorer{
+		connectedPeers: config.ConnectedPeers,
+		peerScore:      config.PeerScore,
+		idProvider:     config.IdProvider,
+	}, nil
+}
+
+// ConnectedPeers returns the score reports of all peers the node is connected to, ordered from the lowest to the
+// highest score.
+func (e *ScoreExplorer) ConnectedPeers() []PeerScoreReport {
+	return e.Peers(e.connectedPeers())
+}
+
+// Peers returns the score reports of the given peers, ordered from the lowest to the highest score.
+// The given peers do not need to be connected to the node.
+func (e *ScoreExplorer) Peers(peerIDs []peer.ID) []PeerScoreReport {
+	reports := make([]PeerScoreReport, 0, len(peerIDs))
+	for _, pid := range peerIDs {
+		reports = append(reports, e.Peer(pid))
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		if reports[i].score() != reports[j].score() {
+			return reports[i].score() < reports[j].score()
+		}
+		return reports[i].PeerID < reports[j].PeerID
+	})
+	return reports
+}
+
+// Peer returns the score report of the given peer.
+func (e *ScoreExplorer) Peer(pid peer.ID) PeerScoreReport {
+	report := PeerScoreReport{PeerID: pid.String()}
+	if identity, ok := e.idProvider.ByPeerID(pid); ok {
+		report.NodeID = identity.NodeID.String()
+		report.Role = identity.Role.String()
+	}
+
+	if exposer := e.peerScore.PeerScoreExposer(); exposer != nil {
+		report.GossipSub = gossipSubScoreReport(exposer, pid)
+	}
+
+	registry := e.peerScore.ScoringRegistryExposer()
+	if registry == nil {
+		return report
+	}
+	if score, updated, ok := registry.GetAppSpecificScore(pid); ok {
+		report.AppSpecificScore = &AppSpecificScoreReport{Score: score, LastUpdated: updated}
+	}
+	if record, ok := registry.GetSpamRecord(pid); ok {
+		report.SpamRecord = &SpamRecordReport{
+			Penalty:             record.Penalty,
+			Decay:               record.Decay,
+			LastDecayAdjustment: record.LastDecayAdjustment,
+		}
+	}
+	for _, violation := range registry.GetRecentViolations(pid) {
+		report.RecentViolations = append(report.RecentViolations, ViolationReport{
+			Time:      violation.Time,
+			MsgType:   violation.MsgType.String(),
+			TopicType: violation.TopicType.String(),
+			Count:     violation.Count,
+			Penalty:   violation.Penalty,
+			Error:     violation.Error,
+		})
+	}
+	return report
+}
+
+// gossipSubScoreReport returns the report of the GossipSub score of the given peer, or nil if the score tracer has
+// no snapshot of the peer.
+func gossipSubScoreReport(exposer p2p.PeerScoreExposer, pid peer.ID) *GossipSubScoreReport {
+	score, ok := exposer.GetScore(pid)
+	if !ok {
+		return nil
+	}
+	report := &GossipSubScoreReport{
+		Score:  score,
+		Topics: make(map[string]TopicScoreReport),
+	}
+	report.AppSpecificScore, _ = exposer.GetAppScore(pid)
+	report.IPColocationFactor, _ = exposer.GetIPColocationFactor(pid)
+	report.BehaviourPenalty, _ = exposer.GetBehaviourPenalty(pid)
+	topics, _ := exposer.GetTopicScores(pid)
+	for topic, snapshot := range topics {
+		report.Topics[topic] = TopicScoreReport{
+			TimeInMesh:               snapshot.TimeInMesh.String(),
+			FirstMessageDeliveries:   snapshot.FirstMessageDeliveries,
+			MeshMessageDeliveries:    snapshot.MeshMessageDeliveries,
+			InvalidMessageDeliveries: snapshot.InvalidMessageDeliveries,
+			Warning:                  snapshot.IsWarning(),
+		}
+	}
+	return report
+}
+
+// ServeHTTP serves the score reports of the connected peers as an HTML page, or as JSON if the "format" query
+// parameter is "json". The reports can be restricted to specific peers with the "peer_id" query parameter, which
+// may be repeated.
+func (e *ScoreExplorer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var reports []PeerScoreReport
+	if values, ok := query["peer_id"]; ok {
+		peerIDs := make([]peer.ID, 0, len(values))
+		for _, value := range values {
+			pid, err := peer.Decode(value)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid peer ID %q: %v", value, err), http.StatusBadRequest)
+				return
+			}
+			peerIDs = append(peerIDs, pid)
+		}
+		reports = e.Peers(peerIDs)
+	} else {
+		reports = e.ConnectedPeers()
+	}
+
+	if query.Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(reports); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := scoreExplorerPage.Execute(w, reports); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+var scoreExplorerPage = template.Must(template.New("score-explorer").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>GossipSub peer scores</title>
+<style>
+body { font-family: monospace; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px; vertical-align: top; text-align: left; }
+.warning { color: #c00; }
+</style>
+</head>
+<body>
+<h1>GossipSub peer scores</h1>
+<p>{{len .}} peers, ordered from the lowest to the highest score. <a href="?format=json">JSON</a></p>
+<table>
+<tr>
+<th>Peer</th><th>Score</th><th>App specific score</th><th>IP colocation factor</th><th>Behaviour penalty</th>
+<th>Topics</th><th>Spam record</th><th>Recent violations</th>
+</tr>
+{{range .}}
+<tr>
+<td><a href="?peer_id={{.PeerID}}">{{.PeerID}}</a>{{if .NodeID}}<br>{{.Role}} {{.NodeID}}{{end}}</td>
+{{with .GossipSub}}
+<td>{{.Score}}</td><td>{{.AppSpecificScore}}</td><td>{{.IPColocationFactor}}</td><td>{{.BehaviourPenalty}}</td>
+<td>{{range $topic, $score := .Topics}}<div{{if $score.Warning}} class="warning"{{end}}>{{$topic}}: mesh time {{$score.TimeInMesh}}, first deliveries {{$score.FirstMessageDeliveries}}, mesh deliveries {{$score.MeshMessageDeliveries}}, invalid deliveries {{$score.InvalidMessageDeliveries}}</div>{{end}}</td>
+{{else}}
+<td colspan="5">no score tracer snapshot</td>
+{{end}}
+<td>{{with .SpamRecord}}penalty {{.Penalty}}<br>decay {{.Decay}}{{else}}-{{end}}{{with .AppSpecificScore}}<br>cached app score {{.Score}} ({{.LastUpdated.Format "15:04:05"}}){{end}}</td>
+<td>{{range .RecentViolations}}<div>{{.Time.Format "15:04:05"}} {{.MsgType}} ({{.TopicType}}) x{{.Count}} penalty {{.Penalty}}{{if .Error}}: {{.Error}}{{end}}</div>{{else}}-{{end}}</td>
+</tr>
+{{end}}
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,151 @@
+package scoring_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/p2p"
+	p2pmsg "github.com/onflow/flow-go/network/p2p/message"
+	mockp2p "github.com/onflow/flow-go/network/p2p/mock"
+	"github.com/onflow/flow-go/network/p2p/scoring"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestScoreExplorer_ConnectedPeers tests that the score explorer joins the GossipSub score snapshots of the score tracer
+// with the state of the scoring registry, and orders the reports from the lowest to the highest score.
+func TestScoreExplorer_ConnectedPeers(t *testing.T) {
+	explorer, penalized, healthy, identity := newTestScoreExplorer(t)
+
+	reports := explorer.ConnectedPeers()
+	require.Len(t, reports, 2)
+
+	// the penalized peer has the lowest score, hence it is reported first.
+	require.Equal(t, penalized.String(), reports[0].PeerID)
+	require.Equal(t, identity.NodeID.String(), reports[0].NodeID)
+	require.Equal(t, identity.Role.String(), reports[0].Role)
+	require.NotNil(t, reports[0].GossipSub)
+	require.Equal(t, -100.0, reports[0].GossipSub.Score)
+	require.Equal(t, -90.0, reports[0].GossipSub.AppSpecificScore)
+	require.Equal(t, 2.0, reports[0].GossipSub.IPColocationFactor)
+	require.Equal(t, scoring.TopicScoreReport{
+		TimeInMesh:               time.Minute.String(),
+		FirstMessageDeliveries:   1,
+		MeshMessageDeliveries:    2,
+		InvalidMessageDeliveries: 3,
+		Warning:                  true,
+	}, reports[0].GossipSub.Topics["test-topic"])
+	require.NotNil(t, reports[0].AppSpecificScore)
+	require.Equal(t, -90.0, reports[0].AppSpecificScore.Score)
+	require.NotNil(t, reports[0].SpamRecord)
+	require.Equal(t, -90.0, reports[0].SpamRecord.Penalty)
+	require.Equal(t, 0.99, reports[0].SpamRecord.Decay)
+	require.Len(t, reports[0].RecentViolations, 1)
+	require.Equal(t, p2pmsg.CtrlMsgGraft.String(), reports[0].RecentViolations[0].MsgType)
+	require.Equal(t, p2p.CtrlMsgNonClusterTopicType.String(), reports[0].RecentViolations[0].TopicType)
+	require.Equal(t, "invalid graft", reports[0].RecentViolations[0].Error)
+
+	// the healthy peer has no score tracer snapshot, no identity, and no spam record.
+	require.Equal(t, healthy.String(), reports[1].PeerID)
+	require.Empty(t, reports[1].NodeID)
+	require.Nil(t, reports[1].GossipSub)
+	require.NotNil(t, reports[1].AppSpecificScore)
+	require.Equal(t, 10.0, reports[1].AppSpecificScore.Score)
+	require.Nil(t, reports[1].SpamRecord)
+	require.Empty(t, reports[1].RecentViolations)
+}
+
+// TestScoreExplorer_ServeHTTP tests that the score explorer serves the score reports as JSON and as an HTML page,
+// optionally restricted to the requested peers.
+func TestScoreExplorer_ServeHTTP(t *testing.T) {
+	explorer, penalized, _, _ := newTestScoreExplorer(t)
+
+	t.Run("json", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		explorer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/?format=json&peer_id=%s", penalized), nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var reports []scoring.PeerScoreReport
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &reports))
+		require.Len(t, reports, 1)
+		require.Equal(t, penalized.String(), reports[0].PeerID)
+	})
+
+	t.Run("html", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		explorer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Contains(t, recorder.Body.String(), penalized.String())
+		require.Contains(t, recorder.Body.String(), "invalid graft")
+	})
+
+	t.Run("invalid peer id", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		explorer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/?peer_id=invalid", nil))
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+// newTestScoreExplorer creates a score explorer with two connected peers: a penalized peer with a known identity, a
+// score tracer snapshot and a spam record, and a healthy peer which is only known to the scoring registry.
+func newTestScoreExplorer(t *testing.T) (*scoring.ScoreExplorer, peer.ID, peer.ID, *flow.Identity) {
+	penalized := unittest.PeerIdFixture(t)
+	healthy := unittest.PeerIdFixture(t)
+	identity := unittest.IdentityFixture()
+
+	idProvider := mock.NewIdentityProvider(t)
+	idProvider.On("ByPeerID", penalized).Return(identity, true).Maybe()
+	idProvider.On("ByPeerID", healthy).Return(nil, false).Maybe()
+
+	exposer := mockp2p.NewPeerScoreExposer(t)
+	exposer.On("GetScore", penalized).Return(-100.0, true).Maybe()
+	exposer.On("GetAppScore", penalized).Return(-90.0, true).Maybe()
+	exposer.On("GetIPColocationFactor", penalized).Return(2.0, true).Maybe()
+	exposer.On("GetBehaviourPenalty", penalized).Return(0.0, true).Maybe()
+	exposer.On("GetTopicScores", penalized).Return(map[string]p2p.TopicScoreSnapshot{
+		"test-topic": {
+			TimeInMesh:               time.Minute,
+			FirstMessageDeliveries:   1,
+			MeshMessageDeliveries:    2,
+			InvalidMessageDeliveries: 3,
+		},
+	}, true).Maybe()
+	exposer.On("GetScore", healthy).Return(0.0, false).Maybe()
+
+	registry := mockp2p.NewScoringRegistryExposer(t)
+	registry.On("GetAppSpecificScore", penalized).Return(-90.0, time.Now(), true).Maybe()
+	registry.On("GetSpamRecord", penalized).Return(p2p.GossipSubSpamRecord{Penalty: -90, Decay: 0.99}, true).Maybe()
+	registry.On("GetRecentViolations", penalized).Return([]p2p.InvCtrlMsgViolation{{
+		Time:      time.Now(),
+		MsgType:   p2pmsg.CtrlMsgGraft,
+		TopicType: p2p.CtrlMsgNonClusterTopicType,
+		Count:     1,
+		Error:     "invalid graft",
+		Penalty:   -10,
+	}}).Maybe()
+	registry.On("GetAppSpecificScore", healthy).Return(10.0, time.Now(), true).Maybe()
+	registry.On("GetSpamRecord", healthy).Return(p2p.GossipSubSpamRecord{}, false).Maybe()
+	registry.On("GetRecentViolations", healthy).Return(nil).Maybe()
+
+	peerScore := mockp2p.NewPeerScore(t)
+	peerScore.On("PeerScoreExposer").Return(exposer).Maybe()
+	peerScore.On("ScoringRegistryExposer").Return(registry).Maybe()
+
+	explorer, err := scoring.NewScoreExplorer(&scoring.ScoreExplorerConfig{
+		ConnectedPeers: func() peer.IDSlice {
+			return peer.IDSlice{healthy, penalized}
+		},
+		PeerScore:  peerScore,
+		IdProvider: idProvider,
+	})
+	require.NoError(t, err)
+	return explorer, penalized, healthy, identity
+}
@@ -232,6 +232,24 @@ func NewScoreOption(cfg *ScoreOptionConfig, provider p2p.SubscriptionProvider) (
 	return s, nil
 }
 
+// GetAppSpecificScore returns the cached application specific score of the given peer, and the time it was last
+// updated. Returns false if the score of the peer has not been computed yet.
+func (s *ScoreOption) GetAppSpecificScore(peerID peer.ID) (float64, time.Time, bool) {
+	return s.appScoreRegistry.GetAppSpecificScore(peerID)
+}
+
+// GetSpamRecord returns a copy of the spam record of the given peer.
+// Returns false if the peer has no spam record.
+func (s *ScoreOption) GetSpamRecord(peerID peer.ID) (p2p.GossipSubSpamRecord, bool) {
+	return s.appScoreRegistry.GetSpamRecord(peerID)
+}
+
+// GetRecentViolations returns the most recent RPC inspection violations of the given peer which were applied to
+// its spam record, ordered from oldest to newest.
+func (s *ScoreOption) GetRecentViolations(peerID peer.ID) []p2p.InvCtrlMsgViolation {
+	return s.appScoreRegistry.GetRecentViolations(peerID)
+}
+
 func (s *ScoreOption) BuildFlowPubSubScoreOption() (*pubsub.PeerScoreParams, *pubsub.PeerScoreThresholds) {
 	s.logger.Info().
 		Float64("gossip_threshold", s.peerThresholdParams.GossipThreshold).
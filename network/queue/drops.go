@@ -0,0 +1,59 @@
+package queue
+
+import (
+	"sync"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+// SenderDrops are the messages of a sender dropped since the previous flush of the DroppedMessages.
+type SenderDrops struct {
+	SenderID flow.Identifier
+	// Channel is the channel of the first dropped message.
+	Channel channels.Channel
+	Count   uint64
+}
+
+// DroppedMessages aggregates the messages dropped per sender, so that a flooding sender can be logged and reported
+// once per flush rather than once per dropped message. The number of aggregated senders is bounded by the number of
+// senders whose messages are dropped between two flushes.
+//
+// All methods are concurrency safe.
+type DroppedMessages struct {
+	mu    sync.Mutex
+	drops map[flow.Identifier]*SenderDrops
+}
+
+// NewDroppedMessages creates an empty aggregation of dropped messages.
+func NewDroppedMessages() *DroppedMessages {
+	return &DroppedMessages{
+		drops: make(map[flow.Identifier]*SenderDrops),
+	}
+}
+
+// Add adds a message of the given sender dropped on the given channel.
+func (d *DroppedMessages) Add(senderID flow.Identifier, channel channels.Channel) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	drops, ok := d.drops[senderID]
+	if !ok {
+		drops = &SenderDrops{SenderID: senderID, Channel: channel}
+		d.drops[senderID] = drops
+	}
+	drops.Count++
+}
+
+// Flush returns the messages dropped per sender since the previous flush, and resets the aggregation.
+func (d *DroppedMessages) Flush() []SenderDrops {
+	d.mu.Lock()
+	drops := d.drops
+	d.drops = make(map[flow.Identifier]*SenderDrops)
+	d.mu.Unlock()
+
+	flushed := make([]SenderDrops, 0, len(drops))
+	for _, senderDrops := range drops {
+		flushed = append(flushed, *senderDrops)
+	}
+	return flushed
+}
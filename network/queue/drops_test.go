@@ -0,0 +1,34 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/queue"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestDroppedMessages tests that dropped messages are aggregated per sender until they are flushed, keeping the
+// channel of the first dropped message of each sender.
+func TestDroppedMessages(t *testing.T) {
+	drops := queue.NewDroppedMessages()
+	flooder := unittest.IdentifierFixture()
+	other := unittest.IdentifierFixture()
+
+	for i := 0; i < 100; i++ {
+		drops.Add(flooder, channels.SyncCommittee)
+	}
+	drops.Add(flooder, channels.ConsensusCommittee)
+	drops.Add(other, channels.RequestChunks)
+
+	require.ElementsMatch(t, []queue.SenderDrops{
+		{SenderID: flooder, Channel: channels.SyncCommittee, Count: 101},
+		{SenderID: other, Channel: channels.RequestChunks, Count: 1},
+	}, drops.Flush())
+	require.Empty(t, drops.Flush())
+
+	drops.Add(other, channels.SyncCommittee)
+	require.Equal(t, []queue.SenderDrops{{SenderID: other, Channel: channels.SyncCommittee, Count: 1}}, drops.Flush())
+}
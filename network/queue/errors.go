@@ -0,0 +1,33 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// SenderQueueFullError indicates that a message was dropped because its sender already has the maximum number of
+// messages in the queue.
+type SenderQueueFullError struct {
+	SenderID flow.Identifier
+	Max      uint
+}
+
+// NewSenderQueueFullError creates a SenderQueueFullError for a message of the given sender.
+func NewSenderQueueFullError(senderID flow.Identifier, max uint) error {
+	return SenderQueueFullError{
+		SenderID: senderID,
+		Max:      max,
+	}
+}
+
+func (e SenderQueueFullError) Error() string {
+	return fmt.Sprintf("sender %x already has the maximum of %d messages in the queue", e.SenderID, e.Max)
+}
+
+// IsSenderQueueFullError returns whether the given error is a SenderQueueFullError.
+func IsSenderQueueFullError(err error) bool {
+	var e SenderQueueFullError
+	return errors.As(err, &e)
+}
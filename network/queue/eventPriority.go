@@ -21,7 +21,7 @@ type QMessage struct {
 	Payload  interface{}      // the decoded message
 	Size     int              // the size of the message in bytes
 	Target   channels.Channel // the target channel to lookup the engine
-	SenderID flow.Identifier  // senderID for logging
+	SenderID flow.Identifier  // senderID for fair queuing and logging
 }
 
 // GetEventPriority returns the priority of the flow event message.
@@ -36,6 +36,15 @@ func GetEventPriority(message interface{}) (Priority, error) {
 	return Priority(math.Ceil(float64(priorityByType+priorityBySize) / 2)), nil
 }
 
+// GetEventSender returns the sender of the flow event message.
+func GetEventSender(message interface{}) (flow.Identifier, error) {
+	qm, ok := message.(QMessage)
+	if !ok {
+		return flow.ZeroID, fmt.Errorf("invalid message format: %T", message)
+	}
+	return qm.SenderID, nil
+}
+
 // getPriorityByType maps a message type to its priority
 func getPriorityByType(message interface{}) Priority {
 	switch message.(type) {
@@ -0,0 +1,160 @@
+package queue
+
+import (
+	"container/heap"
+	"sort"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+type item struct {
+	message  interface{}
+	priority int             // The priority of the item in the queue.
+	sender   flow.Identifier // The sender of the message, used to schedule the senders fairly within the priority.
+	role     string          // The role of the sender, for telemetry.
+	// start is the virtual start time of the item in its priority class, which determines its position in the
+	// schedule of the class.
+	start float64
+	// seq maintains insertion order for items with the same virtual start time
+	seq       uint64
+	timestamp time.Time // timestamp for telemetry
+}
+
+// senderQueue holds the items of a sender within a priority class, in insertion order.
+type senderQueue struct {
+	items []*item
+	// finish is the virtual finish time of the last item inserted by the sender
+	finish float64
+	// The index is needed by update and is maintained by the heap.Interface methods.
+	index int // The index of the sender in the heap.
+}
+
+// senderHeap implements heap.Interface and holds the senders with queued items of a priority class, ordered by the
+// virtual start time of their oldest item.
+type senderHeap []*senderQueue
+
+func (h senderHeap) Len() int { return len(h) }
+
+func (h senderHeap) Less(i, j int) bool {
+	x, y := h[i].items[0], h[j].items[0]
+	if x.start != y.start {
+		return x.start < y.start
+	}
+	return x.seq < y.seq
+}
+
+func (h senderHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *senderHeap) Push(x interface{}) {
+	sq, ok := x.(*senderQueue)
+	if !ok {
+		return
+	}
+	sq.index = len(*h)
+	*h = append(*h, sq)
+}
+
+func (h *senderHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	sq := old[n-1]
+	old[n-1] = nil // avoid memory leak
+	sq.index = -1  // for safety
+	*h = old[0 : n-1]
+	return sq
+}
+
+// priorityClass schedules the items of one priority with start-time fair queuing: each item is tagged with a
+// virtual start time, which is the later of the virtual time of the class and the virtual finish time of the
+// previous item of its sender. The virtual finish time of an item is its start time plus the inverse of the weight
+// of its sender, so senders with a higher weight are served more often, and a sender inserting a burst of items
+// pushes its own items back without delaying the other senders.
+type priorityClass struct {
+	// vtime is the virtual start time of the last item removed from the class
+	vtime   float64
+	senders map[flow.Identifier]*senderQueue
+	active  senderHeap
+}
+
+func (c *priorityClass) push(it *item, weight float64) {
+	sq, ok := c.senders[it.sender]
+	if !ok {
+		// senders without queued items start at the current virtual time, so idling does not build up credit
+		sq = &senderQueue{finish: c.vtime}
+		c.senders[it.sender] = sq
+	}
+	it.start = sq.finish
+	if it.start < c.vtime {
+		it.start = c.vtime
+	}
+	sq.finish = it.start + 1/weight
+	sq.items = append(sq.items, it)
+	if len(sq.items) == 1 {
+		heap.Push(&c.active, sq)
+	}
+}
+
+func (c *priorityClass) pop() *item {
+	sq := c.active[0]
+	it := sq.items[0]
+	sq.items[0] = nil // avoid memory leak
+	sq.items = sq.items[1:]
+	c.vtime = it.start
+	if len(sq.items) == 0 {
+		heap.Pop(&c.active)
+		delete(c.senders, it.sender)
+	} else {
+		heap.Fix(&c.active, 0)
+	}
+	return it
+}
+
+// fairQueue holds the items by priority. Items of a higher priority are always removed first, and the senders of
+// items of the same priority are scheduled fairly by their weight, see priorityClass.
+type fairQueue struct {
+	classes map[int]*priorityClass
+	// priorities are the priorities of the classes, in descending order
+	priorities []int
+	len        int
+	seq        uint64
+}
+
+func newFairQueue() *fairQueue {
+	return &fairQueue{
+		classes: make(map[int]*priorityClass),
+	}
+}
+
+func (q *fairQueue) Len() int { return q.len }
+
+// push inserts the item, scheduling it with the given weight of its sender.
+func (q *fairQueue) push(it *item, weight float64) {
+	class, ok := q.classes[it.priority]
+	if !ok {
+		class = &priorityClass{senders: make(map[flow.Identifier]*senderQueue)}
+		q.classes[it.priority] = class
+		q.priorities = append(q.priorities, it.priority)
+		sort.Sort(sort.Reverse(sort.IntSlice(q.priorities)))
+	}
+	q.seq++
+	it.seq = q.seq
+	class.push(it, weight)
+	q.len++
+}
+
+// pop removes the next item of the highest priority with queued items. It must not be called on an empty queue.
+func (q *fairQueue) pop() *item {
+	for _, priority := range q.priorities {
+		class := q.classes[priority]
+		if class.active.Len() > 0 {
+			q.len--
+			return class.pop()
+		}
+	}
+	return nil
+}
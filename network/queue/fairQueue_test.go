@@ -0,0 +1,169 @@
+package queue_test
+
+import (
+	"context"
+	"testing"
+
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/queue"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// senderMessage is a test message carrying its sender and priority.
+type senderMessage struct {
+	sender   flow.Identifier
+	priority queue.Priority
+}
+
+func senderPriority(message interface{}) (queue.Priority, error) {
+	return message.(senderMessage).priority, nil
+}
+
+func messageSender(message interface{}) (flow.Identifier, error) {
+	return message.(senderMessage).sender, nil
+}
+
+// newFairQueue creates a message queue with fair queuing, whose senders are the given identities.
+func newFairQueue(t *testing.T, policy *queue.Policy, identities flow.IdentityList) *queue.MessageQueue {
+	idProvider := mock.NewIdentityProvider(t)
+	idProvider.On("ByNodeID", testifymock.Anything).Return(func(nodeID flow.Identifier) (*flow.Identity, bool) {
+		return identities.ByNodeID(nodeID)
+	}).Maybe()
+	idProvider.On("Identities", testifymock.Anything).Return(identities).Maybe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return queue.NewMessageQueue(ctx, senderPriority, metrics.NewNoopCollector(),
+		queue.WithFairQueuing(messageSender, policy, idProvider))
+}
+
+// insert inserts count messages of the given sender and priority.
+func insert(t *testing.T, mq *queue.MessageQueue, sender flow.Identifier, priority queue.Priority, count int) {
+	for i := 0; i < count; i++ {
+		require.NoError(t, mq.Insert(senderMessage{sender: sender, priority: priority}))
+	}
+}
+
+// removeCounts removes count messages and returns the number of removed messages of each sender.
+func removeCounts(mq *queue.MessageQueue, count int) map[flow.Identifier]int {
+	counts := make(map[flow.Identifier]int)
+	for i := 0; i < count; i++ {
+		counts[mq.Remove().(senderMessage).sender]++
+	}
+	return counts
+}
+
+// TestFairQueuing_RoleWeights tests that the senders of messages of the same priority are served in proportion to
+// the weight of their role.
+func TestFairQueuing_RoleWeights(t *testing.T) {
+	consensus := unittest.IdentityFixture(unittest.WithRole(flow.RoleConsensus))
+	access := unittest.IdentityFixture(unittest.WithRole(flow.RoleAccess))
+	mq := newFairQueue(t, queue.NewPolicy(0, false), flow.IdentityList{consensus, access})
+
+	insert(t, mq, access.NodeID, queue.LowPriority, 90)
+	insert(t, mq, consensus.NodeID, queue.LowPriority, 90)
+
+	// consensus nodes weigh 8 times as much as access nodes by default
+	counts := removeCounts(mq, 18)
+	require.Equal(t, 16, counts[consensus.NodeID])
+	require.Equal(t, 2, counts[access.NodeID])
+}
+
+// TestFairQueuing_Flooding tests that a sender flooding the queue does not delay the messages of other senders, and
+// that messages of a higher priority are still removed first.
+func TestFairQueuing_Flooding(t *testing.T) {
+	consensus := unittest.IdentityFixture(unittest.WithRole(flow.RoleConsensus))
+	flooder := unittest.IdentifierFixture()
+	mq := newFairQueue(t, queue.NewDefaultPolicy(), flow.IdentityList{consensus})
+
+	insert(t, mq, flooder, queue.LowPriority, 1000)
+	insert(t, mq, consensus.NodeID, queue.LowPriority, 1)
+	insert(t, mq, flooder, queue.HighPriority, 1)
+
+	require.Equal(t, senderMessage{sender: flooder, priority: queue.HighPriority}, mq.Remove())
+	counts := removeCounts(mq, 2)
+	require.Equal(t, 1, counts[consensus.NodeID])
+	require.Equal(t, 1, counts[flooder])
+}
+
+// TestFairQueuing_StakeWeights tests that the weights of the senders are scaled by their staking weight relative to
+// the average of their role, only if the policy is stake weighted.
+func TestFairQueuing_StakeWeights(t *testing.T) {
+	// the average stake is 200, hence the heavy node weighs 2 times and the light nodes half the weight of the role
+	heavy := unittest.IdentityFixture(unittest.WithRole(flow.RoleConsensus), unittest.WithInitialWeight(400))
+	light := unittest.IdentityListFixture(2, unittest.WithRole(flow.RoleConsensus), unittest.WithInitialWeight(100))
+	identities := append(flow.IdentityList{heavy}, light...)
+
+	t.Run("stake weighted", func(t *testing.T) {
+		mq := newFairQueue(t, queue.NewPolicy(0, true), identities)
+		insert(t, mq, light[0].NodeID, queue.LowPriority, 20)
+		insert(t, mq, heavy.NodeID, queue.LowPriority, 20)
+
+		counts := removeCounts(mq, 10)
+		require.Equal(t, 8, counts[heavy.NodeID])
+		require.Equal(t, 2, counts[light[0].NodeID])
+	})
+
+	t.Run("not stake weighted", func(t *testing.T) {
+		mq := newFairQueue(t, queue.NewPolicy(0, false), identities)
+		insert(t, mq, light[0].NodeID, queue.LowPriority, 20)
+		insert(t, mq, heavy.NodeID, queue.LowPriority, 20)
+
+		counts := removeCounts(mq, 10)
+		require.Equal(t, 5, counts[heavy.NodeID])
+		require.Equal(t, 5, counts[light[0].NodeID])
+	})
+}
+
+// TestFairQueuing_SenderCap tests that the messages of a sender exceeding the maximum number of messages per sender
+// are dropped, without affecting the other senders, and that the cap can be updated at runtime.
+func TestFairQueuing_SenderCap(t *testing.T) {
+	policy := queue.NewPolicy(3, true)
+	mq := newFairQueue(t, policy, flow.IdentityList{})
+	flooder := unittest.IdentifierFixture()
+	other := unittest.IdentifierFixture()
+
+	insert(t, mq, flooder, queue.LowPriority, 3)
+	err := mq.Insert(senderMessage{sender: flooder, priority: queue.HighPriority})
+	require.True(t, queue.IsSenderQueueFullError(err))
+	insert(t, mq, other, queue.LowPriority, 1)
+	require.Equal(t, 4, mq.Len())
+
+	// removing a message of the sender makes room for another one
+	require.Equal(t, flooder, mq.Remove().(senderMessage).sender)
+	insert(t, mq, flooder, queue.LowPriority, 1)
+	err = mq.Insert(senderMessage{sender: flooder, priority: queue.LowPriority})
+	require.True(t, queue.IsSenderQueueFullError(err))
+
+	// disabling the cap at runtime
+	require.NoError(t, policy.SetMaxMessagesPerSender(0))
+	insert(t, mq, flooder, queue.LowPriority, 10)
+	require.Equal(t, 14, mq.Len())
+}
+
+// TestFairQueuing_RuntimeWeights tests that updates of the weights of the roles apply to the messages inserted
+// afterwards, and that invalid weights are rejected.
+func TestFairQueuing_RuntimeWeights(t *testing.T) {
+	consensus := unittest.IdentityFixture(unittest.WithRole(flow.RoleConsensus))
+	access := unittest.IdentityFixture(unittest.WithRole(flow.RoleAccess))
+	policy := queue.NewPolicy(0, false)
+	mq := newFairQueue(t, policy, flow.IdentityList{consensus, access})
+
+	require.Error(t, policy.SetRoleWeight(flow.RoleAccess, 0))
+	require.Error(t, policy.SetRoleWeight(flow.Role(0), 1))
+	require.Error(t, policy.SetUnknownSenderWeight(0))
+	require.NoError(t, policy.SetRoleWeight(flow.RoleAccess, 8))
+	require.Equal(t, uint(8), policy.RoleWeight(flow.RoleAccess))
+
+	insert(t, mq, access.NodeID, queue.LowPriority, 20)
+	insert(t, mq, consensus.NodeID, queue.LowPriority, 20)
+
+	counts := removeCounts(mq, 10)
+	require.Equal(t, 5, counts[consensus.NodeID])
+	require.Equal(t, 5, counts[access.NodeID])
+}
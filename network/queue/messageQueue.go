@@ -1,12 +1,12 @@
 package queue
 
 import (
-	"container/heap"
 	"context"
 	"fmt"
 	"sync"
 	"time"
 
+	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 )
 
@@ -19,13 +19,39 @@ const HighPriority = Priority(10)
 // MessagePriorityFunc - the callback function to derive priority of a message
 type MessagePriorityFunc func(message interface{}) (Priority, error)
 
-// MessageQueue is the heap based priority queue implementation of the MessageQueue implementation
+// MessageSenderFunc - the callback function to derive the sender of a message
+type MessageSenderFunc func(message interface{}) (flow.Identifier, error)
+
+// MessageQueue is the priority queue implementation of the MessageQueue interface. Messages of a higher priority
+// are always removed first. Without fair queuing, messages of the same priority are removed in insertion order.
+// With fair queuing, the senders of messages of the same priority are served in proportion to their weight, and
+// the number of messages per sender is capped, see Policy.
 type MessageQueue struct {
-	pq           *priorityQueue
+	pq           *fairQueue
 	cond         *sync.Cond
 	priorityFunc MessagePriorityFunc
 	ctx          context.Context
 	metrics      module.NetworkInboundQueueMetrics
+
+	// fair queuing, nil senderFunc if disabled
+	senderFunc MessageSenderFunc
+	policy     *Policy
+	weigher    *senderWeigher
+	// perSender is the number of messages of each sender in the queue
+	perSender map[flow.Identifier]uint
+}
+
+// MessageQueueOption is a function that configures a MessageQueue.
+type MessageQueueOption func(*MessageQueue)
+
+// WithFairQueuing enables the fair queuing of the senders of the messages within each priority, according to the
+// given policy. The identity provider is used to derive the weights of the senders from their role and stake.
+func WithFairQueuing(senderFunc MessageSenderFunc, policy *Policy, idProvider module.IdentityProvider) MessageQueueOption {
+	return func(mq *MessageQueue) {
+		mq.senderFunc = senderFunc
+		mq.policy = policy
+		mq.weigher = newSenderWeigher(policy, idProvider)
+	}
 }
 
 func (mq *MessageQueue) Insert(message interface{}) error {
@@ -40,18 +66,42 @@ func (mq *MessageQueue) Insert(message interface{}) error {
 		return fmt.Errorf("failed to derive message priority: %w", err)
 	}
 
+	// determine the sender and its weight, without fair queuing all messages are from the same sender
+	sender := flow.ZeroID
+	weight := float64(1)
+	role := RoleUnknown
+	if mq.senderFunc != nil {
+		sender, err = mq.senderFunc(message)
+		if err != nil {
+			return fmt.Errorf("failed to derive message sender: %w", err)
+		}
+		weight, role = mq.weigher.weigh(sender)
+	}
+
 	// create the queue item
 	item := &item{
 		message:   message,
 		priority:  int(priority),
+		sender:    sender,
+		role:      role,
 		timestamp: time.Now(),
 	}
 
 	// lock the underlying mutex
 	mq.cond.L.Lock()
 
+	// drop the message if its sender already has too many messages in the queue
+	if mq.senderFunc != nil {
+		if max := mq.policy.MaxMessagesPerSender(); max > 0 && mq.perSender[sender] >= max {
+			mq.cond.L.Unlock()
+			mq.metrics.MessageDroppedBySenderCap(role)
+			return NewSenderQueueFullError(sender, max)
+		}
+		mq.perSender[sender]++
+	}
+
 	// push message to the underlying priority queue
-	heap.Push(mq.pq, item)
+	mq.pq.push(item, weight)
 
 	// record metrics
 	mq.metrics.MessageAdded(item.priority)
@@ -77,10 +127,18 @@ func (mq *MessageQueue) Remove() interface{} {
 
 		mq.cond.Wait()
 	}
-	item := heap.Pop(mq.pq).(*item)
+	item := mq.pq.pop()
+	if mq.senderFunc != nil {
+		mq.perSender[item.sender]--
+		if mq.perSender[item.sender] == 0 {
+			delete(mq.perSender, item.sender)
+		}
+	}
 
 	// record metrics
-	mq.metrics.QueueDuration(time.Since(item.timestamp), item.priority)
+	duration := time.Since(item.timestamp)
+	mq.metrics.QueueDuration(duration, item.priority)
+	mq.metrics.SenderQueueDuration(duration, item.role)
 	mq.metrics.MessageRemoved(item.priority)
 
 	return item.message
@@ -92,14 +150,16 @@ func (mq *MessageQueue) Len() int {
 	return mq.pq.Len()
 }
 
-func NewMessageQueue(ctx context.Context, priorityFunc MessagePriorityFunc, metrics module.NetworkInboundQueueMetrics) *MessageQueue {
-	var items = make([]*item, 0)
-	pq := priorityQueue(items)
+func NewMessageQueue(ctx context.Context, priorityFunc MessagePriorityFunc, metrics module.NetworkInboundQueueMetrics, opts ...MessageQueueOption) *MessageQueue {
 	mq := &MessageQueue{
-		pq:           &pq,
+		pq:           newFairQueue(),
 		priorityFunc: priorityFunc,
 		ctx:          ctx,
 		metrics:      metrics,
+		perSender:    make(map[flow.Identifier]uint),
+	}
+	for _, opt := range opts {
+		opt(mq)
 	}
 	m := sync.Mutex{}
 	mq.cond = sync.NewCond(&m)
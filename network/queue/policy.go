@@ -0,0 +1,126 @@
+package queue
+
+import (
+	"fmt"
+
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	// DefaultMaxMessagesPerSender is the default maximum number of messages of a single sender in the queue.
+	DefaultMaxMessagesPerSender = 5000
+
+	// DefaultStakeWeighted is whether the weights of the senders are scaled by their staking weight by default.
+	DefaultStakeWeighted = true
+
+	// DefaultUnknownSenderWeight is the default weight of the senders which are not in the identity table.
+	DefaultUnknownSenderWeight = 1
+)
+
+// DefaultRoleWeights are the default weights of the senders by role. Consensus messages are critical to the
+// liveness of the protocol, while access nodes are the closest to untrusted clients.
+var DefaultRoleWeights = map[flow.Role]uint{
+	flow.RoleConsensus:    8,
+	flow.RoleCollection:   4,
+	flow.RoleExecution:    4,
+	flow.RoleVerification: 2,
+	flow.RoleAccess:       1,
+}
+
+// Policy is the fair queuing policy of the queue, which can be updated at runtime.
+//
+// Within each priority, the senders are served in proportion to their weight, which is the weight of their role,
+// scaled by their staking weight relative to the average staking weight of the nodes of their role if the policy
+// is stake weighted. A sender exceeding the maximum number of messages in the queue has its messages dropped.
+//
+// All methods are concurrency safe.
+type Policy struct {
+	maxMessagesPerSender *atomic.Uint64
+	stakeWeighted        *atomic.Bool
+	// roleWeights holds an entry for each role, hence the map itself is never modified
+	roleWeights         map[flow.Role]*atomic.Uint64
+	unknownSenderWeight *atomic.Uint64
+}
+
+// NewPolicy creates a policy with the given cap on the messages per sender, and the default weights.
+func NewPolicy(maxMessagesPerSender uint, stakeWeighted bool) *Policy {
+	p := &Policy{
+		maxMessagesPerSender: atomic.NewUint64(uint64(maxMessagesPerSender)),
+		stakeWeighted:        atomic.NewBool(stakeWeighted),
+		roleWeights:          make(map[flow.Role]*atomic.Uint64),
+		unknownSenderWeight:  atomic.NewUint64(DefaultUnknownSenderWeight),
+	}
+	for _, role := range flow.Roles() {
+		p.roleWeights[role] = atomic.NewUint64(uint64(DefaultRoleWeights[role]))
+	}
+	return p
+}
+
+// NewDefaultPolicy creates a policy with the default configuration.
+func NewDefaultPolicy() *Policy {
+	return NewPolicy(DefaultMaxMessagesPerSender, DefaultStakeWeighted)
+}
+
+// MaxMessagesPerSender returns the maximum number of messages of a single sender in the queue. Zero means the
+// messages per sender are not limited.
+func (p *Policy) MaxMessagesPerSender() uint {
+	return uint(p.maxMessagesPerSender.Load())
+}
+
+// SetMaxMessagesPerSender sets the maximum number of messages of a single sender in the queue. Zero disables the
+// limit. Messages already in the queue are not dropped when the limit is lowered.
+func (p *Policy) SetMaxMessagesPerSender(max uint) error {
+	p.maxMessagesPerSender.Store(uint64(max))
+	return nil
+}
+
+// StakeWeighted returns whether the weights of the senders are scaled by their staking weight.
+func (p *Policy) StakeWeighted() bool {
+	return p.stakeWeighted.Load()
+}
+
+// SetStakeWeighted sets whether the weights of the senders are scaled by their staking weight.
+func (p *Policy) SetStakeWeighted(stakeWeighted bool) error {
+	p.stakeWeighted.Store(stakeWeighted)
+	return nil
+}
+
+// RoleWeight returns the weight of the senders of the given role.
+func (p *Policy) RoleWeight(role flow.Role) uint {
+	weight, ok := p.roleWeights[role]
+	if !ok {
+		return p.UnknownSenderWeight()
+	}
+	return uint(weight.Load())
+}
+
+// SetRoleWeight sets the weight of the senders of the given role.
+// Returns an error if the role is invalid or the weight is zero.
+func (p *Policy) SetRoleWeight(role flow.Role, weight uint) error {
+	w, ok := p.roleWeights[role]
+	if !ok {
+		return fmt.Errorf("invalid role: %d", role)
+	}
+	if weight == 0 {
+		return fmt.Errorf("weight of role %s must be positive", role)
+	}
+	w.Store(uint64(weight))
+	return nil
+}
+
+// UnknownSenderWeight returns the weight of the senders which are not in the identity table.
+func (p *Policy) UnknownSenderWeight() uint {
+	return uint(p.unknownSenderWeight.Load())
+}
+
+// SetUnknownSenderWeight sets the weight of the senders which are not in the identity table.
+// Returns an error if the weight is zero.
+func (p *Policy) SetUnknownSenderWeight(weight uint) error {
+	if weight == 0 {
+		return fmt.Errorf("weight of unknown senders must be positive")
+	}
+	p.unknownSenderWeight.Store(uint64(weight))
+	return nil
+}
@@ -0,0 +1,85 @@
+package queue
+
+import (
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/module"
+)
+
+const (
+	// RoleUnknown is the role label of the senders which are not in the identity table.
+	RoleUnknown = "unknown"
+
+	// minStakeFactor and maxStakeFactor bound the scaling of the weight of a sender by its staking weight, so that
+	// the role of the sender prevails over its stake.
+	minStakeFactor = 0.25
+	maxStakeFactor = 4
+
+	// averageStakeTTL is the time the average staking weights of the roles are cached for.
+	averageStakeTTL = time.Minute
+)
+
+// senderWeigher derives the weights of the senders from their role and staking weight, according to the policy.
+type senderWeigher struct {
+	policy     *Policy
+	idProvider module.IdentityProvider
+
+	mu sync.Mutex
+	// averageStakes caches the average staking weight of the nodes of each role
+	averageStakes map[flow.Role]float64
+	refreshedAt   time.Time
+}
+
+func newSenderWeigher(policy *Policy, idProvider module.IdentityProvider) *senderWeigher {
+	return &senderWeigher{
+		policy:     policy,
+		idProvider: idProvider,
+	}
+}
+
+// weigh returns the weight of the given sender, and its role label.
+func (w *senderWeigher) weigh(senderID flow.Identifier) (float64, string) {
+	identity, ok := w.idProvider.ByNodeID(senderID)
+	if !ok {
+		return float64(w.policy.UnknownSenderWeight()), RoleUnknown
+	}
+
+	weight := float64(w.policy.RoleWeight(identity.Role))
+	if w.policy.StakeWeighted() {
+		if average := w.averageStake(identity.Role); average > 0 {
+			factor := float64(identity.InitialWeight) / average
+			if factor < minStakeFactor {
+				factor = minStakeFactor
+			}
+			if factor > maxStakeFactor {
+				factor = maxStakeFactor
+			}
+			weight *= factor
+		}
+	}
+	return weight, identity.Role.String()
+}
+
+// averageStake returns the average staking weight of the nodes of the given role.
+func (w *senderWeigher) averageStake(role flow.Role) float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.averageStakes == nil || time.Since(w.refreshedAt) > averageStakeTTL {
+		totals := make(map[flow.Role]uint64)
+		counts := make(map[flow.Role]int)
+		for _, identity := range w.idProvider.Identities(filter.Any) {
+			totals[identity.Role] += identity.InitialWeight
+			counts[identity.Role]++
+		}
+		w.averageStakes = make(map[flow.Role]float64, len(counts))
+		for r, count := range counts {
+			w.averageStakes[r] = float64(totals[r]) / float64(count)
+		}
+		w.refreshedAt = time.Now()
+	}
+	return w.averageStakes[role]
+}
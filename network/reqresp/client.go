@@ -0,0 +1,367 @@
+// Package reqresp implements request/response calls on top of the unicast messages of a network.Conduit.
+//
+// Engines exchanging requests and responses over a channel usually re-implement the same logic: tracking which
+// requests are pending, matching the responses to them, retrying requests which time out with other nodes, and
+// rejecting responses which are unexpected. The Client bundles this logic: a request is sent to a few targets at a
+// time, and a call awaits its response. Responses received by the engine on the channel are delivered to the
+// client, which matches them to the pending calls by key. Requests for a key which already has a pending call are
+// multiplexed onto it, so a single response completes all of them.
+//
+// Requests and responses are regular messages of the channel, hence they are subject to the authorization of their
+// message types: a request must be sent with the protocol its message type is authorized for (see Config.Publish).
+// The responses are multiplexed onto the calls by key, not onto a dedicated stream per request.
+package reqresp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// Config is the configuration of a request/response client.
+type Config[Resp any] struct {
+	// Timeout is the time an attempt waits for a response before the request is retried with alternative targets.
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts made for a request before its call fails with ErrNoResponse.
+	MaxAttempts uint
+	// Fanout is the number of targets each attempt is sent to.
+	Fanout uint
+	// MaxResponseSize is the maximum size of a response in bytes, as measured by ResponseSize.
+	// Zero means the size of the responses is not limited.
+	MaxResponseSize int
+	// ResponseSize returns the size of a response in bytes. It is required if MaxResponseSize is set.
+	ResponseSize func(Resp) int
+	// Publish sends the request of each attempt with a single Conduit.Publish to all its targets, instead of
+	// unicasting it to each target. It is required for requests whose message type is only authorized over pubsub,
+	// since receivers reject unauthorized unicast messages and report their sender to ALSP.
+	Publish bool
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c Config[Resp]) Validate() error {
+	if c.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive, got %s", c.Timeout)
+	}
+	if c.MaxAttempts == 0 {
+		return fmt.Errorf("max attempts must be positive")
+	}
+	if c.Fanout == 0 {
+		return fmt.Errorf("fanout must be positive")
+	}
+	if c.MaxResponseSize < 0 {
+		return fmt.Errorf("max response size must not be negative, got %d", c.MaxResponseSize)
+	}
+	if c.MaxResponseSize > 0 && c.ResponseSize == nil {
+		return fmt.Errorf("response size function is required to limit the response size")
+	}
+	return nil
+}
+
+// Call is a pending request awaiting its response.
+type Call[Resp any] struct {
+	key flow.Identifier
+	// reset is signalled when the request is sent again by a multiplexed request, to restart the attempt timeout.
+	reset chan struct{}
+	done  chan struct{}
+
+	// the fields below are protected by the lock of the client
+	targets  flow.IdentifierList          // candidate targets, in the order they are asked
+	round    map[flow.Identifier]struct{} // targets asked in the current round
+	asked    map[flow.Identifier]struct{} // targets the request was sent to, whose responses are accepted
+	excluded map[flow.Identifier]struct{} // targets which sent invalid responses, which are not asked again
+	attempts uint                         // attempts made since the last request was multiplexed onto the call
+	finished bool                         // whether done is closed
+	resp     Resp                         // response, set once finished
+	originID flow.Identifier              // origin of the response, set once finished
+	err      error                        // error of the call, set once finished
+}
+
+// Done returns a channel which is closed once the call has completed, with a response or an error.
+func (c *Call[Resp]) Done() <-chan struct{} {
+	return c.done
+}
+
+// Result returns the response of the call and the node it was received from. It must only be called once the call
+// is done.
+// Expected errors during normal operations:
+//   - ErrNoResponse if no response was received within all the attempts of the call.
+//   - context.Canceled or context.DeadlineExceeded if the context of the call was done before a response was received.
+func (c *Call[Resp]) Result() (Resp, flow.Identifier, error) {
+	return c.resp, c.originID, c.err
+}
+
+// nextTargets returns the next targets to send the request to, and marks them as asked. Targets which have not
+// been asked in the current round are preferred, in the order of the candidates, so each attempt is sent to
+// alternative targets until all of them have been asked, after which a new round starts. Excluded targets are
+// skipped.
+// The lock of the client must be held.
+func (c *Call[Resp]) nextTargets(fanout uint) flow.IdentifierList {
+	next := make(flow.IdentifierList, 0, fanout)
+	eligible := func(targetID flow.Identifier) bool {
+		_, excluded := c.excluded[targetID]
+		return !excluded && !next.Contains(targetID)
+	}
+	for uint(len(next)) < fanout {
+		candidates := c.targets.Filter(func(targetID flow.Identifier) bool {
+			_, asked := c.round[targetID]
+			return !asked && eligible(targetID)
+		})
+		if len(candidates) == 0 {
+			if len(c.round) == 0 {
+				break
+			}
+			// all targets have been asked, the next ones are asked again in a new round
+			c.round = make(map[flow.Identifier]struct{})
+			continue
+		}
+		c.round[candidates[0]] = struct{}{}
+		c.asked[candidates[0]] = struct{}{}
+		next = append(next, candidates[0])
+	}
+	return next
+}
+
+// Client sends requests over a conduit and matches the responses delivered to it with the pending calls.
+//
+// All methods are concurrency safe.
+type Client[Req any, Resp any] struct {
+	log    zerolog.Logger
+	con    network.Conduit
+	config Config[Resp]
+
+	mu    sync.Mutex
+	calls map[flow.Identifier]*Call[Resp]
+}
+
+// NewClient creates a client sending the requests over the given conduit.
+// Returns an error if the configuration is invalid.
+func NewClient[Req any, Resp any](log zerolog.Logger, con network.Conduit, config Config[Resp]) (*Client[Req, Resp], error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request/response client configuration: %w", err)
+	}
+
+	return &Client[Req, Resp]{
+		log:    log.With().Str("component", "reqresp_client").Logger(),
+		con:    con,
+		config: config,
+		calls:  make(map[flow.Identifier]*Call[Resp]),
+	}, nil
+}
+
+// Request sends the request identified by the given key to the next targets, and returns the call awaiting its
+// response. The targets are the candidates the request may be sent to, in order of preference.
+//
+// If a call for the key is already pending, the request is multiplexed onto it: the given targets are added to the
+// candidates of the call, the request is sent again to its next targets, the call is granted another MaxAttempts
+// attempts, and the pending call is returned with joined set to true. Otherwise, a new call is created, which fails
+// once the given context is done.
+//
+// Expected errors during normal operations:
+//   - ErrNoTargets if there is no target to send the request to.
+//   - network.EmptyTargetList or other network errors if the request could not be sent to any target, in which case
+//     a new call is dropped.
+func (c *Client[Req, Resp]) Request(ctx context.Context, key flow.Identifier, req Req, targetIDs flow.IdentifierList) (*Call[Resp], bool, error) {
+	c.mu.Lock()
+	call, joined := c.calls[key]
+	if !joined {
+		call = &Call[Resp]{
+			key:      key,
+			reset:    make(chan struct{}, 1),
+			done:     make(chan struct{}),
+			round:    make(map[flow.Identifier]struct{}),
+			asked:    make(map[flow.Identifier]struct{}),
+			excluded: make(map[flow.Identifier]struct{}),
+		}
+	}
+	call.targets = call.targets.Union(targetIDs)
+	next := call.nextTargets(c.config.Fanout)
+	if len(next) == 0 {
+		c.mu.Unlock()
+		return nil, false, fmt.Errorf("could not send request %x: %w", key, ErrNoTargets)
+	}
+	call.attempts = 1
+	if !joined {
+		c.calls[key] = call
+	}
+	c.mu.Unlock()
+
+	err := c.send(req, next)
+	if err != nil {
+		err = fmt.Errorf("could not send request %x: %w", key, err)
+		if !joined {
+			c.finish(call, err)
+		}
+		return nil, false, err
+	}
+
+	if joined {
+		// restarts the attempt timeout of the call, since the request has just been sent
+		select {
+		case call.reset <- struct{}{}:
+		default:
+		}
+		return call, true, nil
+	}
+
+	go c.run(ctx, call, req)
+	return call, false, nil
+}
+
+// Deliver delivers a response received from the given origin for the request identified by the given key. The
+// response completes the pending call for the key, if the request was sent to the origin.
+// Expected errors during normal operations:
+//   - ErrUnsolicitedResponse if there is no pending call for the key, or if the request was not sent to the origin.
+//     This includes responses arriving late, after their call has completed or timed out.
+//   - ErrResponseTooLarge if the response exceeds the maximum response size. The origin is not asked again for the
+//     request, and the call keeps awaiting the responses of the other targets.
+func (c *Client[Req, Resp]) Deliver(originID flow.Identifier, key flow.Identifier, resp Resp) error {
+	size := 0
+	if c.config.MaxResponseSize > 0 {
+		size = c.config.ResponseSize(resp)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	call, ok := c.calls[key]
+	if !ok {
+		return fmt.Errorf("no pending request %x: %w", key, ErrUnsolicitedResponse)
+	}
+	if _, ok := call.asked[originID]; !ok {
+		return fmt.Errorf("request %x was not sent to %x: %w", key, originID, ErrUnsolicitedResponse)
+	}
+	if c.config.MaxResponseSize > 0 && size > c.config.MaxResponseSize {
+		call.excluded[originID] = struct{}{}
+		return fmt.Errorf("response of %x to request %x has %d bytes, maximum is %d: %w",
+			originID, key, size, c.config.MaxResponseSize, ErrResponseTooLarge)
+	}
+
+	call.resp = resp
+	call.originID = originID
+	c.finishLocked(call, nil)
+	return nil
+}
+
+// Pending returns whether there is a pending call for the request identified by the given key.
+func (c *Client[Req, Resp]) Pending(key flow.Identifier) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.calls[key]
+	return ok
+}
+
+// run retries the request of the call with alternative targets each time an attempt times out, until the call
+// completes, its attempts are exhausted, or the context is done.
+func (c *Client[Req, Resp]) run(ctx context.Context, call *Call[Resp], req Req) {
+	lg := c.log.With().Hex("key", logging.ID(call.key)).Logger()
+
+	timer := time.NewTimer(c.config.Timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-call.done:
+			return
+		case <-ctx.Done():
+			c.finish(call, ctx.Err())
+			return
+		case <-call.reset:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(c.config.Timeout)
+			continue
+		case <-timer.C:
+		}
+
+		c.mu.Lock()
+		if call.finished {
+			c.mu.Unlock()
+			return
+		}
+		if call.attempts >= c.config.MaxAttempts {
+			c.finishLocked(call, fmt.Errorf("request %x timed out after %d attempts: %w", call.key, call.attempts, ErrNoResponse))
+			c.mu.Unlock()
+			return
+		}
+		call.attempts++
+		attempt := call.attempts
+		next := call.nextTargets(c.config.Fanout)
+		c.mu.Unlock()
+
+		if len(next) == 0 {
+			c.finish(call, fmt.Errorf("could not retry request %x: %w", call.key, ErrNoTargets))
+			return
+		}
+
+		lg.Debug().
+			Uint("attempt", attempt).
+			Strs("target_ids", next.Strings()).
+			Msg("retrying request with alternative targets")
+		err := c.send(req, next)
+		if err != nil {
+			// the attempt still times out, after which the request is retried with the next targets
+			lg.Warn().Err(err).Uint("attempt", attempt).Msg("could not retry request")
+		}
+		timer.Reset(c.config.Timeout)
+	}
+}
+
+// send publishes the request to the given targets if the client is configured to publish requests, or unicasts it
+// to each of them otherwise.
+// Returns an error only if the request could not be sent to any of the targets.
+func (c *Client[Req, Resp]) send(req Req, targetIDs flow.IdentifierList) error {
+	if c.config.Publish {
+		err := c.con.Publish(req, targetIDs...)
+		if err != nil {
+			return fmt.Errorf("could not publish request: %w", err)
+		}
+		return nil
+	}
+
+	var errs *multierror.Error
+	for _, targetID := range targetIDs {
+		err := c.con.Unicast(req, targetID)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("could not unicast request to %x: %w", targetID, err))
+		}
+	}
+	if errs != nil && len(errs.Errors) == len(targetIDs) {
+		return errs.ErrorOrNil()
+	}
+	if errs != nil {
+		c.log.Debug().Err(errs).Msg("could not send request to some of the targets")
+	}
+	return nil
+}
+
+// finish completes the call with the given error, unless it has already completed.
+func (c *Client[Req, Resp]) finish(call *Call[Resp], err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.finishLocked(call, err)
+}
+
+// finishLocked completes the call with the given error, unless it has already completed.
+// The lock of the client must be held.
+func (c *Client[Req, Resp]) finishLocked(call *Call[Resp], err error) {
+	if call.finished {
+		return
+	}
+	call.finished = true
+	call.err = err
+	if c.calls[call.key] == call {
+		delete(c.calls, call.key)
+	}
+	close(call.done)
+}
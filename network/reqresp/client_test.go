@@ -0,0 +1,253 @@
+package reqresp_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/network/reqresp"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// recordingConduit returns a conduit recording the targets each request is unicast to, in order.
+func recordingConduit(t *testing.T) (*mocknetwork.Conduit, func() flow.IdentifierList) {
+	var mu sync.Mutex
+	var sent flow.IdentifierList
+	con := mocknetwork.NewConduit(t)
+	con.On("Unicast", testifymock.Anything, testifymock.Anything).Run(func(args testifymock.Arguments) {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, args.Get(1).(flow.Identifier))
+	}).Return(nil).Maybe()
+
+	return con, func() flow.IdentifierList {
+		mu.Lock()
+		defer mu.Unlock()
+		return sent.Copy()
+	}
+}
+
+func newClient(t *testing.T, con *mocknetwork.Conduit, config reqresp.Config[string]) *reqresp.Client[string, string] {
+	client, err := reqresp.NewClient[string, string](unittest.Logger(), con, config)
+	require.NoError(t, err)
+	return client
+}
+
+// TestClient_Response tests that a response from a target of the request completes the call, and that responses
+// from other nodes or arriving after the call has completed are rejected as unsolicited.
+func TestClient_Response(t *testing.T) {
+	con, sent := recordingConduit(t)
+	client := newClient(t, con, reqresp.Config[string]{Timeout: time.Minute, MaxAttempts: 1, Fanout: 2})
+
+	key := unittest.IdentifierFixture()
+	targets := unittest.IdentifierListFixture(3)
+	call, joined, err := client.Request(context.Background(), key, "request", targets)
+	require.NoError(t, err)
+	require.False(t, joined)
+	require.Equal(t, targets[:2], sent())
+	require.True(t, client.Pending(key))
+
+	// the third target was not asked
+	err = client.Deliver(targets[2], key, "response")
+	require.ErrorIs(t, err, reqresp.ErrUnsolicitedResponse)
+
+	require.NoError(t, client.Deliver(targets[1], key, "response"))
+	unittest.RequireCloseBefore(t, call.Done(), time.Second, "call should be done")
+	resp, originID, err := call.Result()
+	require.NoError(t, err)
+	require.Equal(t, "response", resp)
+	require.Equal(t, targets[1], originID)
+	require.False(t, client.Pending(key))
+
+	// late responses are unsolicited
+	err = client.Deliver(targets[0], key, "response")
+	require.ErrorIs(t, err, reqresp.ErrUnsolicitedResponse)
+}
+
+// TestClient_Retry tests that a request which is not responded to is retried with alternative targets, until its
+// attempts are exhausted.
+func TestClient_Retry(t *testing.T) {
+	con, sent := recordingConduit(t)
+	client := newClient(t, con, reqresp.Config[string]{Timeout: 50 * time.Millisecond, MaxAttempts: 3, Fanout: 1})
+
+	key := unittest.IdentifierFixture()
+	targets := unittest.IdentifierListFixture(2)
+	call, _, err := client.Request(context.Background(), key, "request", targets)
+	require.NoError(t, err)
+
+	unittest.RequireCloseBefore(t, call.Done(), time.Second, "call should time out")
+	_, _, err = call.Result()
+	require.ErrorIs(t, err, reqresp.ErrNoResponse)
+	require.False(t, client.Pending(key))
+	// the targets are asked in turn, starting over once all of them have been asked
+	require.Equal(t, flow.IdentifierList{targets[0], targets[1], targets[0]}, sent())
+}
+
+// TestClient_Multiplexing tests that a request for a key with a pending call joins the call and is sent to the next
+// targets, and that a single response completes the call.
+func TestClient_Multiplexing(t *testing.T) {
+	con, sent := recordingConduit(t)
+	client := newClient(t, con, reqresp.Config[string]{Timeout: time.Minute, MaxAttempts: 1, Fanout: 1})
+
+	key := unittest.IdentifierFixture()
+	targets := unittest.IdentifierListFixture(2)
+	call, joined, err := client.Request(context.Background(), key, "request", targets[:1])
+	require.NoError(t, err)
+	require.False(t, joined)
+
+	joinedCall, joined, err := client.Request(context.Background(), key, "request", targets)
+	require.NoError(t, err)
+	require.True(t, joined)
+	require.Same(t, call, joinedCall)
+	require.Equal(t, targets, sent())
+
+	// requests for other keys are independent
+	otherCall, joined, err := client.Request(context.Background(), unittest.IdentifierFixture(), "request", targets)
+	require.NoError(t, err)
+	require.False(t, joined)
+	require.NotSame(t, call, otherCall)
+
+	require.NoError(t, client.Deliver(targets[1], key, "response"))
+	unittest.RequireCloseBefore(t, call.Done(), time.Second, "call should be done")
+	resp, originID, err := call.Result()
+	require.NoError(t, err)
+	require.Equal(t, "response", resp)
+	require.Equal(t, targets[1], originID)
+	require.False(t, isDone(otherCall))
+}
+
+// TestClient_Cancellation tests that a call fails once its context is done.
+func TestClient_Cancellation(t *testing.T) {
+	con, _ := recordingConduit(t)
+	client := newClient(t, con, reqresp.Config[string]{Timeout: time.Minute, MaxAttempts: 1, Fanout: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	key := unittest.IdentifierFixture()
+	call, _, err := client.Request(ctx, key, "request", unittest.IdentifierListFixture(1))
+	require.NoError(t, err)
+
+	cancel()
+	unittest.RequireCloseBefore(t, call.Done(), time.Second, "call should be cancelled")
+	_, _, err = call.Result()
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, client.Pending(key))
+}
+
+// TestClient_ResponseSize tests that responses exceeding the maximum response size are rejected, and that their
+// origin is not asked again.
+func TestClient_ResponseSize(t *testing.T) {
+	con, sent := recordingConduit(t)
+	client := newClient(t, con, reqresp.Config[string]{
+		Timeout:         50 * time.Millisecond,
+		MaxAttempts:     100,
+		Fanout:          1,
+		MaxResponseSize: 8,
+		ResponseSize:    func(resp string) int { return len(resp) },
+	})
+
+	key := unittest.IdentifierFixture()
+	targets := unittest.IdentifierListFixture(2)
+	call, _, err := client.Request(context.Background(), key, "request", targets)
+	require.NoError(t, err)
+
+	err = client.Deliver(targets[0], key, "oversized response")
+	require.ErrorIs(t, err, reqresp.ErrResponseTooLarge)
+	require.False(t, isDone(call))
+
+	// the request is retried with the second target only
+	require.Eventually(t, func() bool { return len(sent()) >= 3 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, flow.IdentifierList{targets[0], targets[1], targets[1]}, sent()[:3])
+
+	require.NoError(t, client.Deliver(targets[1], key, "response"))
+	unittest.RequireCloseBefore(t, call.Done(), time.Second, "call should be done")
+	resp, _, err := call.Result()
+	require.NoError(t, err)
+	require.Equal(t, "response", resp)
+}
+
+// TestClient_SendFailure tests that a request which cannot be sent to any target fails without leaving a pending
+// call, while a request which can be sent to some targets succeeds.
+func TestClient_SendFailure(t *testing.T) {
+	targets := unittest.IdentifierListFixture(2)
+	con := mocknetwork.NewConduit(t)
+	con.On("Unicast", testifymock.Anything, targets[0]).Return(fmt.Errorf("unreachable"))
+	con.On("Unicast", testifymock.Anything, targets[1]).Return(nil)
+	client := newClient(t, con, reqresp.Config[string]{Timeout: time.Minute, MaxAttempts: 1, Fanout: 2})
+
+	key := unittest.IdentifierFixture()
+	_, _, err := client.Request(context.Background(), key, "request", targets[:1])
+	require.Error(t, err)
+	require.False(t, client.Pending(key))
+
+	_, _, err = client.Request(context.Background(), key, "request", targets)
+	require.NoError(t, err)
+	require.True(t, client.Pending(key))
+
+	_, _, err = client.Request(context.Background(), unittest.IdentifierFixture(), "request", flow.IdentifierList{})
+	require.ErrorIs(t, err, reqresp.ErrNoTargets)
+}
+
+// TestClient_Publish tests that a client configured to publish requests sends each attempt with a single publish to
+// all its targets, and accepts the responses of these targets.
+func TestClient_Publish(t *testing.T) {
+	targets := unittest.IdentifierListFixture(3)
+	retried := make(chan struct{})
+	con := mocknetwork.NewConduit(t)
+	con.On("Publish", "request", targets[0], targets[1]).Return(nil).Once()
+	con.On("Publish", "request", targets[2], targets[0]).Run(func(testifymock.Arguments) {
+		close(retried)
+	}).Return(nil).Once()
+	client := newClient(t, con, reqresp.Config[string]{Timeout: 50 * time.Millisecond, MaxAttempts: 2, Fanout: 2, Publish: true})
+
+	key := unittest.IdentifierFixture()
+	call, _, err := client.Request(context.Background(), key, "request", targets)
+	require.NoError(t, err)
+	// the retry is published to the target which has not been asked yet, and to the first one of the next round
+	unittest.RequireCloseBefore(t, retried, time.Second, "request should be retried")
+
+	require.NoError(t, client.Deliver(targets[2], key, "response"))
+	unittest.RequireCloseBefore(t, call.Done(), time.Second, "call should be done")
+	resp, originID, err := call.Result()
+	require.NoError(t, err)
+	require.Equal(t, "response", resp)
+	require.Equal(t, targets[2], originID)
+}
+
+// TestConfig_Validate tests the validation of the configuration of the client.
+func TestConfig_Validate(t *testing.T) {
+	valid := reqresp.Config[string]{Timeout: time.Second, MaxAttempts: 1, Fanout: 1}
+	require.NoError(t, valid.Validate())
+
+	for name, modify := range map[string]func(*reqresp.Config[string]){
+		"zero timeout":      func(c *reqresp.Config[string]) { c.Timeout = 0 },
+		"zero attempts":     func(c *reqresp.Config[string]) { c.MaxAttempts = 0 },
+		"zero fanout":       func(c *reqresp.Config[string]) { c.Fanout = 0 },
+		"negative max size": func(c *reqresp.Config[string]) { c.MaxResponseSize = -1 },
+		"missing size func": func(c *reqresp.Config[string]) { c.MaxResponseSize = 1 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := valid
+			modify(&config)
+			err := config.Validate()
+			require.Error(t, err)
+			_, err = reqresp.NewClient[string, string](unittest.Logger(), mocknetwork.NewConduit(t), config)
+			require.Error(t, err)
+		})
+	}
+}
+
+func isDone(call *reqresp.Call[string]) bool {
+	select {
+	case <-call.Done():
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,19 @@
+package reqresp
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNoTargets is returned when a request has no target to be sent to.
+	ErrNoTargets = errors.New("no targets to send the request to")
+
+	// ErrNoResponse is returned by a call for which no response was received within all its attempts.
+	ErrNoResponse = errors.New("no response received within the allowed attempts")
+
+	// ErrUnsolicitedResponse is returned when delivering a response which no pending call awaits from its origin.
+	ErrUnsolicitedResponse = errors.New("unsolicited response")
+
+	// ErrResponseTooLarge is returned when delivering a response exceeding the maximum response size.
+	ErrResponseTooLarge = errors.New("response exceeds the maximum response size")
+)
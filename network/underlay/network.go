@@ -23,8 +23,11 @@ import (
 	"github.com/onflow/flow-go/module/component"
 	"github.com/onflow/flow-go/module/irrecoverable"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
 	alspmgr "github.com/onflow/flow-go/network/alsp/manager"
+	"github.com/onflow/flow-go/network/bandwidth"
 	netcache "github.com/onflow/flow-go/network/cache"
+	"github.com/onflow/flow-go/network/capture"
 	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/network/codec"
 	"github.com/onflow/flow-go/network/internal/p2putils"
@@ -65,6 +68,14 @@ const (
 
 	// LargeMsgUnicastTimeout is the maximum time to wait for a unicast request to complete for large message size
 	LargeMsgUnicastTimeout = 1000 * time.Second
+
+	// droppedMessagesReportInterval is the interval at which the messages dropped because their sender exceeded its
+	// inbound queue cap are logged and reported to ALSP, once per sender. The reported penalty is not amplified by
+	// the number of dropped messages: messages may also be dropped because the node itself is slow, in which case
+	// honest senders must not be disallow-listed. A single report with the default penalty per interval is below
+	// the initial decay of the penalty per second, hence dropped messages alone cannot cause a sender to be
+	// disallow-listed, while they add to the penalty of senders misbehaving otherwise.
+	droppedMessagesReportInterval = time.Second
 )
 
 var (
@@ -113,6 +124,10 @@ type Network struct {
 	validators                  []network.MessageValidator
 	authorizedSenderValidator   *validator.AuthorizedSenderValidator
 	preferredUnicasts           []protocols.ProtocolName
+	capturer                    *capture.Capturer      // nil if message capture is not supported
+	bandwidthAccountant         *bandwidth.Accountant  // nil if bandwidth is not accounted
+	inboundQueuePolicy          *queue.Policy          // fair queuing policy of the inbound message queue
+	droppedMessages             *queue.DroppedMessages // messages dropped because their sender exceeded its inbound queue cap
 }
 
 var _ network.EngineRegistry = &Network{}
@@ -255,6 +270,30 @@ func WithMessageValidators(validators ...network.MessageValidator) NetworkOption
 	}
 }
 
+// WithMessageCapturer sets the capturer that records the inbound and outbound messages of the
+// network while a capture session is enabled. By default, messages cannot be captured.
+func WithMessageCapturer(capturer *capture.Capturer) NetworkOption {
+	return func(n *Network) {
+		n.capturer = capturer
+	}
+}
+
+// WithBandwidthAccountant sets the accountant that accounts the bandwidth of the messages exchanged by the
+// network per channel, and enforces the per-channel bandwidth quotas. By default, bandwidth is not accounted.
+func WithBandwidthAccountant(accountant *bandwidth.Accountant) NetworkOption {
+	return func(n *Network) {
+		n.bandwidthAccountant = accountant
+	}
+}
+
+// WithInboundQueuePolicy sets the fair queuing policy of the inbound message queue, which may be updated at
+// runtime. By default, the network uses the default policy.
+func WithInboundQueuePolicy(policy *queue.Policy) NetworkOption {
+	return func(n *Network) {
+		n.inboundQueuePolicy = policy
+	}
+}
+
 // NewNetwork creates a new network with the given configuration.
 // Args:
 // param: network configuration
@@ -282,6 +321,8 @@ func NewNetwork(param *NetworkConfig, opts ...NetworkOption) (*Network, error) {
 		libP2PNode:                  param.Libp2pNode,
 		unicastRateLimiters:         ratelimit.NoopRateLimiters(),
 		validators:                  DefaultValidators(param.Logger.With().Str("component", "network-validators").Logger(), param.Me.NodeID()),
+		inboundQueuePolicy:          queue.NewDefaultPolicy(),
+		droppedMessages:             queue.NewDroppedMessages(),
 	}
 
 	n.subscriptionManager = subscription.NewChannelSubscriptionManager(n)
@@ -368,6 +409,7 @@ func NewNetwork(param *NetworkConfig, opts ...NetworkOption) (*Network, error) {
 	})
 
 	builder.AddWorker(n.createInboundMessageQueue)
+	builder.AddWorker(n.reportDroppedMessages)
 	builder.AddWorker(n.processRegisterEngineRequests)
 	builder.AddWorker(n.processRegisterBlobServiceRequests)
 
@@ -432,12 +474,47 @@ func (n *Network) processRegisterBlobServiceRequests(parent irrecoverable.Signal
 
 // createInboundMessageQueue creates the queue that will be used to process incoming messages.
 func (n *Network) createInboundMessageQueue(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
-	n.queue = queue.NewMessageQueue(ctx, queue.GetEventPriority, n.metrics)
+	n.queue = queue.NewMessageQueue(ctx, queue.GetEventPriority, n.metrics,
+		queue.WithFairQueuing(queue.GetEventSender, n.inboundQueuePolicy, n.identityProvider))
 	queue.CreateQueueWorkers(ctx, queue.DefaultNumWorkers, n.queue, n.queueSubmitFunc)
 
 	ready()
 }
 
+// reportDroppedMessages periodically logs the messages dropped because their sender exceeded its inbound queue cap,
+// and reports the misbehavior of each such sender to ALSP. The penalty of a report is amplified by the number of
+// messages dropped since the previous report, up to the maximum amplification.
+func (n *Network) reportDroppedMessages(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	ready()
+
+	ticker := time.NewTicker(droppedMessagesReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, drops := range n.droppedMessages.Flush() {
+			n.logger.Warn().
+				Bool(logging.KeySuspicious, true).
+				Hex("sender_id", logging.ID(drops.SenderID)).
+				Str("channel", drops.Channel.String()).
+				Uint64("dropped_messages", drops.Count).
+				Msg("dropped messages of sender exceeding its inbound queue cap")
+
+			report, err := alsp.NewMisbehaviorReport(drops.SenderID, alsp.InboundQueueCapExceeded)
+			if err != nil {
+				// failing to create the misbehavior report is unlikely, and indicates a bug.
+				ctx.Throw(fmt.Errorf("failed to create misbehavior report: %w", err))
+				return
+			}
+			n.ReportMisbehaviorOnChannel(drops.Channel, report)
+		}
+	}
+}
+
 func (n *Network) handleRegisterEngineRequest(parent irrecoverable.SignalerContext, channel channels.Channel, engine network.MessageProcessor) (network.Conduit, error) {
 	if !channels.ChannelExists(channel) {
 		return nil, fmt.Errorf("unknown channel: %s, should be registered in topic map", channel)
@@ -586,6 +663,12 @@ func (n *Network) processNetworkMessage(msg network.IncomingMessageScope) error
 
 	// insert the message in the queue
 	err := n.queue.Insert(qm)
+	if queue.IsSenderQueueFullError(err) {
+		// the dropped messages are logged and reported per sender by reportDroppedMessages, since a flooding sender
+		// would otherwise cause a log entry and a misbehavior report per message
+		n.droppedMessages.Add(msg.OriginId(), msg.Channel())
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("failed to insert message in queue: %w", err)
 	}
@@ -629,6 +712,11 @@ func (n *Network) UnicastOnChannel(channel channels.Channel, payload interface{}
 		return fmt.Errorf("message size %d exceeds configured max message size %d", msg.Size(), maxMsgSize)
 	}
 
+	reservation, err := n.bandwidthAccountant.AllowOutbound(channel, msg.Size())
+	if err != nil {
+		return fmt.Errorf("could not send message to %x: %w", targetID, err)
+	}
+
 	maxTimeout := n.unicastMaxMsgDuration(msg.PayloadType())
 
 	// pass in a context with timeout to make the unicast call fail fast
@@ -639,6 +727,7 @@ func (n *Network) UnicastOnChannel(channel channels.Channel, payload interface{}
 	// connection creation is being attempted, and remove it from protected list once stream created.
 	channel, ok := channels.ChannelFromTopic(msg.Topic())
 	if !ok {
+		reservation.Cancel()
 		return fmt.Errorf("could not find channel for topic %s", msg.Topic())
 	}
 	streamProtectionTag := fmt.Sprintf("%v:%v", channel, msg.PayloadType())
@@ -661,9 +750,12 @@ func (n *Network) UnicastOnChannel(channel channels.Channel, payload interface{}
 		return nil
 	})
 	if err != nil {
+		reservation.Cancel()
 		return fmt.Errorf("failed to send message to %x: %w", targetID, err)
 	}
 
+	n.capturer.CaptureOutbound(message.ProtocolTypeUnicast, channel, msg, peerID)
+	n.bandwidthAccountant.OnOutboundSent(channel, msg.Size(), targetID)
 	n.metrics.OutboundMessageSent(msg.Size(), channel.String(), message.ProtocolTypeUnicast.String(), msg.PayloadType())
 	return nil
 }
@@ -736,13 +828,21 @@ func (n *Network) sendOnChannel(channel channels.Channel, msg interface{}, targe
 		return fmt.Errorf("failed to generate outgoing message scope %s: %w", channel, err)
 	}
 
+	reservation, err := n.bandwidthAccountant.AllowOutbound(channel, scope.Size())
+	if err != nil {
+		return fmt.Errorf("could not send message on channel %s: %w", channel, err)
+	}
+
 	// publish the message through the channel, however, the message
 	// is only restricted to targetIDs (if they subscribed to channel).
 	err = n.libP2PNode.Publish(n.ctx, scope)
 	if err != nil {
+		reservation.Cancel()
 		return fmt.Errorf("failed to send message on channel %s: %w", channel, err)
 	}
 
+	n.capturer.CaptureOutbound(message.ProtocolTypePubSub, channel, scope, "")
+	n.bandwidthAccountant.OnOutboundSent(channel, scope.Size(), targetIDs...)
 	n.metrics.OutboundMessageSent(scope.Size(), channel.String(), message.ProtocolTypePubSub.String(), scope.PayloadType())
 
 	return nil
@@ -799,6 +899,17 @@ func (n *Network) ReportMisbehaviorOnChannel(channel channels.Channel, report ne
 	n.misbehaviorReportManager.HandleMisbehaviorReport(channel, report)
 }
 
+// SpamRecords returns the administrative interface to the spam records of the ALSP module, which allows operators
+// to inspect and reset the penalties of misbehaving nodes.
+// Returns nil if the misbehavior report manager of the network does not support it.
+func (n *Network) SpamRecords() alsp.SpamRecordAdmin {
+	admin, ok := n.misbehaviorReportManager.(alsp.SpamRecordAdmin)
+	if !ok {
+		return nil
+	}
+	return admin
+}
+
 func DefaultValidators(log zerolog.Logger, flowID flow.Identifier) []network.MessageValidator {
 	return []network.MessageValidator{
 		validator.ValidateNotSender(flowID),   // validator to filter out messages sent by this node itself
@@ -1163,7 +1274,16 @@ func (n *Network) processAuthenticatedMessage(msg *message.Message, peerID peer.
 		return
 	}
 
+	// capture the message before it is decoded, so that malformed messages are captured as well
+	n.capturer.CaptureInbound(msg, protocol, peerID, originId)
+
 	channel := channels.Channel(msg.ChannelID)
+	err = n.bandwidthAccountant.OnInbound(channel, originId, msg.Size())
+	if err != nil {
+		// the message exceeds the inbound bandwidth quota of its channel, the drop is logged and tracked by the accountant
+		return
+	}
+
 	decodedMsgPayload, err := n.codec.Decode(msg.Payload)
 	switch {
 	case codec.IsErrUnknownMsgCode(err):
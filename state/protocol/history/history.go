@@ -0,0 +1,189 @@
+// Package history answers historical queries about the protocol state, such as the identity
+// table, epoch phase and key-value store contents as of a finalized height, and how they changed
+// between two finalized heights.
+package history
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	accessmodel "github.com/onflow/flow-go/model/access"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+)
+
+// StateAtHeight returns the protocol state as of the finalized block at the given height.
+// Expected errors during normal operation:
+//   - state.ErrUnknownSnapshotReference if no block has been finalized at the given height
+func StateAtHeight(state protocol.State, height uint64) (*accessmodel.ProtocolStateAtHeight, error) {
+	snapshot := state.AtHeight(height)
+	head, err := snapshot.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get block at height %d: %w", height, err)
+	}
+	kvstore, err := snapshot.ProtocolState()
+	if err != nil {
+		return nil, fmt.Errorf("could not get protocol state at height %d: %w", height, err)
+	}
+	epochState, err := snapshot.EpochProtocolState()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch protocol state at height %d: %w", height, err)
+	}
+	_, data, err := kvstore.VersionedEncode()
+	if err != nil {
+		return nil, fmt.Errorf("could not encode protocol state at height %d: %w", height, err)
+	}
+
+	result := &accessmodel.ProtocolStateAtHeight{
+		Height:  head.Height,
+		BlockID: head.ID(),
+		View:    head.View,
+		KVStore: accessmodel.ProtocolKVStore{
+			ID:                          kvstore.ID(),
+			Version:                     kvstore.GetProtocolStateVersion(),
+			EpochStateID:                kvstore.GetEpochStateID(),
+			EpochExtensionViewCount:     kvstore.GetEpochExtensionViewCount(),
+			FinalizationSafetyThreshold: kvstore.GetFinalizationSafetyThreshold(),
+			Data:                        data,
+		},
+		EpochPhase: epochState.EpochPhase(),
+		EpochState: epochState.Entry(),
+	}
+	if upgrade := kvstore.GetVersionUpgrade(); upgrade != nil {
+		result.KVStore.VersionUpgrade = &accessmodel.KVStoreVersionUpgradeSchedule{
+			NewVersion:     upgrade.Data,
+			ActivationView: upgrade.ActivationView,
+		}
+	}
+	return result, nil
+}
+
+// Diff returns the difference between the protocol states as of the finalized blocks at the
+// given heights, where startHeight must not exceed endHeight. Identity changes are determined
+// from the identity tables of the respective current epochs. As the key-value store version
+// never decreases along the finalized chain, the heights at which the version was upgraded are
+// located by binary search, rather than by inspecting every block in the range.
+// Expected errors during normal operation:
+//   - state.ErrUnknownSnapshotReference if no block has been finalized at either height
+func Diff(state protocol.State, startHeight, endHeight uint64) (*accessmodel.ProtocolStateDiff, error) {
+	if startHeight > endHeight {
+		return nil, fmt.Errorf("start height %d exceeds end height %d", startHeight, endHeight)
+	}
+	start, err := StateAtHeight(state, startHeight)
+	if err != nil {
+		return nil, err
+	}
+	end, err := StateAtHeight(state, endHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &accessmodel.ProtocolStateDiff{
+		StartHeight:                 startHeight,
+		EndHeight:                   endHeight,
+		StartEpochCounter:           start.EpochState.EpochCounter(),
+		EndEpochCounter:             end.EpochState.EpochCounter(),
+		StartEpochPhase:             start.EpochPhase,
+		EndEpochPhase:               end.EpochPhase,
+		StartEpochFallbackTriggered: start.EpochState.EpochFallbackTriggered,
+		EndEpochFallbackTriggered:   end.EpochState.EpochFallbackTriggered,
+	}
+	diff.IdentityChanges, diff.Ejections = diffIdentities(start.EpochState.CurrentEpochIdentityTable, end.EpochState.CurrentEpochIdentityTable)
+	diff.VersionUpgrades, err = findVersionUpgrades(state, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("could not find version upgrades between heights %d and %d: %w", startHeight, endHeight, err)
+	}
+	return diff, nil
+}
+
+// diffIdentities returns the changes between the given identity tables, and the nodes which
+// are ejected in the end table but were not ejected in the start table, both ordered by node ID.
+func diffIdentities(start, end flow.IdentityList) ([]accessmodel.IdentityChange, []flow.Identifier) {
+	startLookup := start.Lookup()
+	endLookup := end.Lookup()
+
+	changes := []accessmodel.IdentityChange{}
+	ejections := []flow.Identifier{}
+	for nodeID, before := range startLookup {
+		after, ok := endLookup[nodeID]
+		if !ok {
+			changes = append(changes, accessmodel.IdentityChange{NodeID: nodeID, Type: accessmodel.IdentityRemoved, Before: before})
+			continue
+		}
+		if before.Checksum() != after.Checksum() {
+			changes = append(changes, accessmodel.IdentityChange{NodeID: nodeID, Type: accessmodel.IdentityChanged, Before: before, After: after})
+		}
+		if after.IsEjected() && !before.IsEjected() {
+			ejections = append(ejections, nodeID)
+		}
+	}
+	for nodeID, after := range endLookup {
+		if _, ok := startLookup[nodeID]; ok {
+			continue
+		}
+		changes = append(changes, accessmodel.IdentityChange{NodeID: nodeID, Type: accessmodel.IdentityAdded, After: after})
+		if after.IsEjected() {
+			ejections = append(ejections, nodeID)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return bytes.Compare(changes[i].NodeID[:], changes[j].NodeID[:]) < 0
+	})
+	sort.Slice(ejections, func(i, j int) bool {
+		return bytes.Compare(ejections[i][:], ejections[j][:]) < 0
+	})
+	return changes, ejections
+}
+
+// findVersionUpgrades locates the finalized blocks between the given protocol states at which
+// the key-value store version was upgraded.
+// No errors are expected during normal operation.
+func findVersionUpgrades(state protocol.State, start, end *accessmodel.ProtocolStateAtHeight) ([]accessmodel.KVStoreVersionUpgrade, error) {
+	upgrades := []accessmodel.KVStoreVersionUpgrade{}
+	version := start.KVStore.Version
+	low := start.Height
+	for version < end.KVStore.Version {
+		// invariant: the version at height `low` is `version`, the version at end.Height is larger;
+		// find the lowest height in (low, end.Height] with a larger version
+		lo, hi := low+1, end.Height
+		for lo < hi {
+			mid := lo + (hi-lo)/2
+			midVersion, err := versionAtHeight(state, mid)
+			if err != nil {
+				return nil, err
+			}
+			if midVersion > version {
+				hi = mid
+			} else {
+				lo = mid + 1
+			}
+		}
+
+		upgraded, err := StateAtHeight(state, lo)
+		if err != nil {
+			return nil, err
+		}
+		upgrades = append(upgrades, accessmodel.KVStoreVersionUpgrade{
+			Height:      upgraded.Height,
+			BlockID:     upgraded.BlockID,
+			View:        upgraded.View,
+			FromVersion: version,
+			ToVersion:   upgraded.KVStore.Version,
+		})
+		version = upgraded.KVStore.Version
+		low = lo
+	}
+	return upgrades, nil
+}
+
+// versionAtHeight returns the key-value store version as of the finalized block at the given height.
+// No errors are expected during normal operation.
+func versionAtHeight(state protocol.State, height uint64) (uint64, error) {
+	kvstore, err := state.AtHeight(height).ProtocolState()
+	if err != nil {
+		return 0, fmt.Errorf("could not get protocol state at height %d: %w", height, err)
+	}
+	return kvstore.GetProtocolStateVersion(), nil
+}
@@ -0,0 +1,100 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	accessmodel "github.com/onflow/flow-go/model/access"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	mockprotocol "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestDiff verifies the identity changes, ejections and key-value store version upgrades between
+// two heights, where the version is upgraded from 1 to 2 at height 15 and from 2 to 3 at height 18.
+func TestDiff(t *testing.T) {
+	identities := unittest.IdentityListFixture(4)
+	startTable := identities[:3].Copy()
+	endTable := flow.IdentityList{identities[0], identities[1], identities[3]}.Copy()
+	endTable[1].EpochParticipationStatus = flow.EpochParticipationStatusEjected
+
+	startEntry := unittest.EpochStateFixture()
+	startEntry.CurrentEpochIdentityTable = startTable
+	endEntry := unittest.EpochStateFixture(unittest.WithNextEpochProtocolState())
+	endEntry.CurrentEpochIdentityTable = endTable
+
+	versions := map[uint64]uint64{}
+	for height := uint64(10); height <= 20; height++ {
+		switch {
+		case height < 15:
+			versions[height] = 1
+		case height < 18:
+			versions[height] = 2
+		default:
+			versions[height] = 3
+		}
+	}
+	headers := map[uint64]*flow.Header{}
+	for height := range versions {
+		headers[height] = unittest.BlockHeaderFixture(unittest.WithHeaderHeight(height))
+	}
+
+	state := mockprotocol.NewState(t)
+	state.On("AtHeight", mock.Anything).Return(func(height uint64) protocol.Snapshot {
+		kvstore := mockprotocol.NewKVStoreReader(t)
+		kvstore.On("ID").Return(unittest.IdentifierFixture()).Maybe()
+		kvstore.On("GetProtocolStateVersion").Return(versions[height])
+		kvstore.On("VersionedEncode").Return(versions[height], []byte{byte(height)}, nil).Maybe()
+		kvstore.On("GetEpochStateID").Return(unittest.IdentifierFixture()).Maybe()
+		kvstore.On("GetEpochExtensionViewCount").Return(uint64(100)).Maybe()
+		kvstore.On("GetFinalizationSafetyThreshold").Return(uint64(10)).Maybe()
+		kvstore.On("GetVersionUpgrade").Return(nil).Maybe()
+
+		entry := startEntry
+		if height == 20 {
+			entry = endEntry
+		}
+		epochState := mockprotocol.NewEpochProtocolState(t)
+		epochState.On("EpochPhase").Return(entry.EpochPhase()).Maybe()
+		epochState.On("Entry").Return(entry).Maybe()
+
+		snapshot := mockprotocol.NewSnapshot(t)
+		snapshot.On("Head").Return(headers[height], nil).Maybe()
+		snapshot.On("ProtocolState").Return(kvstore, nil)
+		snapshot.On("EpochProtocolState").Return(epochState, nil).Maybe()
+		return snapshot
+	})
+
+	diff, err := Diff(state, 10, 20)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), diff.StartHeight)
+	assert.Equal(t, uint64(20), diff.EndHeight)
+	assert.Equal(t, flow.EpochPhaseStaking, diff.StartEpochPhase)
+	assert.Equal(t, endEntry.EpochPhase(), diff.EndEpochPhase)
+
+	changes := map[flow.Identifier]accessmodel.IdentityChange{}
+	for _, change := range diff.IdentityChanges {
+		changes[change.NodeID] = change
+	}
+	require.Len(t, changes, 3)
+	assert.Equal(t, accessmodel.IdentityChanged, changes[identities[1].NodeID].Type)
+	assert.Equal(t, accessmodel.IdentityRemoved, changes[identities[2].NodeID].Type)
+	assert.Nil(t, changes[identities[2].NodeID].After)
+	assert.Equal(t, accessmodel.IdentityAdded, changes[identities[3].NodeID].Type)
+	assert.Nil(t, changes[identities[3].NodeID].Before)
+	assert.Equal(t, []flow.Identifier{identities[1].NodeID}, diff.Ejections)
+
+	assert.Equal(t, []accessmodel.KVStoreVersionUpgrade{
+		{Height: 15, BlockID: headers[15].ID(), View: headers[15].View, FromVersion: 1, ToVersion: 2},
+		{Height: 18, BlockID: headers[18].ID(), View: headers[18].View, FromVersion: 2, ToVersion: 3},
+	}, diff.VersionUpgrades)
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := Diff(state, 20, 10)
+		assert.Error(t, err)
+	})
+}
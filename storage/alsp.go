@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/network/alsp/model"
+)
+
+// SpamRecordSnapshots represents persistent storage for snapshots of the spam records of the
+// application layer spam prevention (ALSP) module, which allows penalties to survive restarts.
+type SpamRecordSnapshots interface {
+	// Store persists the given snapshot, replacing the previously stored snapshot.
+	// No errors are expected during normal operation.
+	Store(snapshot *model.SpamRecordSnapshot) error
+
+	// Latest returns the most recently stored snapshot.
+	// Error returns:
+	//   - [storage.ErrNotFound] if no snapshot has been stored
+	Latest() (*model.SpamRecordSnapshot, error)
+}
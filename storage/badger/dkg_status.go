@@ -0,0 +1,59 @@
+package badger
+
+import (
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/dkg"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// DKGStatuses stores the history of the local DKG instances' progress. Must be instantiated
+// using the secrets database.
+type DKGStatuses struct {
+	db *badger.DB
+}
+
+var _ storage.DKGStatuses = (*DKGStatuses)(nil)
+
+// NewDKGStatuses returns the DKGStatuses implementation backed by Badger DB.
+// No errors are expected during normal operations.
+func NewDKGStatuses(db *badger.DB) (*DKGStatuses, error) {
+	err := operation.EnsureSecretDB(db)
+	if err != nil {
+		return nil, fmt.Errorf("cannot instantiate dkg status storage in non-secret db: %w", err)
+	}
+	return &DKGStatuses{db: db}, nil
+}
+
+// Store persists the status of the DKG preparing the status' epoch, replacing any previously
+// stored status for the epoch.
+// No errors are expected during normal operation.
+func (s *DKGStatuses) Store(status *dkg.Status) error {
+	return operation.RetryOnConflict(s.db.Update, operation.UpsertDKGStatus(status))
+}
+
+// ByEpoch returns the status of the DKG preparing the given epoch.
+// Error returns:
+//   - [storage.ErrNotFound] if no status is stored for the given epoch
+func (s *DKGStatuses) ByEpoch(epochCounter uint64) (*dkg.Status, error) {
+	var status dkg.Status
+	err := s.db.View(operation.RetrieveDKGStatus(epochCounter, &status))
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// All returns the statuses of all stored DKGs, ordered by epoch counter.
+// No errors are expected during normal operation.
+func (s *DKGStatuses) All() ([]*dkg.Status, error) {
+	var statuses []*dkg.Status
+	err := s.db.View(operation.FindDKGStatuses(&statuses))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve dkg statuses: %w", err)
+	}
+	return statuses, nil
+}
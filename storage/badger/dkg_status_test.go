@@ -0,0 +1,63 @@
+package badger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/dkg"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestDKGStatuses_StoreRetrieve verifies that DKG statuses are stored per epoch and that storing
+// a status replaces the previous status of the same epoch.
+func TestDKGStatuses_StoreRetrieve(t *testing.T) {
+	unittest.RunWithTypedBadgerDB(t, InitSecret, func(db *badger.DB) {
+		store, err := NewDKGStatuses(db)
+		require.NoError(t, err)
+
+		_, err = store.ByEpoch(2)
+		require.True(t, errors.Is(err, storage.ErrNotFound))
+
+		committee := unittest.IdentityListFixture(3).Sort(flow.Canonical[flow.Identity]).ToSkeleton()
+		first := dkg.NewStatus(2, "dkg-test-2", committee, 1)
+		second := dkg.NewStatus(3, "dkg-test-3", committee, 1)
+		second.LastUpdate = time.Unix(1_700_000_000, 0)
+		require.NoError(t, store.Store(second))
+		require.NoError(t, store.Store(first))
+
+		first.Phase = "Phase1"
+		first.Dealers[0].BroadcastMessages["Phase1"] = 1
+		first.Dealers[2].PrivateMessages = 1
+		first.Complaints = append(first.Complaints, dkg.Complaint{
+			Phase:   "Phase1",
+			Accused: committee[2].NodeID,
+			Reason:  "invalid share",
+			Time:    time.Unix(1_700_000_000, 0),
+		})
+		first.LastUpdate = time.Unix(1_700_000_000, 0)
+		require.NoError(t, store.Store(first))
+
+		stored, err := store.ByEpoch(2)
+		require.NoError(t, err)
+		assert.Equal(t, first, stored)
+
+		all, err := store.All()
+		require.NoError(t, err)
+		assert.Equal(t, []*dkg.Status{first, second}, all)
+	})
+}
+
+// TestDKGStatuses_RequiresSecretDB verifies that DKG statuses can only be stored in the secrets database.
+func TestDKGStatuses_RequiresSecretDB(t *testing.T) {
+	unittest.RunWithTypedBadgerDB(t, InitPublic, func(db *badger.DB) {
+		_, err := NewDKGStatuses(db)
+		require.Error(t, err)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/dgraph-io/badger/v2"
 	"github.com/rs/zerolog"
 
+	"github.com/onflow/flow-go/model/dkg"
 	"github.com/onflow/flow-go/model/encodable"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/storage"
@@ -132,3 +133,36 @@ func MigrateDKGEndStateFromV1(log zerolog.Logger) func(txn *badger.Txn) error {
 		return nil
 	}
 }
+
+// UpsertDKGStatus stores the progress of the local DKG instance for the status' epoch, irrespective
+// of whether an entry for the epoch already exists in the database or not.
+func UpsertDKGStatus(status *dkg.Status) func(*badger.Txn) error {
+	return upsert(makePrefix(codeDKGStatus, status.EpochCounter), status)
+}
+
+// RetrieveDKGStatus retrieves the progress of the local DKG instance for the given epoch.
+// Error returns: [storage.ErrNotFound]
+func RetrieveDKGStatus(epochCounter uint64, status *dkg.Status) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeDKGStatus, epochCounter), status)
+}
+
+// FindDKGStatuses retrieves the progress of the local DKG instances for all epochs, ordered by
+// epoch counter.
+// No errors expected during normal operation.
+func FindDKGStatuses(found *[]*dkg.Status) func(*badger.Txn) error {
+	return traverse(makePrefix(codeDKGStatus), func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			return true
+		}
+		var status *dkg.Status
+		create := func() interface{} {
+			status = new(dkg.Status)
+			return status
+		}
+		handle := func() error {
+			*found = append(*found, status)
+			return nil
+		}
+		return check, create, handle
+	})
+}
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"fmt"
+
 	"github.com/onflow/flow-go/model/flow"
 )
 
@@ -24,9 +26,53 @@ type ChunkDataPacks interface {
 	BatchRemove(chunkID flow.Identifier, batch ReaderBatchWriter) error
 }
 
+// ChunkDataPackCompression identifies the compression applied to the proof of a stored
+// chunk data pack. The zero value is used by chunk data packs stored without compression,
+// including all chunk data packs stored before compression was introduced.
+//
+// Note that software versions which predate compression read compressed proofs verbatim,
+// so the chunk data pack database must be decompressed before downgrading.
+type ChunkDataPackCompression uint8
+
+const (
+	ChunkDataPackUncompressed ChunkDataPackCompression = iota
+	ChunkDataPackCompressedLz4
+	ChunkDataPackCompressedGzip
+)
+
+func (c ChunkDataPackCompression) String() string {
+	switch c {
+	case ChunkDataPackUncompressed:
+		return "none"
+	case ChunkDataPackCompressedLz4:
+		return "lz4"
+	case ChunkDataPackCompressedGzip:
+		return "gzip"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(c))
+	}
+}
+
+// ParseChunkDataPackCompression parses the name of a chunk data pack compression,
+// as returned by ChunkDataPackCompression.String.
+func ParseChunkDataPackCompression(name string) (ChunkDataPackCompression, error) {
+	for _, c := range []ChunkDataPackCompression{
+		ChunkDataPackUncompressed,
+		ChunkDataPackCompressedLz4,
+		ChunkDataPackCompressedGzip,
+	} {
+		if c.String() == name {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown chunk data pack compression: %s", name)
+}
+
 // StoredChunkDataPack is an in-storage representation of chunk data pack.
 // Its prime difference is instead of an actual collection, it keeps a collection ID hence relying on maintaining
-// the collection on a secondary storage.
+// the collection on a secondary storage. Since collections are content-addressed, a collection included in
+// several chunk data packs (e.g. of different forks) is stored only once.
+// The proof may be stored compressed, as indicated by Compression.
 type StoredChunkDataPack struct {
 	ChunkID           flow.Identifier
 	StartState        flow.StateCommitment
@@ -34,6 +80,7 @@ type StoredChunkDataPack struct {
 	CollectionID      flow.Identifier
 	SystemChunk       bool
 	ExecutionDataRoot flow.BlockExecutionDataRoot
+	Compression       ChunkDataPackCompression
 }
 
 func ToStoredChunkDataPack(c *flow.ChunkDataPack) *StoredChunkDataPack {
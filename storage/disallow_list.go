@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/network/disallowlist/model"
+)
+
+// DisallowListEntries represents persistent storage for the operator-managed disallow list of the
+// networking layer, which allows the list to survive restarts.
+type DisallowListEntries interface {
+	// Store persists the given entries, replacing the previously stored entries.
+	// No errors are expected during normal operation.
+	Store(entries []model.Entry) error
+
+	// All returns all stored entries, or an empty list if no entries have been stored.
+	// No errors are expected during normal operation.
+	All() ([]model.Entry, error)
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"github.com/onflow/crypto"
 
+	"github.com/onflow/flow-go/model/dkg"
 	"github.com/onflow/flow-go/model/flow"
 )
 
@@ -101,3 +102,22 @@ type EpochRecoveryMyBeaconKey interface {
 	// No errors are expected during normal operations.
 	UpsertMyBeaconPrivateKey(epochCounter uint64, key crypto.PrivateKey, commit *flow.EpochCommit) error
 }
+
+// DKGStatuses stores the history of the local DKG instances' progress, per epoch. Must be
+// instantiated using the secrets database, alongside the DKGState.
+type DKGStatuses interface {
+
+	// Store persists the status of the DKG preparing the status' epoch, replacing any previously
+	// stored status for the epoch.
+	// No errors are expected during normal operation.
+	Store(status *dkg.Status) error
+
+	// ByEpoch returns the status of the DKG preparing the given epoch.
+	// Error returns:
+	//   - [storage.ErrNotFound] if no status is stored for the given epoch
+	ByEpoch(epochCounter uint64) (*dkg.Status, error)
+
+	// All returns the statuses of all stored DKGs, ordered by epoch counter.
+	// No errors are expected during normal operation.
+	All() ([]*dkg.Status, error)
+}
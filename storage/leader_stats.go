@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+)
+
+// LeaderStats represents persistent storage for the per-leader view outcome statistics
+// collected by the local consensus participant.
+type LeaderStats interface {
+	// Store persists the given statistics, overwriting previously stored statistics for the
+	// same epoch and node.
+	// No errors are expected during normal operation.
+	Store(stats []*model.LeaderStats) error
+
+	// ByEpoch returns the statistics of all leaders observed in the given epoch, ordered by
+	// node ID. Returns an empty list if no statistics are stored for the epoch.
+	// No errors are expected during normal operation.
+	ByEpoch(epoch uint64) ([]*model.LeaderStats, error)
+
+	// All returns the statistics of all epochs, ordered by epoch and node ID.
+	// No errors are expected during normal operation.
+	All() ([]*model.LeaderStats, error)
+}
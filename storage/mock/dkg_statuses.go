@@ -0,0 +1,105 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mock
+
+import (
+	dkg "github.com/onflow/flow-go/model/dkg"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DKGStatuses is an autogenerated mock type for the DKGStatuses type
+type DKGStatuses struct {
+	mock.Mock
+}
+
+// All provides a mock function with given fields:
+func (_m *DKGStatuses) All() ([]*dkg.Status, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for All")
+	}
+
+	var r0 []*dkg.Status
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*dkg.Status, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*dkg.Status); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dkg.Status)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ByEpoch provides a mock function with given fields: epochCounter
+func (_m *DKGStatuses) ByEpoch(epochCounter uint64) (*dkg.Status, error) {
+	ret := _m.Called(epochCounter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ByEpoch")
+	}
+
+	var r0 *dkg.Status
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint64) (*dkg.Status, error)); ok {
+		return rf(epochCounter)
+	}
+	if rf, ok := ret.Get(0).(func(uint64) *dkg.Status); ok {
+		r0 = rf(epochCounter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dkg.Status)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint64) error); ok {
+		r1 = rf(epochCounter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store provides a mock function with given fields: status
+func (_m *DKGStatuses) Store(status *dkg.Status) error {
+	ret := _m.Called(status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Store")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dkg.Status) error); ok {
+		r0 = rf(status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDKGStatuses creates a new instance of DKGStatuses. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDKGStatuses(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DKGStatuses {
+	mock := &DKGStatuses{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,76 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mock
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/onflow/flow-go/network/alsp/model"
+)
+
+// SpamRecordSnapshots is an autogenerated mock type for the SpamRecordSnapshots type
+type SpamRecordSnapshots struct {
+	mock.Mock
+}
+
+// Latest provides a mock function with given fields:
+func (_m *SpamRecordSnapshots) Latest() (*model.SpamRecordSnapshot, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Latest")
+	}
+
+	var r0 *model.SpamRecordSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*model.SpamRecordSnapshot, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *model.SpamRecordSnapshot); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SpamRecordSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store provides a mock function with given fields: snapshot
+func (_m *SpamRecordSnapshots) Store(snapshot *model.SpamRecordSnapshot) error {
+	ret := _m.Called(snapshot)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Store")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.SpamRecordSnapshot) error); ok {
+		r0 = rf(snapshot)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSpamRecordSnapshots creates a new instance of SpamRecordSnapshots. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSpamRecordSnapshots(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SpamRecordSnapshots {
+	mock := &SpamRecordSnapshots{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
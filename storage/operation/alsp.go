@@ -0,0 +1,20 @@
+package operation
+
+import (
+	"github.com/onflow/flow-go/network/alsp/model"
+	"github.com/onflow/flow-go/storage"
+)
+
+// UpsertSpamRecordSnapshot stores the snapshot of the ALSP spam records, replacing the
+// previously stored snapshot.
+// No errors are expected during normal operation.
+func UpsertSpamRecordSnapshot(w storage.Writer, snapshot *model.SpamRecordSnapshot) error {
+	return UpsertByKey(w, MakePrefix(codeAlspSpamRecordSnapshot), snapshot)
+}
+
+// RetrieveSpamRecordSnapshot retrieves the most recently stored snapshot of the ALSP spam records.
+// Error returns:
+//   - [storage.ErrNotFound] if no snapshot has been stored
+func RetrieveSpamRecordSnapshot(r storage.Reader, snapshot *model.SpamRecordSnapshot) error {
+	return RetrieveByKey(r, MakePrefix(codeAlspSpamRecordSnapshot), snapshot)
+}
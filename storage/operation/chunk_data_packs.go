@@ -22,3 +22,25 @@ func RetrieveChunkDataPack(r storage.Reader, chunkID flow.Identifier, c *storage
 func RemoveChunkDataPack(w storage.Writer, chunkID flow.Identifier) error {
 	return RemoveByKey(w, MakePrefix(codeChunkDataPack, chunkID))
 }
+
+// TraverseChunkDataPacks calls the given function for every stored chunk data pack, in
+// order of chunk ID. Error returned by the function stops the traversal and is propagated
+// to the caller.
+// No other errors are expected during normal operation.
+func TraverseChunkDataPacks(r storage.Reader, fn func(c *storage.StoredChunkDataPack) error) error {
+	iterationFunc := func() (CheckFunc, CreateFunc, HandleFunc) {
+		var c storage.StoredChunkDataPack
+		check := func(key []byte) (bool, error) {
+			return true, nil
+		}
+		create := func() interface{} {
+			return &c
+		}
+		handle := func() error {
+			return fn(&c)
+		}
+		return check, create, handle
+	}
+
+	return TraverseByPrefix(r, MakePrefix(codeChunkDataPack), iterationFunc, storage.DefaultIteratorOptions())
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/operation"
 	"github.com/onflow/flow-go/storage/operation/dbtest"
@@ -53,3 +54,35 @@ func TestChunkDataPack(t *testing.T) {
 		})
 	})
 }
+
+func TestTraverseChunkDataPacks(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		expected := make(map[flow.Identifier]storage.StoredChunkDataPack)
+		err := db.WithReaderBatchWriter(func(rw storage.ReaderBatchWriter) error {
+			for i := 0; i < 10; i++ {
+				c := storage.StoredChunkDataPack{
+					ChunkID:      unittest.IdentifierFixture(),
+					StartState:   unittest.StateCommitmentFixture(),
+					Proof:        []byte{'p', byte(i)},
+					CollectionID: unittest.IdentifierFixture(),
+					Compression:  storage.ChunkDataPackCompressedLz4,
+				}
+				expected[c.ChunkID] = c
+				err := operation.InsertChunkDataPack(rw.Writer(), &c)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+
+		actual := make(map[flow.Identifier]storage.StoredChunkDataPack)
+		err = operation.TraverseChunkDataPacks(db.Reader(), func(c *storage.StoredChunkDataPack) error {
+			actual[c.ChunkID] = *c
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+}
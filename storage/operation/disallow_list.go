@@ -0,0 +1,20 @@
+package operation
+
+import (
+	"github.com/onflow/flow-go/network/disallowlist/model"
+	"github.com/onflow/flow-go/storage"
+)
+
+// UpsertDisallowListEntries stores the entries of the operator-managed disallow list, replacing
+// the previously stored entries.
+// No errors are expected during normal operation.
+func UpsertDisallowListEntries(w storage.Writer, entries []model.Entry) error {
+	return UpsertByKey(w, MakePrefix(codeDisallowListEntries), entries)
+}
+
+// RetrieveDisallowListEntries retrieves the entries of the operator-managed disallow list.
+// Error returns:
+//   - [storage.ErrNotFound] if no entries have been stored
+func RetrieveDisallowListEntries(r storage.Reader, entries *[]model.Entry) error {
+	return RetrieveByKey(r, MakePrefix(codeDisallowListEntries), entries)
+}
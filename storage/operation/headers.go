@@ -0,0 +1,45 @@
+package operation
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// InsertHeader inserts a block header by block ID.
+// The same key (`header.ID()`) necessitates that the value (full `header`) is also
+// identical. Therefore, concurrent calls to this function are safe.
+func InsertHeader(w storage.Writer, headerID flow.Identifier, header *flow.Header) error {
+	return UpsertByKey(w, MakePrefix(codeHeader, headerID), header)
+}
+
+// RetrieveHeader retrieves a block header by block ID.
+// Expected errors during normal operations:
+//   - storage.ErrNotFound if no header with the given ID is stored
+func RetrieveHeader(r storage.Reader, blockID flow.Identifier, header *flow.Header) error {
+	return RetrieveByKey(r, MakePrefix(codeHeader, blockID), header)
+}
+
+// IndexFinalizedBlockByHeight indexes the ID of a finalized block by its height.
+// It must only be called for finalized blocks.
+func IndexFinalizedBlockByHeight(w storage.Writer, height uint64, blockID flow.Identifier) error {
+	return UpsertByKey(w, MakePrefix(codeHeightToBlock, height), blockID)
+}
+
+// LookupBlockHeight retrieves the ID of the finalized block with the given height.
+// Expected errors during normal operations:
+//   - storage.ErrNotFound if no finalized block with the given height is indexed
+func LookupBlockHeight(r storage.Reader, height uint64, blockID *flow.Identifier) error {
+	return RetrieveByKey(r, MakePrefix(codeHeightToBlock, height), blockID)
+}
+
+// UpsertFinalizedHeight stores the height of the latest finalized block.
+func UpsertFinalizedHeight(w storage.Writer, height uint64) error {
+	return UpsertByKey(w, MakePrefix(codeFinalizedHeight), height)
+}
+
+// RetrieveFinalizedHeight retrieves the height of the latest finalized block.
+// Expected errors during normal operations:
+//   - storage.ErrNotFound if no finalized height is stored
+func RetrieveFinalizedHeight(r storage.Reader, height *uint64) error {
+	return RetrieveByKey(r, MakePrefix(codeFinalizedHeight), height)
+}
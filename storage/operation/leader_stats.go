@@ -0,0 +1,48 @@
+package operation
+
+import (
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/storage"
+)
+
+// UpsertLeaderStats stores the view outcome statistics of a leader, keyed by epoch and
+// node ID.
+// No errors are expected during normal operation.
+func UpsertLeaderStats(w storage.Writer, stats *model.LeaderStats) error {
+	return UpsertByKey(w, MakePrefix(codeLeaderStats, stats.Epoch, stats.NodeID), stats)
+}
+
+// TraverseLeaderStats calls the given function for the stored statistics of all leaders in
+// the given epoch, in order of node ID. Error returned by the function stops the traversal
+// and is propagated to the caller.
+// No other errors are expected during normal operation.
+func TraverseLeaderStats(r storage.Reader, epoch uint64, fn func(stats *model.LeaderStats) error) error {
+	return traverseLeaderStats(r, MakePrefix(codeLeaderStats, epoch), fn)
+}
+
+// TraverseAllLeaderStats calls the given function for all stored leader statistics, in order
+// of epoch and node ID. Error returned by the function stops the traversal and is propagated
+// to the caller.
+// No other errors are expected during normal operation.
+func TraverseAllLeaderStats(r storage.Reader, fn func(stats *model.LeaderStats) error) error {
+	return traverseLeaderStats(r, MakePrefix(codeLeaderStats), fn)
+}
+
+func traverseLeaderStats(r storage.Reader, prefix []byte, fn func(stats *model.LeaderStats) error) error {
+	iterationFunc := func() (CheckFunc, CreateFunc, HandleFunc) {
+		var stats *model.LeaderStats
+		check := func(key []byte) (bool, error) {
+			return true, nil
+		}
+		create := func() interface{} {
+			stats = new(model.LeaderStats)
+			return stats
+		}
+		handle := func() error {
+			return fn(stats)
+		}
+		return check, create, handle
+	}
+
+	return TraverseByPrefix(r, prefix, iterationFunc, storage.DefaultIteratorOptions())
+}
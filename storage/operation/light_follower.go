@@ -0,0 +1,49 @@
+package operation
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// UpsertLightEpochSetup stores an EpochSetup event followed by the light follower, keyed by
+// the epoch counter.
+func UpsertLightEpochSetup(w storage.Writer, setup *flow.EpochSetup) error {
+	return UpsertByKey(w, MakePrefix(codeLightEpochSetup, setup.Counter), setup)
+}
+
+// UpsertLightEpochCommit stores an EpochCommit event followed by the light follower, keyed by
+// the epoch counter.
+func UpsertLightEpochCommit(w storage.Writer, commit *flow.EpochCommit) error {
+	return UpsertByKey(w, MakePrefix(codeLightEpochCommit, commit.Counter), commit)
+}
+
+// RetrieveLightEpochCommit retrieves the EpochCommit event of the given epoch followed by the
+// light follower.
+// Expected errors during normal operations:
+//   - storage.ErrNotFound if no EpochCommit event is stored for the epoch
+func RetrieveLightEpochCommit(r storage.Reader, counter uint64, commit *flow.EpochCommit) error {
+	return RetrieveByKey(r, MakePrefix(codeLightEpochCommit, counter), commit)
+}
+
+// TraverseLightEpochSetups calls the given function for all EpochSetup events followed by the
+// light follower, in order of epoch counter. Error returned by the function stops the traversal
+// and is propagated to the caller.
+// No other errors are expected during normal operation.
+func TraverseLightEpochSetups(r storage.Reader, fn func(setup *flow.EpochSetup) error) error {
+	iterationFunc := func() (CheckFunc, CreateFunc, HandleFunc) {
+		var setup *flow.EpochSetup
+		check := func(key []byte) (bool, error) {
+			return true, nil
+		}
+		create := func() interface{} {
+			setup = new(flow.EpochSetup)
+			return setup
+		}
+		handle := func() error {
+			return fn(setup)
+		}
+		return check, create, handle
+	}
+
+	return TraverseByPrefix(r, MakePrefix(codeLightEpochSetup), iterationFunc, storage.DefaultIteratorOptions())
+}
@@ -89,6 +89,20 @@ const (
 	codeJobQueue             = 71
 	codeJobQueuePointer      = 72
 
+	// evidence of protocol violations
+	codeSlashingEvidence = 80 // evidence of slashable HotStuff violations, keyed by evidence ID
+
+	// consensus diagnostics
+	codeLeaderStats = 85 // per-leader view outcome statistics, keyed by epoch and node ID
+
+	// light follower
+	codeLightEpochSetup  = 90 // EpochSetup service events followed by the light follower, keyed by epoch counter
+	codeLightEpochCommit = 91 // EpochCommit service events followed by the light follower, keyed by epoch counter
+
+	// networking
+	codeAlspSpamRecordSnapshot = 95 // snapshot of the spam records of the application layer spam prevention module
+	codeDisallowListEntries    = 96 // entries of the operator-managed disallow list
+
 	// legacy codes (should be cleaned up)
 	codeChunkDataPack                      = 100
 	codeCommit                             = 101
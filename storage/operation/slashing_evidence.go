@@ -0,0 +1,50 @@
+package operation
+
+import (
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// UpsertSlashingEvidence stores the evidence of a slashable protocol violation, keyed by
+// the evidence ID.
+// No errors are expected during normal operation.
+func UpsertSlashingEvidence(w storage.Writer, evidenceID flow.Identifier, evidence *model.SlashingEvidence) error {
+	return UpsertByKey(w, MakePrefix(codeSlashingEvidence, evidenceID), evidence)
+}
+
+// RetrieveSlashingEvidence retrieves the evidence with the given ID.
+// Expected errors during normal operations:
+//   - storage.ErrNotFound if no evidence with the given ID is stored
+func RetrieveSlashingEvidence(r storage.Reader, evidenceID flow.Identifier, evidence *model.SlashingEvidence) error {
+	return RetrieveByKey(r, MakePrefix(codeSlashingEvidence, evidenceID), evidence)
+}
+
+// ExistsSlashingEvidence returns true if evidence with the given ID is stored.
+// No errors are expected during normal operation.
+func ExistsSlashingEvidence(r storage.Reader, evidenceID flow.Identifier) (bool, error) {
+	return KeyExists(r, MakePrefix(codeSlashingEvidence, evidenceID))
+}
+
+// TraverseSlashingEvidence calls the given function for all stored evidence, in order
+// of evidence ID. Error returned by the function stops the traversal and is propagated
+// to the caller.
+// No other errors are expected during normal operation.
+func TraverseSlashingEvidence(r storage.Reader, fn func(evidence *model.SlashingEvidence) error) error {
+	iterationFunc := func() (CheckFunc, CreateFunc, HandleFunc) {
+		var evidence *model.SlashingEvidence
+		check := func(key []byte) (bool, error) {
+			return true, nil
+		}
+		create := func() interface{} {
+			evidence = new(model.SlashingEvidence)
+			return evidence
+		}
+		handle := func() error {
+			return fn(evidence)
+		}
+		return check, create, handle
+	}
+
+	return TraverseByPrefix(r, MakePrefix(codeSlashingEvidence), iterationFunc, storage.DefaultIteratorOptions())
+}
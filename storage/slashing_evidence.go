@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// SlashingEvidence represents persistent storage for evidence of slashable
+// HotStuff protocol violations.
+type SlashingEvidence interface {
+	// Store persists the evidence, keyed by its ID. Evidence which has already been
+	// stored is not overwritten, so the first observation of a violation is kept.
+	// Returns true if the evidence was newly stored.
+	// No errors are expected during normal operation.
+	Store(evidence *model.SlashingEvidence) (bool, error)
+
+	// ByID returns the evidence with the given ID.
+	// Expected errors during normal operations:
+	//   - storage.ErrNotFound if no evidence with the given ID is stored
+	ByID(evidenceID flow.Identifier) (*model.SlashingEvidence, error)
+
+	// All returns all stored evidence, ordered by view.
+	// No errors are expected during normal operation.
+	All() ([]*model.SlashingEvidence, error)
+}
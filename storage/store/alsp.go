@@ -0,0 +1,46 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/network/alsp/model"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation"
+)
+
+// SpamRecordSnapshots implements persistent storage for snapshots of the ALSP spam records.
+type SpamRecordSnapshots struct {
+	db storage.DB
+}
+
+var _ storage.SpamRecordSnapshots = (*SpamRecordSnapshots)(nil)
+
+func NewSpamRecordSnapshots(db storage.DB) *SpamRecordSnapshots {
+	return &SpamRecordSnapshots{
+		db: db,
+	}
+}
+
+// Store persists the given snapshot, replacing the previously stored snapshot.
+// No errors are expected during normal operation.
+func (s *SpamRecordSnapshots) Store(snapshot *model.SpamRecordSnapshot) error {
+	err := s.db.WithReaderBatchWriter(func(rw storage.ReaderBatchWriter) error {
+		return operation.UpsertSpamRecordSnapshot(rw.Writer(), snapshot)
+	})
+	if err != nil {
+		return fmt.Errorf("could not store spam record snapshot: %w", err)
+	}
+	return nil
+}
+
+// Latest returns the most recently stored snapshot.
+// Error returns:
+//   - [storage.ErrNotFound] if no snapshot has been stored
+func (s *SpamRecordSnapshots) Latest() (*model.SpamRecordSnapshot, error) {
+	var snapshot model.SpamRecordSnapshot
+	err := operation.RetrieveSpamRecordSnapshot(s.db.Reader(), &snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve spam record snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,46 @@
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/network/alsp/model"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation/dbtest"
+	"github.com/onflow/flow-go/storage/store"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestSpamRecordSnapshotsStoreAndRetrieve tests that the latest stored snapshot replaces the
+// previous snapshot.
+func TestSpamRecordSnapshotsStoreAndRetrieve(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		snapshots := store.NewSpamRecordSnapshots(db)
+
+		_, err := snapshots.Latest()
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		first := &model.SpamRecordSnapshot{
+			Time: time.Unix(1_700_000_000, 0),
+			Records: []model.ProtocolSpamRecord{
+				{OriginId: unittest.IdentifierFixture(), Decay: 1000, Penalty: -500},
+			},
+		}
+		second := &model.SpamRecordSnapshot{
+			Time: time.Unix(1_700_000_060, 0),
+			Records: []model.ProtocolSpamRecord{
+				{OriginId: unittest.IdentifierFixture(), Decay: 100, CutoffCounter: 2, DisallowListed: true, Penalty: -90_000},
+				{OriginId: unittest.IdentifierFixture(), Decay: 1000},
+			},
+		}
+		require.NoError(t, snapshots.Store(first))
+		require.NoError(t, snapshots.Store(second))
+
+		latest, err := snapshots.Latest()
+		require.NoError(t, err)
+		require.True(t, second.Time.Equal(latest.Time))
+		require.Equal(t, second.Records, latest.Records)
+	})
+}
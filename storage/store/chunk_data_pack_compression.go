@@ -0,0 +1,96 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/compressor"
+	"github.com/onflow/flow-go/storage"
+)
+
+// chunkDataPackCompressor returns the compressor for the given compression.
+func chunkDataPackCompressor(compression storage.ChunkDataPackCompression) (network.Compressor, error) {
+	switch compression {
+	case storage.ChunkDataPackCompressedLz4:
+		return compressor.NewLz4Compressor(), nil
+	case storage.ChunkDataPackCompressedGzip:
+		return compressor.GzipStreamCompressor{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported chunk data pack compression: %v", compression)
+	}
+}
+
+// CompressChunkDataPack returns a copy of the uncompressed stored chunk data pack with
+// its proof compressed using the given compression. Compressing with
+// storage.ChunkDataPackUncompressed returns the chunk data pack unchanged.
+// No errors are expected during normal operation.
+func CompressChunkDataPack(
+	sc *storage.StoredChunkDataPack,
+	compression storage.ChunkDataPackCompression,
+) (*storage.StoredChunkDataPack, error) {
+	if sc.Compression != storage.ChunkDataPackUncompressed {
+		return nil, fmt.Errorf("chunk data pack %v is already compressed with %v", sc.ChunkID, sc.Compression)
+	}
+
+	if compression == storage.ChunkDataPackUncompressed {
+		return sc, nil
+	}
+
+	comp, err := chunkDataPackCompressor(compression)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w, err := comp.NewWriter(&buf)
+	if err != nil {
+		return nil, fmt.Errorf("could not create %v writer: %w", compression, err)
+	}
+
+	_, err = w.Write(sc.Proof)
+	if err != nil {
+		return nil, fmt.Errorf("could not compress proof of chunk data pack %v: %w", sc.ChunkID, err)
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, fmt.Errorf("could not compress proof of chunk data pack %v: %w", sc.ChunkID, err)
+	}
+
+	compressed := *sc
+	compressed.Proof = buf.Bytes()
+	compressed.Compression = compression
+	return &compressed, nil
+}
+
+// DecompressChunkDataPack returns a copy of the stored chunk data pack with its proof
+// decompressed. Uncompressed chunk data packs are returned unchanged.
+// No errors are expected during normal operation.
+func DecompressChunkDataPack(sc *storage.StoredChunkDataPack) (*storage.StoredChunkDataPack, error) {
+	if sc.Compression == storage.ChunkDataPackUncompressed {
+		return sc, nil
+	}
+
+	comp, err := chunkDataPackCompressor(sc.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := comp.NewReader(bytes.NewReader(sc.Proof))
+	if err != nil {
+		return nil, fmt.Errorf("could not create %v reader: %w", sc.Compression, err)
+	}
+	defer r.Close()
+
+	proof, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress proof of chunk data pack %v: %w", sc.ChunkID, err)
+	}
+
+	decompressed := *sc
+	decompressed.Proof = proof
+	decompressed.Compression = storage.ChunkDataPackUncompressed
+	return &decompressed, nil
+}
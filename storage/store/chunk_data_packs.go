@@ -13,21 +13,47 @@ import (
 type ChunkDataPacks struct {
 	db             storage.DB
 	collections    storage.Collections
+	compression    storage.ChunkDataPackCompression
 	byChunkIDCache *Cache[flow.Identifier, *storage.StoredChunkDataPack]
 }
 
 var _ storage.ChunkDataPacks = (*ChunkDataPacks)(nil)
 
-func NewChunkDataPacks(collector module.CacheMetrics, db storage.DB, collections storage.Collections, byChunkIDCacheSize uint) *ChunkDataPacks {
+// ChunkDataPacksOption configures optional behaviour of ChunkDataPacks.
+type ChunkDataPacksOption func(*ChunkDataPacks)
+
+// WithChunkDataPackCompression sets the compression applied to the proofs of newly stored
+// chunk data packs. Chunk data packs are always returned decompressed, regardless of how
+// they were stored.
+func WithChunkDataPackCompression(compression storage.ChunkDataPackCompression) ChunkDataPacksOption {
+	return func(ch *ChunkDataPacks) {
+		ch.compression = compression
+	}
+}
+
+// NewChunkDataPacks creates a chunk data pack storage. The cache holds decompressed
+// chunk data packs.
+func NewChunkDataPacks(collector module.CacheMetrics, db storage.DB, collections storage.Collections, byChunkIDCacheSize uint, opts ...ChunkDataPacksOption) *ChunkDataPacks {
+	ch := &ChunkDataPacks{
+		db:          db,
+		collections: collections,
+	}
+
+	for _, opt := range opts {
+		opt(ch)
+	}
 
 	store := func(rw storage.ReaderBatchWriter, key flow.Identifier, val *storage.StoredChunkDataPack) error {
-		return operation.InsertChunkDataPack(rw.Writer(), val)
+		return ch.insert(rw.Writer(), val)
 	}
 
 	retrieve := func(r storage.Reader, key flow.Identifier) (*storage.StoredChunkDataPack, error) {
 		var c storage.StoredChunkDataPack
 		err := operation.RetrieveChunkDataPack(r, key, &c)
-		return &c, err
+		if err != nil {
+			return nil, err
+		}
+		return DecompressChunkDataPack(&c)
 	}
 
 	cache := newCache(collector, metrics.ResourceChunkDataPack,
@@ -36,12 +62,18 @@ func NewChunkDataPacks(collector module.CacheMetrics, db storage.DB, collections
 		withRetrieve(retrieve),
 	)
 
-	ch := ChunkDataPacks{
-		db:             db,
-		byChunkIDCache: cache,
-		collections:    collections,
+	ch.byChunkIDCache = cache
+	return ch
+}
+
+// insert compresses the chunk data pack with the configured compression and writes it.
+// No errors are expected during normal operation.
+func (ch *ChunkDataPacks) insert(w storage.Writer, sc *storage.StoredChunkDataPack) error {
+	compressed, err := CompressChunkDataPack(sc, ch.compression)
+	if err != nil {
+		return fmt.Errorf("could not compress chunk data pack: %w", err)
 	}
-	return &ch
+	return operation.InsertChunkDataPack(w, compressed)
 }
 
 // Remove removes multiple ChunkDataPacks cs keyed by their ChunkIDs in a batch.
@@ -64,10 +96,14 @@ func (ch *ChunkDataPacks) Remove(chunkIDs []flow.Identifier) error {
 // if entity is not serializable or Badger unexpectedly fails to process request
 func (ch *ChunkDataPacks) BatchStore(c *flow.ChunkDataPack, rw storage.ReaderBatchWriter) error {
 	sc := storage.ToStoredChunkDataPack(c)
+	err := ch.insert(rw.Writer(), sc)
+	if err != nil {
+		return err
+	}
 	storage.OnCommitSucceed(rw, func() {
 		ch.byChunkIDCache.Insert(sc.ChunkID, sc)
 	})
-	return operation.InsertChunkDataPack(rw.Writer(), sc)
+	return nil
 }
 
 // Store stores multiple ChunkDataPacks cs keyed by their ChunkIDs in a batch.
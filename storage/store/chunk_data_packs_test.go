@@ -1,6 +1,7 @@
 package store_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/cockroachdb/pebble"
@@ -10,6 +11,7 @@ import (
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/operation"
 	"github.com/onflow/flow-go/storage/operation/pebbleimpl"
 	"github.com/onflow/flow-go/storage/store"
 	"github.com/onflow/flow-go/utils/unittest"
@@ -98,6 +100,42 @@ func TestChunkDataPacks_StoreTwice(t *testing.T) {
 	})
 }
 
+// TestChunkDataPacks_Compression evaluates that compressed chunk data packs are stored with
+// compressed proofs, are returned decompressed, and can be read regardless of the compression
+// configured on the reading store.
+func TestChunkDataPacks_Compression(t *testing.T) {
+	for _, compression := range []storage.ChunkDataPackCompression{
+		storage.ChunkDataPackCompressedLz4,
+		storage.ChunkDataPackCompressedGzip,
+	} {
+		t.Run(compression.String(), func(t *testing.T) {
+			WithChunkDataPacks(t, 10, func(t *testing.T, chunkDataPacks []*flow.ChunkDataPack, _ *store.ChunkDataPacks, bdb *badger.DB, pdb *pebble.DB) {
+				for _, c := range chunkDataPacks {
+					c.Proof = bytes.Repeat([]byte("proof"), 100)
+				}
+
+				transactions := badgerstorage.NewTransactions(&metrics.NoopCollector{}, bdb)
+				collections := badgerstorage.NewCollections(bdb, transactions)
+				compressing := store.NewChunkDataPacks(&metrics.NoopCollector{}, pebbleimpl.ToDB(pdb), collections, 1,
+					store.WithChunkDataPackCompression(compression))
+				require.NoError(t, compressing.Store(chunkDataPacks))
+
+				for _, c := range chunkDataPacks {
+					var stored storage.StoredChunkDataPack
+					err := operation.RetrieveChunkDataPack(pebbleimpl.ToDB(pdb).Reader(), c.ChunkID, &stored)
+					require.NoError(t, err)
+					require.Equal(t, compression, stored.Compression)
+					require.Less(t, len(stored.Proof), len(c.Proof))
+
+					actual, err := compressing.ByChunkID(c.ChunkID)
+					require.NoError(t, err)
+					require.Equal(t, c, actual)
+				}
+			})
+		})
+	}
+}
+
 // WithChunkDataPacks is a test helper that generates specified number of chunk data packs, store1 them using the storeFunc, and
 // then evaluates whether they are successfully retrieved from storage.
 func WithChunkDataPacks(t *testing.T, chunks int, storeFunc func(*testing.T, []*flow.ChunkDataPack, *store.ChunkDataPacks, *badger.DB, *pebble.DB)) {
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go/network/disallowlist/model"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation"
+)
+
+// DisallowListEntries implements persistent storage for the operator-managed disallow list.
+type DisallowListEntries struct {
+	db storage.DB
+}
+
+var _ storage.DisallowListEntries = (*DisallowListEntries)(nil)
+
+func NewDisallowListEntries(db storage.DB) *DisallowListEntries {
+	return &DisallowListEntries{
+		db: db,
+	}
+}
+
+// Store persists the given entries, replacing the previously stored entries.
+// No errors are expected during normal operation.
+func (d *DisallowListEntries) Store(entries []model.Entry) error {
+	err := d.db.WithReaderBatchWriter(func(rw storage.ReaderBatchWriter) error {
+		return operation.UpsertDisallowListEntries(rw.Writer(), entries)
+	})
+	if err != nil {
+		return fmt.Errorf("could not store disallow list entries: %w", err)
+	}
+	return nil
+}
+
+// All returns all stored entries, or an empty list if no entries have been stored.
+// No errors are expected during normal operation.
+func (d *DisallowListEntries) All() ([]model.Entry, error) {
+	var entries []model.Entry
+	err := operation.RetrieveDisallowListEntries(d.db.Reader(), &entries)
+	if errors.Is(err, storage.ErrNotFound) {
+		return []model.Entry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve disallow list entries: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,51 @@
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/network/disallowlist/model"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation/dbtest"
+	"github.com/onflow/flow-go/storage/store"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestDisallowListEntriesStoreAndRetrieve tests that an empty list is returned before any entries are stored,
+// and that stored entries replace the previously stored entries.
+func TestDisallowListEntriesStoreAndRetrieve(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		entries := store.NewDisallowListEntries(db)
+
+		stored, err := entries.All()
+		require.NoError(t, err)
+		require.Empty(t, stored)
+
+		first := []model.Entry{
+			{NodeID: unittest.IdentifierFixture(), Reason: "spamming", Added: time.Unix(1_700_000_000, 0)},
+		}
+		second := []model.Entry{
+			{NodeID: unittest.IdentifierFixture(), Reason: "spamming", Added: time.Unix(1_700_000_000, 0), Expiry: time.Unix(1_700_003_600, 0)},
+			{PeerID: unittest.PeerIdFixture(t), Reason: "eclipse attempt", Added: time.Unix(1_700_000_060, 0)},
+		}
+		require.NoError(t, entries.Store(first))
+		require.NoError(t, entries.Store(second))
+
+		stored, err = entries.All()
+		require.NoError(t, err)
+		require.Len(t, stored, len(second))
+		for i := range second {
+			require.Equal(t, second[i].Key(), stored[i].Key())
+			require.Equal(t, second[i].Reason, stored[i].Reason)
+			require.True(t, second[i].Added.Equal(stored[i].Added))
+			require.True(t, second[i].Expiry.Equal(stored[i].Expiry))
+		}
+
+		require.NoError(t, entries.Store(nil))
+		stored, err = entries.All()
+		require.NoError(t, err)
+		require.Empty(t, stored)
+	})
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation"
+)
+
+// LeaderStats implements persistent storage for per-leader view outcome statistics.
+type LeaderStats struct {
+	db storage.DB
+}
+
+var _ storage.LeaderStats = (*LeaderStats)(nil)
+
+func NewLeaderStats(db storage.DB) *LeaderStats {
+	return &LeaderStats{
+		db: db,
+	}
+}
+
+// Store persists the given statistics, overwriting previously stored statistics for the
+// same epoch and node.
+// No errors are expected during normal operation.
+func (s *LeaderStats) Store(stats []*model.LeaderStats) error {
+	err := s.db.WithReaderBatchWriter(func(rw storage.ReaderBatchWriter) error {
+		for _, leaderStats := range stats {
+			err := operation.UpsertLeaderStats(rw.Writer(), leaderStats)
+			if err != nil {
+				return fmt.Errorf("could not store stats of leader %v in epoch %d: %w", leaderStats.NodeID, leaderStats.Epoch, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not store leader stats: %w", err)
+	}
+	return nil
+}
+
+// ByEpoch returns the statistics of all leaders observed in the given epoch, ordered by
+// node ID. Returns an empty list if no statistics are stored for the epoch.
+// No errors are expected during normal operation.
+func (s *LeaderStats) ByEpoch(epoch uint64) ([]*model.LeaderStats, error) {
+	var all []*model.LeaderStats
+	err := operation.TraverseLeaderStats(s.db.Reader(), epoch, func(stats *model.LeaderStats) error {
+		all = append(all, stats)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not traverse leader stats of epoch %d: %w", epoch, err)
+	}
+	return all, nil
+}
+
+// All returns the statistics of all epochs, ordered by epoch and node ID.
+// No errors are expected during normal operation.
+func (s *LeaderStats) All() ([]*model.LeaderStats, error) {
+	var all []*model.LeaderStats
+	err := operation.TraverseAllLeaderStats(s.db.Reader(), func(stats *model.LeaderStats) error {
+		all = append(all, stats)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not traverse leader stats: %w", err)
+	}
+	return all, nil
+}
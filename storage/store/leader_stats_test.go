@@ -0,0 +1,51 @@
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation/dbtest"
+	"github.com/onflow/flow-go/storage/store"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestLeaderStatsStoreAndRetrieve tests that leader stats are overwritten per epoch and node,
+// and retrieved in order of epoch and node ID.
+func TestLeaderStatsStoreAndRetrieve(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		statsStore := store.NewLeaderStats(db)
+
+		empty, err := statsStore.ByEpoch(1)
+		require.NoError(t, err)
+		require.Empty(t, empty)
+
+		leaders := unittest.IdentifierListFixture(2).Sort(flow.IdentifierCanonical)
+		epoch1 := []*model.LeaderStats{
+			{Epoch: 1, NodeID: leaders[1], Views: 3, ProposalsOnTime: 2, ProposalsMissing: 1, TimedOut: 1, TotalProposalDelay: time.Second, LastView: 30},
+			{Epoch: 1, NodeID: leaders[0], Views: 1, ProposalsLate: 1, LastView: 20},
+		}
+		epoch2 := []*model.LeaderStats{
+			{Epoch: 2, NodeID: leaders[0], Views: 1, ProposalsOnTime: 1, LastView: 120},
+		}
+		require.NoError(t, statsStore.Store(epoch2))
+		require.NoError(t, statsStore.Store(epoch1))
+
+		// storing stats again overwrites the previous stats
+		updated := *epoch1[1]
+		updated.Record(40, model.ViewProposalOnTime, 100*time.Millisecond, false)
+		require.NoError(t, statsStore.Store([]*model.LeaderStats{&updated}))
+
+		actual, err := statsStore.ByEpoch(1)
+		require.NoError(t, err)
+		require.Equal(t, []*model.LeaderStats{&updated, epoch1[0]}, actual)
+
+		all, err := statsStore.All()
+		require.NoError(t, err)
+		require.Equal(t, []*model.LeaderStats{&updated, epoch1[0], epoch2[0]}, all)
+	})
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation"
+)
+
+// SlashingEvidence implements persistent storage for evidence of slashable HotStuff
+// protocol violations.
+type SlashingEvidence struct {
+	db storage.DB
+	// mu serializes the check-and-store of new evidence
+	mu sync.Mutex
+}
+
+var _ storage.SlashingEvidence = (*SlashingEvidence)(nil)
+
+func NewSlashingEvidence(db storage.DB) *SlashingEvidence {
+	return &SlashingEvidence{
+		db: db,
+	}
+}
+
+// Store persists the evidence, keyed by its ID. Evidence which has already been
+// stored is not overwritten, so the first observation of a violation is kept.
+// Returns true if the evidence was newly stored.
+// No errors are expected during normal operation.
+func (s *SlashingEvidence) Store(evidence *model.SlashingEvidence) (bool, error) {
+	evidenceID := evidence.ID()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exists, err := operation.ExistsSlashingEvidence(s.db.Reader(), evidenceID)
+	if err != nil {
+		return false, fmt.Errorf("could not check for slashing evidence %v: %w", evidenceID, err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	err = s.db.WithReaderBatchWriter(func(rw storage.ReaderBatchWriter) error {
+		return operation.UpsertSlashingEvidence(rw.Writer(), evidenceID, evidence)
+	})
+	if err != nil {
+		return false, fmt.Errorf("could not store slashing evidence %v: %w", evidenceID, err)
+	}
+	return true, nil
+}
+
+// ByID returns the evidence with the given ID.
+// Expected errors during normal operations:
+//   - storage.ErrNotFound if no evidence with the given ID is stored
+func (s *SlashingEvidence) ByID(evidenceID flow.Identifier) (*model.SlashingEvidence, error) {
+	var evidence model.SlashingEvidence
+	err := operation.RetrieveSlashingEvidence(s.db.Reader(), evidenceID, &evidence)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve slashing evidence %v: %w", evidenceID, err)
+	}
+	return &evidence, nil
+}
+
+// All returns all stored evidence, ordered by view.
+// No errors are expected during normal operation.
+func (s *SlashingEvidence) All() ([]*model.SlashingEvidence, error) {
+	var all []*model.SlashingEvidence
+	err := operation.TraverseSlashingEvidence(s.db.Reader(), func(evidence *model.SlashingEvidence) error {
+		all = append(all, evidence)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not traverse slashing evidence: %w", err)
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].View < all[j].View
+	})
+	return all, nil
+}
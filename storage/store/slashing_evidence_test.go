@@ -0,0 +1,78 @@
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/helper"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/operation/dbtest"
+	"github.com/onflow/flow-go/storage/store"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestSlashingEvidenceStoreAndRetrieve tests that evidence is stored once per violation,
+// independently of the order in which the conflicting artifacts were observed.
+func TestSlashingEvidenceStoreAndRetrieve(t *testing.T) {
+	dbtest.RunWithDB(t, func(t *testing.T, db storage.DB) {
+		evidenceStore := store.NewSlashingEvidence(db)
+
+		_, err := evidenceStore.ByID(unittest.IdentifierFixture())
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		offender := unittest.IdentityFixture(unittest.WithRole(flow.RoleCollection))
+		vote1 := unittest.VoteFixture(unittest.WithVoteSignerID(offender.NodeID), unittest.WithVoteView(20))
+		vote2 := unittest.VoteFixture(unittest.WithVoteSignerID(offender.NodeID), unittest.WithVoteView(20))
+		doubleVote := &model.SlashingEvidence{
+			Kind:             model.EvidenceDoubleVote,
+			ChainID:          flow.Emulator,
+			Epoch:            1,
+			View:             20,
+			Offender:         offender.NodeID,
+			OffenderIdentity: &offender.IdentitySkeleton,
+			DetectedAt:       time.Now().UTC(),
+			Votes:            []*model.Vote{vote1, vote2},
+		}
+
+		stored, err := evidenceStore.Store(doubleVote)
+		require.NoError(t, err)
+		require.True(t, stored)
+
+		// observing the same violation again does not overwrite the evidence
+		reobserved := *doubleVote
+		reobserved.Votes = []*model.Vote{vote2, vote1}
+		reobserved.DetectedAt = doubleVote.DetectedAt.Add(time.Minute)
+		stored, err = evidenceStore.Store(&reobserved)
+		require.NoError(t, err)
+		require.False(t, stored)
+
+		actual, err := evidenceStore.ByID(doubleVote.ID())
+		require.NoError(t, err)
+		require.Equal(t, doubleVote.ID(), actual.ID())
+		require.True(t, doubleVote.DetectedAt.Equal(actual.DetectedAt))
+		require.Equal(t, doubleVote.Votes, actual.Votes)
+		require.Equal(t, offender.StakingPubKey.String(), actual.OffenderIdentity.StakingPubKey.String())
+
+		timeout1 := helper.TimeoutObjectFixture(helper.WithTimeoutObjectSignerID(offender.NodeID), helper.WithTimeoutObjectView(10))
+		timeout2 := helper.TimeoutObjectFixture(helper.WithTimeoutObjectSignerID(offender.NodeID), helper.WithTimeoutObjectView(10))
+		doubleTimeout := &model.SlashingEvidence{
+			Kind:     model.EvidenceDoubleTimeout,
+			View:     10,
+			Offender: offender.NodeID,
+			Timeouts: []*model.TimeoutObject{timeout1, timeout2},
+		}
+		stored, err = evidenceStore.Store(doubleTimeout)
+		require.NoError(t, err)
+		require.True(t, stored)
+
+		all, err := evidenceStore.All()
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		require.Equal(t, doubleTimeout.ID(), all[0].ID())
+		require.Equal(t, doubleVote.ID(), all[1].ID())
+	})
+}
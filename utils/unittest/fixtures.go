@@ -2664,7 +2664,7 @@ func WithTrieUpdate(trieUpdate *ledger.TrieUpdate) func(*execution_data.ChunkExe
 	}
 }
 
-func ChunkExecutionDataFixture(t *testing.T, minSize int, opts ...func(*execution_data.ChunkExecutionData)) *execution_data.ChunkExecutionData {
+func ChunkExecutionDataFixture(t testing.TB, minSize int, opts ...func(*execution_data.ChunkExecutionData)) *execution_data.ChunkExecutionData {
 	collection := CollectionFixture(5)
 	results := make([]flow.LightTransactionResult, len(collection.Transactions))
 	for i, tx := range collection.Transactions {